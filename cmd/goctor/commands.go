@@ -0,0 +1,338 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/completion"
+	"github.com/ikorihn/goctor/internal/export"
+	"github.com/ikorihn/goctor/internal/i18n"
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/output"
+	"github.com/ikorihn/goctor/internal/schema"
+	"github.com/ikorihn/goctor/internal/upstream"
+)
+
+// commandSpec describes a command for dispatch and shell completion: its flags come from the
+// same constructor the command parses them with, so neither can drift from the flags
+type commandSpec struct {
+	name        string
+	description string
+	flags       func(c *cli) *flag.FlagSet
+	run         func(c *cli, args []string) int // nil for groups of subcommands
+	selfCheck   bool                            // reports configuration problems instead of failing on them
+	flagValues  map[string][]string             // fixed values of flags, by flag name
+	fileFlags   []string                        // flags whose value is a path
+	subcommands []commandSpec
+	args        []string // fixed positional arguments
+	toolIDs     bool     // positional arguments are tool IDs
+	files       bool     // positional arguments are paths
+}
+
+// commandTree describes every command and subcommand, in the order of the help text
+func commandTree() []commandSpec {
+	lags := make([]string, 0, len(upstream.Lags))
+	for _, lag := range upstream.Lags {
+		lags = append(lags, string(lag))
+	}
+
+	return []commandSpec{
+		{
+			name:        "doctor",
+			description: "Check development environment",
+			flags:       newDoctorFlags,
+			run:         runDoctorCommand,
+			subcommands: []commandSpec{
+				{name: "env", description: "Diagnose goctor's own setup", flags: newDoctorEnvFlags, run: runDoctorEnvCommand, selfCheck: true},
+				{name: "paths", description: "Print the directories goctor uses", flags: newDoctorPathsFlags, run: runDoctorPathsCommand},
+				{name: "serve", description: "Serve the report over HTTP", flags: func(c *cli) *flag.FlagSet { return newDoctorServeFlags(c).FlagSet }, run: runDoctorServeCommand},
+				{name: "lsp", description: "JSON-RPC backend for editor extensions", flags: newDoctorLSPFlags, run: runDoctorLSPCommand},
+				{
+					name:        "outdated",
+					description: "Compare versions with the latest upstream releases",
+					flags:       func(c *cli) *flag.FlagSet { return newOutdatedFlags(c).FlagSet },
+					run:         runDoctorOutdatedCommand,
+					flagValues:  map[string][]string{"threshold": lags},
+				},
+				{name: "report", description: "Work with saved doctor --json reports", subcommands: []commandSpec{
+					{
+						name:        "merge",
+						description: "Combine reports of several workspaces into one",
+						flags:       func(c *cli) *flag.FlagSet { return newReportMergeFlags().FlagSet },
+						run:         runReportMergeCommand,
+						fileFlags:   []string{"o"},
+						files:       true,
+					},
+				}},
+				{name: "dev", description: "Tools for goctor development", subcommands: []commandSpec{
+					{
+						name:        "gen-fixtures",
+						description: "Write the test manifests",
+						flags:       func(c *cli) *flag.FlagSet { return newGenFixturesFlags().FlagSet },
+						run:         runGenFixturesCommand,
+						fileFlags:   []string{"o"},
+					},
+				}},
+				{
+					name:        "completion",
+					description: "Print a shell completion script",
+					flags:       func(c *cli) *flag.FlagSet { return newDoctorCompletionFlags(c).FlagSet },
+					run:         runDoctorCompletionCommand,
+					args:        completion.Shells,
+				},
+			},
+		},
+		{name: "watch", description: "Re-check continuously", flags: func(c *cli) *flag.FlagSet { return newWatchFlags(c).FlagSet }, run: runWatchCommand},
+		{name: "tui", description: "Interactive dashboard", flags: newTUIFlags, run: runTUICommand},
+		{name: "agent", description: "Check and report to a fleet server on an interval", flags: func(c *cli) *flag.FlagSet { return newAgentFlags(c).FlagSet }, run: runAgentCommand},
+		{name: "server", description: "Collect reports of a fleet of machines", flags: func(c *cli) *flag.FlagSet { return newServerFlags().FlagSet }, run: runServerCommand, fileFlags: []string{"data"}},
+		{
+			name:        "list",
+			description: "List tools defined in manifest",
+			flags:       func(c *cli) *flag.FlagSet { return newListFlags(c).FlagSet },
+			run:         runListCommand,
+			flagValues:  map[string][]string{"sort": manifest.ToolSortKeys},
+		},
+		{name: "explain", description: "Show full detail for one tool", flags: func(c *cli) *flag.FlagSet { return newExplainFlags(c).FlagSet }, run: runExplainCommand, toolIDs: true},
+		{
+			name:        "lint",
+			description: "Validate the manifest and check it for best practices",
+			flags:       func(c *cli) *flag.FlagSet { return newLintFlags(c).FlagSet },
+			run:         runLintCommand,
+			flagValues:  map[string][]string{"disable": manifest.LintRuleIDs()},
+		},
+		{name: "diff", description: "Compare two doctor --json reports", flags: newDiffFlags, run: runDiffCommand, files: true},
+		{name: "report", description: "Render a saved doctor --json report", flags: func(c *cli) *flag.FlagSet { return newReportFlags(c).FlagSet }, run: runReportCommand, fileFlags: []string{"i"}},
+		{
+			name:        "history",
+			description: "List past doctor runs",
+			flags:       func(c *cli) *flag.FlagSet { return newHistoryFlags(c).FlagSet },
+			run:         runHistoryCommand,
+			subcommands: []commandSpec{
+				{name: "show", description: "Show one past run", flags: func(c *cli) *flag.FlagSet { return newHistoryFlags(c).FlagSet }, run: runHistoryShowCommand},
+			},
+		},
+		{name: "version", description: "Show build information", flags: newVersionFlags, run: runVersionCommand},
+		{name: "migrate", description: "Rewrite a manifest to the current schema version", flags: func(c *cli) *flag.FlagSet { return newMigrateFlags(c).FlagSet }, run: runMigrateCommand, fileFlags: []string{"o"}},
+		{
+			name:        "export",
+			description: "Convert the manifest for other installers",
+			flags:       func(c *cli) *flag.FlagSet { return newExportFlags(c).FlagSet },
+			run:         runExportCommand,
+			flagValues:  map[string][]string{"format": export.Formats},
+			fileFlags:   []string{"o"},
+		},
+		{
+			name:        "sbom",
+			description: "Write an SBOM of the installed tools",
+			flags:       func(c *cli) *flag.FlagSet { return newSBOMFlags(c).FlagSet },
+			run:         runSBOMCommand,
+			flagValues:  map[string][]string{"format": sbomFormats},
+			fileFlags:   []string{"report", "o"},
+		},
+		{
+			name:        "schema",
+			description: "Print the JSON Schema of reports, list output, or the manifest",
+			flags:       func(c *cli) *flag.FlagSet { return newSchemaFlags().FlagSet },
+			run:         runSchemaCommand,
+			fileFlags:   []string{"o"},
+			args:        schema.Names,
+		},
+	}
+}
+
+// commandNames returns the names of the commands in specs
+func commandNames(specs []commandSpec) []string {
+	names := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		names = append(names, spec.name)
+	}
+	return names
+}
+
+// findCommand returns the command named name
+func findCommand(specs []commandSpec, name string) (commandSpec, bool) {
+	for _, spec := range specs {
+		if spec.name == name {
+			return spec, true
+		}
+	}
+	return commandSpec{}, false
+}
+
+// runCommand runs the command of specs named by the first argument, or its subcommand when the
+// next argument names one, with the rest of the arguments; parent is the path of the command
+// specs belong to, "" for the top level
+func (c *cli) runCommand(specs []commandSpec, parent string, args []string) int {
+	spec, ok := findCommand(specs, args[0])
+	if !ok {
+		if parent == "" {
+			fmt.Fprintf(os.Stderr, "Unknown command: %s\n", args[0])
+			showHelp()
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Unknown %s subcommand: %s\n", parent, args[0])
+		return 1
+	}
+
+	path := strings.TrimSpace(parent + " " + spec.name)
+	args = args[1:]
+	if len(args) > 0 {
+		if _, ok := findCommand(spec.subcommands, args[0]); ok {
+			return c.runCommand(spec.subcommands, path, args)
+		}
+	}
+
+	if spec.run == nil {
+		if len(args) > 0 {
+			fmt.Fprintf(os.Stderr, "Unknown %s subcommand: %s\n", path, args[0])
+		} else {
+			fmt.Fprintf(os.Stderr, "Usage: goctor %s %s\n", path, strings.Join(commandNames(spec.subcommands), "|"))
+		}
+		return 1
+	}
+
+	// The self-check reports a broken configuration file instead of failing on it
+	if c.cfgErr != nil && !spec.selfCheck {
+		fmt.Fprintf(os.Stderr, "Error reading config: %v\n", c.cfgErr)
+		return 1
+	}
+
+	return spec.run(c, args)
+}
+
+// isBoolFlag reports whether a flag is given without a value, like --json
+func isBoolFlag(f *flag.Flag) bool {
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
+
+// completionSpec describes goctor's flags and commands for completion scripts; the flags
+// given before a command are the shared ones
+func completionSpec(c *cli) completion.Spec {
+	return completion.Spec{
+		Program:      "goctor",
+		Flags:        completionFlags(c.root, sharedFlagSpec),
+		Commands:     completionCommands(c, commandTree()),
+		ToolIDsArgs:  []string{"doctor", "completion", "--tool-ids"},
+		ManifestFlag: "f",
+	}
+}
+
+// sharedFlagSpec describes the values of the shared flags for completion
+var sharedFlagSpec = commandSpec{
+	flagValues: map[string][]string{
+		"format":          outputFormats,
+		"color":           output.ColorModes,
+		"lang":            i18n.Languages,
+		"progress-format": output.ProgressFormats,
+		"exit-codes":      {checker.ExitPolicySimple, checker.ExitPolicyGranular},
+		"scope":           manifest.Scopes,
+		"merge":           manifest.MergeModes,
+	},
+	fileFlags: []string{"f", "json-file", "merge-results", "ca-cert", "audit-log"},
+}
+
+// completionCommands converts command specs for the completion package
+func completionCommands(c *cli, specs []commandSpec) []completion.Command {
+	commands := make([]completion.Command, 0, len(specs))
+	for _, spec := range specs {
+		command := completion.Command{
+			Name:        spec.name,
+			Description: spec.description,
+			Subcommands: completionCommands(c, spec.subcommands),
+			Args:        spec.args,
+			ToolIDs:     spec.toolIDs,
+			Files:       spec.files,
+		}
+		if spec.flags != nil {
+			fs := spec.flags(c)
+			c.share(fs, logFlags)
+			command.Flags = completionFlags(fs, spec)
+		}
+		commands = append(commands, command)
+	}
+	return commands
+}
+
+// completionFlags lists the flags defined on fs; shared flags the command does not redefine
+// complete like the flags given before the command
+func completionFlags(fs *flag.FlagSet, spec commandSpec) []completion.Flag {
+	var flags []completion.Flag
+	fs.VisitAll(func(f *flag.Flag) {
+		values, files := spec.flagValues[f.Name], slices.Contains(spec.fileFlags, f.Name)
+		if values == nil && !files {
+			values, files = sharedFlagSpec.flagValues[f.Name], slices.Contains(sharedFlagSpec.fileFlags, f.Name)
+		}
+		flags = append(flags, completion.Flag{
+			Name:       f.Name,
+			Usage:      f.Usage,
+			TakesValue: !isBoolFlag(f),
+			Values:     values,
+			Files:      files,
+		})
+	})
+	return flags
+}
+
+// doctorCompletionFlags holds the flags of doctor completion
+type doctorCompletionFlags struct {
+	*flag.FlagSet
+	toolIDs *bool
+}
+
+// newDoctorCompletionFlags defines the flags of doctor completion
+func newDoctorCompletionFlags(c *cli) doctorCompletionFlags {
+	fs := flag.NewFlagSet("doctor completion", flag.ContinueOnError)
+	f := doctorCompletionFlags{
+		FlagSet: fs,
+		toolIDs: fs.Bool("tool-ids", false, "print the manifest's tool IDs, one per line, for completion scripts"),
+	}
+	c.share(fs, manifestFlags)
+	return f
+}
+
+// runDoctorCompletionCommand prints the completion script for a shell, or with --tool-ids the
+// tool IDs the scripts offer for explain
+func runDoctorCompletionCommand(c *cli, args []string) int {
+	fs := newDoctorCompletionFlags(c)
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+
+	if *fs.toolIDs {
+		loader, err := c.loader()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error %v\n", err)
+			return 1
+		}
+		manifestSource := c.manifestPath()
+		m, err := loader.LoadFromSource(manifestSource)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading manifest: %v\n", err)
+			return 1
+		}
+		for _, tool := range m.Tools {
+			fmt.Println(tool.ID)
+		}
+		return 0
+	}
+
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: goctor doctor completion %s\n", strings.Join(completion.Shells, "|"))
+		return 1
+	}
+
+	script, err := completion.Script(args[0], completionSpec(c))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Print(script)
+	return 0
+}
@@ -1,180 +1,2675 @@
 package main
 
 import (
+	"bufio"
+	"cmp"
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"maps"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/ikorihn/goctor/internal/bootstrap"
+	"github.com/ikorihn/goctor/internal/buildinfo"
 	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/config"
+	"github.com/ikorihn/goctor/internal/events"
+	"github.com/ikorihn/goctor/internal/export"
+	"github.com/ikorihn/goctor/internal/fixtures"
+	"github.com/ikorihn/goctor/internal/history"
+	"github.com/ikorihn/goctor/internal/i18n"
+	"github.com/ikorihn/goctor/internal/links"
 	"github.com/ikorihn/goctor/internal/manifest"
 	"github.com/ikorihn/goctor/internal/output"
+	"github.com/ikorihn/goctor/internal/paths"
 	"github.com/ikorihn/goctor/internal/platform"
+	"github.com/ikorihn/goctor/internal/reporting"
+	"github.com/ikorihn/goctor/internal/rpc"
+	"github.com/ikorihn/goctor/internal/scheduler"
+	"github.com/ikorihn/goctor/internal/schema"
+	"github.com/ikorihn/goctor/internal/selfcheck"
+	"github.com/ikorihn/goctor/internal/semver"
+	"github.com/ikorihn/goctor/internal/server"
+	"github.com/ikorihn/goctor/internal/tui"
+	"github.com/ikorihn/goctor/internal/upstream"
 )
 
-const (
-	version = "1.0.0"
-)
+// multiFlag collects repeated string flags such as --header
+type multiFlag []string
+
+func (m *multiFlag) String() string {
+	return strings.Join(*m, ", ")
+}
+
+func (m *multiFlag) Set(value string) error {
+	*m = append(*m, value)
+	return nil
+}
+
+// outputFormats lists the values accepted by --format
+var outputFormats = []string{"human", "json", "jsonl", "markdown", "html", "github", "codeclimate", "junit"}
+
+func main() {
+	cfg, cfgErr := loadUserConfig()
+	c := newCLI(cfg, cfgErr)
+	c.root.Usage = showHelp
+	helpFlag := c.root.Bool("h", false, "show help")
+	versionFlag := c.root.Bool("v", false, "show version")
+	capsFlag := c.root.Bool("capabilities", false, "print machine-readable capabilities as JSON")
+	c.root.Parse(os.Args[1:])
+
+	if *helpFlag {
+		showHelp()
+		return
+	}
+
+	if *versionFlag {
+		fmt.Printf("goctor version %s\n", buildinfo.Get().Version)
+		return
+	}
+
+	if *capsFlag {
+		os.Exit(runCapabilities())
+	}
+
+	args := c.root.Args()
+	if len(args) == 0 {
+		args = []string{"doctor"} // Default command
+	}
+
+	exitCode := c.runCommand(commandTree(), "", args)
+	c.close()
+	os.Exit(exitCode)
+}
+
+// newLoader creates a manifest loader with credentials from flags, environment, the user
+// configuration, and netrc, trusting the certificates of tlsOptions
+func newLoader(headers []string, authToken string, tlsOptions manifest.TLSOptions) (*manifest.Loader, error) {
+	loader := manifest.NewLoader()
+	if err := loader.SetTLSOptions(tlsOptions); err != nil {
+		return nil, err
+	}
+
+	for _, header := range headers {
+		name, value, err := manifest.ParseHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		loader.AddHeader(name, value)
+	}
+
+	if token := os.Getenv("GOCTOR_AUTH_TOKEN"); token != "" {
+		loader.SetBearerToken(token)
+	} else if authToken != "" {
+		loader.SetBearerToken(authToken)
+	}
+
+	if netrcPath := manifest.DefaultNetrcPath(); netrcPath != "" {
+		entries, err := manifest.LoadNetrcFile(netrcPath)
+		if err != nil {
+			return nil, err
+		}
+		loader.SetNetrcEntries(entries)
+	}
+
+	// Bundles are extracted under the cache so repeated runs reuse them
+	if dirs, err := paths.Default(); err == nil {
+		loader.SetBundleDir(dirs.Bundles())
+	}
+
+	return loader, nil
+}
+
+// newLinkResolver creates a link resolver from GOCTOR_LINK_RESOLVERS and --link-resolver flags
+func newLinkResolver(definitions []string) (*links.Resolver, error) {
+	resolver := links.NewResolver()
+
+	var all []string
+	if env := os.Getenv("GOCTOR_LINK_RESOLVERS"); env != "" {
+		all = append(all, strings.Split(env, ",")...)
+	}
+	all = append(all, definitions...)
+
+	for _, definition := range all {
+		if err := resolver.ParseTemplate(definition); err != nil {
+			return nil, err
+		}
+	}
+
+	return resolver, nil
+}
+
+// manifestSources names the manifests merged for source as reports show them, lowest
+// precedence first
+func manifestSources(loader *manifest.Loader, source string) []string {
+	sources := loader.Sources(source)
+	for i, s := range sources {
+		sources[i] = manifest.DisplaySource(s)
+	}
+	return sources
+}
+
+// checkRun holds the settings shared by every doctor run: one-shot, serve, and future modes
+type checkRun struct {
+	loader       *manifest.Loader
+	resolver     *links.Resolver
+	resolveShims bool
+	mergeResults []string
+	schedule     scheduler.Options
+	runner       checker.Runner // nil checks this machine
+	verbose      bool           // trace how each check went
+	capture      bool           // keep the output of version commands in results
+	tags         []string       // check only tools with any of these tags
+	scope        string         // check only tools of this scope
+	prerelease   bool           // let prereleases of every tool meet their release's constraints
+	safety       commandSafety
+	timeouts     checkTimeouts
+	anonymize    bool // hash the hostname and strip usernames from paths
+}
+
+// checkTimeouts are the time budgets of the phases of each check
+type checkTimeouts struct {
+	command time.Duration // running a version command
+	detect  time.Duration // looking up each executable
+}
+
+// apply sets the budgets on a checker
+func (t checkTimeouts) apply(toolChecker *checker.Checker) {
+	toolChecker.SetTimeout(t.command)
+	toolChecker.SetDetectTimeout(t.detect)
+}
+
+// commandSafety restricts and audits the commands checks run, for manifests that are not
+// fully trusted
+type commandSafety struct {
+	restricted bool              // only run `<tool id> --version`-style commands, and no hooks
+	audit      *checker.AuditLog // nil records nothing
+}
+
+// apply configures a checker with the safety settings
+func (s commandSafety) apply(toolChecker *checker.Checker) {
+	toolChecker.SetRestricted(s.restricted)
+	if s.audit != nil {
+		toolChecker.SetAuditLog(s.audit)
+	}
+}
+
+// applyHooks configures a transition tracker with the safety settings; restricted mode runs
+// no hooks
+func (s commandSafety) applyHooks(tracker *checker.TransitionTracker) {
+	tracker.SetRestricted(s.restricted)
+	if s.audit != nil {
+		tracker.SetAuditLog(s.audit)
+	}
+}
+
+// check loads the manifest, checks the tools that apply to this platform, and merges
+// external results; it also returns the merged results so streaming output can emit them
+// Tools for other platforms and tools without any of the run's tags are reported as skipped
+// A non-empty only restricts the run to the tools with those IDs
+// The run is published on bus, which may be nil, with links already resolved
+func (cr checkRun) check(ctx context.Context, manifestSource string, only []string, bus *events.Bus) (*checker.EnvironmentReport, []checker.CheckResult, error) {
+	m, err := cr.loader.LoadFromSource(manifestSource)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading manifest: %w", err)
+	}
+
+	for _, id := range only {
+		if m.GetTool(id) == nil {
+			return nil, nil, fmt.Errorf("tool %q not found in manifest", id)
+		}
+	}
+
+	// Detect platform, on the target when checks run elsewhere
+	platformInfo := platform.DetectPlatform()
+	if cr.runner != nil {
+		platformInfo, err = checker.DetectPlatform(ctx, cr.runner)
+		if err != nil {
+			return nil, nil, fmt.Errorf("detecting platform: %v", err)
+		}
+	}
+	if !platformInfo.IsSupported() {
+		return nil, nil, fmt.Errorf("unsupported platform: %s", platformInfo.String())
+	}
+	if cr.anonymize {
+		platformInfo.Anonymize()
+	}
+
+	// Create checker and run checks for tools applicable to this platform
+	toolChecker := checker.NewChecker()
+	toolChecker.SetResolveShims(cr.resolveShims)
+	toolChecker.SetVerbose(cr.verbose)
+	toolChecker.SetCaptureOutput(cr.capture)
+	toolChecker.SetAllowPrerelease(cr.prerelease)
+	toolChecker.SetAnonymize(cr.anonymize)
+	cr.safety.apply(toolChecker)
+	cr.timeouts.apply(toolChecker)
+	if cr.runner != nil {
+		toolChecker.SetRunner(cr.runner)
+	}
+	var tools []manifest.ToolDefinition
+	var skipped []checker.CheckResult
+	for _, tool := range m.Tools {
+		if len(only) > 0 && !slices.Contains(only, tool.ID) {
+			continue
+		}
+		if reason := cr.skipReason(tool, platformInfo); reason != "" {
+			skipped = append(skipped, checker.SkippedResult(tool, platformInfo, reason))
+		} else {
+			tools = append(tools, tool)
+		}
+	}
+
+	checkEvents := bus.CheckEvents(cr.resolveLinks)
+	results, err := toolChecker.CheckAll(ctx, tools, platformInfo, cr.schedule, checkEvents)
+	if err != nil {
+		return nil, nil, fmt.Errorf("scheduling checks: %v", err)
+	}
+
+	// Skipped tools keep their place in the manifest order
+	for _, result := range skipped {
+		if checkEvents.OnResult != nil {
+			checkEvents.OnResult(result)
+		}
+	}
+	position := make(map[string]int, len(m.Tools))
+	for i, tool := range m.Tools {
+		position[tool.ID] = i
+	}
+	results = append(results, skipped...)
+	slices.SortStableFunc(results, func(a, b checker.CheckResult) int {
+		return cmp.Compare(position[a.ToolID], position[b.ToolID])
+	})
+
+	// Resolve logical links for rendering
+	for i := range results {
+		results[i].Links = cr.resolver.ResolveAll(results[i].Links)
+	}
+
+	// Generate report
+	sources := manifestSources(cr.loader, manifestSource)
+	report := checker.NewEnvironmentReport(platformInfo, strings.Join(sources, ", "), results)
+	if len(sources) > 1 {
+		report.ManifestSources = sources
+	}
+	report.Language = m.Meta.Language
+	if cr.anonymize {
+		cr.anonymizeSources(report)
+	}
+
+	// Blend in findings from external scanners; later files take precedence
+	var merged []checker.CheckResult
+	for _, path := range cr.mergeResults {
+		extra, err := checker.LoadExternalResults(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("merging results: %v", err)
+		}
+		for i := range extra {
+			extra[i].Links = cr.resolver.ResolveAll(extra[i].Links)
+			if cr.anonymize {
+				extra[i] = checker.AnonymizeResult(extra[i], localHome())
+			}
+		}
+		report.MergeResults(extra)
+		merged = append(merged, extra...)
+	}
+
+	bus.Publish(events.Event{Kind: events.RunFinished, Report: report})
+
+	return report, merged, nil
+}
+
+// anonymizeSources strips usernames from the paths of the manifests a report was checked against
+func (cr checkRun) anonymizeSources(report *checker.EnvironmentReport) {
+	home := localHome()
+	report.ManifestSource = checker.AnonymizePaths(report.ManifestSource, home)
+	for i, source := range report.ManifestSources {
+		report.ManifestSources[i] = checker.AnonymizePaths(source, home)
+	}
+}
+
+// localHome returns the current user's home directory, or "" when it is unknown
+func localHome() string {
+	home, _ := os.UserHomeDir()
+	return home
+}
+
+// skipReason explains why a tool is not checked on platformInfo in this run, or returns ""
+func (cr checkRun) skipReason(tool manifest.ToolDefinition, platformInfo platform.PlatformInfo) string {
+	switch {
+	case !tool.AppliesTo(platformInfo.OS, platformInfo.Architecture):
+		return "only for " + strings.Join(tool.Platforms, ", ")
+	case len(cr.tags) > 0 && !tool.HasAnyTag(cr.tags):
+		return "not tagged " + strings.Join(cr.tags, ", ")
+	case cr.scope != "" && tool.Scope != cr.scope:
+		return "not a " + cr.scope + " tool"
+	default:
+		return ""
+	}
+}
+
+// plan loads the manifest and describes how each of its tools would be checked on this
+// platform, without running anything
+func (cr checkRun) plan(manifestSource string) ([]checker.CheckPlan, platform.PlatformInfo, error) {
+	m, err := cr.loader.LoadFromSource(manifestSource)
+	if err != nil {
+		return nil, platform.PlatformInfo{}, fmt.Errorf("loading manifest: %w", err)
+	}
+
+	platformInfo := platform.DetectPlatform()
+	toolChecker := checker.NewChecker()
+	toolChecker.SetAllowPrerelease(cr.prerelease)
+	toolChecker.SetRestricted(cr.safety.restricted)
+	cr.timeouts.apply(toolChecker)
+	plans := make([]checker.CheckPlan, 0, len(m.Tools))
+	for _, tool := range m.Tools {
+		if reason := cr.skipReason(tool, platformInfo); reason != "" {
+			plans = append(plans, checker.SkippedPlan(tool, reason))
+			continue
+		}
+		plans = append(plans, toolChecker.Plan(tool, platformInfo))
+	}
+	return plans, platformInfo, nil
+}
+
+// resolveLinks resolves the logical links of a result for rendering
+func (cr checkRun) resolveLinks(result checker.CheckResult) checker.CheckResult {
+	result.Links = cr.resolver.ResolveAll(result.Links)
+	return result
+}
+
+// newDoctorFlags defines the flags of doctor, all of them shared
+func newDoctorFlags(c *cli) *flag.FlagSet {
+	return c.flagSet("doctor", formatFlags, viewFlags, exitFlags, manifestFlags, checkFlags, runFlags)
+}
+
+func runDoctorCommand(c *cli, args []string) int {
+	args, err := c.parse(newDoctorFlags(c), args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unknown doctor subcommand: %s\n", args[0])
+		return 1
+	}
+
+	run, err := c.checkRun()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		return 1
+	}
+	if c.opts.dryRun {
+		return runDoctorDryRun(c, run)
+	}
+	uploader, err := newReportUploader(c.opts.reportURL, c.opts.reportHeaders, c.cfg.Report)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring report upload: %v\n", err)
+		return 1
+	}
+
+	manifestSource, format, exitPolicy := c.manifestPath(), c.format, c.exitPolicy
+	if c.manifestSource == "" {
+		// Offer to create the default manifest on the first run in a repository
+		if _, err := os.Stat(manifestSource); os.IsNotExist(err) {
+			offerOnboarding(manifestSource)
+		}
+	}
+
+	// The progress protocol reports starts and finishes on stderr for editor integrations,
+	// JSON Lines output streams each result as soon as its check finishes, and the finished
+	// run is recorded for history and uploaded
+	bus := events.NewBus()
+	if c.opts.progressFormat == output.ProgressJSON {
+		progress := output.NewProgressReporter(os.Stderr)
+		progress.SetExitPolicy(exitPolicy)
+		subscribeProgress(bus, progress)
+	}
+	jsonLines := output.NewJSONLinesFormatter()
+	jsonLines.SetExitPolicy(exitPolicy)
+	if format == "jsonl" {
+		bus.Subscribe(func(e events.Event) {
+			printJSONLine(jsonLines.FormatResult(e.Result))
+		}, events.CheckFinished)
+	}
+	if !c.opts.noHistory {
+		bus.Subscribe(func(e events.Event) {
+			recordHistory(*e.Report)
+		}, events.RunFinished)
+	}
+	if uploader != nil {
+		bus.Subscribe(func(e events.Event) {
+			uploadReport(uploader, *e.Report)
+		}, events.RunFinished)
+	}
+
+	report, merged, err := run.check(context.Background(), manifestSource, nil, bus)
+	if err != nil {
+		printCommandError(format, "doctor", err)
+		return exitPolicy.FailureCode()
+	}
+
+	// Keep a machine-readable artifact next to whatever format is printed
+	if c.opts.jsonFile != "" {
+		if err := writeJSONReport(c.opts.jsonFile, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JSON report: %v\n", err)
+			return exitPolicy.FailureCode()
+		}
+	}
+
+	// Output results
+	formatter := newHumanFormatter(c.color)
+	formatter.SetLanguage(i18n.Resolve(c.opts.lang, report.Language, os.Getenv))
+	formatter.SetView(c.view)
+	formatter.SetShowDurations(c.opts.durations)
+	formatter.SetVerbose(run.verbose)
+	if err := printReport(*report, merged, format, formatter, jsonLines, c.color); err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		return exitPolicy.FailureCode()
+	}
+
+	// Slow version commands make every run slow; point manifest authors at them
+	for _, item := range checker.SlowChecks(report.Items, c.slowThreshold) {
+		fmt.Fprintf(os.Stderr, "Warning: the check for %s took %s (over --slow-threshold %s)\n",
+			item.ToolID, time.Duration(item.CheckDuration).Round(time.Millisecond), c.slowThreshold)
+	}
+
+	return report.GetExitCode(exitPolicy)
+}
+
+// printReport prints a report in format; jsonl prints a line per result in results and human
+// output uses formatter
+func printReport(report checker.EnvironmentReport, results []checker.CheckResult, format string, formatter *output.HumanFormatter, jsonLines *output.JSONLinesFormatter, color bool) error {
+	switch format {
+	case "json":
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("generating JSON output: %v", err)
+		}
+		fmt.Println(string(jsonData))
+	case "jsonl":
+		for _, result := range results {
+			printJSONLine(jsonLines.FormatResult(result))
+		}
+		printJSONLine(jsonLines.FormatSummary(report))
+	case "markdown":
+		fmt.Print(output.NewMarkdownFormatter().FormatEnvironmentReport(report))
+	case "html":
+		output, err := output.NewHTMLFormatter().FormatEnvironmentReport(report)
+		if err != nil {
+			return fmt.Errorf("generating HTML output: %v", err)
+		}
+		fmt.Print(output)
+	case "codeclimate":
+		output, err := output.NewCodeClimateFormatter().FormatEnvironmentReport(report)
+		if err != nil {
+			return fmt.Errorf("generating Code Climate output: %v", err)
+		}
+		fmt.Print(output)
+	case "junit":
+		output, err := output.NewJUnitFormatter().FormatEnvironmentReport(report)
+		if err != nil {
+			return fmt.Errorf("generating JUnit output: %v", err)
+		}
+		fmt.Print(output)
+	case "github":
+		if err := writeGitHubOutput(report, color); err != nil {
+			return fmt.Errorf("writing GitHub step summary: %v", err)
+		}
+	default:
+		fmt.Print(formatter.FormatEnvironmentReport(report))
+	}
+	return nil
+}
+
+// writeJSONReport writes the report as indented JSON, the same document `--format json` prints
+func writeJSONReport(path string, report *checker.EnvironmentReport) error {
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(path, append(jsonData, '\n'), 0644)
+}
+
+// subscribeProgress reports every event of a run through the progress protocol
+func subscribeProgress(bus *events.Bus, progress *output.ProgressReporter) {
+	bus.Subscribe(func(e events.Event) {
+		switch e.Kind {
+		case events.RunStarted:
+			progress.RunStarted(len(e.Tools))
+		case events.CheckStarted:
+			progress.Started(e.Tool)
+		case events.CheckFinished:
+			progress.Finished(e.Result)
+		case events.RunFinished:
+			progress.RunFinished(*e.Report)
+		}
+	})
+}
+
+// recordHistory saves the report for `goctor history`; failing to do so only warns
+func recordHistory(report checker.EnvironmentReport) {
+	dirs, err := paths.Default()
+	if err != nil {
+		return
+	}
+
+	if _, err := history.NewStore(dirs.History()).Record(report); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record run history: %v\n", err)
+	}
+}
+
+// newReportUploader creates the uploader for --report-url, GOCTOR_REPORT_URL, or the configured
+// URL, with headers from --report-header and a bearer token from GOCTOR_REPORT_TOKEN or the
+// configuration; it returns nil when no URL is set
+func newReportUploader(reportURL string, headers []string, cfg config.Report) (*reporting.Uploader, error) {
+	if reportURL == "" {
+		reportURL = os.Getenv("GOCTOR_REPORT_URL")
+	}
+	if reportURL == "" {
+		reportURL = cfg.URL
+	}
+	if reportURL == "" {
+		if len(headers) > 0 {
+			return nil, errors.New("--report-header requires --report-url")
+		}
+		return nil, nil
+	}
+
+	uploader, err := reporting.NewUploader(reportURL)
+	if err != nil {
+		return nil, err
+	}
+	token := cmp.Or(os.Getenv("GOCTOR_REPORT_TOKEN"), cfg.Token)
+	if token != "" {
+		uploader.SetBearerToken(token)
+		if !strings.HasPrefix(reportURL, "https://") {
+			fmt.Fprintf(os.Stderr, "Warning: the report token is not sent to %s over plain HTTP; use an https:// URL\n", reportURL)
+		}
+	}
+	for _, header := range headers {
+		name, value, err := manifest.ParseHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		uploader.AddHeader(name, value)
+	}
+
+	return uploader, nil
+}
+
+// uploadReport posts the report to the fleet endpoint; failing to do so only warns so an
+// unreachable endpoint does not fail the check
+func uploadReport(uploader *reporting.Uploader, report checker.EnvironmentReport) {
+	if err := uploader.Upload(context.Background(), report); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to upload report to %s: %v\n", uploader.URL(), err)
+	}
+}
+
+// runDoctorDryRun prints how doctor would check each tool of the manifest, running nothing
+func runDoctorDryRun(c *cli, run checkRun) int {
+	if run.runner != nil {
+		fmt.Fprintln(os.Stderr, "Error: --dry-run cannot be combined with --target")
+		return 1
+	}
+	manifestSource, format := c.manifestPath(), c.format
+
+	plans, platformInfo, err := run.plan(manifestSource)
+	if err != nil {
+		printCommandError(format, "doctor", err)
+		return 1
+	}
+	sources := strings.Join(manifestSources(run.loader, manifestSource), ", ")
+
+	if format == "json" {
+		dryRun := struct {
+			ManifestSource string              `json:"manifest_source"`
+			Platform       string              `json:"platform"`
+			Tools          []checker.CheckPlan `json:"tools"`
+		}{ManifestSource: sources, Platform: platformInfo.String(), Tools: plans}
+
+		jsonData, err := json.MarshalIndent(dryRun, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(jsonData))
+		return 0
+	}
+
+	formatter := newHumanFormatter(c.color)
+	fmt.Print(formatter.FormatCheckPlans(plans, sources))
+	return 0
+}
+
+// doctorServeFlags holds the flags of doctor serve
+type doctorServeFlags struct {
+	*flag.FlagSet
+	addr     *string
+	interval *time.Duration
+}
+
+// newDoctorServeFlags defines the flags of doctor serve
+func newDoctorServeFlags(c *cli) doctorServeFlags {
+	fs := flag.NewFlagSet("doctor serve", flag.ContinueOnError)
+	f := doctorServeFlags{
+		FlagSet:  fs,
+		addr:     fs.String("addr", "127.0.0.1:8080", "address to listen on"),
+		interval: fs.Duration("interval", 0, "re-check periodically (e.g. 5m); 0 checks only on POST /check"),
+	}
+	c.share(fs, manifestFlags, checkFlags)
+	return f
+}
+
+func runDoctorServeCommand(c *cli, args []string) int {
+	fs := newDoctorServeFlags(c)
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+
+	run, err := c.checkRun()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		return 1
+	}
+
+	manifestSource := c.manifestPath()
+
+	srv := server.New(func(ctx context.Context) (*checker.EnvironmentReport, error) {
+		report, _, err := run.check(ctx, manifestSource, nil, nil)
+		return report, err
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The first check runs in the background so /healthz answers immediately
+	go func() {
+		if _, err := srv.Check(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		}
+		if *fs.interval <= 0 {
+			return
+		}
+		ticker := time.NewTicker(*fs.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := srv.Check(ctx); err != nil {
+					fmt.Fprintf(os.Stderr, "Error %v\n", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	httpServer := &http.Server{Addr: *fs.addr, Handler: srv.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving %s on http://%s\n", manifestSource, *fs.addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "Error serving: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// serverFlags holds the flags of server
+type serverFlags struct {
+	*flag.FlagSet
+	listen *string
+	data   *string
+	token  *string
+}
+
+// newServerFlags defines the flags of server
+func newServerFlags() serverFlags {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	return serverFlags{
+		FlagSet: fs,
+		listen:  fs.String("listen", "127.0.0.1:8080", "address to listen on"),
+		data:    fs.String("data", "", "directory to keep reports in (default: fleet in goctor's data directory)"),
+		token:   fs.String("token", "", "bearer token uploads must send (or GOCTOR_SERVER_TOKEN)"),
+	}
+}
+
+func runServerCommand(c *cli, args []string) int {
+	fs := newServerFlags()
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+
+	dir := *fs.data
+	if dir == "" {
+		dirs, err := paths.Default()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v; pass --data\n", err)
+			return 1
+		}
+		dir = dirs.Fleet()
+	}
+
+	fleet, err := server.NewFleet(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading reports: %v\n", err)
+		return 1
+	}
+	token := *fs.token
+	if token == "" {
+		token = os.Getenv("GOCTOR_SERVER_TOKEN")
+	}
+	if token != "" {
+		fleet.SetToken(token)
+	} else {
+		fmt.Fprintln(os.Stderr, "Warning: accepting reports from anyone; set --token or GOCTOR_SERVER_TOKEN to require a bearer token")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	httpServer := &http.Server{Addr: *fs.listen, Handler: fleet.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "Collecting reports in %s on http://%s\n", dir, *fs.listen)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "Error serving: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// agentFlags holds the flags of agent
+type agentFlags struct {
+	*flag.FlagSet
+	reportTo *string
+	interval *time.Duration
+	team     *string
+	once     *bool
+}
+
+// newAgentFlags defines the flags of agent
+func newAgentFlags(c *cli) agentFlags {
+	fs := flag.NewFlagSet("agent", flag.ContinueOnError)
+	f := agentFlags{
+		FlagSet:  fs,
+		reportTo: fs.String("report-to", "", "URL reports are POSTed to, e.g. https://goctor.example.com/reports"),
+		interval: fs.Duration("interval", time.Hour, "check and report this often"),
+		team:     fs.String("team", "", "team this machine belongs to in the fleet"),
+		once:     fs.Bool("once", false, "check and report once, e.g. from cron or launchd, and exit"),
+	}
+	c.share(fs, manifestFlags, checkFlags, []string{"report-header"})
+	return f
+}
+
+// runAgentCommand checks the machine and uploads the report on an interval, for goctor server
+// or another collection endpoint
+func runAgentCommand(c *cli, args []string) int {
+	fs := newAgentFlags(c)
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+	if !*fs.once && *fs.interval <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --interval must be positive")
+		return 1
+	}
+
+	uploader, err := newReportUploader(*fs.reportTo, c.opts.reportHeaders, c.cfg.Report)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring report upload: %v\n", err)
+		return 1
+	}
+	if uploader == nil {
+		fmt.Fprintln(os.Stderr, "Error: agent needs --report-to (or GOCTOR_REPORT_URL, or report.url in config.yaml)")
+		return 1
+	}
+	if *fs.team != "" {
+		uploader.AddHeader(server.TeamHeader, *fs.team)
+	}
+
+	run, err := c.checkRun()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		return 1
+	}
+	manifestSource := c.manifestPath()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report := func() bool {
+		report, _, err := run.check(ctx, manifestSource, nil, nil)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Error %v\n", err)
+			}
+			return false
+		}
+		if err := uploader.Upload(ctx, *report); err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Error uploading report to %s: %v\n", uploader.URL(), err)
+			}
+			return false
+		}
+		fmt.Fprintf(os.Stderr, "%s reported %d tools to %s\n", time.Now().Format(time.TimeOnly), report.Summary.Total, uploader.URL())
+		return true
+	}
+
+	if *fs.once {
+		if !report() {
+			return 1
+		}
+		return 0
+	}
+
+	// Failures are retried at the next interval; the agent keeps running until stopped
+	report()
+	ticker := time.NewTicker(*fs.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			report()
+		case <-ctx.Done():
+			return 0
+		}
+	}
+}
+
+// pathPollInterval is how often watch mode looks for changes to the PATH directories
+const pathPollInterval = time.Second
+
+// watchFlags holds the flags of watch
+type watchFlags struct {
+	*flag.FlagSet
+	interval *time.Duration
+}
+
+// newWatchFlags defines the flags of watch
+func newWatchFlags(c *cli) watchFlags {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	f := watchFlags{
+		FlagSet:  fs,
+		interval: fs.Duration("interval", 30*time.Second, "re-check this often"),
+	}
+	c.share(fs, formatFlags, []string{"lang"}, manifestFlags, checkFlags)
+	return f
+}
+
+// runWatchCommand re-checks the manifest on an interval, and whenever a PATH directory changes
+// for local runs, printing only the tools that were fixed or broke until interrupted
+func runWatchCommand(c *cli, args []string) int {
+	fs := newWatchFlags(c)
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+	if *fs.interval <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --interval must be positive")
+		return 1
+	}
+	format := c.format
+	if format != "human" && format != "jsonl" {
+		fmt.Fprintf(os.Stderr, "Error: watch prints human or jsonl output, not %s\n", format)
+		return 1
+	}
+
+	run, err := c.checkRun()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		return 1
+	}
+	manifestSource := c.manifestPath()
+
+	// Hooks come from the manifest as it was when watching started
+	m, err := run.loader.LoadFromSource(manifestSource)
+	if err != nil {
+		printCommandError(format, "watch", fmt.Errorf("loading manifest: %w", err))
+		return 1
+	}
+	tracker := checker.NewTransitionTracker(m.Tools)
+	run.safety.applyHooks(tracker)
+
+	humanFormatter := output.NewHumanFormatter()
+	humanFormatter.SetColorEnabled(c.color)
+	humanFormatter.SetLanguage(i18n.Resolve(c.opts.lang, m.Meta.Language, os.Getenv))
+	jsonlFormatter := output.NewJSONLinesFormatter()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// check prints the summary of the first check, then only the tools that changed and the
+	// summary after them
+	first := true
+	check := func() {
+		report, _, err := run.check(ctx, manifestSource, nil, nil)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Error %v\n", err)
+			}
+			return
+		}
+
+		results := make(map[string]checker.CheckResult, len(report.Items))
+		for _, item := range report.Items {
+			results[item.ToolID] = item
+		}
+
+		transitions := tracker.Observe(report.Items)
+		if !first && len(transitions) == 0 {
+			return
+		}
+		first = false
+
+		var out strings.Builder
+		for _, transition := range transitions {
+			if format == "jsonl" {
+				line, err := jsonlFormatter.FormatTransition(transition, results[transition.ToolID], report.GeneratedAt)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error generating JSON Lines output: %v\n", err)
+					return
+				}
+				out.WriteString(line)
+			} else {
+				out.WriteString(humanFormatter.FormatTransition(transition, results[transition.ToolID], report.GeneratedAt))
+			}
+		}
+		if format == "jsonl" {
+			line, err := jsonlFormatter.FormatSummary(*report)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error generating JSON Lines output: %v\n", err)
+				return
+			}
+			out.WriteString(line)
+		} else {
+			out.WriteString(humanFormatter.FormatQuickSummary(report.Summary) + "\n")
+		}
+		fmt.Print(out.String())
+	}
+
+	// PATH directories only say something about this machine
+	watchPath := run.runner == nil
+	if watchPath {
+		fmt.Fprintf(os.Stderr, "Watching %s every %s and on PATH changes (Ctrl+C to stop)\n", manifest.DisplaySource(manifestSource), *fs.interval)
+	} else {
+		fmt.Fprintf(os.Stderr, "Watching %s every %s (Ctrl+C to stop)\n", manifest.DisplaySource(manifestSource), *fs.interval)
+	}
+
+	pathFingerprint := platform.PathFingerprint(os.Getenv("PATH"))
+	check()
+
+	ticker := time.NewTicker(*fs.interval)
+	defer ticker.Stop()
+	pathTicker := time.NewTicker(pathPollInterval)
+	defer pathTicker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			check()
+		case <-pathTicker.C:
+			if !watchPath {
+				continue
+			}
+			if fingerprint := platform.PathFingerprint(os.Getenv("PATH")); fingerprint != pathFingerprint {
+				pathFingerprint = fingerprint
+				check()
+				ticker.Reset(*fs.interval)
+			}
+		case <-ctx.Done():
+			return 0
+		}
+	}
+}
+
+// editorBackend serves the manifest and checks to `doctor lsp` clients
+type editorBackend struct {
+	run            checkRun
+	manifestSource string
+}
+
+// Tools reloads the manifest so edits show up without restarting the backend
+func (eb editorBackend) Tools(ctx context.Context) ([]manifest.ToolDefinition, error) {
+	m, err := eb.run.loader.LoadFromSource(eb.manifestSource)
+	if err != nil {
+		return nil, fmt.Errorf("loading manifest: %v", err)
+	}
+	return m.Tools, nil
+}
+
+// Check runs the selected checks, reporting each result as it finishes
+func (eb editorBackend) Check(ctx context.Context, ids []string, onResult func(checker.CheckResult)) (*checker.EnvironmentReport, error) {
+	bus := events.NewBus()
+	bus.Subscribe(func(e events.Event) {
+		onResult(e.Result)
+	}, events.CheckFinished)
+
+	report, _, err := eb.run.check(ctx, eb.manifestSource, ids, bus)
+	return report, err
+}
+
+// newTUIFlags defines the flags of tui, all of them shared
+func newTUIFlags(c *cli) *flag.FlagSet {
+	return c.flagSet("tui", []string{"color"}, manifestFlags, checkFlags)
+}
+
+// runTUICommand shows the interactive dashboard, checking through the same backend as `doctor lsp`
+func runTUICommand(c *cli, args []string) int {
+	fs := newTUIFlags(c)
+	args, err := c.parse(fs, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+	if !isInteractive() {
+		fmt.Fprintln(os.Stderr, "Error: tui needs an interactive terminal; use doctor or watch instead")
+		return 1
+	}
+
+	run, err := c.checkRun()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		return 1
+	}
+	manifestSource := c.manifestPath()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dashboard := tui.NewDashboard(manifestSource, c.color)
+	if err := tui.Run(ctx, editorBackend{run: run, manifestSource: manifestSource}, dashboard, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// newDoctorLSPFlags defines the flags of doctor lsp, all of them shared
+func newDoctorLSPFlags(c *cli) *flag.FlagSet {
+	return c.flagSet("doctor lsp", manifestFlags, checkFlags)
+}
+
+// runDoctorLSPCommand serves editor extensions over JSON-RPC on stdin and stdout
+func runDoctorLSPCommand(c *cli, args []string) int {
+	fs := newDoctorLSPFlags(c)
+	args, err := c.parse(fs, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+
+	run, err := c.checkRun()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		return 1
+	}
+	manifestSource := c.manifestPath()
+
+	srv := rpc.NewServer(editorBackend{run: run, manifestSource: manifestSource}, buildinfo.Get().Version)
+	if err := srv.Serve(context.Background(), os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// lintFlags holds the flags of lint
+type lintFlags struct {
+	*flag.FlagSet
+	checkLinks  *bool
+	timeout     *time.Duration
+	concurrency *int
+	noCache     *bool
+	disable     *string
+}
+
+// newLintFlags defines the flags of lint
+func newLintFlags(c *cli) lintFlags {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	f := lintFlags{
+		FlagSet:     fs,
+		checkLinks:  fs.Bool("check-links", false, "check that every link URL still answers"),
+		timeout:     fs.Duration("timeout", links.DefaultCheckTimeout, "timeout for each DNS lookup and request"),
+		concurrency: fs.Int("concurrency", links.DefaultCheckConcurrency, "number of links checked at once"),
+		noCache:     fs.Bool("no-cache", false, "check every link, including ones that answered recently"),
+		disable:     fs.String("disable", "", "comma-separated lint rules to skip"),
+	}
+	c.share(fs, formatFlags, manifestFlags, linkFlags)
+	return f
+}
+
+// runLintCommand validates the manifest, checks it against the lint rules, and, with
+// --check-links, reports links that no longer answer
+func runLintCommand(c *cli, args []string) int {
+	fs := newLintFlags(c)
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+	format, color := c.format, c.color
+
+	var disabled []string
+	if *fs.disable != "" {
+		disabled = strings.Split(*fs.disable, ",")
+	}
+	if err := manifest.ValidateLintRules(disabled); err != nil {
+		printCommandError(format, "lint", fmt.Errorf("in --disable: %w", err))
+		return 1
+	}
+
+	loader, err := c.loader()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		return 1
+	}
+	resolver, err := newLinkResolver(c.opts.linkResolvers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring link resolvers: %v\n", err)
+		return 1
+	}
+
+	manifestSource := c.manifestPath()
+
+	m, err := loader.LoadFromSource(manifestSource)
+	if err != nil {
+		printCommandError(format, "lint", fmt.Errorf("loading manifest: %w", err))
+		return 1
+	}
+	findings := m.Lint(disabled)
+
+	var report *links.Report
+	if *fs.checkLinks {
+		var refs []links.Reference
+		for _, tool := range m.Tools {
+			resolved := resolver.ResolveAll(tool.Links)
+			for _, linkType := range slices.Sorted(maps.Keys(resolved)) {
+				refs = append(refs, links.Reference{ToolID: tool.ID, LinkType: linkType, URL: resolved[linkType]})
+			}
+		}
+
+		linkChecker := links.NewChecker(*fs.timeout)
+		linkChecker.SetConcurrency(*fs.concurrency)
+
+		// The cache is best-effort and skipped if goctor has no cache directory
+		var cache *links.Cache
+		if dirs, err := paths.Default(); err == nil && !*fs.noCache && !c.cfg.Cache.Disabled {
+			cache = links.LoadCache(dirs.LinkCache(), cmp.Or(c.cfg.Cache.LinkTTL, links.DefaultCacheTTL))
+			linkChecker.SetCache(cache)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		result := linkChecker.CheckReferences(ctx, refs)
+		stop()
+		report = &result
+
+		if cache != nil {
+			if err := cache.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to save link cache: %v\n", err)
+			}
+		}
+	}
+
+	if format == "json" {
+		jsonData, err := json.MarshalIndent(struct {
+			ManifestSource string                 `json:"manifest_source"`
+			Tools          int                    `json:"tools"`
+			Findings       []manifest.LintFinding `json:"findings"`
+			Links          *links.Report          `json:"links,omitempty"`
+		}{
+			ManifestSource: strings.Join(manifestSources(loader, manifestSource), ", "),
+			Tools:          len(m.Tools),
+			Findings:       findings,
+			Links:          report,
+		}, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		fmt.Printf("Manifest %s is valid (%d tools)\n", manifestSource, len(m.Tools))
+		fmt.Print(newHumanFormatter(color).FormatLintFindings(findings))
+		if report != nil {
+			fmt.Print(newHumanFormatter(color).FormatLinkReport(*report))
+		}
+	}
+
+	if len(findings) > 0 || (report != nil && len(report.Dead) > 0) {
+		return 1
+	}
+	return 0
+}
+
+// outdatedFlags holds the flags of outdated
+type outdatedFlags struct {
+	*flag.FlagSet
+	threshold *string
+	timeout   *time.Duration
+}
+
+// newOutdatedFlags defines the flags of outdated
+func newOutdatedFlags(c *cli) outdatedFlags {
+	fs := flag.NewFlagSet("outdated", flag.ContinueOnError)
+	f := outdatedFlags{
+		FlagSet:   fs,
+		threshold: fs.String("threshold", string(upstream.LagMinor), "smallest lag reported: patch, minor, or major"),
+		timeout:   fs.Duration("timeout", upstream.DefaultTimeout, "timeout for each upstream request"),
+	}
+	c.share(fs, formatFlags, manifestFlags, safetyFlags, []string{"resolve-shims"})
+	return f
+}
+
+// runDoctorOutdatedCommand reports tools whose required or installed version has fallen
+// behind the latest release published by the upstream sources the manifest declares
+func runDoctorOutdatedCommand(c *cli, args []string) int {
+	fs := newOutdatedFlags(c)
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+	format := c.format
+
+	threshold := upstream.Lag(*fs.threshold)
+	if !slices.Contains(upstream.Lags, threshold) {
+		fmt.Fprintf(os.Stderr, "Unknown threshold: %s (supported: patch, minor, major)\n", *fs.threshold)
+		return 1
+	}
+
+	run, err := c.checkRun()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		return 1
+	}
+	loader := run.loader
+	manifestSource := c.manifestPath()
+
+	m, err := loader.LoadFromSource(manifestSource)
+	if err != nil {
+		printCommandError(format, "outdated", fmt.Errorf("loading manifest: %w", err))
+		return 1
+	}
+
+	var tools []manifest.ToolDefinition
+	for _, tool := range m.Tools {
+		if tool.Upstream != nil {
+			tools = append(tools, tool)
+		}
+	}
+	if len(tools) == 0 {
+		fmt.Fprintln(os.Stderr, "No tools declare an upstream source; add upstream to the tools to compare")
+		return 1
+	}
+
+	client := upstream.NewClient(*fs.timeout)
+	client.SetGitHubToken(os.Getenv("GITHUB_TOKEN"))
+
+	platformInfo := platform.DetectPlatform()
+	toolChecker := checker.NewChecker()
+	toolChecker.SetResolveShims(run.resolveShims)
+	run.safety.apply(toolChecker)
+
+	// Tools are looked up concurrently since each lookup waits on the network
+	findings := make([]upstream.Finding, len(tools))
+	limit := make(chan struct{}, 8)
+	var wg sync.WaitGroup
+	for i, tool := range tools {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			var installed string
+			if tool.AppliesTo(platformInfo.OS, platformInfo.Architecture) {
+				installed = toolChecker.CheckTool(tool, platformInfo).ActualVersion
+			}
+			findings[i] = client.Compare(context.Background(), tool, installed)
+		}()
+	}
+	wg.Wait()
+
+	exitCode := 0
+	for _, f := range findings {
+		if f.Outdated(threshold) {
+			exitCode = 1
+		}
+	}
+
+	if format == "json" {
+		jsonData, err := json.MarshalIndent(struct {
+			ManifestSource string             `json:"manifest_source"`
+			Threshold      upstream.Lag       `json:"threshold"`
+			Tools          []upstream.Finding `json:"tools"`
+		}{
+			ManifestSource: strings.Join(manifestSources(loader, manifestSource), ", "),
+			Threshold:      threshold,
+			Tools:          findings,
+		}, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		fmt.Print(newHumanFormatter(c.color).FormatOutdated(findings, threshold))
+	}
+
+	return exitCode
+}
+
+// printCommandError reports why a command failed on stderr, or for JSON formats as a JSON
+// error document on stdout so parsers of the output always receive JSON
+func printCommandError(format, command string, err error) {
+	if format == "json" || format == "jsonl" {
+		formatter := output.NewJSONFormatter()
+		formatter.SetPrettyPrint(format == "json")
+		if document, jsonErr := formatter.FormatError(err, command); jsonErr == nil {
+			fmt.Println(document)
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Error %v\n", err)
+}
+
+// printJSONLine writes a JSON Lines record, reporting encoding failures on stderr
+func printJSONLine(line string, err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+		return
+	}
+	fmt.Print(line)
+}
+
+// offerOnboarding interactively bootstraps a manifest from the files in the current directory
+// It runs once per repository, only on a terminal, and can be disabled with GOCTOR_NO_ONBOARDING
+func offerOnboarding(manifestPath string) {
+	if os.Getenv("GOCTOR_NO_ONBOARDING") != "" || !isInteractive() {
+		return
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	suggestions := bootstrap.Detect(dir)
+	if len(suggestions) == 0 {
+		return
+	}
+
+	dirs, err := paths.Default()
+	if err != nil || !bootstrap.FirstRun(dirs.Onboarding(), dir) {
+		return
+	}
+
+	fmt.Printf("No %s found. Answer a few questions to create one (empty answer = yes):\n", manifestPath)
+	tools, err := bootstrap.Ask(os.Stdin, os.Stdout, suggestions, bootstrap.DefaultTimeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Skipping onboarding: %v\n", err)
+		return
+	}
+
+	data, err := bootstrap.Render(filepath.Base(dir), tools)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Skipping onboarding: %v\n", err)
+		return
+	}
+
+	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", manifestPath, err)
+		return
+	}
+	fmt.Printf("Wrote %s with %d tools\n\n", manifestPath, len(tools))
+}
+
+// newTrustPolicy returns the policy remote manifests are loaded under: sources on the
+// allowlist load, others are added to it with --trust or after a yes on a terminal
+func newTrustPolicy(trust bool) (manifest.TrustPolicy, error) {
+	dirs, err := paths.Default()
+	if err != nil {
+		return nil, err
+	}
+	store, err := manifest.LoadTrustStore(dirs.TrustedSources())
+	if err != nil {
+		return nil, err
+	}
+
+	return func(url string) error {
+		if store.Trusted(url) {
+			return nil
+		}
+		if !trust {
+			if !isInteractive() {
+				return fmt.Errorf("%s is not a trusted manifest source; its checks run commands on this machine. Review it and pass --trust to add it to %s", url, store.Path())
+			}
+			if !confirmTrust(url) {
+				return fmt.Errorf("%s is not a trusted manifest source", url)
+			}
+		}
+		if err := store.Add(url); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Trusted %s (saved in %s)\n", url, store.Path())
+		return nil
+	}, nil
+}
+
+// confirmTrust asks on the terminal whether to trust a remote manifest source
+func confirmTrust(url string) bool {
+	fmt.Fprintf(os.Stderr, "%s is a new manifest source; its checks run commands on this machine. Trust it? [y/N] ", url)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// newHumanFormatter creates a human-readable formatter that colors its output only if color is set
+func newHumanFormatter(color bool) *output.HumanFormatter {
+	formatter := output.NewHumanFormatter()
+	formatter.SetColorEnabled(color)
+	return formatter
+}
+
+// isInteractive returns true if both stdin and stdout are terminals
+func isInteractive() bool {
+	for _, f := range []*os.File{os.Stdin, os.Stdout} {
+		info, err := f.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// writeGitHubOutput prints workflow annotations and appends the report to the job's step summary
+func writeGitHubOutput(report checker.EnvironmentReport, color bool) error {
+	formatter := output.NewGitHubFormatter()
+	fmt.Print(formatter.FormatAnnotations(report))
+	fmt.Println(newHumanFormatter(color).FormatQuickSummary(report.Summary))
+
+	summaryPath := platform.GitHubStepSummaryPath()
+	if summaryPath == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(summaryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(formatter.FormatStepSummary(report))
+	return err
+}
+
+// newDoctorEnvFlags defines the flags of doctor env, all of them shared
+func newDoctorEnvFlags(c *cli) *flag.FlagSet {
+	return c.flagSet("doctor env", formatFlags, manifestFlags, linkFlags)
+}
+
+// runDoctorEnvCommand diagnoses goctor's own setup, reporting configuration problems instead
+// of failing on them
+func runDoctorEnvCommand(c *cli, args []string) int {
+	fs := newDoctorEnvFlags(c)
+	args, err := c.parse(fs, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+
+	manifestSource := c.manifestPath()
+
+	var configErrors []error
+	if c.cfgErr != nil {
+		configErrors = append(configErrors, fmt.Errorf("config file: %v", c.cfgErr))
+	}
+	loader, err := newLoader(c.opts.headers, c.cfg.AuthToken, c.tlsOptions())
+	if err != nil {
+		configErrors = append(configErrors, fmt.Errorf("manifest loader: %v", err))
+	} else {
+		loader.SetOverlays(c.overlays)
+		loader.SetMergeMode(c.opts.merge)
+	}
+	if _, err := newLinkResolver(c.opts.linkResolvers); err != nil {
+		configErrors = append(configErrors, fmt.Errorf("link resolvers: %v", err))
+	}
+
+	dirs, err := paths.Default()
+	if err != nil {
+		configErrors = append(configErrors, fmt.Errorf("cache directory: %v", err))
+	}
+
+	findings := selfcheck.Run(selfcheck.Options{
+		Loader:         loader,
+		ConfigErrors:   configErrors,
+		ManifestSource: manifestSource,
+		CacheDir:       dirs.Cache,
+	})
+
+	if c.format == "json" {
+		jsonData, err := json.MarshalIndent(struct {
+			Version  string              `json:"version"`
+			Findings []selfcheck.Finding `json:"findings"`
+		}{
+			Version:  buildinfo.Get().Version,
+			Findings: findings,
+		}, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		formatter := newHumanFormatter(c.color)
+		fmt.Print(formatter.FormatSelfCheck(buildinfo.Get().Version, findings))
+	}
+
+	if selfcheck.HasFailures(findings) {
+		return 1
+	}
+	return 0
+}
+
+// newDoctorPathsFlags defines the flags of doctor paths, all of them shared
+func newDoctorPathsFlags(c *cli) *flag.FlagSet {
+	return c.flagSet("doctor paths", formatFlags)
+}
+
+// runDoctorPathsCommand prints the directories goctor uses
+func runDoctorPathsCommand(c *cli, args []string) int {
+	fs := newDoctorPathsFlags(c)
+	args, err := c.parse(fs, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+
+	entries, err := paths.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error resolving directories: %v\n", err)
+		return 1
+	}
+
+	if c.format == "json" {
+		jsonData, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(jsonData))
+		return 0
+	}
+
+	formatter := newHumanFormatter(c.color)
+	fmt.Print(formatter.FormatPaths(entries))
+	return 0
+}
+
+// reportMergeFlags holds the flags of report merge
+type reportMergeFlags struct {
+	*flag.FlagSet
+	out *string
+}
+
+// newReportMergeFlags defines the flags of report merge
+func newReportMergeFlags() reportMergeFlags {
+	fs := flag.NewFlagSet("report merge", flag.ContinueOnError)
+	return reportMergeFlags{
+		FlagSet: fs,
+		out:     fs.String("o", "-", "path to write the merged report to, - for stdout"),
+	}
+}
+
+func runReportMergeCommand(c *cli, args []string) int {
+	fs := newReportMergeFlags()
+	inputs, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if len(inputs) < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: goctor doctor report merge [WORKSPACE=]REPORT.json... [-o PATH]")
+		return 1
+	}
+
+	reports := make([]checker.WorkspaceReport, 0, len(inputs))
+	for _, input := range inputs {
+		workspace, path, named := strings.Cut(input, "=")
+		if !named {
+			path = input
+		}
+
+		report, err := checker.LoadEnvironmentReport(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if !named {
+			workspace = workspaceName(path, report.ManifestSource)
+		}
+		reports = append(reports, checker.WorkspaceReport{Workspace: workspace, Report: *report})
+	}
+
+	merged, err := checker.MergeReports(reports)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error merging reports: %v\n", err)
+		return 1
+	}
+
+	jsonData, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+		return 1
+	}
+
+	if *fs.out == "-" {
+		fmt.Println(string(jsonData))
+		return 0
+	}
+	if err := os.WriteFile(*fs.out, append(jsonData, '\n'), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing merged report: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Merged %d reports (%d tools) into %s\n", len(reports), merged.Summary.Total, *fs.out)
+	return 0
+}
+
+// workspaceName names a report's workspace after the directory of its manifest, e.g. api
+// for services/api/tools.yaml, or after the report file when the manifest is at the root
+func workspaceName(reportPath, manifestSource string) string {
+	if !strings.Contains(manifestSource, "://") {
+		dir := filepath.Base(filepath.Dir(manifestSource))
+		if dir != "." && dir != string(filepath.Separator) {
+			return dir
+		}
+	}
+	return strings.TrimSuffix(filepath.Base(reportPath), filepath.Ext(reportPath))
+}
+
+// genFixturesFlags holds the flags of gen-fixtures
+type genFixturesFlags struct {
+	*flag.FlagSet
+	out     *string
+	tools   *int
+	missing *int
+}
+
+// newGenFixturesFlags defines the flags of gen-fixtures
+func newGenFixturesFlags() genFixturesFlags {
+	fs := flag.NewFlagSet("gen-fixtures", flag.ContinueOnError)
+	return genFixturesFlags{
+		FlagSet: fs,
+		out:     fs.String("o", fixtures.DefaultDir, "directory to write the fixture manifests to"),
+		tools:   fs.Int("tools", fixtures.DefaultLargeTools, "number of tools in the synthetic large manifest"),
+		missing: fs.Int("missing", 0, "number of synthetic tools whose command does not exist"),
+	}
+}
+
+func runGenFixturesCommand(c *cli, args []string) int {
+	fs := newGenFixturesFlags()
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+
+	written, err := fixtures.Write(*fs.out, fixtures.Options{LargeTools: *fs.tools, LargeMissing: *fs.missing})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating fixtures: %v\n", err)
+		return 1
+	}
+
+	for _, path := range written {
+		fmt.Printf("Wrote %s\n", path)
+	}
+	return 0
+}
+
+// listFlags holds the flags of list
+type listFlags struct {
+	*flag.FlagSet
+	tags     *string
+	platform *string
+	sort     *string
+	status   *bool
+}
+
+// newListFlags defines the flags of list
+func newListFlags(c *cli) listFlags {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	f := listFlags{
+		FlagSet:  fs,
+		tags:     fs.String("tags", "", "only list tools with any of these comma-separated tags"),
+		platform: fs.String("platform", "", "only list tools that apply to OS or OS/ARCH"),
+		sort:     fs.String("sort", "", "sort by "+strings.Join(manifest.ToolSortKeys, ", ")),
+		status:   fs.Bool("with-status", false, "show each tool's current status, reusing recent results"),
+	}
+	c.share(fs, formatFlags, []string{"lang"}, manifestFlags, linkFlags, safetyFlags, []string{"resolve-shims"})
+	return f
+}
+
+func runListCommand(c *cli, args []string) int {
+	fs := newListFlags(c)
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+	format := c.format
+
+	run, err := c.checkRun()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		return 1
+	}
+	loader, resolver := run.loader, run.resolver
+
+	// Load manifest
+	manifestSource := c.manifestPath()
+
+	m, err := loader.LoadFromSource(manifestSource)
+	if err != nil {
+		printCommandError(format, "list", fmt.Errorf("loading manifest: %w", err))
+		return 1
+	}
+
+	filter := manifest.ToolFilter{Platform: *fs.platform}
+	if *fs.tags != "" {
+		filter.Tags = strings.Split(*fs.tags, ",")
+	}
+	tools := manifest.FilterTools(m.Tools, filter)
+	if err := manifest.SortTools(tools, *fs.sort); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Resolve logical links for rendering
+	for i := range tools {
+		tools[i].Links = resolver.ResolveAll(tools[i].Links)
+		// A file that cannot be read is reported by the check; the list shows require meanwhile
+		if required, err := checker.ResolveRequirement(tools[i]); err == nil {
+			tools[i].RequiredVersion = required
+		}
+	}
+
+	var results map[string]checker.CheckResult
+	if *fs.status {
+		results = quickCheck(tools, run.resolveShims, run.safety, c.cfg.Cache)
+	}
+
+	// Output tool list
+	if format == "json" {
+		listResponse := output.ListResponse{
+			SchemaVersion:  output.ListSchemaVersion,
+			ManifestSource: strings.Join(manifestSources(loader, manifestSource), ", "),
+			Tools:          make([]output.ListedTool, len(tools)),
+		}
+
+		for i, tool := range tools {
+			listResponse.Tools[i] = output.ListedTool{
+				ID:              tool.ID,
+				Name:            tool.Name,
+				RequiredVersion: tool.RequiredVersion,
+				Rationale:       tool.Rationale,
+				Severity:        tool.Severity,
+				Tags:            tool.Tags,
+				Scope:           tool.Scope,
+				Platforms:       tool.Platforms,
+			}
+			if result, ok := results[tool.ID]; ok {
+				listResponse.Tools[i].Status = result.Status.String()
+				listResponse.Tools[i].ActualVersion = result.ActualVersion
+			}
+		}
+
+		jsonData, err := json.MarshalIndent(listResponse, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		formatter := newHumanFormatter(c.color)
+		formatter.SetLanguage(i18n.Resolve(c.opts.lang, m.Meta.Language, os.Getenv))
+		output := formatter.FormatToolListWithStatus(tools, results, manifestSource)
+		fmt.Print(output)
+	}
+
+	return 0
+}
+
+// quickCheck checks the tools that apply to this platform, reusing results cached by
+// recent runs; the cache is best-effort and skipped if goctor has no cache directory
+func quickCheck(tools []manifest.ToolDefinition, resolveShims bool, safety commandSafety, cacheConfig config.Cache) map[string]checker.CheckResult {
+	platformInfo := platform.DetectPlatform()
+	toolChecker := checker.NewChecker()
+	toolChecker.SetResolveShims(resolveShims)
+	safety.apply(toolChecker)
+
+	var cache *checker.ResultCache
+	if dirs, err := paths.Default(); err == nil && !cacheConfig.Disabled {
+		cache = checker.LoadResultCache(dirs.ResultCache(), cmp.Or(cacheConfig.ResultTTL, checker.DefaultResultCacheTTL))
+	}
+
+	results := make(map[string]checker.CheckResult, len(tools))
+	for _, tool := range tools {
+		if !tool.AppliesTo(platformInfo.OS, platformInfo.Architecture) {
+			continue
+		}
+		if cache != nil {
+			results[tool.ID] = toolChecker.CheckToolCached(tool, platformInfo, cache)
+		} else {
+			results[tool.ID] = toolChecker.CheckTool(tool, platformInfo)
+		}
+	}
+
+	if cache != nil {
+		if err := cache.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save result cache: %v\n", err)
+		}
+	}
+
+	return results
+}
+
+// explainFlags holds the flags of explain
+type explainFlags struct {
+	*flag.FlagSet
+	check *bool
+}
+
+// newExplainFlags defines the flags of explain
+func newExplainFlags(c *cli) explainFlags {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	f := explainFlags{
+		FlagSet: fs,
+		check:   fs.Bool("check", false, "run the check and show live detection details"),
+	}
+	c.share(fs, formatFlags, manifestFlags, linkFlags, safetyFlags)
+	return f
+}
+
+func runExplainCommand(c *cli, args []string) int {
+	fs := newExplainFlags(c)
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: goctor explain TOOL_ID [--check]")
+		return 1
+	}
+	toolID, format := args[0], c.format
+
+	run, err := c.checkRun()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		return 1
+	}
+
+	manifestSource := c.manifestPath()
+
+	m, err := run.loader.LoadFromSource(manifestSource)
+	if err != nil {
+		printCommandError(format, "explain", fmt.Errorf("loading manifest: %w", err))
+		return 1
+	}
+
+	tool := m.GetTool(toolID)
+	if tool == nil {
+		fmt.Fprintf(os.Stderr, "Tool not found in manifest: %s\n", toolID)
+		return 1
+	}
+	tool.Links = run.resolver.ResolveAll(tool.Links)
+	if required, err := checker.ResolveRequirement(*tool); err == nil {
+		tool.RequiredVersion = required
+	}
+
+	var explanation string
+	if tool.RequiredVersion != "" {
+		explanation, err = semver.ExplainConstraints(tool.RequiredVersion)
+		if err != nil {
+			explanation = "unparseable constraint: " + err.Error()
+		}
+	}
+
+	var result *checker.CheckResult
+	if *fs.check {
+		platformInfo := platform.DetectPlatform()
+		toolChecker := checker.NewChecker()
+		run.safety.apply(toolChecker)
+		checkResult := toolChecker.CheckTool(*tool, platformInfo)
+		result = &checkResult
+	}
+
+	if format == "json" {
+		explainResponse := struct {
+			Tool        manifest.ToolDefinition `json:"tool"`
+			Explanation string                  `json:"constraint_explanation,omitempty"`
+			Check       *checker.CheckResult    `json:"check,omitempty"`
+			RawOutput   string                  `json:"raw_output,omitempty"`
+		}{
+			Tool:        *tool,
+			Explanation: explanation,
+			Check:       result,
+		}
+		if result != nil {
+			explainResponse.RawOutput = result.RawOutput
+		}
+
+		jsonData, err := json.MarshalIndent(explainResponse, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		formatter := newHumanFormatter(c.color)
+		fmt.Print(formatter.FormatToolExplanation(*tool, explanation, result))
+	}
+
+	return 0
+}
+
+// newDiffFlags defines the flags of diff, all of them shared
+func newDiffFlags(c *cli) *flag.FlagSet {
+	return c.flagSet("diff", formatFlags)
+}
+
+func runDiffCommand(c *cli, args []string) int {
+	paths, err := c.parse(newDiffFlags(c), args)
+	if err != nil {
+		return 1
+	}
+	if len(paths) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: goctor diff OLD.json NEW.json [--json]")
+		return 1
+	}
 
-func main() {
-	var (
-		manifestFlag = flag.String("f", "", "manifest file path or URL")
-		jsonFlag     = flag.Bool("json", false, "output JSON format")
-		helpFlag     = flag.Bool("h", false, "show help")
-		versionFlag  = flag.Bool("v", false, "show version")
-	)
+	oldReport, err := checker.LoadEnvironmentReport(paths[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading report: %v\n", err)
+		return 1
+	}
+	newReport, err := checker.LoadEnvironmentReport(paths[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading report: %v\n", err)
+		return 1
+	}
 
-	flag.Parse()
+	diff := checker.DiffReports(*oldReport, *newReport)
 
-	if *helpFlag {
-		showHelp()
-		return
+	if c.format == "json" {
+		jsonData, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(jsonData))
+		return 0
 	}
 
-	if *versionFlag {
-		fmt.Printf("goctor version %s\n", version)
-		return
+	formatter := newHumanFormatter(c.color)
+	fmt.Print(formatter.FormatReportDiff(diff, paths[0], paths[1]))
+
+	return 0
+}
+
+// reportFlags holds the flags of report
+type reportFlags struct {
+	*flag.FlagSet
+	input *string
+}
+
+// newReportFlags defines the flags of report
+func newReportFlags(c *cli) reportFlags {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	f := reportFlags{
+		FlagSet: fs,
+		input:   fs.String("i", "", "doctor --json report to render, - for stdin"),
 	}
+	c.share(fs, formatFlags, viewFlags, exitFlags)
+	return f
+}
 
-	args := flag.Args()
-	if len(args) == 0 {
-		args = []string{"doctor"} // Default command
+// runReportCommand renders a saved doctor --json report in any output format without checking
+// anything, so checks can run on one machine and be presented elsewhere
+func runReportCommand(c *cli, args []string) int {
+	fs := newReportFlags(c)
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if *fs.input == "" || len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: goctor report -i REPORT.json [--format FORMAT]")
+		return 1
 	}
 
-	command := args[0]
+	var report *checker.EnvironmentReport
+	if *fs.input == "-" {
+		var data []byte
+		data, err = io.ReadAll(os.Stdin)
+		if err == nil {
+			report, err = checker.ParseEnvironmentReport(data, "from stdin")
+		}
+	} else {
+		report, err = checker.LoadEnvironmentReport(*fs.input)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading report: %v\n", err)
+		return 1
+	}
 
-	switch command {
-	case "doctor":
-		exitCode := runDoctorCommand(*manifestFlag, *jsonFlag)
-		os.Exit(exitCode)
-	case "list":
-		exitCode := runListCommand(*manifestFlag, *jsonFlag)
-		os.Exit(exitCode)
-	default:
-		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
-		showHelp()
-		os.Exit(1)
+	formatter := newHumanFormatter(c.color)
+	formatter.SetLanguage(i18n.Resolve(c.opts.lang, report.Language, os.Getenv))
+	formatter.SetView(c.view)
+	formatter.SetShowDurations(c.opts.durations)
+	formatter.SetVerbose(c.opts.verbose)
+	jsonLines := output.NewJSONLinesFormatter()
+	jsonLines.SetExitPolicy(c.exitPolicy)
+	if err := printReport(*report, report.Items, c.format, formatter, jsonLines, c.color); err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		return 1
 	}
+	return 0
 }
 
-func runDoctorCommand(manifestSource string, useJSON bool) int {
-	// Load manifest
-	loader := manifest.NewLoader()
-	var m *manifest.Manifest
-	var err error
+// historyFlags holds the flags of history
+type historyFlags struct {
+	*flag.FlagSet
+	diff *bool
+}
 
-	if manifestSource == "" {
-		// Default to ./tools.yaml
-		manifestSource = "./tools.yaml"
+// newHistoryFlags defines the flags of history and history show
+func newHistoryFlags(c *cli) historyFlags {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	f := historyFlags{
+		FlagSet: fs,
+		diff:    fs.Bool("diff-latest", false, "compare with the latest run"),
 	}
+	c.share(fs, formatFlags, []string{"lang"})
+	return f
+}
+
+// runHistoryCommand lists the recorded runs
+func runHistoryCommand(c *cli, args []string) int {
+	return runHistory(c, false, args)
+}
 
-	m, err = loader.LoadFromSource(manifestSource)
+// runHistoryShowCommand shows one recorded run
+func runHistoryShowCommand(c *cli, args []string) int {
+	return runHistory(c, true, args)
+}
 
+func runHistory(c *cli, show bool, args []string) int {
+	fs := newHistoryFlags(c)
+	ids, err := c.parse(fs.FlagSet, args)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading manifest: %v\n", err)
 		return 1
 	}
+	if (show && len(ids) != 1) || (!show && len(ids) != 0) {
+		fmt.Fprintln(os.Stderr, "Usage: goctor history [--diff-latest] [--json] | goctor history show RUN_ID [--diff-latest] [--json]")
+		return 1
+	}
+	asJSON, color := c.format == "json", c.color
 
-	// Detect platform
-	platformInfo := platform.DetectPlatform()
-	if !platformInfo.IsSupported() {
-		fmt.Fprintf(os.Stderr, "Unsupported platform: %s\n", platformInfo.String())
+	dirs, err := paths.Default()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error resolving directories: %v\n", err)
 		return 1
 	}
+	store := history.NewStore(dirs.History())
 
-	// Create checker and run checks
-	toolChecker := checker.NewChecker()
-	results := make([]checker.CheckResult, len(m.Tools))
+	selected := history.Latest
+	if show {
+		selected = ids[0]
+	}
 
-	for i, tool := range m.Tools {
-		result := toolChecker.CheckTool(tool, platformInfo)
-		results[i] = result
+	switch {
+	case *fs.diff:
+		return runHistoryDiff(store, selected, asJSON, color)
+	case show:
+		report, entry, err := store.Load(selected)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading run: %v\n", err)
+			return 1
+		}
+		if asJSON {
+			jsonData, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+				return 1
+			}
+			fmt.Println(string(jsonData))
+			return 0
+		}
+		formatter := newHumanFormatter(color)
+		formatter.SetLanguage(i18n.Resolve(c.opts.lang, report.Language, os.Getenv))
+		fmt.Printf("Run %s\n\n", entry.ID)
+		fmt.Print(formatter.FormatEnvironmentReport(*report))
+		return 0
 	}
 
-	// Generate report
-	report := checker.NewEnvironmentReport(platformInfo, manifestSource, results)
+	entries, err := store.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+		return 1
+	}
+	if asJSON {
+		jsonData, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(jsonData))
+		return 0
+	}
+	if len(entries) == 0 {
+		fmt.Println("No runs recorded yet; every goctor doctor run is recorded unless --no-history is set")
+		return 0
+	}
+	fmt.Print(newHumanFormatter(color).FormatHistory(entries))
+	return 0
+}
 
-	// Output results
-	if useJSON {
-		jsonData, err := json.MarshalIndent(report, "", "  ")
+// runHistoryDiff compares a recorded run with the latest one; the latest run itself is
+// compared with the run before it
+func runHistoryDiff(store *history.Store, id string, asJSON bool, color bool) int {
+	latest, latestEntry, err := store.Load(history.Latest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading run: %v\n", err)
+		return 1
+	}
+
+	old, oldEntry, err := store.Load(id)
+	if err == nil && oldEntry.ID == latestEntry.ID {
+		old, oldEntry, err = store.Previous(latestEntry.ID)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading run: %v\n", err)
+		return 1
+	}
+
+	diff := checker.DiffReports(*old, *latest)
+
+	if asJSON {
+		jsonData, err := json.MarshalIndent(diff, "", "  ")
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
 			return 1
 		}
 		fmt.Println(string(jsonData))
+		return 0
+	}
+
+	fmt.Print(newHumanFormatter(color).FormatReportDiff(diff, oldEntry.ID, latestEntry.ID))
+	return 0
+}
+
+// migrateFlags holds the flags of migrate
+type migrateFlags struct {
+	*flag.FlagSet
+	output *string
+}
+
+// newMigrateFlags defines the flags of migrate
+func newMigrateFlags(c *cli) migrateFlags {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	f := migrateFlags{
+		FlagSet: fs,
+		output:  fs.String("o", "", "output path (default: rewrite in place, \"-\" for stdout)"),
+	}
+	c.share(fs, []string{"f"})
+	return f
+}
+
+func runMigrateCommand(c *cli, args []string) int {
+	fs := newMigrateFlags(c)
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+	if len(c.overlays) > 0 {
+		fmt.Fprintln(os.Stderr, "Error: migrate rewrites a single manifest; pass one -f")
+		return 1
+	}
+
+	manifestSource := c.manifestPath()
+
+	if strings.HasPrefix(manifestSource, "http://") || strings.HasPrefix(manifestSource, "https://") {
+		fmt.Fprintln(os.Stderr, "Error: migrate only supports local manifest files")
+		return 1
+	}
+
+	// A manifest piped to stdin is migrated to stdout
+	var data []byte
+	if manifestSource == manifest.StdinSource {
+		data, err = io.ReadAll(os.Stdin)
 	} else {
-		formatter := output.NewHumanFormatter()
-		output := formatter.FormatEnvironmentReport(*report)
-		fmt.Print(output)
+		data, err = os.ReadFile(manifestSource)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading manifest: %v\n", err)
+		return 1
+	}
+
+	migrated, err := manifest.MigrateToCurrent(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error migrating manifest: %v\n", err)
+		return 1
+	}
+
+	outputPath := *fs.output
+	if outputPath == "" {
+		outputPath = manifestSource
 	}
 
-	return report.GetExitCode()
+	if outputPath == "-" {
+		fmt.Print(string(migrated))
+		return 0
+	}
+
+	if err := os.WriteFile(outputPath, migrated, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing manifest: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "Migrated %s to manifest version %d\n", outputPath, manifest.CurrentVersion)
+	return 0
 }
 
-func runListCommand(manifestSource string, useJSON bool) int {
-	// Load manifest
-	loader := manifest.NewLoader()
-	var m *manifest.Manifest
-	var err error
+// exportFlags holds the flags of export
+type exportFlags struct {
+	*flag.FlagSet
+	format *string
+	output *string
+}
+
+// newExportFlags defines the flags of export; its --format names the export format
+func newExportFlags(c *cli) exportFlags {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	f := exportFlags{
+		FlagSet: fs,
+		format:  fs.String("format", "", "export format ("+strings.Join(export.Formats, ", ")+")"),
+		output:  fs.String("o", "-", "output path (\"-\" for stdout)"),
+	}
+	c.share(fs, manifestFlags)
+	return f
+}
+
+func runExportCommand(c *cli, args []string) int {
+	fs := newExportFlags(c)
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+	if *fs.format == "" {
+		fmt.Fprintf(os.Stderr, "Error: --format is required (%s)\n", strings.Join(export.Formats, ", "))
+		return 1
+	}
 
-	if manifestSource == "" {
-		// Default to ./tools.yaml
-		manifestSource = "./tools.yaml"
+	loader, err := c.loader()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		return 1
 	}
 
-	m, err = loader.LoadFromSource(manifestSource)
+	manifestSource := c.manifestPath()
 
+	m, err := loader.LoadFromSource(manifestSource)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading manifest: %v\n", err)
 		return 1
 	}
 
-	// Output tool list
-	if useJSON {
-		listResponse := struct {
-			ManifestSource string `json:"manifest_source"`
-			Tools          []struct {
-				ID              string `json:"id"`
-				Name            string `json:"name"`
-				RequiredVersion string `json:"required_version"`
-				Rationale       string `json:"rationale"`
-			} `json:"tools"`
+	exported, err := export.Export(*fs.format, m.Meta.Name, m.Tools)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *fs.output == "-" {
+		fmt.Print(exported)
+		return 0
+	}
+
+	if err := os.WriteFile(*fs.output, []byte(exported), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", *fs.output, err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %s to %s\n", manifestSource, *fs.output)
+	return 0
+}
+
+// sbomFormats lists the SBOM formats of `goctor sbom`
+var sbomFormats = []string{"cyclonedx"}
+
+// sbomFlags holds the flags of sbom
+type sbomFlags struct {
+	*flag.FlagSet
+	format *string
+	report *string
+	output *string
+}
+
+// newSBOMFlags defines the flags of sbom
+func newSBOMFlags(c *cli) sbomFlags {
+	fs := flag.NewFlagSet("sbom", flag.ContinueOnError)
+	f := sbomFlags{
+		FlagSet: fs,
+		format:  fs.String("format", "cyclonedx", "SBOM format ("+strings.Join(sbomFormats, ", ")+")"),
+		report:  fs.String("report", "", "build the SBOM from a doctor --json report instead of checking"),
+		output:  fs.String("o", "-", "output path (\"-\" for stdout)"),
+	}
+	c.share(fs, manifestFlags, checkFlags)
+	return f
+}
+
+// runSBOMCommand writes an SBOM of the installed tools, from a new check or a saved doctor --json report
+func runSBOMCommand(c *cli, args []string) int {
+	fs := newSBOMFlags(c)
+	args, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+	if !slices.Contains(sbomFormats, *fs.format) {
+		fmt.Fprintf(os.Stderr, "Error: unknown SBOM format %q (must be one of: %s)\n", *fs.format, strings.Join(sbomFormats, ", "))
+		return 1
+	}
+
+	var report *checker.EnvironmentReport
+	if *fs.report != "" {
+		report, err = checker.LoadEnvironmentReport(*fs.report)
+	} else {
+		var run checkRun
+		run, err = c.checkRun()
+		if err == nil {
+			manifestSource := c.manifestPath()
+			report, _, err = run.check(context.Background(), manifestSource, nil, nil)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	sbom, err := output.NewCycloneDXFormatter(buildinfo.Get().Version).FormatEnvironmentReport(*report)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *fs.output == "-" {
+		fmt.Print(sbom)
+		return 0
+	}
+
+	if err := os.WriteFile(*fs.output, []byte(sbom), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", *fs.output, err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote %s SBOM to %s\n", *fs.format, *fs.output)
+	return 0
+}
+
+// schemaFlags holds the flags of schema
+type schemaFlags struct {
+	*flag.FlagSet
+	output *string
+}
+
+// newSchemaFlags defines the flags of schema
+func newSchemaFlags() schemaFlags {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	return schemaFlags{
+		FlagSet: fs,
+		output:  fs.String("o", "", "write the schemas as versioned files into this directory"),
+	}
+}
+
+// runSchemaCommand prints the JSON Schema of a report, list output, or the manifest, or with -o
+// writes them as the versioned files published under schema/
+func runSchemaCommand(c *cli, args []string) int {
+	fs := newSchemaFlags()
+	names, err := c.parse(fs.FlagSet, args)
+	if err != nil {
+		return 1
+	}
+
+	if *fs.output == "" {
+		if len(names) != 1 {
+			fmt.Fprintf(os.Stderr, "Usage: goctor schema %s (or -o DIR [NAME...])\n", strings.Join(schema.Names, "|"))
+			return 1
+		}
+		s, err := schema.Lookup(names[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		data, err := schema.Document(s)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		os.Stdout.Write(data)
+		return 0
+	}
+
+	if len(names) == 0 {
+		names = schema.Names
+	}
+	if err := os.MkdirAll(*fs.output, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	for _, name := range names {
+		s, err := schema.Lookup(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		data, err := schema.Document(s)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fileName, _ := schema.FileName(name)
+		path := filepath.Join(*fs.output, fileName)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+	}
+	return 0
+}
+
+// newVersionFlags defines the flags of version, all of them shared
+func newVersionFlags(c *cli) *flag.FlagSet {
+	return c.flagSet("version", []string{"json", "format"})
+}
+
+func runVersionCommand(c *cli, args []string) int {
+	args, err := c.parse(newVersionFlags(c), args)
+	if err != nil {
+		return 1
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument: %s\n", args[0])
+		return 1
+	}
+
+	info := buildinfo.Get()
+
+	if c.format == "json" {
+		versionResponse := struct {
+			buildinfo.Info
+			ManifestVersions    []int    `json:"manifest_versions"`
+			ReportSchemaVersion int      `json:"report_schema_version"`
+			Features            []string `json:"features"`
 		}{
-			ManifestSource: manifestSource,
-			Tools:          make([]struct {
-				ID              string `json:"id"`
-				Name            string `json:"name"`
-				RequiredVersion string `json:"required_version"`
-				Rationale       string `json:"rationale"`
-			}, len(m.Tools)),
-		}
-
-		for i, tool := range m.Tools {
-			listResponse.Tools[i] = struct {
-				ID              string `json:"id"`
-				Name            string `json:"name"`
-				RequiredVersion string `json:"required_version"`
-				Rationale       string `json:"rationale"`
-			}{
-				ID:              tool.ID,
-				Name:            tool.Name,
-				RequiredVersion: tool.RequiredVersion,
-				Rationale:       tool.Rationale,
-			}
+			Info:                info,
+			ManifestVersions:    manifest.SupportedVersions,
+			ReportSchemaVersion: checker.ReportSchemaVersion,
+			Features:            buildinfo.Features,
 		}
 
-		jsonData, err := json.MarshalIndent(listResponse, "", "  ")
+		jsonData, err := json.MarshalIndent(versionResponse, "", "  ")
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
 			return 1
 		}
 		fmt.Println(string(jsonData))
-	} else {
-		formatter := output.NewHumanFormatter()
-		output := formatter.FormatToolList(m.Tools, manifestSource)
-		fmt.Print(output)
+		return 0
+	}
+
+	fmt.Printf("goctor version %s\n", info.Version)
+	if info.Commit != "" {
+		fmt.Printf("  commit:   %s\n", info.Commit)
+	}
+	if info.Date != "" {
+		fmt.Printf("  built:    %s\n", info.Date)
+	}
+	fmt.Printf("  go:       %s\n", info.GoVersion)
+	fmt.Printf("  platform: %s\n", info.Platform)
+
+	return 0
+}
+
+// runCapabilities prints what this build supports so wrapper tooling can feature-detect it
+func runCapabilities() int {
+	platforms := make([]string, 0, len(platform.SupportedOS)*len(platform.SupportedArchitectures))
+	for _, goos := range platform.SupportedOS {
+		for _, goarch := range platform.SupportedArchitectures {
+			platforms = append(platforms, goos+"/"+goarch)
+		}
 	}
 
+	capabilities := struct {
+		Version             string   `json:"version"`
+		Commands            []string `json:"commands"`
+		Formats             []string `json:"formats"`
+		CheckTypes          []string `json:"check_types"`
+		VersionSchemes      []string `json:"version_schemes"`
+		ManifestVersions    []int    `json:"manifest_versions"`
+		ManifestFormats     []string `json:"manifest_formats"`
+		Languages           []string `json:"languages"`
+		ReportSchemaVersion int      `json:"report_schema_version"`
+		Platforms           []string `json:"platforms"`
+		Features            []string `json:"features"`
+		BuiltinTools        []string `json:"builtin_tools"`
+	}{
+		Version:             buildinfo.Get().Version,
+		Commands:            commandNames(commandTree()),
+		Formats:             outputFormats,
+		CheckTypes:          manifest.CheckTypes,
+		VersionSchemes:      semver.SchemeNames(),
+		ManifestVersions:    manifest.SupportedVersions,
+		ManifestFormats:     manifest.ManifestFormats,
+		Languages:           i18n.Languages,
+		ReportSchemaVersion: checker.ReportSchemaVersion,
+		Platforms:           platforms,
+		Features:            buildinfo.Features,
+		BuiltinTools:        manifest.BuiltinToolIDs(),
+	}
+
+	jsonData, err := json.MarshalIndent(capabilities, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+		return 1
+	}
+	fmt.Println(string(jsonData))
+
 	return 0
 }
 
@@ -186,11 +2681,120 @@ USAGE:
 
 COMMANDS:
     doctor    Check development environment (default)
+    doctor env
+              Diagnose goctor's own setup (config, cache dir, manifest, clock, plugins)
+    doctor paths
+              Print the config, cache, state, and data directories goctor uses
+    doctor serve
+              Serve /healthz, /report, and POST /check over HTTP
+              (doctor serve [--addr HOST:PORT] [--interval 5m])
+    doctor lsp
+              JSON-RPC backend for editor extensions on stdin/stdout
+    doctor outdated
+              Compare required and installed versions with the latest upstream releases
+              (doctor outdated [--threshold patch|minor|major] [--timeout 10s])
+    doctor report merge
+              Combine doctor --json reports of several workspaces into one
+              (doctor report merge [WORKSPACE=]REPORT.json... [-o PATH])
+    doctor dev gen-fixtures
+              Write the test manifests under testdata/manifests
+              (doctor dev gen-fixtures [-o DIR] [--tools N] [--missing N])
+    doctor completion
+              Print a shell completion script (doctor completion bash|zsh|fish)
+    watch     Re-check continuously, printing tools that were fixed or broke
+              (watch [--interval 30s])
+    tui       Interactive dashboard of the tools with live statuses
+    agent     Check this machine and upload the report to a fleet server on an interval
+              (agent --report-to URL [--interval 1h] [--team NAME] [--once])
+    server    Collect reports of a fleet of machines; dashboard and JSON API of compliance
+              (server [--listen HOST:PORT] [--data DIR] [--token TOKEN])
     list      List tools defined in manifest
+              (list [--tags T1,T2] [--platform OS[/ARCH]] [--sort name|id|severity] [--with-status])
+    explain   Show full detail for one tool (explain TOOL_ID [--check])
+    lint      Validate the manifest and check it for best practices; --check-links also
+              reports dead link URLs (lint [--disable RULE,...] [--check-links]
+              [--timeout 10s] [--concurrency N] [--no-cache])
+    diff      Compare two doctor --json reports (diff OLD.json NEW.json [--json])
+    report    Render a saved doctor --json report in any output format without re-checking
+              (report -i REPORT.json|- [--format FORMAT])
+    history   List past doctor runs, show one, or compare with the latest
+              (history [--diff-latest] | history show RUN_ID [--diff-latest])
+    version   Show build information (version [--json])
+    migrate   Rewrite a manifest to the current schema version (migrate [-o PATH])
+    export    Convert the manifest for other installers
+              (export --format brewfile|tool-versions|devcontainer|dockerfile [-o PATH])
+    sbom      Write an SBOM of the installed tools
+              (sbom [--format cyclonedx] [--report REPORT.json] [-o PATH])
+    schema    Print the JSON Schema of reports, list output, or the manifest
+              (schema report|list|manifest, or schema -o DIR [NAME...])
 
-FLAGS:
-    -f, --manifest PATH_OR_URL    Manifest file path or URL
+FLAGS (before or after the command, where the command uses them; a command's own flag of
+the same name wins):
+    -f, --manifest PATH_OR_URL    Manifest file path or URL, ARCHIVE#ENTRY for a bundle,
+                                  or - to read it from stdin (repeatable; later manifests
+                                  take precedence)
+    --merge MODE                  How tools defined by more than one manifest or include merge:
+                                  override (default), strictest-constraint, error-on-conflict
     --json                        Output JSON format
+    --format FORMAT               Output format: human, json, jsonl, markdown, html, github,
+                                  codeclimate, junit
+                                  (default: human; github inside GitHub Actions)
+    --header "NAME: VALUE"        Custom header for remote manifests (repeatable)
+    --ca-cert PATH                PEM file of CA certificates trusted for remote manifests,
+                                  e.g. of a proxy that intercepts TLS
+    --insecure-skip-verify        Do not verify TLS certificates of remote manifests (unsafe;
+                                  prefer --ca-cert)
+    --trust                       Trust remote manifest sources not trusted yet and remember them
+    --restricted                  Only run "<tool id> --version"-style check commands; refuse
+                                  plugins, shell checks, hooks, and other commands
+    --audit-log PATH              Append a JSON line for every command checks and hooks execute
+                                  to PATH
+    --link-resolver NAME=TEMPLATE Resolve logical links like wiki:path (repeatable)
+    --merge-results PATH          Merge findings from another scanner's JSON file (repeatable)
+    --resolve-shims               Run checks through asdf/mise/pyenv/... for the current directory
+    --parallel N                  Run up to N checks concurrently (default: 1)
+    --progress-format FORMAT      Progress events on stderr: none (default) or json
+    --no-history                  Do not record this doctor run for goctor history
+    --lang LANG                   Language of human output: en, ja
+                                  (default: meta.language, then LC_ALL/LC_MESSAGES/LANG)
+    --color WHEN                  Color human output: auto (default), always, never
+    --only-failures               Leave tools that need no attention out of human output
+    --summary-only                Print only the one-line summary in human output
+    --exit-codes POLICY           Exit codes of doctor: simple (default; 1 for any failure),
+                                  granular (1 outdated, 2 missing, 3 error), or CLASS=CODE pairs
+    --exit-zero                   Exit 0 whatever the results (report-only mode)
+    --durations                   Show how long each check took in human output
+    -q, --quiet                   Print only the one-line summary and no warnings; the exit
+                                  code tells the result
+    -V, --verbose                 Show the lookups, commands, raw output, regex matches, and
+                                  durations behind each result
+    --capture-output              Keep the output of version commands in JSON reports
+                                  (sanitized, up to 4 KiB per check)
+    --tags T1,T2                  Check only tools with any of these tags; the others are
+                                  reported as skipped
+    --scope SCOPE                 Check only machine or project tools; the others are reported
+                                  as skipped
+    --allow-prerelease            Let prereleases meet the constraints of their release
+                                  (1.0.0-rc.1 meets >=1.0.0)
+    --dry-run                     Print the command, timeout, regex, capture group, and constraint
+                                  of each tool's check without running anything
+    --slow-threshold DURATION     Warn about checks slower than this (default: 2s; 0 disables)
+    --command-timeout DURATION    How long a version command may run (default: 5s; a tool's
+                                  timeout_sec overrides it)
+    --detect-timeout DURATION     How long looking up each executable may take (default: 5s)
+    --log-level LEVEL             Log diagnostics at this level or above to stderr: debug,
+                                  info, warn, error (default: warn); debug traces manifest
+                                  loading and merging, commands run, and regex matches
+    --log-format FORMAT           Format of logs on stderr: text, json (default: text)
+    --json-file PATH              Also write the JSON report to PATH, whatever the output format
+    --report-url URL              POST the doctor report as JSON to URL (or GOCTOR_REPORT_URL)
+    --report-header "NAME: VALUE"  Header sent with report uploads (repeatable)
+    --anonymize                   Replace the hostname with a stable hash and strip usernames
+                                  from paths in reports (or report.anonymize in config.yaml)
+    --no-expand-env               Keep ${VAR} references in the manifest as written
+    --target URL                  Run doctor checks elsewhere: ssh://[USER@]HOST[:PORT],
+                                  or docker://IMAGE|CONTAINER
+    --capabilities                Print supported formats, check types, schemas, and features as JSON
     -h, --help                    Show help
     -v, --version                 Show version
 
@@ -198,7 +2802,36 @@ EXAMPLES:
     doctor                                    # Check using ./tools.yaml
     doctor -f custom-manifest.yaml           # Check using custom manifest
     doctor --json                            # Output JSON format
+    doctor --format markdown                 # Output Markdown for PRs and wikis
+    --format html doctor > report.html       # Standalone HTML report
+    --json-file report.json doctor           # Human output plus a JSON artifact
+    -f base.yaml -f local.yaml doctor         # Layer local overrides over a shared manifest
+    --only-failures doctor                   # Show only the tools that need attention
+    doctor -V                                 # Debug why a version fails to parse
+    doctor --dry-run                          # Review what each check would run
+    watch --interval 10s                     # Watch checks flip while installing tools
+    agent --report-to https://goctor.example.com/reports --team backend  # Report to a fleet server hourly
     list                                     # List tools in ./tools.yaml
     list -f https://company.com/manifest.yaml # List tools from remote manifest
+    doctor -f https://company.com/tools.yaml --trust --restricted  # Trust a remote manifest, run only --version checks
+    list --tags backend --sort severity       # Backend tools, blocking ones first
+    list --platform darwin --with-status      # macOS tools with their current status
+    explain go --check                        # Explain the go tool and run its check
+    diff before.json after.json               # Show what changed between two reports
+    report -i report.json --format junit      # Render a saved report as JUnit XML for CI
+    export --format tool-versions -o .tool-versions # Pin minimum versions for asdf
+    export --format devcontainer              # Dev container features of the manifest tools
+
+ENVIRONMENT:
+    GOCTOR_AUTH_TOKEN    Bearer token sent with remote manifest requests
+    HTTPS_PROXY, HTTP_PROXY, NO_PROXY  Proxy for remote manifest requests
+    GOCTOR_NETRC         netrc-style credentials file (default: ~/.netrc)
+    GOCTOR_LINK_RESOLVERS  Comma-separated NAME=TEMPLATE link resolvers
+    GOCTOR_SERVER_TOKEN  Bearer token goctor server requires with uploaded reports
+    LC_ALL, LC_MESSAGES, LANG  Language of human output when neither --lang nor meta.language selects one
+
+CONFIGURATION:
+    Defaults for the flags above are read from config.yaml in the config directory
+    (see doctor paths); flags and environment variables override it.
 `)
 }
@@ -0,0 +1,492 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/config"
+	"github.com/ikorihn/goctor/internal/i18n"
+	"github.com/ikorihn/goctor/internal/logging"
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/output"
+	"github.com/ikorihn/goctor/internal/paths"
+	"github.com/ikorihn/goctor/internal/platform"
+	"github.com/ikorihn/goctor/internal/scheduler"
+)
+
+// options holds the flags commands share; they may be given before the command, as in
+// `goctor --json doctor`, or after it among the command's own flags
+type options struct {
+	// Output
+	json         bool
+	format       string
+	color        string
+	lang         string
+	quiet        bool
+	verbose      bool
+	onlyFailures bool
+	summaryOnly  bool
+	durations    bool
+	exitCodes    string
+	exitZero     bool
+	logLevel     string
+	logFormat    string
+
+	// Manifest
+	manifests     multiFlag
+	headers       multiFlag
+	linkResolvers multiFlag
+	merge         string
+	caCert        string
+	insecure      bool
+	noExpandEnv   bool
+	trust         bool
+
+	// Check
+	resolveShims   bool
+	parallel       int
+	progressFormat string
+	noHistory      bool
+	jsonFile       string
+	slowThreshold  time.Duration
+	target         string
+	reportURL      string
+	reportHeaders  multiFlag
+	captureOutput  bool
+	tags           string
+	scope          string
+	prerelease     bool
+	dryRun         bool
+	restricted     bool
+	auditLog       string
+	commandTimeout time.Duration
+	detectTimeout  time.Duration
+	anonymize      bool
+	mergeResults   multiFlag
+}
+
+// defineSharedFlags defines every shared flag on fs, bound to o
+func defineSharedFlags(o *options, fs *flag.FlagSet) {
+	// Output
+	fs.BoolVar(&o.json, "json", false, "output JSON format")
+	fs.StringVar(&o.format, "format", "human", "output format ("+strings.Join(outputFormats, ", ")+")")
+	fs.StringVar(&o.color, "color", output.ColorAuto, "color human output: auto, always, never")
+	fs.StringVar(&o.lang, "lang", "", "language of human output (en, ja); defaults to meta.language, then LANG")
+	fs.BoolVar(&o.quiet, "q", false, "print only the one-line summary and no warnings; the exit code tells the result")
+	fs.BoolVar(&o.quiet, "quiet", false, "same as -q")
+	fs.BoolVar(&o.verbose, "V", false, "show the lookups, commands, output, and regex matches behind each result")
+	fs.BoolVar(&o.verbose, "verbose", false, "same as -V")
+	fs.BoolVar(&o.onlyFailures, "only-failures", false, "print only tools that need attention in human output")
+	fs.BoolVar(&o.summaryOnly, "summary-only", false, "print only the one-line summary in human output")
+	fs.BoolVar(&o.durations, "durations", false, "show how long each check took in human output")
+	fs.StringVar(&o.exitCodes, "exit-codes", checker.ExitPolicySimple, "exit code policy: simple, granular, or CLASS=CODE pairs")
+	fs.BoolVar(&o.exitZero, "exit-zero", false, "exit 0 whatever the check results (report-only mode)")
+	fs.StringVar(&o.logLevel, "log-level", logging.DefaultLevel, "log diagnostics at this level or above to stderr (debug, info, warn, error)")
+	fs.StringVar(&o.logFormat, "log-format", logging.FormatText, "format of logs on stderr (text, json)")
+
+	// Manifest
+	fs.Var(&o.manifests, "f", "manifest file path or URL, or - for stdin (repeatable; later manifests take precedence)")
+	fs.Var(&o.headers, "header", "custom header for remote manifests (\"Name: value\", repeatable)")
+	fs.Var(&o.linkResolvers, "link-resolver", "template for logical links (\"name=https://host/{path}\", repeatable)")
+	fs.StringVar(&o.merge, "merge", manifest.MergeOverride, "how tools defined by more than one manifest merge (override, strictest-constraint, error-on-conflict)")
+	fs.StringVar(&o.caCert, "ca-cert", "", "PEM file of CA certificates trusted for remote manifests")
+	fs.BoolVar(&o.insecure, "insecure-skip-verify", false, "do not verify TLS certificates of remote manifests (unsafe)")
+	fs.BoolVar(&o.noExpandEnv, "no-expand-env", false, "do not expand ${VAR} references in the manifest")
+	fs.BoolVar(&o.trust, "trust", false, "trust remote manifest sources not trusted yet and remember them")
+
+	// Check
+	fs.BoolVar(&o.resolveShims, "resolve-shims", false, "run checks through the owning version manager (asdf, mise, pyenv, ...)")
+	fs.IntVar(&o.parallel, "parallel", 1, "number of checks to run concurrently")
+	fs.StringVar(&o.progressFormat, "progress-format", output.ProgressNone, "progress events on stderr (none, json)")
+	fs.BoolVar(&o.noHistory, "no-history", false, "do not record this run for goctor history")
+	fs.StringVar(&o.jsonFile, "json-file", "", "also write the JSON report to this file")
+	fs.DurationVar(&o.slowThreshold, "slow-threshold", 2*time.Second, "warn about checks slower than this (0 disables)")
+	fs.StringVar(&o.target, "target", "", "run doctor checks on another machine ("+checker.TargetUsage+")")
+	fs.StringVar(&o.reportURL, "report-url", "", "POST the JSON report of each doctor run to this URL")
+	fs.Var(&o.reportHeaders, "report-header", "header sent with --report-url uploads (\"Name: value\", repeatable)")
+	fs.BoolVar(&o.captureOutput, "capture-output", false, "keep the sanitized output of version commands in JSON reports")
+	fs.StringVar(&o.tags, "tags", "", "check only tools with any of these comma-separated tags; the others are reported as skipped")
+	fs.StringVar(&o.scope, "scope", "", "check only machine or project tools; the others are reported as skipped")
+	fs.BoolVar(&o.prerelease, "allow-prerelease", false, "let prereleases meet the constraints of their release (1.0.0-rc.1 meets >=1.0.0)")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "print what doctor would run and evaluate for each tool without running anything")
+	fs.BoolVar(&o.restricted, "restricted", false, "only run \"<tool id> --version\"-style check commands; refuse plugins, shell checks, and hooks")
+	fs.StringVar(&o.auditLog, "audit-log", "", "append a JSON line for every command checks and hooks execute to this file")
+	fs.DurationVar(&o.commandTimeout, "command-timeout", 5*time.Second, "how long a version command may run; a tool's timeout_sec overrides it")
+	fs.DurationVar(&o.detectTimeout, "detect-timeout", 5*time.Second, "how long looking up each executable may take")
+	fs.BoolVar(&o.anonymize, "anonymize", false, "replace the hostname with a stable hash and strip usernames from paths in reports")
+	fs.Var(&o.mergeResults, "merge-results", "merge findings from another scanner's JSON file into the report (repeatable)")
+}
+
+// Sets of shared flags, by name; a command takes the sets, or single flags, whose options it
+// reads, so any other shared flag is rejected instead of ignored
+var (
+	// logFlags set up the diagnostics on stderr; every command takes them
+	logFlags = []string{"log-level", "log-format"}
+	// formatFlags pick the output format and whether human output is colored
+	formatFlags = []string{"json", "format", "color"}
+	// viewFlags shape human reports of check results
+	viewFlags = []string{"lang", "q", "quiet", "V", "verbose", "only-failures", "summary-only", "durations"}
+	// exitFlags map check results to the exit code
+	exitFlags = []string{"exit-codes", "exit-zero"}
+	// manifestFlags choose the manifests and how they are loaded
+	manifestFlags = []string{"f", "header", "merge", "ca-cert", "insecure-skip-verify", "no-expand-env", "trust"}
+	// linkFlags resolve the logical links of tools
+	linkFlags = []string{"link-resolver"}
+	// safetyFlags restrict and audit the commands goctor runs
+	safetyFlags = []string{"restricted", "audit-log"}
+	// checkFlags set up full checks of the manifest's tools (cli.checkRun)
+	checkFlags = []string{"link-resolver", "restricted", "audit-log", "resolve-shims", "parallel", "target",
+		"capture-output", "tags", "scope", "allow-prerelease", "command-timeout", "detect-timeout", "anonymize",
+		"merge-results", "V", "verbose"}
+	// runFlags are doctor's own: what a run shows while checking, and where its report goes
+	runFlags = []string{"progress-format", "slow-threshold", "no-history", "json-file", "report-url",
+		"report-header", "dry-run"}
+)
+
+// cli is one run of goctor: the shared options, the user configuration that fills in the ones
+// left out, and the settings derived from them once the command's flags are parsed
+type cli struct {
+	opts   options
+	root   *flag.FlagSet   // every shared flag, for the flags given before the command
+	given  map[string]bool // shared flags set on the command line or by the configuration
+	cfg    config.Config
+	cfgErr error
+
+	// Set up by parse
+	format         string
+	color          bool
+	view           string
+	slowThreshold  time.Duration
+	exitPolicy     checker.ExitPolicy
+	manifestSource string   // "" for the nearest default manifest
+	overlays       []string // manifests layered over manifestSource
+	auditFile      *os.File
+}
+
+// newCLI defines the shared flags with the defaults of the user configuration; cfgErr is why
+// the configuration could not be read, reported by every command but doctor env
+func newCLI(cfg config.Config, cfgErr error) *cli {
+	c := &cli{
+		root:   flag.NewFlagSet("goctor", flag.ExitOnError),
+		given:  make(map[string]bool),
+		cfg:    cfg,
+		cfgErr: cfgErr,
+	}
+	defineSharedFlags(&c.opts, c.root)
+	if c.cfgErr == nil {
+		c.cfgErr = c.applyUserConfig()
+	}
+	return c
+}
+
+// share defines the shared flags of sets on a command's flags, bound to the same options as
+// the flags given before the command; a command's own flag of the same name, such as
+// export's --format, keeps its meaning
+func (c *cli) share(fs *flag.FlagSet, sets ...[]string) {
+	for _, set := range sets {
+		for _, name := range set {
+			if fs.Lookup(name) != nil {
+				continue
+			}
+			shared := c.root.Lookup(name)
+			fs.Var(shared.Value, shared.Name, shared.Usage)
+			fs.Lookup(name).DefValue = shared.DefValue
+		}
+	}
+}
+
+// flagSet returns the flags of a command that has only shared flags
+func (c *cli) flagSet(name string, sets ...[]string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	c.share(fs, sets...)
+	return fs
+}
+
+// isSet returns true if the named shared flag was given on the command line or set by the
+// user configuration
+func (c *cli) isSet(name string) bool {
+	return c.given[name]
+}
+
+// applyUserConfig sets the shared flags to the configured values; flags on the command line,
+// parsed later, override them
+// The values are set on the flags themselves, so the flags given before the command are only
+// the ones on the command line
+func (c *cli) applyUserConfig() error {
+	settings := []struct{ name, value string }{
+		{"format", c.cfg.Format},
+		{"color", c.cfg.Color},
+		{"lang", c.cfg.Lang},
+		{"ca-cert", c.cfg.CACert},
+	}
+	if c.cfg.Parallel > 0 {
+		settings = append(settings, struct{ name, value string }{"parallel", strconv.Itoa(c.cfg.Parallel)})
+	}
+
+	for _, setting := range settings {
+		if setting.value == "" {
+			continue
+		}
+		if err := c.root.Lookup(setting.name).Value.Set(setting.value); err != nil {
+			return fmt.Errorf("%s: %v", setting.name, err)
+		}
+		c.given[setting.name] = true
+	}
+	return nil
+}
+
+// parse parses a command's flags, which may come before, between, and after its positional
+// arguments, and sets up the shared options; it returns the positional arguments
+// Problems are reported on stderr
+func (c *cli) parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	c.share(fs, logFlags)
+
+	// A shared flag given before the command must be one the command takes as well
+	var unknown error
+	c.root.Visit(func(f *flag.Flag) {
+		if own := fs.Lookup(f.Name); unknown == nil && (own == nil || own.Value != f.Value) {
+			unknown = fmt.Errorf("flag provided but not defined: -%s", f.Name)
+		}
+	})
+	if unknown != nil {
+		fmt.Fprintln(fs.Output(), unknown)
+		fs.Usage()
+		return nil, unknown
+	}
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		// Everything after -- is an argument
+		if len(rest) < len(args) && args[len(args)-len(rest)-1] == "--" {
+			positional = append(positional, rest...)
+			break
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+
+	c.root.Visit(func(f *flag.Flag) {
+		c.given[f.Name] = true
+	})
+	fs.Visit(func(f *flag.Flag) {
+		if shared := c.root.Lookup(f.Name); shared != nil && shared.Value == f.Value {
+			c.given[f.Name] = true
+		}
+	})
+
+	if err := c.setup(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return nil, err
+	}
+	return positional, nil
+}
+
+// setup checks the shared options and derives the settings commands use from them
+func (c *cli) setup() error {
+	o := &c.opts
+
+	c.format = o.format
+	if o.json {
+		c.format = "json"
+	} else if !c.isSet("format") && platform.DetectCI() == platform.CIGitHubActions {
+		// Annotate the workflow run unless a format was requested explicitly
+		c.format = "github"
+	}
+	if !slices.Contains(outputFormats, c.format) {
+		return fmt.Errorf("unknown format: %s", c.format)
+	}
+
+	if !slices.Contains(output.ProgressFormats, o.progressFormat) {
+		return fmt.Errorf("unknown progress format: %s", o.progressFormat)
+	}
+	if o.lang != "" && !i18n.Supported(o.lang) {
+		return fmt.Errorf("unknown language: %s (supported: %s)", o.lang, strings.Join(i18n.Languages, ", "))
+	}
+	if o.scope != "" && !slices.Contains(manifest.Scopes, o.scope) {
+		return fmt.Errorf("unknown scope: %s (supported: %s)", o.scope, strings.Join(manifest.Scopes, ", "))
+	}
+	if !slices.Contains(manifest.MergeModes, o.merge) {
+		return fmt.Errorf("unknown merge mode: %s (supported: %s)", o.merge, strings.Join(manifest.MergeModes, ", "))
+	}
+	if !slices.Contains(output.ColorModes, o.color) {
+		return fmt.Errorf("unknown color mode: %s (supported: %s)", o.color, strings.Join(output.ColorModes, ", "))
+	}
+	c.color = output.ColorEnabled(o.color, os.Stdout, os.Getenv)
+
+	// Logs go to stderr so they never mix with reports on stdout; -q leaves out warnings
+	// unless a log level is asked for
+	logLevel := o.logLevel
+	if o.quiet && !c.isSet("log-level") {
+		logLevel = "error"
+	}
+	logger, err := logging.New(os.Stderr, logLevel, o.logFormat)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+
+	c.view = output.ViewFull
+	c.slowThreshold = o.slowThreshold
+	switch {
+	case o.onlyFailures && o.summaryOnly:
+		return errors.New("--only-failures and --summary-only cannot be combined")
+	case o.quiet && o.verbose:
+		return errors.New("--quiet and --verbose cannot be combined")
+	case o.quiet:
+		// Only the summary line remains; the exit code tells the rest
+		c.view = output.ViewSummary
+		c.slowThreshold = 0
+	case o.onlyFailures:
+		c.view = output.ViewFailures
+	case o.summaryOnly:
+		c.view = output.ViewSummary
+	}
+
+	if o.commandTimeout <= 0 || o.detectTimeout <= 0 {
+		return errors.New("--command-timeout and --detect-timeout must be positive")
+	}
+
+	c.exitPolicy, err = checker.ParseExitPolicy(o.exitCodes)
+	if err != nil {
+		return err
+	}
+	if o.exitZero {
+		c.exitPolicy = checker.ExitPolicy{}
+	}
+
+	// Repeated -f flags layer manifests over the first one, left to right
+	c.manifestSource, c.overlays = "", nil
+	if len(o.manifests) > 0 {
+		c.manifestSource, c.overlays = o.manifests[0], o.manifests[1:]
+	} else if c.cfg.Manifest != "" {
+		// The configured manifest is used where no manifest is found nearby
+		if cwd, err := os.Getwd(); err == nil {
+			if _, found := manifest.FindManifest(cwd); !found {
+				c.manifestSource = c.cfg.Manifest
+			}
+		}
+	}
+
+	return nil
+}
+
+// manifestPath returns the manifest to load: the one given with -f or configured, or else the
+// nearest default manifest (see manifest.DefaultManifestPath)
+func (c *cli) manifestPath() string {
+	if c.manifestSource != "" {
+		return c.manifestSource
+	}
+	return manifest.DefaultManifestPath()
+}
+
+// tlsOptions returns the certificates remote manifests are verified with
+func (c *cli) tlsOptions() manifest.TLSOptions {
+	return manifest.TLSOptions{CACertFile: c.opts.caCert, InsecureSkipVerify: c.opts.insecure}
+}
+
+// loader sets up the manifest loader with the credentials, trust policy, and merging of the
+// shared options
+func (c *cli) loader() (*manifest.Loader, error) {
+	tlsOptions := c.tlsOptions()
+	if tlsOptions.InsecureSkipVerify {
+		fmt.Fprintln(os.Stderr, "WARNING: --insecure-skip-verify disables TLS certificate verification; anyone on the network path can alter remote manifests. Prefer --ca-cert")
+	}
+
+	loader, err := newLoader(c.opts.headers, c.cfg.AuthToken, tlsOptions)
+	if err != nil {
+		return nil, fmt.Errorf("configuring manifest loader: %w", err)
+	}
+	policy, err := newTrustPolicy(c.opts.trust)
+	if err != nil {
+		return nil, fmt.Errorf("loading trusted manifest sources: %w", err)
+	}
+	loader.SetTrustPolicy(policy)
+	loader.SetExpandEnv(!c.opts.noExpandEnv)
+	loader.SetOverlays(c.overlays)
+	loader.SetMergeMode(c.opts.merge)
+	return loader, nil
+}
+
+// checkRun sets up the loader, link resolver, target, and command safety of a check from the
+// shared options
+func (c *cli) checkRun() (checkRun, error) {
+	loader, err := c.loader()
+	if err != nil {
+		return checkRun{}, err
+	}
+
+	resolver, err := newLinkResolver(c.opts.linkResolvers)
+	if err != nil {
+		return checkRun{}, fmt.Errorf("configuring link resolvers: %w", err)
+	}
+
+	var runner checker.Runner
+	if c.opts.target != "" {
+		runner, err = checker.ParseTarget(c.opts.target)
+		if err != nil {
+			return checkRun{}, fmt.Errorf("in --target: %w", err)
+		}
+	}
+
+	safety := commandSafety{restricted: c.opts.restricted}
+	if c.opts.auditLog != "" {
+		if c.auditFile == nil {
+			c.auditFile, err = os.OpenFile(c.opts.auditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				return checkRun{}, fmt.Errorf("opening audit log: %w", err)
+			}
+		}
+		safety.audit = checker.NewAuditLog(c.auditFile)
+	}
+
+	run := checkRun{
+		loader:       loader,
+		resolver:     resolver,
+		resolveShims: c.opts.resolveShims,
+		mergeResults: c.opts.mergeResults,
+		schedule:     scheduler.Options{Parallelism: c.opts.parallel},
+		runner:       runner,
+		verbose:      c.opts.verbose,
+		capture:      c.opts.captureOutput,
+		prerelease:   c.opts.prerelease,
+		safety:       safety,
+		timeouts:     checkTimeouts{command: c.opts.commandTimeout, detect: c.opts.detectTimeout},
+		anonymize:    c.opts.anonymize || c.cfg.Report.Anonymize,
+		scope:        c.opts.scope,
+	}
+	if c.opts.tags != "" {
+		run.tags = strings.Split(c.opts.tags, ",")
+	}
+	return run, nil
+}
+
+// close releases what the command opened, such as the audit log
+func (c *cli) close() {
+	if c.auditFile != nil {
+		c.auditFile.Close()
+	}
+}
+
+// loadUserConfig reads the user configuration file, if goctor has a config directory
+func loadUserConfig() (config.Config, error) {
+	dirs, err := paths.Default()
+	if err != nil {
+		return config.Config{}, nil
+	}
+	return config.Load(dirs.ConfigFile())
+}
@@ -0,0 +1,245 @@
+package bootstrap
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// DefaultTimeout bounds the whole onboarding flow so an unattended terminal never hangs
+const DefaultTimeout = 2 * time.Minute
+
+// ErrTimedOut is returned when the user does not finish answering before the deadline
+var ErrTimedOut = errors.New("onboarding timed out")
+
+// Suggestion is a tool proposed because a marker file was found in the repository
+type Suggestion struct {
+	Marker string
+	Tool   manifest.ToolDefinition
+}
+
+// rule maps marker files to the tool they indicate
+type rule struct {
+	markers []string
+	tool    func(dir, marker string) manifest.ToolDefinition
+}
+
+// rules are evaluated in order; each tool is suggested at most once
+var rules = []rule{
+	{[]string{"go.mod"}, goTool},
+	{[]string{"Dockerfile", "compose.yaml", "docker-compose.yml", "docker-compose.yaml"}, staticTool(manifest.ToolDefinition{
+		ID:              "docker",
+		Name:            "Docker",
+		Rationale:       "Builds and runs the project's containers",
+		RequiredVersion: ">=24.0",
+		Check: manifest.CheckConfig{
+			Command: []string{"docker", "--version"},
+			Regex:   `(?P<ver>\d+\.\d+\.\d+)`,
+		},
+		Links: map[string]string{"homepage": "https://docs.docker.com/get-docker/"},
+	})},
+	{[]string{"package.json", ".nvmrc", ".node-version"}, staticTool(manifest.ToolDefinition{
+		ID:              "node",
+		Name:            "Node.js",
+		Rationale:       "Runs the project's JavaScript tooling",
+		RequiredVersion: ">=18.0",
+		Check: manifest.CheckConfig{
+			Command: []string{"node", "--version"},
+			Regex:   `v(?P<ver>\d+\.\d+\.\d+)`,
+		},
+		Links: map[string]string{"homepage": "https://nodejs.org/"},
+	})},
+	{[]string{"pyproject.toml", "requirements.txt", ".python-version"}, staticTool(manifest.ToolDefinition{
+		ID:              "python",
+		Name:            "Python",
+		Rationale:       "Runs the project's Python code",
+		RequiredVersion: ">=3.10",
+		Check: manifest.CheckConfig{
+			Command: []string{"python3", "--version"},
+			Regex:   `Python (?P<ver>\d+\.\d+\.\d+)`,
+		},
+		Links: map[string]string{"homepage": "https://www.python.org/downloads/"},
+	})},
+	{[]string{"Cargo.toml"}, staticTool(manifest.ToolDefinition{
+		ID:              "rust",
+		Name:            "Rust",
+		Rationale:       "Builds the project's Rust crates",
+		RequiredVersion: ">=1.70",
+		Check: manifest.CheckConfig{
+			Command: []string{"rustc", "--version"},
+			Regex:   `rustc (?P<ver>\d+\.\d+\.\d+)`,
+		},
+		Links: map[string]string{"homepage": "https://www.rust-lang.org/tools/install"},
+	})},
+	{[]string{"Gemfile"}, staticTool(manifest.ToolDefinition{
+		ID:              "ruby",
+		Name:            "Ruby",
+		Rationale:       "Runs the project's Ruby code",
+		RequiredVersion: ">=3.0",
+		Check: manifest.CheckConfig{
+			Command: []string{"ruby", "--version"},
+			Regex:   `ruby (?P<ver>\d+\.\d+\.\d+)`,
+		},
+		Links: map[string]string{"homepage": "https://www.ruby-lang.org/en/downloads/"},
+	})},
+	{[]string{".git"}, staticTool(manifest.ToolDefinition{
+		ID:              "git",
+		Name:            "Git",
+		Rationale:       "Version control for the repository",
+		RequiredVersion: ">=2.30",
+		Check: manifest.CheckConfig{
+			Command: []string{"git", "--version"},
+			Regex:   `git version (?P<ver>\d+\.\d+\.\d+)`,
+		},
+		Links: map[string]string{"homepage": "https://git-scm.com/downloads"},
+	})},
+}
+
+// goDirectiveRegex matches the go directive of a go.mod file
+var goDirectiveRegex = regexp.MustCompile(`(?m)^go\s+(\d+\.\d+)`)
+
+// goTool suggests Go, requiring at least the version declared in go.mod
+func goTool(dir, marker string) manifest.ToolDefinition {
+	require := ">=1.21"
+	if data, err := os.ReadFile(filepath.Join(dir, marker)); err == nil {
+		if m := goDirectiveRegex.FindSubmatch(data); m != nil {
+			require = ">=" + string(m[1])
+		}
+	}
+
+	return manifest.ToolDefinition{
+		ID:              "go",
+		Name:            "Go",
+		Rationale:       "Builds the project's Go module",
+		RequiredVersion: require,
+		Check: manifest.CheckConfig{
+			Command: []string{"go", "version"},
+			Regex:   `go(?P<ver>\d+\.\d+(\.\d+)?)`,
+		},
+		Links: map[string]string{"homepage": "https://go.dev/dl/"},
+	}
+}
+
+// staticTool returns a rule that always suggests the same tool definition
+func staticTool(tool manifest.ToolDefinition) func(string, string) manifest.ToolDefinition {
+	return func(string, string) manifest.ToolDefinition {
+		return tool
+	}
+}
+
+// Detect returns tool suggestions for the marker files present in dir
+func Detect(dir string) []Suggestion {
+	var suggestions []Suggestion
+
+	for _, r := range rules {
+		for _, marker := range r.markers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err != nil {
+				continue
+			}
+			suggestions = append(suggestions, Suggestion{Marker: marker, Tool: r.tool(dir, marker)})
+			break
+		}
+	}
+
+	return suggestions
+}
+
+// FirstRun returns true the first time it is called for dir, recording the visit under stateDir
+// Onboarding is only offered once per repository, whatever the user answers
+func FirstRun(stateDir, dir string) bool {
+	sum := sha256.Sum256([]byte(dir))
+	marker := filepath.Join(stateDir, hex.EncodeToString(sum[:8]))
+
+	if _, err := os.Stat(marker); err == nil {
+		return false
+	}
+
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return false
+	}
+	if err := os.WriteFile(marker, []byte(dir+"\n"), 0644); err != nil {
+		return false
+	}
+
+	return true
+}
+
+// Ask offers each suggestion and returns the accepted tools
+// Empty answers accept the suggestion; the flow is abandoned with ErrTimedOut once
+// timeout has passed
+func Ask(in io.Reader, out io.Writer, suggestions []Suggestion, timeout time.Duration) ([]manifest.ToolDefinition, error) {
+	answers := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			answers <- scanner.Text()
+		}
+		close(answers)
+	}()
+
+	deadline := time.After(timeout)
+	var accepted []manifest.ToolDefinition
+
+	for _, suggestion := range suggestions {
+		fmt.Fprintf(out, "detected %s → add %s? [Y/n] ", suggestion.Marker, suggestion.Tool.Name)
+
+		select {
+		case answer, ok := <-answers:
+			if !ok {
+				fmt.Fprintln(out)
+				return accepted, nil
+			}
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "", "y", "yes":
+				accepted = append(accepted, suggestion.Tool)
+			}
+		case <-deadline:
+			fmt.Fprintln(out)
+			return nil, ErrTimedOut
+		}
+	}
+
+	return accepted, nil
+}
+
+// Render builds a manifest named name from the accepted tools and returns it as YAML
+func Render(name string, tools []manifest.ToolDefinition) ([]byte, error) {
+	if len(tools) == 0 {
+		return nil, errors.New("no tools selected")
+	}
+
+	m := manifest.Manifest{
+		Meta:  manifest.ManifestMeta{Version: manifest.CurrentVersion, Name: name},
+		Tools: tools,
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("generated manifest is invalid: %v", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by goctor's first-run onboarding; adjust versions and rationales to your project\n")
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(m); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,135 @@
+package bootstrap
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+func touch(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	touch(t, dir, "go.mod", "module example.com/app\n\ngo 1.23.1\n")
+	touch(t, dir, "Dockerfile", "FROM scratch\n")
+	touch(t, dir, "docker-compose.yml", "services: {}\n")
+
+	suggestions := Detect(dir)
+
+	if len(suggestions) != 2 {
+		t.Fatalf("Expected 2 suggestions, got %d", len(suggestions))
+	}
+	if suggestions[0].Tool.ID != "go" || suggestions[0].Tool.RequiredVersion != ">=1.23" {
+		t.Errorf("Expected go requiring the go.mod version, got %s %s", suggestions[0].Tool.ID, suggestions[0].Tool.RequiredVersion)
+	}
+	if suggestions[1].Tool.ID != "docker" || suggestions[1].Marker != "Dockerfile" {
+		t.Errorf("Expected docker once, detected by Dockerfile, got %s (%s)", suggestions[1].Tool.ID, suggestions[1].Marker)
+	}
+}
+
+func TestAsk(t *testing.T) {
+	suggestions := []Suggestion{
+		{Marker: "go.mod", Tool: manifest.ToolDefinition{ID: "go", Name: "Go"}},
+		{Marker: "Dockerfile", Tool: manifest.ToolDefinition{ID: "docker", Name: "Docker"}},
+		{Marker: "package.json", Tool: manifest.ToolDefinition{ID: "node", Name: "Node.js"}},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"accept all by default", "\n\n\n", []string{"go", "docker", "node"}},
+		{"decline some", "y\nn\nyes\n", []string{"go", "node"}},
+		{"input ends early", "y\n", []string{"go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tools, err := Ask(strings.NewReader(tt.input), &out, suggestions, time.Second)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, tool := range tools {
+				got = append(got, tool.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+			if !strings.Contains(out.String(), "detected go.mod → add Go? [Y/n]") {
+				t.Errorf("Expected prompt in output, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestAskTimesOut(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	suggestions := []Suggestion{{Marker: "go.mod", Tool: manifest.ToolDefinition{ID: "go", Name: "Go"}}}
+	_, err := Ask(reader, io.Discard, suggestions, 10*time.Millisecond)
+	if !errors.Is(err, ErrTimedOut) {
+		t.Errorf("Expected ErrTimedOut, got %v", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	touch(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	touch(t, dir, "Gemfile", "source 'https://rubygems.org'\n")
+
+	var tools []manifest.ToolDefinition
+	for _, suggestion := range Detect(dir) {
+		tools = append(tools, suggestion.Tool)
+	}
+
+	data, err := Render("app", tools)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	path := filepath.Join(dir, "tools.yaml")
+	touch(t, dir, "tools.yaml", string(data))
+
+	m, err := manifest.NewLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Generated manifest does not load: %v\n%s", err, data)
+	}
+	if m.Meta.Name != "app" || m.GetTool("go") == nil || m.GetTool("ruby") == nil {
+		t.Errorf("Unexpected generated manifest:\n%s", data)
+	}
+
+	if _, err := Render("app", nil); err == nil {
+		t.Error("Expected error when no tools are selected")
+	}
+}
+
+func TestFirstRun(t *testing.T) {
+	stateDir := filepath.Join(t.TempDir(), "onboarding")
+
+	if !FirstRun(stateDir, "/repo/a") {
+		t.Error("Expected first run for a new repository")
+	}
+	if FirstRun(stateDir, "/repo/a") {
+		t.Error("Expected onboarding to be offered only once per repository")
+	}
+	if !FirstRun(stateDir, "/repo/b") {
+		t.Error("Expected first run for another repository")
+	}
+}
@@ -0,0 +1,153 @@
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Build metadata injected at build time, e.g.:
+//
+//	go build -ldflags "-X github.com/ikorihn/goctor/internal/buildinfo.Version=1.2.0" ./cmd/goctor
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+// Features lists the optional capabilities compiled into this build
+var Features = []string{
+	"format-markdown",
+	"format-html",
+	"informational-tools",
+	"remote-manifest-auth",
+	"link-resolvers",
+	"version-manager-shims",
+	"transition-hooks",
+	"explain",
+	"manifest-v2",
+	"command-templates",
+	"version-schemes",
+	"tiered-requirements",
+	"check-plugins",
+	"sub-checks",
+	"report-diff",
+	"manifest-include",
+	"shell-checks",
+	"self-check",
+	"github-actions",
+	"manifest-bundles",
+	"merge-results",
+	"service-checks",
+	"onboarding",
+	"parallel-checks",
+	"manifest-formats",
+	"package-manager-inventory",
+	"format-jsonl",
+	"xdg-paths",
+	"progress-protocol",
+	"serve",
+	"export",
+	"editor-backend",
+	"i18n",
+	"dev-fixtures",
+	"depends-on",
+	"history",
+	"fallback-commands",
+	"link-check",
+	"color-modes",
+	"upstream-outdated",
+	"report-merge",
+	"strict-semver",
+	"exit-code-policy",
+	"json-file",
+	"ssh-target",
+	"docker-target",
+	"env-interpolation",
+	"human-views",
+	"codeclimate-format",
+	"builtin-tools",
+	"tool-deprecation",
+	"json-errors",
+	"stdin-manifest",
+	"layered-manifests",
+	"watch-mode",
+	"check-durations",
+	"pessimistic-constraints",
+	"tui",
+	"os-checks",
+	"git-config-checks",
+	"sbom",
+	"report-upload",
+	"shell-completion",
+	"flags-after-command",
+	"manifest-discovery",
+	"user-config",
+	"json-schema",
+	"regex-key",
+	"require-from",
+	"verbosity",
+	"capture-output",
+	"lenient-versions",
+	"custom-ca",
+	"skipped-status",
+	"expect-exit-codes",
+	"fleet-server",
+	"dry-run",
+	"allow-prerelease",
+	"exclude-versions",
+	"command-safety",
+	"distro-package-managers",
+	"tool-variants",
+	"report-render",
+	"env-checks",
+	"phase-timeouts",
+	"checksum-pins",
+	"manifest-lint",
+	"structured-logging",
+	"devcontainer-export",
+	"anonymized-reports",
+	"tool-scopes",
+	"merge-modes",
+	"constraint-analysis",
+}
+
+// Info describes the running goctor binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns build metadata, falling back to VCS information embedded by the Go toolchain
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = setting.Value
+				}
+			}
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,7 @@
+//go:build toml
+
+package buildinfo
+
+func init() {
+	Features = append(Features, "toml-manifests")
+}
@@ -0,0 +1,89 @@
+package checker
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"slices"
+
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+// AnonymousHome replaces home directories in the paths of anonymized results
+const AnonymousHome = "~"
+
+// userHomeRegex matches the home directory of any user in a Linux or macOS path
+var userHomeRegex = regexp.MustCompile(`(?:/home|/Users)/[^/\s"':;,]+`)
+
+// sharedHomes are directories under /home and /Users that belong to no person
+var sharedHomes = []string{"linuxbrew", "Shared"}
+
+// SetAnonymize strips usernames from the paths in results, so reports can be shared without
+// identifying who ran them
+func (c *Checker) SetAnonymize(enabled bool) {
+	c.anonymize = enabled
+}
+
+// AnonymizePaths replaces home, the home directory of the user checks ran as, and every
+// /home/NAME or /Users/NAME directory in s with ~
+func AnonymizePaths(s, home string) string {
+	if home != "" && home != "/" {
+		homeRegex := regexp.MustCompile(regexp.QuoteMeta(home) + `(/|$|[\s"':;,])`)
+		s = homeRegex.ReplaceAllString(s, AnonymousHome+"$1")
+	}
+	return userHomeRegex.ReplaceAllStringFunc(s, func(dir string) string {
+		if slices.Contains(sharedHomes, path.Base(dir)) {
+			return dir
+		}
+		return AnonymousHome
+	})
+}
+
+// AnonymizeResult strips usernames from every path a result may hold: where the command was
+// found, error messages, captured output, and trace steps
+func AnonymizeResult(result CheckResult, home string) CheckResult {
+	anonymize := func(s string) string {
+		return AnonymizePaths(s, home)
+	}
+
+	result.CommandPath = anonymize(result.CommandPath)
+	result.ResolvedCommand = anonymize(result.ResolvedCommand)
+	result.ErrorMessage = anonymize(result.ErrorMessage)
+	result.Source = anonymize(result.Source)
+	result.Workspace = anonymize(result.Workspace)
+	result.Output = anonymize(result.Output)
+	result.RawOutput = anonymize(result.RawOutput)
+
+	result.SubChecks = anonymizeSubResults(result.SubChecks, anonymize)
+	result.Variants = anonymizeSubResults(result.Variants, anonymize)
+	if result.Trace != nil {
+		result.Trace = slices.Clone(result.Trace)
+		for i := range result.Trace {
+			result.Trace[i].Detail = anonymize(result.Trace[i].Detail)
+			result.Trace[i].Output = anonymize(result.Trace[i].Output)
+		}
+	}
+	return result
+}
+
+// anonymizeSubResults returns copies of sub-check or variant results with anonymized paths
+func anonymizeSubResults(results []SubCheckResult, anonymize func(string) string) []SubCheckResult {
+	if results == nil {
+		return nil
+	}
+	results = slices.Clone(results)
+	for i := range results {
+		results[i].CommandPath = anonymize(results[i].CommandPath)
+		results[i].ErrorMessage = anonymize(results[i].ErrorMessage)
+	}
+	return results
+}
+
+// homeDir returns the home directory of the user checks run as on platformInfo
+func homeDir(platformInfo platform.PlatformInfo) string {
+	if platformInfo.Home != "" {
+		return platformInfo.Home
+	}
+	home, _ := os.UserHomeDir()
+	return home
+}
@@ -0,0 +1,74 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+func TestAnonymizePaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		home  string
+		want  string
+	}{
+		{name: "own home", input: "/home/alice/.local/bin/node", home: "/home/alice", want: "~/.local/bin/node"},
+		{name: "home outside /home", input: "/var/lib/jenkins/bin/go", home: "/var/lib/jenkins", want: "~/bin/go"},
+		{name: "home prefix of another directory", input: "/var/lib/jenkins2/bin/go", home: "/var/lib/jenkins", want: "/var/lib/jenkins2/bin/go"},
+		{name: "other user on linux", input: "/home/bob/bin/terraform", home: "/home/alice", want: "~/bin/terraform"},
+		{name: "macOS user", input: "open /Users/carol/.nvm/versions/node/v20.11.0/bin/node: permission denied", want: "open ~/.nvm/versions/node/v20.11.0/bin/node: permission denied"},
+		{name: "several paths", input: "node: /home/alice/bin/node, /home/bob/bin/node", want: "node: ~/bin/node, ~/bin/node"},
+		{name: "linuxbrew", input: "/home/linuxbrew/.linuxbrew/bin/go", want: "/home/linuxbrew/.linuxbrew/bin/go"},
+		{name: "shared macOS folder", input: "/Users/Shared/tools/go", want: "/Users/Shared/tools/go"},
+		{name: "system path", input: "/usr/local/bin/go", home: "/home/alice", want: "/usr/local/bin/go"},
+		{name: "root home is kept", input: "/usr/bin/go", home: "/", want: "/usr/bin/go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AnonymizePaths(tt.input, tt.home); got != tt.want {
+				t.Errorf("AnonymizePaths(%q, %q) = %q, want %q", tt.input, tt.home, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnonymizeResult(t *testing.T) {
+	trace := []TraceStep{{Action: TraceLookup, Detail: "node: /home/alice/bin/node"}}
+	subChecks := []SubCheckResult{{Name: "npm", CommandPath: "/home/alice/bin/npm"}}
+	result := CheckResult{
+		ToolID:       "node",
+		CommandPath:  "/home/alice/bin/node",
+		ErrorMessage: "/home/alice/bin/node: exit status 1",
+		Output:       "error in /home/alice/project",
+		SubChecks:    subChecks,
+		Trace:        trace,
+	}
+
+	got := AnonymizeResult(result, "/home/alice")
+	if got.CommandPath != "~/bin/node" || got.ErrorMessage != "~/bin/node: exit status 1" || got.Output != "error in ~/project" {
+		t.Errorf("Paths not anonymized: %+v", got)
+	}
+	if got.SubChecks[0].CommandPath != "~/bin/npm" || got.Trace[0].Detail != "node: ~/bin/node" {
+		t.Errorf("Sub-checks or trace not anonymized: %+v %+v", got.SubChecks, got.Trace)
+	}
+	if subChecks[0].CommandPath != "/home/alice/bin/npm" || trace[0].Detail != "node: /home/alice/bin/node" {
+		t.Error("AnonymizeResult modified the original result")
+	}
+}
+
+func TestAnonymizeHostname(t *testing.T) {
+	info := platform.PlatformInfo{OS: "linux", Architecture: "amd64", Hostname: "alice-laptop"}
+	info.Anonymize()
+
+	if info.Hostname == "alice-laptop" || info.Hostname != platform.AnonymizeHostname("alice-laptop") {
+		t.Errorf("Hostname %q is not the stable pseudonym", info.Hostname)
+	}
+	if other := platform.AnonymizeHostname("bob-laptop"); other == info.Hostname {
+		t.Errorf("Different hostnames share the pseudonym %q", other)
+	}
+	if got := platform.AnonymizeHostname(""); got != "" {
+		t.Errorf("AnonymizeHostname(\"\") = %q, want empty", got)
+	}
+}
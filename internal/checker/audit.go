@@ -0,0 +1,81 @@
+package checker
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditEntry records one command a check or a transition hook executed
+type AuditEntry struct {
+	Time     time.Time    `json:"time"`
+	ToolID   string       `json:"tool_id"`
+	Hook     string       `json:"hook,omitempty"` // on_fail or on_recover for hooks
+	Command  []string     `json:"command"`
+	Workdir  string       `json:"workdir,omitempty"`
+	Target   string       `json:"target"`
+	ExitCode int          `json:"exit_code"`
+	Error    string       `json:"error,omitempty"`
+	Duration Milliseconds `json:"duration_ms"`
+}
+
+// AuditLog appends a JSON line per executed command to a writer; checks running in parallel
+// share it
+type AuditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewAuditLog creates an audit log writing to w
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// Record writes an entry; failing to write it does not fail the check
+func (a *AuditLog) Record(entry AuditEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.w.Write(append(data, '\n'))
+}
+
+// SetAuditLog records every command checks execute, plugins included, in log
+func (c *Checker) SetAuditLog(log *AuditLog) {
+	c.audit = log
+}
+
+// auditCommand records a command that ran for a tool; commands that could not start have
+// exit code -1
+func (c *Checker) auditCommand(toolID string, command []string, workdir string, start time.Time, err error) {
+	if c.audit == nil {
+		return
+	}
+
+	entry := newAuditEntry(toolID, command, start, err)
+	entry.Workdir = workdir
+	entry.Target = c.runner.String()
+	c.audit.Record(entry)
+}
+
+// newAuditEntry returns the entry of a command started at start that returned err
+func newAuditEntry(toolID string, command []string, start time.Time, err error) AuditEntry {
+	entry := AuditEntry{
+		Time:     start,
+		ToolID:   toolID,
+		Command:  command,
+		Duration: Milliseconds(time.Since(start)),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+		entry.ExitCode = -1
+		if code, exited := exitCode(err); exited {
+			entry.ExitCode = code
+		}
+	}
+	return entry
+}
@@ -0,0 +1,105 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+// DefaultResultCacheTTL is how long a cached check result is reused
+const DefaultResultCacheTTL = 10 * time.Minute
+
+// ResultCache stores recent check results on disk so quick checks can skip running tools
+type ResultCache struct {
+	path    string
+	ttl     time.Duration
+	entries map[string]cachedResult
+	now     func() time.Time
+}
+
+// cachedResult is a check result with the time it was produced
+type cachedResult struct {
+	CheckedAt time.Time   `json:"checked_at"`
+	Result    CheckResult `json:"result"`
+}
+
+// LoadResultCache reads the result cache at path; a missing or unreadable cache starts empty
+func LoadResultCache(path string, ttl time.Duration) *ResultCache {
+	cache := &ResultCache{
+		path:    path,
+		ttl:     ttl,
+		entries: make(map[string]cachedResult),
+		now:     time.Now,
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		cache.entries = make(map[string]cachedResult)
+	}
+
+	return cache
+}
+
+// Get returns a fresh cached result for the tool, if any
+func (rc *ResultCache) Get(tool manifest.ToolDefinition) (CheckResult, bool) {
+	entry, ok := rc.entries[resultCacheKey(tool)]
+	if !ok || rc.now().Sub(entry.CheckedAt) > rc.ttl {
+		return CheckResult{}, false
+	}
+	return entry.Result, true
+}
+
+// Put records a tool's result
+func (rc *ResultCache) Put(tool manifest.ToolDefinition, result CheckResult) {
+	rc.entries[resultCacheKey(tool)] = cachedResult{CheckedAt: rc.now(), Result: result}
+}
+
+// Save writes the cache back to disk, dropping expired entries
+func (rc *ResultCache) Save() error {
+	for key, entry := range rc.entries {
+		if rc.now().Sub(entry.CheckedAt) > rc.ttl {
+			delete(rc.entries, key)
+		}
+	}
+
+	data, err := json.Marshal(rc.entries)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(rc.path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(rc.path, data, 0644)
+}
+
+// CheckToolCached returns a fresh cached result for the tool or checks it and caches the result
+func (c *Checker) CheckToolCached(tool manifest.ToolDefinition, platformInfo platform.PlatformInfo, cache *ResultCache) CheckResult {
+	if result, ok := cache.Get(tool); ok {
+		return result
+	}
+
+	result := c.CheckTool(tool, platformInfo)
+	cache.Put(tool, result)
+	return result
+}
+
+// resultCacheKey identifies a tool definition in a directory; editing the tool or
+// moving to a directory with different version manager pins invalidates the entry
+func resultCacheKey(tool manifest.ToolDefinition) string {
+	definition, _ := json.Marshal(tool)
+	wd, _ := os.Getwd()
+
+	sum := sha256.Sum256(append(definition, wd...))
+	return tool.ID + "-" + hex.EncodeToString(sum[:8])
+}
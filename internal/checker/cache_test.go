@@ -0,0 +1,50 @@
+package checker
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+func TestResultCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "results.json")
+	tool := manifest.ToolDefinition{ID: "go", Name: "Go", RequiredVersion: ">=1.22"}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cache := LoadResultCache(path, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	if _, ok := cache.Get(tool); ok {
+		t.Fatal("Expected empty cache")
+	}
+
+	cache.Put(tool, CheckResult{ToolID: "go", Status: StatusOK, ActualVersion: "1.23.0"})
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Unexpected error saving cache: %v", err)
+	}
+
+	reloaded := LoadResultCache(path, time.Minute)
+	reloaded.now = func() time.Time { return now.Add(30 * time.Second) }
+
+	result, ok := reloaded.Get(tool)
+	if !ok {
+		t.Fatal("Expected cached result after reload")
+	}
+	if result.Status != StatusOK || result.ActualVersion != "1.23.0" {
+		t.Errorf("Unexpected cached result: %+v", result)
+	}
+
+	// A changed requirement is a different tool definition
+	changed := tool
+	changed.RequiredVersion = ">=1.24"
+	if _, ok := reloaded.Get(changed); ok {
+		t.Error("Expected cache miss for a changed tool definition")
+	}
+
+	reloaded.now = func() time.Time { return now.Add(2 * time.Minute) }
+	if _, ok := reloaded.Get(tool); ok {
+		t.Error("Expected expired entry to be ignored")
+	}
+}
@@ -0,0 +1,59 @@
+package checker
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxCapturedOutput is the most bytes of command output a result keeps with SetCaptureOutput
+const MaxCapturedOutput = 4096
+
+// truncatedMarker ends captured output that was cut at MaxCapturedOutput
+const truncatedMarker = "\n[truncated]"
+
+var (
+	// escapeRegex matches terminal escape sequences such as colors and cursor movement
+	escapeRegex = regexp.MustCompile(`\x1b(\[[0-9;?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)|[@-Z\\-_])`)
+	// secretRegex matches assignments of secret-looking names, e.g. GITHUB_TOKEN=... or password: ...
+	secretRegex = regexp.MustCompile(`(?i)\b([a-z0-9_.-]*(?:token|secret|password|passwd|api[_-]?key)[a-z0-9_.-]*)(\s*[=:]\s*)\S+`)
+	// bearerRegex matches credentials of authorization headers
+	bearerRegex = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[a-z0-9._~+/=-]+`)
+	// userinfoRegex matches credentials embedded in URLs
+	userinfoRegex = regexp.MustCompile(`(://)[^/\s:@]+:[^/\s@]+@`)
+)
+
+// SetCaptureOutput makes results keep the sanitized output of their version commands, so a
+// report shows what a tool printed when its version could not be parsed elsewhere
+func (c *Checker) SetCaptureOutput(enabled bool) {
+	c.captureOutput = enabled
+}
+
+// SanitizeOutput prepares command output for a report: terminal escape sequences and control
+// characters are removed, secrets in assignments, authorization headers, and URLs are
+// redacted, and the output is cut to max bytes on a character boundary
+func SanitizeOutput(output string, max int) string {
+	output = strings.ToValidUTF8(output, "�")
+	output = escapeRegex.ReplaceAllString(output, "")
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+	output = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' || !unicode.IsControl(r) {
+			return r
+		}
+		return -1
+	}, output)
+
+	output = secretRegex.ReplaceAllString(output, "${1}${2}[REDACTED]")
+	output = bearerRegex.ReplaceAllString(output, "${1} [REDACTED]")
+	output = userinfoRegex.ReplaceAllString(output, "${1}[REDACTED]@")
+
+	if len(output) <= max {
+		return output
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(output[cut]) {
+		cut--
+	}
+	return output[:cut] + truncatedMarker
+}
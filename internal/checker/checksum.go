@@ -0,0 +1,77 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+// verifyChecksum returns an error unless the executable at path has the SHA-256 the tool pins
+// for the platform; tools without a pin there are not verified
+// The executable is hashed before it runs, so a tampered binary is never executed
+func (c *Checker) verifyChecksum(tool manifest.ToolDefinition, platformInfo platform.PlatformInfo, path string, result *CheckResult) error {
+	expected := tool.ExpectedSHA256(platformInfo.OS, platformInfo.Architecture)
+	if expected == "" {
+		return nil
+	}
+
+	start := time.Now()
+	actual, err := c.hashExecutable(tool, platformInfo, path, result)
+	if err != nil {
+		return err
+	}
+	c.trace(result, TraceStep{Action: TraceChecksum, Detail: path + ": " + actual, Duration: Milliseconds(time.Since(start))})
+
+	if actual != expected {
+		return NewCheckError(fmt.Sprintf("checksum mismatch for %s: sha256 is %s, expected %s", path, actual, expected), ErrorTypeChecksum)
+	}
+	return nil
+}
+
+// hashExecutable returns the hex SHA-256 of the file at path, reading it directly on this
+// machine and with sha256sum, or shasum on macOS, on other targets
+func (c *Checker) hashExecutable(tool manifest.ToolDefinition, platformInfo platform.PlatformInfo, path string, result *CheckResult) (string, error) {
+	if isLocal(c.runner) {
+		return fileSHA256(path)
+	}
+
+	command := []string{"sha256sum", path}
+	if platformInfo.OS == "darwin" {
+		command = []string{"shasum", "-a", "256", path}
+	}
+	output, err := c.runTraced(result, command, tool.TimeoutSeconds, "", nil)
+	if err != nil {
+		if isTimeout(err) {
+			return "", err
+		}
+		return "", NewCheckError(fmt.Sprintf("failed to hash %s: %v", path, err), ErrorTypeExecution)
+	}
+
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return "", NewCheckError("failed to hash "+path+": no output", ErrorTypeParsing)
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// fileSHA256 returns the hex SHA-256 of a local file
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", NewCheckError(fmt.Sprintf("failed to hash %s: %v", path, err), ErrorTypeExecution)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", NewCheckError(fmt.Sprintf("failed to hash %s: %v", path, err), ErrorTypeExecution)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,127 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+func TestCheckToolChecksum(t *testing.T) {
+	const (
+		goodSum  = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+		otherSum = "60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752"
+	)
+	runner := &fakeRunner{
+		installed: map[string]string{"terraform": "/usr/local/bin/terraform"},
+		outputs: map[string]string{
+			"sha256sum /usr/local/bin/terraform":     goodSum + "  /usr/local/bin/terraform\n",
+			"shasum -a 256 /usr/local/bin/terraform": strings.ToUpper(goodSum) + "  /usr/local/bin/terraform\n",
+			"terraform version":                      "Terraform v1.7.5",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		sums       map[string]string
+		platform   platform.PlatformInfo
+		wantStatus CheckStatus
+		wantError  string
+		wantRan    bool
+	}{
+		{
+			name:       "no checksum pinned",
+			platform:   platform.PlatformInfo{OS: "linux", Architecture: "amd64"},
+			wantStatus: StatusOK,
+			wantRan:    true,
+		},
+		{
+			name:       "checksum matches",
+			sums:       map[string]string{"linux": goodSum},
+			platform:   platform.PlatformInfo{OS: "linux", Architecture: "amd64"},
+			wantStatus: StatusOK,
+			wantRan:    true,
+		},
+		{
+			name:       "arch entry wins over OS entry",
+			sums:       map[string]string{"linux": otherSum, "linux/amd64": goodSum},
+			platform:   platform.PlatformInfo{OS: "linux", Architecture: "amd64"},
+			wantStatus: StatusOK,
+			wantRan:    true,
+		},
+		{
+			name:       "checksum mismatch is not run",
+			sums:       map[string]string{"linux/amd64": otherSum},
+			platform:   platform.PlatformInfo{OS: "linux", Architecture: "amd64"},
+			wantStatus: StatusError,
+			wantError:  "checksum mismatch for /usr/local/bin/terraform: sha256 is " + goodSum + ", expected " + otherSum,
+		},
+		{
+			name:       "other platforms are not verified",
+			sums:       map[string]string{"linux/arm64": otherSum},
+			platform:   platform.PlatformInfo{OS: "linux", Architecture: "amd64"},
+			wantStatus: StatusOK,
+			wantRan:    true,
+		},
+		{
+			name:       "macOS hashes with shasum",
+			sums:       map[string]string{"darwin": strings.ToUpper(goodSum)},
+			platform:   platform.PlatformInfo{OS: "darwin", Architecture: "arm64"},
+			wantStatus: StatusOK,
+			wantRan:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner.ran = nil
+			tool := manifest.ToolDefinition{
+				ID:              "terraform",
+				Name:            "Terraform",
+				RequiredVersion: ">=1.5",
+				Check:           manifest.CheckConfig{Command: []string{"terraform", "version"}, Regex: `v(?P<ver>\d+\.\d+\.\d+)`},
+				ExpectSHA256:    tt.sums,
+			}
+
+			toolChecker := NewChecker()
+			toolChecker.SetRunner(runner)
+			result := toolChecker.CheckTool(tool, tt.platform)
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Expected %v, got %v (%s)", tt.wantStatus, result.Status, result.ErrorMessage)
+			}
+			if tt.wantError != "" && result.ErrorMessage != tt.wantError {
+				t.Errorf("Expected error %q, got %q", tt.wantError, result.ErrorMessage)
+			}
+			if tt.wantError != "" && result.ErrorType != "checksum" {
+				t.Errorf("Expected error type checksum, got %q", result.ErrorType)
+			}
+			if ran := slices.Contains(runner.ran, "terraform version"); ran != tt.wantRan {
+				t.Errorf("Expected version command run = %v, ran %v", tt.wantRan, runner.ran)
+			}
+		})
+	}
+}
+
+func TestFileSHA256(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool")
+	if err := os.WriteFile(path, []byte("test"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	sum, err := fileSHA256(path)
+	if err != nil {
+		t.Fatalf("fileSHA256() error = %v", err)
+	}
+	if want := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"; sum != want {
+		t.Errorf("fileSHA256() = %s, want %s", sum, want)
+	}
+
+	if _, err := fileSHA256(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
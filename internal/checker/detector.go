@@ -2,8 +2,11 @@ package checker
 
 import (
 	"context"
-	"os/exec"
+	"errors"
+	"fmt"
+	"log/slog"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,18 +18,83 @@ import (
 // Checker handles tool detection and version checking
 type Checker struct {
 	commandTimeout time.Duration
+	// detectTimeout bounds each lookup of an executable, separately from running it
+	detectTimeout time.Duration
+	resolveShims  bool
+	runner        Runner
+	verbose       bool
+	captureOutput bool
+	// allowPrerelease lets prereleases of every tool meet the constraints of their release
+	allowPrerelease bool
+	// restricted refuses checks that run anything but `<tool id> --version`
+	restricted bool
+	audit      *AuditLog
+	// anonymize strips usernames from the paths in results
+	anonymize bool
+	logger    *slog.Logger
+	now       func() time.Time
 }
 
 // NewChecker creates a new tool checker with default configuration
 func NewChecker() *Checker {
 	return &Checker{
 		commandTimeout: 5 * time.Second,
+		detectTimeout:  5 * time.Second,
+		runner:         LocalRunner{},
+		logger:         slog.Default(),
+		now:            time.Now,
 	}
 }
 
 // CheckTool performs a complete check of a tool including detection and version validation
+// The result records how long the check and its sub-checks took
 func (c *Checker) CheckTool(tool manifest.ToolDefinition, platformInfo platform.PlatformInfo) CheckResult {
-	result := CheckResult{
+	start := time.Now()
+	var result CheckResult
+	if len(tool.Variants) > 0 {
+		result = c.checkVariants(tool, platformInfo)
+	} else {
+		result = c.checkTool(tool, platformInfo)
+	}
+
+	// Sub-checks are only meaningful once the main tool has been found
+	if len(tool.Checks) > 0 && result.Status != StatusNotFound {
+		c.checkSubChecks(tool, platformInfo, &result)
+	}
+
+	c.applyDeprecation(tool, &result)
+	if c.captureOutput && result.RawOutput != "" {
+		result.Output = SanitizeOutput(result.RawOutput, MaxCapturedOutput)
+	}
+	if c.anonymize {
+		result = AnonymizeResult(result, homeDir(platformInfo))
+	}
+	result.CheckDuration = Milliseconds(time.Since(start))
+	c.logger.Debug("checked tool", "tool", tool.ID, "status", result.Status.String(), "version", result.ActualVersion,
+		"error", result.ErrorMessage, "duration", time.Duration(result.CheckDuration))
+
+	return result
+}
+
+// applyDeprecation turns the result of a deprecated tool, or one past its sunset date, into a
+// warning while it is still installed; once the tool is gone it passes, whatever its version
+func (c *Checker) applyDeprecation(tool manifest.ToolDefinition, result *CheckResult) {
+	result.Sunset = tool.Sunset
+	if !tool.IsDeprecatedAt(c.now()) {
+		return
+	}
+
+	if result.Status == StatusNotFound || result.Status == StatusMissing {
+		result.Status = StatusOK
+		result.ErrorMessage = ""
+		return
+	}
+	result.Deprecated = true
+}
+
+// newResult returns the result of checking tool before anything has been checked
+func newResult(tool manifest.ToolDefinition, platformInfo platform.PlatformInfo) CheckResult {
+	return CheckResult{
 		ToolID:          tool.ID,
 		ToolName:        tool.Name,
 		RequiredVersion: tool.RequiredVersion,
@@ -36,10 +104,92 @@ func (c *Checker) CheckTool(tool manifest.ToolDefinition, platformInfo platform.
 		ErrorMessage:    "",
 		Links:           tool.Links,
 		Platform:        platformInfo.String(),
+		Informational:   tool.Informational,
+		Severity:        tool.GetSeverity(),
+		Scope:           tool.Scope,
+		InstallHint:     tool.PreferredInstallHint(platformInfo.InstallPreference()),
 	}
+}
 
-	// Check if tool is available and get its path
-	commandPath, available, err := c.getToolPath(tool.CheckCommand()[0])
+// checkTool checks a single tool definition, ignoring its sub-checks
+func (c *Checker) checkTool(tool manifest.ToolDefinition, platformInfo platform.PlatformInfo) CheckResult {
+	result := newResult(tool, platformInfo)
+
+	// The constraint may come from a project file such as .nvmrc
+	if tool.RequireFrom != nil {
+		required, err := ResolveRequirement(tool)
+		if err != nil {
+			result.AddError(err.Error())
+			return result
+		}
+		tool.RequiredVersion = required
+		result.RequiredVersion = required
+		c.trace(&result, TraceStep{Action: TraceRequire, Detail: tool.RequireFrom.File + ": " + required})
+	}
+
+	// OS checks read the platform's own version instead of running a tool
+	if tool.IsOS() {
+		c.checkOS(tool, platformInfo, &result)
+		return result
+	}
+
+	// Git config checks match configuration values instead of a version
+	if tool.IsGitConfig() {
+		c.checkGitConfig(tool, &result)
+		return result
+	}
+
+	// Env checks read a variable of goctor's own environment
+	if tool.IsEnv() {
+		c.checkEnv(tool, &result)
+		return result
+	}
+
+	// Expand platform variables such as {{ .brew_prefix }} in the check command
+	// Built-in service probes run verbatim since their arguments are Go templates for the tool itself
+	if tool.Check.Probe != "" {
+		tool.Check.Command = tool.CheckCommand()
+		tool.Check.Probe = ""
+	} else {
+		candidates := tool.CheckCommands()
+		for i, candidate := range candidates {
+			command, err := expandCommand(candidate, platformInfo.TemplateVars())
+			if err != nil {
+				result.AddError(err.Error())
+				return result
+			}
+			candidates[i] = command
+		}
+		tool.Check.Command = candidates[0]
+		tool.Check.Fallbacks = candidates[1:]
+	}
+
+	// Restricted mode refuses manifest commands before looking any of them up
+	if c.restricted {
+		if err := restrictedCheck(tool); err != nil {
+			result.Status = StatusError
+			result.ErrorMessage = err.Error()
+			return result
+		}
+	}
+
+	// Plugins implement their own detection and report status directly
+	if tool.IsPlugin() {
+		if !isLocal(c.runner) {
+			result.Status = StatusError
+			result.ErrorMessage = "check plugins cannot run on target " + c.runner.String()
+			return result
+		}
+		return c.checkPlugin(tool, platformInfo, result)
+	}
+
+	// Check if tool is available and get its path, trying fallbacks in order
+	// A lookup that hangs is an error of its own rather than a missing tool
+	commandPath, available, err := c.resolveCommand(&tool, &result)
+	if isTimeout(err) {
+		result.Fail(err.Error(), err)
+		return result
+	}
 	if err != nil || !available {
 		result.Status = StatusNotFound
 		if err != nil {
@@ -50,77 +200,206 @@ func (c *Checker) CheckTool(tool manifest.ToolDefinition, platformInfo platform.
 		return result
 	}
 
+	if len(tool.Check.Fallbacks) > 0 {
+		result.ResolvedCommand = strings.Join(tool.CheckCommand(), " ")
+	}
 	result.CommandPath = commandPath
+	result.ManagedBy = DetectVersionManager(commandPath)
+
+	// A pinned executable must match its checksum before it is run
+	if err := c.verifyChecksum(tool, platformInfo, commandPath, &result); err != nil {
+		result.Fail(err.Error(), err)
+		return result
+	}
+
+	// Run the check through the version manager's resolution for the current directory
+	if tool.Check.Shell {
+		tool.Check.Command = platformInfo.ShellCommand(tool.CheckCommand()[0])
+	} else if c.resolveShims && result.ManagedBy != "" {
+		tool.Check.Command = managerCommand(commandPath, tool.CheckCommand(), c.isInstalled)
+	}
+
+	// Services are healthy when their command succeeds, whatever version is installed
+	if tool.IsService() {
+		c.checkServiceHealth(tool, &result)
+		return result
+	}
 
 	// Extract version from command output
-	version, err := c.extractVersion(tool)
+	version, group, rawOutput, err := c.extractVersion(tool, &result)
+	result.RawOutput = rawOutput
 	if err != nil {
-		result.Status = StatusError
-		result.ErrorMessage = err.Error()
+		result.Fail(err.Error(), err)
 		return result
 	}
 
 	result.ActualVersion = version
+	result.VersionGroup = group
+	c.applyRequirements(tool, &result)
 
-	// Parse and validate version against requirements
-	if err := c.validateVersion(version, tool.RequiredVersion); err != nil {
+	return result
+}
+
+// applyRequirements sets the result status by validating ActualVersion against the
+// tool's minimum and recommended requirements
+func (c *Checker) applyRequirements(tool manifest.ToolDefinition, result *CheckResult) {
+	// Strict tools must report a full MAJOR.MINOR.PATCH version
+	if tool.Check.StrictSemver {
+		if _, err := semver.ParseStrictVersion(result.ActualVersion); err != nil {
+			result.Status = StatusError
+			result.ErrorMessage = "invalid actual version format: " + err.Error()
+			return
+		}
+	}
+
+	// Excluded versions fail whatever the constraint says
+	if excluded := excludedBy(tool, result.ActualVersion); excluded != nil {
 		result.Status = StatusOutdated
-		result.ErrorMessage = err.Error()
-	} else {
+		result.Blocked = excluded
+		result.ErrorMessage = fmt.Sprintf("version %s is blocked by exclude_versions %q", result.ActualVersion, excluded.Versions)
+		if excluded.Reason != "" {
+			result.ErrorMessage += ": " + excluded.Reason
+		}
+		return
+	}
+
+	// Informational tools without a requirement only report the detected version
+	if tool.Informational && tool.RequiredVersion == "" {
 		result.Status = StatusOK
+		return
+	}
+
+	// Parse and validate version against requirements
+	if err := c.validateVersion(result.ActualVersion, tool.RequiredVersion, tool.Check.VersionScheme, tool.AllowPrerelease); err != nil {
+		result.ErrorMessage = err.Error()
+		if checkErr, ok := err.(CheckError); ok && checkErr.Type == ErrorTypeVersionMismatch {
+			result.Status = StatusOutdated
+			result.ConstraintFailure = checkErr.Mismatch
+		} else {
+			result.Status = StatusError
+		}
+		return
 	}
 
 	// Determine final status
 	result.DetermineStatus()
 
-	return result
+	// Meeting the minimum but not the recommended tier is a non-blocking warning
+	if tool.RecommendedVersion != "" {
+		result.RecommendedVersion = tool.RecommendedVersion
+		if err := c.validateVersion(result.ActualVersion, tool.RecommendedVersion, tool.Check.VersionScheme, tool.AllowPrerelease); err != nil {
+			result.BelowRecommended = true
+		}
+	}
 }
 
-// getToolPath checks if a command is available and returns its path
+// excludedBy returns the first exclude_versions entry of the tool that version matches, or nil
+func excludedBy(tool manifest.ToolDefinition, version string) *manifest.ExcludedVersion {
+	scheme, err := semver.GetScheme(tool.Check.VersionScheme)
+	if err != nil {
+		return nil
+	}
+	for _, excluded := range tool.ExcludeVersions {
+		if matched, err := scheme.Satisfies(version, excluded.Versions); err == nil && matched {
+			return &excluded
+		}
+	}
+	return nil
+}
+
+// getToolPath checks if a command is available on the target and returns its path
+// Remote lookups that run out of time would look like missing commands, so a lookup past the
+// detection timeout fails with ErrorTypeTimeout whatever the runner returned
 func (c *Checker) getToolPath(command string) (string, bool, error) {
-	// Use `which` command to check if tool exists and get its path
-	ctx, cancel := context.WithTimeout(context.Background(), c.commandTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), c.detectTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "command", "-v", command)
-	output, err := cmd.Output()
+	path, available, err := c.runner.LookPath(ctx, command)
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", false, NewCheckError(fmt.Sprintf("looking up %s timed out after %s", command, c.detectTimeout), ErrorTypeTimeout)
+	}
+	return path, available, err
+}
+
+// isInstalled returns true if an executable is available on the target
+func (c *Checker) isInstalled(command string) bool {
+	_, available, _ := c.getToolPath(command)
+	return available
+}
 
-	if err != nil {
-		// Check if it's a timeout or other error
-		if ctx.Err() == context.DeadlineExceeded {
-			return "", false, ctx.Err()
+// resolveCommand finds the first of the tool's check commands whose executable is available
+// and makes it the tool's check command
+func (c *Checker) resolveCommand(tool *manifest.ToolDefinition, result *CheckResult) (string, bool, error) {
+	for _, candidate := range tool.CheckCommands() {
+		// Shell scripts are detected by the first program they run
+		executable := candidate[0]
+		if tool.Check.Shell {
+			executable = firstWord(executable)
+		}
+
+		start := time.Now()
+		commandPath, available, err := c.getToolPath(executable)
+		if err != nil {
+			return "", false, err
+		}
+		found := commandPath
+		if !available {
+			found = "not found"
+		}
+		c.trace(result, TraceStep{Action: TraceLookup, Detail: executable + ": " + found, Duration: Milliseconds(time.Since(start))})
+		if available {
+			tool.Check.Command = candidate
+			return commandPath, true, nil
 		}
-		// Command not found is expected for missing tools
-		return "", false, nil
 	}
 
-	path := strings.TrimSpace(string(output))
-	return path, true, nil
+	return "", false, nil
 }
 
 // extractVersion runs the tool's check command and extracts version using regex
-func (c *Checker) extractVersion(tool manifest.ToolDefinition) (string, error) {
+// The capture group the version came from and the raw command output are returned alongside
+// the version for diagnostics
+// Each step is traced on result in verbose mode
+func (c *Checker) extractVersion(tool manifest.ToolDefinition, result *CheckResult) (string, string, string, error) {
 	if len(tool.CheckCommand()) == 0 {
-		return "", NewCheckError("no check command specified", ErrorTypeConfiguration)
+		return "", "", "", NewCheckError("no check command specified", ErrorTypeConfiguration)
 	}
 
-	// Execute the version check command
-	output, err := c.runCommand(tool.CheckCommand(), tool.TimeoutSeconds)
-	if err != nil {
-		return "", NewCheckError("failed to run version command: "+err.Error(), ErrorTypeExecution)
+	// Execute the version check command; tools such as `java -version` may exit non-zero, and
+	// expect_exit_codes lets their output be parsed anyway
+	output, err := c.runTraced(result, tool.CheckCommand(), tool.TimeoutSeconds, tool.Check.Workdir, tool.Check.Env)
+	if code, exited := exitCode(err); (err != nil && !exited) || !tool.Check.ExpectsExitCode(code) {
+		message := "failed to run version command: exit status 0"
+		if err != nil {
+			message = "failed to run version command: " + err.Error()
+		}
+		if len(tool.Check.ExpectExitCodes) > 0 {
+			codes := make([]string, len(tool.Check.ExpectExitCodes))
+			for i, expected := range tool.Check.ExpectExitCodes {
+				codes[i] = strconv.Itoa(expected)
+			}
+			message += " (expected exit codes " + strings.Join(codes, ", ") + ")"
+		}
+		failure := NewCheckError(message, ErrorTypeExecution)
+		if isTimeout(err) {
+			failure.Type = ErrorTypeTimeout
+		}
+		return "", "", output, failure
 	}
 
 	// Extract version using regex
-	version, err := c.parseVersionFromOutput(output, tool.VersionRegex())
+	version, group, err := c.parseVersionFromOutput(output, tool.VersionRegex(), tool.VersionRegexKey())
+	c.traceMatch(result, output, tool.VersionRegex(), version, group, err)
 	if err != nil {
-		return "", NewCheckError("failed to parse version: "+err.Error(), ErrorTypeParsing)
+		return "", "", output, NewCheckError("failed to parse version: "+err.Error(), ErrorTypeParsing)
 	}
 
-	return version, nil
+	return version, group, output, nil
 }
 
-// runCommand executes a command with timeout and returns its output
-func (c *Checker) runCommand(command []string, timeoutSec int) (string, error) {
+// runCommand executes a command for a tool with timeout and returns its output
+// workdir and env, when set, override the working directory and add environment variables
+func (c *Checker) runCommand(toolID string, command []string, timeoutSec int, workdir string, env map[string]string) (string, error) {
 	timeout := c.commandTimeout
 	if timeoutSec > 0 {
 		timeout = time.Duration(timeoutSec) * time.Second
@@ -129,51 +408,91 @@ func (c *Checker) runCommand(command []string, timeoutSec int) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
-	output, err := cmd.CombinedOutput()
-
+	start := time.Now()
+	output, err := c.runner.Run(ctx, command, workdir, env)
+	c.auditCommand(toolID, command, workdir, start, err)
 	if err != nil {
+		if _, ok := err.(CheckError); ok {
+			return output, err
+		}
 		if ctx.Err() == context.DeadlineExceeded {
-			return "", NewCheckError("command timed out", ErrorTypeTimeout)
+			return output, NewCheckError(fmt.Sprintf("command timed out after %s", timeout), ErrorTypeTimeout)
 		}
-		return "", NewCheckError("command failed: "+err.Error(), ErrorTypeExecution)
+		failure := NewCheckError("command failed: "+err.Error(), ErrorTypeExecution)
+		failure.Cause = err
+		return output, failure
 	}
 
-	return string(output), nil
+	return output, nil
+}
+
+// exitCode returns the exit code of a command that ran and failed, and whether there was one;
+// runners pass on the exit errors of the commands they run
+func exitCode(err error) (int, bool) {
+	var exited interface{ ExitCode() int }
+	if errors.As(err, &exited) {
+		return exited.ExitCode(), true
+	}
+	return 0, false
+}
+
+// firstWord returns the first whitespace-separated word of a shell script
+func firstWord(script string) string {
+	fields := strings.Fields(script)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
 }
 
 // parseVersionFromOutput extracts version string using regex with named capture groups
-func (c *Checker) parseVersionFromOutput(output, regexPattern string) (string, error) {
+// The version is taken from the group named regexKey when it is set; otherwise from a group
+// named ver, version, or v, then the first group. The name of that group is returned too
+func (c *Checker) parseVersionFromOutput(output, regexPattern, regexKey string) (string, string, error) {
 	if regexPattern == "" {
-		return "", NewCheckError("empty regex pattern", ErrorTypeConfiguration)
+		return "", "", NewCheckError("empty regex pattern", ErrorTypeConfiguration)
 	}
 
 	// Compile regex
 	regex, err := regexp.Compile(regexPattern)
 	if err != nil {
-		return "", NewCheckError("invalid regex: "+err.Error(), ErrorTypeConfiguration)
+		return "", "", NewCheckError("invalid regex: "+err.Error(), ErrorTypeConfiguration)
+	}
+
+	// A configured key must name a group of the regex
+	keyIndex := -1
+	if regexKey != "" {
+		keyIndex = regex.SubexpIndex(regexKey)
+		if keyIndex < 0 {
+			return "", "", NewCheckError(fmt.Sprintf("regex has no capture group named %q (regex_key)", regexKey), ErrorTypeConfiguration)
+		}
 	}
 
 	// Find matches
 	matches := regex.FindStringSubmatch(output)
 	if matches == nil {
-		return "", NewCheckError("no version found in output", ErrorTypeParsing)
+		return "", "", NewCheckError("no version found in output", ErrorTypeParsing)
 	}
 
 	// Get subexp names to find named capture groups
 	names := regex.SubexpNames()
 
-	// Look for common capture group names
-	versionGroupNames := []string{"ver", "version", "v"}
+	if keyIndex >= 0 {
+		if matches[keyIndex] == "" {
+			return "", "", NewCheckError(fmt.Sprintf("capture group %q matched no version", regexKey), ErrorTypeParsing)
+		}
+		return strings.TrimSpace(matches[keyIndex]), regexKey, nil
+	}
 
+	// Look for common capture group names
 	for i, name := range names {
 		if name != "" && i < len(matches) {
 			// Check if this is a version-related capture group
 			lowerName := strings.ToLower(name)
-			for _, versionName := range versionGroupNames {
+			for _, versionName := range manifest.VersionGroupNames {
 				if lowerName == versionName {
 					if matches[i] != "" {
-						return strings.TrimSpace(matches[i]), nil
+						return strings.TrimSpace(matches[i]), name, nil
 					}
 				}
 			}
@@ -182,14 +501,17 @@ func (c *Checker) parseVersionFromOutput(output, regexPattern string) (string, e
 
 	// If no named group found, try the first capture group
 	if len(matches) > 1 && matches[1] != "" {
-		return strings.TrimSpace(matches[1]), nil
+		return strings.TrimSpace(matches[1]), names[1], nil
 	}
 
-	return "", NewCheckError("no version captured by regex", ErrorTypeParsing)
+	return "", "", NewCheckError("no version captured by regex", ErrorTypeParsing)
 }
 
 // validateVersion checks if the actual version satisfies the required version constraint
-func (c *Checker) validateVersion(actualVersion, requiredVersion string) error {
+// using the given version scheme (semver when empty)
+// With allowPrerelease, or when the checker allows prereleases of every tool, a prerelease
+// satisfies the constraints its release satisfies
+func (c *Checker) validateVersion(actualVersion, requiredVersion, schemeName string, allowPrerelease bool) error {
 	if actualVersion == "" {
 		return NewCheckError("no actual version to validate", ErrorTypeParsing)
 	}
@@ -198,31 +520,70 @@ func (c *Checker) validateVersion(actualVersion, requiredVersion string) error {
 		return NewCheckError("no required version specified", ErrorTypeConfiguration)
 	}
 
-	// Parse the actual version
-	actual, err := semver.ParseVersion(actualVersion)
+	scheme, err := semver.GetScheme(schemeName)
 	if err != nil {
+		return NewCheckError(err.Error(), ErrorTypeConfiguration)
+	}
+
+	// Parse the actual version
+	if err := scheme.Validate(actualVersion); err != nil {
 		return NewCheckError("invalid actual version format: "+err.Error(), ErrorTypeParsing)
 	}
 
-	// Parse the required version constraint
-	constraint, err := semver.ParseConstraint(requiredVersion)
+	// Check if actual version satisfies constraint
+	var satisfied bool
+	if allowPrerelease || c.allowPrerelease {
+		satisfied, err = semver.SatisfiesAllowingPrerelease(scheme, actualVersion, requiredVersion)
+	} else {
+		satisfied, err = scheme.Satisfies(actualVersion, requiredVersion)
+	}
 	if err != nil {
 		return NewCheckError("invalid required version constraint: "+err.Error(), ErrorTypeConfiguration)
 	}
-
-	// Check if actual version satisfies constraint
-	if !constraint.IsSatisfiedBy(actual) {
-		return NewCheckError("version does not satisfy constraint", ErrorTypeVersionMismatch)
+	if !satisfied {
+		checkErr := NewCheckError("version does not satisfy constraint", ErrorTypeVersionMismatch)
+		if mismatch, err := semver.ExplainMismatch(scheme, actualVersion, requiredVersion); err == nil && mismatch != nil {
+			checkErr.Message += ": " + mismatch.String()
+			checkErr.Mismatch = mismatch
+		}
+		return checkErr
 	}
 
 	return nil
 }
 
-// SetTimeout sets the default command timeout
+// SetTimeout sets the default timeout of version commands; a tool's timeout_sec overrides it
 func (c *Checker) SetTimeout(timeout time.Duration) {
 	c.commandTimeout = timeout
 }
 
+// SetDetectTimeout sets how long looking up each executable may take
+func (c *Checker) SetDetectTimeout(timeout time.Duration) {
+	c.detectTimeout = timeout
+}
+
+// SetLogger sets the logger each step of a check is logged to at debug level; slog's default
+// logger when the checker was created is used otherwise
+func (c *Checker) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
+// SetAllowPrerelease makes prereleases of every tool meet the constraints their release meets,
+// as allow_prerelease does for one tool
+func (c *Checker) SetAllowPrerelease(enabled bool) {
+	c.allowPrerelease = enabled
+}
+
+// SetRunner makes the checker run commands through runner instead of on this machine
+func (c *Checker) SetRunner(runner Runner) {
+	c.runner = runner
+}
+
+// SetResolveShims enables running checks through the owning version manager (asdf, mise, ...)
+func (c *Checker) SetResolveShims(enabled bool) {
+	c.resolveShims = enabled
+}
+
 // CheckMultipleTools runs checks for multiple tools concurrently
 func (c *Checker) CheckMultipleTools(tools []manifest.ToolDefinition, platformInfo platform.PlatformInfo) []CheckResult {
 	results := make([]CheckResult, len(tools))
@@ -234,4 +595,3 @@ func (c *Checker) CheckMultipleTools(tools []manifest.ToolDefinition, platformIn
 
 	return results
 }
-
@@ -0,0 +1,562 @@
+package checker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+func TestCheckToolExecutionOptions(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	workdir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workdir, "VERSION"), []byte("3.1.4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		check          manifest.CheckConfig
+		expectedStatus CheckStatus
+		expectedError  string
+	}{
+		{
+			name: "shell pipeline",
+			check: manifest.CheckConfig{
+				Command: []string{"echo 'tool 1.2.3 (build 7)' | cut -d' ' -f2"},
+				Shell:   true,
+			},
+			expectedStatus: StatusOK,
+		},
+		{
+			name: "workdir",
+			check: manifest.CheckConfig{
+				Command: []string{"cat", "VERSION"},
+				Workdir: workdir,
+			},
+			expectedStatus: StatusOK,
+		},
+		{
+			name: "env",
+			check: manifest.CheckConfig{
+				Command: []string{"echo $TOOL_VERSION"},
+				Shell:   true,
+				Env:     map[string]string{"TOOL_VERSION": "2.0.0"},
+			},
+			expectedStatus: StatusOK,
+		},
+		{
+			name: "shell program not installed",
+			check: manifest.CheckConfig{
+				Command: []string{"goctor-nonexistent-tool --version | head -1"},
+				Shell:   true,
+			},
+			expectedStatus: StatusNotFound,
+		},
+		{
+			name: "missing workdir",
+			check: manifest.CheckConfig{
+				Command: []string{"cat", "VERSION"},
+				Workdir: filepath.Join(workdir, "missing"),
+			},
+			expectedStatus: StatusError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check.Regex = `(?P<ver>\d+\.\d+\.\d+)`
+			tool := manifest.ToolDefinition{
+				ID:              "tool",
+				Name:            "Tool",
+				RequiredVersion: ">=1.0",
+				Check:           tt.check,
+			}
+
+			result := NewChecker().CheckTool(tool, platformInfo)
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Expected status %v, got %v (%s)", tt.expectedStatus, result.Status, result.ErrorMessage)
+			}
+		})
+	}
+}
+
+// hangingRunner is a target whose lookups hang until they are cancelled, like a remote shell
+// that stopped answering; it then reports the command as not found
+type hangingRunner struct {
+	fakeRunner
+}
+
+func (h *hangingRunner) LookPath(ctx context.Context, name string) (string, bool, error) {
+	<-ctx.Done()
+	return "", false, nil
+}
+
+func TestCheckToolTimeouts(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+	tool := manifest.ToolDefinition{
+		ID:              "tool",
+		Name:            "Tool",
+		RequiredVersion: ">=1.0",
+		Check:           manifest.CheckConfig{Regex: `(?P<ver>\d+\.\d+\.\d+)`},
+	}
+
+	t.Run("version command", func(t *testing.T) {
+		// The shell's child keeps the output open after the shell is killed
+		tool := tool
+		tool.Check.Command = []string{"echo tool 1.2.3; sleep 10"}
+		tool.Check.Shell = true
+
+		toolChecker := NewChecker()
+		toolChecker.SetTimeout(200 * time.Millisecond)
+		start := time.Now()
+		result := toolChecker.CheckTool(tool, platformInfo)
+
+		if result.Status != StatusError || result.ErrorType != "timeout" {
+			t.Errorf("Expected a timeout error, got %v %q (%s)", result.Status, result.ErrorType, result.ErrorMessage)
+		}
+		if !strings.Contains(result.ErrorMessage, "timed out after 200ms") {
+			t.Errorf("Expected the error to name the budget, got %q", result.ErrorMessage)
+		}
+		if !strings.Contains(result.RawOutput, "tool 1.2.3") {
+			t.Errorf("Expected the partial output to be kept, got %q", result.RawOutput)
+		}
+		if took := time.Since(start); took > 3*time.Second {
+			t.Errorf("Expected the check to end soon after its budget, took %s", took)
+		}
+	})
+
+	t.Run("lookup", func(t *testing.T) {
+		tool := tool
+		tool.Check.Command = []string{"tool", "--version"}
+
+		toolChecker := NewChecker()
+		toolChecker.SetRunner(&hangingRunner{})
+		toolChecker.SetDetectTimeout(50 * time.Millisecond)
+		result := toolChecker.CheckTool(tool, platformInfo)
+
+		if result.Status != StatusError || result.ErrorType != "timeout" {
+			t.Errorf("Expected a timeout error, got %v %q (%s)", result.Status, result.ErrorType, result.ErrorMessage)
+		}
+		if result.ErrorMessage != "looking up tool timed out after 50ms" {
+			t.Errorf("Unexpected error %q", result.ErrorMessage)
+		}
+	})
+}
+
+func TestCheckToolFallbacks(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	tests := []struct {
+		name             string
+		check            manifest.CheckConfig
+		expectedStatus   CheckStatus
+		expectedResolved string
+	}{
+		{
+			name: "primary command installed",
+			check: manifest.CheckConfig{
+				Command:   []string{"echo", "fd 9.0.0"},
+				Fallbacks: [][]string{{"goctor-nonexistent-fdfind", "--version"}},
+			},
+			expectedStatus:   StatusOK,
+			expectedResolved: "echo fd 9.0.0",
+		},
+		{
+			name: "fallback installed",
+			check: manifest.CheckConfig{
+				Command:   []string{"goctor-nonexistent-fd", "--version"},
+				Fallbacks: [][]string{{"goctor-nonexistent-fdfind", "--version"}, {"echo", "fdfind 8.7.0"}},
+			},
+			expectedStatus:   StatusOK,
+			expectedResolved: "echo fdfind 8.7.0",
+		},
+		{
+			name: "shell fallback installed",
+			check: manifest.CheckConfig{
+				Command:   []string{"goctor-nonexistent-fd --version | head -1"},
+				Shell:     true,
+				Fallbacks: [][]string{{"echo 'fdfind 8.7.0' | cut -d' ' -f2"}},
+			},
+			expectedStatus:   StatusOK,
+			expectedResolved: "echo 'fdfind 8.7.0' | cut -d' ' -f2",
+		},
+		{
+			name: "nothing installed",
+			check: manifest.CheckConfig{
+				Command:   []string{"goctor-nonexistent-fd", "--version"},
+				Fallbacks: [][]string{{"goctor-nonexistent-fdfind", "--version"}},
+			},
+			expectedStatus: StatusNotFound,
+		},
+		{
+			name: "no fallbacks configured",
+			check: manifest.CheckConfig{
+				Command: []string{"echo", "fd 9.0.0"},
+			},
+			expectedStatus: StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check.Regex = `(?P<ver>\d+\.\d+\.\d+)`
+			tool := manifest.ToolDefinition{
+				ID:              "fd",
+				Name:            "fd",
+				RequiredVersion: ">=8.0",
+				Check:           tt.check,
+			}
+
+			result := NewChecker().CheckTool(tool, platformInfo)
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Expected status %v, got %v (%s)", tt.expectedStatus, result.Status, result.ErrorMessage)
+			}
+			if result.ResolvedCommand != tt.expectedResolved {
+				t.Errorf("Expected resolved command %q, got %q", tt.expectedResolved, result.ResolvedCommand)
+			}
+		})
+	}
+}
+
+func TestCheckToolStrictSemver(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	tests := []struct {
+		name           string
+		output         string
+		strict         bool
+		expectedStatus CheckStatus
+	}{
+		{name: "full version", output: "tool 1.4.2", strict: true, expectedStatus: StatusOK},
+		{name: "truncated version", output: "tool 1.4", strict: true, expectedStatus: StatusError},
+		{name: "major only", output: "tool 2", strict: true, expectedStatus: StatusError},
+		{name: "truncated version without strict mode", output: "tool 1.4", expectedStatus: StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := manifest.ToolDefinition{
+				ID:              "tool",
+				Name:            "Tool",
+				RequiredVersion: ">=1.0",
+				Check: manifest.CheckConfig{
+					Command:      []string{"echo", tt.output},
+					Regex:        `(?P<ver>\d+(\.\d+)*)`,
+					StrictSemver: tt.strict,
+				},
+			}
+
+			result := NewChecker().CheckTool(tool, platformInfo)
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Expected status %v, got %v (%s)", tt.expectedStatus, result.Status, result.ErrorMessage)
+			}
+			if tt.expectedStatus == StatusError && !strings.Contains(result.ErrorMessage, "MAJOR.MINOR.PATCH") {
+				t.Errorf("Expected the error to explain the strict format, got %q", result.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestCheckToolExpectExitCodes(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	tests := []struct {
+		name           string
+		script         string
+		expect         []int
+		expectedStatus CheckStatus
+		expectedError  string
+	}{
+		{name: "non-zero exit rejected by default", script: "echo 'openjdk version \"17.0.9\"' >&2; exit 1", expectedStatus: StatusError, expectedError: "exit status 1"},
+		{name: "expected non-zero exit with stderr output", script: "echo 'openjdk version \"17.0.9\"' >&2; exit 1", expect: []int{0, 1}, expectedStatus: StatusOK},
+		{name: "unexpected exit code", script: "echo 'tool 1.2.3'; exit 2", expect: []int{0, 1}, expectedStatus: StatusError, expectedError: "exit status 2 (expected exit codes 0, 1)"},
+		{name: "zero exit not expected", script: "echo 'tool 1.2.3'", expect: []int{1}, expectedStatus: StatusError, expectedError: "exit status 0 (expected exit codes 1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := manifest.ToolDefinition{
+				ID:              "tool",
+				Name:            "Tool",
+				RequiredVersion: ">=1.0",
+				Check: manifest.CheckConfig{
+					Command:         []string{tt.script},
+					Shell:           true,
+					Regex:           `(?P<ver>\d+\.\d+\.\d+)`,
+					ExpectExitCodes: tt.expect,
+				},
+			}
+
+			result := NewChecker().CheckTool(tool, platformInfo)
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Expected status %v, got %v (%s)", tt.expectedStatus, result.Status, result.ErrorMessage)
+			}
+			if !strings.Contains(result.ErrorMessage, tt.expectedError) {
+				t.Errorf("Expected an error containing %q, got %q", tt.expectedError, result.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestCheckToolAllowPrerelease(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	tests := []struct {
+		name           string
+		version        string
+		toolAllows     bool
+		checkerAllows  bool
+		expectedStatus CheckStatus
+	}{
+		{name: "prerelease rejected by default", version: "1.22.0-rc.1", expectedStatus: StatusOutdated},
+		{name: "prerelease allowed by the tool", version: "1.22.0-rc.1", toolAllows: true, expectedStatus: StatusOK},
+		{name: "prerelease allowed for every tool", version: "1.22.0-rc.1", checkerAllows: true, expectedStatus: StatusOK},
+		{name: "prerelease of an older version", version: "1.21.0-rc.1", toolAllows: true, expectedStatus: StatusOutdated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := manifest.ToolDefinition{
+				ID:              "go",
+				Name:            "Go",
+				RequiredVersion: ">=1.22.0",
+				AllowPrerelease: tt.toolAllows,
+				Check: manifest.CheckConfig{
+					Command: []string{"echo", "go version go" + tt.version},
+					Regex:   `go(?P<ver>\d+\.\d+\.\d+(-[0-9A-Za-z.]+)?)`,
+				},
+			}
+
+			toolChecker := NewChecker()
+			toolChecker.SetAllowPrerelease(tt.checkerAllows)
+			result := toolChecker.CheckTool(tool, platformInfo)
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Expected status %v, got %v (%s)", tt.expectedStatus, result.Status, result.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestCheckToolExcludeVersions(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+	excluded := []manifest.ExcludedVersion{
+		{Versions: "1.21.5", Reason: "breaks go.sum"},
+		{Versions: ">=2.0.0-rc <2.0.0"},
+	}
+
+	tests := []struct {
+		version        string
+		expectedStatus CheckStatus
+		expectedError  string
+	}{
+		{version: "1.21.4", expectedStatus: StatusOK},
+		{version: "1.21.5", expectedStatus: StatusOutdated, expectedError: `version 1.21.5 is blocked by exclude_versions "1.21.5": breaks go.sum`},
+		{version: "2.0.0-rc.1", expectedStatus: StatusOutdated, expectedError: `version 2.0.0-rc.1 is blocked by exclude_versions ">=2.0.0-rc <2.0.0"`},
+		{version: "2.0.0", expectedStatus: StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			tool := manifest.ToolDefinition{
+				ID:              "go",
+				Name:            "Go",
+				RequiredVersion: ">=1.21",
+				ExcludeVersions: excluded,
+				Check: manifest.CheckConfig{
+					Command: []string{"echo", "go version go" + tt.version},
+					Regex:   `go(?P<ver>\d+\.\d+\.\d+(-[0-9A-Za-z.]+)?)`,
+				},
+			}
+
+			result := NewChecker().CheckTool(tool, platformInfo)
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Expected status %v, got %v (%s)", tt.expectedStatus, result.Status, result.ErrorMessage)
+			}
+			if result.ErrorMessage != tt.expectedError {
+				t.Errorf("Expected error %q, got %q", tt.expectedError, result.ErrorMessage)
+			}
+			if blocked := result.Blocked != nil; blocked != (tt.expectedError != "") {
+				t.Errorf("Expected blocked = %t, got %+v", tt.expectedError != "", result.Blocked)
+			}
+		})
+	}
+}
+
+func TestCheckToolExplainsConstraintFailure(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	tool := manifest.ToolDefinition{
+		ID:              "go",
+		Name:            "Go",
+		RequiredVersion: ">=1.22 <2",
+		Check: manifest.CheckConfig{
+			Command: []string{"echo", "go version go1.21.3 linux/amd64"},
+			Regex:   `go(?P<ver>\d+\.\d+\.\d+)`,
+		},
+	}
+
+	result := NewChecker().CheckTool(tool, platformInfo)
+	if result.Status != StatusOutdated {
+		t.Fatalf("Expected outdated, got %v (%s)", result.Status, result.ErrorMessage)
+	}
+
+	expected := "version does not satisfy constraint: 1.21.3 fails '>=1.22.0' of '>=1.22.0 <2.0.0'"
+	if result.ErrorMessage != expected {
+		t.Errorf("Expected %q, got %q", expected, result.ErrorMessage)
+	}
+
+	failure := result.ConstraintFailure
+	if failure == nil {
+		t.Fatal("Expected a structured constraint failure")
+	}
+	if failure.Version != "1.21.3" || failure.Failed != ">=1.22.0" || len(failure.Constraints) != 2 {
+		t.Errorf("Unexpected constraint failure: %+v", failure)
+	}
+}
+
+func TestCheckToolDeprecation(t *testing.T) {
+	runner := &fakeRunner{
+		installed: map[string]string{"docker-compose": "/usr/local/bin/docker-compose"},
+		outputs:   map[string]string{"docker-compose --version": "docker-compose version 1.29.2, build 5becea4c"},
+	}
+	toolChecker := NewChecker()
+	toolChecker.SetRunner(runner)
+	toolChecker.now = func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC) }
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	compose := func(command string) manifest.ToolDefinition {
+		return manifest.ToolDefinition{
+			ID:              "docker-compose",
+			Name:            "Docker Compose v1",
+			RequiredVersion: ">=1.29",
+			Check:           manifest.CheckConfig{Command: []string{command, "--version"}, Regex: `version (?P<ver>\d+\.\d+\.\d+)`},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		command        string
+		deprecated     bool
+		sunset         string
+		wantStatus     CheckStatus
+		wantDeprecated bool
+	}{
+		{name: "not deprecated", command: "docker-compose", wantStatus: StatusOK},
+		{name: "deprecated and installed", command: "docker-compose", deprecated: true, wantStatus: StatusOK, wantDeprecated: true},
+		{name: "deprecated and removed", command: "compose-v1", deprecated: true, wantStatus: StatusOK},
+		{name: "before sunset", command: "docker-compose", sunset: "2025-07-01", wantStatus: StatusOK},
+		{name: "before sunset and removed", command: "compose-v1", sunset: "2025-07-01", wantStatus: StatusNotFound},
+		{name: "past sunset", command: "docker-compose", sunset: "2025-01-01", wantStatus: StatusOK, wantDeprecated: true},
+		{name: "sunset day", command: "docker-compose", sunset: "2025-06-01", wantStatus: StatusOK, wantDeprecated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := compose(tt.command)
+			tool.Deprecated = tt.deprecated
+			tool.Sunset = tt.sunset
+
+			result := toolChecker.CheckTool(tool, platformInfo)
+			if result.Status != tt.wantStatus || result.Deprecated != tt.wantDeprecated {
+				t.Errorf("Expected %v (deprecated %v), got %v (deprecated %v): %s",
+					tt.wantStatus, tt.wantDeprecated, result.Status, result.Deprecated, result.ErrorMessage)
+			}
+			if result.Sunset != tt.sunset {
+				t.Errorf("Expected sunset %q, got %q", tt.sunset, result.Sunset)
+			}
+		})
+	}
+}
+
+func TestParseVersionFromOutputRegexKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		regex       string
+		regexKey    string
+		wantVersion string
+		wantGroup   string
+		wantError   string
+	}{
+		{name: "ver group", regex: `go(?P<ver>\d+\.\d+\.\d+)`, wantVersion: "1.22.3", wantGroup: "ver"},
+		{name: "version group", regex: `go(?P<version>\d+\.\d+\.\d+)`, wantVersion: "1.22.3", wantGroup: "version"},
+		{name: "first group", regex: `go(?P<release>\d+\.\d+\.\d+)`, wantVersion: "1.22.3", wantGroup: "release"},
+		{name: "regex key", regex: `(?P<ver>go)(?P<release>\d+\.\d+\.\d+)`, regexKey: "release", wantVersion: "1.22.3", wantGroup: "release"},
+		{name: "regex key missing", regex: `go(?P<ver>\d+\.\d+\.\d+)`, regexKey: "release", wantError: `no capture group named "release"`},
+		{name: "regex key empty", regex: `go(?P<ver>\d+\.\d+\.\d+)(?P<suffix>-rc)?`, regexKey: "suffix", wantError: `capture group "suffix" matched no version`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, group, err := NewChecker().parseVersionFromOutput("go version go1.22.3 linux/amd64", tt.regex, tt.regexKey)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if version != tt.wantVersion || group != tt.wantGroup {
+				t.Errorf("Got version %q from group %q, want %q from %q", version, group, tt.wantVersion, tt.wantGroup)
+			}
+		})
+	}
+}
+
+func TestResolveRequirement(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".nvmrc"), []byte("v20.11.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".python-version"), []byte("system\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		require     string
+		requireFrom *manifest.RequireFrom
+		want        string
+		expectError bool
+	}{
+		{name: "no require_from", require: ">=18", want: ">=18"},
+		{name: "file", requireFrom: &manifest.RequireFrom{File: ".nvmrc"}, want: ">=20.11.0"},
+		{name: "file over require", require: ">=18", requireFrom: &manifest.RequireFrom{File: ".nvmrc", Match: manifest.MatchCompatible}, want: "~20.11.0"},
+		{name: "missing file falls back to require", require: ">=18", requireFrom: &manifest.RequireFrom{File: ".node-version"}, want: ">=18"},
+		{name: "missing file without require", requireFrom: &manifest.RequireFrom{File: ".node-version"}, expectError: true},
+		{name: "unreadable version", require: ">=3.10", requireFrom: &manifest.RequireFrom{File: ".python-version"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := manifest.ToolDefinition{
+				ID:              "node",
+				RequiredVersion: tt.require,
+				RequireFrom:     tt.requireFrom,
+				Check:           manifest.CheckConfig{Workdir: dir},
+			}
+
+			got, err := ResolveRequirement(tool)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got constraint %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveRequirement() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
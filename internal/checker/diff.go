@@ -0,0 +1,146 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ikorihn/goctor/internal/semver"
+)
+
+// Version change directions
+const (
+	VersionUpgraded   = "upgraded"
+	VersionDowngraded = "downgraded"
+	VersionChanged    = "changed"
+)
+
+// ToolChange describes how a single tool differs between two reports
+type ToolChange struct {
+	ToolID        string      `json:"id"`
+	ToolName      string      `json:"name"`
+	OldVersion    string      `json:"old_version,omitempty"`
+	NewVersion    string      `json:"new_version,omitempty"`
+	VersionChange string      `json:"version_change,omitempty"`
+	OldStatus     CheckStatus `json:"old_status,omitempty"`
+	NewStatus     CheckStatus `json:"new_status,omitempty"`
+}
+
+// StatusChanged returns true if the tool's status differs between the reports
+func (tc ToolChange) StatusChanged() bool {
+	return tc.OldStatus != tc.NewStatus
+}
+
+// ReportDiff describes the differences between two environment reports
+type ReportDiff struct {
+	Added   []ToolChange `json:"added"`
+	Removed []ToolChange `json:"removed"`
+	Changed []ToolChange `json:"changed"`
+}
+
+// IsEmpty returns true if the reports describe the same environment
+func (rd ReportDiff) IsEmpty() bool {
+	return len(rd.Added) == 0 && len(rd.Removed) == 0 && len(rd.Changed) == 0
+}
+
+// LoadEnvironmentReport reads an environment report written by `goctor doctor --json`
+func LoadEnvironmentReport(path string) (*EnvironmentReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %v", err)
+	}
+
+	return ParseEnvironmentReport(data, path)
+}
+
+// ParseEnvironmentReport decodes an environment report; name identifies it in errors
+func ParseEnvironmentReport(data []byte, name string) (*EnvironmentReport, error) {
+	var report EnvironmentReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %v", name, err)
+	}
+
+	if report.SchemaVersion != ReportSchemaVersion {
+		return nil, fmt.Errorf("unsupported report schema version %d in %s", report.SchemaVersion, name)
+	}
+
+	return &report, nil
+}
+
+// DiffReports compares two reports; tools are matched by ID and listed in report order
+func DiffReports(oldReport, newReport EnvironmentReport) ReportDiff {
+	diff := ReportDiff{
+		Added:   []ToolChange{},
+		Removed: []ToolChange{},
+		Changed: []ToolChange{},
+	}
+
+	oldItems := make(map[string]CheckResult, len(oldReport.Items))
+	for _, item := range oldReport.Items {
+		oldItems[item.ToolID] = item
+	}
+
+	newItems := make(map[string]CheckResult, len(newReport.Items))
+	for _, item := range newReport.Items {
+		newItems[item.ToolID] = item
+	}
+
+	for _, item := range oldReport.Items {
+		if _, exists := newItems[item.ToolID]; !exists {
+			diff.Removed = append(diff.Removed, ToolChange{
+				ToolID:     item.ToolID,
+				ToolName:   item.ToolName,
+				OldVersion: item.ActualVersion,
+				OldStatus:  item.Status,
+			})
+		}
+	}
+
+	for _, item := range newReport.Items {
+		old, exists := oldItems[item.ToolID]
+		if !exists {
+			diff.Added = append(diff.Added, ToolChange{
+				ToolID:     item.ToolID,
+				ToolName:   item.ToolName,
+				NewVersion: item.ActualVersion,
+				NewStatus:  item.Status,
+			})
+			continue
+		}
+
+		change := ToolChange{
+			ToolID:        item.ToolID,
+			ToolName:      item.ToolName,
+			OldVersion:    old.ActualVersion,
+			NewVersion:    item.ActualVersion,
+			VersionChange: compareReportedVersions(old.ActualVersion, item.ActualVersion),
+			OldStatus:     old.Status,
+			NewStatus:     item.Status,
+		}
+
+		if change.VersionChange != "" || change.StatusChanged() {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+
+	return diff
+}
+
+// compareReportedVersions classifies a version change, or returns "" if unchanged
+func compareReportedVersions(oldVersion, newVersion string) string {
+	if oldVersion == newVersion {
+		return ""
+	}
+	if oldVersion == "" || newVersion == "" {
+		return VersionChanged
+	}
+
+	switch comparison := semver.CompareVersions(newVersion, oldVersion); {
+	case comparison > 0:
+		return VersionUpgraded
+	case comparison < 0:
+		return VersionDowngraded
+	default:
+		return VersionChanged
+	}
+}
@@ -0,0 +1,90 @@
+package checker
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiffReports(t *testing.T) {
+	oldReport := EnvironmentReport{
+		Items: []CheckResult{
+			{ToolID: "go", ToolName: "Go", ActualVersion: "1.21.3", Status: StatusOutdated},
+			{ToolID: "node", ToolName: "Node.js", ActualVersion: "20.1.0", Status: StatusOK},
+			{ToolID: "git", ToolName: "Git", ActualVersion: "2.42.0", Status: StatusOK},
+			{ToolID: "docker", ToolName: "Docker", Status: StatusNotFound},
+		},
+	}
+	newReport := EnvironmentReport{
+		Items: []CheckResult{
+			{ToolID: "go", ToolName: "Go", ActualVersion: "1.22.1", Status: StatusOK},
+			{ToolID: "node", ToolName: "Node.js", ActualVersion: "18.17.0", Status: StatusOK},
+			{ToolID: "git", ToolName: "Git", ActualVersion: "2.42.0", Status: StatusOK},
+			{ToolID: "terraform", ToolName: "Terraform", ActualVersion: "1.6.0", Status: StatusOK},
+		},
+	}
+
+	diff := DiffReports(oldReport, newReport)
+
+	if len(diff.Added) != 1 || diff.Added[0].ToolID != "terraform" {
+		t.Errorf("Expected terraform to be added, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].ToolID != "docker" {
+		t.Errorf("Expected docker to be removed, got %+v", diff.Removed)
+	}
+	if len(diff.Changed) != 2 {
+		t.Fatalf("Expected 2 changed tools, got %+v", diff.Changed)
+	}
+
+	goChange := diff.Changed[0]
+	if goChange.ToolID != "go" || goChange.VersionChange != VersionUpgraded || !goChange.StatusChanged() {
+		t.Errorf("Expected go to be upgraded with a status change, got %+v", goChange)
+	}
+
+	nodeChange := diff.Changed[1]
+	if nodeChange.ToolID != "node" || nodeChange.VersionChange != VersionDowngraded || nodeChange.StatusChanged() {
+		t.Errorf("Expected node to be downgraded without a status change, got %+v", nodeChange)
+	}
+
+	if !DiffReports(oldReport, oldReport).IsEmpty() {
+		t.Error("Expected identical reports to produce an empty diff")
+	}
+}
+
+func TestLoadEnvironmentReport(t *testing.T) {
+	report := EnvironmentReport{
+		SchemaVersion: ReportSchemaVersion,
+		Items:         []CheckResult{{ToolID: "go", ToolName: "Go", Status: StatusOutdated}},
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Failed to marshal report: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	loaded, err := LoadEnvironmentReport(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded.Items[0].Status != StatusOutdated {
+		t.Errorf("Expected status to round-trip, got %v", loaded.Items[0].Status)
+	}
+
+	// Reports written before statuses were serialized as strings use numbers
+	legacy := []byte(`{"schema_version": 1, "items": [{"id": "go", "name": "Go", "status": 3}]}`)
+	if err := os.WriteFile(path, legacy, 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	loaded, err = LoadEnvironmentReport(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded.Items[0].Status != StatusOutdated {
+		t.Errorf("Expected numeric status to decode, got %v", loaded.Items[0].Status)
+	}
+}
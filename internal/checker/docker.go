@@ -0,0 +1,115 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// dockerFailed is the exit status docker uses for its own errors
+const dockerFailed = 125
+
+// DockerRunner runs commands in a Docker container, or in a throwaway container of an image
+// A reference naming an existing container is checked with `docker exec`; anything else is
+// treated as an image, pulled if needed, and every command runs in a fresh `docker run --rm`
+// with the image's entrypoint cleared
+type DockerRunner struct {
+	Reference string
+
+	resolve   sync.Once
+	container bool
+	err       error
+}
+
+// NewDockerRunner creates a runner for a container name or ID, or an image reference
+func NewDockerRunner(reference string) (*DockerRunner, error) {
+	if reference == "" || strings.ContainsAny(reference, " \t\n") {
+		return nil, fmt.Errorf("invalid docker target %q", reference)
+	}
+	// docker would read such a reference as an option, e.g. --privileged or -v=/:/host
+	if strings.HasPrefix(reference, "-") {
+		return nil, fmt.Errorf("invalid docker target %q: the image or container cannot start with -", reference)
+	}
+	return &DockerRunner{Reference: reference}, nil
+}
+
+// LookPath asks the container's shell for the executable's path
+func (r *DockerRunner) LookPath(ctx context.Context, name string) (string, bool, error) {
+	output, err := r.Run(ctx, []string{"/bin/sh", "-c", "command -v " + shellQuote(name)}, "", nil)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return strings.TrimSpace(output), true, nil
+}
+
+// Run executes the command with docker exec or docker run
+func (r *DockerRunner) Run(ctx context.Context, command []string, workdir string, env map[string]string) (string, error) {
+	if err := r.prepare(ctx); err != nil {
+		return "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "docker", r.args(command, workdir, env)...)
+	cmd.WaitDelay = waitDelay
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == dockerFailed {
+		return string(output), fmt.Errorf("docker failed: %v", runnerFailure(err, string(output)))
+	}
+	return string(output), err
+}
+
+func (r *DockerRunner) String() string {
+	return "docker://" + r.Reference
+}
+
+// args builds the docker command line for one check command
+func (r *DockerRunner) args(command []string, workdir string, env map[string]string) []string {
+	args := []string{"exec"}
+	if !r.container {
+		args = []string{"run", "--rm", "--entrypoint", ""}
+	}
+
+	if workdir != "" {
+		args = append(args, "--workdir", workdir)
+	}
+
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "--env", name+"="+env[name])
+	}
+
+	args = append(args, r.Reference)
+	return append(args, command...)
+}
+
+// prepare decides once whether the reference is a container or an image, pulling the image
+// if it is not present yet so the pull does not count against a check's timeout
+func (r *DockerRunner) prepare(ctx context.Context) error {
+	r.resolve.Do(func() {
+		if err := exec.CommandContext(ctx, "docker", "container", "inspect", r.Reference).Run(); err == nil {
+			r.container = true
+			return
+		}
+		if err := exec.CommandContext(ctx, "docker", "image", "inspect", r.Reference).Run(); err == nil {
+			return
+		}
+
+		output, err := exec.CommandContext(ctx, "docker", "pull", r.Reference).CombinedOutput()
+		if err != nil {
+			r.err = fmt.Errorf("%s is neither a container nor a pullable image: %v", r.Reference, runnerFailure(err, string(output)))
+		}
+	})
+	return r.err
+}
@@ -0,0 +1,81 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// MaskedValue replaces the value of a sensitive environment variable in results and traces
+const MaskedValue = "********"
+
+// checkEnv checks an environment variable of goctor's own environment: it must be set, match
+// check.regex when one is given, and name an existing directory or file when check.exists says
+// so. The value is reported as the actual version unless the check is sensitive, and never
+// appears in an error message
+func (c *Checker) checkEnv(tool manifest.ToolDefinition, result *CheckResult) {
+	name := tool.Check.Var
+	if !isLocal(c.runner) {
+		result.Status = StatusError
+		result.ErrorMessage = "env checks cannot run on target " + c.runner.String()
+		return
+	}
+
+	value, set := os.LookupEnv(name)
+	if !set || value == "" {
+		c.trace(result, TraceStep{Action: TraceEnv, Detail: name + " is not set"})
+		result.Status = StatusMissing
+		result.ErrorMessage = name + " is not set"
+		return
+	}
+
+	shown := value
+	if tool.Check.Sensitive {
+		shown = MaskedValue
+	}
+	c.trace(result, TraceStep{Action: TraceEnv, Detail: name + "=" + shown})
+	result.ActualVersion = shown
+
+	if tool.Check.Regex != "" {
+		re, err := regexp.Compile(tool.Check.Regex)
+		if err != nil {
+			result.AddError("malformed regex: " + err.Error())
+			return
+		}
+		if !re.MatchString(value) {
+			result.AddError(fmt.Sprintf("%s does not match %s", name, tool.Check.Regex))
+			return
+		}
+	}
+
+	if tool.Check.Exists != "" {
+		if err := checkEnvPath(name, value, tool.Check.Exists); err != nil {
+			result.AddError(err.Error())
+			return
+		}
+	}
+
+	result.Status = StatusOK
+}
+
+// checkEnvPath returns an error unless value is an existing directory or file, as kind says
+func checkEnvPath(name, value, kind string) error {
+	noun := "file"
+	if kind == manifest.EnvExistsDir {
+		noun = "directory"
+	}
+
+	info, err := os.Stat(value)
+	if err != nil {
+		return fmt.Errorf("%s does not point to an existing %s", name, noun)
+	}
+	if kind == manifest.EnvExistsDir && !info.IsDir() {
+		return fmt.Errorf("%s does not point to a directory", name)
+	}
+	if kind == manifest.EnvExistsFile && info.IsDir() {
+		return fmt.Errorf("%s points to a directory, not a file", name)
+	}
+	return nil
+}
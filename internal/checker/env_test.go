@@ -0,0 +1,141 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+func TestCheckToolWithEnv(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "credentials")
+	if err := os.WriteFile(file, []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		env            map[string]string
+		check          manifest.CheckConfig
+		expectedStatus CheckStatus
+		expectedActual string
+		expectedError  string
+	}{
+		{
+			name:           "set",
+			env:            map[string]string{"GOCTOR_TEST_PROFILE": "dev"},
+			check:          manifest.CheckConfig{Var: "GOCTOR_TEST_PROFILE"},
+			expectedStatus: StatusOK,
+			expectedActual: "dev",
+		},
+		{
+			name:           "not set",
+			check:          manifest.CheckConfig{Var: "GOCTOR_TEST_PROFILE"},
+			expectedStatus: StatusMissing,
+			expectedError:  "GOCTOR_TEST_PROFILE is not set",
+		},
+		{
+			name:           "empty",
+			env:            map[string]string{"GOCTOR_TEST_PROFILE": ""},
+			check:          manifest.CheckConfig{Var: "GOCTOR_TEST_PROFILE"},
+			expectedStatus: StatusMissing,
+			expectedError:  "GOCTOR_TEST_PROFILE is not set",
+		},
+		{
+			name:           "sensitive",
+			env:            map[string]string{"GOCTOR_TEST_TOKEN": "s3cr3t"},
+			check:          manifest.CheckConfig{Var: "GOCTOR_TEST_TOKEN", Sensitive: true},
+			expectedStatus: StatusOK,
+			expectedActual: MaskedValue,
+		},
+		{
+			name:           "matching value",
+			env:            map[string]string{"GOCTOR_TEST_GOPRIVATE": "github.com/acme,gitlab.com/other"},
+			check:          manifest.CheckConfig{Var: "GOCTOR_TEST_GOPRIVATE", Regex: `(^|,)github\.com/acme(/|,|$)`},
+			expectedStatus: StatusOK,
+			expectedActual: "github.com/acme,gitlab.com/other",
+		},
+		{
+			name:           "sensitive value not matching",
+			env:            map[string]string{"GOCTOR_TEST_TOKEN": "s3cr3t"},
+			check:          manifest.CheckConfig{Var: "GOCTOR_TEST_TOKEN", Regex: `^ghp_`, Sensitive: true},
+			expectedStatus: StatusError,
+			expectedActual: MaskedValue,
+			expectedError:  "GOCTOR_TEST_TOKEN does not match ^ghp_",
+		},
+		{
+			name:           "existing dir",
+			env:            map[string]string{"GOCTOR_TEST_HOME": dir},
+			check:          manifest.CheckConfig{Var: "GOCTOR_TEST_HOME", Exists: manifest.EnvExistsDir},
+			expectedStatus: StatusOK,
+			expectedActual: dir,
+		},
+		{
+			name:           "file instead of dir",
+			env:            map[string]string{"GOCTOR_TEST_HOME": file},
+			check:          manifest.CheckConfig{Var: "GOCTOR_TEST_HOME", Exists: manifest.EnvExistsDir},
+			expectedStatus: StatusError,
+			expectedActual: file,
+			expectedError:  "GOCTOR_TEST_HOME does not point to a directory",
+		},
+		{
+			name:           "missing file",
+			env:            map[string]string{"GOCTOR_TEST_CREDENTIALS": filepath.Join(dir, "missing")},
+			check:          manifest.CheckConfig{Var: "GOCTOR_TEST_CREDENTIALS", Exists: manifest.EnvExistsFile, Sensitive: true},
+			expectedStatus: StatusError,
+			expectedActual: MaskedValue,
+			expectedError:  "GOCTOR_TEST_CREDENTIALS does not point to an existing file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			tt.check.Type = manifest.CheckTypeEnv
+			tool := manifest.ToolDefinition{ID: "env", Name: "Environment", Check: tt.check}
+
+			toolChecker := NewChecker()
+			toolChecker.SetVerbose(true)
+			result := toolChecker.CheckTool(tool, platform.PlatformInfo{OS: "linux", Architecture: "amd64"})
+
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Expected status %v, got %v (%s)", tt.expectedStatus, result.Status, result.ErrorMessage)
+			}
+			if result.ActualVersion != tt.expectedActual {
+				t.Errorf("Expected actual %q, got %q", tt.expectedActual, result.ActualVersion)
+			}
+			if result.ErrorMessage != tt.expectedError {
+				t.Errorf("Expected error %q, got %q", tt.expectedError, result.ErrorMessage)
+			}
+
+			// A sensitive value appears nowhere in the result
+			if tt.check.Sensitive {
+				for _, step := range result.Trace {
+					if strings.Contains(step.String(), tt.env[tt.check.Var]) {
+						t.Errorf("Trace step %q reveals the value", step)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCheckToolWithEnvOnTarget(t *testing.T) {
+	t.Setenv("GOCTOR_TEST_PROFILE", "dev")
+	tool := manifest.ToolDefinition{ID: "env", Name: "Environment", Check: manifest.CheckConfig{Type: manifest.CheckTypeEnv, Var: "GOCTOR_TEST_PROFILE"}}
+
+	toolChecker := NewChecker()
+	toolChecker.SetRunner(&fakeRunner{})
+	result := toolChecker.CheckTool(tool, platform.PlatformInfo{OS: "linux", Architecture: "amd64"})
+
+	if result.Status != StatusError || !strings.HasPrefix(result.ErrorMessage, "env checks cannot run on target") {
+		t.Errorf("Expected env checks to refuse a target, got %v (%s)", result.Status, result.ErrorMessage)
+	}
+}
@@ -0,0 +1,91 @@
+package checker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Exit code policies
+const (
+	ExitPolicySimple   = "simple"
+	ExitPolicyGranular = "granular"
+)
+
+// ExitPolicies lists the named exit code policies
+var ExitPolicies = []string{ExitPolicySimple, ExitPolicyGranular}
+
+// ExitPolicy maps each class of failure to the exit code of a run
+// When tools fail in several ways the most severe class decides: errors, then missing
+// tools, then outdated ones. The zero value exits 0 whatever the report says.
+type ExitPolicy struct {
+	Outdated int
+	Missing  int
+	Error    int
+}
+
+// DefaultExitPolicy exits 1 for any failure
+var DefaultExitPolicy = ExitPolicy{Outdated: 1, Missing: 1, Error: 1}
+
+// GranularExitPolicy gives every failure class its own exit code
+var GranularExitPolicy = ExitPolicy{Outdated: 1, Missing: 2, Error: 3}
+
+// ParseExitPolicy parses a named policy (simple or granular) or a list of class=code
+// pairs such as "outdated=0,missing=2,error=3"; classes left out of a list exit 1
+func ParseExitPolicy(s string) (ExitPolicy, error) {
+	switch s {
+	case "", ExitPolicySimple:
+		return DefaultExitPolicy, nil
+	case ExitPolicyGranular:
+		return GranularExitPolicy, nil
+	}
+
+	policy := DefaultExitPolicy
+	for _, pair := range strings.Split(s, ",") {
+		class, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return ExitPolicy{}, fmt.Errorf("invalid exit code policy %q (expected %s, or CLASS=CODE pairs)", s, strings.Join(ExitPolicies, ", "))
+		}
+
+		code, err := strconv.Atoi(value)
+		if err != nil || code < 0 || code > 125 {
+			return ExitPolicy{}, fmt.Errorf("invalid exit code %q for %s (must be 0-125)", value, class)
+		}
+
+		switch class {
+		case "outdated":
+			policy.Outdated = code
+		case "missing":
+			policy.Missing = code
+		case "error":
+			policy.Error = code
+		default:
+			return ExitPolicy{}, fmt.Errorf("unknown failure class %q (expected outdated, missing, or error)", class)
+		}
+	}
+
+	return policy, nil
+}
+
+// FailureCode returns the exit code for a run that failed before producing a report,
+// which is the error code, or 1 if the policy would hide the failure
+func (p ExitPolicy) FailureCode() int {
+	if p.Error == 0 {
+		return 1
+	}
+	return p.Error
+}
+
+// exitCode returns the code for the most severe failure class in the summary
+func (p ExitPolicy) exitCode(summary CheckSummary) int {
+	switch {
+	case summary.Errors > 0:
+		return p.Error
+	case summary.Missing > 0:
+		return p.Missing
+	case summary.Outdated > 0:
+		return p.Outdated
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,75 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseExitPolicy(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ExitPolicy
+		wantErr  string
+	}{
+		{input: "", expected: DefaultExitPolicy},
+		{input: "simple", expected: DefaultExitPolicy},
+		{input: "granular", expected: GranularExitPolicy},
+		{input: "outdated=0,missing=2,error=3", expected: ExitPolicy{Outdated: 0, Missing: 2, Error: 3}},
+		{input: "missing=4", expected: ExitPolicy{Outdated: 1, Missing: 4, Error: 1}},
+		{input: "strict", wantErr: "invalid exit code policy"},
+		{input: "missing=two", wantErr: "invalid exit code"},
+		{input: "error=300", wantErr: "must be 0-125"},
+		{input: "skipped=2", wantErr: "unknown failure class"},
+	}
+
+	for _, tt := range tests {
+		policy, err := ParseExitPolicy(tt.input)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseExitPolicy(%q): expected error containing %q, got %v", tt.input, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseExitPolicy(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if policy != tt.expected {
+			t.Errorf("ParseExitPolicy(%q) = %+v, want %+v", tt.input, policy, tt.expected)
+		}
+	}
+}
+
+func TestGetExitCodeWithPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []CheckStatus
+		simple   int
+		granular int
+	}{
+		{"all ok", []CheckStatus{StatusOK, StatusOK}, 0, 0},
+		{"outdated", []CheckStatus{StatusOK, StatusOutdated}, 1, 1},
+		{"missing beats outdated", []CheckStatus{StatusOutdated, StatusMissing}, 1, 2},
+		{"error beats missing", []CheckStatus{StatusMissing, StatusError, StatusOutdated}, 1, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]CheckResult, len(tt.statuses))
+			for i, status := range tt.statuses {
+				items[i] = CheckResult{ToolID: "tool", ToolName: "Tool", Status: status}
+			}
+			report := NewEnvironmentReport(nil, "tools.yaml", items)
+
+			if code := report.GetExitCode(); code != tt.simple {
+				t.Errorf("Expected default exit code %d, got %d", tt.simple, code)
+			}
+			if code := report.GetExitCode(GranularExitPolicy); code != tt.granular {
+				t.Errorf("Expected granular exit code %d, got %d", tt.granular, code)
+			}
+			if code := report.GetExitCode(ExitPolicy{}); code != 0 {
+				t.Errorf("Expected the zero policy to exit 0, got %d", code)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// checkGitConfig reads each key of a git-config check with `git config --get` and matches its
+// value against the key's regex. Every key is reported as a sub-check; an unset key is missing,
+// a value that does not match is an error, and the first failing key decides the tool's status
+func (c *Checker) checkGitConfig(tool manifest.ToolDefinition, result *CheckResult) {
+	commandPath, available, err := c.getToolPath("git")
+	if isTimeout(err) {
+		result.Fail(err.Error(), err)
+		return
+	}
+	if err != nil || !available {
+		result.Status = StatusNotFound
+		result.ErrorMessage = "git not found"
+		if err != nil {
+			result.ErrorMessage = err.Error()
+		}
+		return
+	}
+	result.CommandPath = commandPath
+
+	var failed []string
+	result.Status = StatusOK
+	for _, key := range tool.GitConfigKeys() {
+		sub := c.checkGitConfigKey(tool, key)
+		result.SubChecks = append(result.SubChecks, sub)
+
+		if sub.Status != StatusOK {
+			failed = append(failed, fmt.Sprintf("%s (%s)", key, sub.ErrorMessage))
+			if result.Status == StatusOK {
+				result.Status = sub.Status
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		result.ErrorMessage = "git config: " + strings.Join(failed, ", ")
+	}
+}
+
+// checkGitConfigKey reads one git configuration value and matches it against its regex
+// `git config --get` fails without output when the key is unset
+func (c *Checker) checkGitConfigKey(tool manifest.ToolDefinition, key string) SubCheckResult {
+	pattern := tool.Check.GitConfig[key]
+	sub := SubCheckResult{Name: key, RequiredVersion: pattern}
+
+	output, err := c.runCommand(tool.ID, []string{"git", "config", "--get", key}, tool.TimeoutSeconds, tool.Check.Workdir, tool.Check.Env)
+	if err != nil {
+		if strings.TrimSpace(output) == "" {
+			sub.Status = StatusMissing
+			sub.ErrorMessage = "not set"
+			return sub
+		}
+		sub.Status = StatusError
+		sub.ErrorMessage = lastLine(output)
+		return sub
+	}
+
+	value := strings.TrimSuffix(output, "\n")
+	sub.ActualVersion = value
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		sub.Status = StatusError
+		sub.ErrorMessage = "malformed regex: " + err.Error()
+		return sub
+	}
+	if !re.MatchString(value) {
+		sub.Status = StatusError
+		sub.ErrorMessage = "unexpected value"
+		return sub
+	}
+
+	sub.Status = StatusOK
+	return sub
+}
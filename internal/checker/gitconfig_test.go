@@ -0,0 +1,86 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+func TestCheckToolWithGitConfig(t *testing.T) {
+	gitConfig := map[string]string{
+		"user.email":      `@example\.com$`,
+		"commit.gpgsign":  "^true$",
+		"user.signingkey": ".+",
+	}
+
+	tests := []struct {
+		name           string
+		installed      map[string]string
+		outputs        map[string]string
+		expectedStatus CheckStatus
+		expectedSubs   []SubCheckResult
+		expectedError  string
+	}{
+		{
+			name:      "all values match",
+			installed: map[string]string{"git": "/usr/bin/git"},
+			outputs: map[string]string{
+				"git config --get commit.gpgsign":  "true\n",
+				"git config --get user.email":      "dev@example.com\n",
+				"git config --get user.signingkey": "ABCD1234\n",
+			},
+			expectedStatus: StatusOK,
+			expectedSubs: []SubCheckResult{
+				{Name: "commit.gpgsign", Status: StatusOK, RequiredVersion: "^true$", ActualVersion: "true"},
+				{Name: "user.email", Status: StatusOK, RequiredVersion: `@example\.com$`, ActualVersion: "dev@example.com"},
+				{Name: "user.signingkey", Status: StatusOK, RequiredVersion: ".+", ActualVersion: "ABCD1234"},
+			},
+		},
+		{
+			name:      "personal email and no signing key",
+			installed: map[string]string{"git": "/usr/bin/git"},
+			outputs: map[string]string{
+				"git config --get commit.gpgsign": "true\n",
+				"git config --get user.email":     "dev@gmail.com\n",
+			},
+			expectedStatus: StatusError,
+			expectedSubs: []SubCheckResult{
+				{Name: "commit.gpgsign", Status: StatusOK, RequiredVersion: "^true$", ActualVersion: "true"},
+				{Name: "user.email", Status: StatusError, RequiredVersion: `@example\.com$`, ActualVersion: "dev@gmail.com", ErrorMessage: "unexpected value"},
+				{Name: "user.signingkey", Status: StatusMissing, RequiredVersion: ".+", ErrorMessage: "not set"},
+			},
+			expectedError: "git config: user.email (unexpected value), user.signingkey (not set)",
+		},
+		{
+			name:           "git not installed",
+			expectedStatus: StatusNotFound,
+			expectedError:  "git not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolChecker := NewChecker()
+			toolChecker.SetRunner(&fakeRunner{installed: tt.installed, outputs: tt.outputs})
+
+			tool := manifest.ToolDefinition{
+				ID:    "git-identity",
+				Name:  "Git identity",
+				Check: manifest.CheckConfig{Type: manifest.CheckTypeGitConfig, GitConfig: gitConfig},
+			}
+
+			result := toolChecker.CheckTool(tool, platform.PlatformInfo{OS: "linux", Architecture: "amd64"})
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Expected status %v, got %v (%s)", tt.expectedStatus, result.Status, result.ErrorMessage)
+			}
+			if !reflect.DeepEqual(result.SubChecks, tt.expectedSubs) {
+				t.Errorf("Expected sub-checks %+v, got %+v", tt.expectedSubs, result.SubChecks)
+			}
+			if result.ErrorMessage != tt.expectedError {
+				t.Errorf("Expected error %q, got %q", tt.expectedError, result.ErrorMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package checker
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// TransitionKind represents the direction of a tool status transition
+type TransitionKind int
+
+const (
+	TransitionFail TransitionKind = iota
+	TransitionRecover
+)
+
+// String returns the string representation of the transition kind
+func (tk TransitionKind) String() string {
+	switch tk {
+	case TransitionFail:
+		return "fail"
+	case TransitionRecover:
+		return "recover"
+	default:
+		return "unknown"
+	}
+}
+
+// Transition describes a tool whose status changed between two observations
+type Transition struct {
+	ToolID    string
+	Kind      TransitionKind
+	From      CheckStatus
+	To        CheckStatus
+	HookError string
+}
+
+// TransitionTracker remembers tool statuses across repeated runs (watch/daemon modes)
+// and executes the tool's on_fail / on_recover hooks when a status transitions
+type TransitionTracker struct {
+	tools       map[string]manifest.ToolDefinition
+	previous    map[string]CheckStatus
+	hookTimeout time.Duration
+	restricted  bool
+	audit       *AuditLog
+}
+
+// NewTransitionTracker creates a tracker for the given tool definitions
+func NewTransitionTracker(tools []manifest.ToolDefinition) *TransitionTracker {
+	toolMap := make(map[string]manifest.ToolDefinition, len(tools))
+	for _, tool := range tools {
+		toolMap[tool.ID] = tool
+	}
+
+	return &TransitionTracker{
+		tools:       toolMap,
+		previous:    make(map[string]CheckStatus),
+		hookTimeout: 10 * time.Second,
+	}
+}
+
+// SetHookTimeout sets the maximum time a single hook may run
+func (tt *TransitionTracker) SetHookTimeout(timeout time.Duration) {
+	tt.hookTimeout = timeout
+}
+
+// SetRestricted makes the tracker refuse to run hooks, which are arbitrary manifest commands,
+// as restricted mode refuses them for checks
+func (tt *TransitionTracker) SetRestricted(enabled bool) {
+	tt.restricted = enabled
+}
+
+// SetAuditLog records every hook the tracker runs in log
+func (tt *TransitionTracker) SetAuditLog(log *AuditLog) {
+	tt.audit = log
+}
+
+// Observe records a new set of results, runs hooks for transitions, and returns them
+// The first observation of a tool only establishes a baseline and never fires hooks
+func (tt *TransitionTracker) Observe(results []CheckResult) []Transition {
+	var transitions []Transition
+
+	for _, result := range results {
+		// A skipped tool was not checked, so it neither broke nor recovered
+		if result.Status == StatusSkipped {
+			continue
+		}
+
+		previous, seen := tt.previous[result.ToolID]
+		tt.previous[result.ToolID] = result.Status
+
+		if !seen {
+			continue
+		}
+
+		wasOK := previous == StatusOK
+		isOK := result.Status == StatusOK
+		if wasOK == isOK {
+			continue
+		}
+
+		transition := Transition{
+			ToolID: result.ToolID,
+			From:   previous,
+			To:     result.Status,
+		}
+
+		tool := tt.tools[result.ToolID]
+		hook := tool.OnFail
+		transition.Kind = TransitionFail
+		if isOK {
+			hook = tool.OnRecover
+			transition.Kind = TransitionRecover
+		}
+
+		if len(hook) > 0 {
+			if err := tt.runHook(hook, transition); err != nil {
+				transition.HookError = err.Error()
+			}
+		}
+
+		transitions = append(transitions, transition)
+	}
+
+	return transitions
+}
+
+// runHook executes a hook command with transition details in its environment
+func (tt *TransitionTracker) runHook(command []string, transition Transition) error {
+	if tt.restricted {
+		return NewCheckError("restricted mode does not run hooks", ErrorTypeConfiguration)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tt.hookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = append(os.Environ(),
+		"GOCTOR_TOOL_ID="+transition.ToolID,
+		"GOCTOR_TRANSITION="+transition.Kind.String(),
+		"GOCTOR_PREVIOUS_STATUS="+transition.From.String(),
+		"GOCTOR_STATUS="+transition.To.String(),
+	)
+
+	start := time.Now()
+	err := cmd.Run()
+	if tt.audit != nil {
+		entry := newAuditEntry(transition.ToolID, command, start, err)
+		entry.Hook = "on_" + transition.Kind.String()
+		entry.Target = LocalRunner{}.String()
+		tt.audit.Record(entry)
+	}
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return NewCheckError("hook timed out", ErrorTypeTimeout)
+		}
+		return NewCheckError("hook failed: "+err.Error(), ErrorTypeExecution)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package checker
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+func TestTransitionTrackerObserve(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "failed")
+
+	tracker := NewTransitionTracker([]manifest.ToolDefinition{
+		{ID: "docker", OnFail: []string{"touch", marker}},
+	})
+
+	// First observation only establishes a baseline
+	if transitions := tracker.Observe([]CheckResult{{ToolID: "docker", Status: StatusOK}}); len(transitions) != 0 {
+		t.Fatalf("Expected no transitions on first observation, got %v", transitions)
+	}
+
+	transitions := tracker.Observe([]CheckResult{{ToolID: "docker", Status: StatusError}})
+	if len(transitions) != 1 || transitions[0].Kind != TransitionFail {
+		t.Fatalf("Expected one fail transition, got %v", transitions)
+	}
+	if transitions[0].HookError != "" {
+		t.Fatalf("Expected hook to succeed, got: %s", transitions[0].HookError)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Errorf("Expected on_fail hook to run: %v", err)
+	}
+
+	// Staying failed does not fire again
+	if transitions := tracker.Observe([]CheckResult{{ToolID: "docker", Status: StatusNotFound}}); len(transitions) != 0 {
+		t.Errorf("Expected no transitions while still failing, got %v", transitions)
+	}
+
+	transitions = tracker.Observe([]CheckResult{{ToolID: "docker", Status: StatusOK}})
+	if len(transitions) != 1 || transitions[0].Kind != TransitionRecover {
+		t.Errorf("Expected one recover transition, got %v", transitions)
+	}
+
+	// Skipping a passing tool is not a failure, nor is checking it again a recovery
+	for _, status := range []CheckStatus{StatusSkipped, StatusOK} {
+		if transitions := tracker.Observe([]CheckResult{{ToolID: "docker", Status: status}}); len(transitions) != 0 {
+			t.Errorf("Expected no transitions when %v, got %v", status, transitions)
+		}
+	}
+}
+
+func TestTransitionTrackerRestrictedAndAudited(t *testing.T) {
+	tests := []struct {
+		name       string
+		restricted bool
+		wantErr    string
+		wantAudit  string
+	}{
+		{"audited", false, "", `"tool_id":"docker","hook":"on_fail","command":["touch",`},
+		{"restricted", true, "restricted mode does not run hooks", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marker := filepath.Join(t.TempDir(), "failed")
+			var buf bytes.Buffer
+
+			tracker := NewTransitionTracker([]manifest.ToolDefinition{
+				{ID: "docker", OnFail: []string{"touch", marker}},
+			})
+			tracker.SetRestricted(tt.restricted)
+			tracker.SetAuditLog(NewAuditLog(&buf))
+
+			tracker.Observe([]CheckResult{{ToolID: "docker", Status: StatusOK}})
+			transitions := tracker.Observe([]CheckResult{{ToolID: "docker", Status: StatusError}})
+			if len(transitions) != 1 || !strings.Contains(transitions[0].HookError, tt.wantErr) || (tt.wantErr == "") != (transitions[0].HookError == "") {
+				t.Fatalf("Expected one transition with hook error %q, got %+v", tt.wantErr, transitions)
+			}
+
+			_, err := os.Stat(marker)
+			if ran := err == nil; ran == tt.restricted {
+				t.Errorf("Expected the hook to run: %v, it ran: %v", !tt.restricted, ran)
+			}
+			if !strings.Contains(buf.String(), tt.wantAudit) || (tt.wantAudit == "") != (buf.Len() == 0) {
+				t.Errorf("Expected an audit log containing %q, got %q", tt.wantAudit, buf.String())
+			}
+		})
+	}
+}
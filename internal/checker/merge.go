@@ -0,0 +1,130 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadExternalResults reads findings produced by another scanner from a JSON file
+// The file holds either an array of results or an object with an "items" array, such as
+// a goctor report; results use the same fields as report items
+func LoadExternalResults(path string) ([]CheckResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results: %v", err)
+	}
+
+	var results []CheckResult
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		err = json.Unmarshal(data, &results)
+	} else {
+		var document struct {
+			Items []CheckResult `json:"items"`
+		}
+		err = json.Unmarshal(data, &document)
+		results = document.Items
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse results %s: %v", path, err)
+	}
+
+	seen := make(map[string]bool, len(results))
+	for i := range results {
+		result := &results[i]
+		if result.ToolID == "" {
+			return nil, fmt.Errorf("result %d in %s: id is required", i, path)
+		}
+		if result.Status == StatusUnknown {
+			return nil, fmt.Errorf("result %d (%s) in %s: status is required", i, result.ToolID, path)
+		}
+		if seen[result.ToolID] {
+			return nil, fmt.Errorf("duplicate result id %q in %s", result.ToolID, path)
+		}
+		seen[result.ToolID] = true
+
+		if result.ToolName == "" {
+			result.ToolName = result.ToolID
+		}
+		if result.Source == "" {
+			result.Source = path
+		}
+	}
+
+	return results, nil
+}
+
+// MergeResults adds externally produced results to the report and recalculates its summary
+// A result with the same ID as an existing item replaces it in place; others are appended
+func (er *EnvironmentReport) MergeResults(results []CheckResult) {
+	index := make(map[string]int, len(er.Items))
+	for i, item := range er.Items {
+		index[item.ToolID] = i
+	}
+
+	for _, result := range results {
+		if i, exists := index[result.ToolID]; exists {
+			er.Items[i] = result
+			continue
+		}
+		index[result.ToolID] = len(er.Items)
+		er.Items = append(er.Items, result)
+	}
+
+	er.Summary = CalculateCheckSummary(er.Items)
+}
+
+// WorkspaceReport is the report of one workspace of a repository, e.g. a package of a monorepo
+type WorkspaceReport struct {
+	Workspace string
+	Report    EnvironmentReport
+}
+
+// MergeReports combines the reports of several workspaces into one validated report
+// Every item records its workspace, and tool IDs reported by more than one workspace are
+// prefixed with "WORKSPACE/" so they stay distinguishable. The merged report takes the
+// platform of the first report and lists every manifest source.
+func MergeReports(reports []WorkspaceReport) (*EnvironmentReport, error) {
+	if len(reports) == 0 {
+		return nil, errors.New("no reports to merge")
+	}
+
+	workspaces := make(map[string]bool, len(reports))
+	occurrences := make(map[string]int)
+	for _, wr := range reports {
+		if wr.Workspace == "" {
+			return nil, errors.New("workspace name cannot be empty")
+		}
+		if workspaces[wr.Workspace] {
+			return nil, fmt.Errorf("duplicate workspace: %s", wr.Workspace)
+		}
+		workspaces[wr.Workspace] = true
+
+		for _, item := range wr.Report.Items {
+			occurrences[item.ToolID]++
+		}
+	}
+
+	items := []CheckResult{}
+	var sources []string
+	for _, wr := range reports {
+		sources = append(sources, wr.Report.ManifestSource)
+		for _, item := range wr.Report.Items {
+			if occurrences[item.ToolID] > 1 {
+				item.ToolID = wr.Workspace + "/" + item.ToolID
+			}
+			item.Workspace = wr.Workspace
+			items = append(items, item)
+		}
+	}
+
+	merged := NewEnvironmentReport(reports[0].Report.Platform, strings.Join(sources, ", "), items)
+	if err := merged.Validate(); err != nil {
+		return nil, fmt.Errorf("merged report is invalid: %v", err)
+	}
+
+	return merged, nil
+}
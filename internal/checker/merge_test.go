@@ -0,0 +1,171 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeResults(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "extra.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write results: %v", err)
+	}
+	return path
+}
+
+func TestLoadExternalResults(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantIDs []string
+		wantErr string
+	}{
+		{
+			name:    "array of results",
+			content: `[{"id": "license-scan", "name": "License scan", "status": "ok", "actual_version": "3.1"}]`,
+			wantIDs: []string{"license-scan"},
+		},
+		{
+			name:    "report document",
+			content: `{"schema_version": 1, "items": [{"id": "vpn", "status": "missing"}, {"id": "sso", "status": 1}]}`,
+			wantIDs: []string{"vpn", "sso"},
+		},
+		{
+			name:    "missing id",
+			content: `[{"name": "License scan", "status": "ok"}]`,
+			wantErr: "id is required",
+		},
+		{
+			name:    "missing status",
+			content: `[{"id": "vpn"}]`,
+			wantErr: "status is required",
+		},
+		{
+			name:    "duplicate id",
+			content: `[{"id": "vpn", "status": "ok"}, {"id": "vpn", "status": "error"}]`,
+			wantErr: "duplicate result id",
+		},
+		{
+			name:    "invalid status",
+			content: `[{"id": "vpn", "status": "broken"}]`,
+			wantErr: "failed to parse results",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeResults(t, tt.content)
+
+			results, err := LoadExternalResults(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(results) != len(tt.wantIDs) {
+				t.Fatalf("Expected %d results, got %d", len(tt.wantIDs), len(results))
+			}
+			for i, id := range tt.wantIDs {
+				if results[i].ToolID != id {
+					t.Errorf("Result %d: expected id %s, got %s", i, id, results[i].ToolID)
+				}
+				if results[i].ToolName == "" {
+					t.Errorf("Result %d: expected name to default to the id", i)
+				}
+				if results[i].Source != path {
+					t.Errorf("Result %d: expected source %s, got %s", i, path, results[i].Source)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeResults(t *testing.T) {
+	report := NewEnvironmentReport(nil, "tools.yaml", []CheckResult{
+		{ToolID: "go", ToolName: "Go", Status: StatusOK, ActualVersion: "1.23.0"},
+		{ToolID: "docker", ToolName: "Docker", Status: StatusOK, ActualVersion: "27.0.1"},
+	})
+
+	report.MergeResults([]CheckResult{
+		{ToolID: "docker", ToolName: "Docker", Status: StatusOutdated, ActualVersion: "27.0.1", Source: "policy.json"},
+		{ToolID: "vpn", ToolName: "VPN client", Status: StatusMissing, Source: "policy.json"},
+	})
+
+	if len(report.Items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(report.Items))
+	}
+	if report.Items[1].ToolID != "docker" || report.Items[1].Status != StatusOutdated {
+		t.Errorf("Expected docker to be replaced in place, got %+v", report.Items[1])
+	}
+	if report.Items[2].ToolID != "vpn" {
+		t.Errorf("Expected vpn to be appended, got %s", report.Items[2].ToolID)
+	}
+
+	want := CheckSummary{Total: 3, OK: 1, Missing: 1, Outdated: 1}
+	if report.Summary != want {
+		t.Errorf("Expected summary %+v, got %+v", want, report.Summary)
+	}
+	if report.GetExitCode() != 1 {
+		t.Error("Expected merged failures to fail the run")
+	}
+}
+
+func TestMergeReports(t *testing.T) {
+	links := map[string]string{"homepage": "https://example.com/"}
+	api := NewEnvironmentReport(map[string]interface{}{"os": "linux"}, "services/api/tools.yaml", []CheckResult{
+		{ToolID: "go", ToolName: "Go", Status: StatusOK, RequiredVersion: ">=1.22", ActualVersion: "1.22.1", Links: links},
+		{ToolID: "buf", ToolName: "Buf", Status: StatusNotFound, RequiredVersion: ">=1.30", Links: links},
+	})
+	web := NewEnvironmentReport(map[string]interface{}{"os": "linux"}, "apps/web/tools.yaml", []CheckResult{
+		{ToolID: "node", ToolName: "Node.js", Status: StatusOK, RequiredVersion: ">=20", ActualVersion: "20.11.0", Links: links},
+		{ToolID: "go", ToolName: "Go", Status: StatusOutdated, RequiredVersion: ">=1.23", ActualVersion: "1.22.1", Links: links},
+	})
+
+	merged, err := MergeReports([]WorkspaceReport{{"api", *api}, {"web", *web}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, item := range merged.Items {
+		ids = append(ids, item.Workspace+":"+item.ToolID)
+	}
+	if strings.Join(ids, ",") != "api:api/go,api:buf,web:node,web:web/go" {
+		t.Errorf("Unexpected items: %v", ids)
+	}
+	if merged.Summary.Total != 4 || merged.Summary.OK != 2 || merged.Summary.Missing != 1 || merged.Summary.Outdated != 1 {
+		t.Errorf("Unexpected summary: %+v", merged.Summary)
+	}
+	if merged.ManifestSource != "services/api/tools.yaml, apps/web/tools.yaml" {
+		t.Errorf("Unexpected manifest source: %s", merged.ManifestSource)
+	}
+
+	tests := []struct {
+		name    string
+		reports []WorkspaceReport
+		wantErr string
+	}{
+		{"no reports", nil, "no reports to merge"},
+		{"duplicate workspace", []WorkspaceReport{{"api", *api}, {"api", *web}}, "duplicate workspace: api"},
+		{"empty workspace", []WorkspaceReport{{"", *api}}, "workspace name cannot be empty"},
+		{"invalid item", []WorkspaceReport{{"api", *NewEnvironmentReport(nil, "tools.yaml", []CheckResult{{ToolID: "go", Status: StatusOK}})}}, "merged report is invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MergeReports(tt.reports)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package checker
+
+import (
+	"errors"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+// checkOS compares the version of the OS, a Linux distribution, or the kernel with the tool's
+// requirement; a machine running another OS or distribution fails the check as not found
+func (c *Checker) checkOS(tool manifest.ToolDefinition, platformInfo platform.PlatformInfo, result *CheckResult) {
+	source := tool.Check.OS
+	if goos := platform.OSVersionOS(source); goos != "" && goos != platformInfo.OS {
+		otherOS := &platform.OtherOSError{Want: source, Running: platformInfo.OS}
+		result.Status = StatusNotFound
+		result.ErrorMessage = otherOS.Error()
+		return
+	}
+
+	output, err := c.runTraced(result, tool.CheckCommand(), tool.TimeoutSeconds, "", nil)
+	result.RawOutput = output
+	if err != nil {
+		result.Fail("failed to read the OS version: "+err.Error(), err)
+		return
+	}
+
+	version, err := platform.ParseOSVersion(source, output)
+	if err != nil {
+		var otherOS *platform.OtherOSError
+		if errors.As(err, &otherOS) {
+			result.Status = StatusNotFound
+		} else {
+			result.Status = StatusError
+		}
+		result.ErrorMessage = err.Error()
+		return
+	}
+
+	result.ActualVersion = version
+	c.applyRequirements(tool, result)
+}
@@ -0,0 +1,119 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+const ubuntuRelease = `PRETTY_NAME="Ubuntu 22.04.4 LTS"
+NAME="Ubuntu"
+VERSION_ID="22.04"
+ID=ubuntu
+ID_LIKE=debian
+`
+
+func TestCheckToolWithOS(t *testing.T) {
+	linux := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+	darwin := platform.PlatformInfo{OS: "darwin", Architecture: "arm64"}
+
+	tests := []struct {
+		name           string
+		os             string
+		require        string
+		platform       platform.PlatformInfo
+		outputs        map[string]string
+		expectedStatus CheckStatus
+		expectedVer    string
+		expectedError  string
+	}{
+		{
+			name:           "macOS new enough",
+			os:             "macos",
+			require:        ">=13",
+			platform:       darwin,
+			outputs:        map[string]string{"sw_vers -productVersion": "14.2.1\n"},
+			expectedStatus: StatusOK,
+			expectedVer:    "14.2.1",
+		},
+		{
+			name:           "macOS too old",
+			os:             "macos",
+			require:        ">=13",
+			platform:       darwin,
+			outputs:        map[string]string{"sw_vers -productVersion": "12.7\n"},
+			expectedStatus: StatusOutdated,
+			expectedVer:    "12.7",
+		},
+		{
+			name:           "macOS required on linux",
+			os:             "macos",
+			require:        ">=13",
+			platform:       linux,
+			expectedStatus: StatusNotFound,
+			expectedError:  "running linux, not macos",
+		},
+		{
+			name:           "ubuntu release",
+			os:             "ubuntu",
+			require:        ">=22.04",
+			platform:       linux,
+			outputs:        map[string]string{"cat /etc/os-release": ubuntuRelease},
+			expectedStatus: StatusOK,
+			expectedVer:    "22.04",
+		},
+		{
+			name:           "other distribution",
+			os:             "fedora",
+			require:        ">=39",
+			platform:       linux,
+			outputs:        map[string]string{"cat /etc/os-release": ubuntuRelease},
+			expectedStatus: StatusNotFound,
+			expectedError:  "running ubuntu, not fedora",
+		},
+		{
+			name:           "kernel release",
+			os:             "kernel",
+			require:        ">=6.1",
+			platform:       linux,
+			outputs:        map[string]string{"uname -r": "6.5.0-14-generic\n"},
+			expectedStatus: StatusOK,
+			expectedVer:    "6.5.0",
+		},
+		{
+			name:           "unreadable version",
+			os:             "kernel",
+			require:        ">=6.1",
+			platform:       linux,
+			expectedStatus: StatusError,
+			expectedError:  "failed to read the OS version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolChecker := NewChecker()
+			toolChecker.SetRunner(&fakeRunner{outputs: tt.outputs})
+
+			tool := manifest.ToolDefinition{
+				ID:              "os",
+				Name:            "Operating system",
+				RequiredVersion: tt.require,
+				Check:           manifest.CheckConfig{Type: manifest.CheckTypeOS, OS: tt.os},
+			}
+
+			result := toolChecker.CheckTool(tool, tt.platform)
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Expected status %v, got %v (%s)", tt.expectedStatus, result.Status, result.ErrorMessage)
+			}
+			if result.ActualVersion != tt.expectedVer {
+				t.Errorf("Expected version %q, got %q", tt.expectedVer, result.ActualVersion)
+			}
+			if !strings.Contains(result.ErrorMessage, tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %q", tt.expectedError, result.ErrorMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package checker
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+// CheckPlan describes how a tool would be checked without checking it, for doctor --dry-run
+type CheckPlan struct {
+	ToolID          string                     `json:"tool_id"`
+	ToolName        string                     `json:"tool_name"`
+	Type            string                     `json:"type"`
+	Command         []string                   `json:"command,omitempty"`
+	Fallbacks       [][]string                 `json:"fallbacks,omitempty"`
+	Shell           bool                       `json:"shell,omitempty"`
+	Plugin          string                     `json:"plugin,omitempty"`
+	GitConfig       map[string]string          `json:"git_config,omitempty"`
+	Var             string                     `json:"var,omitempty"`
+	Exists          string                     `json:"exists,omitempty"`
+	Sensitive       bool                       `json:"sensitive,omitempty"`
+	Workdir         string                     `json:"workdir,omitempty"`
+	Env             map[string]string          `json:"env,omitempty"`
+	Timeout         Milliseconds               `json:"timeout_ms,omitempty"`
+	DetectTimeout   Milliseconds               `json:"detect_timeout_ms,omitempty"`
+	ExpectSHA256    string                     `json:"expect_sha256,omitempty"`
+	Regex           string                     `json:"regex,omitempty"`
+	CaptureGroup    string                     `json:"capture_group,omitempty"`
+	ExpectExitCodes []int                      `json:"expect_exit_codes,omitempty"`
+	Constraint      string                     `json:"constraint,omitempty"`
+	ConstraintFrom  string                     `json:"constraint_from,omitempty"`
+	Recommended     string                     `json:"recommended,omitempty"`
+	VersionScheme   string                     `json:"version_scheme,omitempty"`
+	AllowPrerelease bool                       `json:"allow_prerelease,omitempty"`
+	ExcludeVersions []manifest.ExcludedVersion `json:"exclude_versions,omitempty"`
+	SubChecks       []CheckPlan                `json:"sub_checks,omitempty"`
+	Variants        []CheckPlan                `json:"variants,omitempty"`
+	SkipReason      string                     `json:"skip_reason,omitempty"`
+	// Error explains why the check would fail before running anything
+	Error string `json:"error,omitempty"`
+}
+
+// Plan returns what checking tool on platformInfo would run and evaluate, without running or
+// looking up any command
+func (c *Checker) Plan(tool manifest.ToolDefinition, platformInfo platform.PlatformInfo) CheckPlan {
+	// Each variant is checked as a tool of its own
+	if len(tool.Variants) > 0 {
+		plan := CheckPlan{ToolID: tool.ID, ToolName: tool.Name, Type: checkType(tool)}
+		for _, v := range tool.Variants {
+			variantPlan := c.Plan(tool.VariantDefinition(v), platformInfo)
+			variantPlan.ToolName = v.Name
+			plan.Variants = append(plan.Variants, variantPlan)
+		}
+		return plan
+	}
+
+	plan := CheckPlan{
+		ToolID:          tool.ID,
+		ToolName:        tool.Name,
+		Type:            checkType(tool),
+		Shell:           tool.Check.Shell,
+		Workdir:         tool.Check.Workdir,
+		Env:             tool.Check.Env,
+		Constraint:      tool.RequiredVersion,
+		Recommended:     tool.RecommendedVersion,
+		VersionScheme:   tool.Check.VersionScheme,
+		AllowPrerelease: tool.AllowPrerelease || c.allowPrerelease,
+		ExcludeVersions: tool.ExcludeVersions,
+		ExpectSHA256:    tool.ExpectedSHA256(platformInfo.OS, platformInfo.Architecture),
+	}
+
+	// The constraint may come from a project file such as .nvmrc
+	if tool.RequireFrom != nil {
+		plan.ConstraintFrom = tool.RequireFrom.File
+		required, err := ResolveRequirement(tool)
+		if err != nil {
+			plan.Error = err.Error()
+			return plan
+		}
+		plan.Constraint = required
+	}
+
+	switch plan.Type {
+	case manifest.CheckTypePlugin:
+		plan.Plugin = tool.Check.Plugin
+	case manifest.CheckTypeGitConfig:
+		plan.GitConfig = tool.Check.GitConfig
+	case manifest.CheckTypeEnv:
+		plan.Var = tool.Check.Var
+		plan.Exists = tool.Check.Exists
+		plan.Sensitive = tool.Check.Sensitive
+		plan.Regex = tool.Check.Regex
+	case manifest.CheckTypeOS:
+		plan.Command = tool.CheckCommand()
+	default:
+		if tool.Check.Probe != "" {
+			plan.Command = tool.CheckCommand()
+			break
+		}
+		candidates := tool.CheckCommands()
+		for i, candidate := range candidates {
+			command, err := expandCommand(candidate, platformInfo.TemplateVars())
+			if err != nil {
+				plan.Error = err.Error()
+				return plan
+			}
+			candidates[i] = command
+		}
+		plan.Command = candidates[0]
+		plan.Fallbacks = candidates[1:]
+	}
+
+	// Env checks run no command
+	if plan.Type != manifest.CheckTypeEnv {
+		timeout := c.commandTimeout
+		if tool.TimeoutSeconds > 0 {
+			timeout = time.Duration(tool.TimeoutSeconds) * time.Second
+		}
+		plan.Timeout = Milliseconds(timeout)
+	}
+
+	// Executables are looked up before they run
+	switch plan.Type {
+	case manifest.CheckTypeCommand, manifest.CheckTypeService, manifest.CheckTypeGitConfig:
+		plan.DetectTimeout = Milliseconds(c.detectTimeout)
+	}
+
+	// Only version commands have their output parsed
+	if plan.Type == manifest.CheckTypeCommand {
+		plan.Regex = tool.VersionRegex()
+		plan.ExpectExitCodes = tool.Check.ExpectExitCodes
+		group, err := captureGroup(plan.Regex, tool.VersionRegexKey())
+		if err != nil {
+			plan.Error = err.Error()
+		}
+		plan.CaptureGroup = group
+	}
+
+	if c.restricted && plan.Error == "" {
+		if err := restrictedCheck(tool); err != nil {
+			plan.Error = err.Error()
+		}
+	}
+
+	for _, sub := range tool.Checks {
+		subPlan := c.Plan(tool.SubCheckDefinition(sub), platformInfo)
+		subPlan.ToolName = sub.Name
+		plan.SubChecks = append(plan.SubChecks, subPlan)
+	}
+
+	return plan
+}
+
+// SkippedPlan returns the plan of a tool the run would not check, with the reason why
+func SkippedPlan(tool manifest.ToolDefinition, reason string) CheckPlan {
+	return CheckPlan{ToolID: tool.ID, ToolName: tool.Name, Type: checkType(tool), SkipReason: reason}
+}
+
+// checkType returns the check backend a tool uses, command when none is configured
+func checkType(tool manifest.ToolDefinition) string {
+	switch {
+	case tool.IsPlugin():
+		return manifest.CheckTypePlugin
+	case tool.IsService():
+		return manifest.CheckTypeService
+	case tool.IsOS():
+		return manifest.CheckTypeOS
+	case tool.IsGitConfig():
+		return manifest.CheckTypeGitConfig
+	case tool.IsEnv():
+		return manifest.CheckTypeEnv
+	default:
+		return manifest.CheckTypeCommand
+	}
+}
+
+// captureGroup names the capture group of pattern the version would be read from: regexKey,
+// the first group named like a version, or else the first group
+func captureGroup(pattern, regexKey string) (string, error) {
+	if pattern == "" {
+		return "", NewCheckError("empty regex pattern", ErrorTypeConfiguration)
+	}
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", NewCheckError("invalid regex: "+err.Error(), ErrorTypeConfiguration)
+	}
+
+	names := regex.SubexpNames()
+	if regexKey != "" {
+		if !slices.Contains(names, regexKey) {
+			return "", NewCheckError(fmt.Sprintf("regex has no capture group named %q (regex_key)", regexKey), ErrorTypeConfiguration)
+		}
+		return regexKey, nil
+	}
+	for _, name := range names {
+		if slices.Contains(manifest.VersionGroupNames, strings.ToLower(name)) {
+			return name, nil
+		}
+	}
+	if len(names) > 1 {
+		return cmp.Or(names[1], "1"), nil
+	}
+	return "", NewCheckError("regex has no capture group", ErrorTypeConfiguration)
+}
@@ -0,0 +1,127 @@
+package checker
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+func TestCheckerPlan(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	tests := []struct {
+		name        string
+		tool        manifest.ToolDefinition
+		wantType    string
+		wantCommand []string
+		wantTimeout time.Duration
+		wantGroup   string
+		wantErr     string
+	}{
+		{
+			name: "named group",
+			tool: manifest.ToolDefinition{
+				ID:              "go",
+				RequiredVersion: ">=1.22",
+				Check:           manifest.CheckConfig{Command: []string{"go", "version"}, Regex: `go(?P<ver>\d+\.\d+)`},
+			},
+			wantType:    manifest.CheckTypeCommand,
+			wantCommand: []string{"go", "version"},
+			wantTimeout: 5 * time.Second,
+			wantGroup:   "ver",
+		},
+		{
+			name: "first group and tool timeout",
+			tool: manifest.ToolDefinition{
+				ID:             "make",
+				TimeoutSeconds: 20,
+				Check:          manifest.CheckConfig{Command: []string{"make", "--version"}, Regex: `Make (\d+\.\d+)`},
+			},
+			wantType:    manifest.CheckTypeCommand,
+			wantCommand: []string{"make", "--version"},
+			wantTimeout: 20 * time.Second,
+			wantGroup:   "1",
+		},
+		{
+			name: "regex key",
+			tool: manifest.ToolDefinition{
+				ID:    "java",
+				Check: manifest.CheckConfig{Command: []string{"java", "-version"}, Regex: `(?P<major>\d+)\.(?P<full>\d+\.\d+)`, RegexKey: "full"},
+			},
+			wantType:    manifest.CheckTypeCommand,
+			wantCommand: []string{"java", "-version"},
+			wantTimeout: 5 * time.Second,
+			wantGroup:   "full",
+		},
+		{
+			name: "platform template",
+			tool: manifest.ToolDefinition{
+				ID:    "tool",
+				Check: manifest.CheckConfig{Command: []string{"tool-{{ .os }}", "--version"}, Regex: `(\d+)`},
+			},
+			wantType:    manifest.CheckTypeCommand,
+			wantCommand: []string{"tool-linux", "--version"},
+			wantTimeout: 5 * time.Second,
+			wantGroup:   "1",
+		},
+		{
+			name: "unknown regex key",
+			tool: manifest.ToolDefinition{
+				ID:    "tool",
+				Check: manifest.CheckConfig{Command: []string{"tool"}, Regex: `(?P<ver>\d+)`, RegexKey: "version"},
+			},
+			wantType:    manifest.CheckTypeCommand,
+			wantCommand: []string{"tool"},
+			wantTimeout: 5 * time.Second,
+			wantErr:     `regex has no capture group named "version" (regex_key)`,
+		},
+		{
+			name: "regex without groups",
+			tool: manifest.ToolDefinition{
+				ID:    "tool",
+				Check: manifest.CheckConfig{Command: []string{"tool"}, Regex: `\d+`},
+			},
+			wantType:    manifest.CheckTypeCommand,
+			wantCommand: []string{"tool"},
+			wantTimeout: 5 * time.Second,
+			wantErr:     "regex has no capture group",
+		},
+		{
+			name: "service",
+			tool: manifest.ToolDefinition{
+				ID:    "docker-daemon",
+				Check: manifest.CheckConfig{Type: manifest.CheckTypeService, Command: []string{"docker", "info"}},
+			},
+			wantType:    manifest.CheckTypeService,
+			wantCommand: []string{"docker", "info"},
+			wantTimeout: 5 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := NewChecker().Plan(tt.tool, platformInfo)
+			if plan.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", plan.Type, tt.wantType)
+			}
+			if !slices.Equal(plan.Command, tt.wantCommand) {
+				t.Errorf("Command = %q, want %q", plan.Command, tt.wantCommand)
+			}
+			if time.Duration(plan.Timeout) != tt.wantTimeout {
+				t.Errorf("Timeout = %v, want %v", time.Duration(plan.Timeout), tt.wantTimeout)
+			}
+			if plan.CaptureGroup != tt.wantGroup {
+				t.Errorf("CaptureGroup = %q, want %q", plan.CaptureGroup, tt.wantGroup)
+			}
+			if plan.Error != tt.wantErr {
+				t.Errorf("Error = %q, want %q", plan.Error, tt.wantErr)
+			}
+			if plan.Constraint != tt.tool.RequiredVersion {
+				t.Errorf("Constraint = %q, want %q", plan.Constraint, tt.tool.RequiredVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+// PluginResponse is the JSON document a check plugin must print on stdout
+type PluginResponse struct {
+	Status  string `json:"status"`
+	Version string `json:"version,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Plugin response statuses
+const (
+	PluginStatusOK       = "ok"
+	PluginStatusMissing  = "missing"
+	PluginStatusOutdated = "outdated"
+	PluginStatusError    = "error"
+)
+
+// checkPlugin runs the tool's check.plugin executable and converts its response into a result
+// The plugin receives GOCTOR_TOOL_ID, GOCTOR_REQUIRE, GOCTOR_OS, and GOCTOR_ARCH in its environment
+func (c *Checker) checkPlugin(tool manifest.ToolDefinition, platformInfo platform.PlatformInfo, result CheckResult) CheckResult {
+	pluginPath, err := ResolvePluginPath(tool.Check.Plugin)
+	if err != nil {
+		result.Status = StatusNotFound
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	result.CommandPath = pluginPath
+
+	env := []string{
+		"GOCTOR_TOOL_ID=" + tool.ID,
+		"GOCTOR_REQUIRE=" + tool.RequiredVersion,
+		"GOCTOR_OS=" + platformInfo.OS,
+		"GOCTOR_ARCH=" + platformInfo.Architecture,
+	}
+
+	for name, value := range tool.Check.Env {
+		env = append(env, name+"="+value)
+	}
+
+	start := time.Now()
+	response, rawOutput, err := c.runPlugin(tool.ID, pluginPath, tool.Check.Workdir, env, tool.TimeoutSeconds)
+	result.RawOutput = rawOutput
+	detail := pluginPath
+	if err != nil {
+		detail += ": " + err.Error()
+	}
+	c.trace(&result, TraceStep{Action: TraceRun, Detail: detail, Output: rawOutput, Duration: Milliseconds(time.Since(start))})
+	if err != nil {
+		result.Fail(err.Error(), err)
+		return result
+	}
+
+	result.ActualVersion = response.Version
+
+	switch response.Status {
+	case PluginStatusOK:
+		// A reported version is still held to the manifest's requirements
+		if response.Version != "" && tool.RequiredVersion != "" {
+			c.applyRequirements(tool, &result)
+			return result
+		}
+		result.Status = StatusOK
+	case PluginStatusMissing:
+		result.Status = StatusNotFound
+		result.ErrorMessage = pluginMessage(response, "Plugin reported tool missing")
+	case PluginStatusOutdated:
+		result.Status = StatusOutdated
+		result.ErrorMessage = pluginMessage(response, "Plugin reported tool outdated")
+	case PluginStatusError:
+		result.Status = StatusError
+		result.ErrorMessage = pluginMessage(response, "Plugin reported an error")
+	default:
+		result.Status = StatusError
+		result.ErrorMessage = "invalid plugin status: " + response.Status
+	}
+
+	return result
+}
+
+// runPlugin executes a plugin for a tool and decodes its JSON response from stdout
+// A non-zero exit is tolerated as long as a valid response was printed
+func (c *Checker) runPlugin(toolID, pluginPath, workdir string, env []string, timeoutSec int) (PluginResponse, string, error) {
+	timeout := c.commandTimeout
+	if timeoutSec > 0 {
+		timeout = time.Duration(timeoutSec) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, pluginPath)
+	cmd.WaitDelay = waitDelay
+	cmd.Dir = workdir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	runErr := cmd.Run()
+	c.auditCommand(toolID, []string{pluginPath}, workdir, start, runErr)
+	rawOutput := stdout.String() + stderr.String()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return PluginResponse{}, rawOutput, NewCheckError(fmt.Sprintf("plugin timed out after %s", timeout), ErrorTypeTimeout)
+	}
+
+	var response PluginResponse
+	if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &response); err != nil {
+		if runErr != nil {
+			return PluginResponse{}, rawOutput, NewCheckError("plugin failed: "+runErr.Error(), ErrorTypeExecution)
+		}
+		return PluginResponse{}, rawOutput, NewCheckError("invalid plugin response: "+err.Error(), ErrorTypeParsing)
+	}
+
+	if response.Status == "" {
+		return PluginResponse{}, rawOutput, NewCheckError("invalid plugin response: missing status", ErrorTypeParsing)
+	}
+
+	return response, rawOutput, nil
+}
+
+// ResolvePluginPath locates a plugin; paths containing a separator are used as-is,
+// bare names are looked up in PATH
+func ResolvePluginPath(plugin string) (string, error) {
+	if !strings.ContainsRune(plugin, os.PathSeparator) && !strings.Contains(plugin, "/") {
+		path, err := exec.LookPath(plugin)
+		if err != nil {
+			return "", NewCheckError("plugin not found in PATH: "+plugin, ErrorTypeConfiguration)
+		}
+		return path, nil
+	}
+
+	info, err := os.Stat(plugin)
+	if err != nil {
+		return "", NewCheckError("plugin not found: "+plugin, ErrorTypeConfiguration)
+	}
+	if info.IsDir() {
+		return "", NewCheckError("plugin is a directory: "+plugin, ErrorTypeConfiguration)
+	}
+
+	// Make the path absolute so check.workdir does not change which file runs
+	absPath, err := filepath.Abs(plugin)
+	if err != nil {
+		return "", NewCheckError("plugin not found: "+plugin, ErrorTypeConfiguration)
+	}
+
+	return absPath, nil
+}
+
+// pluginMessage returns the plugin's message or a fallback when it did not provide one
+func pluginMessage(response PluginResponse, fallback string) string {
+	if response.Message != "" {
+		return response.Message
+	}
+	return fallback
+}
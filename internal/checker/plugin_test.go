@@ -0,0 +1,104 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+func writePlugin(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "plugin.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write plugin: %v", err)
+	}
+	return path
+}
+
+func TestCheckToolWithPlugin(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	tests := []struct {
+		name           string
+		script         string
+		require        string
+		expectedStatus CheckStatus
+		expectedError  string
+	}{
+		{
+			name:           "ok without version",
+			script:         `echo '{"status": "ok"}'`,
+			expectedStatus: StatusOK,
+		},
+		{
+			name:           "ok with satisfying version",
+			script:         `echo '{"status": "ok", "version": "1.4.0"}'`,
+			require:        ">=1.2",
+			expectedStatus: StatusOK,
+		},
+		{
+			name:           "ok with outdated version",
+			script:         `echo '{"status": "ok", "version": "1.0.0"}'`,
+			require:        ">=1.2",
+			expectedStatus: StatusOutdated,
+		},
+		{
+			name:           "missing with message",
+			script:         `echo '{"status": "missing", "message": "VPN is not connected"}'; exit 1`,
+			expectedStatus: StatusNotFound,
+			expectedError:  "VPN is not connected",
+		},
+		{
+			name:           "receives environment",
+			script:         `[ "$GOCTOR_TOOL_ID" = "vpn" ] && [ "$GOCTOR_OS" = "linux" ] && echo '{"status": "ok"}'`,
+			expectedStatus: StatusOK,
+		},
+		{
+			name:           "invalid response",
+			script:         `echo 'connected'`,
+			expectedStatus: StatusError,
+		},
+		{
+			name:           "unknown status",
+			script:         `echo '{"status": "great"}'`,
+			expectedStatus: StatusError,
+			expectedError:  "invalid plugin status: great",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := manifest.ToolDefinition{
+				ID:              "vpn",
+				Name:            "VPN",
+				RequiredVersion: tt.require,
+				Check:           manifest.CheckConfig{Plugin: writePlugin(t, tt.script)},
+			}
+
+			result := NewChecker().CheckTool(tool, platformInfo)
+
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Expected status %v, got %v (%s)", tt.expectedStatus, result.Status, result.ErrorMessage)
+			}
+			if tt.expectedError != "" && result.ErrorMessage != tt.expectedError {
+				t.Errorf("Expected error %q, got %q", tt.expectedError, result.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestCheckToolWithMissingPlugin(t *testing.T) {
+	tool := manifest.ToolDefinition{
+		ID:    "vpn",
+		Name:  "VPN",
+		Check: manifest.CheckConfig{Plugin: filepath.Join(t.TempDir(), "missing.sh")},
+	}
+
+	result := NewChecker().CheckTool(tool, platform.PlatformInfo{})
+	if result.Status != StatusNotFound {
+		t.Errorf("Expected status not_found, got %v", result.Status)
+	}
+}
@@ -0,0 +1,28 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/versionfile"
+)
+
+// ResolveRequirement returns the tool's version constraint, reading it from the project file
+// of require_from when the tool has one; require applies while that file is missing
+func ResolveRequirement(tool manifest.ToolDefinition) (string, error) {
+	if tool.RequireFrom == nil {
+		return tool.RequiredVersion, nil
+	}
+
+	version, err := versionfile.Read(tool.RequireFromPath(), tool.RequireFrom.Key, tool.ID)
+	if errors.Is(err, fs.ErrNotExist) && tool.RequiredVersion != "" {
+		return tool.RequiredVersion, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("require_from: %v", err)
+	}
+
+	return tool.RequireFrom.Constraint(version), nil
+}
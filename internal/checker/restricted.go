@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// versionArgs are the arguments restricted mode lets a version command take
+var versionArgs = []string{"--version", "-version", "-v", "-V"}
+
+// versionSubcommand is only allowed for checks that set version_subcommand, as the version
+// subcommand of some tools does more than print it (yarn version bumps the package version)
+const versionSubcommand = "version"
+
+// SetRestricted makes checks refuse to run anything but the tool's own binary with a
+// --version-style argument, for manifests that are not fully trusted
+func (c *Checker) SetRestricted(enabled bool) {
+	c.restricted = enabled
+}
+
+// restrictedCheck returns why restricted mode refuses to check tool, or nil if every command
+// it may run is `<tool id> <version arg>` from PATH, with goctor's environment and working directory
+// OS and git config checks run goctor's own commands and env checks run none, so they are
+// always allowed
+func restrictedCheck(tool manifest.ToolDefinition) error {
+	switch {
+	case tool.IsOS() || tool.IsGitConfig() || tool.IsEnv():
+		return nil
+	case tool.IsPlugin():
+		return NewCheckError("restricted mode does not run check plugins", ErrorTypeConfiguration)
+	case tool.Check.Shell:
+		return NewCheckError("restricted mode does not run shell checks", ErrorTypeConfiguration)
+	case len(tool.Check.Env) > 0:
+		// Variables such as PATH or LD_PRELOAD decide what a harmless-looking command runs
+		return NewCheckError(fmt.Sprintf("restricted mode does not set environment variables for checks (%s)", strings.Join(slices.Sorted(maps.Keys(tool.Check.Env)), ", ")), ErrorTypeConfiguration)
+	case tool.Check.Workdir != "":
+		return NewCheckError(fmt.Sprintf("restricted mode does not run checks in another directory (%s)", tool.Check.Workdir), ErrorTypeConfiguration)
+	}
+
+	args := versionArgs
+	if tool.Check.VersionSubcommand {
+		args = append(slices.Clip(args), versionSubcommand)
+	}
+	for _, command := range tool.CheckCommands() {
+		if !restrictedCommand(tool.ID, args, command) {
+			return NewCheckError(fmt.Sprintf("restricted mode only runs %q from PATH with one of %s, not %q", tool.ID, strings.Join(args, ", "), strings.Join(command, " ")), ErrorTypeConfiguration)
+		}
+	}
+	return nil
+}
+
+// restrictedCommand returns true if command runs the binary named toolID with a single one of
+// args; the binary must be looked up in PATH, as a path can point at any executable
+func restrictedCommand(toolID string, args []string, command []string) bool {
+	if len(command) != 2 || !slices.Contains(args, command[1]) {
+		return false
+	}
+	return command[0] == toolID || command[0] == toolID+".exe"
+}
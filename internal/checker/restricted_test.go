@@ -0,0 +1,180 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+func TestCheckToolRestricted(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	tests := []struct {
+		name       string
+		tool       manifest.ToolDefinition
+		wantStatus CheckStatus
+		wantErr    string
+	}{
+		{
+			name:       "tool version",
+			tool:       manifest.ToolDefinition{ID: "node", RequiredVersion: ">=20", Check: manifest.CheckConfig{Command: []string{"node", "--version"}, Regex: `v(\d+\.\d+\.\d+)`}},
+			wantStatus: StatusOK,
+		},
+		{
+			name:       "version subcommand allowed",
+			tool:       manifest.ToolDefinition{ID: "go", RequiredVersion: ">=1.22", Check: manifest.CheckConfig{Command: []string{"go", "version"}, Regex: `go(\d+\.\d+)`, VersionSubcommand: true}},
+			wantStatus: StatusOK,
+		},
+		{
+			name:       "version subcommand not allowed",
+			tool:       manifest.ToolDefinition{ID: "yarn", Check: manifest.CheckConfig{Command: []string{"yarn", "version"}, Regex: `(\d+\.\d+\.\d+)`}},
+			wantStatus: StatusError,
+			wantErr:    `restricted mode only runs "yarn" from PATH with one of --version, -version, -v, -V, not "yarn version"`,
+		},
+		{
+			name:       "tool by path",
+			tool:       manifest.ToolDefinition{ID: "go", Check: manifest.CheckConfig{Command: []string{"/tmp/evil/go", "version"}, Regex: `go(\d+\.\d+)`, VersionSubcommand: true}},
+			wantStatus: StatusError,
+			wantErr:    `not "/tmp/evil/go version"`,
+		},
+		{
+			name:       "tool by relative path",
+			tool:       manifest.ToolDefinition{ID: "node", Check: manifest.CheckConfig{Command: []string{"./node", "--version"}, Regex: `v(\d+\.\d+\.\d+)`}},
+			wantStatus: StatusError,
+			wantErr:    `not "./node --version"`,
+		},
+		{
+			name:       "other binary",
+			tool:       manifest.ToolDefinition{ID: "node", Check: manifest.CheckConfig{Command: []string{"curl", "--version"}, Regex: `(\d+)`}},
+			wantStatus: StatusError,
+			wantErr:    `restricted mode only runs "node"`,
+		},
+		{
+			name:       "other arguments",
+			tool:       manifest.ToolDefinition{ID: "node", Check: manifest.CheckConfig{Command: []string{"node", "-e", "require('child_process')"}, Regex: `(\d+)`}},
+			wantStatus: StatusError,
+			wantErr:    `not "node -e require('child_process')"`,
+		},
+		{
+			name: "unsafe fallback",
+			tool: manifest.ToolDefinition{ID: "node", Check: manifest.CheckConfig{
+				Command:   []string{"node", "--version"},
+				Fallbacks: [][]string{{"sh", "-c", "node --version"}},
+				Regex:     `v(\d+\.\d+\.\d+)`,
+			}},
+			wantStatus: StatusError,
+			wantErr:    `not "sh -c node --version"`,
+		},
+		{
+			name:       "shell",
+			tool:       manifest.ToolDefinition{ID: "node", Check: manifest.CheckConfig{Command: []string{"node --version"}, Shell: true, Regex: `v(\d+\.\d+\.\d+)`}},
+			wantStatus: StatusError,
+			wantErr:    "restricted mode does not run shell checks",
+		},
+		{
+			name:       "plugin",
+			tool:       manifest.ToolDefinition{ID: "vpn", Check: manifest.CheckConfig{Type: manifest.CheckTypePlugin, Plugin: "./check-vpn"}},
+			wantStatus: StatusError,
+			wantErr:    "restricted mode does not run check plugins",
+		},
+		{
+			name: "environment",
+			tool: manifest.ToolDefinition{ID: "node", Check: manifest.CheckConfig{
+				Command: []string{"node", "--version"},
+				Env:     map[string]string{"LD_PRELOAD": "/tmp/evil.so", "PATH": "/tmp"},
+				Regex:   `v(\d+\.\d+\.\d+)`,
+			}},
+			wantStatus: StatusError,
+			wantErr:    "restricted mode does not set environment variables for checks (LD_PRELOAD, PATH)",
+		},
+		{
+			name:       "workdir",
+			tool:       manifest.ToolDefinition{ID: "node", Check: manifest.CheckConfig{Command: []string{"node", "--version"}, Workdir: "/tmp/untrusted", Regex: `v(\d+\.\d+\.\d+)`}},
+			wantStatus: StatusError,
+			wantErr:    "restricted mode does not run checks in another directory (/tmp/untrusted)",
+		},
+		{
+			name:       "git config",
+			tool:       manifest.ToolDefinition{ID: "git-identity", Check: manifest.CheckConfig{Type: manifest.CheckTypeGitConfig, GitConfig: map[string]string{"user.email": "@"}}},
+			wantStatus: StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &fakeRunner{
+				installed: map[string]string{"node": "/usr/bin/node", "curl": "/usr/bin/curl", "go": "/usr/local/go/bin/go", "yarn": "/usr/bin/yarn", "/tmp/evil/go": "/tmp/evil/go", "./node": "./node", "git": "/usr/bin/git"},
+				outputs:   map[string]string{"node --version": "v20.11.0\n", "go version": "go version go1.22.1 linux/amd64\n", "git config --get user.email": "dev@example.com\n"},
+			}
+			toolChecker := NewChecker()
+			toolChecker.SetRunner(runner)
+			toolChecker.SetRestricted(true)
+
+			result := toolChecker.CheckTool(tt.tool, platformInfo)
+			if result.Status != tt.wantStatus {
+				t.Fatalf("Expected %v, got %v: %s", tt.wantStatus, result.Status, result.ErrorMessage)
+			}
+			if !strings.Contains(result.ErrorMessage, tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %q", tt.wantErr, result.ErrorMessage)
+			}
+			if tt.wantErr != "" && len(runner.ran) > 0 {
+				t.Errorf("Expected nothing to run, ran %q", runner.ran)
+			}
+
+			// The dry run refuses exactly what the check refuses
+			plan := toolChecker.Plan(tt.tool, platformInfo)
+			if !strings.Contains(plan.Error, tt.wantErr) || (tt.wantErr == "") != (plan.Error == "") {
+				t.Errorf("Expected a plan error containing %q, got %q", tt.wantErr, plan.Error)
+			}
+		})
+	}
+}
+
+func TestCheckToolAuditLog(t *testing.T) {
+	runner := &fakeRunner{
+		installed: map[string]string{"node": "/usr/bin/node", "git": "/usr/bin/git"},
+		outputs:   map[string]string{"node --version": "v20.11.0\n"},
+	}
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	var buf bytes.Buffer
+	toolChecker := NewChecker()
+	toolChecker.SetRunner(runner)
+	toolChecker.SetAuditLog(NewAuditLog(&buf))
+
+	toolChecker.CheckTool(manifest.ToolDefinition{ID: "node", Check: manifest.CheckConfig{Command: []string{"node", "--version"}, Workdir: "web", Regex: `v(\d+\.\d+\.\d+)`}}, platformInfo)
+	toolChecker.CheckTool(manifest.ToolDefinition{ID: "git-email", Check: manifest.CheckConfig{Type: manifest.CheckTypeGitConfig, GitConfig: map[string]string{"user.email": ".+"}}}, platformInfo)
+
+	var entries []AuditEntry
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid audit line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	want := []struct {
+		toolID, command, workdir, err string
+		exitCode                      int
+	}{
+		{toolID: "node", command: "node --version", workdir: "web"},
+		{toolID: "git-email", command: "git config --get user.email", err: "exit status 127", exitCode: -1},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d audit entries, got %+v", len(want), entries)
+	}
+	for i, w := range want {
+		entry := entries[i]
+		if entry.ToolID != w.toolID || strings.Join(entry.Command, " ") != w.command || entry.Workdir != w.workdir || entry.Error != w.err || entry.ExitCode != w.exitCode {
+			t.Errorf("Entry %d = %+v, want %+v", i, entry, w)
+		}
+		if entry.Target != "fake://box" || entry.Time.IsZero() {
+			t.Errorf("Entry %d has target %q and time %v", i, entry.Target, entry.Time)
+		}
+	}
+}
@@ -1,11 +1,18 @@
 package checker
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/semver"
 )
 
+// ReportSchemaVersion is the schema version of generated environment reports
+const ReportSchemaVersion = 1
+
 // CheckStatus represents the possible states of a tool check
 type CheckStatus int
 
@@ -16,6 +23,7 @@ const (
 	StatusOutdated
 	StatusError
 	StatusNotFound // Alias for StatusMissing for backwards compatibility
+	StatusSkipped  // Not checked on purpose; SkipReason says why
 )
 
 // ErrorType represents different categories of check errors
@@ -27,8 +35,29 @@ const (
 	ErrorTypeParsing
 	ErrorTypeTimeout
 	ErrorTypeVersionMismatch
+	ErrorTypeChecksum
 )
 
+// String returns the name of the error type reported as error_type in JSON
+func (et ErrorType) String() string {
+	switch et {
+	case ErrorTypeConfiguration:
+		return "configuration"
+	case ErrorTypeExecution:
+		return "execution"
+	case ErrorTypeParsing:
+		return "parsing"
+	case ErrorTypeTimeout:
+		return "timeout"
+	case ErrorTypeVersionMismatch:
+		return "version_mismatch"
+	case ErrorTypeChecksum:
+		return "checksum"
+	default:
+		return "unknown"
+	}
+}
+
 // String returns the string representation of the check status
 func (cs CheckStatus) String() string {
 	switch cs {
@@ -42,6 +71,8 @@ func (cs CheckStatus) String() string {
 		return "outdated"
 	case StatusError:
 		return "error"
+	case StatusSkipped:
+		return "skipped"
 	case StatusUnknown:
 		return "unknown"
 	default:
@@ -49,16 +80,98 @@ func (cs CheckStatus) String() string {
 	}
 }
 
+// ParseCheckStatus converts a status string produced by String back into a CheckStatus
+func ParseCheckStatus(s string) (CheckStatus, error) {
+	switch s {
+	case "ok":
+		return StatusOK, nil
+	case "missing":
+		return StatusMissing, nil
+	case "not_found":
+		return StatusNotFound, nil
+	case "outdated":
+		return StatusOutdated, nil
+	case "error":
+		return StatusError, nil
+	case "skipped":
+		return StatusSkipped, nil
+	case "unknown":
+		return StatusUnknown, nil
+	default:
+		return StatusUnknown, fmt.Errorf("unknown check status: %s", s)
+	}
+}
+
+// MarshalJSON encodes the status as its string representation
+func (cs CheckStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cs.String())
+}
+
+// UnmarshalJSON decodes a status string, also accepting the numeric form
+// written by older versions of goctor
+func (cs *CheckStatus) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		*cs = CheckStatus(number)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid check status: %s", data)
+	}
+
+	status, err := ParseCheckStatus(s)
+	if err != nil {
+		return err
+	}
+	*cs = status
+	return nil
+}
+
+// Milliseconds is a duration encoded in JSON as a whole number of milliseconds
+type Milliseconds time.Duration
+
+// MarshalJSON encodes the duration rounded to milliseconds
+func (ms Milliseconds) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(ms).Round(time.Millisecond).Milliseconds())
+}
+
+// UnmarshalJSON decodes a number of milliseconds
+func (ms *Milliseconds) UnmarshalJSON(data []byte) error {
+	var millis int64
+	if err := json.Unmarshal(data, &millis); err != nil {
+		return fmt.Errorf("invalid duration in milliseconds: %s", data)
+	}
+	*ms = Milliseconds(time.Duration(millis) * time.Millisecond)
+	return nil
+}
+
 // CheckError represents an error that occurred during tool checking
 type CheckError struct {
 	Message string
 	Type    ErrorType
+	// Mismatch explains a version mismatch, if known
+	Mismatch *semver.Mismatch
+	// Cause is the error the check error was made from, if any
+	Cause error
 }
 
 func (ce CheckError) Error() string {
 	return ce.Message
 }
 
+// Unwrap returns the cause of the check error
+func (ce CheckError) Unwrap() error {
+	return ce.Cause
+}
+
+// isTimeout returns true if err is, or wraps, a check error of a command that ran out of time
+func isTimeout(err error) bool {
+	var checkErr CheckError
+	return errors.As(err, &checkErr) && checkErr.Type == ErrorTypeTimeout
+}
+
 // NewCheckError creates a new CheckError with the specified message and type
 func NewCheckError(message string, errorType ErrorType) CheckError {
 	return CheckError{
@@ -69,41 +182,84 @@ func NewCheckError(message string, errorType ErrorType) CheckError {
 
 // CheckResult represents the outcome of verifying a single tool installation
 type CheckResult struct {
-	ToolID          string            `json:"id"`
-	ToolName        string            `json:"name"`
-	Status          CheckStatus       `json:"status"`
-	RequiredVersion string            `json:"required"`
-	ActualVersion   string            `json:"actual_version"`
-	CommandPath     string            `json:"command_path,omitempty"`
-	ErrorMessage    string            `json:"error_message,omitempty"`
-	Platform        string            `json:"platform"`
-	Links           map[string]string `json:"links"`
-	CheckDuration   time.Duration     `json:"check_duration,omitempty"`
+	ToolID             string                    `json:"id"`
+	ToolName           string                    `json:"name"`
+	Status             CheckStatus               `json:"status"`
+	RequiredVersion    string                    `json:"required"`
+	ActualVersion      string                    `json:"actual_version"`
+	VersionGroup       string                    `json:"version_group,omitempty"` // Regex capture group ActualVersion came from
+	RecommendedVersion string                    `json:"recommended,omitempty"`
+	BelowRecommended   bool                      `json:"below_recommended,omitempty"`
+	Deprecated         bool                      `json:"deprecated,omitempty"`
+	Blocked            *manifest.ExcludedVersion `json:"blocked,omitempty"` // exclude_versions entry the version matched
+	Sunset             string                    `json:"sunset,omitempty"`
+	CommandPath        string                    `json:"command_path,omitempty"`
+	ResolvedCommand    string                    `json:"resolved_command,omitempty"`
+	ManagedBy          string                    `json:"managed_by,omitempty"`
+	ErrorMessage       string                    `json:"error_message,omitempty"`
+	ErrorType          string                    `json:"error_type,omitempty"`  // Why the check errored, e.g. timeout
+	SkipReason         string                    `json:"skip_reason,omitempty"` // Why a skipped tool was not checked
+	Platform           string                    `json:"platform"`
+	Links              map[string]string         `json:"links"`
+	CheckDuration      Milliseconds              `json:"check_duration_ms,omitempty"`
+	Informational      bool                      `json:"informational,omitempty"`
+	Severity           string                    `json:"severity,omitempty"`
+	Scope              string                    `json:"scope,omitempty"` // machine or project, as the manifest says
+	InstallHint        string                    `json:"install_hint,omitempty"`
+	ConstraintFailure  *semver.Mismatch          `json:"constraint_failure,omitempty"`
+	SubChecks          []SubCheckResult          `json:"sub_checks,omitempty"`
+	Variants           []SubCheckResult          `json:"variants,omitempty"`
+	Source             string                    `json:"source,omitempty"`
+	Workspace          string                    `json:"workspace,omitempty"`
+	Trace              []TraceStep               `json:"trace,omitempty"`  // How the check went, in verbose mode
+	Output             string                    `json:"output,omitempty"` // Sanitized RawOutput, when captured
+	RawOutput          string                    `json:"-"`
+}
+
+// SubCheckResult is the outcome of one named sub-check or variant of a tool
+type SubCheckResult struct {
+	Name            string      `json:"name"`
+	Status          CheckStatus `json:"status"`
+	RequiredVersion string      `json:"required,omitempty"`
+	ActualVersion   string      `json:"actual,omitempty"`
+	CommandPath     string      `json:"command_path,omitempty"`
+	ErrorMessage    string      `json:"error,omitempty"`
 }
 
 // EnvironmentReport represents a comprehensive summary of all tool checks
 type EnvironmentReport struct {
-	SchemaVersion  int           `json:"schema_version"`
-	Platform       interface{}   `json:"platform"` // Use interface{} to avoid circular import
-	Summary        CheckSummary  `json:"summary"`
-	ManifestSource string        `json:"manifest_source"`
-	Items          []CheckResult `json:"items"`
-	GeneratedAt    time.Time     `json:"generated_at"`
+	SchemaVersion  int          `json:"schema_version"`
+	Platform       interface{}  `json:"platform"` // Use interface{} to avoid circular import
+	Summary        CheckSummary `json:"summary"`
+	ManifestSource string       `json:"manifest_source"`
+	// ManifestSources lists every manifest of a layered run, lowest precedence first;
+	// ManifestSource then joins them
+	ManifestSources []string      `json:"manifest_sources,omitempty"`
+	Language        string        `json:"language,omitempty"` // The manifest's meta.language
+	Items           []CheckResult `json:"items"`
+	GeneratedAt     time.Time     `json:"generated_at"`
 }
 
 // CheckSummary provides statistical summary of tool verification results
 type CheckSummary struct {
-	Total    int `json:"total"`
-	OK       int `json:"ok"`
-	Missing  int `json:"missing"`
-	Outdated int `json:"outdated"`
-	Errors   int `json:"errors"`
+	Total         int `json:"total"`
+	OK            int `json:"ok"`
+	Missing       int `json:"missing"`
+	Outdated      int `json:"outdated"`
+	Errors        int `json:"errors"`
+	Skipped       int `json:"skipped"`
+	Informational int `json:"informational"`
+	Warnings      int `json:"warnings"`
 }
 
 // Validate performs validation of the check result
 func (cr *CheckResult) Validate() error {
 	// Check required fields
-	if cr.ToolID == "" || cr.ToolName == "" || cr.RequiredVersion == "" {
+	if cr.ToolID == "" || cr.ToolName == "" {
+		return errors.New("required fields cannot be empty")
+	}
+
+	if cr.RequiredVersion == "" && !cr.Informational {
 		return errors.New("required fields cannot be empty")
 	}
 
@@ -129,6 +285,10 @@ func (cr *CheckResult) Validate() error {
 		if cr.ErrorMessage == "" {
 			return errors.New("Error status must have error message")
 		}
+	case StatusSkipped:
+		if cr.SkipReason == "" {
+			return errors.New("Skipped status must have skip reason")
+		}
 	}
 
 	return nil
@@ -159,6 +319,30 @@ func (cr *CheckResult) AddError(errMsg string) {
 	cr.Status = StatusError
 }
 
+// Fail marks the result as errored with message, recording the type of err when it is a
+// CheckError so reports can tell timeouts from other failures
+func (cr *CheckResult) Fail(message string, err error) {
+	cr.AddError(message)
+	var checkErr CheckError
+	if errors.As(err, &checkErr) {
+		cr.ErrorType = checkErr.Type.String()
+	}
+}
+
+// Skip marks the result as not checked for the given reason
+func (cr *CheckResult) Skip(reason string) {
+	cr.SkipReason = reason
+	cr.Status = StatusSkipped
+}
+
+// NeedsAttention returns true if the result should appear in remediation output
+func (cr *CheckResult) NeedsAttention() bool {
+	if cr.Informational || cr.Status == StatusSkipped {
+		return false
+	}
+	return cr.Status != StatusOK || cr.BelowRecommended || cr.Deprecated
+}
+
 // HasErrors returns true if the check result has any errors
 func (cr *CheckResult) HasErrors() bool {
 	return cr.ErrorMessage != ""
@@ -166,7 +350,7 @@ func (cr *CheckResult) HasErrors() bool {
 
 // Validate performs validation of the environment report
 func (er *EnvironmentReport) Validate() error {
-	if er.SchemaVersion != 1 {
+	if er.SchemaVersion != ReportSchemaVersion {
 		return fmt.Errorf("unsupported schema version: %d", er.SchemaVersion)
 	}
 
@@ -183,7 +367,8 @@ func (er *EnvironmentReport) Validate() error {
 		return errors.New("summary total mismatch")
 	}
 
-	calculatedTotal := er.Summary.OK + er.Summary.Missing + er.Summary.Outdated + er.Summary.Errors
+	calculatedTotal := er.Summary.OK + er.Summary.Missing + er.Summary.Outdated + er.Summary.Errors +
+		er.Summary.Skipped + er.Summary.Informational + er.Summary.Warnings
 	if calculatedTotal != er.Summary.Total {
 		return errors.New("summary counts don't add up to total")
 	}
@@ -205,7 +390,6 @@ func (er *EnvironmentReport) Validate() error {
 	return nil
 }
 
-
 // CalculateCheckSummary calculates summary statistics from check results
 func CalculateCheckSummary(items []CheckResult) CheckSummary {
 	summary := CheckSummary{
@@ -213,10 +397,40 @@ func CalculateCheckSummary(items []CheckResult) CheckSummary {
 	}
 
 	for _, item := range items {
+		// Skipped tools were never checked, so they neither pass nor fail
+		if item.Status == StatusSkipped {
+			summary.Skipped++
+			continue
+		}
+
+		// Informational tools are reported but excluded from pass/fail accounting
+		if item.Informational {
+			summary.Informational++
+			continue
+		}
+
+		// Failures of warning-severity tools are reported without failing the run
+		if item.Severity == manifest.SeverityWarning && item.Status != StatusOK {
+			summary.Warnings++
+			continue
+		}
+
+		// Passing the minimum but not the recommended tier is a soft-upgrade nudge
+		if item.Status == StatusOK && item.BelowRecommended {
+			summary.Warnings++
+			continue
+		}
+
+		// Deprecated tools that are still installed should be phased out, but never fail the run
+		if item.Deprecated {
+			summary.Warnings++
+			continue
+		}
+
 		switch item.Status {
 		case StatusOK:
 			summary.OK++
-		case StatusMissing:
+		case StatusMissing, StatusNotFound:
 			summary.Missing++
 		case StatusOutdated:
 			summary.Outdated++
@@ -228,12 +442,28 @@ func CalculateCheckSummary(items []CheckResult) CheckSummary {
 	return summary
 }
 
+// SlowChecks returns the results whose check took longer than threshold, in order; a
+// threshold of zero reports none
+func SlowChecks(items []CheckResult, threshold time.Duration) []CheckResult {
+	if threshold <= 0 {
+		return nil
+	}
+
+	var slow []CheckResult
+	for _, item := range items {
+		if time.Duration(item.CheckDuration) > threshold {
+			slow = append(slow, item)
+		}
+	}
+	return slow
+}
+
 // NewEnvironmentReport creates a new environment report with current timestamp
 func NewEnvironmentReport(platform interface{}, manifestSource string, items []CheckResult) *EnvironmentReport {
 	summary := CalculateCheckSummary(items)
 
 	return &EnvironmentReport{
-		SchemaVersion:  1,
+		SchemaVersion:  ReportSchemaVersion,
 		Platform:       platform,
 		Summary:        summary,
 		ManifestSource: manifestSource,
@@ -247,10 +477,11 @@ func (er *EnvironmentReport) IsSuccessful() bool {
 	return er.Summary.Missing == 0 && er.Summary.Outdated == 0 && er.Summary.Errors == 0
 }
 
-// GetExitCode returns the appropriate exit code for the report
-func (er *EnvironmentReport) GetExitCode() int {
-	if er.IsSuccessful() {
-		return 0
+// GetExitCode returns the appropriate exit code for the report under the given policy,
+// or DefaultExitPolicy if none is given
+func (er *EnvironmentReport) GetExitCode(policy ...ExitPolicy) int {
+	if len(policy) > 0 {
+		return policy[0].exitCode(er.Summary)
 	}
-	return 1
-}
\ No newline at end of file
+	return DefaultExitPolicy.exitCode(er.Summary)
+}
@@ -1,6 +1,9 @@
 package checker
 
 import (
+	"encoding/json"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -106,7 +109,7 @@ func TestCheckResultValidation(t *testing.T) {
 				Links: map[string]string{
 					"homepage": "https://go.dev/",
 				},
-				CheckDuration: time.Millisecond * 100,
+				CheckDuration: Milliseconds(100 * time.Millisecond),
 			},
 			expectError: false,
 		},
@@ -204,6 +207,7 @@ func TestCheckStatusString(t *testing.T) {
 		{StatusMissing, "missing"},
 		{StatusOutdated, "outdated"},
 		{StatusError, "error"},
+		{StatusSkipped, "skipped"},
 		{StatusUnknown, "unknown"},
 	}
 
@@ -361,3 +365,160 @@ func TestCheckSummaryCalculation(t *testing.T) {
 	}
 }
 
+func TestCheckSummaryExcludesInformational(t *testing.T) {
+	items := []CheckResult{
+		{Status: StatusOK},
+		{Status: StatusNotFound, Informational: true},
+		{Status: StatusOK, Informational: true},
+	}
+
+	summary := CalculateCheckSummary(items)
+
+	expected := CheckSummary{
+		Total:         3,
+		OK:            1,
+		Informational: 2,
+	}
+
+	if summary != expected {
+		t.Errorf("Expected summary %+v, got %+v", expected, summary)
+	}
+
+	report := EnvironmentReport{Summary: summary}
+	if !report.IsSuccessful() {
+		t.Error("Expected informational failures not to affect success")
+	}
+}
+
+func TestCheckSummaryCountsBelowRecommendedAsWarning(t *testing.T) {
+	items := []CheckResult{
+		{Status: StatusOK},
+		{Status: StatusOK, RecommendedVersion: ">=1.23", BelowRecommended: true},
+	}
+
+	summary := CalculateCheckSummary(items)
+
+	expected := CheckSummary{
+		Total:    2,
+		OK:       1,
+		Warnings: 1,
+	}
+
+	if summary != expected {
+		t.Errorf("Expected summary %+v, got %+v", expected, summary)
+	}
+
+	report := EnvironmentReport{Summary: summary}
+	if !report.IsSuccessful() {
+		t.Error("Expected recommended-tier failures not to affect success")
+	}
+
+	if !items[1].NeedsAttention() {
+		t.Error("Expected result below recommended version to need attention")
+	}
+}
+
+func TestCheckSummaryCountsDeprecatedAsWarning(t *testing.T) {
+	items := []CheckResult{
+		{Status: StatusOK},
+		{Status: StatusOK, Deprecated: true},
+		{Status: StatusOutdated, Deprecated: true},
+	}
+
+	summary := CalculateCheckSummary(items)
+
+	expected := CheckSummary{
+		Total:    3,
+		OK:       1,
+		Warnings: 2,
+	}
+
+	if summary != expected {
+		t.Errorf("Expected summary %+v, got %+v", expected, summary)
+	}
+
+	if !items[1].NeedsAttention() {
+		t.Error("Expected an installed deprecated tool to need attention")
+	}
+}
+
+func TestCheckSummaryCountsSkipped(t *testing.T) {
+	items := []CheckResult{
+		{Status: StatusOK},
+		{Status: StatusSkipped, SkipReason: "dependency failed: docker"},
+		{Status: StatusSkipped, SkipReason: "not supported on linux/amd64", Informational: true},
+	}
+
+	summary := CalculateCheckSummary(items)
+
+	expected := CheckSummary{
+		Total:   3,
+		OK:      1,
+		Skipped: 2,
+	}
+
+	if summary != expected {
+		t.Errorf("Expected summary %+v, got %+v", expected, summary)
+	}
+
+	report := EnvironmentReport{Summary: summary}
+	if !report.IsSuccessful() {
+		t.Error("Expected skipped tools not to affect success")
+	}
+
+	if items[1].NeedsAttention() {
+		t.Error("Expected a skipped tool not to need attention")
+	}
+
+	if err := (&CheckResult{ToolID: "docker", ToolName: "Docker", RequiredVersion: ">=24", Links: map[string]string{"docs": "x"}, Status: StatusSkipped}).Validate(); err == nil {
+		t.Error("Expected a skipped result without a reason to be invalid")
+	}
+}
+
+func TestSlowChecks(t *testing.T) {
+	items := []CheckResult{
+		{ToolID: "go", CheckDuration: Milliseconds(200 * time.Millisecond)},
+		{ToolID: "docker", CheckDuration: Milliseconds(4 * time.Second)},
+		{ToolID: "java", CheckDuration: Milliseconds(2 * time.Second)},
+	}
+
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		want      []string
+	}{
+		{"over threshold", time.Second, []string{"docker", "java"}},
+		{"equal to threshold is not slow", 2 * time.Second, []string{"docker"}},
+		{"disabled", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, item := range SlowChecks(items, tt.threshold) {
+				got = append(got, item.ToolID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SlowChecks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMillisecondsJSON(t *testing.T) {
+	data, err := json.Marshal(CheckResult{ToolID: "go", CheckDuration: Milliseconds(1500*time.Microsecond + 400*time.Microsecond)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), `"check_duration_ms":2`) {
+		t.Errorf("Expected the duration in milliseconds, got %s", data)
+	}
+
+	var result CheckResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.CheckDuration != Milliseconds(2*time.Millisecond) {
+		t.Errorf("Expected 2ms after a round trip, got %v", time.Duration(result.CheckDuration))
+	}
+}
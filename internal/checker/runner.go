@@ -0,0 +1,132 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+// Runner executes check commands on the machine being checked
+// The local machine is the default; other runners reach remote hosts so the same manifest
+// can validate a dev box or build agent
+type Runner interface {
+	// LookPath returns the path of an executable on the target's PATH and whether it was found
+	LookPath(ctx context.Context, name string) (string, bool, error)
+	// Run executes a command in workdir, when set, with extra environment variables and
+	// returns its combined output
+	Run(ctx context.Context, command []string, workdir string, env map[string]string) (string, error)
+	// String describes the target for messages
+	String() string
+}
+
+// waitDelay bounds how long the output of a command killed at its timeout is waited for;
+// children that inherited its output would otherwise keep the check running past its budget
+const waitDelay = time.Second
+
+// LocalRunner runs commands on this machine
+type LocalRunner struct{}
+
+// LookPath resolves the command against PATH directly; `command -v` is a shell builtin
+// and cannot be executed without a shell
+func (LocalRunner) LookPath(ctx context.Context, name string) (string, bool, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		// Command not found is expected for missing tools
+		return "", false, nil
+	}
+	return path, true, nil
+}
+
+// Run executes the command as a child process
+func (LocalRunner) Run(ctx context.Context, command []string, workdir string, env map[string]string) (string, error) {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.WaitDelay = waitDelay
+	if workdir != "" {
+		if info, err := os.Stat(workdir); err != nil || !info.IsDir() {
+			return "", NewCheckError("working directory not found: "+workdir, ErrorTypeConfiguration)
+		}
+		cmd.Dir = workdir
+	}
+	if len(env) > 0 {
+		cmd.Env = os.Environ()
+		for name, value := range env {
+			cmd.Env = append(cmd.Env, name+"="+value)
+		}
+	}
+
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+func (LocalRunner) String() string { return "local" }
+
+// isLocal returns true if the runner executes commands on this machine
+func isLocal(runner Runner) bool {
+	_, ok := runner.(LocalRunner)
+	return ok
+}
+
+// TargetUsage describes the accepted --target values
+const TargetUsage = "ssh://[USER@]HOST[:PORT] or docker://IMAGE|CONTAINER"
+
+// ParseTarget returns the runner for a --target URL
+func ParseTarget(target string) (Runner, error) {
+	// Image references are not URLs: registry/name:tag has no port
+	if reference, ok := strings.CutPrefix(target, "docker://"); ok {
+		return NewDockerRunner(reference)
+	}
+
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid target %q (expected %s)", target, TargetUsage)
+	}
+
+	switch u.Scheme {
+	case "ssh":
+		return NewSSHRunner(u)
+	default:
+		return nil, fmt.Errorf("unsupported target scheme %q (expected %s)", u.Scheme, TargetUsage)
+	}
+}
+
+// DetectPlatform identifies the platform a runner executes on with `uname -sm`, along with
+// its host name, home directory, and package managers
+func DetectPlatform(ctx context.Context, runner Runner) (platform.PlatformInfo, error) {
+	output, err := runner.Run(ctx, []string{"uname", "-sm"}, "", nil)
+	if err != nil {
+		return platform.PlatformInfo{}, fmt.Errorf("running uname on %s: %v", runner, runnerFailure(err, output))
+	}
+
+	goos, arch, err := platform.ParseUname(output)
+	if err != nil {
+		return platform.PlatformInfo{}, err
+	}
+
+	info := platform.PlatformInfo{OS: goos, Architecture: arch}
+	if hostname, err := runner.Run(ctx, []string{"uname", "-n"}, "", nil); err == nil {
+		info.Hostname = strings.TrimSpace(hostname)
+	}
+	if home, err := runner.Run(ctx, []string{"printenv", "HOME"}, "", nil); err == nil {
+		info.Home = strings.TrimSpace(home)
+	}
+	info.PackageManagers = platform.DetectPackageManagersWith(func(binary string) bool {
+		_, found, _ := runner.LookPath(ctx, binary)
+		return found
+	})
+
+	return info, nil
+}
+
+// runnerFailure adds the last line of a failed command's output to its error
+func runnerFailure(err error, output string) error {
+	if line := lastLine(output); line != "" && !strings.Contains(err.Error(), line) {
+		return fmt.Errorf("%v (%s)", err, line)
+	}
+	return err
+}
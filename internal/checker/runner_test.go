@@ -0,0 +1,187 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+// fakeRunner answers commands from a table keyed by the space-joined command
+type fakeRunner struct {
+	installed map[string]string
+	outputs   map[string]string
+	ran       []string
+}
+
+func (f *fakeRunner) LookPath(ctx context.Context, name string) (string, bool, error) {
+	path, ok := f.installed[name]
+	return path, ok, nil
+}
+
+func (f *fakeRunner) Run(ctx context.Context, command []string, workdir string, env map[string]string) (string, error) {
+	key := strings.Join(command, " ")
+	f.ran = append(f.ran, key)
+	output, ok := f.outputs[key]
+	if !ok {
+		return "", errors.New("exit status 127")
+	}
+	return output, nil
+}
+
+func (f *fakeRunner) String() string { return "fake://box" }
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		want    string
+		wantErr string
+	}{
+		{target: "ssh://dev@build-01", want: "ssh://dev@build-01"},
+		{target: "ssh://build-01:2222", want: "ssh://build-01:2222"},
+		{target: "build-01", wantErr: "invalid target"},
+		{target: "ssh://dev@build-01/home", wantErr: "paths are not supported"},
+		{target: "ssh://-oProxyCommand=id", wantErr: "cannot start with -"},
+		{target: "ssh://-oProxyCommand=id@build-01", wantErr: "cannot start with -"},
+		{target: "docker://golang:1.22", want: "docker://golang:1.22"},
+		{target: "docker://ghcr.io/acme/ci-base:2024.10", want: "docker://ghcr.io/acme/ci-base:2024.10"},
+		{target: "docker://", wantErr: "invalid docker target"},
+		{target: "docker://--privileged", wantErr: "cannot start with -"},
+		{target: "docker://-v=/:/host", wantErr: "cannot start with -"},
+		{target: "telnet://build-01", wantErr: "unsupported target scheme"},
+	}
+
+	for _, tt := range tests {
+		runner, err := ParseTarget(tt.target)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseTarget(%q): expected error containing %q, got %v", tt.target, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTarget(%q): unexpected error: %v", tt.target, err)
+			continue
+		}
+		if runner.String() != tt.want {
+			t.Errorf("ParseTarget(%q) = %s, want %s", tt.target, runner, tt.want)
+		}
+	}
+}
+
+func TestRemoteScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  []string
+		workdir  string
+		env      map[string]string
+		expected string
+	}{
+		{name: "plain command", command: []string{"go", "version"}, expected: "go version"},
+		{name: "quoted arguments", command: []string{"/bin/sh", "-c", "node --version | head -1"}, expected: "/bin/sh -c 'node --version | head -1'"},
+		{name: "single quotes", command: []string{"echo", "it's"}, expected: `echo 'it'\''s'`},
+		{
+			name:     "workdir and env",
+			command:  []string{"terraform", "version"},
+			workdir:  "infra dir",
+			env:      map[string]string{"TF_LOG": "", "A": "1"},
+			expected: "cd 'infra dir' && env A=1 TF_LOG= terraform version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := remoteScript(tt.command, tt.workdir, tt.env); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSSHRunnerArgs(t *testing.T) {
+	runner := &SSHRunner{Destination: "dev@build-01", Port: "2222"}
+	args := runner.args("go version")
+	if got := strings.Join(args[len(args)-5:], " "); got != "-p 2222 -- dev@build-01 go version" {
+		t.Errorf("Unexpected ssh arguments: %s", got)
+	}
+}
+
+func TestDockerRunnerArgs(t *testing.T) {
+	env := map[string]string{"B": "2", "A": "1"}
+
+	image := &DockerRunner{Reference: "golang:1.22"}
+	got := strings.Join(image.args([]string{"go", "version"}, "/src", env), " ")
+	if got != "run --rm --entrypoint  --workdir /src --env A=1 --env B=2 golang:1.22 go version" {
+		t.Errorf("Unexpected image arguments: %s", got)
+	}
+
+	container := &DockerRunner{Reference: "ci-agent", container: true}
+	got = strings.Join(container.args([]string{"go", "version"}, "", nil), " ")
+	if got != "exec ci-agent go version" {
+		t.Errorf("Unexpected container arguments: %s", got)
+	}
+}
+
+func TestDetectPlatform(t *testing.T) {
+	runner := &fakeRunner{
+		installed: map[string]string{"apt-get": "/usr/bin/apt-get"},
+		outputs: map[string]string{
+			"uname -sm":     "Linux aarch64\n",
+			"uname -n":      "build-01\n",
+			"printenv HOME": "/home/ci\n",
+		},
+	}
+
+	info, err := DetectPlatform(context.Background(), runner)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.OS != "linux" || info.Architecture != "arm64" || info.Hostname != "build-01" || info.Home != "/home/ci" {
+		t.Errorf("Unexpected platform: %+v", info)
+	}
+	if len(info.PackageManagers) != 1 || info.PackageManagers[0] != "apt" {
+		t.Errorf("Expected apt to be detected, got %v", info.PackageManagers)
+	}
+
+	if _, err := DetectPlatform(context.Background(), &fakeRunner{}); err == nil {
+		t.Error("Expected an error when uname fails")
+	}
+}
+
+func TestCheckToolWithRunner(t *testing.T) {
+	runner := &fakeRunner{
+		installed: map[string]string{"go": "/usr/local/go/bin/go"},
+		outputs:   map[string]string{"go version": "go version go1.22.1 linux/arm64"},
+	}
+	toolChecker := NewChecker()
+	toolChecker.SetRunner(runner)
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "arm64"}
+
+	tool := manifest.ToolDefinition{
+		ID:              "go",
+		Name:            "Go",
+		RequiredVersion: ">=1.22",
+		Check:           manifest.CheckConfig{Command: []string{"go", "version"}, Regex: `go(?P<ver>\d+\.\d+\.\d+)`},
+	}
+	result := toolChecker.CheckTool(tool, platformInfo)
+	if result.Status != StatusOK || result.ActualVersion != "1.22.1" || result.CommandPath != "/usr/local/go/bin/go" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	missing := toolChecker.CheckTool(manifest.ToolDefinition{ID: "node", Name: "Node", RequiredVersion: ">=20", Check: manifest.CheckConfig{Command: []string{"node", "--version"}, Regex: `v(?P<ver>\d+\.\d+\.\d+)`}}, platformInfo)
+	if missing.Status != StatusNotFound {
+		t.Errorf("Expected node to be missing on the target, got %v", missing.Status)
+	}
+
+	plugin := toolChecker.CheckTool(manifest.ToolDefinition{ID: "vpn", Name: "VPN", Check: manifest.CheckConfig{Plugin: "./check-vpn"}}, platformInfo)
+	if plugin.Status != StatusError || !strings.Contains(plugin.ErrorMessage, "fake://box") {
+		t.Errorf("Expected plugins to be rejected on a remote target, got %v (%s)", plugin.Status, plugin.ErrorMessage)
+	}
+
+	if strings.Join(runner.ran, ",") != "go version" {
+		t.Errorf("Expected only the go check to run on the target, got %v", runner.ran)
+	}
+}
@@ -0,0 +1,132 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+	"github.com/ikorihn/goctor/internal/scheduler"
+)
+
+// errBlockingFailure marks a scheduled check whose result fails the run
+var errBlockingFailure = errors.New("blocking check failed")
+
+// errNotPassed marks a scheduled check that did not pass without failing the run; it
+// still skips the tools that depend on it
+var errNotPassed = fmt.Errorf("check did not pass: %w", scheduler.ErrNonFatal)
+
+// CheckEvents receives notifications while CheckAll runs; calls are never concurrent
+type CheckEvents struct {
+	// OnPlan is called once with the tools about to be checked, before any check starts
+	OnPlan func(tools []manifest.ToolDefinition)
+	// OnStart is called when a tool's check begins
+	OnStart func(tool manifest.ToolDefinition)
+	// OnResult is called with each result as soon as its check finishes or is skipped
+	OnResult func(result CheckResult)
+}
+
+// CheckAll checks the tools through the scheduler and returns their results in input order
+// A tool's depends_on prerequisites are checked first, and the tool is skipped unless all
+// of them passed; prerequisites that are not part of this run are ignored
+// A check counts as failed for fail-fast purposes when it would fail the run; checks the
+// scheduler skips are reported as skipped with the scheduler's reason
+func (c *Checker) CheckAll(ctx context.Context, tools []manifest.ToolDefinition, platformInfo platform.PlatformInfo, opts scheduler.Options, events CheckEvents) ([]CheckResult, error) {
+	results := make([]CheckResult, len(tools))
+	tasks := make([]scheduler.Task, len(tools))
+
+	var mu sync.Mutex
+	notifyStart := func(tool manifest.ToolDefinition) {
+		if events.OnStart == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		events.OnStart(tool)
+	}
+	notify := func(result CheckResult) {
+		if events.OnResult == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		events.OnResult(result)
+	}
+
+	if events.OnPlan != nil {
+		events.OnPlan(tools)
+	}
+
+	inRun := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		inRun[tool.ID] = true
+	}
+
+	for i, tool := range tools {
+		var deps []string
+		for _, dep := range tool.DependsOn {
+			if inRun[dep] {
+				deps = append(deps, dep)
+			}
+		}
+
+		tasks[i] = scheduler.Task{
+			ID:        tool.ID,
+			DependsOn: deps,
+			Run: func(ctx context.Context) error {
+				notifyStart(tool)
+				results[i] = c.CheckTool(tool, platformInfo)
+				notify(results[i])
+				if isBlockingFailure(results[i]) {
+					return errBlockingFailure
+				}
+				if results[i].Status != StatusOK {
+					return errNotPassed
+				}
+				return nil
+			},
+		}
+	}
+
+	outcomes, err := scheduler.Run(ctx, tasks, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, outcome := range outcomes {
+		if !outcome.Skipped {
+			continue
+		}
+		results[i] = SkippedResult(tools[i], platformInfo, outcome.SkipReason)
+		notify(results[i])
+	}
+
+	return results, nil
+}
+
+// SkippedResult returns the result of a tool that is not checked for the given reason, such
+// as a platform it does not support or a prerequisite that failed
+func SkippedResult(tool manifest.ToolDefinition, platformInfo platform.PlatformInfo, reason string) CheckResult {
+	result := CheckResult{
+		ToolID:          tool.ID,
+		ToolName:        tool.Name,
+		RequiredVersion: tool.RequiredVersion,
+		Links:           tool.Links,
+		Platform:        platformInfo.String(),
+		Informational:   tool.Informational,
+		Severity:        tool.GetSeverity(),
+		Scope:           tool.Scope,
+	}
+	result.Skip(reason)
+	return result
+}
+
+// isBlockingFailure returns true if the result makes the run fail
+func isBlockingFailure(result CheckResult) bool {
+	if result.Informational || result.Severity == manifest.SeverityWarning {
+		return false
+	}
+	return result.Status != StatusOK
+}
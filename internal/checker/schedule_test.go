@@ -0,0 +1,124 @@
+package checker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+	"github.com/ikorihn/goctor/internal/scheduler"
+)
+
+func TestCheckAll(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	service := func(id, script string, severity string) manifest.ToolDefinition {
+		return manifest.ToolDefinition{
+			ID:       id,
+			Name:     id,
+			Severity: severity,
+			Check: manifest.CheckConfig{
+				Type:    manifest.CheckTypeService,
+				Command: []string{script},
+				Shell:   true,
+			},
+		}
+	}
+
+	tools := []manifest.ToolDefinition{
+		service("first", "true", ""),
+		service("soft", "false", manifest.SeverityWarning),
+		service("hard", "false", ""),
+		service("last", "true", ""),
+	}
+
+	tests := []struct {
+		name     string
+		opts     scheduler.Options
+		expected []CheckStatus
+		skipped  []bool
+	}{
+		{
+			name:     "parallel keeps input order",
+			opts:     scheduler.Options{Parallelism: 4},
+			expected: []CheckStatus{StatusOK, StatusError, StatusError, StatusOK},
+			skipped:  []bool{false, false, false, false},
+		},
+		{
+			name:     "fail-fast ignores warnings and stops after a blocking failure",
+			opts:     scheduler.Options{FailFast: true},
+			expected: []CheckStatus{StatusOK, StatusError, StatusError, StatusSkipped},
+			skipped:  []bool{false, false, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := NewChecker().CheckAll(context.Background(), tools, platformInfo, tt.opts, CheckEvents{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			for i, result := range results {
+				if result.ToolID != tools[i].ID {
+					t.Errorf("Expected result %d to be %s, got %s", i, tools[i].ID, result.ToolID)
+				}
+				if result.Status != tt.expected[i] {
+					t.Errorf("%s: expected status %v, got %v", result.ToolID, tt.expected[i], result.Status)
+				}
+				if skipped := result.SkipReason == scheduler.SkipFailFast; skipped != tt.skipped[i] {
+					t.Errorf("%s: expected skipped=%v, got skip reason %q", result.ToolID, tt.skipped[i], result.SkipReason)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckAllDependsOn(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	service := func(id, script string, dependsOn ...string) manifest.ToolDefinition {
+		return manifest.ToolDefinition{
+			ID:        id,
+			Name:      id,
+			Severity:  manifest.SeverityWarning,
+			DependsOn: dependsOn,
+			Check: manifest.CheckConfig{
+				Type:    manifest.CheckTypeService,
+				Command: []string{script},
+				Shell:   true,
+			},
+		}
+	}
+
+	tools := []manifest.ToolDefinition{
+		service("compose", "true", "docker"),
+		service("docker", "false"),
+		service("context", "true", "kubectl"),
+		service("kubectl", "true"),
+		service("buildx", "true", "compose"),
+		service("lint", "true", "not-in-this-run"),
+	}
+
+	results, err := NewChecker().CheckAll(context.Background(), tools, platformInfo, scheduler.Options{Parallelism: 2}, CheckEvents{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	skipped := map[string]string{
+		"compose": "dependency failed: docker",
+		"buildx":  "dependency failed: compose",
+	}
+	for _, result := range results {
+		want, ok := skipped[result.ToolID]
+		if !ok {
+			if result.Status == StatusSkipped {
+				t.Errorf("%s: expected check to run, got skipped: %q", result.ToolID, result.SkipReason)
+			}
+			continue
+		}
+		if result.Status != StatusSkipped || result.SkipReason != want {
+			t.Errorf("%s: expected skipped with %q, got %v %q", result.ToolID, want, result.Status, result.SkipReason)
+		}
+	}
+}
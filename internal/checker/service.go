@@ -0,0 +1,49 @@
+package checker
+
+import (
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// checkServiceHealth runs a service check's command and sets the result status
+// A failing command means the service is down; a version is only extracted and
+// validated when the check has a regex
+func (c *Checker) checkServiceHealth(tool manifest.ToolDefinition, result *CheckResult) {
+	output, err := c.runTraced(result, tool.CheckCommand(), tool.TimeoutSeconds, tool.Check.Workdir, tool.Check.Env)
+	result.RawOutput = output
+	if err != nil {
+		result.Fail("service is not healthy: "+err.Error(), err)
+		if line := lastLine(output); line != "" {
+			result.ErrorMessage += " (" + line + ")"
+		}
+		return
+	}
+
+	if tool.VersionRegex() == "" {
+		result.Status = StatusOK
+		return
+	}
+
+	version, group, err := c.parseVersionFromOutput(output, tool.VersionRegex(), tool.VersionRegexKey())
+	c.traceMatch(result, output, tool.VersionRegex(), version, group, err)
+	if err != nil {
+		result.Status = StatusError
+		result.ErrorMessage = "failed to parse version: " + err.Error()
+		return
+	}
+	result.ActualVersion = version
+	result.VersionGroup = group
+
+	if tool.RequiredVersion == "" {
+		result.Status = StatusOK
+		return
+	}
+	c.applyRequirements(tool, result)
+}
+
+// lastLine returns the last non-empty line of command output, which usually holds the reason it failed
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
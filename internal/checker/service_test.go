@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+func TestCheckToolWithService(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	tests := []struct {
+		name           string
+		script         string
+		regex          string
+		require        string
+		expectedStatus CheckStatus
+		expectedError  string
+		expectedVer    string
+	}{
+		{
+			name:           "healthy",
+			script:         "true",
+			expectedStatus: StatusOK,
+		},
+		{
+			name:           "unhealthy",
+			script:         "echo 'Cannot connect to the daemon' >&2; exit 1",
+			expectedStatus: StatusError,
+			expectedError:  "(Cannot connect to the daemon)",
+		},
+		{
+			name:           "healthy with version",
+			script:         "echo 27.1.0",
+			regex:          `(?P<ver>\d+\.\d+\.\d+)`,
+			require:        ">=24.0",
+			expectedStatus: StatusOK,
+			expectedVer:    "27.1.0",
+		},
+		{
+			name:           "healthy but outdated",
+			script:         "echo 20.10.0",
+			regex:          `(?P<ver>\d+\.\d+\.\d+)`,
+			require:        ">=24.0",
+			expectedStatus: StatusOutdated,
+			expectedVer:    "20.10.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := manifest.ToolDefinition{
+				ID:              "daemon",
+				Name:            "Daemon",
+				RequiredVersion: tt.require,
+				Check: manifest.CheckConfig{
+					Type:    manifest.CheckTypeService,
+					Command: []string{tt.script},
+					Shell:   true,
+					Regex:   tt.regex,
+				},
+			}
+
+			result := NewChecker().CheckTool(tool, platformInfo)
+
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Expected status %v, got %v (%s)", tt.expectedStatus, result.Status, result.ErrorMessage)
+			}
+			if tt.expectedError != "" && !strings.Contains(result.ErrorMessage, tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %q", tt.expectedError, result.ErrorMessage)
+			}
+			if result.ActualVersion != tt.expectedVer {
+				t.Errorf("Expected version %q, got %q", tt.expectedVer, result.ActualVersion)
+			}
+		})
+	}
+}
+
+func TestCheckToolWithServiceProbeNotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	tool := manifest.ToolDefinition{
+		ID:    "docker-daemon",
+		Name:  "Docker daemon",
+		Check: manifest.CheckConfig{Type: manifest.CheckTypeService, Probe: "docker"},
+	}
+
+	result := NewChecker().CheckTool(tool, platform.PlatformInfo{OS: "linux", Architecture: "amd64"})
+	if result.Status != StatusNotFound {
+		t.Errorf("Expected not found when the probe's CLI is missing, got %v (%s)", result.Status, result.ErrorMessage)
+	}
+}
@@ -0,0 +1,69 @@
+package checker
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// versionManager describes a version manager whose shims or installs can be recognized by path
+type versionManager struct {
+	Name        string
+	PathMarkers []string
+	ExecPrefix  []string
+}
+
+// knownVersionManagers lists the version managers goctor can recognize
+var knownVersionManagers = []versionManager{
+	{Name: "asdf", PathMarkers: []string{"/.asdf/shims/", "/asdf/shims/"}, ExecPrefix: []string{"asdf", "exec"}},
+	{Name: "mise", PathMarkers: []string{"/mise/shims/", "/mise/installs/", "/rtx/shims/"}, ExecPrefix: []string{"mise", "exec", "--"}},
+	{Name: "pyenv", PathMarkers: []string{"/.pyenv/shims/"}, ExecPrefix: []string{"pyenv", "exec"}},
+	{Name: "rbenv", PathMarkers: []string{"/.rbenv/shims/"}, ExecPrefix: []string{"rbenv", "exec"}},
+	{Name: "nodenv", PathMarkers: []string{"/.nodenv/shims/"}, ExecPrefix: []string{"nodenv", "exec"}},
+	{Name: "goenv", PathMarkers: []string{"/.goenv/shims/"}, ExecPrefix: []string{"goenv", "exec"}},
+	// nvm is a shell function, so commands cannot be re-run through it
+	{Name: "nvm", PathMarkers: []string{"/.nvm/versions/"}},
+}
+
+// DetectVersionManager returns the name of the version manager owning the command path, if any
+func DetectVersionManager(commandPath string) string {
+	if manager := findVersionManager(commandPath); manager != nil {
+		return manager.Name
+	}
+	return ""
+}
+
+// findVersionManager returns the version manager owning the command path, or nil
+func findVersionManager(commandPath string) *versionManager {
+	if commandPath == "" {
+		return nil
+	}
+
+	path := filepath.ToSlash(commandPath)
+	for i := range knownVersionManagers {
+		for _, marker := range knownVersionManagers[i].PathMarkers {
+			if strings.Contains(path, marker) {
+				return &knownVersionManagers[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// managerCommand wraps a check command so it runs through the version manager's resolution
+// The original command is returned if the manager cannot execute commands or is not installed
+func managerCommand(commandPath string, command []string, installed func(string) bool) []string {
+	manager := findVersionManager(commandPath)
+	if manager == nil || len(manager.ExecPrefix) == 0 {
+		return command
+	}
+
+	if !installed(manager.ExecPrefix[0]) {
+		return command
+	}
+
+	wrapped := make([]string, 0, len(manager.ExecPrefix)+len(command))
+	wrapped = append(wrapped, manager.ExecPrefix...)
+	wrapped = append(wrapped, command...)
+	return wrapped
+}
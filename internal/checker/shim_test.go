@@ -0,0 +1,56 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectVersionManager(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"asdf shim", "/home/dev/.asdf/shims/node", "asdf"},
+		{"mise shim", "/home/dev/.local/share/mise/shims/go", "mise"},
+		{"pyenv shim", "/Users/dev/.pyenv/shims/python", "pyenv"},
+		{"nvm install", "/home/dev/.nvm/versions/node/v20.1.0/bin/node", "nvm"},
+		{"system binary", "/usr/local/bin/go", ""},
+		{"empty path", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectVersionManager(tt.path); got != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestManagerCommandWithoutManager(t *testing.T) {
+	command := []string{"go", "version"}
+	installed := func(string) bool { return true }
+
+	got := managerCommand("/usr/local/bin/go", command, installed)
+	if len(got) != 2 || got[0] != "go" {
+		t.Errorf("Expected command to be unchanged, got %v", got)
+	}
+
+	// nvm cannot execute commands, so the command is never wrapped
+	got = managerCommand("/home/dev/.nvm/versions/node/v20.1.0/bin/node", []string{"node", "--version"}, installed)
+	if len(got) != 2 || got[0] != "node" {
+		t.Errorf("Expected nvm command to be unchanged, got %v", got)
+	}
+
+	// A manager that is not installed on the target cannot run the command
+	got = managerCommand("/home/dev/.asdf/shims/go", command, func(string) bool { return false })
+	if len(got) != 2 || got[0] != "go" {
+		t.Errorf("Expected command to be unchanged without asdf, got %v", got)
+	}
+
+	got = managerCommand("/home/dev/.asdf/shims/go", command, installed)
+	if strings.Join(got, " ") != "asdf exec go version" {
+		t.Errorf("Expected the command to run through asdf, got %v", got)
+	}
+}
@@ -0,0 +1,136 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// sshConnectionFailed is the exit status ssh uses for its own errors
+const sshConnectionFailed = 255
+
+// SSHRunner runs commands on a remote host through the ssh client
+// Authentication, host keys, and jump hosts come from the user's ssh configuration; ssh never
+// prompts, and one connection is shared by all commands of a run
+type SSHRunner struct {
+	Destination string // [user@]host
+	Port        string
+}
+
+// NewSSHRunner creates a runner for an ssh://[user@]host[:port] URL
+func NewSSHRunner(target *url.URL) (*SSHRunner, error) {
+	if target.Hostname() == "" {
+		return nil, fmt.Errorf("invalid target %q: missing host", target)
+	}
+	if target.Path != "" && target.Path != "/" {
+		return nil, fmt.Errorf("invalid target %q: paths are not supported", target)
+	}
+
+	destination := target.Hostname()
+	if user := target.User.Username(); user != "" {
+		destination = user + "@" + destination
+	}
+	// ssh would read such a destination as an option, e.g. -oProxyCommand=...
+	if strings.HasPrefix(destination, "-") {
+		return nil, fmt.Errorf("invalid target %q: the host or user cannot start with -", target)
+	}
+
+	return &SSHRunner{Destination: destination, Port: target.Port()}, nil
+}
+
+// LookPath asks the remote shell for the executable's path
+func (r *SSHRunner) LookPath(ctx context.Context, name string) (string, bool, error) {
+	output, err := r.ssh(ctx, "command -v "+shellQuote(name))
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() != sshConnectionFailed {
+			return "", false, nil
+		}
+		return "", false, runnerFailure(err, output)
+	}
+	return strings.TrimSpace(output), true, nil
+}
+
+// Run executes the command through the remote user's shell
+func (r *SSHRunner) Run(ctx context.Context, command []string, workdir string, env map[string]string) (string, error) {
+	output, err := r.ssh(ctx, remoteScript(command, workdir, env))
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == sshConnectionFailed {
+		return output, fmt.Errorf("connecting to %s failed: %v", r.Destination, runnerFailure(err, output))
+	}
+	return output, err
+}
+
+func (r *SSHRunner) String() string {
+	if r.Port != "" {
+		return "ssh://" + r.Destination + ":" + r.Port
+	}
+	return "ssh://" + r.Destination
+}
+
+// ssh runs a script on the remote host and returns its combined output
+func (r *SSHRunner) ssh(ctx context.Context, script string) (string, error) {
+	cmd := exec.CommandContext(ctx, "ssh", r.args(script)...)
+	cmd.WaitDelay = waitDelay
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// args builds the ssh command line running script; -- ends the options, so the destination is
+// never read as one
+func (r *SSHRunner) args(script string) []string {
+	args := []string{
+		"-o", "BatchMode=yes",
+		"-o", "ControlMaster=auto",
+		"-o", "ControlPath=" + filepath.Join(os.TempDir(), "goctor-ssh-%C"),
+		"-o", "ControlPersist=60",
+	}
+	if r.Port != "" {
+		args = append(args, "-p", r.Port)
+	}
+	return append(args, "--", r.Destination, script)
+}
+
+// remoteScript renders a command, its working directory, and environment as a shell script
+func remoteScript(command []string, workdir string, env map[string]string) string {
+	var script strings.Builder
+	if workdir != "" {
+		script.WriteString("cd " + shellQuote(workdir) + " && ")
+	}
+
+	if len(env) > 0 {
+		names := make([]string, 0, len(env))
+		for name := range env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		script.WriteString("env")
+		for _, name := range names {
+			script.WriteString(" " + shellQuote(name+"="+env[name]))
+		}
+		script.WriteString(" ")
+	}
+
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = shellQuote(arg)
+	}
+	script.WriteString(strings.Join(quoted, " "))
+
+	return script.String()
+}
+
+// shellQuote quotes a word for a POSIX shell, leaving plain words as they are
+func shellQuote(word string) string {
+	if word != "" && strings.Trim(word, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:@,+%") == "" {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
@@ -0,0 +1,48 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+// checkSubChecks runs a tool's sub-checks and folds them into the tool's result
+// A passing tool takes the status of its first failing sub-check so the summary
+// still shows one entry per tool
+func (c *Checker) checkSubChecks(tool manifest.ToolDefinition, platformInfo platform.PlatformInfo, result *CheckResult) {
+	var failed []string
+	aggregateStatus := StatusOK
+
+	for _, sc := range tool.Checks {
+		subResult := c.checkTool(tool.SubCheckDefinition(sc), platformInfo)
+		for _, step := range subResult.Trace {
+			step.Detail = sc.Name + ": " + step.Detail
+			result.Trace = append(result.Trace, step)
+		}
+
+		result.SubChecks = append(result.SubChecks, SubCheckResult{
+			Name:            sc.Name,
+			Status:          subResult.Status,
+			RequiredVersion: sc.RequiredVersion,
+			ActualVersion:   subResult.ActualVersion,
+			CommandPath:     subResult.CommandPath,
+			ErrorMessage:    subResult.ErrorMessage,
+		})
+
+		if subResult.Status != StatusOK {
+			failed = append(failed, fmt.Sprintf("%s (%s)", sc.Name, subResult.Status))
+			if aggregateStatus == StatusOK {
+				aggregateStatus = subResult.Status
+			}
+		}
+	}
+
+	if len(failed) == 0 || result.Status != StatusOK {
+		return
+	}
+
+	result.Status = aggregateStatus
+	result.ErrorMessage = "sub-checks failed: " + strings.Join(failed, ", ")
+}
@@ -0,0 +1,81 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+func TestCheckToolAggregatesSubChecks(t *testing.T) {
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+	echo := func(output string) manifest.CheckConfig {
+		return manifest.CheckConfig{
+			Command: []string{"echo", output},
+			Regex:   `(?P<ver>\d+\.\d+\.\d+)`,
+		}
+	}
+
+	tests := []struct {
+		name             string
+		checks           []manifest.SubCheck
+		expectedStatus   CheckStatus
+		expectedStatuses []CheckStatus
+	}{
+		{
+			name: "all sub-checks pass",
+			checks: []manifest.SubCheck{
+				{Name: "compose", RequiredVersion: ">=2.0", Check: echo("2.20.1")},
+				{Name: "buildx", Check: echo("0.11.2")},
+			},
+			expectedStatus:   StatusOK,
+			expectedStatuses: []CheckStatus{StatusOK, StatusOK},
+		},
+		{
+			name: "outdated sub-check",
+			checks: []manifest.SubCheck{
+				{Name: "compose", RequiredVersion: ">=2.0", Check: echo("1.29.2")},
+				{Name: "buildx", Check: echo("0.11.2")},
+			},
+			expectedStatus:   StatusOutdated,
+			expectedStatuses: []CheckStatus{StatusOutdated, StatusOK},
+		},
+		{
+			name: "missing sub-check",
+			checks: []manifest.SubCheck{
+				{Name: "buildx", Check: manifest.CheckConfig{
+					Command: []string{"goctor-nonexistent-buildx", "version"},
+					Regex:   `(?P<ver>\d+\.\d+\.\d+)`,
+				}},
+			},
+			expectedStatus:   StatusNotFound,
+			expectedStatuses: []CheckStatus{StatusNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := manifest.ToolDefinition{
+				ID:              "docker",
+				Name:            "Docker",
+				RequiredVersion: ">=24.0",
+				Check:           echo("24.0.7"),
+				Checks:          tt.checks,
+			}
+
+			result := NewChecker().CheckTool(tool, platformInfo)
+
+			if result.Status != tt.expectedStatus {
+				t.Errorf("Expected status %v, got %v (%s)", tt.expectedStatus, result.Status, result.ErrorMessage)
+			}
+			if len(result.SubChecks) != len(tt.expectedStatuses) {
+				t.Fatalf("Expected %d sub-check results, got %d", len(tt.expectedStatuses), len(result.SubChecks))
+			}
+			for i, expected := range tt.expectedStatuses {
+				if result.SubChecks[i].Status != expected {
+					t.Errorf("Expected sub-check %s status %v, got %v", result.SubChecks[i].Name, expected, result.SubChecks[i].Status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package checker
+
+import (
+	"strings"
+	"text/template"
+)
+
+// expandCommand renders {{ .var }} templates in each argument of a check command
+func expandCommand(command []string, vars map[string]string) ([]string, error) {
+	expanded := make([]string, len(command))
+
+	for i, arg := range command {
+		if !strings.Contains(arg, "{{") {
+			expanded[i] = arg
+			continue
+		}
+
+		tmpl, err := template.New("cmd").Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return nil, NewCheckError("invalid command template: "+err.Error(), ErrorTypeConfiguration)
+		}
+
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, vars); err != nil {
+			return nil, NewCheckError("failed to expand command template: "+err.Error(), ErrorTypeConfiguration)
+		}
+		expanded[i] = sb.String()
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,28 @@
+package checker
+
+import (
+	"testing"
+)
+
+func TestExpandCommand(t *testing.T) {
+	vars := map[string]string{
+		"brew_prefix": "/opt/homebrew",
+		"arch":        "arm64",
+	}
+
+	expanded, err := expandCommand([]string{"{{ .brew_prefix }}/bin/tool", "--arch={{.arch}}", "--version"}, vars)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []string{"/opt/homebrew/bin/tool", "--arch=arm64", "--version"}
+	for i := range expected {
+		if expanded[i] != expected[i] {
+			t.Errorf("Expected argument %d to be '%s', got '%s'", i, expected[i], expanded[i])
+		}
+	}
+
+	if _, err := expandCommand([]string{"{{ .unknown }}/bin/tool"}, vars); err == nil {
+		t.Error("Expected error for unknown template variable")
+	}
+}
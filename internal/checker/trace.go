@@ -0,0 +1,106 @@
+package checker
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Trace actions
+const (
+	TraceRequire  = "require"  // the constraint was read from a project file
+	TraceLookup   = "lookup"   // an executable was looked up on the target's PATH
+	TraceRun      = "run"      // a command ran
+	TraceMatch    = "match"    // the version regex was applied to a command's output
+	TraceEnv      = "env"      // an environment variable was read
+	TraceChecksum = "checksum" // an executable was hashed to verify expect_sha256
+)
+
+// TraceStep is one step of a check, recorded in verbose mode to show how its result came about
+type TraceStep struct {
+	Action   string       `json:"action"`
+	Detail   string       `json:"detail"`
+	Output   string       `json:"output,omitempty"` // What a command printed
+	Duration Milliseconds `json:"duration_ms,omitempty"`
+}
+
+// String returns the step as one line, e.g. "run go version (12ms)"
+func (s TraceStep) String() string {
+	line := s.Action + " " + s.Detail
+	if took := time.Duration(s.Duration); took >= time.Millisecond {
+		line += fmt.Sprintf(" (%s)", took.Round(time.Millisecond))
+	} else if took > 0 {
+		line += fmt.Sprintf(" (%s)", took.Round(time.Microsecond))
+	}
+	return line
+}
+
+// SetVerbose makes checks record a trace of the lookups, commands, and regex matches behind
+// each result
+func (c *Checker) SetVerbose(enabled bool) {
+	c.verbose = enabled
+}
+
+// trace appends a step to the result's trace in verbose mode and logs it at debug level, with
+// command output sanitized as for --capture-output
+func (c *Checker) trace(result *CheckResult, step TraceStep) {
+	if c.verbose {
+		result.Trace = append(result.Trace, step)
+	}
+	if c.logger.Enabled(context.Background(), slog.LevelDebug) {
+		attrs := []any{"tool", result.ToolID, "detail", step.Detail}
+		if step.Duration > 0 {
+			attrs = append(attrs, "duration", time.Duration(step.Duration))
+		}
+		if step.Output != "" {
+			attrs = append(attrs, "output", SanitizeOutput(step.Output, MaxCapturedOutput))
+		}
+		c.logger.Debug(step.Action, attrs...)
+	}
+}
+
+// tracing returns true if trace steps are recorded or logged, so they are worth building
+func (c *Checker) tracing() bool {
+	return c.verbose || c.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// runTraced runs a check command like runCommand, tracing it with its output and duration
+func (c *Checker) runTraced(result *CheckResult, command []string, timeoutSec int, workdir string, env map[string]string) (string, error) {
+	start := time.Now()
+	output, err := c.runCommand(result.ToolID, command, timeoutSec, workdir, env)
+
+	detail := strings.Join(command, " ")
+	if workdir != "" {
+		detail += " in " + workdir
+	}
+	if err != nil {
+		detail += ": " + err.Error()
+	}
+	c.trace(result, TraceStep{Action: TraceRun, Detail: detail, Output: output, Duration: Milliseconds(time.Since(start))})
+	return output, err
+}
+
+// traceMatch traces how the version regex matched a command's output, given the version and
+// capture group parseVersionFromOutput returned or its error; an unnamed group is the first
+func (c *Checker) traceMatch(result *CheckResult, output, pattern, version, group string, err error) {
+	if !c.tracing() {
+		return
+	}
+
+	detail := pattern
+	if regex, compileErr := regexp.Compile(pattern); compileErr == nil {
+		if match := regex.FindString(output); match != "" {
+			detail += fmt.Sprintf(" matched %q", match)
+		}
+	}
+	if err != nil {
+		detail += ": " + err.Error()
+	} else {
+		detail += fmt.Sprintf(": version %s from group %s", version, cmp.Or(group, "1"))
+	}
+	c.trace(result, TraceStep{Action: TraceMatch, Detail: detail})
+}
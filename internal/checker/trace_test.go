@@ -0,0 +1,151 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+func TestCheckToolTrace(t *testing.T) {
+	runner := &fakeRunner{
+		installed: map[string]string{"node": "/usr/bin/node"},
+		outputs:   map[string]string{"node --version": "v20.11.0\n", "nodejs --version": "unused"},
+	}
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+	tool := manifest.ToolDefinition{
+		ID:              "node",
+		Name:            "Node.js",
+		RequiredVersion: ">=20",
+		Check: manifest.CheckConfig{
+			Command:   []string{"nodejs", "--version"},
+			Fallbacks: [][]string{{"node", "--version"}},
+			Regex:     `v(?P<ver>\d+\.\d+\.\d+)`,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		verbose bool
+		want    []string
+	}{
+		{name: "quiet by default", verbose: false},
+		{
+			name:    "verbose",
+			verbose: true,
+			want: []string{
+				"lookup nodejs: not found",
+				"lookup node: /usr/bin/node",
+				"run node --version",
+				`match v(?P<ver>\d+\.\d+\.\d+) matched "v20.11.0": version 20.11.0 from group ver`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolChecker := NewChecker()
+			toolChecker.SetRunner(runner)
+			toolChecker.SetVerbose(tt.verbose)
+
+			result := toolChecker.CheckTool(tool, platformInfo)
+			if result.Status != StatusOK {
+				t.Fatalf("Expected ok, got %v: %s", result.Status, result.ErrorMessage)
+			}
+			if len(result.Trace) != len(tt.want) {
+				t.Fatalf("Expected %d trace steps, got %+v", len(tt.want), result.Trace)
+			}
+			for i, step := range result.Trace {
+				// Durations vary from run to run
+				step.Duration = 0
+				if got := step.String(); got != tt.want[i] {
+					t.Errorf("Step %d = %q, want %q", i, got, tt.want[i])
+				}
+			}
+			if tt.verbose && result.Trace[2].Output != "v20.11.0\n" {
+				t.Errorf("Expected the run step to keep the raw output, got %q", result.Trace[2].Output)
+			}
+		})
+	}
+}
+
+func TestTraceMatchFailure(t *testing.T) {
+	toolChecker := NewChecker()
+	toolChecker.SetVerbose(true)
+
+	var result CheckResult
+	_, _, err := toolChecker.parseVersionFromOutput("command not found", `(?P<ver>\d+\.\d+)`, "")
+	toolChecker.traceMatch(&result, "command not found", `(?P<ver>\d+\.\d+)`, "", "", err)
+
+	want := `match (?P<ver>\d+\.\d+): no version found in output`
+	if len(result.Trace) != 1 || result.Trace[0].String() != want {
+		t.Errorf("Expected %q, got %+v", want, result.Trace)
+	}
+}
+
+func TestCheckToolDebugLog(t *testing.T) {
+	runner := &fakeRunner{
+		installed: map[string]string{"node": "/usr/bin/node"},
+		outputs:   map[string]string{"node --version": "v20.11.0 NPM_TOKEN=abc123\n"},
+	}
+	tool := manifest.ToolDefinition{
+		ID:              "node",
+		Name:            "Node.js",
+		RequiredVersion: ">=20",
+		Check:           manifest.CheckConfig{Command: []string{"node", "--version"}, Regex: `v(?P<ver>\d+\.\d+\.\d+)`},
+	}
+
+	tests := []struct {
+		name  string
+		level slog.Level
+		want  []string
+	}{
+		{name: "info logs nothing", level: slog.LevelInfo},
+		{
+			name:  "debug logs each step without verbose",
+			level: slog.LevelDebug,
+			want:  []string{"lookup", "run", "match", "checked tool"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			toolChecker := NewChecker()
+			toolChecker.SetRunner(runner)
+			toolChecker.SetLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: tt.level})))
+
+			result := toolChecker.CheckTool(tool, platform.PlatformInfo{OS: "linux", Architecture: "amd64"})
+			if len(result.Trace) != 0 {
+				t.Errorf("Expected no trace without verbose, got %+v", result.Trace)
+			}
+
+			var messages []string
+			decoder := json.NewDecoder(&buf)
+			for decoder.More() {
+				var record struct {
+					Msg    string `json:"msg"`
+					Tool   string `json:"tool"`
+					Output string `json:"output"`
+				}
+				if err := decoder.Decode(&record); err != nil {
+					t.Fatalf("Invalid log record: %v", err)
+				}
+				if record.Tool != "node" {
+					t.Errorf("Expected tool node in %+v", record)
+				}
+				if record.Msg == TraceRun && record.Output != "v20.11.0 NPM_TOKEN=[REDACTED]\n" {
+					t.Errorf("Expected sanitized output, got %q", record.Output)
+				}
+				messages = append(messages, record.Msg)
+			}
+			if !slices.Equal(messages, tt.want) {
+				t.Errorf("Logged %v, want %v", messages, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+// checkVariants checks each variant of a tool on its own and folds them into one result
+// The tool passes when every variant does; otherwise it takes the status of its first
+// failing variant, like a tool with failing sub-checks
+func (c *Checker) checkVariants(tool manifest.ToolDefinition, platformInfo platform.PlatformInfo) CheckResult {
+	result := newResult(tool, platformInfo)
+	result.Status = StatusOK
+
+	var failed []string
+	for _, v := range tool.Variants {
+		variantResult := c.checkTool(tool.VariantDefinition(v), platformInfo)
+		for _, step := range variantResult.Trace {
+			step.Detail = v.Name + ": " + step.Detail
+			result.Trace = append(result.Trace, step)
+		}
+
+		result.Variants = append(result.Variants, SubCheckResult{
+			Name:            v.Name,
+			Status:          variantResult.Status,
+			RequiredVersion: variantResult.RequiredVersion,
+			ActualVersion:   variantResult.ActualVersion,
+			CommandPath:     variantResult.CommandPath,
+			ErrorMessage:    variantResult.ErrorMessage,
+		})
+
+		if variantResult.Status != StatusOK {
+			failed = append(failed, fmt.Sprintf("%s (%s)", v.Name, variantResult.Status))
+			if result.Status == StatusOK {
+				result.Status = variantResult.Status
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		result.ErrorMessage = "variants failed: " + strings.Join(failed, ", ")
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+func TestCheckToolVariants(t *testing.T) {
+	runner := &fakeRunner{
+		installed: map[string]string{"/usr/lib/jvm/java-11/bin/java": "/usr/lib/jvm/java-11/bin/java", "/usr/lib/jvm/java-17/bin/java": "/usr/lib/jvm/java-17/bin/java"},
+		outputs: map[string]string{
+			"/usr/lib/jvm/java-11/bin/java -version": `openjdk version "11.0.22" 2024-01-16`,
+			"/usr/lib/jvm/java-17/bin/java -version": `openjdk version "17.0.10" 2024-01-16`,
+		},
+	}
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+
+	variant := func(name, require, home string) manifest.Variant {
+		return manifest.Variant{Name: name, RequiredVersion: require, Check: manifest.CheckConfig{Command: []string{home + "/bin/java", "-version"}}}
+	}
+
+	tests := []struct {
+		name       string
+		variants   []manifest.Variant
+		wantStatus CheckStatus
+		wantError  string
+		want       []CheckStatus
+	}{
+		{
+			name:       "all variants installed",
+			variants:   []manifest.Variant{variant("11", "~11", "/usr/lib/jvm/java-11"), variant("17", "~17", "/usr/lib/jvm/java-17")},
+			wantStatus: StatusOK,
+			want:       []CheckStatus{StatusOK, StatusOK},
+		},
+		{
+			name:       "one variant missing",
+			variants:   []manifest.Variant{variant("11", "~11", "/usr/lib/jvm/java-11"), variant("21", "~21", "/usr/lib/jvm/java-21")},
+			wantStatus: StatusNotFound,
+			wantError:  "variants failed: 21 (not_found)",
+			want:       []CheckStatus{StatusOK, StatusNotFound},
+		},
+		{
+			name:       "wrong version at a variant's location",
+			variants:   []manifest.Variant{variant("11", "~11", "/usr/lib/jvm/java-17"), variant("17", "~17", "/usr/lib/jvm/java-17")},
+			wantStatus: StatusOutdated,
+			wantError:  "variants failed: 11 (outdated)",
+			want:       []CheckStatus{StatusOutdated, StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := manifest.ToolDefinition{
+				ID:       "java",
+				Name:     "Java",
+				Check:    manifest.CheckConfig{Command: []string{"java", "-version"}, Regex: `version "(?P<ver>\d+(\.\d+)*)"`},
+				Variants: tt.variants,
+			}
+
+			toolChecker := NewChecker()
+			toolChecker.SetRunner(runner)
+			result := toolChecker.CheckTool(tool, platformInfo)
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Expected %v, got %v (%s)", tt.wantStatus, result.Status, result.ErrorMessage)
+			}
+			if result.ErrorMessage != tt.wantError {
+				t.Errorf("Expected error %q, got %q", tt.wantError, result.ErrorMessage)
+			}
+			if len(result.Variants) != len(tt.want) {
+				t.Fatalf("Expected %d variant results, got %+v", len(tt.want), result.Variants)
+			}
+			for i, v := range result.Variants {
+				if v.Name != tt.variants[i].Name || v.Status != tt.want[i] || v.RequiredVersion != tt.variants[i].RequiredVersion {
+					t.Errorf("Variant %d = %+v, want %s %v", i, v, tt.variants[i].Name, tt.want[i])
+				}
+			}
+		})
+	}
+
+	// Each variant reports where it was found
+	tool := manifest.ToolDefinition{
+		ID:       "java",
+		Name:     "Java",
+		Check:    manifest.CheckConfig{Regex: `version "(?P<ver>\d+(\.\d+)*)"`},
+		Variants: []manifest.Variant{variant("17", "~17", "/usr/lib/jvm/java-17")},
+	}
+	toolChecker := NewChecker()
+	toolChecker.SetRunner(runner)
+	if got := toolChecker.CheckTool(tool, platformInfo).Variants[0]; got.ActualVersion != "17.0.10" || got.CommandPath != "/usr/lib/jvm/java-17/bin/java" {
+		t.Errorf("Expected 17.0.10 at /usr/lib/jvm/java-17/bin/java, got %+v", got)
+	}
+}
@@ -0,0 +1,101 @@
+package completion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bash generates a bash completion script
+// The script walks the words typed so far to find the command being completed, skipping flag
+// values, then offers that command's flags, subcommands, or arguments
+func Bash(spec Spec) string {
+	fn := "_" + functionName(spec.Program)
+	nodes := spec.nodes()
+	manifest, hasManifest := spec.manifestFlag()
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "# bash completion for %s\n\n", spec.Program)
+	fmt.Fprintf(&out, "%s() {\n", fn)
+	out.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	out.WriteString("    local cmd=\"\" word i skip=0\n")
+	out.WriteString("    local -a manifests=()\n")
+	out.WriteString("    COMPREPLY=()\n\n")
+
+	// Find the command, skipping flag values and remembering the manifests for tool IDs
+	out.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	out.WriteString("        word=\"${COMP_WORDS[i]}\"\n")
+	out.WriteString("        if ((skip)); then\n            skip=0\n            continue\n        fi\n")
+	out.WriteString("        case \"$cmd:$word\" in\n")
+	for _, n := range commandsFirst(nodes) {
+		var flags []Flag
+		for _, f := range valueFlags(n.flags) {
+			if n.path == "" && hasManifest && f.Name == manifest.Name {
+				fmt.Fprintf(&out, "            %s)\n", globalPatterns([]Flag{manifest}))
+				out.WriteString("                skip=1\n")
+				out.WriteString("                manifests+=(\"$word\" \"${COMP_WORDS[i+1]}\")\n")
+				out.WriteString("                ;;\n")
+				continue
+			}
+			flags = append(flags, f)
+		}
+		if len(flags) > 0 {
+			fmt.Fprintf(&out, "            %s) skip=1 ;;\n", n.patterns(flags))
+		}
+	}
+	for _, n := range nodes {
+		for _, sub := range n.command.Subcommands {
+			fmt.Fprintf(&out, "            \"%s:%s\") cmd=\"%s\" ;;\n", n.path, sub.Name, strings.TrimSpace(n.path+" "+sub.Name))
+		}
+	}
+	out.WriteString("        esac\n")
+	out.WriteString("    done\n\n")
+
+	// Complete the value of the previous flag
+	out.WriteString("    if ((skip)); then\n")
+	out.WriteString("        case \"$cmd:$prev\" in\n")
+	for _, n := range commandsFirst(nodes) {
+		for _, f := range valueFlags(n.flags) {
+			switch {
+			case len(f.Values) > 0:
+				fmt.Fprintf(&out, "            %s) COMPREPLY=($(compgen -W %s -- \"$cur\")) ;;\n", n.patterns([]Flag{f}), singleQuote(strings.Join(f.Values, " ")))
+			case f.Files:
+				fmt.Fprintf(&out, "            %s) compopt -o filenames 2>/dev/null; COMPREPLY=($(compgen -f -- \"$cur\")) ;;\n", n.patterns([]Flag{f}))
+			}
+		}
+	}
+	out.WriteString("        esac\n")
+	out.WriteString("        return 0\n")
+	out.WriteString("    fi\n\n")
+
+	// Complete flags
+	out.WriteString("    if [[ $cur == -* ]]; then\n")
+	out.WriteString("        case \"$cmd\" in\n")
+	for _, n := range nodes {
+		if flags := spec.allFlags(n); len(flags) > 0 {
+			fmt.Fprintf(&out, "            \"%s\") COMPREPLY=($(compgen -W %s -- \"$cur\")) ;;\n", n.path, singleQuote(strings.Join(flagNames(flags), " ")))
+		}
+	}
+	out.WriteString("        esac\n")
+	out.WriteString("        return 0\n")
+	out.WriteString("    fi\n\n")
+
+	// Complete subcommands and arguments
+	toolIDs := fmt.Sprintf("$(\"${COMP_WORDS[0]}\" \"${manifests[@]}\" %s 2>/dev/null)", strings.Join(spec.ToolIDsArgs, " "))
+	out.WriteString("    case \"$cmd\" in\n")
+	for _, n := range nodes {
+		words := append(commandNames(n.command.Subcommands), n.command.Args...)
+		switch {
+		case n.command.Files:
+			fmt.Fprintf(&out, "        \"%s\") compopt -o filenames 2>/dev/null; COMPREPLY=($(compgen -f -- \"$cur\")) ;;\n", n.path)
+		case n.command.ToolIDs && len(spec.ToolIDsArgs) > 0:
+			fmt.Fprintf(&out, "        \"%s\") COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")) ;;\n", n.path, strings.TrimSpace(strings.Join(words, " ")+" "+toolIDs))
+		case len(words) > 0:
+			fmt.Fprintf(&out, "        \"%s\") COMPREPLY=($(compgen -W %s -- \"$cur\")) ;;\n", n.path, singleQuote(strings.Join(words, " ")))
+		}
+	}
+	out.WriteString("    esac\n")
+	out.WriteString("}\n\n")
+
+	fmt.Fprintf(&out, "complete -F %s %s\n", fn, spec.Program)
+	return out.String()
+}
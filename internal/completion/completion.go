@@ -0,0 +1,223 @@
+// Package completion generates bash, zsh, and fish completion scripts from a description of
+// the command tree, so the scripts stay in step with the flags each command defines
+package completion
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Shells
+const (
+	ShellBash = "bash"
+	ShellZsh  = "zsh"
+	ShellFish = "fish"
+)
+
+// Shells lists the shells completion scripts can be generated for
+var Shells = []string{ShellBash, ShellZsh, ShellFish}
+
+// Flag is a flag as completed on the command line
+type Flag struct {
+	Name       string // without dashes
+	Usage      string
+	TakesValue bool
+	Values     []string // fixed values completed after the flag
+	Files      bool     // the value is a path
+}
+
+// Command is a command or subcommand and the arguments it takes
+type Command struct {
+	Name        string
+	Description string
+	Flags       []Flag
+	Subcommands []Command
+	Args        []string // fixed positional arguments
+	ToolIDs     bool     // positional arguments are tool IDs
+	Files       bool     // positional arguments are paths
+}
+
+// Spec describes the program being completed
+// Global flags are completed anywhere on the command line, except after a command that defines
+// a flag of the same name
+type Spec struct {
+	Program  string
+	Flags    []Flag // global flags
+	Commands []Command
+
+	// ToolIDsArgs are the arguments that make the program print the manifest's tool IDs, one
+	// per line; ManifestFlag, the global flag naming the manifest, is passed along
+	ToolIDsArgs  []string
+	ManifestFlag string
+}
+
+// Script generates the completion script for a shell
+func Script(shell string, spec Spec) (string, error) {
+	switch shell {
+	case ShellBash:
+		return Bash(spec), nil
+	case ShellZsh:
+		return Zsh(spec), nil
+	case ShellFish:
+		return Fish(spec), nil
+	default:
+		return "", fmt.Errorf("unknown shell %q (must be one of: %s)", shell, strings.Join(Shells, ", "))
+	}
+}
+
+// node is one position in the command tree, identified by the space-separated path of
+// command names leading to it ("" is the program itself)
+type node struct {
+	path    string
+	flags   []Flag
+	command Command
+}
+
+// nodes lists the program and every command below it, parents first
+func (s Spec) nodes() []node {
+	nodes := []node{{flags: s.Flags, command: Command{Subcommands: s.Commands}}}
+	var walk func(prefix string, commands []Command)
+	walk = func(prefix string, commands []Command) {
+		for _, command := range commands {
+			path := strings.TrimSpace(prefix + " " + command.Name)
+			nodes = append(nodes, node{path: path, flags: command.Flags, command: command})
+			walk(path, command.Subcommands)
+		}
+	}
+	walk("", s.Commands)
+	return nodes
+}
+
+// flagName returns the spelling completed for a flag: -x for single letters, --name otherwise
+func flagName(f Flag) string {
+	if len(f.Name) == 1 {
+		return "-" + f.Name
+	}
+	return "--" + f.Name
+}
+
+// flagSpellings returns every spelling of a flag the program accepts
+func flagSpellings(f Flag) []string {
+	return []string{"-" + f.Name, "--" + f.Name}
+}
+
+// functionName turns the program name into a shell function name
+func functionName(program string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, program)
+}
+
+// singleQuote quotes s for the shell
+func singleQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// casePatterns returns the quoted "path:word" patterns matching any spelling of the flags
+func casePatterns(path string, flags []Flag) string {
+	var patterns []string
+	for _, f := range flags {
+		for _, spelling := range flagSpellings(f) {
+			patterns = append(patterns, `"`+path+":"+spelling+`"`)
+		}
+	}
+	return strings.Join(patterns, "|")
+}
+
+// valueFlags returns the flags that take a value
+func valueFlags(flags []Flag) []Flag {
+	var values []Flag
+	for _, f := range flags {
+		if f.TakesValue {
+			values = append(values, f)
+		}
+	}
+	return values
+}
+
+// commandNames returns the names of commands
+func commandNames(commands []Command) []string {
+	names := make([]string, 0, len(commands))
+	for _, command := range commands {
+		names = append(names, command.Name)
+	}
+	return names
+}
+
+// flagNames returns the completed spelling of each flag
+func flagNames(flags []Flag) []string {
+	names := make([]string, 0, len(flags))
+	for _, f := range flags {
+		names = append(names, flagName(f))
+	}
+	return names
+}
+
+// defines reports whether a command below the program defines a flag named name
+func (n node) defines(name string) bool {
+	if n.path == "" {
+		return false
+	}
+	for _, f := range n.flags {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// allFlags returns the flags completed for a node: its own, then the global flags it does not
+// redefine
+func (s Spec) allFlags(n node) []Flag {
+	if n.path == "" {
+		return s.Flags
+	}
+	flags := slices.Clone(n.flags)
+	for _, f := range s.Flags {
+		if !n.defines(f.Name) {
+			flags = append(flags, f)
+		}
+	}
+	return flags
+}
+
+// commandsFirst returns the nodes with the program last, so a command's own flag is matched
+// before the global flag of the same name
+func commandsFirst(nodes []node) []node {
+	return append(slices.Clone(nodes[1:]), nodes[0])
+}
+
+// patterns returns the quoted case patterns matching the flags of a node: after the node's
+// command, or after any command for global flags
+func (n node) patterns(flags []Flag) string {
+	if n.path == "" {
+		return globalPatterns(flags)
+	}
+	return casePatterns(n.path, flags)
+}
+
+// globalPatterns returns the quoted case patterns matching any spelling of the flags after
+// any command
+func globalPatterns(flags []Flag) string {
+	var patterns []string
+	for _, f := range flags {
+		for _, spelling := range flagSpellings(f) {
+			patterns = append(patterns, `*":`+spelling+`"`)
+		}
+	}
+	return strings.Join(patterns, "|")
+}
+
+// manifestFlag returns the global flag naming the manifest, if any
+func (s Spec) manifestFlag() (Flag, bool) {
+	for _, f := range s.Flags {
+		if f.Name == s.ManifestFlag && f.TakesValue {
+			return f, true
+		}
+	}
+	return Flag{}, false
+}
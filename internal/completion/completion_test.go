@@ -0,0 +1,123 @@
+package completion
+
+import (
+	"strings"
+	"testing"
+)
+
+func testSpec() Spec {
+	return Spec{
+		Program: "goctor",
+		Flags: []Flag{
+			{Name: "f", Usage: "manifest file path or URL", TakesValue: true, Files: true},
+			{Name: "format", Usage: "output format", TakesValue: true, Values: []string{"human", "json"}},
+			{Name: "json", Usage: "output JSON format"},
+		},
+		Commands: []Command{
+			{Name: "doctor", Description: "Check development environment", Subcommands: []Command{
+				{Name: "completion", Description: "Print a shell completion script", Args: []string{"bash", "zsh", "fish"}},
+				{Name: "serve", Description: "Serve the report over HTTP", Flags: []Flag{{Name: "addr", Usage: "address to listen on", TakesValue: true}}},
+			}},
+			{Name: "explain", Description: "Show full detail for one tool", Flags: []Flag{{Name: "check", Usage: "run the check"}}, ToolIDs: true},
+			{Name: "diff", Description: "Compare two reports", Flags: []Flag{{Name: "format", Usage: "diff format", TakesValue: true, Values: []string{"unified"}}}, Files: true},
+		},
+		ToolIDsArgs:  []string{"doctor", "completion", "--tool-ids"},
+		ManifestFlag: "f",
+	}
+}
+
+func TestScript(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{
+			shell: ShellBash,
+			want: []string{
+				`*":-f"|*":--f")`,
+				`manifests+=("$word" "${COMP_WORDS[i+1]}")`,
+				`"diff:-format"|"diff:--format") skip=1 ;;`,
+				`*":-format"|*":--format") skip=1 ;;`,
+				`"doctor serve:-addr"|"doctor serve:--addr") skip=1 ;;`,
+				`"doctor:serve") cmd="doctor serve" ;;`,
+				"\"diff:-format\"|\"diff:--format\") COMPREPLY=($(compgen -W 'unified' -- \"$cur\")) ;;\n            *\":-f\"",
+				`*":-format"|*":--format") COMPREPLY=($(compgen -W 'human json' -- "$cur")) ;;`,
+				`"") COMPREPLY=($(compgen -W '-f --format --json' -- "$cur")) ;;`,
+				`"doctor serve") COMPREPLY=($(compgen -W '--addr -f --format --json' -- "$cur")) ;;`,
+				`"diff") COMPREPLY=($(compgen -W '--format -f --json' -- "$cur")) ;;`,
+				`"doctor") COMPREPLY=($(compgen -W 'completion serve' -- "$cur")) ;;`,
+				`"doctor completion") COMPREPLY=($(compgen -W 'bash zsh fish' -- "$cur")) ;;`,
+				`"explain") COMPREPLY=($(compgen -W "$("${COMP_WORDS[0]}" "${manifests[@]}" doctor completion --tool-ids 2>/dev/null)" -- "$cur")) ;;`,
+				`"diff") compopt -o filenames 2>/dev/null; COMPREPLY=($(compgen -f -- "$cur")) ;;`,
+				"complete -F _goctor goctor",
+			},
+		},
+		{
+			shell: ShellZsh,
+			want: []string{
+				"#compdef goctor",
+				`("doctor:serve") cmd="doctor serve" ;;`,
+				`(*":-format"|*":--format") compadd -- 'human' 'json' ;;`,
+				`(*":-f"|*":--f") _files ;;`,
+				`("explain") entries=('--check:run the check' '-f:manifest file path or URL' '--format:output format' '--json:output JSON format') ;;`,
+				`("") entries=('doctor:Check development environment' 'explain:Show full detail for one tool' 'diff:Compare two reports'); _describe -t commands command entries ;;`,
+				`("explain") compadd -- ${(f)"$(${words[1]} $manifests doctor completion --tool-ids 2>/dev/null)"} ;;`,
+				"compdef _goctor goctor",
+			},
+		},
+		{
+			shell: ShellFish,
+			want: []string{
+				"case 'doctor:serve'\n                set -g __goctor_cmd 'doctor serve'",
+				"complete -c goctor -f\n",
+				`complete -c goctor -n "__goctor_command_is ''" -a 'doctor' -d 'Check development environment'`,
+				"case '*:-f' '*:--f'\n                set skip 1\n                set manifest 1",
+				"case 'diff:format'\n            return 1",
+				`complete -c goctor -s f -r -F -d 'manifest file path or URL'`,
+				`complete -c goctor -n '__goctor_global_flag format' -l format -x -a 'human json' -d 'output format'`,
+				`complete -c goctor -n "__goctor_command_is 'diff'" -l format -x -a 'unified' -d 'diff format'`,
+				`complete -c goctor -n "__goctor_command_is 'doctor serve'" -l addr -x -d 'address to listen on'`,
+				`complete -c goctor -n "__goctor_command_is 'doctor completion'" -a 'bash zsh fish'`,
+				`complete -c goctor -n "__goctor_command_is 'explain'" -a '(__goctor_tool_ids)'`,
+				`complete -c goctor -n "__goctor_command_is 'diff'" -F`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			script, err := Script(tt.shell, testSpec())
+			if err != nil {
+				t.Fatalf("Script() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(script, want) {
+					t.Errorf("script does not contain %q:\n%s", want, script)
+				}
+			}
+		})
+	}
+}
+
+func TestScriptUnknownShell(t *testing.T) {
+	if _, err := Script("powershell", testSpec()); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
+
+func TestSingleQuote(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"plain", "'plain'"},
+		{"goctor's setup", `'goctor'\''s setup'`},
+		{"", "''"},
+	}
+
+	for _, tt := range tests {
+		if got := singleQuote(tt.s); got != tt.want {
+			t.Errorf("singleQuote(%q) = %s, want %s", tt.s, got, tt.want)
+		}
+	}
+}
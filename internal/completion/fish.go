@@ -0,0 +1,150 @@
+package completion
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Fish generates a fish completion script
+// A helper function finds the command being completed; every completion but the global flags
+// is conditioned on it
+func Fish(spec Spec) string {
+	fn := "__" + functionName(spec.Program)
+	nodes := spec.nodes()
+	manifest, hasManifest := spec.manifestFlag()
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "# fish completion for %s\n\n", spec.Program)
+
+	// Find the command, skipping flag values and remembering the manifests for tool IDs
+	fmt.Fprintf(&out, "function %s_walk\n", fn)
+	out.WriteString("    set -l tokens (commandline -opc)\n")
+	out.WriteString("    set -e tokens[1]\n")
+	fmt.Fprintf(&out, "    set -g %s_cmd \"\"\n", fn)
+	fmt.Fprintf(&out, "    set -g %s_manifests\n", fn)
+	out.WriteString("    set -l skip 0\n")
+	out.WriteString("    set -l manifest 0\n")
+	out.WriteString("    for word in $tokens\n")
+	out.WriteString("        if test $skip -eq 1\n")
+	out.WriteString("            set skip 0\n")
+	out.WriteString("            if test $manifest -eq 1\n")
+	fmt.Fprintf(&out, "                set -a %s_manifests $word\n", fn)
+	out.WriteString("                set manifest 0\n")
+	out.WriteString("            end\n")
+	out.WriteString("            continue\n")
+	out.WriteString("        end\n")
+	fmt.Fprintf(&out, "        switch \"$%s_cmd:$word\"\n", fn)
+	for _, n := range commandsFirst(nodes) {
+		var flags []Flag
+		for _, f := range valueFlags(n.flags) {
+			if n.path == "" && hasManifest && f.Name == manifest.Name {
+				fmt.Fprintf(&out, "            case %s\n", fishPatterns(n.path, []Flag{manifest}))
+				out.WriteString("                set skip 1\n")
+				out.WriteString("                set manifest 1\n")
+				fmt.Fprintf(&out, "                set -a %s_manifests $word\n", fn)
+				continue
+			}
+			flags = append(flags, f)
+		}
+		if len(flags) > 0 {
+			fmt.Fprintf(&out, "            case %s\n                set skip 1\n", fishPatterns(n.path, flags))
+		}
+	}
+	for _, n := range nodes {
+		for _, sub := range n.command.Subcommands {
+			path := strings.TrimSpace(n.path + " " + sub.Name)
+			fmt.Fprintf(&out, "            case %s\n                set -g %s_cmd %s\n", singleQuote(n.path+":"+sub.Name), fn, singleQuote(path))
+		}
+	}
+	out.WriteString("        end\n")
+	out.WriteString("    end\n")
+	out.WriteString("end\n\n")
+
+	fmt.Fprintf(&out, "function %s_command_is\n", fn)
+	fmt.Fprintf(&out, "    %s_walk\n", fn)
+	fmt.Fprintf(&out, "    test \"$%s_cmd\" = \"$argv[1]\"\n", fn)
+	out.WriteString("end\n\n")
+
+	// Global flags a command redefines are completed with the command's meaning only
+	var redefined []string
+	for _, n := range nodes {
+		for _, f := range spec.Flags {
+			if n.defines(f.Name) {
+				redefined = append(redefined, singleQuote(n.path+":"+f.Name))
+			}
+		}
+	}
+	if len(redefined) > 0 {
+		fmt.Fprintf(&out, "function %s_global_flag\n", fn)
+		fmt.Fprintf(&out, "    %s_walk\n", fn)
+		fmt.Fprintf(&out, "    switch \"$%s_cmd:$argv[1]\"\n", fn)
+		fmt.Fprintf(&out, "        case %s\n", strings.Join(redefined, " "))
+		out.WriteString("            return 1\n")
+		out.WriteString("    end\n")
+		out.WriteString("    return 0\n")
+		out.WriteString("end\n\n")
+	}
+
+	if len(spec.ToolIDsArgs) > 0 {
+		fmt.Fprintf(&out, "function %s_tool_ids\n", fn)
+		fmt.Fprintf(&out, "    %s_walk\n", fn)
+		fmt.Fprintf(&out, "    set -l program (commandline -opc)[1]\n")
+		fmt.Fprintf(&out, "    $program $%s_manifests %s 2>/dev/null\n", fn, strings.Join(spec.ToolIDsArgs, " "))
+		out.WriteString("end\n\n")
+	}
+
+	fmt.Fprintf(&out, "complete -c %s -f\n", spec.Program)
+	for _, n := range nodes {
+		condition := fmt.Sprintf("-n \"%s_command_is '%s'\"", fn, n.path)
+		for _, sub := range n.command.Subcommands {
+			fmt.Fprintf(&out, "complete -c %s %s -a %s -d %s\n", spec.Program, condition, singleQuote(sub.Name), singleQuote(sub.Description))
+		}
+		if len(n.command.Args) > 0 {
+			fmt.Fprintf(&out, "complete -c %s %s -a %s\n", spec.Program, condition, singleQuote(strings.Join(n.command.Args, " ")))
+		}
+		if n.command.ToolIDs && len(spec.ToolIDsArgs) > 0 {
+			fmt.Fprintf(&out, "complete -c %s %s -a %s\n", spec.Program, condition, singleQuote("("+fn+"_tool_ids)"))
+		}
+		if n.command.Files {
+			fmt.Fprintf(&out, "complete -c %s %s -F\n", spec.Program, condition)
+		}
+		for _, f := range n.flags {
+			if n.path == "" {
+				condition = ""
+				if slices.ContainsFunc(nodes, func(n node) bool { return n.defines(f.Name) }) {
+					condition = fmt.Sprintf("-n %s", singleQuote(fn+"_global_flag "+f.Name))
+				}
+			}
+			option := "-l " + f.Name
+			if len(f.Name) == 1 {
+				option = "-s " + f.Name
+			}
+			switch {
+			case len(f.Values) > 0:
+				option += " -x -a " + singleQuote(strings.Join(f.Values, " "))
+			case f.Files:
+				option += " -r -F"
+			case f.TakesValue:
+				option += " -x"
+			}
+			fmt.Fprintf(&out, "complete -c %s %s -d %s\n", spec.Program, strings.TrimSpace(condition+" "+option), singleQuote(f.Usage))
+		}
+	}
+	return out.String()
+}
+
+// fishPatterns returns the quoted "path:word" patterns matching any spelling of the flags,
+// after any command for the program's own flags
+func fishPatterns(path string, flags []Flag) string {
+	if path == "" {
+		path = "*"
+	}
+	var patterns []string
+	for _, f := range flags {
+		for _, spelling := range flagSpellings(f) {
+			patterns = append(patterns, singleQuote(path+":"+spelling))
+		}
+	}
+	return strings.Join(patterns, " ")
+}
@@ -0,0 +1,128 @@
+package completion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Zsh generates a zsh completion script, usable from fpath or with source
+// It finds the command being completed the same way as the bash script, and describes each
+// flag and subcommand with its usage
+func Zsh(spec Spec) string {
+	fn := "_" + functionName(spec.Program)
+	nodes := spec.nodes()
+	manifest, hasManifest := spec.manifestFlag()
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "#compdef %s\n# zsh completion for %s\n\n", spec.Program, spec.Program)
+	fmt.Fprintf(&out, "%s() {\n", fn)
+	out.WriteString("    local cur=\"${words[CURRENT]}\" prev=\"${words[CURRENT-1]}\"\n")
+	out.WriteString("    local cmd=\"\" word i skip=0\n")
+	out.WriteString("    local -a manifests entries\n\n")
+
+	// Find the command, skipping flag values and remembering the manifests for tool IDs
+	out.WriteString("    for ((i = 2; i < CURRENT; i++)); do\n")
+	out.WriteString("        word=\"${words[i]}\"\n")
+	out.WriteString("        if ((skip)); then\n            skip=0\n            continue\n        fi\n")
+	out.WriteString("        case \"${cmd}:${word}\" in\n")
+	for _, n := range commandsFirst(nodes) {
+		var flags []Flag
+		for _, f := range valueFlags(n.flags) {
+			if n.path == "" && hasManifest && f.Name == manifest.Name {
+				fmt.Fprintf(&out, "            (%s)\n", globalPatterns([]Flag{manifest}))
+				out.WriteString("                skip=1\n")
+				out.WriteString("                manifests+=(\"$word\" \"${words[i+1]}\")\n")
+				out.WriteString("                ;;\n")
+				continue
+			}
+			flags = append(flags, f)
+		}
+		if len(flags) > 0 {
+			fmt.Fprintf(&out, "            (%s) skip=1 ;;\n", n.patterns(flags))
+		}
+	}
+	for _, n := range nodes {
+		for _, sub := range n.command.Subcommands {
+			fmt.Fprintf(&out, "            (\"%s:%s\") cmd=\"%s\" ;;\n", n.path, sub.Name, strings.TrimSpace(n.path+" "+sub.Name))
+		}
+	}
+	out.WriteString("        esac\n")
+	out.WriteString("    done\n\n")
+
+	// Complete the value of the previous flag
+	out.WriteString("    if ((skip)); then\n")
+	out.WriteString("        case \"${cmd}:${prev}\" in\n")
+	for _, n := range commandsFirst(nodes) {
+		for _, f := range valueFlags(n.flags) {
+			switch {
+			case len(f.Values) > 0:
+				fmt.Fprintf(&out, "            (%s) compadd -- %s ;;\n", n.patterns([]Flag{f}), strings.Join(quoteAll(f.Values), " "))
+			case f.Files:
+				fmt.Fprintf(&out, "            (%s) _files ;;\n", n.patterns([]Flag{f}))
+			}
+		}
+	}
+	out.WriteString("        esac\n")
+	out.WriteString("        return\n")
+	out.WriteString("    fi\n\n")
+
+	// Complete flags
+	out.WriteString("    if [[ $cur == -* ]]; then\n")
+	out.WriteString("        case \"$cmd\" in\n")
+	for _, n := range nodes {
+		flags := spec.allFlags(n)
+		if len(flags) == 0 {
+			continue
+		}
+		var entries []string
+		for _, f := range flags {
+			entries = append(entries, singleQuote(flagName(f)+":"+f.Usage))
+		}
+		fmt.Fprintf(&out, "            (\"%s\") entries=(%s) ;;\n", n.path, strings.Join(entries, " "))
+	}
+	out.WriteString("        esac\n")
+	out.WriteString("        _describe -t flags flag entries\n")
+	out.WriteString("        return\n")
+	out.WriteString("    fi\n\n")
+
+	// Complete subcommands and arguments
+	toolIDs := fmt.Sprintf("${(f)\"$(${words[1]} $manifests %s 2>/dev/null)\"}", strings.Join(spec.ToolIDsArgs, " "))
+	out.WriteString("    case \"$cmd\" in\n")
+	for _, n := range nodes {
+		var actions []string
+		if len(n.command.Subcommands) > 0 {
+			var entries []string
+			for _, sub := range n.command.Subcommands {
+				entries = append(entries, singleQuote(sub.Name+":"+sub.Description))
+			}
+			actions = append(actions, fmt.Sprintf("entries=(%s); _describe -t commands command entries", strings.Join(entries, " ")))
+		}
+		if len(n.command.Args) > 0 {
+			actions = append(actions, "compadd -- "+strings.Join(quoteAll(n.command.Args), " "))
+		}
+		if n.command.ToolIDs && len(spec.ToolIDsArgs) > 0 {
+			actions = append(actions, "compadd -- "+toolIDs)
+		}
+		if n.command.Files {
+			actions = append(actions, "_files")
+		}
+		if len(actions) > 0 {
+			fmt.Fprintf(&out, "        (\"%s\") %s ;;\n", n.path, strings.Join(actions, "; "))
+		}
+	}
+	out.WriteString("    esac\n")
+	out.WriteString("}\n\n")
+
+	// Autoloaded from fpath, the file is the function body; sourced, it registers the function
+	fmt.Fprintf(&out, "if [[ \"$funcstack[1]\" == %q ]]; then\n    %s \"$@\"\nelse\n    compdef %s %s\nfi\n", fn, fn, fn, spec.Program)
+	return out.String()
+}
+
+// quoteAll quotes each string for the shell
+func quoteAll(values []string) []string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, singleQuote(v))
+	}
+	return quoted
+}
@@ -0,0 +1,89 @@
+// Package config reads the user configuration file, whose settings are defaults for every run
+// Command-line flags and environment variables take precedence over them
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the user configuration, usually ~/.config/goctor/config.yaml
+type Config struct {
+	// Manifest is the manifest path or URL used when -f is not given and no manifest is
+	// found in the current directory or its parents
+	Manifest string `yaml:"manifest"`
+	Format   string `yaml:"format"`
+	Color    string `yaml:"color"`
+	Lang     string `yaml:"lang"`
+	Parallel int    `yaml:"parallel"`
+	Cache    Cache  `yaml:"cache"`
+
+	// AuthToken is sent as a bearer token with remote manifest requests
+	AuthToken string `yaml:"auth_token"`
+	Report    Report `yaml:"report"`
+
+	// CACert is a PEM file of certificates trusted for remote manifests
+	CACert string `yaml:"ca_cert"`
+}
+
+// Cache configures the caches of recent check results and link checks
+type Cache struct {
+	Disabled  bool          `yaml:"disabled"`
+	ResultTTL time.Duration `yaml:"result_ttl"`
+	LinkTTL   time.Duration `yaml:"link_ttl"`
+}
+
+// Report configures where doctor reports are uploaded
+type Report struct {
+	URL   string `yaml:"url"`
+	Token string `yaml:"token"`
+	// Anonymize hashes the hostname and strips usernames from paths in every report, as
+	// --anonymize does
+	Anonymize bool `yaml:"anonymize"`
+}
+
+// Load reads the configuration at path; a missing or empty file is an empty configuration
+// Unknown keys are errors, so a misspelled setting does not go unnoticed
+func Load(path string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return Config{}, fmt.Errorf("parsing %s: %v", path, err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return Config{}, fmt.Errorf("%s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// validate checks the settings that have no flag to validate them
+func (c Config) validate() error {
+	if c.Parallel < 0 {
+		return fmt.Errorf("parallel must not be negative, got %d", c.Parallel)
+	}
+	if c.Cache.ResultTTL < 0 {
+		return fmt.Errorf("cache.result_ttl must not be negative, got %s", c.Cache.ResultTTL)
+	}
+	if c.Cache.LinkTTL < 0 {
+		return fmt.Errorf("cache.link_ttl must not be negative, got %s", c.Cache.LinkTTL)
+	}
+	return nil
+}
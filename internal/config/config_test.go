@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Config
+		wantErr string
+	}{
+		{
+			name: "all settings",
+			content: `manifest: https://example.com/tools.yaml
+format: markdown
+color: never
+lang: ja
+parallel: 4
+cache:
+  result_ttl: 30m
+  link_ttl: 1h
+auth_token: secret
+report:
+  url: https://fleet.example.com/reports
+  token: fleet
+ca_cert: /etc/ssl/corp.pem
+`,
+			want: Config{
+				Manifest:  "https://example.com/tools.yaml",
+				Format:    "markdown",
+				Color:     "never",
+				Lang:      "ja",
+				Parallel:  4,
+				Cache:     Cache{ResultTTL: 30 * time.Minute, LinkTTL: time.Hour},
+				AuthToken: "secret",
+				Report:    Report{URL: "https://fleet.example.com/reports", Token: "fleet"},
+				CACert:    "/etc/ssl/corp.pem",
+			},
+		},
+		{name: "empty file", content: ""},
+		{name: "comments only", content: "# nothing configured yet\n"},
+		{name: "unknown key", content: "formt: json\n", wantErr: "field formt not found"},
+		{name: "negative parallelism", content: "parallel: -1\n", wantErr: "parallel must not be negative"},
+		{name: "negative ttl", content: "cache:\n  link_ttl: -1h\n", wantErr: "cache.link_ttl must not be negative"},
+		{name: "invalid duration", content: "cache:\n  result_ttl: soon\n", wantErr: "parsing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Load(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Load() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	got, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil || got != (Config{}) {
+		t.Errorf("Load() = %+v, %v, want an empty configuration", got, err)
+	}
+}
@@ -0,0 +1,124 @@
+// Package events carries what happens during a check run to the consumers that care
+//
+// The checker reports plan, start, and result notifications through checker.CheckEvents; a
+// Bus turns them into events and hands each one to every subscriber, so progress output,
+// streaming formatters, and history recording stay independent of each other and of the
+// checker instead of each walking the results once the run is over.
+package events
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// Kind identifies what an event reports
+type Kind string
+
+// Event kinds, in the order a run publishes them
+const (
+	// RunStarted carries the tools about to be checked
+	RunStarted Kind = "run_started"
+	// CheckStarted carries the tool whose check began
+	CheckStarted Kind = "check_started"
+	// CheckFinished carries a tool's result, including checks skipped by dependencies
+	CheckFinished Kind = "check_finished"
+	// RunFinished carries the complete report, after external results are merged
+	RunFinished Kind = "run_finished"
+)
+
+// Event is one notification; only the field matching its kind is set
+type Event struct {
+	Kind   Kind
+	Tools  []manifest.ToolDefinition
+	Tool   manifest.ToolDefinition
+	Result checker.CheckResult
+	Report *checker.EnvironmentReport
+}
+
+// Handler consumes events
+type Handler func(Event)
+
+// subscription is a handler and the kinds it receives; no kinds means every kind
+type subscription struct {
+	id      int
+	kinds   []Kind
+	handler Handler
+}
+
+// Bus delivers published events to its subscribers
+// Delivery is synchronous and serialized: handlers run one event at a time, in the order
+// they subscribed, on the publishing goroutine. Handlers must not publish to the same bus.
+// A nil *Bus accepts and drops every event, so callers that do not observe a run can pass nil.
+type Bus struct {
+	mu            sync.Mutex
+	deliver       sync.Mutex
+	subscriptions []subscription
+	nextID        int
+}
+
+// NewBus creates a bus with no subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler for the given kinds, or every kind if none are given, and
+// returns a function that removes it
+func (b *Bus) Subscribe(handler Handler, kinds ...Kind) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	id := b.nextID
+	b.subscriptions = append(b.subscriptions, subscription{id: id, kinds: kinds, handler: handler})
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.subscriptions = slices.DeleteFunc(b.subscriptions, func(s subscription) bool { return s.id == id })
+	}
+}
+
+// Publish delivers an event to every subscriber interested in its kind
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	subscriptions := slices.Clone(b.subscriptions)
+	b.mu.Unlock()
+
+	b.deliver.Lock()
+	defer b.deliver.Unlock()
+	for _, s := range subscriptions {
+		if len(s.kinds) == 0 || slices.Contains(s.kinds, event.Kind) {
+			s.handler(event)
+		}
+	}
+}
+
+// CheckEvents returns checker callbacks that publish RunStarted, CheckStarted, and
+// CheckFinished events; prepare, when set, adjusts each result before it is published
+func (b *Bus) CheckEvents(prepare func(checker.CheckResult) checker.CheckResult) checker.CheckEvents {
+	if b == nil {
+		return checker.CheckEvents{}
+	}
+
+	return checker.CheckEvents{
+		OnPlan: func(tools []manifest.ToolDefinition) {
+			b.Publish(Event{Kind: RunStarted, Tools: tools})
+		},
+		OnStart: func(tool manifest.ToolDefinition) {
+			b.Publish(Event{Kind: CheckStarted, Tool: tool})
+		},
+		OnResult: func(result checker.CheckResult) {
+			if prepare != nil {
+				result = prepare(result)
+			}
+			b.Publish(Event{Kind: CheckFinished, Result: result})
+		},
+	}
+}
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+	"github.com/ikorihn/goctor/internal/scheduler"
+)
+
+func TestBusDelivery(t *testing.T) {
+	bus := NewBus()
+
+	var all, finished []string
+	bus.Subscribe(func(e Event) { all = append(all, string(e.Kind)) })
+	unsubscribe := bus.Subscribe(func(e Event) { finished = append(finished, e.Result.ToolID) }, CheckFinished)
+
+	bus.Publish(Event{Kind: CheckStarted, Tool: manifest.ToolDefinition{ID: "go"}})
+	bus.Publish(Event{Kind: CheckFinished, Result: checker.CheckResult{ToolID: "go"}})
+	unsubscribe()
+	bus.Publish(Event{Kind: CheckFinished, Result: checker.CheckResult{ToolID: "git"}})
+
+	if strings.Join(all, ",") != "check_started,check_finished,check_finished" {
+		t.Errorf("Unexpected events for the catch-all subscriber: %v", all)
+	}
+	if strings.Join(finished, ",") != "go" {
+		t.Errorf("Expected only go before unsubscribing, got %v", finished)
+	}
+}
+
+func TestNilBus(t *testing.T) {
+	var bus *Bus
+	bus.Publish(Event{Kind: RunFinished})
+
+	events := bus.CheckEvents(nil)
+	if events.OnPlan != nil || events.OnStart != nil || events.OnResult != nil {
+		t.Error("Expected no callbacks from a nil bus")
+	}
+}
+
+func TestBusCheckEvents(t *testing.T) {
+	bus := NewBus()
+
+	var kinds []string
+	var links []string
+	bus.Subscribe(func(e Event) {
+		kinds = append(kinds, string(e.Kind))
+		if e.Kind == CheckFinished {
+			links = append(links, e.Result.Links["homepage"])
+		}
+	})
+
+	tools := []manifest.ToolDefinition{
+		{
+			ID:              "tool",
+			Name:            "Tool",
+			RequiredVersion: ">=1.0",
+			Check:           manifest.CheckConfig{Command: []string{"echo", "tool 1.2.3"}, Regex: `(?P<ver>\d+\.\d+\.\d+)`},
+			Links:           map[string]string{"homepage": "wiki:tool"},
+		},
+	}
+	prepare := func(result checker.CheckResult) checker.CheckResult {
+		result.Links = map[string]string{"homepage": "https://wiki.example.com/tool"}
+		return result
+	}
+
+	platformInfo := platform.PlatformInfo{OS: "linux", Architecture: "amd64"}
+	if _, err := checker.NewChecker().CheckAll(context.Background(), tools, platformInfo, scheduler.Options{}, bus.CheckEvents(prepare)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if strings.Join(kinds, ",") != "run_started,check_started,check_finished" {
+		t.Errorf("Unexpected events: %v", kinds)
+	}
+	if len(links) != 1 || links[0] != "https://wiki.example.com/tool" {
+		t.Errorf("Expected the prepared result to be published, got %v", links)
+	}
+}
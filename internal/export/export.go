@@ -0,0 +1,293 @@
+// Package export converts manifests into files understood by other installers
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/semver"
+)
+
+// Export formats
+const (
+	FormatBrewfile     = "brewfile"
+	FormatToolVersions = "tool-versions"
+	FormatDevcontainer = "devcontainer"
+	FormatDockerfile   = "dockerfile"
+)
+
+// Formats lists the supported export formats
+var Formats = []string{FormatBrewfile, FormatToolVersions, FormatDevcontainer, FormatDockerfile}
+
+// asdfPlugins maps common tool IDs to their asdf plugin names where the two differ
+var asdfPlugins = map[string]string{
+	"node":   "nodejs",
+	"nodejs": "nodejs",
+	"go":     "golang",
+	"golang": "golang",
+	"k8s":    "kubectl",
+}
+
+// devcontainerFeatures maps common tool IDs to the dev container features that install them,
+// all of which take a version option
+var devcontainerFeatures = map[string]string{
+	"go":        "ghcr.io/devcontainers/features/go:1",
+	"golang":    "ghcr.io/devcontainers/features/go:1",
+	"node":      "ghcr.io/devcontainers/features/node:1",
+	"nodejs":    "ghcr.io/devcontainers/features/node:1",
+	"python":    "ghcr.io/devcontainers/features/python:1",
+	"python3":   "ghcr.io/devcontainers/features/python:1",
+	"java":      "ghcr.io/devcontainers/features/java:1",
+	"rust":      "ghcr.io/devcontainers/features/rust:1",
+	"ruby":      "ghcr.io/devcontainers/features/ruby:1",
+	"php":       "ghcr.io/devcontainers/features/php:1",
+	"dotnet":    "ghcr.io/devcontainers/features/dotnet:2",
+	"docker":    "ghcr.io/devcontainers/features/docker-in-docker:2",
+	"kubectl":   "ghcr.io/devcontainers/features/kubectl-helm-minikube:1",
+	"terraform": "ghcr.io/devcontainers/features/terraform:1",
+	"aws":       "ghcr.io/devcontainers/features/aws-cli:1",
+	"awscli":    "ghcr.io/devcontainers/features/aws-cli:1",
+	"az":        "ghcr.io/devcontainers/features/azure-cli:1",
+	"gh":        "ghcr.io/devcontainers/features/github-cli:1",
+	"git":       "ghcr.io/devcontainers/features/git:1",
+	"pwsh":      "ghcr.io/devcontainers/features/powershell:1",
+}
+
+// Export renders the tools in the given format
+func Export(format, name string, tools []manifest.ToolDefinition) (string, error) {
+	switch format {
+	case FormatBrewfile:
+		return Brewfile(name, tools), nil
+	case FormatToolVersions:
+		return ToolVersions(name, tools), nil
+	case FormatDevcontainer:
+		return Devcontainer(name, tools), nil
+	case FormatDockerfile:
+		return Dockerfile(name, tools), nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (must be one of: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Brewfile renders a Homebrew Brewfile from the tools' brew install hints
+// Brewfiles cannot pin versions, so each entry notes the manifest's requirement; tools
+// without a brew hint are listed as comments for the reader to resolve
+func Brewfile(name string, tools []manifest.ToolDefinition) string {
+	var out strings.Builder
+	writeHeader(&out, name)
+
+	for _, tool := range tools {
+		if tool.Informational {
+			continue
+		}
+
+		entries := brewEntries(tool.Install["brew"])
+		if len(entries) == 0 {
+			out.WriteString(fmt.Sprintf("# %s: no Homebrew install hint in the manifest\n", tool.ID))
+			continue
+		}
+
+		for _, entry := range entries {
+			out.WriteString(entry)
+			if tool.RequiredVersion != "" {
+				out.WriteString(fmt.Sprintf(" # %s %s", tool.Name, tool.RequiredVersion))
+			}
+			out.WriteString("\n")
+		}
+	}
+
+	return out.String()
+}
+
+// brewEntries turns a hint such as "brew install --cask docker" into Brewfile lines
+// Hints that are not brew install commands yield nothing
+func brewEntries(hint string) []string {
+	fields := strings.Fields(hint)
+	if len(fields) < 3 || fields[0] != "brew" || fields[1] != "install" {
+		return nil
+	}
+
+	kind := "brew"
+	var entries []string
+	for _, field := range fields[2:] {
+		switch {
+		case field == "--cask":
+			kind = "cask"
+		case strings.HasPrefix(field, "-"):
+			continue
+		default:
+			entries = append(entries, fmt.Sprintf("%s %q", kind, field))
+		}
+	}
+
+	return entries
+}
+
+// ToolVersions renders an asdf .tool-versions file pinning each tool to its minimum version
+// The plugin name comes from an "asdf" install hint when present, otherwise from the tool ID
+func ToolVersions(name string, tools []manifest.ToolDefinition) string {
+	var out strings.Builder
+	writeHeader(&out, name)
+
+	for _, tool := range tools {
+		if tool.Informational {
+			continue
+		}
+
+		version, ok := semver.MinimumVersion(tool.RequiredVersion)
+		if !ok {
+			out.WriteString(fmt.Sprintf("# %s: no minimum version in %q\n", tool.ID, tool.RequiredVersion))
+			continue
+		}
+
+		out.WriteString(fmt.Sprintf("%s %s\n", asdfPlugin(tool), version))
+	}
+
+	return out.String()
+}
+
+// asdfPlugin returns the asdf plugin that installs the tool
+func asdfPlugin(tool manifest.ToolDefinition) string {
+	// Hints look like "asdf plugin add nodejs" or "asdf install nodejs 20.11.0"
+	fields := strings.Fields(tool.Install["asdf"])
+	switch {
+	case len(fields) >= 4 && fields[0] == "asdf" && fields[1] == "plugin" && fields[2] == "add":
+		return fields[3]
+	case len(fields) >= 3 && fields[0] == "asdf" && fields[1] == "install":
+		return fields[2]
+	}
+
+	if plugin, ok := asdfPlugins[tool.ID]; ok {
+		return plugin
+	}
+	return tool.ID
+}
+
+// Devcontainer renders the features section of a devcontainer.json, whose JSON with comments
+// format lets tools without a feature be listed as comments for the reader to resolve
+// Each feature is pinned to the minimum version of the tool's requirement, or left at the
+// feature's default when the requirement has none
+func Devcontainer(name string, tools []manifest.ToolDefinition) string {
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("// Generated by goctor export from %q; edit the manifest instead\n", name))
+
+	var features []string
+	var entries []string
+	for _, tool := range tools {
+		if tool.Informational {
+			continue
+		}
+
+		feature := devcontainerFeature(tool)
+		switch {
+		case feature == "":
+			hint := "no dev container feature is known for the tool"
+			if len(aptPackages(tool.Install["apt"])) > 0 {
+				hint += "; install it with --format dockerfile"
+			}
+			out.WriteString(fmt.Sprintf("// %s: %s\n", tool.ID, hint))
+			continue
+		case slices.Contains(features, feature):
+			continue
+		}
+		features = append(features, feature)
+
+		options := "{}"
+		if version, ok := semver.MinimumVersion(tool.RequiredVersion); ok {
+			options = fmt.Sprintf(`{"version": %s}`, jsonString(version))
+		}
+		entries = append(entries, fmt.Sprintf("    %s: %s", jsonString(feature), options))
+	}
+
+	out.WriteString("{\n  \"features\": {")
+	if len(entries) > 0 {
+		out.WriteString("\n" + strings.Join(entries, ",\n") + "\n  ")
+	}
+	out.WriteString("}\n}\n")
+
+	return out.String()
+}
+
+// devcontainerFeature returns the dev container feature that installs the tool: the
+// "devcontainer" install hint when present, otherwise the feature known for the tool ID
+func devcontainerFeature(tool manifest.ToolDefinition) string {
+	if feature := strings.TrimSpace(tool.Install["devcontainer"]); feature != "" {
+		return feature
+	}
+	return devcontainerFeatures[tool.ID]
+}
+
+// Dockerfile renders a Dockerfile RUN block installing the packages of the tools' apt install
+// hints, for the Debian and Ubuntu images dev containers are usually built on
+// apt cannot install by constraint, so the requirements are noted in comments, as are the tools
+// without an apt hint
+func Dockerfile(name string, tools []manifest.ToolDefinition) string {
+	var out strings.Builder
+	writeHeader(&out, name)
+
+	var packages []string
+	for _, tool := range tools {
+		if tool.Informational {
+			continue
+		}
+
+		toolPackages := aptPackages(tool.Install["apt"])
+		if len(toolPackages) == 0 {
+			out.WriteString(fmt.Sprintf("# %s: no apt install hint in the manifest\n", tool.ID))
+			continue
+		}
+		if tool.RequiredVersion != "" {
+			out.WriteString(fmt.Sprintf("# %s: %s %s\n", strings.Join(toolPackages, " "), tool.Name, tool.RequiredVersion))
+		}
+		for _, pkg := range toolPackages {
+			if !slices.Contains(packages, pkg) {
+				packages = append(packages, pkg)
+			}
+		}
+	}
+
+	if len(packages) == 0 {
+		return out.String()
+	}
+	out.WriteString("RUN apt-get update \\\n    && apt-get install -y --no-install-recommends \\\n")
+	for _, pkg := range packages {
+		out.WriteString(fmt.Sprintf("        %s \\\n", pkg))
+	}
+	out.WriteString("    && rm -rf /var/lib/apt/lists/*\n")
+
+	return out.String()
+}
+
+// aptPackages returns the packages of a hint such as "sudo apt-get install -y jq"
+// Hints that are not apt install commands yield nothing
+func aptPackages(hint string) []string {
+	fields := strings.Fields(hint)
+	if len(fields) > 0 && fields[0] == "sudo" {
+		fields = fields[1:]
+	}
+	if len(fields) < 3 || (fields[0] != "apt" && fields[0] != "apt-get") || fields[1] != "install" {
+		return nil
+	}
+
+	var packages []string
+	for _, field := range fields[2:] {
+		if !strings.HasPrefix(field, "-") {
+			packages = append(packages, field)
+		}
+	}
+	return packages
+}
+
+// jsonString quotes s as a JSON string
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// writeHeader notes where the file came from
+func writeHeader(out *strings.Builder, name string) {
+	out.WriteString(fmt.Sprintf("# Generated by goctor export from %q; edit the manifest instead\n", name))
+}
@@ -0,0 +1,120 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+var exportTools = []manifest.ToolDefinition{
+	{ID: "go", Name: "Go", RequiredVersion: ">=1.22", Install: map[string]string{"brew": "brew install go"}},
+	{ID: "node", Name: "Node.js", RequiredVersion: "^20.11.0", Install: map[string]string{"asdf": "asdf plugin add nodejs"}},
+	{ID: "docker", Name: "Docker", RequiredVersion: "<99", Install: map[string]string{"brew": "brew install --cask docker"}},
+	{ID: "terraform", Name: "Terraform", RequiredVersion: ">=1.6 <2", Install: map[string]string{"brew": "brew install hashicorp/tap/terraform"}},
+	{ID: "vpn", Name: "VPN", Informational: true, Install: map[string]string{"brew": "brew install vpn"}},
+}
+
+func TestBrewfile(t *testing.T) {
+	got := Brewfile("team", exportTools)
+	want := `# Generated by goctor export from "team"; edit the manifest instead
+brew "go" # Go >=1.22
+# node: no Homebrew install hint in the manifest
+cask "docker" # Docker <99
+brew "hashicorp/tap/terraform" # Terraform >=1.6 <2
+`
+	if got != want {
+		t.Errorf("Brewfile mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToolVersions(t *testing.T) {
+	got := ToolVersions("team", exportTools)
+	want := `# Generated by goctor export from "team"; edit the manifest instead
+golang 1.22
+nodejs 20.11.0
+# docker: no minimum version in "<99"
+terraform 1.6
+`
+	if got != want {
+		t.Errorf("ToolVersions mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+var containerTools = []manifest.ToolDefinition{
+	{ID: "go", Name: "Go", RequiredVersion: ">=1.22"},
+	{ID: "golang", Name: "Go", RequiredVersion: ">=1.21"},
+	{ID: "node", Name: "Node.js", Install: map[string]string{"devcontainer": "ghcr.io/example/features/node:2"}},
+	{ID: "jq", Name: "jq", RequiredVersion: ">=1.6", Install: map[string]string{"apt": "sudo apt-get install -y jq"}},
+	{ID: "psql", Name: "PostgreSQL client", Install: map[string]string{"apt": "apt install postgresql-client jq"}},
+	{ID: "xcode", Name: "Xcode", Install: map[string]string{"brew": "brew install xcodes"}},
+	{ID: "vpn", Name: "VPN", Informational: true, Install: map[string]string{"apt": "apt install vpn"}},
+}
+
+func TestDevcontainer(t *testing.T) {
+	tests := []struct {
+		name  string
+		tools []manifest.ToolDefinition
+		want  string
+	}{
+		{
+			name:  "features",
+			tools: containerTools,
+			want: `// Generated by goctor export from "team"; edit the manifest instead
+// jq: no dev container feature is known for the tool; install it with --format dockerfile
+// psql: no dev container feature is known for the tool; install it with --format dockerfile
+// xcode: no dev container feature is known for the tool
+{
+  "features": {
+    "ghcr.io/devcontainers/features/go:1": {"version": "1.22"},
+    "ghcr.io/example/features/node:2": {}
+  }
+}
+`,
+		},
+		{
+			name:  "no features",
+			tools: containerTools[5:],
+			want: `// Generated by goctor export from "team"; edit the manifest instead
+// xcode: no dev container feature is known for the tool
+{
+  "features": {}
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Devcontainer("team", tt.tools); got != tt.want {
+				t.Errorf("Devcontainer mismatch\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDockerfile(t *testing.T) {
+	got := Dockerfile("team", containerTools)
+	want := `# Generated by goctor export from "team"; edit the manifest instead
+# go: no apt install hint in the manifest
+# golang: no apt install hint in the manifest
+# node: no apt install hint in the manifest
+# jq: jq >=1.6
+# xcode: no apt install hint in the manifest
+RUN apt-get update \
+    && apt-get install -y --no-install-recommends \
+        jq \
+        postgresql-client \
+    && rm -rf /var/lib/apt/lists/*
+`
+	if got != want {
+		t.Errorf("Dockerfile mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	_, err := Export("npmrc", "team", exportTools)
+	if err == nil || !strings.Contains(err.Error(), "unknown export format") {
+		t.Errorf("Expected unknown format error, got: %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+// Package fixtures generates the manifests the contract and integration tests use
+//
+// The sample and missing-tools manifests are embedded verbatim; the large manifest is
+// synthesized so performance can be measured at any number of tools without real
+// installations: its tools check `echo`, which exists on every supported platform.
+package fixtures
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// DefaultDir is where fixtures are written, relative to the repository root
+const DefaultDir = "testdata/manifests"
+
+// Fixture file names
+const (
+	SampleName       = "sample.yaml"
+	MissingToolsName = "missing-tools.yaml"
+	LargeName        = "large.yaml"
+)
+
+// DefaultLargeTools is the number of tools in the synthetic manifest
+const DefaultLargeTools = 150
+
+// Options control the generated fixtures
+type Options struct {
+	// LargeTools is the number of tools in the synthetic manifest
+	LargeTools int
+	// LargeMissing is how many of those tools check a command that does not exist
+	LargeMissing int
+}
+
+// Fixture is one generated manifest
+type Fixture struct {
+	Name    string
+	Content []byte
+}
+
+// sampleManifest has tools most contributors have installed
+const sampleManifest = `meta:
+  version: 1
+  name: "Test Manifest"
+  language: "en"
+
+defaults:
+  timeout_sec: 5
+  regex_key: "ver"
+
+tools:
+  - id: go
+    name: "Go"
+    rationale: "Go development toolchain"
+    require: ">=1.22"
+    check:
+      cmd: ["go", "version"]
+      regex: "go(?P<ver>\\d+\\.\\d+(\\.\\d+)?)"
+    links:
+      homepage: "https://go.dev/"
+      download: "https://go.dev/dl/"
+      docs: "https://go.dev/doc/"
+      github: "https://github.com/golang/go"
+
+  - id: git
+    name: "Git"
+    rationale: "Version control system"
+    require: ">=2.30"
+    check:
+      cmd: ["git", "--version"]
+      regex: "git version (?P<ver>\\d+\\.\\d+\\.\\d+)"
+    links:
+      homepage: "https://git-scm.com/"
+      download: "https://git-scm.com/downloads"
+      docs: "https://git-scm.com/doc"
+
+  - id: docker
+    name: "Docker"
+    rationale: "Container platform for development"
+    require: ">=24"
+    check:
+      cmd: ["docker", "--version"]
+      regex: "version (?P<ver>\\d+\\.\\d+\\.\\d+)"
+    links:
+      homepage: "https://www.docker.com/"
+      download: "https://docs.docker.com/get-docker/"
+      docs: "https://docs.docker.com/"
+`
+
+// missingToolsManifest always fails: one tool does not exist and one requires an
+// unreleased version of Go
+const missingToolsManifest = `meta:
+  version: 1
+  name: "Missing Tools Manifest"
+  language: "en"
+
+defaults:
+  timeout_sec: 5
+  regex_key: "ver"
+
+tools:
+  - id: nonexistent-tool
+    name: "Nonexistent Tool"
+    rationale: "A tool that is never installed"
+    require: ">=1.0"
+    check:
+      cmd: ["goctor-nonexistent-tool", "--version"]
+      regex: "(?P<ver>\\d+\\.\\d+\\.\\d+)"
+    links:
+      homepage: "https://example.com/nonexistent-tool"
+
+  - id: go
+    name: "Go"
+    rationale: "A Go release that does not exist yet"
+    require: ">=99.0"
+    check:
+      cmd: ["go", "version"]
+      regex: "go(?P<ver>\\d+\\.\\d+(\\.\\d+)?)"
+    links:
+      homepage: "https://go.dev/"
+`
+
+// Generate returns the fixtures in the order they are written
+func Generate(opts Options) ([]Fixture, error) {
+	large, err := Large(opts.LargeTools, opts.LargeMissing)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Fixture{
+		{Name: SampleName, Content: []byte(sampleManifest)},
+		{Name: MissingToolsName, Content: []byte(missingToolsManifest)},
+		{Name: LargeName, Content: large},
+	}, nil
+}
+
+// Write generates the fixtures into dir, replacing existing files, and returns their paths
+func Write(dir string, opts Options) ([]string, error) {
+	fixtures, err := Generate(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for _, fixture := range fixtures {
+		path := filepath.Join(dir, fixture.Name)
+		if err := os.WriteFile(path, fixture.Content, 0644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// Large builds a synthetic manifest with the given number of tools, the last missing of
+// which check commands that do not exist
+// Every tenth tool is a warning and every fifteenth informational, so summaries and
+// severity sorting have something to show
+func Large(tools, missing int) ([]byte, error) {
+	if tools < 1 {
+		return nil, errors.New("large manifest needs at least one tool")
+	}
+	if missing < 0 || missing > tools {
+		return nil, fmt.Errorf("missing tools must be between 0 and %d, got %d", tools, missing)
+	}
+
+	m := manifest.Manifest{
+		Meta:     manifest.ManifestMeta{Version: manifest.CurrentVersion, Name: fmt.Sprintf("Synthetic Manifest (%d tools)", tools)},
+		Defaults: manifest.ManifestDefaults{TimeoutSeconds: 5},
+		Tools:    make([]manifest.ToolDefinition, 0, tools),
+	}
+
+	for i := 1; i <= tools; i++ {
+		id := fmt.Sprintf("synthetic-%03d", i)
+		tool := manifest.ToolDefinition{
+			ID:              id,
+			Name:            fmt.Sprintf("Synthetic Tool %d", i),
+			Rationale:       "Generated for performance testing",
+			RequiredVersion: ">=1.0.0",
+			Check: manifest.CheckConfig{
+				Command: []string{"echo", fmt.Sprintf("%s version 1.%d.0", id, i)},
+				Regex:   `version (?P<ver>\d+\.\d+\.\d+)`,
+			},
+			Links: map[string]string{"homepage": "https://example.com/tools/" + id},
+			Tags:  []string{fmt.Sprintf("group-%d", i%5)},
+		}
+		if i > tools-missing {
+			tool.Check.Command = []string{"goctor-fixture-missing-" + id, "--version"}
+		}
+		switch {
+		case i%15 == 0:
+			tool.Informational = true
+		case i%10 == 0:
+			tool.Severity = manifest.SeverityWarning
+		}
+		m.Tools = append(m.Tools, tool)
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("generated manifest is invalid: %v", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by 'goctor doctor dev gen-fixtures'; do not edit\n")
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(m); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,79 @@
+package fixtures
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+func TestWriteProducesLoadableManifests(t *testing.T) {
+	dir := t.TempDir()
+
+	written, err := Write(dir, Options{LargeTools: 120, LargeMissing: 5})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(written) != 3 {
+		t.Fatalf("Expected 3 fixtures, got %d", len(written))
+	}
+
+	loader := manifest.NewLoader()
+	for _, path := range written {
+		if _, err := loader.LoadFromFile(path); err != nil {
+			t.Errorf("Fixture %s does not load: %v", filepath.Base(path), err)
+		}
+	}
+
+	large, err := loader.LoadFromFile(filepath.Join(dir, LargeName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(large.Tools) != 120 {
+		t.Errorf("Expected 120 tools, got %d", len(large.Tools))
+	}
+
+	missing := 0
+	for _, tool := range large.Tools {
+		if strings.HasPrefix(tool.Check.Command[0], "goctor-fixture-missing-") {
+			missing++
+		}
+	}
+	if missing != 5 {
+		t.Errorf("Expected 5 missing tools, got %d", missing)
+	}
+}
+
+func TestLargeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		tools   int
+		missing int
+		wantErr string
+	}{
+		{"no tools", 0, 0, "at least one tool"},
+		{"too many missing", 10, 11, "between 0 and 10"},
+		{"negative missing", 10, -1, "between 0 and 10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Large(tt.tools, tt.missing)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSampleMatchesTestdata(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", DefaultDir, SampleName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != sampleManifest {
+		t.Errorf("%s/%s is out of date; run 'goctor doctor dev gen-fixtures'", DefaultDir, SampleName)
+	}
+}
@@ -0,0 +1,221 @@
+// Package history keeps the reports of past doctor runs so environment drift can be traced
+//
+// Each run is stored as a `goctor doctor --json` report named after the time it was
+// generated, in UTC, e.g. 20241015T093000Z.json. Only the newest runs are kept.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+// DefaultLimit is the number of runs kept
+const DefaultLimit = 100
+
+// idLayout formats run IDs; IDs sort in chronological order
+const idLayout = "20060102T150405Z"
+
+// Latest selects the most recent run in Load
+const Latest = "latest"
+
+// Entry describes one recorded run
+type Entry struct {
+	ID             string               `json:"id"`
+	Time           time.Time            `json:"time"`
+	ManifestSource string               `json:"manifest_source"`
+	Summary        checker.CheckSummary `json:"summary"`
+	ExitCode       int                  `json:"exit_code"`
+	Path           string               `json:"path"`
+}
+
+// Store reads and writes run reports in a directory
+type Store struct {
+	dir   string
+	limit int
+}
+
+// NewStore returns a store keeping DefaultLimit runs in dir
+func NewStore(dir string) *Store {
+	return &Store{dir: dir, limit: DefaultLimit}
+}
+
+// SetLimit sets how many runs are kept; values below 1 keep every run
+func (s *Store) SetLimit(limit int) {
+	s.limit = limit
+}
+
+// Record saves a report and prunes the oldest runs beyond the limit
+// A run generated in the same second as an existing one replaces it
+func (s *Store) Record(report checker.EnvironmentReport) (Entry, error) {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return Entry{}, err
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return Entry{}, err
+	}
+
+	id := report.GeneratedAt.UTC().Format(idLayout)
+	path := filepath.Join(s.dir, id+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return Entry{}, err
+	}
+
+	if err := s.prune(); err != nil {
+		return Entry{}, err
+	}
+
+	return newEntry(id, path, report), nil
+}
+
+// List returns the recorded runs, newest first
+// Files that are not readable reports are skipped
+func (s *Store) List() ([]Entry, error) {
+	ids, err := s.ids()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		path := s.path(ids[i])
+		report, err := checker.LoadEnvironmentReport(path)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, newEntry(ids[i], path, *report))
+	}
+
+	return entries, nil
+}
+
+// Load returns the run with the given ID, Latest, or a prefix matching exactly one ID
+func (s *Store) Load(id string) (*checker.EnvironmentReport, Entry, error) {
+	ids, err := s.ids()
+	if err != nil {
+		return nil, Entry{}, err
+	}
+	if len(ids) == 0 {
+		return nil, Entry{}, errors.New("no runs recorded yet")
+	}
+
+	var matches []string
+	if id == Latest {
+		matches = ids[len(ids)-1:]
+	} else {
+		for _, candidate := range ids {
+			if candidate == id {
+				matches = []string{candidate}
+				break
+			}
+			if strings.HasPrefix(candidate, id) {
+				matches = append(matches, candidate)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, Entry{}, fmt.Errorf("no run matches %q", id)
+	case 1:
+	default:
+		return nil, Entry{}, fmt.Errorf("%q matches %d runs: %s", id, len(matches), strings.Join(matches, ", "))
+	}
+
+	path := s.path(matches[0])
+	report, err := checker.LoadEnvironmentReport(path)
+	if err != nil {
+		return nil, Entry{}, err
+	}
+
+	return report, newEntry(matches[0], path, *report), nil
+}
+
+// Previous returns the run recorded just before the one with the given ID
+func (s *Store) Previous(id string) (*checker.EnvironmentReport, Entry, error) {
+	ids, err := s.ids()
+	if err != nil {
+		return nil, Entry{}, err
+	}
+
+	i := sort.SearchStrings(ids, id)
+	if i == 0 {
+		return nil, Entry{}, fmt.Errorf("no run recorded before %s", id)
+	}
+
+	return s.Load(ids[i-1])
+}
+
+// ids returns the IDs of the recorded runs in chronological order
+func (s *Store) ids() ([]string, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ids []string
+	for _, file := range files {
+		id, ok := strings.CutSuffix(file.Name(), ".json")
+		if !ok || file.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(idLayout, id); err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// prune removes the oldest runs beyond the limit
+func (s *Store) prune() error {
+	if s.limit < 1 {
+		return nil
+	}
+
+	ids, err := s.ids()
+	if err != nil {
+		return err
+	}
+
+	for len(ids) > s.limit {
+		if err := os.Remove(s.path(ids[0])); err != nil {
+			return err
+		}
+		ids = ids[1:]
+	}
+
+	return nil
+}
+
+// path returns the report file of a run
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// newEntry describes a recorded report
+func newEntry(id, path string, report checker.EnvironmentReport) Entry {
+	when, _ := time.Parse(idLayout, id)
+	return Entry{
+		ID:             id,
+		Time:           when,
+		ManifestSource: report.ManifestSource,
+		Summary:        report.Summary,
+		ExitCode:       report.GetExitCode(),
+		Path:           path,
+	}
+}
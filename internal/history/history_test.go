@@ -0,0 +1,118 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+func report(at time.Time, status checker.CheckStatus) checker.EnvironmentReport {
+	r := checker.NewEnvironmentReport(nil, "tools.yaml", []checker.CheckResult{
+		{ToolID: "go", ToolName: "Go", Status: status, ActualVersion: "1.22.1"},
+	})
+	r.GeneratedAt = at
+	return *r
+}
+
+func TestRecordAndList(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "history")
+	store := NewStore(dir)
+	store.SetLimit(2)
+
+	base := time.Date(2024, 10, 15, 9, 30, 0, 0, time.UTC)
+	for i, status := range []checker.CheckStatus{checker.StatusOK, checker.StatusOutdated, checker.StatusOK} {
+		if _, err := store.Record(report(base.Add(time.Duration(i)*time.Hour), status)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// Unrelated files are ignored
+	if err := os.WriteFile(filepath.Join(dir, "notes.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	if strings.Join(ids, ",") != "20241015T113000Z,20241015T103000Z" {
+		t.Fatalf("Expected the two newest runs, newest first, got %v", ids)
+	}
+	if entries[1].ExitCode != 1 || entries[1].Summary.Outdated != 1 {
+		t.Errorf("Expected the outdated run to be summarized, got %+v", entries[1])
+	}
+	if !entries[0].Time.Equal(base.Add(2 * time.Hour)) {
+		t.Errorf("Unexpected time %v", entries[0].Time)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	base := time.Date(2024, 10, 15, 9, 30, 0, 0, time.UTC)
+	for _, offset := range []time.Duration{0, time.Minute, 24 * time.Hour} {
+		if _, err := store.Record(report(base.Add(offset), checker.StatusOK)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		wantID  string
+		wantErr string
+	}{
+		{"exact", "20241015T093100Z", "20241015T093100Z", ""},
+		{"latest", Latest, "20241016T093000Z", ""},
+		{"unique prefix", "20241016", "20241016T093000Z", ""},
+		{"ambiguous prefix", "20241015", "", "matches 2 runs"},
+		{"unknown", "2023", "", "no run matches"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, entry, err := store.Load(tt.id)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if entry.ID != tt.wantID {
+				t.Errorf("Expected %s, got %s", tt.wantID, entry.ID)
+			}
+		})
+	}
+
+	_, previous, err := store.Previous("20241016T093000Z")
+	if err != nil || previous.ID != "20241015T093100Z" {
+		t.Errorf("Expected previous run 20241015T093100Z, got %s (%v)", previous.ID, err)
+	}
+	if _, _, err := store.Previous("20241015T093000Z"); err == nil {
+		t.Error("Expected error for the oldest run")
+	}
+}
+
+func TestLoadEmpty(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "missing"))
+
+	entries, err := store.List()
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no runs, got %d (%v)", len(entries), err)
+	}
+	if _, _, err := store.Load(Latest); err == nil || !strings.Contains(err.Error(), "no runs recorded yet") {
+		t.Errorf("Expected no runs error, got: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+// Package i18n localizes goctor's human-readable output
+//
+// The language is chosen from the --lang flag, then the manifest's meta.language, then
+// the LC_ALL, LC_MESSAGES, and LANG environment variables, falling back to English.
+// Messages missing from a catalog fall back to English as well.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported languages
+const (
+	English  = "en"
+	Japanese = "ja"
+)
+
+// DefaultLanguage is used when nothing selects a supported language
+const DefaultLanguage = English
+
+// Languages lists the supported language codes
+var Languages = []string{English, Japanese}
+
+// localeVariables are checked in POSIX precedence order
+var localeVariables = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+
+// Printer renders messages in one language
+type Printer struct {
+	lang     string
+	messages map[string]string
+}
+
+// NewPrinter returns a printer for lang; unsupported languages print English
+func NewPrinter(lang string) *Printer {
+	lang = Normalize(lang)
+	messages, ok := catalogs[lang]
+	if !ok {
+		lang = DefaultLanguage
+		messages = catalogs[DefaultLanguage]
+	}
+
+	return &Printer{lang: lang, messages: messages}
+}
+
+// Language returns the language the printer renders
+func (p *Printer) Language() string {
+	return p.lang
+}
+
+// Sprintf formats the message identified by key; unknown keys are rendered as the key itself
+func (p *Printer) Sprintf(key string, args ...interface{}) string {
+	format, ok := p.messages[key]
+	if !ok {
+		if format, ok = catalogs[DefaultLanguage][key]; !ok {
+			format = key
+		}
+	}
+
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Normalize reduces a language tag or locale such as "ja_JP.UTF-8" or "en-US" to its
+// lowercase language code; the C and POSIX locales have no language
+func Normalize(value string) string {
+	value = strings.TrimSpace(value)
+	if i := strings.IndexAny(value, "_-.@"); i >= 0 {
+		value = value[:i]
+	}
+
+	value = strings.ToLower(value)
+	if value == "c" || value == "posix" {
+		return ""
+	}
+	return value
+}
+
+// Supported returns true if lang normalizes to a language with a message catalog
+func Supported(lang string) bool {
+	_, ok := catalogs[Normalize(lang)]
+	return ok
+}
+
+// Resolve picks the output language from the flag, the manifest, and the environment,
+// in that order; values naming unsupported languages are skipped
+func Resolve(flagLang, manifestLang string, getenv func(string) string) string {
+	candidates := []string{flagLang, manifestLang}
+
+	// Only the first locale variable that is set applies, as in POSIX
+	for _, name := range localeVariables {
+		if value := getenv(name); value != "" {
+			candidates = append(candidates, value)
+			break
+		}
+	}
+
+	for _, candidate := range candidates {
+		if Supported(candidate) {
+			return Normalize(candidate)
+		}
+	}
+
+	return DefaultLanguage
+}
@@ -0,0 +1,97 @@
+package i18n
+
+import (
+	"testing"
+)
+
+func TestCatalogsComplete(t *testing.T) {
+	for _, lang := range Languages {
+		catalog, ok := catalogs[lang]
+		if !ok {
+			t.Fatalf("Expected a catalog for %s", lang)
+		}
+		for key := range catalogs[DefaultLanguage] {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("Catalog %s is missing %q", lang, key)
+			}
+		}
+		for key := range catalog {
+			if _, ok := catalogs[DefaultLanguage][key]; !ok {
+				t.Errorf("Catalog %s defines %q, which English does not", lang, key)
+			}
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"ja", "ja"},
+		{"ja_JP.UTF-8", "ja"},
+		{"en-US", "en"},
+		{"EN", "en"},
+		{"de_DE@euro", "de"},
+		{"C", ""},
+		{"POSIX", ""},
+		{"C.UTF-8", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.value); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		flag     string
+		manifest string
+		env      map[string]string
+		want     string
+	}{
+		{"default", "", "", nil, English},
+		{"flag wins", "ja", "en", map[string]string{"LANG": "en_US.UTF-8"}, Japanese},
+		{"manifest before environment", "", "ja", map[string]string{"LANG": "en_US.UTF-8"}, Japanese},
+		{"environment", "", "", map[string]string{"LANG": "ja_JP.UTF-8"}, Japanese},
+		{"unsupported manifest falls through", "", "fr", map[string]string{"LANG": "ja_JP.UTF-8"}, Japanese},
+		{"LC_ALL overrides LANG", "", "", map[string]string{"LC_ALL": "ja_JP.UTF-8", "LANG": "en_US.UTF-8"}, Japanese},
+		{"first set locale variable applies", "", "", map[string]string{"LC_ALL": "fr_FR.UTF-8", "LANG": "ja_JP.UTF-8"}, English},
+		{"C locale", "", "", map[string]string{"LANG": "C"}, English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(name string) string { return tt.env[name] }
+			if got := Resolve(tt.flag, tt.manifest, getenv); got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	ja := NewPrinter("ja_JP.UTF-8")
+	if ja.Language() != Japanese {
+		t.Errorf("Expected Japanese printer, got %s", ja.Language())
+	}
+	if got := ja.Sprintf("summary.total", 3); got != "ツール数: 3" {
+		t.Errorf("Unexpected Japanese message: %q", got)
+	}
+
+	fallback := NewPrinter("fr")
+	if fallback.Language() != English {
+		t.Errorf("Expected unsupported language to fall back to English, got %s", fallback.Language())
+	}
+	if got := fallback.Sprintf("summary.total", 3); got != "Total tools: 3" {
+		t.Errorf("Unexpected English message: %q", got)
+	}
+
+	if got := fallback.Sprintf("no.such.key"); got != "no.such.key" {
+		t.Errorf("Expected unknown key to render as itself, got %q", got)
+	}
+}
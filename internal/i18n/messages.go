@@ -0,0 +1,178 @@
+package i18n
+
+// catalogs holds the messages of each supported language, keyed by message ID
+// English is the reference catalog; every other catalog should define the same keys
+var catalogs = map[string]map[string]string{
+	English: {
+		// Report header
+		"report.title":     "Development Environment Check",
+		"report.platform":  "Platform: %s/%s",
+		"report.manifest":  "Manifest: %s",
+		"report.generated": "Generated: %s",
+
+		// Summary section
+		"summary.title":         "Summary:",
+		"summary.total":         "Total tools: %d",
+		"summary.ok":            "%d tools OK",
+		"summary.missing":       "%d tools missing",
+		"summary.outdated":      "%d tools outdated",
+		"summary.errors":        "%d tools with errors",
+		"summary.warnings":      "%d warnings (non-blocking)",
+		"summary.informational": "%d informational tools",
+		"summary.skipped":       "%d tools skipped",
+
+		// Detailed results
+		"results.title":            "Detailed Results:",
+		"results.none":             "No tools need attention",
+		"results.scope.machine":    "Machine tools:",
+		"results.scope.project":    "Project tools:",
+		"results.scope.none":       "Other tools:",
+		"result.informational":     "[informational]",
+		"result.warning":           "[warning]",
+		"result.installed":         "Installed: %s",
+		"result.required":          "Required:  %s",
+		"result.recommended":       "Recommended: %s",
+		"result.path":              "Path:      %s",
+		"result.resolved_command":  "Command:   %s",
+		"result.managed_by":        "Managed by: %s",
+		"result.source":            "Source:    %s",
+		"result.duration":          "Took:      %s",
+		"result.trace":             "Trace:",
+		"result.error":             "Error:",
+		"result.subchecks":         "Sub-checks:",
+		"result.variants":          "Variants:",
+		"result.subcheck_required": "(%s required)",
+		"result.not_found":         "Tool not found in PATH",
+		"result.skipped":           "skipped: %s",
+		"result.outdated":          "Installed version does not meet requirements",
+		"result.blocked":           "Installed version is blocked by the manifest",
+		"result.below_recommended": "Installed version is below the recommended %s",
+		"result.deprecated":        "Deprecated: remove it or migrate away",
+		"result.migration":         "Migration guide: %s",
+		"result.sunset":            "Sunset: %s",
+
+		// Recommendations
+		"recommendations.title":        "Recommendations:",
+		"recommendation.upgrade":       "Consider upgrading to %s",
+		"recommendation.install":       "Install this tool to continue development",
+		"recommendation.update":        "Update to version %s or later",
+		"recommendation.check_install": "Check tool installation and PATH configuration",
+		"recommendation.deprecated":    "Migrate away from this tool and uninstall it",
+		"recommendation.blocked":       "Switch to a version that is not blocked",
+		"recommendation.install_hint":  "Install: %s",
+		"links":                        "Links:",
+
+		// Tool list
+		"list.title":            "Tools defined in manifest (%s):",
+		"list.required_version": "Required version: %s",
+		"list.rationale":        "Rationale: %s",
+		"list.status":           "Status: %s",
+		"list.severity":         "Severity: %s",
+		"list.tags":             "Tags: %s",
+		"list.scope":            "Scope: %s",
+		"list.platforms":        "Platforms: %s",
+
+		// Quick summary
+		"quick.ready":     "All %d tools are ready",
+		"quick.attention": "%d of %d tools need attention",
+
+		// Watch mode
+		"watch.fixed": "%s (%s) fixed: %s -> %s",
+		"watch.broke": "%s (%s) broke: %s -> %s",
+
+		// Status words
+		"status.ok":        "ok",
+		"status.missing":   "missing",
+		"status.not_found": "not_found",
+		"status.outdated":  "outdated",
+		"status.error":     "error",
+		"status.skipped":   "skipped",
+		"status.unknown":   "unknown",
+	},
+	Japanese: {
+		// Report header
+		"report.title":     "開発環境チェック",
+		"report.platform":  "プラットフォーム: %s/%s",
+		"report.manifest":  "マニフェスト: %s",
+		"report.generated": "生成日時: %s",
+
+		// Summary section
+		"summary.title":         "概要:",
+		"summary.total":         "ツール数: %d",
+		"summary.ok":            "%d 個のツールが正常です",
+		"summary.missing":       "%d 個のツールが見つかりません",
+		"summary.outdated":      "%d 個のツールが古いバージョンです",
+		"summary.errors":        "%d 個のツールでエラーが発生しました",
+		"summary.warnings":      "%d 件の警告 (終了コードには影響しません)",
+		"summary.informational": "%d 個の参考情報ツール",
+		"summary.skipped":       "%d 個のツールをスキップしました",
+
+		// Detailed results
+		"results.title":            "詳細結果:",
+		"results.none":             "対応が必要なツールはありません",
+		"results.scope.machine":    "マシンのツール:",
+		"results.scope.project":    "プロジェクトのツール:",
+		"results.scope.none":       "その他のツール:",
+		"result.informational":     "[参考情報]",
+		"result.warning":           "[警告]",
+		"result.installed":         "インストール済み: %s",
+		"result.required":          "必要なバージョン: %s",
+		"result.recommended":       "推奨バージョン: %s",
+		"result.path":              "パス: %s",
+		"result.resolved_command":  "使用したコマンド: %s",
+		"result.managed_by":        "管理ツール: %s",
+		"result.source":            "取得元: %s",
+		"result.duration":          "所要時間: %s",
+		"result.trace":             "トレース:",
+		"result.error":             "エラー:",
+		"result.subchecks":         "サブチェック:",
+		"result.variants":          "バリアント:",
+		"result.subcheck_required": "(%s が必要)",
+		"result.not_found":         "PATH にツールが見つかりません",
+		"result.skipped":           "スキップ: %s",
+		"result.outdated":          "インストール済みのバージョンが要件を満たしていません",
+		"result.blocked":           "インストール済みのバージョンはマニフェストでブロックされています",
+		"result.below_recommended": "インストール済みのバージョンが推奨バージョン %s より古いです",
+		"result.deprecated":        "非推奨です: 削除するか移行してください",
+		"result.migration":         "移行ガイド: %s",
+		"result.sunset":            "廃止日: %s",
+
+		// Recommendations
+		"recommendations.title":        "推奨対応:",
+		"recommendation.upgrade":       "%s へのアップグレードを検討してください",
+		"recommendation.install":       "開発を続けるにはこのツールをインストールしてください",
+		"recommendation.update":        "バージョン %s 以降に更新してください",
+		"recommendation.check_install": "ツールのインストール状況と PATH の設定を確認してください",
+		"recommendation.deprecated":    "このツールから移行し、アンインストールしてください",
+		"recommendation.blocked":       "ブロックされていないバージョンに切り替えてください",
+		"recommendation.install_hint":  "インストール: %s",
+		"links":                        "リンク:",
+
+		// Tool list
+		"list.title":            "マニフェストで定義されたツール (%s):",
+		"list.required_version": "必要なバージョン: %s",
+		"list.rationale":        "理由: %s",
+		"list.status":           "状態: %s",
+		"list.severity":         "重要度: %s",
+		"list.tags":             "タグ: %s",
+		"list.scope":            "スコープ: %s",
+		"list.platforms":        "プラットフォーム: %s",
+
+		// Quick summary
+		"quick.ready":     "%d 個のツールすべての準備ができています",
+		"quick.attention": "%d / %d 個のツールに対応が必要です",
+
+		// Watch mode
+		"watch.fixed": "%s (%s) が復旧しました: %s -> %s",
+		"watch.broke": "%s (%s) が失敗しました: %s -> %s",
+
+		// Status words
+		"status.ok":        "正常",
+		"status.missing":   "未インストール",
+		"status.not_found": "未検出",
+		"status.outdated":  "要更新",
+		"status.error":     "エラー",
+		"status.skipped":   "スキップ",
+		"status.unknown":   "不明",
+	},
+}
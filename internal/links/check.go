@@ -0,0 +1,320 @@
+package links
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultCheckTimeout bounds each DNS lookup and HTTP request made by the link checker
+const DefaultCheckTimeout = 10 * time.Second
+
+// DefaultCheckConcurrency is how many links are checked at once
+const DefaultCheckConcurrency = 8
+
+// DefaultCacheTTL is how long a link that answered is trusted without checking it again
+const DefaultCacheTTL = 24 * time.Hour
+
+// LinkStatus is the outcome of checking one URL
+type LinkStatus struct {
+	URL        string    `json:"url"`
+	OK         bool      `json:"ok"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// Reference is a link as it appears on a tool in a manifest
+type Reference struct {
+	ToolID   string `json:"tool_id"`
+	LinkType string `json:"link_type"`
+	URL      string `json:"url"`
+}
+
+// DeadLink is a reference whose URL did not answer
+type DeadLink struct {
+	Reference
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error"`
+}
+
+// Report summarizes a link check
+type Report struct {
+	Checked int         `json:"checked"`
+	Dead    []DeadLink  `json:"dead"`
+	Skipped []Reference `json:"skipped,omitempty"` // Logical links no resolver expanded
+}
+
+// Checker checks that link URLs still answer
+// Host names are resolved once each, concurrently, before any request is made, so a
+// manifest with many links to a few sites does not pay for the same lookup repeatedly
+type Checker struct {
+	client      *http.Client
+	timeout     time.Duration
+	concurrency int
+	cache       *Cache
+	lookupHost  func(ctx context.Context, host string) ([]string, error)
+}
+
+// NewChecker creates a link checker with the given per-request timeout
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	return &Checker{
+		client:      &http.Client{Timeout: timeout},
+		timeout:     timeout,
+		concurrency: DefaultCheckConcurrency,
+		lookupHost:  net.DefaultResolver.LookupHost,
+	}
+}
+
+// SetConcurrency sets how many links are checked at once
+func (c *Checker) SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	c.concurrency = n
+}
+
+// SetCache makes the checker reuse and record results in cache
+func (c *Checker) SetCache(cache *Cache) {
+	c.cache = cache
+}
+
+// Check checks every distinct URL and returns their statuses keyed by URL
+func (c *Checker) Check(ctx context.Context, urls []string) map[string]LinkStatus {
+	statuses := make(map[string]LinkStatus, len(urls))
+
+	var pending []string
+	for _, link := range urls {
+		if _, seen := statuses[link]; seen {
+			continue
+		}
+		if c.cache != nil {
+			if status, ok := c.cache.Get(link); ok {
+				statuses[link] = status
+				continue
+			}
+		}
+		statuses[link] = LinkStatus{}
+		pending = append(pending, link)
+	}
+
+	dnsErrors := c.prefetchHosts(ctx, pending)
+
+	var mu sync.Mutex
+	c.forEach(pending, func(link string) {
+		status := c.checkURL(ctx, link, dnsErrors)
+		mu.Lock()
+		statuses[link] = status
+		mu.Unlock()
+	})
+
+	if c.cache != nil {
+		for _, link := range pending {
+			c.cache.Put(statuses[link])
+		}
+	}
+
+	return statuses
+}
+
+// CheckReferences checks the URL of every reference, in order
+// Logical links that were not resolved to a URL cannot be checked and are skipped
+func (c *Checker) CheckReferences(ctx context.Context, refs []Reference) Report {
+	report := Report{Dead: []DeadLink{}}
+
+	var urls []string
+	for _, ref := range refs {
+		if IsLogical(ref.URL) {
+			report.Skipped = append(report.Skipped, ref)
+			continue
+		}
+		urls = append(urls, ref.URL)
+	}
+
+	statuses := c.Check(ctx, urls)
+	for _, ref := range refs {
+		status, checked := statuses[ref.URL]
+		if !checked || IsLogical(ref.URL) {
+			continue
+		}
+		report.Checked++
+		if !status.OK {
+			report.Dead = append(report.Dead, DeadLink{Reference: ref, StatusCode: status.StatusCode, Error: status.Error})
+		}
+	}
+
+	return report
+}
+
+// prefetchHosts resolves the host of every URL once and returns the lookup errors by host
+func (c *Checker) prefetchHosts(ctx context.Context, urls []string) map[string]error {
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, link := range urls {
+		u, err := url.Parse(link)
+		if err != nil || u.Hostname() == "" || seen[u.Hostname()] {
+			continue
+		}
+		seen[u.Hostname()] = true
+		hosts = append(hosts, u.Hostname())
+	}
+
+	var mu sync.Mutex
+	failures := make(map[string]error)
+	c.forEach(hosts, func(host string) {
+		lookupCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+		if _, err := c.lookupHost(lookupCtx, host); err != nil {
+			mu.Lock()
+			failures[host] = err
+			mu.Unlock()
+		}
+	})
+
+	return failures
+}
+
+// checkURL sends a HEAD request, falling back to GET for servers that do not allow HEAD
+func (c *Checker) checkURL(ctx context.Context, link string, dnsErrors map[string]error) LinkStatus {
+	status := LinkStatus{URL: link, CheckedAt: time.Now()}
+
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		status.Error = "not an http(s) URL"
+		return status
+	}
+	if err := dnsErrors[u.Hostname()]; err != nil {
+		status.Error = "DNS lookup failed: " + err.Error()
+		return status
+	}
+
+	code, err := c.request(ctx, http.MethodHead, link)
+	if err == nil && (code == http.StatusMethodNotAllowed || code == http.StatusNotImplemented) {
+		code, err = c.request(ctx, http.MethodGet, link)
+	}
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	status.StatusCode = code
+	status.OK = code < 400
+	if !status.OK {
+		status.Error = fmt.Sprintf("HTTP %d %s", code, http.StatusText(code))
+	}
+	return status
+}
+
+// request sends one request and returns the final status code after redirects
+func (c *Checker) request(ctx context.Context, method, link string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "goctor-link-check")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+// forEach calls fn for every item using up to the checker's concurrency
+func (c *Checker) forEach(items []string, fn func(string)) {
+	work := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < c.concurrency && i < len(items); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range work {
+				fn(item)
+			}
+		}()
+	}
+	for _, item := range items {
+		work <- item
+	}
+	close(work)
+	wg.Wait()
+}
+
+// Cache stores links that answered so repeated lint runs skip them
+// Dead links are never cached: they are checked again until they are fixed
+type Cache struct {
+	path    string
+	ttl     time.Duration
+	entries map[string]LinkStatus
+	now     func() time.Time
+}
+
+// LoadCache reads the link cache at path; a missing or unreadable cache starts empty
+func LoadCache(path string, ttl time.Duration) *Cache {
+	cache := &Cache{
+		path:    path,
+		ttl:     ttl,
+		entries: make(map[string]LinkStatus),
+		now:     time.Now,
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		cache.entries = make(map[string]LinkStatus)
+	}
+
+	return cache
+}
+
+// Get returns a fresh cached status for the URL, if any
+func (lc *Cache) Get(link string) (LinkStatus, bool) {
+	status, ok := lc.entries[link]
+	if !ok || lc.now().Sub(status.CheckedAt) > lc.ttl {
+		return LinkStatus{}, false
+	}
+	return status, true
+}
+
+// Put records a status; dead links are forgotten instead
+func (lc *Cache) Put(status LinkStatus) {
+	if !status.OK {
+		delete(lc.entries, status.URL)
+		return
+	}
+	lc.entries[status.URL] = status
+}
+
+// Save writes the cache back to disk, dropping expired entries
+func (lc *Cache) Save() error {
+	for link, status := range lc.entries {
+		if lc.now().Sub(status.CheckedAt) > lc.ttl {
+			delete(lc.entries, link)
+		}
+	}
+
+	data, err := json.Marshal(lc.entries)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(lc.path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(lc.path, data, 0644)
+}
@@ -0,0 +1,143 @@
+package links
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckerCheck(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/ok":
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checker := NewChecker(time.Second)
+	checker.lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		if host == "gone.invalid" {
+			return nil, errors.New("no such host")
+		}
+		return []string{"127.0.0.1"}, nil
+	}
+
+	tests := []struct {
+		url      string
+		wantOK   bool
+		wantCode int
+	}{
+		{server.URL + "/ok", true, http.StatusOK},
+		{server.URL + "/moved", true, http.StatusOK},
+		{server.URL + "/no-head", true, http.StatusOK},
+		{server.URL + "/missing", false, http.StatusNotFound},
+		{"https://gone.invalid/docs", false, 0},
+		{"wiki:onboarding/go", false, 0},
+	}
+
+	var urls []string
+	for _, tt := range tests {
+		urls = append(urls, tt.url)
+	}
+	// Duplicates are checked once
+	urls = append(urls, server.URL+"/missing")
+
+	statuses := checker.Check(context.Background(), urls)
+	for _, tt := range tests {
+		status := statuses[tt.url]
+		if status.OK != tt.wantOK || status.StatusCode != tt.wantCode {
+			t.Errorf("%s: expected ok=%v code=%d, got %+v", tt.url, tt.wantOK, tt.wantCode, status)
+		}
+		if !status.OK && status.Error == "" {
+			t.Errorf("%s: expected an error message", tt.url)
+		}
+	}
+
+	// ok, moved + ok, HEAD + GET no-head, and missing
+	if got := requests.Load(); got != 6 {
+		t.Errorf("Expected 6 requests, got %d", got)
+	}
+}
+
+func TestCheckerUsesCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/ok" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "links.json")
+	urls := []string{server.URL + "/ok", server.URL + "/missing"}
+
+	checker := NewChecker(time.Second)
+	checker.SetCache(LoadCache(path, time.Hour))
+	checker.Check(context.Background(), urls)
+	if err := checker.cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the healthy link is reused; the dead one is checked again
+	checker.SetCache(LoadCache(path, time.Hour))
+	statuses := checker.Check(context.Background(), urls)
+	if got := requests.Load(); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+	if !statuses[server.URL+"/ok"].OK || statuses[server.URL+"/missing"].OK {
+		t.Errorf("Unexpected statuses: %+v", statuses)
+	}
+
+	// Expired entries are checked again
+	expired := LoadCache(path, time.Hour)
+	expired.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, ok := expired.Get(server.URL + "/ok"); ok {
+		t.Error("Expected expired entry to be ignored")
+	}
+}
+
+func TestCheckerCheckReferences(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ok" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	refs := []Reference{
+		{ToolID: "go", LinkType: "homepage", URL: server.URL + "/ok"},
+		{ToolID: "go", LinkType: "docs", URL: server.URL + "/gone"},
+		{ToolID: "git", LinkType: "docs", URL: server.URL + "/gone"},
+		{ToolID: "git", LinkType: "wiki", URL: "wiki:onboarding/git"},
+	}
+
+	report := NewChecker(time.Second).CheckReferences(context.Background(), refs)
+	if report.Checked != 3 {
+		t.Errorf("Expected 3 checked links, got %d", report.Checked)
+	}
+	if len(report.Dead) != 2 || report.Dead[0].ToolID != "go" || report.Dead[1].ToolID != "git" {
+		t.Fatalf("Expected the docs links of go and git to be dead, got %+v", report.Dead)
+	}
+	if report.Dead[0].StatusCode != http.StatusNotFound || report.Dead[0].Error != "HTTP 404 Not Found" {
+		t.Errorf("Unexpected dead link: %+v", report.Dead[0])
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0].LinkType != "wiki" {
+		t.Errorf("Expected the logical link to be skipped, got %+v", report.Skipped)
+	}
+}
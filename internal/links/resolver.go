@@ -0,0 +1,91 @@
+package links
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// logicalLinkRegex matches logical links such as "wiki:onboarding/go"
+var logicalLinkRegex = regexp.MustCompile(`^([a-z][a-z0-9-]*):([^/].*)$`)
+
+// Resolver expands logical link keys into URLs using configured templates
+type Resolver struct {
+	templates map[string]string
+}
+
+// NewResolver creates a new resolver with no templates configured
+func NewResolver() *Resolver {
+	return &Resolver{
+		templates: make(map[string]string),
+	}
+}
+
+// IsLogical returns true if the link is a logical key rather than an http(s) URL
+func IsLogical(link string) bool {
+	if strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") {
+		return false
+	}
+	return logicalLinkRegex.MatchString(link)
+}
+
+// AddTemplate registers a URL template for a logical link prefix
+// The template must contain {path}, which is replaced with the escaped remainder of the link
+func (r *Resolver) AddTemplate(name, template string) error {
+	if name == "" {
+		return errors.New("link resolver name cannot be empty")
+	}
+
+	if !strings.Contains(template, "{path}") {
+		return fmt.Errorf("link resolver template for %s must contain {path}", name)
+	}
+
+	r.templates[name] = template
+	return nil
+}
+
+// ParseTemplate parses a "name=template" resolver definition and registers it
+func (r *Resolver) ParseTemplate(definition string) error {
+	name, template, found := strings.Cut(definition, "=")
+	if !found {
+		return fmt.Errorf("invalid link resolver (expected 'name=template'): %s", definition)
+	}
+
+	return r.AddTemplate(strings.TrimSpace(name), strings.TrimSpace(template))
+}
+
+// Resolve expands a logical link; links without a matching template are returned unchanged
+func (r *Resolver) Resolve(link string) string {
+	if !IsLogical(link) {
+		return link
+	}
+
+	matches := logicalLinkRegex.FindStringSubmatch(link)
+	template, exists := r.templates[matches[1]]
+	if !exists {
+		return link
+	}
+
+	segments := strings.Split(matches[2], "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.ReplaceAll(template, "{path}", strings.Join(segments, "/"))
+}
+
+// ResolveAll returns a copy of the links map with every logical link resolved
+func (r *Resolver) ResolveAll(links map[string]string) map[string]string {
+	if links == nil {
+		return nil
+	}
+
+	resolved := make(map[string]string, len(links))
+	for linkType, link := range links {
+		resolved[linkType] = r.Resolve(link)
+	}
+
+	return resolved
+}
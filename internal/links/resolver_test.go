@@ -0,0 +1,56 @@
+package links
+
+import (
+	"testing"
+)
+
+func TestResolverResolve(t *testing.T) {
+	resolver := NewResolver()
+	if err := resolver.ParseTemplate("wiki=https://wiki.example.com/pages/{path}"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		link     string
+		expected string
+	}{
+		{"logical link", "wiki:onboarding/go", "https://wiki.example.com/pages/onboarding/go"},
+		{"escaped segment", "wiki:setup guide", "https://wiki.example.com/pages/setup%20guide"},
+		{"unknown resolver", "docs:go", "docs:go"},
+		{"plain URL", "https://go.dev/", "https://go.dev/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolver.Resolve(tt.link); got != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestResolverParseTemplate(t *testing.T) {
+	tests := []struct {
+		name        string
+		definition  string
+		expectError bool
+	}{
+		{"valid template", "wiki=https://wiki.example.com/{path}", false},
+		{"missing separator", "https://wiki.example.com/{path}", true},
+		{"missing placeholder", "wiki=https://wiki.example.com/", true},
+		{"empty name", "=https://wiki.example.com/{path}", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewResolver().ParseTemplate(tt.definition)
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error for '%s', got nil", tt.definition)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error for '%s', got: %v", tt.definition, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Package logging configures the structured logger goctor writes diagnostics to
+//
+// Logs go to stderr so they never mix with reports on stdout. Manifest loading, merging,
+// and every step of a check are logged at debug level; by default only warnings are logged,
+// such as version constraints no version can meet.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// Log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Formats lists the accepted --log-format values
+var Formats = []string{FormatText, FormatJSON}
+
+// Levels lists the accepted --log-level values, most verbose first
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// DefaultLevel is the level logged when --log-level is not given
+const DefaultLevel = "warn"
+
+// New creates a logger writing records at level or above to w in format
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var minLevel slog.Level
+	if !isLevel(level) || minLevel.UnmarshalText([]byte(level)) != nil {
+		return nil, fmt.Errorf("invalid log level %q (expected %s)", level, strings.Join(Levels, ", "))
+	}
+
+	options := &slog.HandlerOptions{Level: minLevel}
+	switch format {
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q (expected %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// isLevel returns true if level is one of Levels; slog also accepts offsets such as "info+2"
+func isLevel(level string) bool {
+	return slices.ContainsFunc(Levels, func(l string) bool { return strings.EqualFold(level, l) })
+}
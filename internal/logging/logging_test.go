@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		format  string
+		want    []string
+		wantErr bool
+	}{
+		{name: "default level drops debug", level: DefaultLevel, format: FormatText, want: []string{"level=WARN msg=warned"}},
+		{name: "debug text", level: "debug", format: FormatText, want: []string{"level=DEBUG msg=traced tool=go", "level=WARN msg=warned"}},
+		{name: "info json", level: "INFO", format: FormatJSON, want: []string{`"level":"WARN","msg":"warned"`}},
+		{name: "unknown level", level: "trace", format: FormatText, wantErr: true},
+		{name: "level offset", level: "info+2", format: FormatText, wantErr: true},
+		{name: "unknown format", level: "debug", format: "logfmt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := New(&buf, tt.level, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			logger.Debug("traced", "tool", "go")
+			logger.Warn("warned")
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("Expected %d records, got %q", len(tt.want), buf.String())
+			}
+			for i, line := range lines {
+				if !strings.Contains(line, tt.want[i]) {
+					t.Errorf("Record %d = %q, want it to contain %q", i, line, tt.want[i])
+				}
+				if tt.format == FormatJSON && !json.Valid([]byte(line)) {
+					t.Errorf("Record %d is not JSON: %q", i, line)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NetrcEntry represents credentials for a single machine in a netrc-style file
+type NetrcEntry struct {
+	Machine  string
+	Login    string
+	Password string
+}
+
+// ParseHeader parses a "Name: value" header string
+func ParseHeader(header string) (string, string, error) {
+	name, value, found := strings.Cut(header, ":")
+	if !found {
+		return "", "", fmt.Errorf("invalid header format (expected 'Name: value'): %s", header)
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", "", errors.New("header name cannot be empty")
+	}
+
+	return name, strings.TrimSpace(value), nil
+}
+
+// ParseNetrc parses netrc-style data into a list of entries
+// The "default" entry, if present, is returned with an empty machine name
+func ParseNetrc(data []byte) ([]NetrcEntry, error) {
+	var entries []NetrcEntry
+	var current *NetrcEntry
+
+	tokens := strings.Fields(string(data))
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "machine":
+			if i+1 >= len(tokens) {
+				return nil, errors.New("netrc: missing machine name")
+			}
+			i++
+			entries = append(entries, NetrcEntry{Machine: tokens[i]})
+			current = &entries[len(entries)-1]
+		case "default":
+			entries = append(entries, NetrcEntry{})
+			current = &entries[len(entries)-1]
+		case "login", "password", "account":
+			if current == nil {
+				return nil, fmt.Errorf("netrc: %s appears before any machine", tokens[i])
+			}
+			if i+1 >= len(tokens) {
+				return nil, fmt.Errorf("netrc: missing value for %s", tokens[i])
+			}
+			key := tokens[i]
+			i++
+			switch key {
+			case "login":
+				current.Login = tokens[i]
+			case "password":
+				current.Password = tokens[i]
+			}
+		case "macdef":
+			// Macro definitions are not supported; skip the rest of the file
+			return entries, nil
+		}
+	}
+
+	return entries, nil
+}
+
+// LoadNetrcFile reads and parses a netrc-style credentials file
+func LoadNetrcFile(path string) ([]NetrcEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read netrc file %s: %v", path, err)
+	}
+
+	entries, err := ParseNetrc(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse netrc file %s: %v", path, err)
+	}
+
+	return entries, nil
+}
+
+// DefaultNetrcPath returns the netrc file to use, honoring GOCTOR_NETRC
+// Returns an empty string if no file is configured or present
+func DefaultNetrcPath() string {
+	if path := os.Getenv("GOCTOR_NETRC"); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	path := filepath.Join(home, ".netrc")
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+
+	return path
+}
+
+// findNetrcEntry returns the credentials matching host, falling back to the default entry
+func findNetrcEntry(entries []NetrcEntry, host string) *NetrcEntry {
+	var fallback *NetrcEntry
+	for i := range entries {
+		if entries[i].Machine == host {
+			return &entries[i]
+		}
+		if entries[i].Machine == "" && fallback == nil {
+			fallback = &entries[i]
+		}
+	}
+	return fallback
+}
+
+// addSourceHosts lets credentials be sent to the hosts of manifest sources; includes and bundles
+// on other hosts are fetched without them
+func (l *Loader) addSourceHosts(sources ...string) {
+	for _, source := range sources {
+		if !IsURL(source) {
+			continue
+		}
+		if u, err := url.Parse(source); err == nil {
+			l.sourceHosts[u.Host] = true
+		}
+	}
+}
+
+// applyCredentials adds configured headers and credentials to a manifest request
+// Precedence: explicit headers, then bearer token, then netrc credentials
+// Nothing is sent over plain HTTP; headers, the bearer token, and the netrc default entry only
+// go to the hosts of the loader's sources, and other netrc entries to their machine
+func (l *Loader) applyCredentials(req *http.Request) {
+	if req.URL.Scheme != "https" {
+		return
+	}
+
+	entry := findNetrcEntry(l.netrcEntries, req.URL.Hostname())
+	if !l.sourceHosts[req.URL.Host] {
+		if entry != nil && entry.Machine != "" {
+			req.SetBasicAuth(entry.Login, entry.Password)
+		}
+		return
+	}
+
+	for name, values := range l.headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	if req.Header.Get("Authorization") != "" {
+		return
+	}
+
+	if l.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+l.bearerToken)
+		return
+	}
+
+	if entry != nil {
+		req.SetBasicAuth(entry.Login, entry.Password)
+	}
+}
+
+// redirectPolicy wraps a redirect policy so credentials are never forwarded to another host
+func (l *Loader) redirectPolicy(next func(*http.Request, []*http.Request) error) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+
+		original := via[0].URL
+		if req.URL.Host != original.Host || (original.Scheme == "https" && req.URL.Scheme != "https") {
+			req.Header.Del("Authorization")
+			for name := range l.headers {
+				req.Header.Del(name)
+			}
+		}
+
+		if next != nil {
+			return next(req, via)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,181 @@
+package manifest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const authTestManifest = `meta:
+  version: 1
+  name: "Auth Test"
+tools:
+  - id: go
+    name: "Go"
+    rationale: "Go development"
+    require: ">=1.22"
+    check:
+      cmd: ["go", "version"]
+      regex: "go(?P<ver>\\d+\\.\\d+)"
+    links:
+      homepage: "https://go.dev/"
+`
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        string
+		expectedName  string
+		expectedValue string
+		expectError   bool
+	}{
+		{"simple header", "X-Api-Key: secret", "X-Api-Key", "secret", false},
+		{"value with colon", "Authorization: Basic a:b", "Authorization", "Basic a:b", false},
+		{"missing colon", "X-Api-Key secret", "", "", true},
+		{"empty name", ": secret", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, value, err := ParseHeader(tt.header)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for header '%s', got nil", tt.header)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if name != tt.expectedName || value != tt.expectedValue {
+				t.Errorf("Expected %s=%s, got %s=%s", tt.expectedName, tt.expectedValue, name, value)
+			}
+		})
+	}
+}
+
+func TestParseNetrc(t *testing.T) {
+	data := []byte(`machine manifests.example.com
+  login alice
+  password s3cret
+default login anon password guest
+`)
+
+	entries, err := ParseNetrc(data)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	entry := findNetrcEntry(entries, "manifests.example.com")
+	if entry == nil || entry.Login != "alice" || entry.Password != "s3cret" {
+		t.Errorf("Expected alice/s3cret for matching machine, got %+v", entry)
+	}
+
+	entry = findNetrcEntry(entries, "other.example.com")
+	if entry == nil || entry.Login != "anon" {
+		t.Errorf("Expected default entry for unknown machine, got %+v", entry)
+	}
+}
+
+func TestLoadFromURLSendsCredentials(t *testing.T) {
+	var gotAuth, gotKey string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotKey = r.Header.Get("X-Api-Key")
+		w.Write([]byte(authTestManifest))
+	}))
+	defer server.Close()
+
+	loader := NewLoader()
+	loader.SetHTTPClient(server.Client())
+	loader.AddHeader("X-Api-Key", "key-123")
+	loader.SetBearerToken("token-abc")
+
+	if _, err := loader.LoadFromURL(server.URL); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if gotAuth != "Bearer token-abc" {
+		t.Errorf("Expected bearer token, got '%s'", gotAuth)
+	}
+	if gotKey != "key-123" {
+		t.Errorf("Expected custom header, got '%s'", gotKey)
+	}
+}
+
+func TestLoadFromURLDropsCredentialsOnCrossHostRedirect(t *testing.T) {
+	var gotAuth, gotKey string
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotKey = r.Header.Get("X-Api-Key")
+		w.Write([]byte(authTestManifest))
+	}))
+	defer target.Close()
+
+	origin := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer origin.Close()
+
+	loader := NewLoader()
+	loader.SetHTTPClient(target.Client())
+	loader.AddHeader("X-Api-Key", "key-123")
+	loader.SetBearerToken("token-abc")
+
+	if _, err := loader.LoadFromURL(origin.URL); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if gotAuth != "" || gotKey != "" {
+		t.Errorf("Expected credentials to be dropped, got Authorization='%s' X-Api-Key='%s'", gotAuth, gotKey)
+	}
+}
+
+func TestApplyCredentialsScope(t *testing.T) {
+	netrc := []NetrcEntry{
+		{Machine: "mirror.example.com", Login: "mirror", Password: "m"},
+		{Login: "anyone", Password: "d"},
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		url      string
+		wantAuth string
+		wantKey  string
+	}{
+		{"bearer token to the source host", "token-abc", "https://config.example.com/team.yaml", "Bearer token-abc", "key-123"},
+		{"nothing over plain HTTP", "token-abc", "http://config.example.com/team.yaml", "", ""},
+		{"no bearer token to another host", "token-abc", "https://evil.example.com/tools.yaml", "", ""},
+		{"netrc default to the source host", "", "https://config.example.com/team.yaml", "Basic YW55b25lOmQ=", "key-123"},
+		{"no netrc default to another host", "", "https://evil.example.com/tools.yaml", "", ""},
+		{"netrc machine to its host", "", "https://mirror.example.com/tools.yaml", "Basic bWlycm9yOm0=", ""},
+		{"no netrc machine over plain HTTP", "", "http://mirror.example.com/tools.yaml", "", ""},
+		{"source host with another port", "token-abc", "https://config.example.com:8443/tools.yaml", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := NewLoader()
+			loader.AddHeader("X-Api-Key", "key-123")
+			loader.SetBearerToken(tt.token)
+			loader.SetNetrcEntries(netrc)
+			loader.addSourceHosts("https://config.example.com/tools.yaml")
+
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			loader.applyCredentials(req)
+
+			if got := req.Header.Get("Authorization"); got != tt.wantAuth {
+				t.Errorf("Expected Authorization %q, got %q", tt.wantAuth, got)
+			}
+			if got := req.Header.Get("X-Api-Key"); got != tt.wantKey {
+				t.Errorf("Expected X-Api-Key %q, got %q", tt.wantKey, got)
+			}
+		})
+	}
+}
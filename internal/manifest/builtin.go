@@ -0,0 +1,70 @@
+package manifest
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BuiltinPrefix marks a `use` reference to the built-in tool library
+const BuiltinPrefix = "builtin/"
+
+//go:embed builtin/tools.yaml
+var builtinCatalog []byte
+
+var (
+	builtinOnce  sync.Once
+	builtinTools map[string]*yaml.Node
+)
+
+// builtinDefinitions returns the YAML nodes of the built-in tool definitions by ID
+func builtinDefinitions() map[string]*yaml.Node {
+	builtinOnce.Do(func() {
+		var catalog struct {
+			Tools []yaml.Node `yaml:"tools"`
+		}
+		if err := yaml.Unmarshal(builtinCatalog, &catalog); err != nil {
+			panic(fmt.Sprintf("invalid built-in tool catalog: %v", err))
+		}
+
+		builtinTools = make(map[string]*yaml.Node, len(catalog.Tools))
+		for i := range catalog.Tools {
+			var id struct {
+				ID string `yaml:"id"`
+			}
+			if err := catalog.Tools[i].Decode(&id); err != nil {
+				panic(fmt.Sprintf("invalid built-in tool catalog: %v", err))
+			}
+			builtinTools[id.ID] = &catalog.Tools[i]
+		}
+	})
+	return builtinTools
+}
+
+// BuiltinToolIDs lists the tools of the built-in library in alphabetical order
+func BuiltinToolIDs() []string {
+	ids := make([]string, 0, len(builtinDefinitions()))
+	for id := range builtinDefinitions() {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// builtinDefinition returns the catalog entry a `use` value refers to
+func builtinDefinition(use string) (*yaml.Node, error) {
+	id, ok := strings.CutPrefix(use, BuiltinPrefix)
+	if !ok {
+		return nil, fmt.Errorf("unsupported use %q (expected %sNAME)", use, BuiltinPrefix)
+	}
+
+	node, ok := builtinDefinitions()[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown built-in tool %q (available: %s)", id, strings.Join(BuiltinToolIDs(), ", "))
+	}
+	return node, nil
+}
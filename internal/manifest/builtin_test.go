@@ -0,0 +1,120 @@
+package manifest
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestBuiltinToolsValid(t *testing.T) {
+	for _, id := range BuiltinToolIDs() {
+		t.Run(id, func(t *testing.T) {
+			var tool ToolDefinition
+			if err := yaml.Unmarshal([]byte("use: builtin/"+id+"\nrequire: \">=1.0\"\n"), &tool); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tool.ID != id {
+				t.Errorf("Expected ID %q, got %q", id, tool.ID)
+			}
+			if err := tool.Validate(); err != nil {
+				t.Errorf("Built-in definition is invalid: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionUse(t *testing.T) {
+	tests := []struct {
+		name        string
+		yaml        string
+		expectError bool
+		check       func(t *testing.T, tool ToolDefinition)
+	}{
+		{
+			name: "built-in definition",
+			yaml: "use: builtin/go\nrequire: \">=1.22\"\n",
+			check: func(t *testing.T, tool ToolDefinition) {
+				if tool.ID != "go" || tool.Name != "Go" || tool.RequiredVersion != ">=1.22" {
+					t.Errorf("Unexpected tool %+v", tool)
+				}
+				if len(tool.Check.Command) == 0 || tool.Check.Regex == "" {
+					t.Errorf("Expected the built-in check, got %+v", tool.Check)
+				}
+			},
+		},
+		{
+			name: "overrides",
+			yaml: "use: builtin/python\nid: python311\nrequire: \">=3.11\"\ncheck:\n  cmd: [\"python3.11\", \"--version\"]\nlinks:\n  wiki: \"https://wiki.example.com/python\"\n",
+			check: func(t *testing.T, tool ToolDefinition) {
+				if tool.ID != "python311" {
+					t.Errorf("Expected ID python311, got %q", tool.ID)
+				}
+				if len(tool.Check.Command) != 2 || tool.Check.Command[0] != "python3.11" {
+					t.Errorf("Expected the overridden command, got %v", tool.Check.Command)
+				}
+				if tool.Check.Regex == "" {
+					t.Error("Expected the built-in regex to be kept")
+				}
+				if tool.Links["wiki"] == "" || tool.Links["homepage"] == "" {
+					t.Errorf("Expected links to be merged, got %v", tool.Links)
+				}
+			},
+		},
+		{
+			name: "tiered require",
+			yaml: "use: builtin/node\nrequire:\n  minimum: \">=18\"\n  recommended: \">=20\"\n",
+			check: func(t *testing.T, tool ToolDefinition) {
+				if tool.RequiredVersion != ">=18" || tool.RecommendedVersion != ">=20" {
+					t.Errorf("Unexpected requirements %q, %q", tool.RequiredVersion, tool.RecommendedVersion)
+				}
+			},
+		},
+		{
+			name:        "unknown built-in",
+			yaml:        "use: builtin/cobol\nrequire: \">=1.0\"\n",
+			expectError: true,
+		},
+		{
+			name:        "unsupported reference",
+			yaml:        "use: go\nrequire: \">=1.0\"\n",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tool ToolDefinition
+			err := yaml.Unmarshal([]byte(tt.yaml), &tool)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tt.check(t, tool)
+		})
+	}
+}
+
+func TestManifestUseRequiresV2(t *testing.T) {
+	loader := NewLoader()
+
+	v1 := "meta:\n  version: 1\n  name: test\ntools:\n  - use: builtin/git\n    require: \">=2.30\"\n"
+	if _, err := loader.parseYAML([]byte(v1)); err == nil {
+		t.Error("Expected use to be rejected in a v1 manifest")
+	}
+
+	v2 := "meta:\n  version: 2\n  name: test\ntools:\n  - use: builtin/git\n    require: \">=2.30\"\n"
+	manifest, err := loader.parseYAML([]byte(v2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if manifest.Tools[0].ID != "git" {
+		t.Errorf("Expected tool git, got %q", manifest.Tools[0].ID)
+	}
+}
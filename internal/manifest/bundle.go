@@ -0,0 +1,290 @@
+package manifest
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultBundleEntry is the manifest loaded from a bundle when the source has no #entry
+const DefaultBundleEntry = "tools.yaml"
+
+// MaxBundleSize is the maximum total size of the files extracted from a bundle
+const MaxBundleSize = 64 << 20
+
+// bundleExtensions lists the supported archive formats
+var bundleExtensions = []string{".zip", ".tgz", ".tar.gz", ".tar"}
+
+// ParseBundleSource splits a bundle source such as policy.tgz#tools.yaml into the
+// archive location and the manifest entry inside it
+func ParseBundleSource(source string) (archive, entry string, ok bool) {
+	archive, entry, _ = strings.Cut(source, "#")
+	if bundleFormat(archive) == "" {
+		return "", "", false
+	}
+
+	if entry == "" {
+		entry = DefaultBundleEntry
+	}
+
+	return archive, entry, true
+}
+
+// bundleFormat returns the archive extension of a bundle location, or "" if it is not a bundle
+func bundleFormat(archive string) string {
+	name := strings.ToLower(archive)
+	if IsURL(name) {
+		name, _, _ = strings.Cut(name, "?")
+	}
+
+	for _, ext := range bundleExtensions {
+		if strings.HasSuffix(name, ext) {
+			return ext
+		}
+	}
+
+	return ""
+}
+
+// loadBundle extracts a bundle and loads the manifest entry from it
+// Relative plugin paths are resolved against the bundle root so bundled plugins run
+// from the extracted copy
+func (l *Loader) loadBundle(archive, entry string, chain []string) (*Manifest, error) {
+	if err := validateBundleEntry(entry); err != nil {
+		return nil, fmt.Errorf("invalid bundle entry %q in %s: %v", entry, archive, err)
+	}
+
+	var data []byte
+	var err error
+	if IsURL(archive) {
+		data, err = l.fetch(archive)
+	} else {
+		data, err = os.ReadFile(archive)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle %s: %v", archive, err)
+	}
+
+	root, err := l.extractBundle(data, bundleFormat(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract bundle %s: %v", archive, err)
+	}
+
+	manifestPath := filepath.Join(root, filepath.FromSlash(entry))
+	if _, err := os.Stat(manifestPath); err != nil {
+		return nil, fmt.Errorf("manifest %s not found in bundle %s", entry, archive)
+	}
+
+	return l.loadWithIncludes(manifestPath, root, chain)
+}
+
+// extractBundle unpacks an archive into a directory named after its checksum and returns it
+// A bundle that was already extracted is reused
+func (l *Loader) extractBundle(data []byte, format string) (string, error) {
+	baseDir := l.bundleDir
+	if baseDir == "" {
+		baseDir = filepath.Join(os.TempDir(), "goctor-bundles")
+	}
+
+	sum := sha256.Sum256(data)
+	root := filepath.Join(baseDir, hex.EncodeToString(sum[:])[:16])
+	if info, err := os.Stat(root); err == nil && info.IsDir() {
+		return root, nil
+	}
+
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return "", err
+	}
+
+	// Extract next to the final location and rename so a partial extraction is never reused
+	tmpDir, err := os.MkdirTemp(baseDir, ".extract-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	switch format {
+	case ".zip":
+		err = extractZip(data, tmpDir)
+	case ".tgz", ".tar.gz":
+		var gz *gzip.Reader
+		gz, err = gzip.NewReader(bytes.NewReader(data))
+		if err == nil {
+			err = extractTar(gz, tmpDir)
+		}
+	default:
+		err = extractTar(bytes.NewReader(data), tmpDir)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmpDir, root); err != nil {
+		// Another goctor process may have extracted the same bundle concurrently
+		if info, statErr := os.Stat(root); statErr == nil && info.IsDir() {
+			return root, nil
+		}
+		return "", err
+	}
+
+	return root, nil
+}
+
+// extractTar unpacks regular files and directories from a tar stream; links are skipped
+func extractTar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	var total int64
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if _, err := bundlePath(dest, header.Name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			total += header.Size
+			if total > MaxBundleSize {
+				return fmt.Errorf("bundle exceeds maximum size of %d bytes", MaxBundleSize)
+			}
+			if err := writeBundleFile(dest, header.Name, tr, header.FileInfo().Mode()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// extractZip unpacks regular files and directories from a zip archive; links are skipped
+func extractZip(data []byte, dest string) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, file := range zr.File {
+		mode := file.Mode()
+		if mode.IsDir() {
+			if _, err := bundlePath(dest, file.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		if !mode.IsRegular() {
+			continue
+		}
+
+		total += int64(file.UncompressedSize64)
+		if total > MaxBundleSize {
+			return fmt.Errorf("bundle exceeds maximum size of %d bytes", MaxBundleSize)
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = writeBundleFile(dest, file.Name, rc, mode)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeBundleFile writes one archive entry below dest, keeping its executable bits
+func writeBundleFile(dest, name string, r io.Reader, mode os.FileMode) error {
+	target, err := bundlePath(dest, name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	perm := os.FileMode(0644)
+	if mode.Perm()&0111 != 0 {
+		perm = 0755
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	// The declared size can lie, so the copy itself is bounded too
+	if _, err := io.Copy(f, io.LimitReader(r, MaxBundleSize)); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// bundlePath maps an archive entry name to a path below dest, rejecting entries that escape it
+func bundlePath(dest, name string) (string, error) {
+	if err := validateBundleEntry(name); err != nil {
+		return "", fmt.Errorf("unsafe archive entry %q: %v", name, err)
+	}
+
+	return filepath.Join(dest, filepath.FromSlash(path.Clean(name))), nil
+}
+
+// validateBundleEntry checks that an entry name is a relative path inside the archive
+func validateBundleEntry(name string) error {
+	if name == "" {
+		return errors.New("empty path")
+	}
+	if path.IsAbs(name) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return errors.New("absolute path")
+	}
+
+	cleaned := path.Clean(strings.ReplaceAll(name, `\`, "/"))
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return errors.New("path escapes the bundle")
+	}
+
+	return nil
+}
+
+// rebasePlugins resolves relative plugin paths against root, the bundle root or the
+// manifest's directory
+func rebasePlugins(manifest *Manifest, root string) {
+	for i := range manifest.Tools {
+		tool := &manifest.Tools[i]
+		tool.Check.Plugin = rebasePlugin(tool.Check.Plugin, root)
+		for j := range tool.Checks {
+			tool.Checks[j].Check.Plugin = rebasePlugin(tool.Checks[j].Check.Plugin, root)
+		}
+		for j := range tool.Variants {
+			tool.Variants[j].Check.Plugin = rebasePlugin(tool.Variants[j].Check.Plugin, root)
+		}
+	}
+}
+
+// rebasePlugin resolves a single plugin path; bare names are still looked up in PATH
+func rebasePlugin(plugin, root string) string {
+	if !strings.Contains(plugin, "/") || filepath.IsAbs(plugin) {
+		return plugin
+	}
+	return filepath.Join(root, filepath.FromSlash(plugin))
+}
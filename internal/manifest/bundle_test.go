@@ -0,0 +1,234 @@
+package manifest
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const pluginManifest = `meta:
+  version: 2
+  name: bundle
+tools:
+  - id: internal-vpn
+    name: Internal VPN
+    rationale: test
+    check:
+      plugin: plugins/vpn-check
+    links:
+      homepage: https://example.com/
+`
+
+type bundleFile struct {
+	name    string
+	content string
+	mode    int64
+}
+
+func writeTarGz(t *testing.T, path string, files []bundleFile) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		header := &tar.Header{Name: file.name, Mode: file.mode, Size: int64(len(file.content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(file.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeZip(t *testing.T, path string, files []bundleFile) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for _, file := range files {
+		header := &zip.FileHeader{Name: file.name, Method: zip.Deflate}
+		header.SetMode(os.FileMode(file.mode))
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(file.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseBundleSource(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		wantArchive string
+		wantEntry   string
+		wantOK      bool
+	}{
+		{"tarball with entry", "policy.tgz#tools.yaml", "policy.tgz", "tools.yaml", true},
+		{"nested entry", "policy.tar.gz#team/tools.yaml", "policy.tar.gz", "team/tools.yaml", true},
+		{"zip default entry", "policy.zip", "policy.zip", DefaultBundleEntry, true},
+		{"remote bundle with query", "https://artifacts.example.com/policy.tgz?rev=3#tools.yaml", "https://artifacts.example.com/policy.tgz?rev=3", "tools.yaml", true},
+		{"plain manifest", "tools.yaml", "", "", false},
+		{"remote manifest", "https://example.com/tools.yaml", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive, entry, ok := ParseBundleSource(tt.source)
+			if ok != tt.wantOK || archive != tt.wantArchive || entry != tt.wantEntry {
+				t.Errorf("ParseBundleSource(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.source, archive, entry, ok, tt.wantArchive, tt.wantEntry, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLoadBundleTarGz(t *testing.T) {
+	dir := t.TempDir()
+	src := t.TempDir()
+	if err := os.Mkdir(filepath.Join(src, "shared"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeManifest(t, filepath.Join(src, "shared"), "base.yaml", nil, "git")
+	writeManifest(t, src, "tools.yaml", []string{"shared/base.yaml"}, "go")
+
+	archive := filepath.Join(dir, "policy.tgz")
+	writeTarGz(t, archive, []bundleFile{
+		{"tools.yaml", readFile(t, filepath.Join(src, "tools.yaml")), 0644},
+		{"shared/base.yaml", readFile(t, filepath.Join(src, "shared", "base.yaml")), 0644},
+	})
+
+	loader := NewLoader()
+	loader.SetBundleDir(filepath.Join(dir, "bundles"))
+
+	m, err := loader.LoadFromSource(archive + "#tools.yaml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if m.GetTool("go") == nil || m.GetTool("git") == nil {
+		t.Errorf("Expected tools from the bundle and its include, got %d tools", len(m.Tools))
+	}
+
+	// A second load reuses the extracted bundle
+	if _, err := loader.LoadFromSource(archive + "#tools.yaml"); err != nil {
+		t.Fatalf("Unexpected error on reload: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "bundles"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected one extracted bundle, got %d", len(entries))
+	}
+}
+
+func TestLoadBundleZipRebasesPlugins(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "policy.zip")
+	writeZip(t, archive, []bundleFile{
+		{"tools.yaml", pluginManifest, 0644},
+		{"plugins/vpn-check", "#!/bin/sh\necho '{\"status\":\"ok\"}'\n", 0755},
+	})
+
+	loader := NewLoader()
+	loader.SetBundleDir(filepath.Join(dir, "bundles"))
+
+	m, err := loader.LoadFromSource(archive)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	plugin := m.GetTool("internal-vpn").Check.Plugin
+	if !filepath.IsAbs(plugin) || !strings.HasPrefix(plugin, filepath.Join(dir, "bundles")) {
+		t.Fatalf("Expected plugin path inside the extracted bundle, got %s", plugin)
+	}
+
+	info, err := os.Stat(plugin)
+	if err != nil {
+		t.Fatalf("Expected extracted plugin: %v", err)
+	}
+	if info.Mode().Perm()&0111 == 0 {
+		t.Errorf("Expected plugin to stay executable, got mode %v", info.Mode())
+	}
+}
+
+func TestLoadBundleErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	traversal := filepath.Join(dir, "traversal.tgz")
+	writeTarGz(t, traversal, []bundleFile{
+		{"../evil.yaml", "meta: {}", 0644},
+	})
+
+	valid := filepath.Join(dir, "valid.tgz")
+	writeTarGz(t, valid, []bundleFile{
+		{"tools.yaml", pluginManifest, 0644},
+	})
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{"entry escapes the archive", traversal + "#tools.yaml", "unsafe archive entry"},
+		{"missing entry", valid + "#other.yaml", "manifest other.yaml not found in bundle"},
+		{"entry outside the bundle", valid + "#../tools.yaml", "path escapes the bundle"},
+		{"missing archive", filepath.Join(dir, "missing.zip"), "failed to read bundle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := NewLoader()
+			loader.SetBundleDir(filepath.Join(dir, "bundles"))
+
+			_, err := loader.LoadFromSource(tt.source)
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "evil.yaml")); err == nil {
+		t.Error("Expected traversal entry not to be written")
+	}
+}
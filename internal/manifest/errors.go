@@ -0,0 +1,93 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Manifest load error types
+const (
+	ErrorTypeRead        = "read_error"        // The file or URL could not be read
+	ErrorTypeSyntax      = "syntax_error"      // The YAML, TOML, or JSON is malformed
+	ErrorTypeEnvironment = "environment_error" // A ${VAR} reference could not be expanded
+	ErrorTypeValidation  = "validation_error"  // The document does not describe a valid manifest
+)
+
+// LoadError is a manifest load failure with its source and, when known, the position in it
+// The message is the same as the wrapped error's, so callers that only print errors are unaffected
+type LoadError struct {
+	Type   string
+	Source string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *LoadError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// lineRegex finds the line number yaml.v3 and the manifest decoder put in their messages
+var lineRegex = regexp.MustCompile(`\bline (\d+)\b`)
+
+// newLoadError classifies err, taking the line from its message if it names one
+func newLoadError(errorType string, err error) *LoadError {
+	loadErr := &LoadError{Type: errorType, Err: err}
+	if matches := lineRegex.FindStringSubmatch(err.Error()); matches != nil {
+		loadErr.Line, _ = strconv.Atoi(matches[1])
+	}
+	return loadErr
+}
+
+// syntaxError classifies a TOML or JSON parse failure of data, with its line and column
+func syntaxError(err error, data []byte) *LoadError {
+	loadErr := &LoadError{Type: ErrorTypeSyntax, Err: err}
+
+	var jsonSyntaxErr *json.SyntaxError
+	var jsonTypeErr *json.UnmarshalTypeError
+	if line, column, ok := tomlPosition(err); ok {
+		loadErr.Line, loadErr.Column = line, column
+		return loadErr
+	}
+
+	switch {
+	case errors.As(err, &jsonSyntaxErr):
+		loadErr.Line, loadErr.Column = position(data, jsonSyntaxErr.Offset)
+	case errors.As(err, &jsonTypeErr):
+		loadErr.Line, loadErr.Column = position(data, jsonTypeErr.Offset)
+	}
+
+	return loadErr
+}
+
+// position converts a byte offset into a 1-based line and column
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// sourceError attributes a load failure to source, prefixing its message with context
+func sourceError(source, context string, err error) *LoadError {
+	loadErr := &LoadError{Type: ErrorTypeValidation}
+	var inner *LoadError
+	if errors.As(err, &inner) {
+		*loadErr = *inner
+	}
+
+	loadErr.Source = source
+	loadErr.Err = fmt.Errorf("%s: %w", context, err)
+	return loadErr
+}
@@ -0,0 +1,86 @@
+package manifest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name       string
+		file       string
+		content    string
+		wantType   string
+		wantLine   int
+		wantColumn int
+	}{
+		{
+			name:     "YAML syntax",
+			file:     "tools.yaml",
+			content:  "meta:\n  version: 1\n  name: x\ntools:\n  - id: a\n   name: b\n",
+			wantType: ErrorTypeSyntax,
+			wantLine: 4,
+		},
+		{
+			name:       "JSON syntax",
+			file:       "tools.json",
+			content:    "{\n  \"meta\": {\n    \"version\": 1,\n  }\n}\n",
+			wantType:   ErrorTypeSyntax,
+			wantLine:   4,
+			wantColumn: 4,
+		},
+		{
+			name:     "wrong type",
+			file:     "typed.yaml",
+			content:  "meta:\n  version: 1\n  name: x\ntools:\n  - id: a\n    timeout_sec: soon\n",
+			wantType: ErrorTypeValidation,
+			wantLine: 6,
+		},
+		{
+			name:     "invalid manifest",
+			file:     "invalid.yaml",
+			content:  "meta:\n  version: 1\n  name: x\ntools: []\n",
+			wantType: ErrorTypeValidation,
+		},
+		{
+			name:     "unset variable",
+			file:     "env.yaml",
+			content:  "meta:\n  version: 1\n  name: ${GOCTOR_TEST_UNSET_VARIABLE}\n",
+			wantType: ErrorTypeEnvironment,
+			wantLine: 3,
+		},
+		{
+			name:     "missing file",
+			file:     "missing.yaml",
+			wantType: ErrorTypeRead,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := NewLoader().LoadFromSource(path)
+			var loadErr *LoadError
+			if !errors.As(err, &loadErr) {
+				t.Fatalf("Expected a LoadError, got %v", err)
+			}
+
+			if loadErr.Type != tt.wantType || loadErr.Source != path {
+				t.Errorf("Expected %s in %s, got %s in %s: %v", tt.wantType, path, loadErr.Type, loadErr.Source, err)
+			}
+			if loadErr.Line != tt.wantLine || loadErr.Column != tt.wantColumn {
+				t.Errorf("Expected line %d column %d, got line %d column %d: %v", tt.wantLine, tt.wantColumn, loadErr.Line, loadErr.Column, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest file formats
+const (
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+	FormatJSON = "json"
+)
+
+// ManifestFormats lists the supported manifest file formats
+var ManifestFormats = []string{FormatYAML, FormatTOML, FormatJSON}
+
+// DefaultManifestNames are the manifest files looked up in each directory, in order
+// tools.toml is only looked up by builds that can load it
+var DefaultManifestNames = slices.Concat([]string{"tools.yaml"}, tomlManifestNames, []string{"tools.json", ".goctor.yaml", ".config/goctor/tools.yaml"})
+
+// DefaultManifestPath returns the manifest nearest to the current directory (see FindManifest),
+// relative to it, or ./tools.yaml if there is none
+func DefaultManifestPath() string {
+	if cwd, err := os.Getwd(); err == nil {
+		if found, ok := FindManifest(cwd); ok {
+			if filepath.Dir(found) == cwd {
+				return "./" + filepath.Base(found)
+			}
+			if rel, err := filepath.Rel(cwd, found); err == nil {
+				return rel
+			}
+			return found
+		}
+	}
+	return "./" + DefaultManifestNames[0]
+}
+
+// FindManifest searches dir, then its parents, for a default manifest, like .editorconfig, so
+// subdirectories of a monorepo can have manifests of their own
+// The search stops at the repository root, the nearest directory with a .git entry; outside
+// a repository only dir itself is searched
+func FindManifest(dir string) (string, bool) {
+	root := repositoryRoot(dir)
+	for {
+		for _, name := range DefaultManifestNames {
+			candidate := filepath.Join(dir, filepath.FromSlash(name))
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate, true
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if root == "" || dir == root || parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// repositoryRoot returns the nearest directory at or above dir with a .git directory or file,
+// or "" if there is none
+func repositoryRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// tomlTableRegex matches a TOML table header such as [meta] or [[tools]]
+var tomlTableRegex = regexp.MustCompile(`^\[\[?[A-Za-z0-9_.-]+\]\]?\s*(#.*)?$`)
+
+// tomlKeyRegex matches a TOML key/value line such as version = 2; YAML uses colons instead
+var tomlKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+\s*=`)
+
+// DetectFormat returns the format of a manifest, by file extension when the source has a
+// known one and otherwise by sniffing the content
+func DetectFormat(source string, data []byte) string {
+	// Drop the query string of URLs before looking at the extension
+	if i := strings.IndexAny(source, "?#"); i >= 0 && IsURL(source) {
+		source = source[:i]
+	}
+
+	switch strings.ToLower(path.Ext(source)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	case ".json":
+		return FormatJSON
+	}
+
+	return sniffFormat(data)
+}
+
+// sniffFormat guesses a manifest's format from its first meaningful line
+func sniffFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return FormatJSON
+	}
+
+	for _, line := range strings.Split(string(trimmed), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if tomlTableRegex.MatchString(line) || tomlKeyRegex.MatchString(line) {
+			return FormatTOML
+		}
+		break
+	}
+
+	return FormatYAML
+}
+
+// toYAML converts TOML or JSON manifest data to YAML so every format goes through the
+// same decoding and validation
+func toYAML(format string, data []byte) ([]byte, error) {
+	var document map[string]interface{}
+
+	switch format {
+	case FormatYAML:
+		return data, nil
+	case FormatTOML:
+		if err := decodeTOML(data, &document); err != nil {
+			return nil, err
+		}
+	case FormatJSON:
+		if err := json.Unmarshal(data, &document); err != nil {
+			return nil, syntaxError(fmt.Errorf("JSON parsing error: %w", err), data)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported manifest format: %s", format)
+	}
+
+	return yaml.Marshal(document)
+}
+
+// parse detects the format of manifest data read from source and parses it
+func (l *Loader) parse(source string, data []byte) (*Manifest, error) {
+	converted, err := toYAML(DetectFormat(source, data), data)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.parseYAML(converted)
+}
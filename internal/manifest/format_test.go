@@ -0,0 +1,205 @@
+package manifest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const tomlManifest = `# Team tools
+[meta]
+version = 1
+name = "TOML tools"
+
+[[tools]]
+id = "go"
+name = "Go"
+rationale = "Go toolchain"
+require = ">=1.20"
+check = { cmd = ["go", "version"], regex = 'go(?P<ver>\d+\.\d+(\.\d+)?)' }
+links = { homepage = "https://go.dev/" }
+`
+
+const jsonManifest = `{
+  "meta": {"version": 1, "name": "JSON tools"},
+  "tools": [
+    {
+      "id": "go",
+      "name": "Go",
+      "rationale": "Go toolchain",
+      "require": ">=1.20",
+      "check": {"cmd": ["go", "version"], "regex": "go(?P<ver>\\d+\\.\\d+(\\.\\d+)?)"},
+      "links": {"homepage": "https://go.dev/"}
+    }
+  ]
+}
+`
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		data   string
+		want   string
+	}{
+		{"yaml extension", "tools.yml", "", FormatYAML},
+		{"toml extension", "tools.toml", "", FormatTOML},
+		{"json extension", "TOOLS.JSON", "", FormatJSON},
+		{"url with query", "https://example.com/tools.toml?ref=main", "", FormatTOML},
+		{"sniff json", "https://example.com/manifest", jsonManifest, FormatJSON},
+		{"sniff toml table", "manifest", tomlManifest, FormatTOML},
+		{"sniff toml key", "manifest", "include = [\"base.toml\"]\n", FormatTOML},
+		{"sniff yaml", "manifest", "# comment\nmeta:\n  version: 1\n", FormatYAML},
+		{"yaml sequence", "manifest", "- a\n- b\n", FormatYAML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.source, []byte(tt.data)); got != tt.want {
+				t.Errorf("DetectFormat(%q) = %s, want %s", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadManifestFormats(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		wantName string
+		wantErr  string
+	}{
+		{"json by extension", "tools.json", jsonManifest, "JSON tools", ""},
+		{"invalid json", "bad.json", "{", "", "JSON parsing error"},
+		{"same validation", "invalid.json", `{"meta": {"version": 1, "name": "x"}}`, "", "manifest validation failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			m, err := NewLoader().LoadFromSource(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if m.Meta.Name != tt.wantName {
+				t.Errorf("Expected name %q, got %q", tt.wantName, m.Meta.Name)
+			}
+			tool := m.GetTool("go")
+			if tool == nil {
+				t.Fatal("Expected tool go")
+			}
+			if tool.RequiredVersion != ">=1.20" || len(tool.Check.Command) != 2 || tool.Links["homepage"] != "https://go.dev/" {
+				t.Errorf("Unexpected tool definition: %+v", tool)
+			}
+		})
+	}
+}
+
+func TestLoadFromStdin(t *testing.T) {
+	loader := NewLoader()
+	loader.SetStdin(strings.NewReader(jsonManifest))
+
+	// Reloads, e.g. in serve mode, reuse what was read the first time
+	for i := 0; i < 2; i++ {
+		m, err := loader.LoadFromSource(StdinSource)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if m.Meta.Name != "JSON tools" {
+			t.Errorf("Expected the manifest piped to stdin, got %q", m.Meta.Name)
+		}
+	}
+
+	loader.SetStdin(strings.NewReader("meta: [\n"))
+	_, err := loader.LoadFromSource(StdinSource)
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) || loadErr.Source != "stdin" {
+		t.Errorf("Expected a load error from stdin, got %v", err)
+	}
+
+	if got := DisplaySource(StdinSource); got != "stdin" {
+		t.Errorf("Expected stdin to be reported as \"stdin\", got %q", got)
+	}
+}
+
+func TestFindManifest(t *testing.T) {
+	repo := t.TempDir()
+	for _, dir := range []string{".git", "services/api/internal", "tools/lint"} {
+		if err := os.MkdirAll(filepath.Join(repo, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{"tools.yaml", "services/api/.goctor.yaml", "tools/.config/goctor/tools.yaml"} {
+		path := filepath.Join(repo, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{dir: ".", want: "tools.yaml"},
+		{dir: "services", want: "tools.yaml"},
+		{dir: "services/api/internal", want: "services/api/.goctor.yaml"},
+		{dir: "tools/lint", want: "tools/.config/goctor/tools.yaml"},
+	}
+
+	for _, tt := range tests {
+		got, ok := FindManifest(filepath.Join(repo, tt.dir))
+		if !ok || got != filepath.Join(repo, tt.want) {
+			t.Errorf("FindManifest(%s) = %q, %v, want %s", tt.dir, got, ok, tt.want)
+		}
+	}
+}
+
+func TestFindManifestStopsAtRepositoryRoot(t *testing.T) {
+	outer := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outer, "tools.yaml"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A repository below a directory with a manifest does not use that manifest
+	repo := filepath.Join(outer, "repo")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(repo, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := FindManifest(filepath.Join(repo, "sub")); ok {
+		t.Errorf("FindManifest() = %q, want no manifest", got)
+	}
+
+	// Outside a repository only the directory itself is searched
+	plain := filepath.Join(outer, "plain")
+	if err := os.MkdirAll(plain, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := FindManifest(plain); ok {
+		t.Errorf("FindManifest() = %q, want no manifest", got)
+	}
+	if _, ok := FindManifest(outer); !ok {
+		t.Error("FindManifest() found no manifest in the directory itself")
+	}
+}
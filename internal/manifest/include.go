@@ -0,0 +1,147 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// MaxIncludeDepth is the maximum number of nested includes below the root manifest
+const MaxIncludeDepth = 8
+
+// loadWithIncludes loads a manifest and merges its includes beneath it
+// chain holds the canonical sources of the manifests that led here, for cycle detection
+// Relative plugin paths resolve against root, or the directory of a local manifest when root is
+// empty; relative require_from files resolve against the directory of a local manifest only
+func (l *Loader) loadWithIncludes(source, root string, chain []string) (*Manifest, error) {
+	if archive, entry, ok := ParseBundleSource(source); ok {
+		return l.loadBundle(archive, entry, chain)
+	}
+
+	key, err := canonicalSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(chain, key) {
+		return nil, fmt.Errorf("include cycle detected: %s", strings.Join(append(slices.Clone(chain), key), " -> "))
+	}
+	chain = append(slices.Clone(chain), key)
+	if len(chain) > MaxIncludeDepth+1 {
+		return nil, fmt.Errorf("include chain exceeds maximum depth of %d: %s", MaxIncludeDepth, strings.Join(chain, " -> "))
+	}
+
+	manifest, err := l.loadSource(source)
+	if err != nil {
+		return nil, err
+	}
+	if dir := manifestDir(source, root); dir != "" {
+		rebasePlugins(manifest, dir)
+	}
+	// Project files such as go.mod are found next to a local manifest, but a bundle's
+	// extracted copy is no project, so its tools read them from the working directory
+	if dir := manifestDir(source, ""); dir != "" && root == "" {
+		for i := range manifest.Tools {
+			manifest.Tools[i].manifestDir = dir
+		}
+	}
+
+	if len(manifest.Include) == 0 {
+		return manifest, nil
+	}
+
+	// Included manifests are merged first so the including manifest takes precedence
+	manifests := make([]*Manifest, 0, len(manifest.Include)+1)
+	for _, include := range manifest.Include {
+		l.logger.Debug("including manifest", "source", DisplaySource(source), "include", resolveInclude(source, include.Source), "merge", include.Merge)
+		included, err := l.loadWithIncludes(resolveInclude(source, include.Source), root, chain)
+		if err != nil {
+			return nil, err
+		}
+		// The include's merge mode sticks to its tools, so no later manifest can loosen it
+		for i := range included.Tools {
+			included.Tools[i].mergeMode = stricterMergeMode(included.Tools[i].mergeMode, include.Merge)
+		}
+		manifests = append(manifests, included)
+	}
+	manifests = append(manifests, manifest)
+
+	return l.MergeManifests(manifests...)
+}
+
+// canonicalSource returns a stable identity for a manifest source; local paths are made
+// absolute with symlinks resolved so the same file reached by different names is detected
+func canonicalSource(source string) (string, error) {
+	if IsURL(source) || source == StdinSource {
+		return source, nil
+	}
+
+	absPath, err := filepath.Abs(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve manifest path %s: %v", source, err)
+	}
+
+	resolved, err := filepath.EvalSymlinks(absPath)
+	if err != nil {
+		// Missing files are reported by the loader itself; anything else (e.g. a symlink loop) is fatal here
+		if errors.Is(err, fs.ErrNotExist) {
+			return absPath, nil
+		}
+		return "", fmt.Errorf("failed to resolve manifest path %s: %v", source, err)
+	}
+
+	return resolved, nil
+}
+
+// manifestDir returns the absolute directory relative paths of a manifest resolve against:
+// root when set, otherwise the directory of a local manifest file; remote manifests and stdin
+// have none, so their paths stay relative to the working directory
+func manifestDir(source, root string) string {
+	dir := root
+	if dir == "" {
+		if IsURL(source) || source == StdinSource {
+			return ""
+		}
+		dir = filepath.Dir(source)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	return absDir
+}
+
+// resolveInclude resolves an include entry relative to the manifest that declares it
+// Includes of a remote manifest always resolve to URLs, never to local files
+func resolveInclude(base, include string) string {
+	if IsURL(include) {
+		return include
+	}
+
+	if IsURL(base) {
+		baseURL, err := url.Parse(base)
+		if err != nil {
+			return include
+		}
+		ref, err := url.Parse(include)
+		if err != nil {
+			return include
+		}
+		return baseURL.ResolveReference(ref).String()
+	}
+
+	if filepath.IsAbs(include) || base == StdinSource {
+		return include
+	}
+
+	return filepath.Join(filepath.Dir(base), include)
+}
+
+// IsURL returns true if the manifest source is an http(s) URL
+func IsURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
@@ -0,0 +1,171 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeManifest(t *testing.T, dir, name string, include []string, toolID string) string {
+	t.Helper()
+
+	var content strings.Builder
+	content.WriteString("meta:\n  version: 2\n  name: " + name + "\n")
+	if len(include) > 0 {
+		content.WriteString("include:\n")
+		for _, inc := range include {
+			content.WriteString("  - " + inc + "\n")
+		}
+	}
+	content.WriteString(fmt.Sprintf(`tools:
+  - id: %s
+    name: %s
+    rationale: test
+    require: ">=1.0"
+    check:
+      cmd: ["%s", "--version"]
+      regex: "(?P<ver>\\d+\\.\\d+)"
+    links:
+      homepage: https://example.com/
+`, toolID, toolID, toolID))
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+	return path
+}
+
+func TestLoadWithIncludes(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "shared"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeManifest(t, filepath.Join(dir, "shared"), "base.yaml", nil, "git")
+	root := writeManifest(t, dir, "tools.yaml", []string{"shared/base.yaml"}, "go")
+
+	m, err := NewLoader().LoadFromSource(root)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if m.Meta.Name != "tools.yaml" {
+		t.Errorf("Expected including manifest's meta, got %s", m.Meta.Name)
+	}
+	if m.GetTool("go") == nil || m.GetTool("git") == nil {
+		t.Errorf("Expected tools from both manifests, got %d tools", len(m.Tools))
+	}
+}
+
+func TestLoadFromSourceWithOverlays(t *testing.T) {
+	dir := t.TempDir()
+	base := writeManifest(t, dir, "base.yaml", nil, "go")
+	team := writeManifest(t, dir, "team.yaml", nil, "git")
+	local := writeManifest(t, dir, "local.yaml", nil, "go")
+
+	loader := NewLoader()
+	loader.SetOverlays([]string{team, local})
+
+	if sources := loader.Sources(base); !slices.Equal(sources, []string{base, team, local}) {
+		t.Errorf("Expected sources in merge order, got %v", sources)
+	}
+
+	m, err := loader.LoadFromSource(base)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if m.Meta.Name != "local.yaml" {
+		t.Errorf("Expected the last manifest's meta, got %s", m.Meta.Name)
+	}
+	if len(m.Tools) != 2 || m.GetTool("go") == nil || m.GetTool("git") == nil {
+		t.Errorf("Expected go and git once each, got %d tools", len(m.Tools))
+	}
+
+	loader.SetOverlays([]string{filepath.Join(dir, "missing.yaml")})
+	_, err = loader.LoadFromSource(base)
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) || loadErr.Type != ErrorTypeRead {
+		t.Errorf("Expected a read error for the missing overlay, got %v", err)
+	}
+}
+
+func TestLoadWithIncludesDetectsCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "a.yaml", []string{"b.yaml"}, "go")
+	writeManifest(t, dir, "b.yaml", []string{"a.yaml"}, "git")
+
+	_, err := NewLoader().LoadFromSource(filepath.Join(dir, "a.yaml"))
+	if err == nil {
+		t.Fatal("Expected include cycle error")
+	}
+	if !strings.Contains(err.Error(), "include cycle detected") ||
+		!strings.Contains(err.Error(), "a.yaml -> ") || !strings.Contains(err.Error(), "b.yaml -> ") {
+		t.Errorf("Expected error naming the chain, got: %v", err)
+	}
+}
+
+func TestLoadWithIncludesDetectsCycleThroughSymlink(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "a.yaml", []string{"link.yaml"}, "go")
+	if err := os.Symlink(filepath.Join(dir, "a.yaml"), filepath.Join(dir, "link.yaml")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	_, err := NewLoader().LoadFromSource(filepath.Join(dir, "a.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "include cycle detected") {
+		t.Errorf("Expected include cycle error, got: %v", err)
+	}
+}
+
+func TestLoadWithIncludesSymlinkLoop(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "a.yaml", []string{"loop1.yaml"}, "go")
+	if err := os.Symlink(filepath.Join(dir, "loop2.yaml"), filepath.Join(dir, "loop1.yaml")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "loop1.yaml"), filepath.Join(dir, "loop2.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewLoader().LoadFromSource(filepath.Join(dir, "a.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "failed to resolve manifest path") {
+		t.Errorf("Expected symlink loop error, got: %v", err)
+	}
+}
+
+func TestLoadWithIncludesDepthLimit(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i <= MaxIncludeDepth+1; i++ {
+		writeManifest(t, dir, fmt.Sprintf("m%d.yaml", i), []string{fmt.Sprintf("m%d.yaml", i+1)}, fmt.Sprintf("tool%d", i))
+	}
+
+	_, err := NewLoader().LoadFromSource(filepath.Join(dir, "m0.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "exceeds maximum depth") {
+		t.Errorf("Expected depth limit error, got: %v", err)
+	}
+}
+
+func TestResolveInclude(t *testing.T) {
+	tests := []struct {
+		base     string
+		include  string
+		expected string
+	}{
+		{"configs/tools.yaml", "shared/base.yaml", filepath.Join("configs", "shared", "base.yaml")},
+		{"configs/tools.yaml", "/etc/goctor/base.yaml", "/etc/goctor/base.yaml"},
+		{"configs/tools.yaml", "https://example.com/base.yaml", "https://example.com/base.yaml"},
+		{"https://example.com/team/tools.yaml", "base.yaml", "https://example.com/team/base.yaml"},
+		{"https://example.com/team/tools.yaml", "/etc/passwd", "https://example.com/etc/passwd"},
+	}
+
+	for _, tt := range tests {
+		if got := resolveInclude(tt.base, tt.include); got != tt.expected {
+			t.Errorf("resolveInclude(%q, %q) = %q, expected %q", tt.base, tt.include, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envReferenceRegex matches ${NAME} and ${NAME:-default}, optionally escaped as $${...}
+var envReferenceRegex = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?\}`)
+
+// ExpandEnv replaces ${NAME} with the value of the environment variable NAME and
+// ${NAME:-default} with default when NAME is unset or empty; $${...} is kept as a literal ${...}
+// Referencing an unset variable without a default is an error
+func ExpandEnv(s string, lookup func(string) (string, bool)) (string, error) {
+	var missing []string
+
+	expanded := envReferenceRegex.ReplaceAllStringFunc(s, func(reference string) string {
+		if strings.HasPrefix(reference, "$$") {
+			return reference[1:]
+		}
+
+		matches := envReferenceRegex.FindStringSubmatch(reference)
+		name, fallback := matches[1], matches[2]
+
+		value, ok := lookup(name)
+		if fallback != "" && value == "" {
+			return strings.TrimPrefix(fallback, ":-")
+		}
+		if !ok {
+			missing = append(missing, name)
+		}
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set (use ${%s:-default} for a fallback)", missing[0], missing[0])
+	}
+
+	return expanded, nil
+}
+
+// interpolateEnv expands environment references in every string value of a YAML document
+// Keys are left alone. An unquoted value is re-resolved after expansion, so
+// `timeout_sec: ${CHECK_TIMEOUT:-10}` still decodes as a number
+func interpolateEnv(node *yaml.Node, lookup func(string) (string, bool)) error {
+	if node.Kind == yaml.MappingNode {
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := interpolateEnv(node.Content[i], lookup); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, child := range node.Content {
+		if err := interpolateEnv(child, lookup); err != nil {
+			return err
+		}
+	}
+
+	if node.Kind != yaml.ScalarNode || node.ShortTag() != "!!str" || !strings.Contains(node.Value, "${") {
+		return nil
+	}
+
+	expanded, err := ExpandEnv(node.Value, lookup)
+	if err != nil {
+		return fmt.Errorf("line %d: %v", node.Line, err)
+	}
+
+	node.Value = expanded
+	if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+		node.Tag = ""
+	}
+
+	return nil
+}
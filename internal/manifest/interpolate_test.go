@@ -0,0 +1,106 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	env := map[string]string{"REGISTRY": "registry.example.com", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  string
+	}{
+		{input: "no references", expected: "no references"},
+		{input: "${REGISTRY}/tools", expected: "registry.example.com/tools"},
+		{input: "${MIRROR:-ghcr.io}/tools", expected: "ghcr.io/tools"},
+		{input: "${REGISTRY:-ghcr.io}", expected: "registry.example.com"},
+		{input: "${EMPTY:-fallback}", expected: "fallback"},
+		{input: "${EMPTY}", expected: ""},
+		{input: "${MIRROR:-}", expected: ""},
+		{input: "$${REGISTRY} stays", expected: "${REGISTRY} stays"},
+		{input: "$REGISTRY and ${} are not references", expected: "$REGISTRY and ${} are not references"},
+		{input: "${MIRROR}", wantErr: "MIRROR is not set"},
+	}
+
+	for _, tt := range tests {
+		got, err := ExpandEnv(tt.input, lookup)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ExpandEnv(%q): expected error containing %q, got %v", tt.input, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ExpandEnv(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ExpandEnv(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestLoaderExpandsEnv(t *testing.T) {
+	data := []byte(`meta:
+  version: 2
+  name: ${TEAM:-platform} tools
+defaults:
+  timeout_sec: ${CHECK_TIMEOUT:-10}
+tools:
+  - id: node
+    name: Node.js
+    rationale: Frontend builds
+    require: ">=20"
+    check:
+      cmd: ["node", "--version"]
+      regex: "v(?P<ver>\\d+\\.\\d+\\.\\d+)"
+    install:
+      npm: "npm config set registry https://${REGISTRY}/npm"
+    links:
+      homepage: "https://nodejs.org/"
+`)
+
+	loader := NewLoader()
+	loader.lookupEnv = func(name string) (string, bool) {
+		if name == "REGISTRY" {
+			return "registry.example.com", true
+		}
+		return "", false
+	}
+
+	m, err := loader.parse("tools.yaml", data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if m.Meta.Name != "platform tools" {
+		t.Errorf("Expected the default team name, got %q", m.Meta.Name)
+	}
+	if m.Defaults.TimeoutSeconds != 10 {
+		t.Errorf("Expected an expanded numeric timeout of 10, got %d", m.Defaults.TimeoutSeconds)
+	}
+	if hint := m.Tools[0].Install["npm"]; hint != "npm config set registry https://registry.example.com/npm" {
+		t.Errorf("Unexpected install hint: %q", hint)
+	}
+
+	// Unset variables without a default fail the load unless expansion is off
+	loader.lookupEnv = func(string) (string, bool) { return "", false }
+	if _, err := loader.parse("tools.yaml", data); err == nil || !strings.Contains(err.Error(), "REGISTRY") {
+		t.Errorf("Expected an error naming REGISTRY, got %v", err)
+	}
+
+	loader.SetExpandEnv(false)
+	m, err = loader.parse("tools.yaml", []byte(strings.Replace(string(data), "${CHECK_TIMEOUT:-10}", "10", 1)))
+	if err != nil {
+		t.Fatalf("Unexpected error with expansion off: %v", err)
+	}
+	if hint := m.Tools[0].Install["npm"]; !strings.Contains(hint, "${REGISTRY}") {
+		t.Errorf("Expected the reference to be kept, got %q", hint)
+	}
+}
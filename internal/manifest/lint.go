@@ -0,0 +1,308 @@
+package manifest
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/semver"
+)
+
+// LintRule is a best practice `lint` checks manifests against
+type LintRule struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+// Lint rule IDs
+const (
+	RuleMissingDownloadLink = "missing-download-link"
+	RuleUnnamedVersionGroup = "unnamed-version-group"
+	RuleBroadConstraint     = "broad-constraint"
+	RuleUnsatisfiable       = "unsatisfiable-constraint"
+	RuleLongTimeout         = "long-timeout"
+	RuleInsecureLink        = "insecure-link"
+	RuleDuplicateName       = "duplicate-name"
+	RuleUnusedDefault       = "unused-default"
+)
+
+// LintRules lists every lint rule, in the order findings are reported
+var LintRules = []LintRule{
+	{ID: RuleMissingDownloadLink, Description: "tools that can be installed have a download link"},
+	{ID: RuleUnnamedVersionGroup, Description: "version regexes name the group holding the version (ver, version, v, or the regex key)"},
+	{ID: RuleBroadConstraint, Description: "require does not accept every version, as >=0 does"},
+	{ID: RuleUnsatisfiable, Description: "some version meets every constraint, unlike >=2.0 <1.5"},
+	{ID: RuleLongTimeout, Description: fmt.Sprintf("timeout_sec is at most %d seconds", MaxLintTimeoutSeconds)},
+	{ID: RuleInsecureLink, Description: "links use https rather than http"},
+	{ID: RuleDuplicateName, Description: "tool names are unique"},
+	{ID: RuleUnusedDefault, Description: "every default applies to at least one tool"},
+}
+
+// MaxLintTimeoutSeconds is the longest timeout_sec the long-timeout rule accepts
+const MaxLintTimeoutSeconds = 60
+
+// DownloadLink is the links key of the page a tool is installed from
+const DownloadLink = "download"
+
+// VersionGroupNames are the capture groups searched for the version when no regex key is set
+var VersionGroupNames = []string{"ver", "version", "v"}
+
+// LintFinding is a manifest smell found by a lint rule; ToolID is empty for manifest-wide findings
+type LintFinding struct {
+	Rule    string `json:"rule"`
+	ToolID  string `json:"tool_id,omitempty"`
+	Message string `json:"message"`
+}
+
+// LintRuleIDs returns the IDs of every lint rule
+func LintRuleIDs() []string {
+	ids := make([]string, len(LintRules))
+	for i, rule := range LintRules {
+		ids[i] = rule.ID
+	}
+	return ids
+}
+
+// ValidateLintRules returns an error unless every ID names a lint rule
+func ValidateLintRules(ids []string) error {
+	known := LintRuleIDs()
+	for _, id := range ids {
+		if !slices.Contains(known, id) {
+			return fmt.Errorf("unknown lint rule %q (available: %s)", id, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// Lint checks a valid manifest against the lint rules except the disabled ones and returns
+// what they found, grouped by rule
+func (m *Manifest) Lint(disabled []string) []LintFinding {
+	findings := []LintFinding{}
+	for _, rule := range LintRules {
+		if slices.Contains(disabled, rule.ID) {
+			continue
+		}
+		for _, finding := range m.lintRule(rule.ID) {
+			finding.Rule = rule.ID
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// lintRule returns the findings of one rule
+func (m *Manifest) lintRule(id string) []LintFinding {
+	var findings []LintFinding
+	add := func(toolID, format string, args ...any) {
+		findings = append(findings, LintFinding{ToolID: toolID, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch id {
+	case RuleMissingDownloadLink:
+		for _, tool := range m.Tools {
+			if tool.installable() && tool.Links[DownloadLink] == "" {
+				add(tool.ID, "no %s link to install the tool from", DownloadLink)
+			}
+		}
+	case RuleUnnamedVersionGroup:
+		for _, tool := range m.Tools {
+			if group, ok := tool.unnamedVersionGroup(); !ok {
+				add(tool.ID, "the version is read from capture group %q by position; name it ver or set check.regex_key", group)
+			}
+		}
+	case RuleBroadConstraint:
+		for _, tool := range m.Tools {
+			for _, require := range tool.requirements() {
+				if broadConstraint(tool.Check.VersionScheme, require) {
+					add(tool.ID, "require %q accepts every version", require)
+				}
+			}
+		}
+	case RuleUnsatisfiable:
+		for _, tool := range m.Tools {
+			for _, problem := range tool.unsatisfiableRequirements() {
+				add(tool.ID, "%s", problem)
+			}
+		}
+	case RuleLongTimeout:
+		if m.Defaults.TimeoutSeconds > MaxLintTimeoutSeconds {
+			add("", "defaults.timeout_sec is %ds, over %ds", m.Defaults.TimeoutSeconds, MaxLintTimeoutSeconds)
+		}
+		for _, tool := range m.Tools {
+			if !tool.timeoutFromDefaults && tool.TimeoutSeconds > MaxLintTimeoutSeconds {
+				add(tool.ID, "timeout_sec is %ds, over %ds", tool.TimeoutSeconds, MaxLintTimeoutSeconds)
+			}
+		}
+	case RuleInsecureLink:
+		for _, tool := range m.Tools {
+			for _, linkType := range slices.Sorted(maps.Keys(tool.Links)) {
+				if u, err := url.Parse(tool.Links[linkType]); err == nil && u.Scheme == "http" {
+					add(tool.ID, "%s link %s is not https", linkType, tool.Links[linkType])
+				}
+			}
+		}
+	case RuleDuplicateName:
+		seen := make(map[string]string, len(m.Tools))
+		for _, tool := range m.Tools {
+			key := strings.ToLower(strings.TrimSpace(tool.Name))
+			if first, ok := seen[key]; ok {
+				add(tool.ID, "name %q is also used by %s", tool.Name, first)
+				continue
+			}
+			seen[key] = tool.ID
+		}
+	case RuleUnusedDefault:
+		if m.Defaults.TimeoutSeconds > 0 && !slices.ContainsFunc(m.Tools, func(tool ToolDefinition) bool {
+			return tool.timeoutFromDefaults && !tool.IsEnv()
+		}) {
+			add("", "defaults.timeout_sec is overridden or unused by every tool")
+		}
+		if m.Defaults.RegexKey != "" && !slices.ContainsFunc(m.Tools, func(tool ToolDefinition) bool {
+			return tool.parsesVersion() && tool.Check.RegexKey == ""
+		}) {
+			add("", "defaults.regex_key is overridden or unused by every tool")
+		}
+		if m.Defaults.StrictSemver && !slices.ContainsFunc(m.Tools, func(tool ToolDefinition) bool {
+			return tool.parsesVersion() && (tool.Check.VersionScheme == "" || tool.Check.VersionScheme == semver.DefaultScheme)
+		}) {
+			add("", "defaults.strict_semver applies to no tool, since none uses the semver scheme")
+		}
+	}
+	return findings
+}
+
+// installable returns true if the tool is a program one installs, so a download link helps
+func (td *ToolDefinition) installable() bool {
+	return !td.IsOS() && !td.IsGitConfig() && !td.IsEnv()
+}
+
+// parsesVersion returns true if the tool's check reads a version from command output
+func (td *ToolDefinition) parsesVersion() bool {
+	return !td.IsPlugin() && !td.IsService() && !td.IsGitConfig() && !td.IsEnv() && td.Check.Regex != ""
+}
+
+// unnamedVersionGroup returns the group the version is read from and whether the group is
+// chosen by name rather than by position
+func (td *ToolDefinition) unnamedVersionGroup() (string, bool) {
+	if !td.parsesVersion() || td.VersionRegexKey() != "" {
+		return "", true
+	}
+	regex, err := regexp.Compile(td.Check.Regex)
+	if err != nil {
+		return "", true
+	}
+
+	names := regex.SubexpNames()
+	for _, name := range names {
+		if slices.Contains(VersionGroupNames, strings.ToLower(name)) {
+			return name, true
+		}
+	}
+	if len(names) < 2 {
+		return "", true
+	}
+	return cmp.Or(names[1], "1"), false
+}
+
+// requirements returns the constraints the tool and its variants require
+func (td *ToolDefinition) requirements() []string {
+	var requirements []string
+	if td.RequiredVersion != "" {
+		requirements = append(requirements, td.RequiredVersion)
+	}
+	for _, v := range td.Variants {
+		if v.RequiredVersion != "" {
+			requirements = append(requirements, v.RequiredVersion)
+		}
+	}
+	return requirements
+}
+
+// unsatisfiableRequirements describes the constraints of the tool, its recommended tier,
+// variants, and sub-checks that no version can meet
+func (td *ToolDefinition) unsatisfiableRequirements() []string {
+	var problems []string
+	add := func(prefix, scheme, constraint string) {
+		if clauses := conflictingConstraints(scheme, constraint); clauses != nil {
+			problems = append(problems, fmt.Sprintf("%srequire %q can never be met: %s", prefix, constraint, conflictDetail(clauses)))
+		}
+	}
+
+	if td.RequiredVersion != "" {
+		add("", td.Check.VersionScheme, td.RequiredVersion)
+	}
+	if td.RecommendedVersion != "" {
+		add("recommended ", td.Check.VersionScheme, td.RecommendedVersion)
+	}
+	for _, v := range td.Variants {
+		if v.RequiredVersion != "" {
+			add("variant "+v.Name+": ", td.VariantDefinition(v).Check.VersionScheme, v.RequiredVersion)
+		}
+	}
+	for _, sub := range td.Checks {
+		if sub.RequiredVersion != "" {
+			add("check "+sub.Name+": ", td.SubCheckDefinition(sub).Check.VersionScheme, sub.RequiredVersion)
+		}
+	}
+	return problems
+}
+
+// conflictingConstraints returns the clauses of a constraint that no version meets together,
+// or nil if some version meets them all; only the semver and lenient schemes can tell, so
+// constraints of other schemes are assumed satisfiable
+func conflictingConstraints(scheme, constraint string) []string {
+	parse := semver.ParseConstraints
+	lenient := false
+	switch scheme {
+	case "", semver.DefaultScheme:
+	case "lenient":
+		parse = semver.ParseLenientConstraints
+		lenient = true
+	default:
+		return nil
+	}
+
+	constraints, err := parse(constraint)
+	if err != nil {
+		return nil
+	}
+	var clauses []string
+	for _, c := range semver.Conflicting(constraints, lenient) {
+		clauses = append(clauses, c.String())
+	}
+	return clauses
+}
+
+// conflictDetail explains why the clauses returned by conflictingConstraints cannot be met
+func conflictDetail(clauses []string) string {
+	switch len(clauses) {
+	case 1:
+		return clauses[0] + " excludes every version"
+	case 2:
+		return clauses[0] + " conflicts with " + clauses[1]
+	default:
+		return strings.Join(clauses, " ") + " cannot all be met"
+	}
+}
+
+// broadConstraint returns true if a semver constraint accepts both the earliest and a far
+// future release, so it only checks that the tool is installed
+func broadConstraint(scheme, constraint string) bool {
+	if scheme != "" && scheme != semver.DefaultScheme && scheme != "lenient" {
+		return false
+	}
+	s, err := semver.GetScheme(scheme)
+	if err != nil {
+		return false
+	}
+	for _, version := range []string{"0.0.1", "999999.0.0"} {
+		if ok, err := s.Satisfies(version, constraint); err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,204 @@
+package manifest
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestManifestLint(t *testing.T) {
+	const header = `meta:
+  version: 2
+  name: lint
+`
+	tool := func(fields string) string {
+		return `  - id: go
+    name: Go
+    rationale: Builds the project
+    links:
+      download: https://go.dev/dl/
+` + fields
+	}
+
+	tests := []struct {
+		name     string
+		manifest string
+		disabled []string
+		want     []LintFinding
+	}{
+		{
+			name: "clean",
+			manifest: header + "tools:\n" + tool(`    require: ">=1.22"
+    check:
+      cmd: [go, version]
+      regex: 'go(?P<ver>\d+\.\d+(\.\d+)?)'
+`),
+			want: []LintFinding{},
+		},
+		{
+			name: "missing download link",
+			manifest: header + `tools:
+  - id: jq
+    name: jq
+    rationale: Scripts parse JSON
+    require: ">=1.6"
+    check:
+      cmd: [jq, --version]
+      regex: 'jq-(?P<ver>\d+\.\d+)'
+    links:
+      docs: https://jqlang.github.io/jq/
+  - id: editor
+    name: EDITOR
+    rationale: Commit messages open in it
+    check:
+      type: env
+      var: EDITOR
+    links:
+      docs: https://example.com/setup
+`,
+			want: []LintFinding{{Rule: RuleMissingDownloadLink, ToolID: "jq", Message: "no download link to install the tool from"}},
+		},
+		{
+			name: "version read by position",
+			manifest: header + "tools:\n" + tool(`    require: ">=1.22"
+    check:
+      cmd: [go, version]
+      regex: 'go(\d+\.\d+)(?P<rest>.*)'
+`),
+			want: []LintFinding{{Rule: RuleUnnamedVersionGroup, ToolID: "go", Message: `the version is read from capture group "1" by position; name it ver or set check.regex_key`}},
+		},
+		{
+			name: "broad constraint",
+			manifest: header + "tools:\n" + tool(`    require: ">=0"
+    check:
+      cmd: [go, version]
+      regex: 'go(?P<ver>\d+\.\d+(\.\d+)?)'
+`),
+			want: []LintFinding{{Rule: RuleBroadConstraint, ToolID: "go", Message: `require ">=0" accepts every version`}},
+		},
+		{
+			name: "unsatisfiable constraints",
+			manifest: header + "tools:\n" + tool(`    require: ">=2.0 <1.5"
+    check:
+      cmd: [go, version]
+      regex: 'go(?P<ver>\d+\.\d+(\.\d+)?)'
+`) + `  - id: java
+    name: Java
+    rationale: Runs the build
+    check:
+      cmd: [java, -version]
+      regex: 'version "(?P<ver>[\d._]+)"'
+      version_scheme: lenient
+    variants:
+      - name: "17"
+        require: ">=17 <18"
+      - name: "11"
+        require: "^11.0 <11 >=1.0"
+    links:
+      download: https://adoptium.net/
+`,
+			want: []LintFinding{
+				{Rule: RuleUnsatisfiable, ToolID: "go", Message: `require ">=2.0 <1.5" can never be met: >=2.0.0 conflicts with <1.5.0`},
+				{Rule: RuleUnsatisfiable, ToolID: "java", Message: `variant 11: require "^11.0 <11 >=1.0" can never be met: ^11.0.0 conflicts with <11.0.0`},
+			},
+		},
+		{
+			name: "no release between the bounds",
+			manifest: header + "tools:\n" + tool(`    require: ">1.0.0 <1.0.1"
+    check:
+      cmd: [go, version]
+      regex: 'go(?P<ver>\d+\.\d+(\.\d+)?)'
+`),
+			want: []LintFinding{{Rule: RuleUnsatisfiable, ToolID: "go", Message: `require ">1.0.0 <1.0.1" can never be met: >1.0.0 conflicts with <1.0.1`}},
+		},
+		{
+			name: "long timeouts",
+			manifest: header + `defaults:
+  timeout_sec: 90
+tools:
+` + tool(`    require: ">=1.22"
+    timeout_sec: 120
+    check:
+      cmd: [go, version]
+      regex: 'go(?P<ver>\d+\.\d+(\.\d+)?)'
+`),
+			want: []LintFinding{
+				{Rule: RuleLongTimeout, Message: "defaults.timeout_sec is 90s, over 60s"},
+				{Rule: RuleLongTimeout, ToolID: "go", Message: "timeout_sec is 120s, over 60s"},
+				{Rule: RuleUnusedDefault, Message: "defaults.timeout_sec is overridden or unused by every tool"},
+			},
+		},
+		{
+			name: "insecure link and duplicate name",
+			manifest: header + "tools:\n" + tool(`    require: ">=1.22"
+    check:
+      cmd: [go, version]
+      regex: 'go(?P<ver>\d+\.\d+(\.\d+)?)'
+`) + `  - id: golang
+    name: go
+    rationale: Builds the tools
+    require: ">=1.21"
+    check:
+      cmd: [go, version]
+      regex: 'go(?P<ver>\d+\.\d+(\.\d+)?)'
+    links:
+      download: http://go.dev/dl/
+`,
+			want: []LintFinding{
+				{Rule: RuleInsecureLink, ToolID: "golang", Message: "download link http://go.dev/dl/ is not https"},
+				{Rule: RuleDuplicateName, ToolID: "golang", Message: `name "go" is also used by go`},
+			},
+		},
+		{
+			name: "unused defaults",
+			manifest: header + `defaults:
+  regex_key: ver
+  strict_semver: true
+tools:
+` + tool(`    require: ">=1.22"
+    check:
+      cmd: [go, version]
+      regex: 'go(?P<num>\d+\.\d+)'
+      regex_key: num
+      version_scheme: loose
+`),
+			want: []LintFinding{
+				{Rule: RuleUnusedDefault, Message: "defaults.regex_key is overridden or unused by every tool"},
+				{Rule: RuleUnusedDefault, Message: "defaults.strict_semver applies to no tool, since none uses the semver scheme"},
+			},
+		},
+		{
+			name: "disabled rules",
+			manifest: header + "tools:\n" + tool(`    require: ">=0"
+    timeout_sec: 120
+    check:
+      cmd: [go, version]
+      regex: 'go(?P<ver>\d+\.\d+(\.\d+)?)'
+`),
+			disabled: []string{RuleBroadConstraint, RuleLongTimeout},
+			want:     []LintFinding{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewLoader().parseYAML([]byte(tt.manifest))
+			if err != nil {
+				t.Fatalf("parseYAML() error = %v", err)
+			}
+
+			got := m.Lint(tt.disabled)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Lint() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateLintRules(t *testing.T) {
+	if err := ValidateLintRules([]string{RuleBroadConstraint, RuleUnusedDefault}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := ValidateLintRules([]string{"no-such-rule"}); err == nil {
+		t.Error("Expected error for an unknown rule")
+	}
+}
@@ -1,9 +1,11 @@
 package manifest
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
@@ -14,7 +16,31 @@ import (
 
 // Loader handles loading and parsing of manifest files
 type Loader struct {
-	httpClient *http.Client
+	httpClient   *http.Client
+	headers      http.Header
+	bearerToken  string
+	netrcEntries []NetrcEntry
+	sourceHosts  map[string]bool // hosts of the sources given to the loader, which get credentials
+	bundleDir    string
+	expandEnv    bool
+	lookupEnv    func(string) (string, bool)
+	stdin        io.Reader
+	stdinData    []byte // stdin can be read only once, so it is kept for reloads
+	overlays     []string
+	mergeMode    string
+	trust        TrustPolicy
+	logger       *slog.Logger
+}
+
+// StdinSource is the manifest source that reads the manifest from standard input
+const StdinSource = "-"
+
+// DisplaySource returns how a manifest source is named in reports
+func DisplaySource(source string) string {
+	if source == StdinSource {
+		return "stdin"
+	}
+	return source
 }
 
 // NewLoader creates a new manifest loader with default configuration
@@ -23,9 +49,35 @@ func NewLoader() *Loader {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		headers:     make(http.Header),
+		sourceHosts: make(map[string]bool),
+		expandEnv:   true,
+		lookupEnv:   os.LookupEnv,
+		stdin:       os.Stdin,
+		mergeMode:   MergeOverride,
+		logger:      slog.Default(),
 	}
 }
 
+// LoadFromStdin loads a manifest piped to standard input
+// The format is detected from the content; includes resolve against the working directory
+func (l *Loader) LoadFromStdin() (*Manifest, error) {
+	if l.stdinData == nil {
+		data, err := io.ReadAll(l.stdin)
+		if err != nil {
+			return nil, &LoadError{Type: ErrorTypeRead, Source: DisplaySource(StdinSource), Err: fmt.Errorf("failed to read manifest from stdin: %v", err)}
+		}
+		l.stdinData = data
+	}
+
+	manifest, err := l.parse("", l.stdinData)
+	if err != nil {
+		return nil, sourceError(DisplaySource(StdinSource), "failed to parse manifest from stdin", err)
+	}
+
+	return manifest, nil
+}
+
 // LoadFromFile loads a manifest from a local file
 func (l *Loader) LoadFromFile(filePath string) (*Manifest, error) {
 	if filePath == "" {
@@ -34,19 +86,19 @@ func (l *Loader) LoadFromFile(filePath string) (*Manifest, error) {
 
 	// Check if file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("manifest file not found: %s", filePath)
+		return nil, &LoadError{Type: ErrorTypeRead, Source: filePath, Err: fmt.Errorf("manifest file not found: %s", filePath)}
 	}
 
 	// Read file
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read manifest file %s: %v", filePath, err)
+		return nil, &LoadError{Type: ErrorTypeRead, Source: filePath, Err: fmt.Errorf("failed to read manifest file %s: %v", filePath, err)}
 	}
 
-	// Parse YAML
-	manifest, err := l.parseYAML(data)
+	// Parse YAML, TOML, or JSON
+	manifest, err := l.parse(filePath, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse manifest file %s: %v", filePath, err)
+		return nil, sourceError(filePath, "failed to parse manifest file "+filePath, err)
 	}
 
 	return manifest, nil
@@ -54,6 +106,13 @@ func (l *Loader) LoadFromFile(filePath string) (*Manifest, error) {
 
 // LoadFromURL loads a manifest from a remote URL
 func (l *Loader) LoadFromURL(url string) (*Manifest, error) {
+	l.addSourceHosts(url)
+	return l.loadURL(url)
+}
+
+// loadURL loads a manifest from a remote URL, sending credentials only if its host is one of
+// the loader's sources
+func (l *Loader) loadURL(url string) (*Manifest, error) {
 	if url == "" {
 		return nil, errors.New("URL cannot be empty")
 	}
@@ -63,12 +122,49 @@ func (l *Loader) LoadFromURL(url string) (*Manifest, error) {
 		return nil, fmt.Errorf("invalid URL format: %s", url)
 	}
 
-	// Make HTTP request
-	resp, err := l.httpClient.Get(url)
+	data, err := l.fetch(url)
+	if err != nil {
+		return nil, &LoadError{Type: ErrorTypeRead, Source: url, Err: err}
+	}
+
+	// Parse YAML, TOML, or JSON
+	manifest, err := l.parse(url, data)
+	if err != nil {
+		return nil, sourceError(url, "failed to parse manifest from "+url, err)
+	}
+
+	return manifest, nil
+}
+
+// fetch downloads a remote resource with the configured credentials
+func (l *Loader) fetch(url string) ([]byte, error) {
+	if l.trust != nil {
+		if err := l.trust(url); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL format: %s", url)
+	}
+	l.applyCredentials(req)
+
+	// Make HTTP request, dropping credentials on cross-host redirects
+	client := *l.httpClient
+	client.CheckRedirect = l.redirectPolicy(l.httpClient.CheckRedirect)
+
+	l.logger.Debug("fetching manifest", "url", url)
+	start := time.Now()
+	resp, err := client.Do(req)
 	if err != nil {
+		if hint := tlsHint(err); hint != "" {
+			return nil, fmt.Errorf("failed to fetch manifest from %s: %v (%s)", url, err, hint)
+		}
 		return nil, fmt.Errorf("failed to fetch manifest from %s: %v", url, err)
 	}
 	defer resp.Body.Close()
+	l.logger.Debug("fetched manifest", "url", url, "status", resp.StatusCode, "duration", time.Since(start))
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
@@ -81,37 +177,94 @@ func (l *Loader) LoadFromURL(url string) (*Manifest, error) {
 		return nil, fmt.Errorf("failed to read response from %s: %v", url, err)
 	}
 
-	// Parse YAML
-	manifest, err := l.parseYAML(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse manifest from %s: %v", url, err)
-	}
-
-	return manifest, nil
+	return data, nil
 }
 
-// LoadFromSource loads a manifest from either a file path or URL
+// LoadFromSource loads a manifest from either a file path or URL, resolving includes
+// Overlays set with SetOverlays are merged over it
 func (l *Loader) LoadFromSource(source string) (*Manifest, error) {
 	if source == "" {
 		return nil, errors.New("source cannot be empty")
 	}
 
-	// Determine if source is URL or file path
-	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
-		return l.LoadFromURL(source)
+	l.addSourceHosts(l.Sources(source)...)
+
+	var manifest *Manifest
+	var err error
+	if len(l.overlays) > 0 {
+		manifest, err = l.LoadMultipleSources(l.Sources(source)...)
+	} else {
+		manifest, err = l.loadWithIncludes(source, "", nil)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return l.LoadFromFile(source)
+	l.warnUnsatisfiable(manifest)
+	return manifest, nil
 }
 
+// warnUnsatisfiable logs a warning for every constraint of the loaded manifest no version can
+// meet, which may only arise once includes and overlays are merged; the manifest stays valid,
+// since its checks would report the problem anyway
+func (l *Loader) warnUnsatisfiable(m *Manifest) {
+	for _, tool := range m.Tools {
+		for _, problem := range tool.unsatisfiableRequirements() {
+			l.logger.Warn("unsatisfiable version constraint", "tool", tool.ID, "problem", problem)
+		}
+	}
+}
+
+// Sources returns the manifests LoadFromSource merges for source, lowest precedence first
+func (l *Loader) Sources(source string) []string {
+	return append([]string{source}, l.overlays...)
+}
+
+// loadSource loads a single manifest from either a file path or URL without resolving includes
+func (l *Loader) loadSource(source string) (*Manifest, error) {
+	l.logger.Debug("loading manifest", "source", DisplaySource(source))
+
+	// Determine if source is stdin, a URL, or a file path
+	var manifest *Manifest
+	var err error
+	switch {
+	case source == StdinSource:
+		manifest, err = l.LoadFromStdin()
+	case IsURL(source):
+		manifest, err = l.loadURL(source)
+	default:
+		manifest, err = l.LoadFromFile(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l.logger.Debug("loaded manifest", "source", DisplaySource(source), "version", manifest.Meta.Version,
+		"tools", len(manifest.Tools), "includes", len(manifest.Include))
+	return manifest, nil
+}
 
 // parseYAML parses YAML data into a Manifest struct
 func (l *Loader) parseYAML(data []byte) (*Manifest, error) {
 	var manifest Manifest
 
 	// Parse YAML
-	if err := yaml.Unmarshal(data, &manifest); err != nil {
-		return nil, fmt.Errorf("YAML parsing error: %v", err)
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, newLoadError(ErrorTypeSyntax, fmt.Errorf("YAML parsing error: %v", err))
+	}
+
+	// Expand ${VAR} references in string values
+	if l.expandEnv {
+		if err := interpolateEnv(&document, l.lookupEnv); err != nil {
+			return nil, newLoadError(ErrorTypeEnvironment, fmt.Errorf("environment interpolation: %v", err))
+		}
+	}
+
+	if document.Kind != 0 {
+		if err := document.Decode(&manifest); err != nil {
+			return nil, newLoadError(ErrorTypeValidation, fmt.Errorf("YAML parsing error: %v", err))
+		}
 	}
 
 	// Apply defaults to tools
@@ -119,7 +272,7 @@ func (l *Loader) parseYAML(data []byte) (*Manifest, error) {
 
 	// Validate the manifest
 	if err := manifest.Validate(); err != nil {
-		return nil, fmt.Errorf("manifest validation failed: %v", err)
+		return nil, newLoadError(ErrorTypeValidation, fmt.Errorf("manifest validation failed: %v", err))
 	}
 
 	return &manifest, nil
@@ -139,7 +292,20 @@ func (l *Loader) MergeManifests(manifests ...*Manifest) (*Manifest, error) {
 		if manifests[i] == nil {
 			continue
 		}
-		result = result.Merge(*manifests[i])
+		if l.logger.Enabled(context.Background(), slog.LevelDebug) {
+			var overridden []string
+			for _, tool := range manifests[i].Tools {
+				if result.GetTool(tool.ID) != nil {
+					overridden = append(overridden, tool.ID)
+				}
+			}
+			l.logger.Debug("merging manifest", "layer", i, "tools", len(manifests[i].Tools), "overrides", overridden, "merge", l.mergeMode)
+		}
+		merged, err := result.MergeWith(*manifests[i], l.mergeMode)
+		if err != nil {
+			return nil, err
+		}
+		result = merged
 	}
 
 	// Apply defaults and validate the merged result
@@ -157,6 +323,7 @@ func (l *Loader) LoadMultipleSources(sources ...string) (*Manifest, error) {
 		return nil, errors.New("no sources provided")
 	}
 
+	l.addSourceHosts(sources...)
 	manifests := make([]*Manifest, 0, len(sources))
 
 	for i, source := range sources {
@@ -164,9 +331,9 @@ func (l *Loader) LoadMultipleSources(sources ...string) (*Manifest, error) {
 			continue
 		}
 
-		manifest, err := l.LoadFromSource(source)
+		manifest, err := l.loadWithIncludes(source, "", nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load manifest %d from %s: %v", i, source, err)
+			return nil, fmt.Errorf("failed to load manifest %d from %s: %w", i, DisplaySource(source), err)
 		}
 
 		manifests = append(manifests, manifest)
@@ -227,17 +394,35 @@ func (l *Loader) ValidateManifestStructure(data []byte) error {
 			return fmt.Errorf("tool %d must be an object", i)
 		}
 
-		requiredFields := []string{"id", "name", "rationale", "require", "check", "links"}
+		requiredFields := []string{"id", "name", "rationale", "check", "links"}
 		for _, field := range requiredFields {
 			if _, exists := toolMap[field]; !exists {
 				return fmt.Errorf("tool %d missing required field: %s", i, field)
 			}
 		}
+
+		// Informational, plugin, service, git config, and env tools may omit the version requirement
+		informational, _ := toolMap["informational"].(bool)
+		checkMap, _ := toolMap["check"].(map[string]interface{})
+		_, isPlugin := checkMap["plugin"]
+		isService := checkMap["type"] == CheckTypeService
+		isGitConfig := checkMap["type"] == CheckTypeGitConfig
+		isEnv := checkMap["type"] == CheckTypeEnv
+		if !informational && !isPlugin && !isService && !isGitConfig && !isEnv {
+			if _, exists := toolMap["require"]; !exists {
+				return fmt.Errorf("tool %d missing required field: require", i)
+			}
+		}
 	}
 
 	return nil
 }
 
+// SetLogger sets the logger manifest loading and merging are logged to at debug level; slog's
+// default logger when the loader was created is used otherwise
+func (l *Loader) SetLogger(logger *slog.Logger) {
+	l.logger = logger
+}
 
 // SetHTTPTimeout sets the timeout for HTTP requests
 func (l *Loader) SetHTTPTimeout(timeout time.Duration) {
@@ -247,4 +432,81 @@ func (l *Loader) SetHTTPTimeout(timeout time.Duration) {
 // SetHTTPClient allows setting a custom HTTP client
 func (l *Loader) SetHTTPClient(client *http.Client) {
 	l.httpClient = client
-}
\ No newline at end of file
+}
+
+// AddHeader adds a custom header sent with remote manifest requests
+func (l *Loader) AddHeader(name, value string) {
+	l.headers.Add(name, value)
+}
+
+// SetBearerToken sets a bearer token sent with remote manifest requests
+func (l *Loader) SetBearerToken(token string) {
+	l.bearerToken = token
+}
+
+// SetMergeMode sets how tools defined by more than one merged manifest are merged; includes
+// may ask for a stricter mode
+func (l *Loader) SetMergeMode(mode string) {
+	l.mergeMode = mode
+}
+
+// SetOverlays sets manifests merged, in order, over every manifest loaded with LoadFromSource,
+// so a base manifest can be layered with team and local ones; later overlays take precedence
+func (l *Loader) SetOverlays(sources []string) {
+	l.overlays = sources
+}
+
+// SetStdin sets the reader `-` loads the manifest from
+func (l *Loader) SetStdin(stdin io.Reader) {
+	l.stdin = stdin
+	l.stdinData = nil
+}
+
+// SetExpandEnv turns expansion of ${VAR} references in manifest values on or off
+func (l *Loader) SetExpandEnv(enabled bool) {
+	l.expandEnv = enabled
+}
+
+// SetBundleDir sets the directory manifest bundles are extracted into
+func (l *Loader) SetBundleDir(dir string) {
+	l.bundleDir = dir
+}
+
+// SetNetrcEntries sets netrc-style credentials used for matching hosts
+func (l *Loader) SetNetrcEntries(entries []NetrcEntry) {
+	l.netrcEntries = entries
+}
+
+// ServerTime sends a HEAD request to a remote manifest URL and returns the server's Date header
+func (l *Loader) ServerTime(url string) (time.Time, error) {
+	l.addSourceHosts(url)
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid URL format: %s", url)
+	}
+	l.applyCredentials(req)
+
+	client := *l.httpClient
+	client.CheckRedirect = l.redirectPolicy(l.httpClient.CheckRedirect)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if hint := tlsHint(err); hint != "" {
+			return time.Time{}, fmt.Errorf("failed to reach %s: %v (%s)", url, err, hint)
+		}
+		return time.Time{}, fmt.Errorf("failed to reach %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	date := resp.Header.Get("Date")
+	if date == "" {
+		return time.Time{}, fmt.Errorf("no Date header in response from %s", url)
+	}
+
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid Date header from %s: %v", url, err)
+	}
+
+	return serverTime, nil
+}
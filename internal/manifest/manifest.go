@@ -4,13 +4,23 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/scheduler"
 )
 
+// SupportedVersions lists the manifest schema versions (meta.version) this build can load
+var SupportedVersions = []int{1, 2}
+
+// CurrentVersion is the latest manifest schema version
+const CurrentVersion = 2
+
 // Manifest represents the complete configuration for tool requirements
 type Manifest struct {
 	Meta     ManifestMeta     `yaml:"meta" json:"meta"`
 	Defaults ManifestDefaults `yaml:"defaults,omitempty" json:"defaults,omitempty"`
 	Tools    []ToolDefinition `yaml:"tools" json:"tools"`
+	Include  []Include        `yaml:"include,omitempty" json:"include,omitempty"`
 }
 
 // ManifestMeta contains metadata about the manifest
@@ -24,6 +34,7 @@ type ManifestMeta struct {
 type ManifestDefaults struct {
 	TimeoutSeconds int    `yaml:"timeout_sec,omitempty" json:"timeout_sec,omitempty"`
 	RegexKey       string `yaml:"regex_key,omitempty" json:"regex_key,omitempty"`
+	StrictSemver   bool   `yaml:"strict_semver,omitempty" json:"strict_semver,omitempty"`
 }
 
 // Validate performs comprehensive validation of the manifest
@@ -36,10 +47,29 @@ func (m *Manifest) Validate() error {
 		return fmt.Errorf("defaults validation failed: %v", err)
 	}
 
-	if len(m.Tools) == 0 {
+	// A manifest that only includes others may have no tools of its own
+	if len(m.Tools) == 0 && len(m.Include) == 0 {
 		return errors.New("tools list cannot be empty")
 	}
 
+	if m.Defaults.StrictSemver && m.Meta.Version < 2 {
+		return fmt.Errorf("defaults.strict_semver is a schema v2 field but meta.version is %d; set meta.version: 2 or run 'goctor migrate'", m.Meta.Version)
+	}
+
+	if len(m.Include) > 0 && m.Meta.Version < 2 {
+		return fmt.Errorf("include is a schema v2 field but meta.version is %d; set meta.version: 2 or run 'goctor migrate'", m.Meta.Version)
+	}
+	for _, include := range m.Include {
+		if include.Source == "" {
+			return errors.New("include entries cannot be empty")
+		}
+		if include.Merge != "" {
+			if err := ValidateMergeMode(include.Merge); err != nil {
+				return fmt.Errorf("include %s: %v", include.Source, err)
+			}
+		}
+	}
+
 	// Check for duplicate tool IDs
 	toolIDs := make(map[string]bool)
 	for i, tool := range m.Tools {
@@ -52,6 +82,40 @@ func (m *Manifest) Validate() error {
 		if err := tool.Validate(); err != nil {
 			return fmt.Errorf("tool %d (%s) validation failed: %v", i, tool.ID, err)
 		}
+
+		// Schema v2 fields are not allowed in v1 manifests
+		if m.Meta.Version < 2 {
+			if fields := tool.v2FieldsInUse(); len(fields) > 0 {
+				return fmt.Errorf("tool %d (%s) uses schema v2 fields (%s) but meta.version is %d; set meta.version: 2 or run 'goctor migrate'",
+					i, tool.ID, strings.Join(fields, ", "), m.Meta.Version)
+			}
+		}
+	}
+
+	// Dependencies may point into includes, so they are checked once includes are merged
+	if len(m.Include) == 0 {
+		if err := m.validateDependencies(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateDependencies checks that depends_on names tools of this manifest without cycles
+func (m *Manifest) validateDependencies() error {
+	tasks := make([]scheduler.Task, len(m.Tools))
+	for i, tool := range m.Tools {
+		for _, dep := range tool.DependsOn {
+			if m.GetTool(dep) == nil {
+				return fmt.Errorf("tool %s depends on unknown tool %s", tool.ID, dep)
+			}
+		}
+		tasks[i] = scheduler.Task{ID: tool.ID, DependsOn: tool.DependsOn}
+	}
+
+	if err := scheduler.Validate(tasks); err != nil {
+		return fmt.Errorf("depends_on: %v", err)
 	}
 
 	return nil
@@ -64,32 +128,10 @@ func (m *Manifest) ApplyDefaults() {
 	}
 }
 
-// Merge combines this manifest with another, with the other taking precedence
+// Merge combines this manifest with another, with the other taking precedence; a tool defined
+// by both is replaced by the other's definition
 func (m *Manifest) Merge(other Manifest) Manifest {
-	result := Manifest{
-		Meta:     other.Meta, // Use the other's metadata
-		Defaults: m.mergeDefaults(other.Defaults),
-		Tools:    make([]ToolDefinition, 0),
-	}
-
-	// Create a map of tools from the other manifest
-	otherTools := make(map[string]ToolDefinition)
-	for _, tool := range other.Tools {
-		otherTools[tool.ID] = tool
-	}
-
-	// Add all tools from other manifest first
-	for _, tool := range other.Tools {
-		result.Tools = append(result.Tools, tool)
-	}
-
-	// Add tools from this manifest that aren't in the other
-	for _, tool := range m.Tools {
-		if _, exists := otherTools[tool.ID]; !exists {
-			result.Tools = append(result.Tools, tool)
-		}
-	}
-
+	result, _ := m.MergeWith(other, MergeOverride)
 	return result
 }
 
@@ -105,6 +147,10 @@ func (m *Manifest) mergeDefaults(other ManifestDefaults) ManifestDefaults {
 		result.RegexKey = other.RegexKey
 	}
 
+	if other.StrictSemver {
+		result.StrictSemver = true
+	}
+
 	return result
 }
 
@@ -120,7 +166,7 @@ func (m *Manifest) GetTool(id string) *ToolDefinition {
 
 // Validate performs validation of the manifest metadata
 func (mm *ManifestMeta) Validate() error {
-	if mm.Version != 1 {
+	if !isSupportedVersion(mm.Version) {
 		return fmt.Errorf("unsupported manifest version: %d", mm.Version)
 	}
 
@@ -144,6 +190,16 @@ func (mm *ManifestMeta) Validate() error {
 	return nil
 }
 
+// isSupportedVersion returns true if the manifest schema version can be loaded
+func isSupportedVersion(version int) bool {
+	for _, supported := range SupportedVersions {
+		if version == supported {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate performs validation of the manifest defaults
 func (md *ManifestDefaults) Validate() error {
 	if md.TimeoutSeconds < 0 {
@@ -154,7 +210,12 @@ func (md *ManifestDefaults) Validate() error {
 		return errors.New("timeout too large")
 	}
 
-	// No validation for empty regex key since it's optional
+	// The regex key is optional
+	if md.RegexKey != "" {
+		if err := ValidateRegexKey(md.RegexKey); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -47,7 +47,7 @@ func TestManifestValidation(t *testing.T) {
 			name: "invalid meta version",
 			manifest: Manifest{
 				Meta: ManifestMeta{
-					Version:  2,
+					Version:  99,
 					Name:     "Test Manifest",
 					Language: "en",
 				},
@@ -261,6 +261,15 @@ func TestManifestDefaultsValidation(t *testing.T) {
 			expectError: true,
 			errorMsg:    "timeout too large",
 		},
+		{
+			name: "invalid regex key",
+			defaults: ManifestDefaults{
+				TimeoutSeconds: 5,
+				RegexKey:       "ver sion",
+			},
+			expectError: true,
+			errorMsg:    "invalid regex key",
+		},
 		{
 			name: "empty regex key - allowed",
 			defaults: ManifestDefaults{
@@ -341,6 +350,21 @@ func TestManifestApplyDefaults(t *testing.T) {
 	if manifest.Tools[1].TimeoutSeconds != 15 {
 		t.Errorf("Expected tool2 timeout to remain 15 (explicit value), got %d", manifest.Tools[1].TimeoutSeconds)
 	}
+
+	// Global strict mode applies only to tools using the semver scheme
+	manifest.Defaults.StrictSemver = true
+	manifest.Tools[1].Check.VersionScheme = "calver"
+	manifest.ApplyDefaults()
+
+	if !manifest.Tools[0].Check.StrictSemver {
+		t.Error("Expected tool1 to inherit strict_semver from defaults")
+	}
+	if manifest.Tools[1].Check.StrictSemver {
+		t.Error("Expected the calver tool2 not to inherit strict_semver")
+	}
+	if err := manifest.Validate(); err == nil || !strings.Contains(err.Error(), "defaults.strict_semver") {
+		t.Errorf("Expected defaults.strict_semver to be rejected in a v1 manifest, got %v", err)
+	}
 }
 
 func TestManifestMerge(t *testing.T) {
@@ -469,4 +493,57 @@ func findToolByID(tools []ToolDefinition, id string) *ToolDefinition {
 		}
 	}
 	return nil
-}
\ No newline at end of file
+}
+func TestManifestDependsOnValidation(t *testing.T) {
+	tool := func(id string, dependsOn ...string) ToolDefinition {
+		return ToolDefinition{
+			ID:              id,
+			Name:            id,
+			Rationale:       "test",
+			RequiredVersion: ">=1.0",
+			Check: CheckConfig{
+				Command: []string{id, "--version"},
+				Regex:   `(?P<ver>\d+\.\d+)`,
+			},
+			Links:     map[string]string{"homepage": "https://example.com/"},
+			DependsOn: dependsOn,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		version  int
+		tools    []ToolDefinition
+		include  []Include
+		errorMsg string
+	}{
+		{"valid chain", 2, []ToolDefinition{tool("docker"), tool("compose", "docker"), tool("buildx", "compose", "docker")}, nil, ""},
+		{"v1 manifest", 1, []ToolDefinition{tool("docker"), tool("compose", "docker")}, nil, "schema v2 fields (depends_on)"},
+		{"unknown tool", 2, []ToolDefinition{tool("compose", "docker")}, nil, "tool compose depends on unknown tool docker"},
+		{"unknown tool may come from an include", 2, []ToolDefinition{tool("compose", "docker")}, []Include{{Source: "base.yaml"}}, ""},
+		{"self dependency", 2, []ToolDefinition{tool("docker", "docker")}, nil, "a tool cannot depend on itself"},
+		{"duplicate entry", 2, []ToolDefinition{tool("docker"), tool("compose", "docker", "docker")}, nil, "duplicate depends_on entry: docker"},
+		{"cycle", 2, []ToolDefinition{tool("a", "b"), tool("b", "c"), tool("c", "a")}, nil, "depends_on: dependency cycle detected: a -> b -> c -> a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Manifest{
+				Meta:    ManifestMeta{Version: tt.version, Name: "test"},
+				Tools:   tt.tools,
+				Include: tt.include,
+			}
+
+			err := m.Validate()
+			if tt.errorMsg == "" {
+				if err != nil {
+					t.Errorf("Expected no validation error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
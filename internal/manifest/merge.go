@@ -0,0 +1,148 @@
+package manifest
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// Merge modes, which decide what happens to a tool defined by more than one merged manifest
+const (
+	// MergeOverride replaces the earlier definition with the later one
+	MergeOverride = "override"
+	// MergeStrictestConstraint keeps the later definition but requires both constraints
+	MergeStrictestConstraint = "strictest-constraint"
+	// MergeErrorOnConflict fails unless both definitions are the same
+	MergeErrorOnConflict = "error-on-conflict"
+)
+
+// MergeModes lists the merge modes, from the most to the least permissive
+var MergeModes = []string{MergeOverride, MergeStrictestConstraint, MergeErrorOnConflict}
+
+// ValidateMergeMode returns an error unless mode names a merge mode
+func ValidateMergeMode(mode string) error {
+	if !slices.Contains(MergeModes, mode) {
+		return fmt.Errorf("unknown merge mode %q (available: %s)", mode, strings.Join(MergeModes, ", "))
+	}
+	return nil
+}
+
+// stricterMergeMode returns the less permissive of two merge modes; an empty mode is override
+func stricterMergeMode(a, b string) string {
+	if slices.Index(MergeModes, b) > slices.Index(MergeModes, a) {
+		return b
+	}
+	return a
+}
+
+// Include is an entry of a manifest's include list: the manifest to include and, optionally,
+// the merge mode its tools are merged with
+type Include struct {
+	Source string `yaml:"source" json:"source"`
+	Merge  string `yaml:"merge,omitempty" json:"merge,omitempty"`
+}
+
+// UnmarshalYAML accepts an include either as a manifest source or as a map
+func (inc *Include) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		inc.Source = value.Value
+		return nil
+	}
+
+	type plainInclude Include
+	return value.Decode((*plainInclude)(inc))
+}
+
+// MergeWith combines this manifest with another, with the other taking precedence, and merges
+// tools both define as mode says; a tool included with a stricter merge mode keeps it
+func (m *Manifest) MergeWith(other Manifest, mode string) (Manifest, error) {
+	result := Manifest{
+		Meta:     other.Meta, // Use the other's metadata
+		Defaults: m.mergeDefaults(other.Defaults),
+		Tools:    make([]ToolDefinition, 0, len(m.Tools)+len(other.Tools)),
+	}
+
+	// Tools of the other manifest come first, merged with the definition they replace
+	for _, tool := range other.Tools {
+		if base := m.GetTool(tool.ID); base != nil {
+			merged, err := mergeTool(*base, tool, stricterMergeMode(mode, stricterMergeMode(base.mergeMode, tool.mergeMode)))
+			if err != nil {
+				return Manifest{}, err
+			}
+			tool = merged
+		}
+		result.Tools = append(result.Tools, tool)
+	}
+
+	// Add tools from this manifest that aren't in the other
+	for _, tool := range m.Tools {
+		if other.GetTool(tool.ID) == nil {
+			result.Tools = append(result.Tools, tool)
+		}
+	}
+
+	return result, nil
+}
+
+// mergeTool merges two definitions of a tool, over taking precedence
+func mergeTool(base, over ToolDefinition, mode string) (ToolDefinition, error) {
+	over.mergeMode = stricterMergeMode(base.mergeMode, over.mergeMode)
+
+	switch mode {
+	case MergeErrorOnConflict:
+		if !reflect.DeepEqual(definition(base), definition(over)) {
+			return ToolDefinition{}, fmt.Errorf("tool %s is defined differently by two merged manifests (%s)", over.ID, mode)
+		}
+	case MergeStrictestConstraint:
+		return mergeConstraints(base, over)
+	}
+	return over, nil
+}
+
+// mergeConstraints returns over requiring the constraints of both definitions, or an error if
+// no version can meet them both
+func mergeConstraints(base, over ToolDefinition) (ToolDefinition, error) {
+	switch {
+	case base.RequireFrom != nil || over.RequireFrom != nil:
+		// Constraints read from project files are only known when the tool is checked
+		if !reflect.DeepEqual(base.RequireFrom, over.RequireFrom) || base.RequiredVersion != over.RequiredVersion {
+			return ToolDefinition{}, fmt.Errorf("tool %s: require_from cannot be combined with another constraint (%s)", over.ID, MergeStrictestConstraint)
+		}
+		return over, nil
+	case base.RequiredVersion == "" || base.RequiredVersion == over.RequiredVersion:
+		return over, nil
+	case over.RequiredVersion == "":
+		over.RequiredVersion = base.RequiredVersion
+		over.RecommendedVersion = cmp.Or(over.RecommendedVersion, base.RecommendedVersion)
+		return over, nil
+	}
+
+	baseScheme := cmp.Or(base.Check.VersionScheme, semver.DefaultScheme)
+	overScheme := cmp.Or(over.Check.VersionScheme, semver.DefaultScheme)
+	if baseScheme != overScheme {
+		return ToolDefinition{}, fmt.Errorf("tool %s: require %q (%s) and %q (%s) use different version schemes (%s)",
+			over.ID, base.RequiredVersion, baseScheme, over.RequiredVersion, overScheme, MergeStrictestConstraint)
+	}
+
+	combined := base.RequiredVersion + " " + over.RequiredVersion
+	if conflictingConstraints(overScheme, combined) != nil {
+		return ToolDefinition{}, fmt.Errorf("tool %s: require %q and %q cannot both be met (%s)",
+			over.ID, base.RequiredVersion, over.RequiredVersion, MergeStrictestConstraint)
+	}
+	over.RequiredVersion = combined
+	return over, nil
+}
+
+// definition returns the tool as its manifest defines it, without the state set when loading
+func definition(td ToolDefinition) ToolDefinition {
+	td.defaultRegexKey = ""
+	td.timeoutFromDefaults = false
+	td.mergeMode = ""
+	td.manifestDir = ""
+	return td
+}
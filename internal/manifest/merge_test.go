@@ -0,0 +1,176 @@
+package manifest
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMergeWith(t *testing.T) {
+	tool := func(require, scheme string) ToolDefinition {
+		return ToolDefinition{
+			ID:              "go",
+			Name:            "Go",
+			RequiredVersion: require,
+			Check:           CheckConfig{Command: []string{"go", "version"}, VersionScheme: scheme},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		mode     string
+		base     ToolDefinition
+		over     ToolDefinition
+		expected string
+		errorMsg string
+	}{
+		{"override", MergeOverride, tool(">=1.20", ""), tool("<1.0", ""), "<1.0", ""},
+		{"strictest combines constraints", MergeStrictestConstraint, tool(">=1.20", ""), tool("<1.23", ""), ">=1.20 <1.23", ""},
+		{"strictest keeps a shared constraint once", MergeStrictestConstraint, tool(">=1.20", ""), tool(">=1.20", ""), ">=1.20", ""},
+		{"strictest keeps the base constraint", MergeStrictestConstraint, tool(">=1.20", ""), tool("", ""), ">=1.20", ""},
+		{"strictest irreconcilable", MergeStrictestConstraint, tool("<1.0", ""), tool(">=2.0", ""), "", `tool go: require "<1.0" and ">=2.0" cannot both be met (strictest-constraint)`},
+		{"strictest lenient", MergeStrictestConstraint, tool(">=17", "lenient"), tool("<11", "lenient"), "", "cannot both be met"},
+		{"strictest calver is not checked", MergeStrictestConstraint, tool(">=2024.01", "calver"), tool("<2023.01", "calver"), ">=2024.01 <2023.01", ""},
+		{"strictest different schemes", MergeStrictestConstraint, tool(">=1.20", ""), tool(">=1.20", "loose"), ">=1.20", ""},
+		{"strictest different schemes and constraints", MergeStrictestConstraint, tool(">=1.20", ""), tool(">=1.21", "loose"), "", "use different version schemes"},
+		{"error on conflict with the same definition", MergeErrorOnConflict, tool(">=1.20", ""), tool(">=1.20", ""), ">=1.20", ""},
+		{"error on conflict", MergeErrorOnConflict, tool(">=1.20", ""), tool(">=1.22", ""), "", "tool go is defined differently by two merged manifests (error-on-conflict)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := Manifest{Tools: []ToolDefinition{tt.base}}
+			merged, err := base.MergeWith(Manifest{Tools: []ToolDefinition{tt.over}}, tt.mode)
+			if tt.errorMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(merged.Tools) != 1 || merged.Tools[0].RequiredVersion != tt.expected {
+				t.Errorf("Expected one tool requiring %q, got %+v", tt.expected, merged.Tools)
+			}
+		})
+	}
+}
+
+func TestLoadWithIncludeMergeMode(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "base.yaml", nil, "go")
+	write := func(name, include string) string {
+		path := filepath.Join(dir, name)
+		content := "meta:\n  version: 2\n  name: " + name + "\ninclude:\n" + include + `tools:
+  - id: go
+    name: go
+    rationale: test
+    require: ">=2.0"
+    check:
+      cmd: ["go", "--version"]
+      regex: "(?P<ver>\\d+\\.\\d+)"
+    links:
+      homepage: https://example.com/
+`
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write manifest: %v", err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name     string
+		include  string
+		mode     string
+		expected string
+		errorMsg string
+	}{
+		{"plain include overrides", "  - base.yaml\n", MergeOverride, ">=2.0", ""},
+		{"include without a mode", "  - source: base.yaml\n", MergeOverride, ">=2.0", ""},
+		{"include merge mode", "  - source: base.yaml\n    merge: error-on-conflict\n", MergeOverride, "", "tool go is defined differently"},
+		{"loader merge mode", "  - base.yaml\n", MergeStrictestConstraint, ">=1.0 >=2.0", ""},
+		{"include cannot loosen the loader mode", "  - source: base.yaml\n    merge: override\n", MergeErrorOnConflict, "", "tool go is defined differently"},
+		{"unknown merge mode", "  - source: base.yaml\n    merge: additive\n", MergeOverride, "", `include base.yaml: unknown merge mode "additive"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := NewLoader()
+			loader.SetMergeMode(tt.mode)
+			m, err := loader.LoadFromSource(write("tools.yaml", tt.include))
+			if tt.errorMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tool := m.GetTool("go"); tool == nil || tool.RequiredVersion != tt.expected {
+				t.Errorf("Expected go to require %q, got %+v", tt.expected, tool)
+			}
+		})
+	}
+}
+
+func TestIncludeMergeModeSticksToLayers(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "policy.yaml", nil, "go")
+	team := filepath.Join(dir, "team.yaml")
+	content := "meta:\n  version: 2\n  name: team\ninclude:\n  - source: policy.yaml\n    merge: error-on-conflict\n"
+	if err := os.WriteFile(team, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	local := writeManifest(t, dir, "local.yaml", nil, "go")
+
+	// local.yaml defines go as policy.yaml does, so only a different definition conflicts
+	if _, err := NewLoader().LoadMultipleSources(team, local); err != nil {
+		t.Fatalf("Unexpected error for the same definition: %v", err)
+	}
+
+	data, err := os.ReadFile(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(local, []byte(strings.Replace(string(data), ">=1.0", ">=0.9", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLoader().LoadMultipleSources(team, local); err == nil || !strings.Contains(err.Error(), "tool go is defined differently") {
+		t.Errorf("Expected the include's merge mode to reject the redefinition, got %v", err)
+	}
+}
+
+func TestLoadWarnsOfUnsatisfiableConstraints(t *testing.T) {
+	dir := t.TempDir()
+	base := writeManifest(t, dir, "base.yaml", nil, "go")
+	team := writeManifest(t, dir, "team.yaml", nil, "go")
+	data, err := os.ReadFile(team)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(team, []byte(strings.Replace(string(data), ">=1.0", ">=2.0 <1.5", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var logs bytes.Buffer
+	loader := NewLoader()
+	loader.SetLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+	if _, err := loader.LoadFromSource(base); err != nil || logs.Len() > 0 {
+		t.Fatalf("Expected no warning for base.yaml, got error %v and logs %q", err, logs.String())
+	}
+
+	// The warning is about the merged manifest, whichever layer the constraint came from
+	loader.SetOverlays([]string{team})
+	if _, err := loader.LoadFromSource(base); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := `level=WARN msg="unsatisfiable version constraint" tool=go problem="require \">=2.0 <1.5\" can never be met: >=2.0.0 conflicts with <1.5.0"`
+	if !strings.Contains(logs.String(), want) {
+		t.Errorf("Expected warning %s, got %q", want, logs.String())
+	}
+}
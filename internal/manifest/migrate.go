@@ -0,0 +1,78 @@
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MigrateToCurrent rewrites manifest YAML to the current schema version
+// Formatting and comments are preserved; already-current manifests are returned unchanged
+func MigrateToCurrent(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("YAML parsing error: %v", err)
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("manifest must be a YAML mapping")
+	}
+
+	versionNode := findMappingValue(findMappingValue(doc.Content[0], "meta"), "version")
+	if versionNode == nil {
+		return nil, errors.New("missing required 'meta.version' field")
+	}
+
+	version, err := strconv.Atoi(versionNode.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid meta.version: %s", versionNode.Value)
+	}
+
+	if version == CurrentVersion {
+		return data, nil
+	}
+	if version != 1 {
+		return nil, fmt.Errorf("cannot migrate manifest version %d", version)
+	}
+
+	// v2 is a superset of v1, so only the version marker changes. Rewrite it in
+	// place so comments, blank lines, and formatting are preserved exactly.
+	lines := bytes.Split(data, []byte("\n"))
+	lineIndex := versionNode.Line - 1
+	column := versionNode.Column - 1
+	if lineIndex < 0 || lineIndex >= len(lines) || column < 0 || column > len(lines[lineIndex]) {
+		return nil, errors.New("failed to locate meta.version in manifest")
+	}
+
+	line := lines[lineIndex]
+	rest := bytes.Replace(line[column:], []byte(versionNode.Value), []byte(strconv.Itoa(CurrentVersion)), 1)
+	lines[lineIndex] = append(append([]byte{}, line[:column]...), rest...)
+	migrated := bytes.Join(lines, []byte("\n"))
+
+	// Make sure the result still loads; references stay unexpanded so they survive migration
+	loader := NewLoader()
+	loader.SetExpandEnv(false)
+	if _, err := loader.parseYAML(migrated); err != nil {
+		return nil, fmt.Errorf("migrated manifest is invalid: %v", err)
+	}
+
+	return migrated, nil
+}
+
+// findMappingValue returns the value node for key in a mapping node, or nil
+func findMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
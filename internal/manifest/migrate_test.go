@@ -0,0 +1,104 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMigrateToCurrent(t *testing.T) {
+	v1 := `# Team tools
+meta:
+  version: 1
+  name: "Team Tools"
+tools:
+  - id: go
+    name: "Go"
+    rationale: "Go development" # keep this comment
+    require: ">=1.22"
+    check:
+      cmd: ["go", "version"]
+      regex: "go(?P<ver>\\d+\\.\\d+)"
+    links:
+      homepage: "https://go.dev/"
+`
+
+	migrated, err := MigrateToCurrent([]byte(v1))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := string(migrated)
+	if !strings.Contains(output, "version: 2") {
+		t.Errorf("Expected migrated manifest to declare version 2, got:\n%s", output)
+	}
+	if !strings.Contains(output, "# keep this comment") {
+		t.Errorf("Expected comments to be preserved, got:\n%s", output)
+	}
+
+	// Migrating again is a no-op
+	again, err := MigrateToCurrent(migrated)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if string(again) != output {
+		t.Errorf("Expected migrating a current manifest to be a no-op")
+	}
+}
+
+func TestV2FieldsRejectedInV1(t *testing.T) {
+	m := Manifest{
+		Meta: ManifestMeta{Version: 1, Name: "Test"},
+		Tools: []ToolDefinition{
+			{
+				ID:              "go",
+				Name:            "Go",
+				Rationale:       "Go development",
+				RequiredVersion: ">=1.22",
+				Check: CheckConfig{
+					Command: []string{"go", "version"},
+					Regex:   "go(?P<ver>\\d+\\.\\d+)",
+				},
+				Links:     map[string]string{"homepage": "https://go.dev/"},
+				Severity:  SeverityWarning,
+				Platforms: []string{"darwin"},
+			},
+		},
+	}
+
+	err := m.Validate()
+	if err == nil {
+		t.Fatal("Expected v2 fields in a v1 manifest to be rejected")
+	}
+	if !strings.Contains(err.Error(), "severity, platforms") || !strings.Contains(err.Error(), "goctor migrate") {
+		t.Errorf("Expected actionable error naming the fields, got: %v", err)
+	}
+
+	m.Meta.Version = 2
+	if err := m.Validate(); err != nil {
+		t.Errorf("Expected v2 manifest to be valid, got: %v", err)
+	}
+}
+
+func TestToolDefinitionAppliesTo(t *testing.T) {
+	tool := ToolDefinition{Platforms: []string{"darwin", "linux/amd64"}}
+
+	tests := []struct {
+		goos     string
+		goarch   string
+		expected bool
+	}{
+		{"darwin", "arm64", true},
+		{"linux", "amd64", true},
+		{"linux", "arm64", false},
+	}
+
+	for _, tt := range tests {
+		if got := tool.AppliesTo(tt.goos, tt.goarch); got != tt.expected {
+			t.Errorf("AppliesTo(%s, %s) = %t, expected %t", tt.goos, tt.goarch, got, tt.expected)
+		}
+	}
+
+	if !(&ToolDefinition{}).AppliesTo("linux", "arm64") {
+		t.Error("Expected tools without platforms to apply everywhere")
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !toml
+
+package manifest
+
+import "errors"
+
+// errTOMLUnsupported is returned for TOML manifests by builds without the toml tag, as TOML
+// needs a third-party decoder and the default build sticks to the standard library and yaml
+var errTOMLUnsupported = errors.New("TOML manifests are not supported by this build of goctor; rebuild it with -tags toml or convert the manifest to YAML or JSON")
+
+// tomlManifestNames is empty, so discovery skips tools.toml rather than finding a manifest
+// this build cannot load
+var tomlManifestNames []string
+
+// decodeTOML rejects TOML manifests, which this build cannot decode
+func decodeTOML(data []byte, document *map[string]interface{}) error {
+	return errTOMLUnsupported
+}
+
+// tomlPosition never finds a position, as this build has no TOML parse errors
+func tomlPosition(err error) (int, int, bool) {
+	return 0, 0, false
+}
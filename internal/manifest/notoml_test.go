@@ -0,0 +1,54 @@
+//go:build !toml
+
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadTOMLManifestUnsupported(t *testing.T) {
+	dir := t.TempDir()
+
+	// TOML is still recognised, by extension or content, so the error says how to load it
+	for _, file := range []string{"tools.toml", "manifest"} {
+		path := filepath.Join(dir, file)
+		if err := os.WriteFile(path, []byte(tomlManifest), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := NewLoader().LoadFromSource(path)
+		if err == nil || !strings.Contains(err.Error(), "rebuild it with -tags toml") {
+			t.Errorf("Expected %s to be rejected as TOML, got: %v", file, err)
+		}
+	}
+}
+
+func TestFindManifestSkipsTOML(t *testing.T) {
+	repo := t.TempDir()
+	web := filepath.Join(repo, "services", "web")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(web, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(web, "tools.toml"), []byte(tomlManifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// With only a tools.toml there is no manifest this build can load
+	if got, ok := FindManifest(web); ok {
+		t.Errorf("FindManifest() = %q, want no manifest", got)
+	}
+
+	// A loadable manifest further up is used instead
+	if err := os.WriteFile(filepath.Join(repo, "tools.yaml"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := FindManifest(web); !ok || got != filepath.Join(repo, "tools.yaml") {
+		t.Errorf("FindManifest() = %q, %v, want %s", got, ok, filepath.Join(repo, "tools.yaml"))
+	}
+}
@@ -0,0 +1,111 @@
+package manifest
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Tool sort keys accepted by SortTools
+const (
+	SortByName     = "name"
+	SortByID       = "id"
+	SortBySeverity = "severity"
+)
+
+// ToolSortKeys lists the supported sort keys
+var ToolSortKeys = []string{SortByName, SortByID, SortBySeverity}
+
+// ToolFilter selects a subset of a manifest's tools
+type ToolFilter struct {
+	// Tags keeps tools carrying at least one of these tags
+	Tags []string
+	// Platform keeps tools that apply to this OS ("darwin") or OS/arch pair ("darwin/arm64")
+	Platform string
+}
+
+// FilterTools returns the tools matching the filter, in manifest order
+func FilterTools(tools []ToolDefinition, filter ToolFilter) []ToolDefinition {
+	filtered := make([]ToolDefinition, 0, len(tools))
+
+	for _, tool := range tools {
+		if len(filter.Tags) > 0 && !tool.HasAnyTag(filter.Tags) {
+			continue
+		}
+		if filter.Platform != "" && !tool.AvailableOn(filter.Platform) {
+			continue
+		}
+		filtered = append(filtered, tool)
+	}
+
+	return filtered
+}
+
+// SortTools sorts tools in place by the given key; an empty key keeps manifest order
+// Severity order puts blocking tools first, then warnings, then informational tools
+func SortTools(tools []ToolDefinition, key string) error {
+	switch key {
+	case "":
+		return nil
+	case SortByName:
+		sort.SliceStable(tools, func(i, j int) bool {
+			return strings.ToLower(tools[i].Name) < strings.ToLower(tools[j].Name)
+		})
+	case SortByID:
+		sort.SliceStable(tools, func(i, j int) bool {
+			return tools[i].ID < tools[j].ID
+		})
+	case SortBySeverity:
+		sort.SliceStable(tools, func(i, j int) bool {
+			return severityRank(tools[i]) < severityRank(tools[j])
+		})
+	default:
+		return fmt.Errorf("invalid sort key %q (must be one of: %s)", key, strings.Join(ToolSortKeys, ", "))
+	}
+
+	return nil
+}
+
+// severityRank orders tools by how strongly a failure affects the exit code
+func severityRank(tool ToolDefinition) int {
+	switch {
+	case tool.Informational:
+		return 2
+	case tool.GetSeverity() == SeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// HasAnyTag returns true if the tool carries at least one of the given tags
+func (td *ToolDefinition) HasAnyTag(tags []string) bool {
+	for _, tag := range tags {
+		if slices.Contains(td.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// AvailableOn returns true if the tool applies to an OS ("linux") or OS/arch pair ("linux/amd64")
+// An OS alone matches tools restricted to any architecture of that OS
+func (td *ToolDefinition) AvailableOn(platform string) bool {
+	goos, goarch, hasArch := strings.Cut(platform, "/")
+	if hasArch {
+		return td.AppliesTo(goos, goarch)
+	}
+
+	if len(td.Platforms) == 0 {
+		return true
+	}
+
+	for _, p := range td.Platforms {
+		if p == goos || strings.HasPrefix(p, goos+"/") {
+			return true
+		}
+	}
+
+	return false
+}
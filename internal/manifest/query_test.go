@@ -0,0 +1,78 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func queryTools() []ToolDefinition {
+	return []ToolDefinition{
+		{ID: "go", Name: "Go", Tags: []string{"backend"}},
+		{ID: "docker", Name: "Docker", Severity: SeverityWarning, Tags: []string{"backend", "infra"}, Platforms: []string{"linux", "darwin"}},
+		{ID: "brew", Name: "Homebrew", Informational: true, Platforms: []string{"darwin/arm64"}},
+		{ID: "awscli", Name: "aws", Tags: []string{"infra"}, Platforms: []string{"linux/amd64"}},
+	}
+}
+
+func toolIDs(tools []ToolDefinition) []string {
+	ids := make([]string, len(tools))
+	for i, tool := range tools {
+		ids[i] = tool.ID
+	}
+	return ids
+}
+
+func TestFilterTools(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ToolFilter
+		want   []string
+	}{
+		{"no filter", ToolFilter{}, []string{"go", "docker", "brew", "awscli"}},
+		{"single tag", ToolFilter{Tags: []string{"infra"}}, []string{"docker", "awscli"}},
+		{"any of several tags", ToolFilter{Tags: []string{"backend", "infra"}}, []string{"go", "docker", "awscli"}},
+		{"OS matches any architecture", ToolFilter{Platform: "darwin"}, []string{"go", "docker", "brew"}},
+		{"OS and architecture", ToolFilter{Platform: "linux/arm64"}, []string{"go", "docker"}},
+		{"tag and platform", ToolFilter{Tags: []string{"infra"}, Platform: "linux"}, []string{"docker", "awscli"}},
+		{"no match", ToolFilter{Tags: []string{"frontend"}}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toolIDs(FilterTools(queryTools(), tt.filter))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterTools() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortTools(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    []string
+		wantErr bool
+	}{
+		{"", []string{"go", "docker", "brew", "awscli"}, false},
+		{SortByName, []string{"awscli", "docker", "go", "brew"}, false},
+		{SortByID, []string{"awscli", "brew", "docker", "go"}, false},
+		{SortBySeverity, []string{"go", "awscli", "docker", "brew"}, false},
+		{"version", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			tools := queryTools()
+			err := SortTools(tools, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SortTools() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := toolIDs(tools); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortTools() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
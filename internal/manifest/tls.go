@@ -0,0 +1,87 @@
+package manifest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// TLSOptions configure how remote manifests are fetched over HTTPS, for networks whose
+// proxies intercept TLS with a private certificate authority
+type TLSOptions struct {
+	// CACertFile is a PEM file of certificates trusted in addition to the system roots
+	CACertFile string
+	// InsecureSkipVerify accepts any server certificate; remote manifests can then be altered
+	// by anyone on the network path
+	InsecureSkipVerify bool
+}
+
+// SetTLSOptions makes remote manifest requests trust the certificates of opts, going through
+// the proxy of HTTPS_PROXY, HTTP_PROXY, and NO_PROXY as before
+func (l *Loader) SetTLSOptions(opts TLSOptions) error {
+	if opts.CACertFile == "" && !opts.InsecureSkipVerify {
+		return nil
+	}
+
+	config := &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
+	if opts.CACertFile != "" {
+		pool, err := certPool(opts.CACertFile)
+		if err != nil {
+			return err
+		}
+		config.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	transport.TLSClientConfig = config
+	l.httpClient.Transport = transport
+	return nil
+}
+
+// certPool returns the system certificate pool with the certificates of a PEM file added
+func certPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA certificate: %v", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// tlsHint returns advice for a failed request whose cause is a TLS or proxy problem, or ""
+func tlsHint(err error) string {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var verification *tls.CertificateVerificationError
+	var header tls.RecordHeaderError
+
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return "the certificate is signed by an unknown authority; if a proxy intercepts TLS, pass its CA certificate with --ca-cert"
+	case errors.As(err, &hostname):
+		return "the certificate is for another host; check the URL, or whether a proxy answers for the server"
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		return "the certificate has expired or is not yet valid; check the server's certificate and this machine's clock"
+	case errors.As(err, &invalid), errors.As(err, &verification):
+		return "the certificate could not be verified; pass the issuing CA certificate with --ca-cert"
+	case errors.As(err, &header):
+		return "the server did not answer with TLS; check whether the URL should use http:// or the port is right"
+	case strings.Contains(err.Error(), "proxyconnect"):
+		return "the proxy could not be reached; check HTTPS_PROXY and NO_PROXY"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,30 @@
+//go:build toml
+
+package manifest
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlManifestNames are the default manifest names in TOML
+var tomlManifestNames = []string{"tools.toml"}
+
+// decodeTOML decodes a TOML manifest into document
+func decodeTOML(data []byte, document *map[string]interface{}) error {
+	if _, err := toml.Decode(string(data), document); err != nil {
+		return syntaxError(fmt.Errorf("TOML parsing error: %w", err), data)
+	}
+	return nil
+}
+
+// tomlPosition returns the line and column of a TOML parse failure
+func tomlPosition(err error) (int, int, bool) {
+	var tomlErr toml.ParseError
+	if !errors.As(err, &tomlErr) {
+		return 0, 0, false
+	}
+	return tomlErr.Position.Line, tomlErr.Position.Col, true
+}
@@ -0,0 +1,91 @@
+//go:build toml
+
+package manifest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadTOMLManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		wantName string
+		wantErr  string
+	}{
+		{"toml by extension", "tools.toml", tomlManifest, "TOML tools", ""},
+		{"toml by content", "manifest", tomlManifest, "TOML tools", ""},
+		{"invalid toml", "bad.toml", "[meta\n", "", "TOML parsing error"},
+		{"same validation", "invalid.toml", "[meta]\nversion = 1\nname = \"x\"\n", "", "manifest validation failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			m, err := NewLoader().LoadFromSource(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if m.Meta.Name != tt.wantName {
+				t.Errorf("Expected name %q, got %q", tt.wantName, m.Meta.Name)
+			}
+			if tool := m.GetTool("go"); tool == nil || tool.RequiredVersion != ">=1.20" || tool.Links["homepage"] != "https://go.dev/" {
+				t.Errorf("Unexpected tool definition: %+v", tool)
+			}
+		})
+	}
+}
+
+func TestTOMLSyntaxErrorPosition(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tools.toml")
+	if err := os.WriteFile(path, []byte("[meta]\nversion = 1\nname = \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewLoader().LoadFromSource(path)
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) {
+		t.Fatalf("Expected a LoadError, got %v", err)
+	}
+	if loadErr.Type != ErrorTypeSyntax || loadErr.Line != 3 || loadErr.Column != 8 {
+		t.Errorf("Expected a syntax error at line 3 column 8, got %s at line %d column %d: %v", loadErr.Type, loadErr.Line, loadErr.Column, err)
+	}
+}
+
+func TestFindManifestTOML(t *testing.T) {
+	repo := t.TempDir()
+	web := filepath.Join(repo, "services", "web")
+	for _, dir := range []string{".git", "services/web"} {
+		if err := os.MkdirAll(filepath.Join(repo, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{"tools.yaml", "services/web/tools.toml", "services/web/tools.json"} {
+		if err := os.WriteFile(filepath.Join(repo, file), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// tools.toml comes after tools.yaml and before tools.json in each directory
+	if got, ok := FindManifest(web); !ok || got != filepath.Join(web, "tools.toml") {
+		t.Errorf("FindManifest() = %q, %v, want %s", got, ok, filepath.Join(web, "tools.toml"))
+	}
+}
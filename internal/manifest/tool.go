@@ -3,40 +3,527 @@ package manifest
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ikorihn/goctor/internal/links"
+	"github.com/ikorihn/goctor/internal/platform"
+	"github.com/ikorihn/goctor/internal/semver"
 )
 
 // CheckConfig represents the check configuration for a tool
 type CheckConfig struct {
-	Command []string `yaml:"cmd" json:"cmd"`
-	Regex   string   `yaml:"regex" json:"regex"`
+	Type      string            `yaml:"type,omitempty" json:"type,omitempty"`
+	Probe     string            `yaml:"probe,omitempty" json:"probe,omitempty"`
+	OS        string            `yaml:"os,omitempty" json:"os,omitempty"`
+	GitConfig map[string]string `yaml:"git_config,omitempty" json:"git_config,omitempty"`
+	// Var, Exists, and Sensitive configure an env check: the variable, whether its value must
+	// name an existing dir or file, and whether its value is masked in all output
+	Var           string            `yaml:"var,omitempty" json:"var,omitempty"`
+	Exists        string            `yaml:"exists,omitempty" json:"exists,omitempty"`
+	Sensitive     bool              `yaml:"sensitive,omitempty" json:"sensitive,omitempty"`
+	Command       []string          `yaml:"cmd" json:"cmd"`
+	Regex         string            `yaml:"regex" json:"regex"`
+	RegexKey      string            `yaml:"regex_key,omitempty" json:"regex_key,omitempty"`
+	VersionScheme string            `yaml:"version_scheme,omitempty" json:"version_scheme,omitempty"`
+	Plugin        string            `yaml:"plugin,omitempty" json:"plugin,omitempty"`
+	Shell         bool              `yaml:"shell,omitempty" json:"shell,omitempty"`
+	Workdir       string            `yaml:"workdir,omitempty" json:"workdir,omitempty"`
+	Env           map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+	Fallbacks     [][]string        `yaml:"fallbacks,omitempty" json:"fallbacks,omitempty"`
+	StrictSemver  bool              `yaml:"strict_semver,omitempty" json:"strict_semver,omitempty"`
+	// ExpectExitCodes are the exit codes of a version command whose output is parsed; [0] if empty
+	ExpectExitCodes []int `yaml:"expect_exit_codes,omitempty" json:"expect_exit_codes,omitempty"`
+	// VersionSubcommand lets restricted mode run `<tool id> version`, which it otherwise refuses
+	VersionSubcommand bool `yaml:"version_subcommand,omitempty" json:"version_subcommand,omitempty"`
+}
+
+// ExpectsExitCode returns true if a version command exiting with code should have its
+// output parsed; only 0 is expected unless expect_exit_codes says otherwise
+func (cc CheckConfig) ExpectsExitCode(code int) bool {
+	if len(cc.ExpectExitCodes) == 0 {
+		return code == 0
+	}
+	return slices.Contains(cc.ExpectExitCodes, code)
 }
 
 // ToolDefinition represents a development tool with its requirements and detection logic
 type ToolDefinition struct {
-	ID              string            `yaml:"id" json:"id"`
-	Name            string            `yaml:"name" json:"name"`
-	Rationale       string            `yaml:"rationale" json:"rationale"`
-	RequiredVersion string            `yaml:"require" json:"require"`
-	Check           CheckConfig       `yaml:"check" json:"check"`
-	Links           map[string]string `yaml:"links" json:"links"`
-	TimeoutSeconds  int               `yaml:"timeout_sec,omitempty" json:"timeout_seconds,omitempty"`
+	ID                 string            `yaml:"id" json:"id"`
+	Name               string            `yaml:"name" json:"name"`
+	Rationale          string            `yaml:"rationale" json:"rationale"`
+	RequiredVersion    string            `yaml:"require" json:"require"`
+	RecommendedVersion string            `yaml:"-" json:"recommended,omitempty"`
+	Check              CheckConfig       `yaml:"check" json:"check"`
+	Links              map[string]string `yaml:"links" json:"links"`
+	TimeoutSeconds     int               `yaml:"timeout_sec,omitempty" json:"timeout_seconds,omitempty"`
+	Informational      bool              `yaml:"informational,omitempty" json:"informational,omitempty"`
+	OnFail             []string          `yaml:"on_fail,omitempty" json:"on_fail,omitempty"`
+	OnRecover          []string          `yaml:"on_recover,omitempty" json:"on_recover,omitempty"`
+
+	// Schema v2 fields
+	Use         string            `yaml:"use,omitempty" json:"use,omitempty"`
+	Severity    string            `yaml:"severity,omitempty" json:"severity,omitempty"`
+	Tags        []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Scope       string            `yaml:"scope,omitempty" json:"scope,omitempty"` // machine or project
+	Platforms   []string          `yaml:"platforms,omitempty" json:"platforms,omitempty"`
+	Install     map[string]string `yaml:"install,omitempty" json:"install,omitempty"`
+	Checks      []SubCheck        `yaml:"checks,omitempty" json:"checks,omitempty"`
+	DependsOn   []string          `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+	Upstream    *Upstream         `yaml:"upstream,omitempty" json:"upstream,omitempty"`
+	RequireFrom *RequireFrom      `yaml:"require_from,omitempty" json:"require_from,omitempty"`
+	Deprecated  bool              `yaml:"deprecated,omitempty" json:"deprecated,omitempty"`
+	Sunset      string            `yaml:"sunset,omitempty" json:"sunset,omitempty"` // YYYY-MM-DD
+	// AllowPrerelease lets a prerelease meet the constraints its release meets
+	AllowPrerelease bool `yaml:"allow_prerelease,omitempty" json:"allow_prerelease,omitempty"`
+	// ExcludeVersions block installed versions even when they meet the constraint
+	ExcludeVersions []ExcludedVersion `yaml:"exclude_versions,omitempty" json:"exclude_versions,omitempty"`
+	// Variants are versions of the tool needed side by side, each located and checked on its own
+	Variants []Variant `yaml:"variants,omitempty" json:"variants,omitempty"`
+	// ExpectSHA256 pins the SHA-256 of the executable found on PATH, keyed by platform
+	// ("linux" or "linux/amd64"); platforms without an entry are not verified
+	ExpectSHA256 map[string]string `yaml:"expect_sha256,omitempty" json:"expect_sha256,omitempty"`
+
+	// defaultRegexKey is the manifest's defaults.regex_key, used when check.regex_key is unset
+	defaultRegexKey string
+	// timeoutFromDefaults is true if TimeoutSeconds was set from defaults.timeout_sec
+	timeoutFromDefaults bool
+	// mergeMode is the merge mode of the include the tool came from, if it set one
+	mergeMode string
+	// manifestDir is the directory of the local manifest the tool came from, "" for remote
+	// manifests, stdin, and bundles
+	manifestDir string
+}
+
+// SunsetLayout is the date format of `sunset`
+const SunsetLayout = "2006-01-02"
+
+// MigrationLink is the links key of a deprecated tool's migration guide
+const MigrationLink = "migration"
+
+// Upstream declares where a tool's latest release is published, for `doctor outdated`
+// Sources are tried in the order github, homebrew, endoflife
+type Upstream struct {
+	GitHub    string `yaml:"github,omitempty" json:"github,omitempty"`       // owner/repo or its https://github.com URL
+	Homebrew  string `yaml:"homebrew,omitempty" json:"homebrew,omitempty"`   // Formula name
+	EndOfLife string `yaml:"endoflife,omitempty" json:"endoflife,omitempty"` // endoflife.date product
+}
+
+// RequireFrom reads a tool's required version from a project file when the tool is checked,
+// e.g. node's from .nvmrc or go's from go.mod; `require`, if set, applies while the file is missing
+type RequireFrom struct {
+	File  string `yaml:"file" json:"file"`                       // Relative to check.workdir within the manifest's directory
+	Key   string `yaml:"key,omitempty" json:"key,omitempty"`     // Tool in .tool-versions, directive in go.mod
+	Match string `yaml:"match,omitempty" json:"match,omitempty"` // How the installed version must match
+}
+
+// Ways the installed version must match the file's
+const (
+	MatchMinimum    = "minimum"    // At least the file's version (the default)
+	MatchExact      = "exact"      // Exactly the file's version
+	MatchCompatible = "compatible" // The file's version up to its last component, as with ~
+)
+
+// RequireMatches lists the values of require_from.match
+var RequireMatches = []string{MatchMinimum, MatchExact, MatchCompatible}
+
+// UnmarshalYAML accepts require_from either as a file path or as a map
+func (rf *RequireFrom) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		rf.File = value.Value
+		return nil
+	}
+
+	type plainRequireFrom RequireFrom
+	return value.Decode((*plainRequireFrom)(rf))
+}
+
+// Constraint returns the version constraint for a version read from the file
+func (rf *RequireFrom) Constraint(version string) string {
+	switch rf.Match {
+	case MatchExact:
+		return "=" + version
+	case MatchCompatible:
+		return "~" + version
+	default:
+		return ">=" + version
+	}
+}
+
+// validate checks that a file is named and the match is known
+func (rf *RequireFrom) validate() error {
+	if rf.File == "" {
+		return errors.New("require_from needs a file")
+	}
+	if rf.Match != "" && !slices.Contains(RequireMatches, rf.Match) {
+		return fmt.Errorf("invalid require_from.match %q (must be one of: %s)", rf.Match, strings.Join(RequireMatches, ", "))
+	}
+	return nil
+}
+
+// ExcludedVersion blocks the installed versions matching a constraint, such as a release with a
+// known bug; in YAML it is the constraint alone or a map with a reason
+type ExcludedVersion struct {
+	Versions string `yaml:"versions" json:"versions"`
+	Reason   string `yaml:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// UnmarshalYAML accepts a bare constraint as well as the map form
+func (ev *ExcludedVersion) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		ev.Versions = value.Value
+		return nil
+	}
+
+	type plainExcludedVersion ExcludedVersion
+	return value.Decode((*plainExcludedVersion)(ev))
+}
+
+// String returns the constraint followed by the reason, if any
+func (ev ExcludedVersion) String() string {
+	if ev.Reason == "" {
+		return ev.Versions
+	}
+	return ev.Versions + " (" + ev.Reason + ")"
+}
+
+// githubRepoRegex matches owner/repo, optionally as a github.com URL to the repository or its releases
+var githubRepoRegex = regexp.MustCompile(`^(?:https://github\.com/)?([A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+?)(?:\.git|/releases)?/?$`)
+
+// GitHubRepository returns the owner/repo of the GitHub source, or "" if there is none
+func (u *Upstream) GitHubRepository() string {
+	matches := githubRepoRegex.FindStringSubmatch(u.GitHub)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// validate checks that at least one source is declared and each is well-formed
+func (u *Upstream) validate() error {
+	if u.GitHub == "" && u.Homebrew == "" && u.EndOfLife == "" {
+		return errors.New("upstream must declare github, homebrew, or endoflife")
+	}
+	if u.GitHub != "" && u.GitHubRepository() == "" {
+		return fmt.Errorf("invalid upstream.github %q (expected owner/repo or a https://github.com URL)", u.GitHub)
+	}
+
+	validNameRegex := regexp.MustCompile(`^[a-z0-9][a-z0-9@.+_-]*$`)
+	if u.Homebrew != "" && !validNameRegex.MatchString(u.Homebrew) {
+		return fmt.Errorf("invalid upstream.homebrew formula %q", u.Homebrew)
+	}
+	if u.EndOfLife != "" && !validNameRegex.MatchString(u.EndOfLife) {
+		return fmt.Errorf("invalid upstream.endoflife product %q", u.EndOfLife)
+	}
+
+	return nil
+}
+
+// SubCheck is an additional named check aggregated into its parent tool's result,
+// e.g. the compose and buildx plugins of docker
+type SubCheck struct {
+	Name            string      `yaml:"name" json:"name"`
+	RequiredVersion string      `yaml:"require,omitempty" json:"require,omitempty"`
+	Check           CheckConfig `yaml:"check" json:"check"`
+}
+
+// Variant is one of several versions of a tool a project needs side by side, e.g. Java 11
+// and 17; the check settings it sets replace the tool's
+type Variant struct {
+	Name            string      `yaml:"name" json:"name"`
+	RequiredVersion string      `yaml:"require,omitempty" json:"require,omitempty"`
+	Check           CheckConfig `yaml:"check,omitempty" json:"check,omitempty"`
+}
+
+// Check types
+const (
+	CheckTypeCommand   = "command"
+	CheckTypePlugin    = "plugin"
+	CheckTypeService   = "service"
+	CheckTypeOS        = "os"
+	CheckTypeGitConfig = "git-config"
+	CheckTypeEnv       = "env"
+)
+
+// CheckTypes lists the supported check backends
+var CheckTypes = []string{CheckTypeCommand, CheckTypePlugin, CheckTypeService, CheckTypeOS, CheckTypeGitConfig, CheckTypeEnv}
+
+// Values of check.exists for env checks
+const (
+	EnvExistsDir  = "dir"
+	EnvExistsFile = "file"
+)
+
+// ServiceProbes maps built-in service probes to a command that only succeeds while the
+// service is up; docker and podman print the server version
+var ServiceProbes = map[string][]string{
+	"docker":         {"docker", "info", "--format", "{{.ServerVersion}}"},
+	"colima":         {"colima", "status"},
+	"podman-machine": {"podman", "info", "--format", "{{.Version.Version}}"},
+	"kubernetes":     {"kubectl", "get", "--raw", "/readyz", "--request-timeout=5s"},
+}
+
+// Tool severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Tool scopes: machine tools such as docker and git are installed once per machine, while
+// project tools such as linters are pinned per repository
+const (
+	ScopeMachine = "machine"
+	ScopeProject = "project"
+)
+
+// Scopes lists the tool scopes
+var Scopes = []string{ScopeMachine, ScopeProject}
+
+// requirementTiers is the map form of `require`
+type requirementTiers struct {
+	Minimum     string `yaml:"minimum"`
+	Recommended string `yaml:"recommended"`
+}
+
+// UnmarshalYAML accepts `require` either as a constraint string or as a map of tiers
+// A tool with `use: builtin/NAME` starts from the built-in definition; fields the tool sets
+// replace the built-in ones, and links and install hints are merged key by key
+func (td *ToolDefinition) UnmarshalYAML(value *yaml.Node) error {
+	type plainToolDefinition ToolDefinition
+
+	node := *value
+	var tiers *requirementTiers
+	var use *yaml.Node
+
+	if value.Kind == yaml.MappingNode {
+		node.Content = make([]*yaml.Node, 0, len(value.Content))
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			key, val := value.Content[i], value.Content[i+1]
+			if key.Value == "require" && val.Kind == yaml.MappingNode {
+				for j := 0; j+1 < len(val.Content); j += 2 {
+					if tier := val.Content[j].Value; tier != "minimum" && tier != "recommended" {
+						return fmt.Errorf("line %d: unknown require tier %q (expected minimum or recommended)", val.Content[j].Line, tier)
+					}
+				}
+				tiers = &requirementTiers{}
+				if err := val.Decode(tiers); err != nil {
+					return err
+				}
+				continue
+			}
+			if key.Value == "use" {
+				use = val
+			}
+			node.Content = append(node.Content, key, val)
+		}
+	}
+
+	if use != nil {
+		builtin, err := builtinDefinition(use.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", use.Line, err)
+		}
+		if err := builtin.Decode((*plainToolDefinition)(td)); err != nil {
+			return err
+		}
+	}
+
+	if err := node.Decode((*plainToolDefinition)(td)); err != nil {
+		return err
+	}
+
+	if tiers != nil {
+		td.RequiredVersion = tiers.Minimum
+		td.RecommendedVersion = tiers.Recommended
+	}
+
+	return nil
 }
 
 // CheckCommand returns the command to execute for version checking
+// Service checks using a built-in probe run the probe's command
 func (td *ToolDefinition) CheckCommand() []string {
+	if td.Check.Probe != "" {
+		return ServiceProbes[td.Check.Probe]
+	}
+	if td.IsOS() {
+		return platform.OSVersionCommand(td.Check.OS)
+	}
 	return td.Check.Command
 }
 
+// CheckCommands returns the check command followed by its fallbacks, in the order they are tried
+func (td *ToolDefinition) CheckCommands() [][]string {
+	return append([][]string{td.CheckCommand()}, td.Check.Fallbacks...)
+}
+
+// SubCheckDefinition returns a standalone tool definition for one of the tool's sub-checks
+// Sub-checks without a requirement only need to be present, so they are informational
+// Sub-checks of a strict tool are strict as well
+func (td *ToolDefinition) SubCheckDefinition(sc SubCheck) ToolDefinition {
+	definition := ToolDefinition{
+		ID:              td.ID,
+		Name:            td.Name + " " + sc.Name,
+		Rationale:       td.Rationale,
+		RequiredVersion: sc.RequiredVersion,
+		Check:           sc.Check,
+		Links:           td.Links,
+		TimeoutSeconds:  td.TimeoutSeconds,
+		Informational:   sc.RequiredVersion == "",
+		AllowPrerelease: td.AllowPrerelease,
+	}
+	definition.Check.StrictSemver = sc.Check.StrictSemver || td.Check.StrictSemver
+	definition.defaultRegexKey = td.defaultRegexKey
+	return definition
+}
+
+// VariantDefinition returns a standalone tool definition for one of the tool's variants: the
+// tool with the variant's requirement and its check settings laid over the tool's
+func (td *ToolDefinition) VariantDefinition(v Variant) ToolDefinition {
+	definition := *td
+	definition.Name = td.Name + " " + v.Name
+	definition.RequiredVersion = v.RequiredVersion
+	definition.Check = td.Check.withOverrides(v.Check)
+	definition.Variants = nil
+	return definition
+}
+
+// withOverrides returns the check with the settings override sets replacing its own
+// A command replaces the fallbacks as well; environment variables are merged
+func (cc CheckConfig) withOverrides(override CheckConfig) CheckConfig {
+	merged := cc
+	if len(override.Command) > 0 {
+		merged.Command = override.Command
+		merged.Fallbacks = override.Fallbacks
+	} else if len(override.Fallbacks) > 0 {
+		merged.Fallbacks = override.Fallbacks
+	}
+	if override.Regex != "" {
+		merged.Regex = override.Regex
+	}
+	if override.RegexKey != "" {
+		merged.RegexKey = override.RegexKey
+	}
+	if override.VersionScheme != "" {
+		merged.VersionScheme = override.VersionScheme
+	}
+	if override.Workdir != "" {
+		merged.Workdir = override.Workdir
+	}
+	if len(override.ExpectExitCodes) > 0 {
+		merged.ExpectExitCodes = override.ExpectExitCodes
+	}
+	merged.Shell = cc.Shell || override.Shell
+	merged.StrictSemver = cc.StrictSemver || override.StrictSemver
+	merged.VersionSubcommand = cc.VersionSubcommand || override.VersionSubcommand
+
+	if len(override.Env) > 0 {
+		merged.Env = make(map[string]string, len(cc.Env)+len(override.Env))
+		for name, value := range cc.Env {
+			merged.Env[name] = value
+		}
+		for name, value := range override.Env {
+			merged.Env[name] = value
+		}
+	}
+
+	return merged
+}
+
+// IsDeprecatedAt returns true if the tool is marked deprecated or its sunset date has passed
+// by the given time; the sunset day itself counts as past
+func (td *ToolDefinition) IsDeprecatedAt(now time.Time) bool {
+	if td.Deprecated {
+		return true
+	}
+	if td.Sunset == "" {
+		return false
+	}
+
+	sunset, err := time.ParseInLocation(SunsetLayout, td.Sunset, now.Location())
+	return err == nil && !now.Before(sunset)
+}
+
+// IsPlugin returns true if the tool is checked by an external plugin instead of cmd/regex
+func (td *ToolDefinition) IsPlugin() bool {
+	return td.Check.Plugin != ""
+}
+
+// IsService returns true if the tool checks that a service is healthy rather than installed
+func (td *ToolDefinition) IsService() bool {
+	return td.Check.Type == CheckTypeService
+}
+
+// IsOS returns true if the tool checks the version of the OS, a Linux distribution, or the kernel
+func (td *ToolDefinition) IsOS() bool {
+	return td.Check.Type == CheckTypeOS
+}
+
+// IsGitConfig returns true if the tool checks git configuration values instead of a version
+func (td *ToolDefinition) IsGitConfig() bool {
+	return td.Check.Type == CheckTypeGitConfig
+}
+
+// IsEnv returns true if the tool checks an environment variable instead of a version
+func (td *ToolDefinition) IsEnv() bool {
+	return td.Check.Type == CheckTypeEnv
+}
+
+// GitConfigKeys returns the git configuration keys the tool checks in sorted order
+func (td *ToolDefinition) GitConfigKeys() []string {
+	keys := make([]string, 0, len(td.Check.GitConfig))
+	for key := range td.Check.GitConfig {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // VersionRegex returns the regex pattern for version extraction
 func (td *ToolDefinition) VersionRegex() string {
 	return td.Check.Regex
 }
 
+// RequireFromPath returns the path of the require_from file; a relative file is found in
+// check.workdir, and a relative workdir in the directory of the local manifest that declared
+// the tool, so the file is the same wherever goctor runs
+func (td *ToolDefinition) RequireFromPath() string {
+	path := td.RequireFrom.File
+	if filepath.IsAbs(path) {
+		return path
+	}
+	dir := td.Check.Workdir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(td.manifestDir, dir)
+	}
+	return filepath.Join(dir, path)
+}
+
+// VersionRegexKey returns the capture group holding the version: check.regex_key, then the
+// manifest's defaults.regex_key; "" leaves the group to the checker's search for a version group
+func (td *ToolDefinition) VersionRegexKey() string {
+	if td.Check.RegexKey != "" {
+		return td.Check.RegexKey
+	}
+	return td.defaultRegexKey
+}
+
 // Validate performs comprehensive validation of the tool definition
 func (td *ToolDefinition) Validate() error {
+	// Each variant carries its own requirement and is validated as a tool of its own
+	if len(td.Variants) > 0 {
+		return td.validateVariants()
+	}
+
 	if err := td.validateRequiredFields(); err != nil {
 		return err
 	}
@@ -45,14 +532,36 @@ func (td *ToolDefinition) Validate() error {
 		return err
 	}
 
-	if err := td.ValidateVersionConstraint(); err != nil {
-		return err
+	// Informational, plugin, service, git config, and env tools may report status without a
+	// constraint, and require_from reads it from a file
+	if (!td.Informational && !td.IsPlugin() && !td.IsService() && !td.IsGitConfig() && !td.IsEnv() && td.RequireFrom == nil) || td.RequiredVersion != "" {
+		if err := td.ValidateVersionConstraint(); err != nil {
+			return err
+		}
 	}
 
-	if err := td.ValidateRegex(); err != nil {
+	if td.RecommendedVersion != "" {
+		recommended := *td
+		recommended.RequiredVersion = td.RecommendedVersion
+		if err := recommended.ValidateVersionConstraint(); err != nil {
+			return fmt.Errorf("recommended: %v", err)
+		}
+	}
+
+	if err := td.validateCheckBackend(); err != nil {
 		return err
 	}
 
+	if td.Check.VersionScheme != "" {
+		if _, err := semver.GetScheme(td.Check.VersionScheme); err != nil {
+			return err
+		}
+	}
+
+	if td.Check.StrictSemver && td.Check.VersionScheme != "" && td.Check.VersionScheme != semver.DefaultScheme {
+		return fmt.Errorf("check.strict_semver requires the semver version scheme, not %s", td.Check.VersionScheme)
+	}
+
 	if err := td.ValidateLinks(); err != nil {
 		return err
 	}
@@ -61,13 +570,369 @@ func (td *ToolDefinition) Validate() error {
 		return err
 	}
 
+	if err := td.validateHooks(); err != nil {
+		return err
+	}
+
+	if err := td.validateCommandTemplates(); err != nil {
+		return err
+	}
+
+	if err := td.validateV2Fields(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetSeverity returns the tool severity, defaulting to error
+func (td *ToolDefinition) GetSeverity() string {
+	if td.Severity == "" {
+		return SeverityError
+	}
+	return td.Severity
+}
+
+// AppliesTo returns true if the tool should be checked on the given platform
+// Platform entries are either an OS ("darwin") or an OS/arch pair ("darwin/arm64")
+func (td *ToolDefinition) AppliesTo(goos, goarch string) bool {
+	if len(td.Platforms) == 0 {
+		return true
+	}
+
+	for _, p := range td.Platforms {
+		if p == goos || p == goos+"/"+goarch {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExpectedSHA256 returns the checksum the tool's executable must have on the given platform,
+// preferring an OS/arch entry over an OS entry, or "" if it is not pinned there
+func (td *ToolDefinition) ExpectedSHA256(goos, goarch string) string {
+	if sum, ok := td.ExpectSHA256[goos+"/"+goarch]; ok {
+		return strings.ToLower(sum)
+	}
+	return strings.ToLower(td.ExpectSHA256[goos])
+}
+
+// InstallHint returns the install command for the given package manager, if any
+func (td *ToolDefinition) InstallHint(packageManager string) string {
+	return td.Install[packageManager]
+}
+
+// PreferredInstallHint returns the hint for the first package manager in the list that the tool defines
+func (td *ToolDefinition) PreferredInstallHint(packageManagers []string) string {
+	for _, pm := range packageManagers {
+		if hint, ok := td.Install[pm]; ok {
+			return hint
+		}
+	}
+	return ""
+}
+
+// v2FieldsInUse returns the names of schema v2 fields set on this tool
+func (td *ToolDefinition) v2FieldsInUse() []string {
+	var fields []string
+	if td.Use != "" {
+		fields = append(fields, "use")
+	}
+	if td.Severity != "" {
+		fields = append(fields, "severity")
+	}
+	if len(td.Tags) > 0 {
+		fields = append(fields, "tags")
+	}
+	if td.Scope != "" {
+		fields = append(fields, "scope")
+	}
+	if len(td.Platforms) > 0 {
+		fields = append(fields, "platforms")
+	}
+	if len(td.Install) > 0 {
+		fields = append(fields, "install")
+	}
+	if td.IsPlugin() {
+		fields = append(fields, "check.plugin")
+	}
+	if td.Check.Type != "" {
+		fields = append(fields, "check.type")
+	}
+	if td.Check.Probe != "" {
+		fields = append(fields, "check.probe")
+	}
+	if td.Check.OS != "" {
+		fields = append(fields, "check.os")
+	}
+	if len(td.Check.GitConfig) > 0 {
+		fields = append(fields, "check.git_config")
+	}
+	if td.Check.Var != "" {
+		fields = append(fields, "check.var")
+	}
+	if td.Check.Exists != "" {
+		fields = append(fields, "check.exists")
+	}
+	if td.Check.Sensitive {
+		fields = append(fields, "check.sensitive")
+	}
+	if len(td.Checks) > 0 {
+		fields = append(fields, "checks")
+	}
+	if td.Check.Shell {
+		fields = append(fields, "check.shell")
+	}
+	if td.Check.Workdir != "" {
+		fields = append(fields, "check.workdir")
+	}
+	if len(td.Check.Env) > 0 {
+		fields = append(fields, "check.env")
+	}
+	if len(td.Check.Fallbacks) > 0 {
+		fields = append(fields, "check.fallbacks")
+	}
+	if td.Check.RegexKey != "" {
+		fields = append(fields, "check.regex_key")
+	}
+	if td.Check.StrictSemver {
+		fields = append(fields, "check.strict_semver")
+	}
+	if len(td.Check.ExpectExitCodes) > 0 {
+		fields = append(fields, "check.expect_exit_codes")
+	}
+	if td.Check.VersionSubcommand {
+		fields = append(fields, "check.version_subcommand")
+	}
+	if len(td.DependsOn) > 0 {
+		fields = append(fields, "depends_on")
+	}
+	if td.Upstream != nil {
+		fields = append(fields, "upstream")
+	}
+	if td.RequireFrom != nil {
+		fields = append(fields, "require_from")
+	}
+	if td.Deprecated {
+		fields = append(fields, "deprecated")
+	}
+	if td.Sunset != "" {
+		fields = append(fields, "sunset")
+	}
+	if td.AllowPrerelease {
+		fields = append(fields, "allow_prerelease")
+	}
+	if len(td.ExcludeVersions) > 0 {
+		fields = append(fields, "exclude_versions")
+	}
+	if len(td.Variants) > 0 {
+		fields = append(fields, "variants")
+	}
+	if len(td.ExpectSHA256) > 0 {
+		fields = append(fields, "expect_sha256")
+	}
+	return fields
+}
+
+// validateV2Fields validates severity, tags, platforms, and install hints
+func (td *ToolDefinition) validateV2Fields() error {
+	switch td.Severity {
+	case "", SeverityError, SeverityWarning:
+	default:
+		return fmt.Errorf("invalid severity %q (expected %q or %q)", td.Severity, SeverityError, SeverityWarning)
+	}
+
+	if td.Scope != "" && !slices.Contains(Scopes, td.Scope) {
+		return fmt.Errorf("invalid scope %q (expected %q or %q)", td.Scope, ScopeMachine, ScopeProject)
+	}
+
+	validTagRegex := regexp.MustCompile(`^[a-z0-9-]+$`)
+	for _, tag := range td.Tags {
+		if !validTagRegex.MatchString(tag) {
+			return fmt.Errorf("invalid tag %q (must be lowercase alphanumeric with hyphens)", tag)
+		}
+	}
+
+	validPlatformRegex := regexp.MustCompile(`^(darwin|linux)(/(amd64|arm64|386))?$`)
+	for _, p := range td.Platforms {
+		if !validPlatformRegex.MatchString(p) {
+			return fmt.Errorf("invalid platform %q (expected e.g. \"darwin\" or \"linux/arm64\")", p)
+		}
+	}
+
+	for packageManager, hint := range td.Install {
+		if packageManager == "" || hint == "" {
+			return errors.New("install hints must have a package manager and a command")
+		}
+	}
+
+	if err := td.validateExpectSHA256(validPlatformRegex); err != nil {
+		return err
+	}
+
+	for i, dep := range td.DependsOn {
+		if dep == "" {
+			return errors.New("depends_on entries cannot be empty")
+		}
+		if dep == td.ID {
+			return errors.New("a tool cannot depend on itself")
+		}
+		if slices.Contains(td.DependsOn[:i], dep) {
+			return fmt.Errorf("duplicate depends_on entry: %s", dep)
+		}
+	}
+
+	if td.Upstream != nil {
+		if err := td.Upstream.validate(); err != nil {
+			return err
+		}
+	}
+
+	if td.RequireFrom != nil {
+		if td.IsGitConfig() || td.IsEnv() {
+			return fmt.Errorf("require_from cannot be combined with check.type: %s", td.Check.Type)
+		}
+		if err := td.RequireFrom.validate(); err != nil {
+			return err
+		}
+	}
+
+	if td.Sunset != "" {
+		if _, err := time.Parse(SunsetLayout, td.Sunset); err != nil {
+			return fmt.Errorf("invalid sunset %q (expected YYYY-MM-DD)", td.Sunset)
+		}
+	}
+
+	if err := td.validateExcludeVersions(); err != nil {
+		return err
+	}
+
+	return td.validateSubChecks()
+}
+
+// validateExpectSHA256 checks that checksums are pinned by platform, as hex SHA-256 digests, on
+// tools whose check runs an executable
+func (td *ToolDefinition) validateExpectSHA256(validPlatformRegex *regexp.Regexp) error {
+	if len(td.ExpectSHA256) == 0 {
+		return nil
+	}
+	if td.IsPlugin() || td.IsOS() || td.IsGitConfig() || td.IsEnv() {
+		return errors.New("expect_sha256 requires a command or service check")
+	}
+
+	validSumRegex := regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+	for p, sum := range td.ExpectSHA256 {
+		if !validPlatformRegex.MatchString(p) {
+			return fmt.Errorf("invalid expect_sha256 platform %q (expected e.g. \"darwin\" or \"linux/arm64\")", p)
+		}
+		if !validSumRegex.MatchString(sum) {
+			return fmt.Errorf("invalid expect_sha256 for %s: must be 64 hex digits", p)
+		}
+	}
+	return nil
+}
+
+// validateSubChecks checks that sub-check names are unique and each check is well-formed
+func (td *ToolDefinition) validateSubChecks() error {
+	validNameRegex := regexp.MustCompile(`^[a-z0-9-]+$`)
+	seen := make(map[string]bool, len(td.Checks))
+
+	for _, sc := range td.Checks {
+		if !validNameRegex.MatchString(sc.Name) {
+			return fmt.Errorf("invalid sub-check name %q (must be lowercase alphanumeric with hyphens)", sc.Name)
+		}
+		if seen[sc.Name] {
+			return fmt.Errorf("duplicate sub-check name: %s", sc.Name)
+		}
+		seen[sc.Name] = true
+
+		sub := td.SubCheckDefinition(sc)
+		if err := sub.Validate(); err != nil {
+			return fmt.Errorf("sub-check %s: %v", sc.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateVariants checks that a tool with variants leaves the requirement to them and that
+// each variant, laid over the tool, is a valid version check
+func (td *ToolDefinition) validateVariants() error {
+	switch {
+	case td.RequiredVersion != "" || td.RecommendedVersion != "":
+		return errors.New("a tool with variants cannot have require; set it on each variant")
+	case td.RequireFrom != nil:
+		return errors.New("require_from cannot be combined with variants")
+	case len(td.Checks) > 0:
+		return errors.New("checks cannot be combined with variants")
+	case len(td.ExpectSHA256) > 0:
+		return errors.New("expect_sha256 cannot be combined with variants, which run different executables")
+	case td.IsPlugin() || (td.Check.Type != "" && td.Check.Type != CheckTypeCommand):
+		return errors.New("variants require a version command check")
+	}
+
+	validNameRegex := regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	seen := make(map[string]bool, len(td.Variants))
+	for _, v := range td.Variants {
+		if !validNameRegex.MatchString(v.Name) {
+			return fmt.Errorf("invalid variant name %q (must be lowercase alphanumeric with dots, hyphens, or underscores)", v.Name)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("duplicate variant name: %s", v.Name)
+		}
+		seen[v.Name] = true
+
+		if v.Check.Type != "" || v.Check.Probe != "" || v.Check.OS != "" || v.Check.Plugin != "" || len(v.Check.GitConfig) > 0 {
+			return fmt.Errorf("variant %s: check can only override cmd, fallbacks, regex, regex_key, version_scheme, shell, workdir, env, strict_semver, expect_exit_codes, and version_subcommand", v.Name)
+		}
+
+		variant := td.VariantDefinition(v)
+		if err := variant.Validate(); err != nil {
+			return fmt.Errorf("variant %s: %v", v.Name, err)
+		}
+	}
+
 	return nil
 }
 
 // validateRequiredFields checks that all required fields are not empty
 func (td *ToolDefinition) validateRequiredFields() error {
-	if td.ID == "" || td.Name == "" || td.Rationale == "" || td.RequiredVersion == "" ||
-	   len(td.Check.Command) == 0 || td.Check.Regex == "" || len(td.Links) == 0 {
+	if td.ID == "" || td.Name == "" || td.Rationale == "" || len(td.Links) == 0 {
+		return errors.New("required fields cannot be empty")
+	}
+
+	// Plugins report their own status, so cmd, regex, and require are optional
+	if td.IsPlugin() {
+		return nil
+	}
+
+	// Git config and env checks match values rather than a version
+	if td.IsGitConfig() || td.IsEnv() {
+		return nil
+	}
+
+	// OS checks read the version themselves, so cmd and regex are not needed
+	if td.IsOS() {
+		if td.RequiredVersion == "" && !td.Informational && td.RequireFrom == nil {
+			return errors.New("required fields cannot be empty")
+		}
+		return nil
+	}
+
+	// Services only need a command or probe; regex and require are optional
+	if td.IsService() {
+		if len(td.CheckCommand()) == 0 {
+			return errors.New("required fields cannot be empty")
+		}
+		return nil
+	}
+
+	if len(td.Check.Command) == 0 || td.Check.Regex == "" {
+		return errors.New("required fields cannot be empty")
+	}
+
+	if td.RequiredVersion == "" && !td.Informational && td.RequireFrom == nil {
 		return errors.New("required fields cannot be empty")
 	}
 	return nil
@@ -94,27 +959,303 @@ func (td *ToolDefinition) ValidateVersionConstraint() error {
 		return errors.New("version constraint cannot be empty")
 	}
 
+	// Non-semver schemes validate constraints against their own version format
+	if td.Check.VersionScheme != "" && td.Check.VersionScheme != semver.DefaultScheme {
+		scheme, err := semver.GetScheme(td.Check.VersionScheme)
+		if err != nil {
+			return err
+		}
+		if err := semver.ValidateConstraintString(scheme, td.RequiredVersion); err != nil {
+			return fmt.Errorf("invalid version constraint format: %s (%v)", td.RequiredVersion, err)
+		}
+		return nil
+	}
+
 	// Basic validation for common semver constraint patterns
 	// This is a simplified validation - full semver parsing happens in the semver package
 	validPatterns := []string{
-		`^\d+(\.\d+)*$`,                           // 1.2.3
-		`^>=\d+(\.\d+)*$`,                        // >=1.2.3
-		`^>\d+(\.\d+)*$`,                         // >1.2.3
-		`^<=\d+(\.\d+)*$`,                        // <=1.2.3
-		`^<\d+(\.\d+)*$`,                         // <1.2.3
-		`^~\d+(\.\d+)*$`,                         // ~1.2.3
-		`^\^\d+(\.\d+)*$`,                        // ^1.2.3
-		`^>=\d+(\.\d+)* <\d+(\.\d+)*$`,          // >=1.2 <1.3
+		`^\d+(\.\d+)*$`,   // 1.2.3
+		`^>=\d+(\.\d+)*$`, // >=1.2.3
+		`^>\d+(\.\d+)*$`,  // >1.2.3
+		`^<=\d+(\.\d+)*$`, // <=1.2.3
+		`^<\d+(\.\d+)*$`,  // <1.2.3
+		`^~\d+(\.\d+)*$`,  // ~1.2.3
+		`^\^\d+(\.\d+)*$`, // ^1.2.3
+		`^~>\d+(\.\d+)*$`, // ~>1.2.3
+	}
+
+	// Clauses separated by spaces must all be met, as in >=1.2 <1.3; the pessimistic operator
+	// may stand apart from its version, as in ~> 1.2.3
+	clauses := strings.Fields(strings.ReplaceAll(td.RequiredVersion, "~> ", "~>"))
+	if len(clauses) == 0 {
+		return fmt.Errorf("invalid version constraint format: %s", td.RequiredVersion)
+	}
+	for _, clause := range clauses {
+		if !slices.ContainsFunc(validPatterns, func(pattern string) bool {
+			matched, _ := regexp.MatchString(pattern, clause)
+			return matched
+		}) {
+			return fmt.Errorf("invalid version constraint format: %s", td.RequiredVersion)
+		}
+	}
+	return nil
+}
+
+// validateCheckBackend checks that exactly one of cmd/regex or plugin is configured
+func (td *ToolDefinition) validateCheckBackend() error {
+	if err := td.validateExecution(); err != nil {
+		return err
+	}
+
+	if err := td.validateExitCodes(); err != nil {
+		return err
+	}
+
+	// check.regex_key names a group of the version regex, so it would be ignored silently by
+	// checks that read no version with one
+	if td.Check.RegexKey != "" && (td.IsPlugin() || td.IsOS() || td.IsGitConfig() || td.IsEnv() || td.Check.Regex == "") {
+		return errors.New("check.regex_key requires a command or service check with check.regex")
+	}
+
+	switch td.Check.Type {
+	case "", CheckTypeCommand, CheckTypePlugin:
+	case CheckTypeService:
+		return td.validateService()
+	case CheckTypeOS:
+		return td.validateOS()
+	case CheckTypeGitConfig:
+		return td.validateGitConfig()
+	case CheckTypeEnv:
+		return td.validateEnv()
+	default:
+		return fmt.Errorf("invalid check.type %q (must be one of: %s)", td.Check.Type, strings.Join(CheckTypes, ", "))
+	}
+
+	if td.Check.Probe != "" {
+		return errors.New("check.probe requires check.type: service")
+	}
+	if td.Check.OS != "" {
+		return errors.New("check.os requires check.type: os")
+	}
+	if len(td.Check.GitConfig) > 0 {
+		return errors.New("check.git_config requires check.type: git-config")
+	}
+	if td.Check.Var != "" || td.Check.Exists != "" || td.Check.Sensitive {
+		return errors.New("check.var, check.exists, and check.sensitive require check.type: env")
+	}
+	if td.Check.Type == CheckTypePlugin && !td.IsPlugin() {
+		return errors.New("check.type plugin requires check.plugin")
+	}
+
+	if !td.IsPlugin() {
+		return td.ValidateRegex()
+	}
+
+	if td.Check.Type == CheckTypeCommand {
+		return errors.New("check.plugin cannot be combined with check.type: command")
+	}
+
+	if len(td.Check.Command) > 0 || td.Check.Regex != "" || td.Check.Shell {
+		return errors.New("check.plugin cannot be combined with check.cmd, check.regex, or check.shell")
+	}
+
+	return nil
+}
+
+// validateService checks that a service check uses either a built-in probe or its own command
+// A version requirement needs a regex to extract the version from the command's output
+func (td *ToolDefinition) validateService() error {
+	if td.IsPlugin() {
+		return errors.New("check.plugin cannot be combined with check.type: service")
+	}
+
+	if td.Check.Probe != "" {
+		if _, ok := ServiceProbes[td.Check.Probe]; !ok {
+			return fmt.Errorf("unknown service probe %q (must be one of: %s)", td.Check.Probe, strings.Join(ServiceProbeNames(), ", "))
+		}
+		if len(td.Check.Command) > 0 {
+			return errors.New("check.probe cannot be combined with check.cmd")
+		}
+	}
+
+	if td.Check.Regex != "" {
+		return td.ValidateRegex()
+	}
+	if td.RequiredVersion != "" {
+		return errors.New("service checks with require need check.regex to extract a version")
+	}
+
+	return nil
+}
+
+// validateOS checks that an OS check names what to compare and nothing else
+// check.os is "macos", "kernel", or a Linux distribution's os-release ID such as "ubuntu"
+func (td *ToolDefinition) validateOS() error {
+	if td.Check.OS == "" {
+		return fmt.Errorf("check.type os requires check.os (%s, %s, or an os-release ID such as ubuntu)", platform.OSVersionMacOS, platform.OSVersionKernel)
+	}
+	// os-release IDs are lowercase letters, digits, and "._-"
+	validOSRegex := regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	if !validOSRegex.MatchString(td.Check.OS) {
+		return fmt.Errorf("invalid check.os %q", td.Check.OS)
+	}
+
+	if td.IsPlugin() || td.Check.Probe != "" || len(td.Check.Command) > 0 || td.Check.Regex != "" || td.Check.Shell ||
+		td.Check.Workdir != "" || len(td.Check.Env) > 0 {
+		return errors.New("check.type os cannot be combined with check.cmd, check.regex, check.plugin, check.probe, check.shell, check.workdir, or check.env")
+	}
+
+	return nil
+}
+
+// validateGitConfig checks that a git-config check has valid keys, each with a valid regex
+// The values are matched instead of a version, so require is not allowed
+func (td *ToolDefinition) validateGitConfig() error {
+	if len(td.Check.GitConfig) == 0 {
+		return errors.New("check.type git-config requires check.git_config (KEY: REGEX pairs)")
 	}
 
-	for _, pattern := range validPatterns {
-		matched, _ := regexp.MatchString(pattern, td.RequiredVersion)
-		if matched {
-			return nil
+	// Keys are section.name or section.subsection.name, as git config --get expects
+	validKeyRegex := regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*(\..+)?\.[A-Za-z][A-Za-z0-9-]*$`)
+	for _, key := range td.GitConfigKeys() {
+		if !validKeyRegex.MatchString(key) {
+			return fmt.Errorf("invalid git config key %q (expected e.g. user.email)", key)
+		}
+		if _, err := regexp.Compile(td.Check.GitConfig[key]); err != nil {
+			return fmt.Errorf("malformed regex for git config %s: %v", key, err)
 		}
 	}
 
-	return fmt.Errorf("invalid version constraint format: %s", td.RequiredVersion)
+	if td.RequiredVersion != "" || td.RecommendedVersion != "" {
+		return errors.New("check.type git-config matches values with check.git_config and cannot have require")
+	}
+	if td.IsPlugin() || td.Check.Probe != "" || td.Check.OS != "" || len(td.Check.Command) > 0 || td.Check.Regex != "" || td.Check.Shell {
+		return errors.New("check.type git-config cannot be combined with check.cmd, check.regex, check.plugin, check.probe, check.os, or check.shell")
+	}
+
+	return nil
+}
+
+// validateEnv checks that an env check names a variable and how its value is matched
+// check.regex, when set, must match the value instead of extracting a version, so require is
+// not allowed
+func (td *ToolDefinition) validateEnv() error {
+	validVarRegex := regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	if td.Check.Var == "" {
+		return errors.New("check.type env requires check.var (the environment variable)")
+	}
+	if !validVarRegex.MatchString(td.Check.Var) {
+		return fmt.Errorf("invalid check.var %q", td.Check.Var)
+	}
+
+	switch td.Check.Exists {
+	case "", EnvExistsDir, EnvExistsFile:
+	default:
+		return fmt.Errorf("invalid check.exists %q (must be %s or %s)", td.Check.Exists, EnvExistsDir, EnvExistsFile)
+	}
+	if td.Check.Regex != "" {
+		if _, err := regexp.Compile(td.Check.Regex); err != nil {
+			return fmt.Errorf("malformed regex for %s: %v", td.Check.Var, err)
+		}
+	}
+
+	if td.RequiredVersion != "" || td.RecommendedVersion != "" {
+		return errors.New("check.type env matches the value with check.regex and check.exists and cannot have require")
+	}
+	if td.IsPlugin() || td.Check.Probe != "" || td.Check.OS != "" || len(td.Check.GitConfig) > 0 || len(td.Check.Command) > 0 ||
+		len(td.Check.Fallbacks) > 0 || td.Check.Shell || td.Check.Workdir != "" || len(td.Check.Env) > 0 {
+		return errors.New("check.type env cannot be combined with check.cmd, check.fallbacks, check.plugin, check.probe, check.os, check.git_config, check.shell, check.workdir, or check.env")
+	}
+
+	return nil
+}
+
+// ServiceProbeNames returns the built-in service probe names in sorted order
+func ServiceProbeNames() []string {
+	names := make([]string, 0, len(ServiceProbes))
+	for name := range ServiceProbes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateExcludeVersions checks that every exclude_versions entry is a constraint of the
+// tool's version scheme
+func (td *ToolDefinition) validateExcludeVersions() error {
+	if len(td.ExcludeVersions) == 0 {
+		return nil
+	}
+	if td.IsGitConfig() || td.IsEnv() {
+		return fmt.Errorf("exclude_versions cannot be combined with check.type: %s", td.Check.Type)
+	}
+
+	scheme, err := semver.GetScheme(td.Check.VersionScheme)
+	if err != nil {
+		return err
+	}
+	for _, excluded := range td.ExcludeVersions {
+		if strings.TrimSpace(excluded.Versions) == "" {
+			return errors.New("exclude_versions entries need versions")
+		}
+		if err := semver.ValidateConstraintString(scheme, excluded.Versions); err != nil {
+			return fmt.Errorf("invalid exclude_versions entry %q: %v", excluded.Versions, err)
+		}
+	}
+	return nil
+}
+
+// validateExitCodes checks that expect_exit_codes lists exit codes of a version command
+func (td *ToolDefinition) validateExitCodes() error {
+	if len(td.Check.ExpectExitCodes) == 0 {
+		return nil
+	}
+	if td.IsPlugin() || td.IsService() || td.IsOS() || td.IsGitConfig() || td.IsEnv() {
+		return errors.New("check.expect_exit_codes requires a version command check")
+	}
+	for _, code := range td.Check.ExpectExitCodes {
+		if code < 0 || code > 255 {
+			return fmt.Errorf("check.expect_exit_codes: %d is not an exit code (0-255)", code)
+		}
+	}
+	return nil
+}
+
+// validateExecution checks the shell, fallback, workdir, and env settings of the check
+func (td *ToolDefinition) validateExecution() error {
+	if td.Check.Shell {
+		if len(td.Check.Command) != 1 || strings.TrimSpace(td.Check.Command[0]) == "" {
+			return errors.New("check.shell requires check.cmd to be a single non-empty script")
+		}
+	}
+
+	if len(td.Check.Fallbacks) > 0 && len(td.Check.Command) == 0 {
+		return errors.New("check.fallbacks requires check.cmd")
+	}
+	for _, fallback := range td.Check.Fallbacks {
+		if td.Check.Shell {
+			if len(fallback) != 1 || strings.TrimSpace(fallback[0]) == "" {
+				return errors.New("check.shell requires each check.fallbacks entry to be a single non-empty script")
+			}
+			continue
+		}
+		if len(fallback) == 0 || fallback[0] == "" {
+			return errors.New("check.fallbacks entries cannot be empty")
+		}
+	}
+
+	if td.Check.Workdir != "" && strings.TrimSpace(td.Check.Workdir) == "" {
+		return errors.New("check.workdir cannot be blank")
+	}
+
+	validEnvRegex := regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	for name := range td.Check.Env {
+		if !validEnvRegex.MatchString(name) {
+			return fmt.Errorf("invalid environment variable name in check.env: %q", name)
+		}
+	}
+
+	return nil
 }
 
 // ValidateRegex validates the version extraction regular expression
@@ -134,6 +1275,26 @@ func (td *ToolDefinition) ValidateRegex() error {
 		return errors.New("VersionRegex must contain named capture group")
 	}
 
+	if key := td.VersionRegexKey(); key != "" {
+		if err := ValidateRegexKey(key); err != nil {
+			return err
+		}
+		if regexp.MustCompile(td.Check.Regex).SubexpIndex(key) < 0 {
+			return fmt.Errorf("regex has no capture group named %q (the regex key from check.regex_key or defaults.regex_key)", key)
+		}
+	}
+
+	return nil
+}
+
+// regexKeyRegex matches the names Go allows for capture groups
+var regexKeyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateRegexKey checks that a regex key can name a capture group
+func ValidateRegexKey(key string) error {
+	if !regexKeyRegex.MatchString(key) {
+		return fmt.Errorf("invalid regex key %q (must be a capture group name: letters, digits, and underscores)", key)
+	}
 	return nil
 }
 
@@ -148,6 +1309,11 @@ func (td *ToolDefinition) ValidateLinks() error {
 			return fmt.Errorf("link %s cannot be empty", linkType)
 		}
 
+		// Logical links (e.g. "wiki:onboarding/go") are resolved at render time
+		if links.IsLogical(linkURL) {
+			continue
+		}
+
 		// Validate URL format
 		if !isValidURL(linkURL) {
 			return fmt.Errorf("invalid URL for %s: %s", linkType, linkURL)
@@ -165,6 +1331,44 @@ func (td *ToolDefinition) validateTimeout() error {
 	return nil
 }
 
+// validateHooks checks that transition hook commands are well-formed
+func (td *ToolDefinition) validateHooks() error {
+	if len(td.OnFail) > 0 && td.OnFail[0] == "" {
+		return errors.New("on_fail command cannot be empty")
+	}
+	if len(td.OnRecover) > 0 && td.OnRecover[0] == "" {
+		return errors.New("on_recover command cannot be empty")
+	}
+	return nil
+}
+
+// commandTemplateVars lists the variables available to check command templates
+var commandTemplateVars = map[string]string{
+	"os":          "",
+	"arch":        "",
+	"brew_prefix": "",
+	"home":        "",
+}
+
+// validateCommandTemplates checks that templated command arguments parse and use known variables
+func (td *ToolDefinition) validateCommandTemplates() error {
+	for _, arg := range td.Check.Command {
+		if !strings.Contains(arg, "{{") {
+			continue
+		}
+
+		tmpl, err := template.New("cmd").Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return fmt.Errorf("invalid command template %q: %v", arg, err)
+		}
+
+		if err := tmpl.Execute(io.Discard, commandTemplateVars); err != nil {
+			return fmt.Errorf("invalid command template %q (available: .os, .arch, .brew_prefix, .home): %v", arg, err)
+		}
+	}
+	return nil
+}
+
 // isValidURL performs basic URL validation
 func isValidURL(urlStr string) bool {
 	if urlStr == "" {
@@ -185,8 +1389,16 @@ func isValidURL(urlStr string) bool {
 func (td *ToolDefinition) ApplyDefaults(defaults ManifestDefaults) {
 	if td.TimeoutSeconds == 0 && defaults.TimeoutSeconds > 0 {
 		td.TimeoutSeconds = defaults.TimeoutSeconds
+		td.timeoutFromDefaults = true
+	}
+
+	// Tools using another version scheme are not affected by the global strict mode
+	if defaults.StrictSemver && (td.Check.VersionScheme == "" || td.Check.VersionScheme == semver.DefaultScheme) {
+		td.Check.StrictSemver = true
 	}
 
-	// If the regex uses the default capture group name, no change needed
-	// This is handled during parsing where the regex key can be used
-}
\ No newline at end of file
+	// The regex key stays out of check.regex_key, which is a schema v2 field
+	if td.defaultRegexKey == "" {
+		td.defaultRegexKey = defaults.RegexKey
+	}
+}
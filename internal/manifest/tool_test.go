@@ -1,7 +1,12 @@
 package manifest
 
 import (
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestToolDefinitionValidation(t *testing.T) {
@@ -123,6 +128,74 @@ func TestToolDefinitionValidation(t *testing.T) {
 			expectError: true,
 			errorMsg:    "TimeoutSeconds must be positive",
 		},
+		{
+			name: "templated check command",
+			tool: ToolDefinition{
+				ID:              "tool",
+				Name:            "Tool",
+				Rationale:       "Installed via Homebrew",
+				RequiredVersion: ">=1.0",
+				Check: CheckConfig{
+					Command: []string{"{{ .brew_prefix }}/bin/tool", "--version"},
+					Regex:   "(?P<ver>\\d+\\.\\d+)",
+				},
+				Links: map[string]string{
+					"homepage": "https://example.com",
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "unknown template variable",
+			tool: ToolDefinition{
+				ID:              "tool",
+				Name:            "Tool",
+				Rationale:       "Installed via Homebrew",
+				RequiredVersion: ">=1.0",
+				Check: CheckConfig{
+					Command: []string{"{{ .prefix }}/bin/tool", "--version"},
+					Regex:   "(?P<ver>\\d+\\.\\d+)",
+				},
+				Links: map[string]string{
+					"homepage": "https://example.com",
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "informational tool without requirement",
+			tool: ToolDefinition{
+				ID:            "xcode",
+				Name:          "Xcode",
+				Rationale:     "Reported for context",
+				Informational: true,
+				Check: CheckConfig{
+					Command: []string{"xcodebuild", "-version"},
+					Regex:   "Xcode (?P<ver>\\d+\\.\\d+)",
+				},
+				Links: map[string]string{
+					"homepage": "https://developer.apple.com/xcode/",
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "non-informational tool without requirement",
+			tool: ToolDefinition{
+				ID:        "xcode",
+				Name:      "Xcode",
+				Rationale: "Required for iOS builds",
+				Check: CheckConfig{
+					Command: []string{"xcodebuild", "-version"},
+					Regex:   "Xcode (?P<ver>\\d+\\.\\d+)",
+				},
+				Links: map[string]string{
+					"homepage": "https://developer.apple.com/xcode/",
+				},
+			},
+			expectError: true,
+			errorMsg:    "required fields cannot be empty",
+		},
 	}
 
 	for _, tt := range tests {
@@ -155,9 +228,13 @@ func TestToolDefinitionSemverConstraintValidation(t *testing.T) {
 		{"valid constraint - range", ">=1.22 <1.25", false},
 		{"valid constraint - tilde", "~1.22.0", false},
 		{"valid constraint - caret", "^1.22.0", false},
+		{"valid constraint - pessimistic", "~>1.22", false},
+		{"valid constraint - spaced pessimistic", "~> 1.22", false},
+		{"valid constraint - combined clauses", ">=1.20 <1.25 ~1.22", false},
 		{"invalid constraint - empty", "", true},
 		{"invalid constraint - malformed", ">=1.22.x", true},
 		{"invalid constraint - invalid operator", "=>1.22", true},
+		{"invalid constraint - malformed clause", ">=1.22 <1.x", true},
 	}
 
 	for _, tt := range tests {
@@ -284,6 +361,14 @@ func TestToolDefinitionLinkValidation(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "valid logical links",
+			links: map[string]string{
+				"docs":     "wiki:onboarding/go",
+				"homepage": "https://example.com",
+			},
+			expectError: false,
+		},
 		{
 			name: "invalid links - not URLs",
 			links: map[string]string{
@@ -335,4 +420,1079 @@ func TestToolDefinitionLinkValidation(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestToolDefinitionTieredRequire(t *testing.T) {
+	tests := []struct {
+		name                string
+		yaml                string
+		expectError         bool
+		expectedRequired    string
+		expectedRecommended string
+	}{
+		{
+			name:             "scalar require",
+			yaml:             "id: go\nrequire: \">=1.22\"\n",
+			expectedRequired: ">=1.22",
+		},
+		{
+			name:                "tiered require",
+			yaml:                "id: go\nrequire:\n  minimum: \">=1.22\"\n  recommended: \">=1.23\"\n",
+			expectedRequired:    ">=1.22",
+			expectedRecommended: ">=1.23",
+		},
+		{
+			name:        "unknown tier",
+			yaml:        "id: go\nrequire:\n  minimum: \">=1.22\"\n  preferred: \">=1.23\"\n",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tool ToolDefinition
+			err := yaml.Unmarshal([]byte(tt.yaml), &tool)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tool.RequiredVersion != tt.expectedRequired {
+				t.Errorf("Expected required %q, got %q", tt.expectedRequired, tool.RequiredVersion)
+			}
+			if tool.RecommendedVersion != tt.expectedRecommended {
+				t.Errorf("Expected recommended %q, got %q", tt.expectedRecommended, tool.RecommendedVersion)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionPluginValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		check       CheckConfig
+		expectError bool
+	}{
+		{
+			name:  "plugin only",
+			check: CheckConfig{Plugin: "./scripts/check-vpn.sh"},
+		},
+		{
+			name:        "plugin with cmd",
+			check:       CheckConfig{Plugin: "./scripts/check-vpn.sh", Command: []string{"vpn", "status"}},
+			expectError: true,
+		},
+		{
+			name:        "neither plugin nor cmd",
+			check:       CheckConfig{},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ToolDefinition{
+				ID:        "vpn",
+				Name:      "VPN",
+				Rationale: "Internal services require the VPN",
+				Check:     tt.check,
+				Links:     map[string]string{"docs": "https://example.com/vpn"},
+			}
+
+			err := tool.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionSubCheckValidation(t *testing.T) {
+	validCheck := CheckConfig{
+		Command: []string{"docker", "compose", "version"},
+		Regex:   "(?P<ver>\\d+\\.\\d+\\.\\d+)",
+	}
+
+	tests := []struct {
+		name        string
+		checks      []SubCheck
+		expectError bool
+	}{
+		{
+			name:   "valid sub-checks",
+			checks: []SubCheck{{Name: "compose", RequiredVersion: ">=2.0", Check: validCheck}, {Name: "buildx", Check: validCheck}},
+		},
+		{
+			name:        "duplicate name",
+			checks:      []SubCheck{{Name: "compose", Check: validCheck}, {Name: "compose", Check: validCheck}},
+			expectError: true,
+		},
+		{
+			name:        "invalid name",
+			checks:      []SubCheck{{Name: "Compose", Check: validCheck}},
+			expectError: true,
+		},
+		{
+			name:        "invalid constraint",
+			checks:      []SubCheck{{Name: "compose", RequiredVersion: "latest", Check: validCheck}},
+			expectError: true,
+		},
+		{
+			name:        "missing regex",
+			checks:      []SubCheck{{Name: "compose", Check: CheckConfig{Command: []string{"docker"}}}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ToolDefinition{
+				ID:              "docker",
+				Name:            "Docker",
+				Rationale:       "Container runtime",
+				RequiredVersion: ">=24.0",
+				Check:           validCheck,
+				Links:           map[string]string{"docs": "https://docs.docker.com/"},
+				Checks:          tt.checks,
+			}
+
+			err := tool.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionExecutionValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		check       CheckConfig
+		expectError bool
+	}{
+		{
+			name:  "shell script",
+			check: CheckConfig{Command: []string{"terraform -chdir=infra version | head -1"}, Shell: true},
+		},
+		{
+			name:        "shell with multiple arguments",
+			check:       CheckConfig{Command: []string{"terraform", "version"}, Shell: true},
+			expectError: true,
+		},
+		{
+			name:  "workdir and env",
+			check: CheckConfig{Command: []string{"terraform", "version"}, Workdir: "infra", Env: map[string]string{"TF_IN_AUTOMATION": "1"}},
+		},
+		{
+			name:        "blank workdir",
+			check:       CheckConfig{Command: []string{"terraform", "version"}, Workdir: "  "},
+			expectError: true,
+		},
+		{
+			name:        "invalid env name",
+			check:       CheckConfig{Command: []string{"terraform", "version"}, Env: map[string]string{"TF-LOG": "1"}},
+			expectError: true,
+		},
+		{
+			name:  "fallbacks",
+			check: CheckConfig{Command: []string{"terraform", "version"}, Fallbacks: [][]string{{"tofu", "version"}}},
+		},
+		{
+			name:        "empty fallback",
+			check:       CheckConfig{Command: []string{"terraform", "version"}, Fallbacks: [][]string{{}}},
+			expectError: true,
+		},
+		{
+			name:  "shell fallback script",
+			check: CheckConfig{Command: []string{"terraform version | head -1"}, Shell: true, Fallbacks: [][]string{{"tofu version | head -1"}}},
+		},
+		{
+			name:        "shell fallback with multiple arguments",
+			check:       CheckConfig{Command: []string{"terraform version | head -1"}, Shell: true, Fallbacks: [][]string{{"tofu", "version"}}},
+			expectError: true,
+		},
+		{
+			name:        "fallbacks without cmd",
+			check:       CheckConfig{Fallbacks: [][]string{{"tofu", "version"}}},
+			expectError: true,
+		},
+		{
+			name:  "expected exit codes",
+			check: CheckConfig{Command: []string{"java", "-version"}, ExpectExitCodes: []int{0, 1}},
+		},
+		{
+			name:        "exit code out of range",
+			check:       CheckConfig{Command: []string{"java", "-version"}, ExpectExitCodes: []int{256}},
+			expectError: true,
+		},
+		{
+			name:        "expected exit codes of a service",
+			check:       CheckConfig{Type: CheckTypeService, Probe: "docker", ExpectExitCodes: []int{1}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check.Regex = "(?P<ver>\\d+\\.\\d+\\.\\d+)"
+			tool := ToolDefinition{
+				ID:              "terraform",
+				Name:            "Terraform",
+				Rationale:       "Infrastructure as code",
+				RequiredVersion: ">=1.5",
+				Check:           tt.check,
+				Links:           map[string]string{"homepage": "https://www.terraform.io/"},
+			}
+
+			err := tool.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionServiceValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		require     string
+		check       CheckConfig
+		expectError bool
+	}{
+		{
+			name:  "built-in probe",
+			check: CheckConfig{Type: CheckTypeService, Probe: "docker"},
+		},
+		{
+			name:    "probe with version requirement",
+			require: ">=24.0",
+			check:   CheckConfig{Type: CheckTypeService, Probe: "docker", Regex: `(?P<ver>\d+\.\d+(\.\d+)?)`},
+		},
+		{
+			name:  "generic health command",
+			check: CheckConfig{Type: CheckTypeService, Command: []string{"pg_isready"}},
+		},
+		{
+			name:        "unknown probe",
+			check:       CheckConfig{Type: CheckTypeService, Probe: "mysql"},
+			expectError: true,
+		},
+		{
+			name:        "probe and cmd",
+			check:       CheckConfig{Type: CheckTypeService, Probe: "docker", Command: []string{"docker", "info"}},
+			expectError: true,
+		},
+		{
+			name:        "neither probe nor cmd",
+			check:       CheckConfig{Type: CheckTypeService},
+			expectError: true,
+		},
+		{
+			name:        "require without regex",
+			require:     ">=24.0",
+			check:       CheckConfig{Type: CheckTypeService, Probe: "docker"},
+			expectError: true,
+		},
+		{
+			name:        "probe without service type",
+			check:       CheckConfig{Probe: "docker", Command: []string{"docker", "--version"}, Regex: `(?P<ver>\d+\.\d+)`},
+			expectError: true,
+		},
+		{
+			name:        "unknown check type",
+			check:       CheckConfig{Type: "daemon", Command: []string{"docker", "info"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ToolDefinition{
+				ID:              "docker-daemon",
+				Name:            "Docker daemon",
+				Rationale:       "Integration tests run in containers",
+				RequiredVersion: tt.require,
+				Check:           tt.check,
+				Links:           map[string]string{"docs": "https://docs.docker.com/"},
+			}
+
+			err := tool.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionOSValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		require     string
+		check       CheckConfig
+		expectError bool
+	}{
+		{name: "macOS", require: ">=13", check: CheckConfig{Type: CheckTypeOS, OS: "macos"}},
+		{name: "kernel", require: ">=5.15", check: CheckConfig{Type: CheckTypeOS, OS: "kernel"}},
+		{name: "distribution", require: ">=22.04", check: CheckConfig{Type: CheckTypeOS, OS: "ubuntu"}},
+		{name: "missing os", require: ">=13", check: CheckConfig{Type: CheckTypeOS}, expectError: true},
+		{name: "invalid os", require: ">=13", check: CheckConfig{Type: CheckTypeOS, OS: "Mac OS"}, expectError: true},
+		{name: "missing require", check: CheckConfig{Type: CheckTypeOS, OS: "macos"}, expectError: true},
+		{
+			name:        "os with cmd",
+			require:     ">=13",
+			check:       CheckConfig{Type: CheckTypeOS, OS: "macos", Command: []string{"sw_vers"}, Regex: `(?P<ver>\d+)`},
+			expectError: true,
+		},
+		{
+			name:        "os without os type",
+			require:     ">=13",
+			check:       CheckConfig{OS: "macos", Command: []string{"sw_vers"}, Regex: `(?P<ver>\d+)`},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ToolDefinition{
+				ID:              "os",
+				Name:            "Operating system",
+				Rationale:       "The toolchain needs a recent SDK",
+				RequiredVersion: tt.require,
+				Check:           tt.check,
+				Links:           map[string]string{"docs": "https://support.apple.com/"},
+			}
+
+			err := tool.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionGitConfigValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		require     string
+		check       CheckConfig
+		expectError bool
+	}{
+		{
+			name:  "email and signing",
+			check: CheckConfig{Type: CheckTypeGitConfig, GitConfig: map[string]string{"user.email": `@example\.com$`, "commit.gpgsign": "^true$"}},
+		},
+		{
+			name:  "subsection key",
+			check: CheckConfig{Type: CheckTypeGitConfig, GitConfig: map[string]string{"url.git@github.com:.insteadOf": "^https://github.com/$"}},
+		},
+		{
+			name:        "no keys",
+			check:       CheckConfig{Type: CheckTypeGitConfig},
+			expectError: true,
+		},
+		{
+			name:        "key without section",
+			check:       CheckConfig{Type: CheckTypeGitConfig, GitConfig: map[string]string{"email": ".+"}},
+			expectError: true,
+		},
+		{
+			name:        "malformed regex",
+			check:       CheckConfig{Type: CheckTypeGitConfig, GitConfig: map[string]string{"user.email": "(unclosed"}},
+			expectError: true,
+		},
+		{
+			name:        "with require",
+			require:     ">=2.40",
+			check:       CheckConfig{Type: CheckTypeGitConfig, GitConfig: map[string]string{"user.email": ".+"}},
+			expectError: true,
+		},
+		{
+			name:        "with cmd",
+			check:       CheckConfig{Type: CheckTypeGitConfig, GitConfig: map[string]string{"user.email": ".+"}, Command: []string{"git", "config", "user.email"}},
+			expectError: true,
+		},
+		{
+			name:        "git_config without type",
+			check:       CheckConfig{GitConfig: map[string]string{"user.email": ".+"}, Command: []string{"git", "--version"}, Regex: `(?P<ver>\d+\.\d+)`},
+			require:     ">=2.40",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ToolDefinition{
+				ID:              "git-identity",
+				Name:            "Git identity",
+				Rationale:       "Commits must use the corporate email and be signed",
+				RequiredVersion: tt.require,
+				Check:           tt.check,
+				Links:           map[string]string{"docs": "https://git-scm.com/docs/git-config"},
+			}
+
+			err := tool.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionEnvValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		require     string
+		check       CheckConfig
+		expectError bool
+	}{
+		{
+			name:  "set",
+			check: CheckConfig{Type: CheckTypeEnv, Var: "AWS_PROFILE", Sensitive: true},
+		},
+		{
+			name:  "existing dir",
+			check: CheckConfig{Type: CheckTypeEnv, Var: "JAVA_HOME", Exists: EnvExistsDir},
+		},
+		{
+			name:  "value regex",
+			check: CheckConfig{Type: CheckTypeEnv, Var: "GOPRIVATE", Regex: `(^|,)github\.com/acme(/|,|$)`},
+		},
+		{
+			name:        "no variable",
+			check:       CheckConfig{Type: CheckTypeEnv},
+			expectError: true,
+		},
+		{
+			name:        "invalid variable",
+			check:       CheckConfig{Type: CheckTypeEnv, Var: "JAVA-HOME"},
+			expectError: true,
+		},
+		{
+			name:        "unknown exists",
+			check:       CheckConfig{Type: CheckTypeEnv, Var: "JAVA_HOME", Exists: "symlink"},
+			expectError: true,
+		},
+		{
+			name:        "malformed regex",
+			check:       CheckConfig{Type: CheckTypeEnv, Var: "GOPRIVATE", Regex: "(unclosed"},
+			expectError: true,
+		},
+		{
+			name:        "with require",
+			require:     ">=1.0",
+			check:       CheckConfig{Type: CheckTypeEnv, Var: "JAVA_HOME"},
+			expectError: true,
+		},
+		{
+			name:        "with cmd",
+			check:       CheckConfig{Type: CheckTypeEnv, Var: "JAVA_HOME", Command: []string{"printenv", "JAVA_HOME"}},
+			expectError: true,
+		},
+		{
+			name:        "var without type",
+			check:       CheckConfig{Var: "JAVA_HOME", Command: []string{"java", "-version"}, Regex: `(?P<ver>\d+)`},
+			require:     ">=17",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ToolDefinition{
+				ID:              "env",
+				Name:            "Environment",
+				Rationale:       "Builds read the environment",
+				RequiredVersion: tt.require,
+				Check:           tt.check,
+				Links:           map[string]string{"docs": "https://example.com/setup"},
+			}
+
+			err := tool.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionExpectSHA256(t *testing.T) {
+	const sum = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	command := CheckConfig{Command: []string{"terraform", "version"}, Regex: `v(?P<ver>\d+\.\d+\.\d+)`}
+
+	tests := []struct {
+		name        string
+		sums        map[string]string
+		check       CheckConfig
+		variants    []Variant
+		expectError bool
+	}{
+		{
+			name:  "by OS and arch",
+			sums:  map[string]string{"linux": sum, "darwin/arm64": strings.ToUpper(sum)},
+			check: command,
+		},
+		{
+			name:        "invalid platform",
+			sums:        map[string]string{"windows": sum},
+			check:       command,
+			expectError: true,
+		},
+		{
+			name:        "not a sha256",
+			sums:        map[string]string{"linux": "d41d8cd98f00b204e9800998ecf8427e"},
+			check:       command,
+			expectError: true,
+		},
+		{
+			name:        "env check",
+			sums:        map[string]string{"linux": sum},
+			check:       CheckConfig{Type: CheckTypeEnv, Var: "TF_HOME"},
+			expectError: true,
+		},
+		{
+			name:        "with variants",
+			sums:        map[string]string{"linux": sum},
+			check:       command,
+			variants:    []Variant{{Name: "1.5", RequiredVersion: "~1.5"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ToolDefinition{
+				ID:           "terraform",
+				Name:         "Terraform",
+				Rationale:    "Provisions infrastructure",
+				Check:        tt.check,
+				Links:        map[string]string{"docs": "https://example.com/terraform"},
+				ExpectSHA256: tt.sums,
+				Variants:     tt.variants,
+			}
+			if tt.check.Type != CheckTypeEnv && len(tt.variants) == 0 {
+				tool.RequiredVersion = ">=1.5"
+			}
+
+			err := tool.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+
+	tool := ToolDefinition{ExpectSHA256: map[string]string{"linux": "AA", "linux/arm64": "BB"}}
+	for _, tt := range []struct{ goos, goarch, want string }{
+		{"linux", "arm64", "bb"},
+		{"linux", "amd64", "aa"},
+		{"darwin", "arm64", ""},
+	} {
+		if got := tool.ExpectedSHA256(tt.goos, tt.goarch); got != tt.want {
+			t.Errorf("ExpectedSHA256(%s, %s) = %q, want %q", tt.goos, tt.goarch, got, tt.want)
+		}
+	}
+}
+
+func TestToolDefinitionPreferredInstallHint(t *testing.T) {
+	tool := ToolDefinition{
+		Install: map[string]string{
+			"brew": "brew install jq",
+			"apt":  "sudo apt-get install jq",
+			"nix":  "nix profile install nixpkgs#jq",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		managers []string
+		want     string
+	}{
+		{"first match wins", []string{"apt", "nix"}, "sudo apt-get install jq"},
+		{"skips undefined managers", []string{"scoop", "mise", "nix"}, "nix profile install nixpkgs#jq"},
+		{"no match", []string{"scoop"}, ""},
+		{"no managers", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tool.PreferredInstallHint(tt.managers); got != tt.want {
+				t.Errorf("PreferredInstallHint(%v) = %q, want %q", tt.managers, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionStrictSemverValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		scheme      string
+		expectError bool
+	}{
+		{name: "default scheme", scheme: ""},
+		{name: "semver scheme", scheme: "semver"},
+		{name: "calver scheme", scheme: "calver", expectError: true},
+		{name: "loose scheme", scheme: "loose", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ToolDefinition{
+				ID:              "tool",
+				Name:            "Tool",
+				Rationale:       "Testing",
+				RequiredVersion: ">=1",
+				Check: CheckConfig{
+					Command:       []string{"tool", "--version"},
+					Regex:         "(?P<ver>\\d+\\.\\d+\\.\\d+)",
+					VersionScheme: tt.scheme,
+					StrictSemver:  true,
+				},
+				Links: map[string]string{"homepage": "https://example.com/"},
+			}
+
+			err := tool.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionRegexKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		regex       string
+		checkKey    string
+		defaultKey  string
+		wantKey     string
+		expectError bool
+	}{
+		{name: "no key", regex: "(?P<ver>\\d+\\.\\d+)", wantKey: ""},
+		{name: "default key", regex: "(?P<version>\\d+\\.\\d+)", defaultKey: "version", wantKey: "version"},
+		{name: "check key overrides default", regex: "(?P<release>\\d+\\.\\d+)", checkKey: "release", defaultKey: "version", wantKey: "release"},
+		{name: "default key missing from regex", regex: "(?P<ver>\\d+\\.\\d+)", defaultKey: "version", wantKey: "version", expectError: true},
+		{name: "check key missing from regex", regex: "(?P<ver>\\d+\\.\\d+)", checkKey: "release", wantKey: "release", expectError: true},
+		{name: "invalid key", regex: "(?P<ver>\\d+\\.\\d+)", checkKey: "my-key", wantKey: "my-key", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ToolDefinition{
+				ID:              "tool",
+				Name:            "Tool",
+				Rationale:       "Testing",
+				RequiredVersion: ">=1",
+				Check: CheckConfig{
+					Command:  []string{"tool", "--version"},
+					Regex:    tt.regex,
+					RegexKey: tt.checkKey,
+				},
+				Links: map[string]string{"homepage": "https://example.com/"},
+			}
+			tool.ApplyDefaults(ManifestDefaults{RegexKey: tt.defaultKey})
+
+			if got := tool.VersionRegexKey(); got != tt.wantKey {
+				t.Errorf("VersionRegexKey() = %q, want %q", got, tt.wantKey)
+			}
+
+			err := tool.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionRegexKeyWithoutVersionRegex(t *testing.T) {
+	tests := []struct {
+		name  string
+		check CheckConfig
+	}{
+		{name: "plugin", check: CheckConfig{Plugin: "./check.sh"}},
+		{name: "service without regex", check: CheckConfig{Type: CheckTypeService, Command: []string{"pg_isready"}}},
+		{name: "env", check: CheckConfig{Type: CheckTypeEnv, Var: "HOME", Regex: "^/(?P<ver>.*)"}},
+		{name: "git config", check: CheckConfig{Type: CheckTypeGitConfig, GitConfig: map[string]string{"user.name": ""}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ToolDefinition{
+				ID:        "tool",
+				Name:      "Tool",
+				Rationale: "Testing",
+				Check:     tt.check,
+				Links:     map[string]string{"homepage": "https://example.com/"},
+			}
+			if err := tool.Validate(); err != nil {
+				t.Fatalf("Unexpected error without regex_key: %v", err)
+			}
+
+			tool.Check.RegexKey = "ver"
+			err := tool.Validate()
+			if err == nil || !strings.Contains(err.Error(), "check.regex_key requires") {
+				t.Errorf("Expected regex_key to be rejected, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionScope(t *testing.T) {
+	tests := []struct {
+		name        string
+		scope       string
+		expectError bool
+	}{
+		{name: "unscoped", scope: ""},
+		{name: "machine", scope: ScopeMachine},
+		{name: "project", scope: ScopeProject},
+		{name: "unknown scope", scope: "team", expectError: true},
+		{name: "scopes are lowercase", scope: "Machine", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ToolDefinition{
+				ID:              "docker",
+				Name:            "Docker",
+				Rationale:       "Testing",
+				RequiredVersion: ">=24",
+				Scope:           tt.scope,
+				Check:           CheckConfig{Command: []string{"docker", "--version"}, Regex: `(?P<ver>\d+\.\d+)`},
+				Links:           map[string]string{"homepage": "https://docker.com/"},
+			}
+
+			err := tool.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if got := slices.Contains(tool.v2FieldsInUse(), "scope"); got != (tt.scope != "") {
+				t.Errorf("v2FieldsInUse() lists scope: %v, want %v", got, tt.scope != "")
+			}
+		})
+	}
+}
+
+func TestManifestRegexKeyVersions(t *testing.T) {
+	loader := NewLoader()
+	tool := "tools:\n  - id: go\n    name: Go\n    rationale: r\n    require: \">=1.20\"\n    links: {homepage: \"https://go.dev/\"}\n    check:\n      cmd: [go, version]\n      regex: \"go(?P<version>\\\\d+\\\\.\\\\d+)\"\n"
+
+	// defaults.regex_key is a v1 field, check.regex_key a v2 one
+	v1Defaults := "meta:\n  version: 1\n  name: test\ndefaults:\n  regex_key: version\n" + tool
+	manifest, err := loader.parseYAML([]byte(v1Defaults))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := manifest.Tools[0].VersionRegexKey(); got != "version" {
+		t.Errorf("VersionRegexKey() = %q, want version", got)
+	}
+
+	v1Check := "meta:\n  version: 1\n  name: test\n" + tool + "      regex_key: version\n"
+	if _, err := loader.parseYAML([]byte(v1Check)); err == nil {
+		t.Error("Expected check.regex_key to be rejected in a v1 manifest")
+	}
+
+	v2Check := "meta:\n  version: 2\n  name: test\n" + tool + "      regex_key: version\n"
+	if _, err := loader.parseYAML([]byte(v2Check)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestToolDefinitionRequireFrom(t *testing.T) {
+	tests := []struct {
+		name        string
+		yaml        string
+		want        RequireFrom
+		expectError bool
+	}{
+		{name: "file shorthand", yaml: "require_from: .nvmrc\n", want: RequireFrom{File: ".nvmrc"}},
+		{name: "map", yaml: "require_from: {file: .tool-versions, key: nodejs, match: exact}\n", want: RequireFrom{File: ".tool-versions", Key: "nodejs", Match: MatchExact}},
+		{name: "with require fallback", yaml: "require: \">=18\"\nrequire_from: .nvmrc\n", want: RequireFrom{File: ".nvmrc"}},
+		{name: "missing file", yaml: "require_from: {key: nodejs}\n", want: RequireFrom{Key: "nodejs"}, expectError: true},
+		{name: "unknown match", yaml: "require_from: {file: .nvmrc, match: newest}\n", want: RequireFrom{File: ".nvmrc", Match: "newest"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "id: node\nname: Node.js\nrationale: Testing\nlinks: {homepage: \"https://nodejs.org/\"}\ncheck:\n  cmd: [node, --version]\n  regex: \"v(?P<ver>\\\\d+\\\\.\\\\d+\\\\.\\\\d+)\"\n" + tt.yaml
+			var tool ToolDefinition
+			if err := yaml.Unmarshal([]byte(data), &tool); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if tool.RequireFrom == nil || *tool.RequireFrom != tt.want {
+				t.Errorf("RequireFrom = %+v, want %+v", tool.RequireFrom, tt.want)
+			}
+
+			err := tool.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionExcludeVersions(t *testing.T) {
+	tests := []struct {
+		name        string
+		yaml        string
+		want        []ExcludedVersion
+		wantErr     string
+	}{
+		{name: "versions shorthand", yaml: "exclude_versions: [\"1.21.5\", \">=2.0.0-rc <2.0.0\"]\n", want: []ExcludedVersion{{Versions: "1.21.5"}, {Versions: ">=2.0.0-rc <2.0.0"}}},
+		{name: "with reason", yaml: "exclude_versions: [{versions: \"1.21.5\", reason: breaks go.sum}]\n", want: []ExcludedVersion{{Versions: "1.21.5", Reason: "breaks go.sum"}}},
+		{name: "invalid constraint", yaml: "exclude_versions: [\">=abc\"]\n", want: []ExcludedVersion{{Versions: ">=abc"}}, wantErr: `invalid exclude_versions entry ">=abc"`},
+		{name: "missing versions", yaml: "exclude_versions: [{reason: broken}]\n", want: []ExcludedVersion{{Reason: "broken"}}, wantErr: "exclude_versions entries need versions"},
+		{name: "git-config check", yaml: "check: {type: git-config, git_config: {user.name: \".+\"}}\nexclude_versions: [\"1.0.0\"]\n", want: []ExcludedVersion{{Versions: "1.0.0"}}, wantErr: "exclude_versions cannot be combined with check.type: git-config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "id: go\nname: Go\nrationale: Testing\nlinks: {homepage: \"https://go.dev/\"}\n" + tt.yaml
+			if !strings.Contains(tt.yaml, "check:") {
+				data += "require: \">=1.21\"\ncheck:\n  cmd: [go, version]\n  regex: \"go(?P<ver>\\\\d+\\\\.\\\\d+\\\\.\\\\d+)\"\n"
+			}
+			var tool ToolDefinition
+			if err := yaml.Unmarshal([]byte(data), &tool); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !slices.Equal(tool.ExcludeVersions, tt.want) {
+				t.Errorf("ExcludeVersions = %+v, want %+v", tool.ExcludeVersions, tt.want)
+			}
+
+			err := tool.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionVariants(t *testing.T) {
+	base := "id: java\nname: Java\nrationale: Testing\nlinks: {homepage: \"https://openjdk.org/\"}\ncheck:\n  cmd: [java, -version]\n  regex: 'version \"(?P<ver>\\d+(\\.\\d+)*)'\n  expect_exit_codes: [0]\n"
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{name: "variants", yaml: "variants:\n  - {name: \"11\", require: \"~11\", check: {cmd: [/usr/lib/jvm/java-11/bin/java, -version]}}\n  - {name: \"17\", require: \"~17\", check: {env: {JAVA_HOME: /usr/lib/jvm/java-17}}}\n"},
+		{name: "requirement on the tool", yaml: "require: \">=11\"\nvariants:\n  - {name: \"11\", require: \"~11\"}\n", wantErr: "a tool with variants cannot have require"},
+		{name: "variant without requirement", yaml: "variants:\n  - {name: \"11\"}\n", wantErr: "variant 11: required fields cannot be empty"},
+		{name: "invalid variant constraint", yaml: "variants:\n  - {name: \"11\", require: \"about 11\"}\n", wantErr: "variant 11: invalid version constraint format"},
+		{name: "duplicate names", yaml: "variants:\n  - {name: \"11\", require: \"~11\"}\n  - {name: \"11\", require: \"~11\"}\n", wantErr: "duplicate variant name: 11"},
+		{name: "invalid name", yaml: "variants:\n  - {name: \"Java 11\", require: \"~11\"}\n", wantErr: `invalid variant name "Java 11"`},
+		{name: "check type override", yaml: "variants:\n  - {name: \"11\", require: \"~11\", check: {plugin: ./java11}}\n", wantErr: "variant 11: check can only override"},
+		{name: "with sub-checks", yaml: "checks: [{name: javac, check: {cmd: [javac, -version], regex: '(\\d+)'}}]\nvariants:\n  - {name: \"11\", require: \"~11\"}\n", wantErr: "checks cannot be combined with variants"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tool ToolDefinition
+			if err := yaml.Unmarshal([]byte(base+tt.yaml), &tool); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			err := tool.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionVariantDefinition(t *testing.T) {
+	tool := ToolDefinition{
+		ID:   "java",
+		Name: "Java",
+		Check: CheckConfig{
+			Command:   []string{"java", "-version"},
+			Fallbacks: [][]string{{"jre", "-version"}},
+			Regex:     `version "(?P<ver>\d+)`,
+			Env:       map[string]string{"LANG": "C"},
+		},
+	}
+
+	java11 := tool.VariantDefinition(Variant{Name: "11", RequiredVersion: "~11", Check: CheckConfig{Command: []string{"java11", "-version"}}})
+	if java11.Name != "Java 11" || java11.RequiredVersion != "~11" {
+		t.Errorf("Expected Java 11 requiring ~11, got %q requiring %q", java11.Name, java11.RequiredVersion)
+	}
+	if !slices.Equal(java11.Check.Command, []string{"java11", "-version"}) || len(java11.Check.Fallbacks) != 0 {
+		t.Errorf("Expected the variant's command without the tool's fallbacks, got %q and %q", java11.Check.Command, java11.Check.Fallbacks)
+	}
+	if java11.Check.Regex != tool.Check.Regex {
+		t.Errorf("Expected the tool's regex, got %q", java11.Check.Regex)
+	}
+
+	java17 := tool.VariantDefinition(Variant{Name: "17", RequiredVersion: "~17", Check: CheckConfig{Env: map[string]string{"JAVA_HOME": "/opt/java17"}}})
+	if !slices.Equal(java17.Check.Command, tool.Check.Command) {
+		t.Errorf("Expected the tool's command, got %q", java17.Check.Command)
+	}
+	if java17.Check.Env["LANG"] != "C" || java17.Check.Env["JAVA_HOME"] != "/opt/java17" {
+		t.Errorf("Expected merged environment variables, got %v", java17.Check.Env)
+	}
+	if _, ok := tool.Check.Env["JAVA_HOME"]; ok {
+		t.Error("Expected the tool's environment to be left alone")
+	}
+}
+
+func TestRequireFromConstraint(t *testing.T) {
+	tests := []struct {
+		match string
+		want  string
+	}{
+		{"", ">=20.11.0"},
+		{MatchMinimum, ">=20.11.0"},
+		{MatchExact, "=20.11.0"},
+		{MatchCompatible, "~20.11.0"},
+	}
+
+	for _, tt := range tests {
+		rf := RequireFrom{File: ".nvmrc", Match: tt.match}
+		if got := rf.Constraint("20.11.0"); got != tt.want {
+			t.Errorf("Constraint() with match %q = %q, want %q", tt.match, got, tt.want)
+		}
+	}
+}
+
+func TestToolDefinitionUpstreamValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		upstream    Upstream
+		wantRepo    string
+		expectError bool
+	}{
+		{name: "github repository", upstream: Upstream{GitHub: "cli/cli"}, wantRepo: "cli/cli"},
+		{name: "github releases URL", upstream: Upstream{GitHub: "https://github.com/jqlang/jq/releases/"}, wantRepo: "jqlang/jq"},
+		{name: "github clone URL", upstream: Upstream{GitHub: "https://github.com/cli/cli.git"}, wantRepo: "cli/cli"},
+		{name: "homebrew and endoflife", upstream: Upstream{Homebrew: "python@3.12", EndOfLife: "python"}},
+		{name: "no source", upstream: Upstream{}, expectError: true},
+		{name: "github without owner", upstream: Upstream{GitHub: "cli"}, expectError: true},
+		{name: "github on another host", upstream: Upstream{GitHub: "https://gitlab.com/cli/cli"}, expectError: true},
+		{name: "invalid formula", upstream: Upstream{Homebrew: "Not A Formula"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ToolDefinition{
+				ID:              "tool",
+				Name:            "Tool",
+				Rationale:       "Testing",
+				RequiredVersion: ">=1.0",
+				Check:           CheckConfig{Command: []string{"tool", "--version"}, Regex: "(?P<ver>\\d+\\.\\d+\\.\\d+)"},
+				Links:           map[string]string{"homepage": "https://example.com/"},
+				Upstream:        &tt.upstream,
+			}
+
+			err := tool.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if got := tt.upstream.GitHubRepository(); got != tt.wantRepo {
+				t.Errorf("Expected repository %q, got %q", tt.wantRepo, got)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionSunset(t *testing.T) {
+	now := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		deprecated     bool
+		sunset         string
+		expectError    bool
+		wantDeprecated bool
+	}{
+		{name: "active tool"},
+		{name: "deprecated", deprecated: true, wantDeprecated: true},
+		{name: "future sunset", sunset: "2025-12-31"},
+		{name: "past sunset", sunset: "2025-01-01", wantDeprecated: true},
+		{name: "sunset today", sunset: "2025-06-01", wantDeprecated: true},
+		{name: "invalid sunset", sunset: "June 2025", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ToolDefinition{
+				ID:              "tool",
+				Name:            "Tool",
+				Rationale:       "Testing",
+				RequiredVersion: ">=1.0",
+				Check:           CheckConfig{Command: []string{"tool", "--version"}, Regex: "(?P<ver>\\d+\\.\\d+\\.\\d+)"},
+				Links:           map[string]string{"homepage": "https://example.com/"},
+				Deprecated:      tt.deprecated,
+				Sunset:          tt.sunset,
+			}
+
+			err := tool.Validate()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got := tool.IsDeprecatedAt(now); got != tt.wantDeprecated {
+				t.Errorf("Expected IsDeprecatedAt %v, got %v", tt.wantDeprecated, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package manifest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// TrustPolicy decides whether a remote manifest or bundle may be fetched; checks run the
+// commands a manifest names, so a source nobody reviewed should not be loaded silently
+type TrustPolicy func(url string) error
+
+// SetTrustPolicy makes the loader consult policy before fetching any URL, includes and
+// bundles included
+func (l *Loader) SetTrustPolicy(policy TrustPolicy) {
+	l.trust = policy
+}
+
+// TrustStore is the allowlist of remote sources the user has trusted, one per line
+// An entry ending in / trusts every URL under it
+type TrustStore struct {
+	path    string
+	sources []string
+}
+
+// LoadTrustStore reads the allowlist at path; a missing file is an empty allowlist
+func LoadTrustStore(path string) (*TrustStore, error) {
+	store := &TrustStore{path: path}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading trusted sources: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		store.sources = append(store.sources, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading trusted sources: %v", err)
+	}
+
+	return store, nil
+}
+
+// Trusted returns true if url, ignoring its query and fragment, is on the allowlist
+func (s *TrustStore) Trusted(url string) bool {
+	key := trustKey(url)
+	for _, source := range s.sources {
+		if key == source || (strings.HasSuffix(source, "/") && strings.HasPrefix(key, source)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add puts url on the allowlist and saves it
+// The query and fragment are dropped so tokens passed in them are not written to disk
+func (s *TrustStore) Add(url string) error {
+	key := trustKey(url)
+	if slices.Contains(s.sources, key) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("saving trusted sources: %v", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("saving trusted sources: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintln(f, key); err != nil {
+		return fmt.Errorf("saving trusted sources: %v", err)
+	}
+	s.sources = append(s.sources, key)
+	return nil
+}
+
+// Path returns the file the allowlist is kept in
+func (s *TrustStore) Path() string {
+	return s.path
+}
+
+// trustKey returns url without its query and fragment
+func trustKey(url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		return url[:i]
+	}
+	return url
+}
@@ -0,0 +1,94 @@
+package manifest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTrustStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goctor", "trusted_sources")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("# reviewed\nhttps://config.example.com/team/\n\nhttps://other.example.com/tools.yaml\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := LoadTrustStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add("https://new.example.com/tools.yaml?token=s3cret"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://config.example.com/team/tools.yaml", want: true},
+		{url: "https://config.example.com/team/nested/tools.yaml", want: true},
+		{url: "https://config.example.com/other/tools.yaml", want: false},
+		{url: "https://other.example.com/tools.yaml", want: true},
+		{url: "https://other.example.com/tools.yaml#frag", want: true},
+		{url: "https://other.example.com/tools.yaml.evil", want: false},
+		{url: "https://new.example.com/tools.yaml", want: true},
+	}
+
+	// Sources added are saved for the next run
+	reloaded, err := LoadTrustStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := reloaded.Trusted(tt.url); got != tt.want {
+				t.Errorf("Trusted(%q) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Errorf("Expected the query to be dropped, got %s", data)
+	}
+}
+
+func TestLoaderTrustPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(authTestManifest))
+	}))
+	defer server.Close()
+
+	store, err := LoadTrustStore(filepath.Join(t.TempDir(), "trusted_sources"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewLoader()
+	loader.SetTrustPolicy(func(url string) error {
+		if store.Trusted(url) {
+			return nil
+		}
+		return errors.New("untrusted")
+	})
+
+	if _, err := loader.LoadFromURL(server.URL); err == nil {
+		t.Fatal("Expected an untrusted source to be refused")
+	}
+
+	if err := store.Add(server.URL); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loader.LoadFromURL(server.URL); err != nil {
+		t.Errorf("Expected a trusted source to load, got: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// CodeClimateFormatter provides Code Climate issue output, the format of GitLab CI
+// Code Quality reports
+type CodeClimateFormatter struct{}
+
+// NewCodeClimateFormatter creates a new Code Climate formatter
+func NewCodeClimateFormatter() *CodeClimateFormatter {
+	return &CodeClimateFormatter{}
+}
+
+// CodeClimateIssue is one entry of a Code Climate report
+type CodeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Content     *CodeClimateContent `json:"content,omitempty"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+	Location    CodeClimateLocation `json:"location"`
+}
+
+// CodeClimateContent holds the Markdown body of an issue
+type CodeClimateContent struct {
+	Body string `json:"body"`
+}
+
+// CodeClimateLocation points an issue at the manifest
+type CodeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines CodeClimateLines `json:"lines"`
+}
+
+// CodeClimateLines is the line range of a location
+type CodeClimateLines struct {
+	Begin int `json:"begin"`
+}
+
+// FormatEnvironmentReport formats the tools needing attention as a JSON array of issues
+// Fingerprints depend only on the tool ID, so GitLab tracks a tool's issue across pipelines
+// even as its status or versions change
+func (cf *CodeClimateFormatter) FormatEnvironmentReport(report checker.EnvironmentReport) (string, error) {
+	// Issues of layered manifests are located in the base manifest
+	path := report.ManifestSource
+	if len(report.ManifestSources) > 0 {
+		path = report.ManifestSources[0]
+	}
+	if !manifest.IsURL(path) {
+		path = filepath.ToSlash(filepath.Clean(path))
+	}
+
+	issues := []CodeClimateIssue{}
+	for _, item := range report.Items {
+		if !item.NeedsAttention() {
+			continue
+		}
+
+		issue := CodeClimateIssue{
+			Type:        "issue",
+			CheckName:   "goctor/" + codeClimateCheckName(item),
+			Description: problemDescription(item),
+			Categories:  []string{"Compatibility"},
+			Severity:    codeClimateSeverity(item),
+			Fingerprint: codeClimateFingerprint(item.ToolID),
+			Location:    CodeClimateLocation{Path: path, Lines: CodeClimateLines{Begin: 1}},
+		}
+		if item.InstallHint != "" {
+			issue.Content = &CodeClimateContent{Body: "Install: `" + item.InstallHint + "`"}
+		}
+
+		issues = append(issues, issue)
+	}
+
+	// Descriptions quote requirements such as >=1.20, which must not become \u003e
+	var output strings.Builder
+	encoder := json.NewEncoder(&output)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(issues); err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
+
+// codeClimateCheckName names the kind of problem a tool has
+func codeClimateCheckName(item checker.CheckResult) string {
+	if item.Deprecated {
+		return "deprecated"
+	}
+	if item.Status == checker.StatusOK {
+		return "below-recommended"
+	}
+	return item.Status.String()
+}
+
+// codeClimateSeverity maps a tool's problem to a Code Climate severity: blocking failures are
+// major; warnings, deprecated tools, and versions below the recommended one are minor
+func codeClimateSeverity(item checker.CheckResult) string {
+	if item.Status == checker.StatusOK || item.Severity == manifest.SeverityWarning || item.Deprecated {
+		return "minor"
+	}
+	return "major"
+}
+
+// codeClimateFingerprint returns the stable fingerprint of a tool's issue
+func codeClimateFingerprint(toolID string) string {
+	sum := sha256.Sum256([]byte("goctor:" + toolID))
+	return hex.EncodeToString(sum[:16])
+}
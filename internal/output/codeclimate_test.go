@@ -0,0 +1,73 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+func TestCodeClimateFormatter(t *testing.T) {
+	report := testReport()
+	report.Items = append(report.Items,
+		checker.CheckResult{ToolID: "terraform", ToolName: "Terraform", Status: checker.StatusError, ErrorMessage: "terraform version timed out after 5s"},
+		checker.CheckResult{ToolID: "shellcheck", ToolName: "ShellCheck", Status: checker.StatusNotFound, Severity: manifest.SeverityWarning, RequiredVersion: ">=0.9"},
+		checker.CheckResult{ToolID: "python", ToolName: "Python", Status: checker.StatusOK, ActualVersion: "3.11.2", RecommendedVersion: ">=3.12", BelowRecommended: true},
+		checker.CheckResult{ToolID: "yarn", ToolName: "Yarn", Status: checker.StatusOK, ActualVersion: "1.22.19", Deprecated: true},
+	)
+	report.Summary = checker.CalculateCheckSummary(report.Items)
+
+	got, err := NewCodeClimateFormatter().FormatEnvironmentReport(report)
+	if err != nil {
+		t.Fatalf("FormatEnvironmentReport() error = %v", err)
+	}
+	assertGolden(t, "report-codeclimate.json", got)
+}
+
+func TestCodeClimateFormatterSuccess(t *testing.T) {
+	report := testReport()
+	report.Items = report.Items[:1]
+	report.Summary = checker.CalculateCheckSummary(report.Items)
+
+	got, err := NewCodeClimateFormatter().FormatEnvironmentReport(report)
+	if err != nil {
+		t.Fatalf("FormatEnvironmentReport() error = %v", err)
+	}
+	if got != "[]\n" {
+		t.Errorf("FormatEnvironmentReport() = %q, want an empty array", got)
+	}
+}
+
+func TestCodeClimateFingerprint(t *testing.T) {
+	// A tool keeps its fingerprint when its status or versions change, so GitLab tracks the
+	// issue across pipelines
+	format := func(item checker.CheckResult) CodeClimateIssue {
+		t.Helper()
+		report := testReport()
+		report.Items = []checker.CheckResult{item}
+		got, err := NewCodeClimateFormatter().FormatEnvironmentReport(report)
+		if err != nil {
+			t.Fatalf("FormatEnvironmentReport() error = %v", err)
+		}
+		var issues []CodeClimateIssue
+		if err := json.Unmarshal([]byte(got), &issues); err != nil {
+			t.Fatal(err)
+		}
+		if len(issues) != 1 {
+			t.Fatalf("got %d issues, want 1", len(issues))
+		}
+		return issues[0]
+	}
+
+	missing := format(checker.CheckResult{ToolID: "node", ToolName: "Node.js", Status: checker.StatusNotFound, RequiredVersion: ">=18"})
+	outdated := format(checker.CheckResult{ToolID: "node", ToolName: "Node.js", Status: checker.StatusOutdated, RequiredVersion: ">=20", ActualVersion: "18.19.0"})
+	other := format(checker.CheckResult{ToolID: "nodejs", ToolName: "Node.js", Status: checker.StatusNotFound, RequiredVersion: ">=18"})
+
+	if missing.Fingerprint != outdated.Fingerprint {
+		t.Errorf("fingerprint changed with the status: %s != %s", missing.Fingerprint, outdated.Fingerprint)
+	}
+	if missing.Fingerprint == other.Fingerprint {
+		t.Errorf("tools node and nodejs share the fingerprint %s", missing.Fingerprint)
+	}
+}
@@ -0,0 +1,34 @@
+package output
+
+import (
+	"os"
+)
+
+// Color modes for human-readable output
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// ColorModes lists the supported --color values
+var ColorModes = []string{ColorAuto, ColorAlways, ColorNever}
+
+// ColorEnabled decides whether output written to out is colored
+// In auto mode color is used only on a terminal, and never when NO_COLOR is set
+// (https://no-color.org) or TERM is "dumb"
+func ColorEnabled(mode string, out *os.File, getenv func(string) string) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if getenv("NO_COLOR") != "" || getenv("TERM") == "dumb" {
+		return false
+	}
+
+	info, err := out.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,182 @@
+package output
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+// CycloneDXSpecVersion is the CycloneDX specification version of generated SBOMs
+const CycloneDXSpecVersion = "1.5"
+
+// CycloneDXFormatter provides a CycloneDX SBOM of the tools found by a check, so developer
+// toolchains can be inventoried alongside application dependencies
+type CycloneDXFormatter struct {
+	version string // goctor's version, recorded as the generating tool
+}
+
+// NewCycloneDXFormatter creates a new CycloneDX formatter
+func NewCycloneDXFormatter(version string) *CycloneDXFormatter {
+	return &CycloneDXFormatter{version: version}
+}
+
+// CycloneDXBOM is the top-level CycloneDX JSON document
+type CycloneDXBOM struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     CycloneDXMetadata    `json:"metadata"`
+	Components   []CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXMetadata describes when, how, and from what the SBOM was generated
+type CycloneDXMetadata struct {
+	Timestamp  string              `json:"timestamp"`
+	Tools      CycloneDXTools      `json:"tools"`
+	Properties []CycloneDXProperty `json:"properties,omitempty"`
+}
+
+// CycloneDXTools lists the tools that generated the SBOM
+type CycloneDXTools struct {
+	Components []CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXComponent is one installed tool
+type CycloneDXComponent struct {
+	Type        string              `json:"type"`
+	BOMRef      string              `json:"bom-ref,omitempty"`
+	Name        string              `json:"name"`
+	Version     string              `json:"version,omitempty"`
+	Description string              `json:"description,omitempty"`
+	PURL        string              `json:"purl,omitempty"`
+	Properties  []CycloneDXProperty `json:"properties,omitempty"`
+}
+
+// CycloneDXProperty is a name/value pair in goctor's namespace
+type CycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// FormatEnvironmentReport formats the tools whose version was detected as a CycloneDX JSON SBOM
+// Missing tools and checks without a version, such as services, are left out; each component
+// is identified by a pkg:generic purl of the tool ID and detected version
+func (cf *CycloneDXFormatter) FormatEnvironmentReport(report checker.EnvironmentReport) (string, error) {
+	serialNumber, err := newUUID()
+	if err != nil {
+		return "", fmt.Errorf("generating serial number: %v", err)
+	}
+
+	bom := CycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  CycloneDXSpecVersion,
+		SerialNumber: "urn:uuid:" + serialNumber,
+		Version:      1,
+		Metadata: CycloneDXMetadata{
+			Timestamp: report.GeneratedAt.UTC().Format(time.RFC3339),
+			Tools: CycloneDXTools{Components: []CycloneDXComponent{
+				{Type: "application", Name: "goctor", Version: cf.version},
+			}},
+			Properties: cycloneDXMetadataProperties(report),
+		},
+		Components: []CycloneDXComponent{},
+	}
+
+	for _, item := range report.Items {
+		if item.ActualVersion == "" {
+			continue
+		}
+
+		purl := genericPURL(item.ToolID, item.ActualVersion)
+		bom.Components = append(bom.Components, CycloneDXComponent{
+			Type:        "application",
+			BOMRef:      purl,
+			Name:        item.ToolID,
+			Version:     item.ActualVersion,
+			Description: item.ToolName,
+			PURL:        purl,
+			Properties:  cycloneDXComponentProperties(item),
+		})
+	}
+
+	// Requirements such as >=1.20 must not become \u003e
+	var output strings.Builder
+	encoder := json.NewEncoder(&output)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bom); err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
+
+// cycloneDXMetadataProperties records the machine and manifest the SBOM describes
+func cycloneDXMetadataProperties(report checker.EnvironmentReport) []CycloneDXProperty {
+	var properties []CycloneDXProperty
+
+	if info, ok := reportPlatform(report); ok {
+		properties = append(properties, CycloneDXProperty{Name: "goctor:platform", Value: info.String()})
+	}
+
+	sources := report.ManifestSources
+	if len(sources) == 0 && report.ManifestSource != "" {
+		sources = []string{report.ManifestSource}
+	}
+	for _, source := range sources {
+		properties = append(properties, CycloneDXProperty{Name: "goctor:manifest", Value: source})
+	}
+
+	return properties
+}
+
+// cycloneDXComponentProperties records how a tool was checked and whether it passed
+func cycloneDXComponentProperties(item checker.CheckResult) []CycloneDXProperty {
+	properties := []CycloneDXProperty{{Name: "goctor:status", Value: item.Status.String()}}
+	if item.RequiredVersion != "" {
+		properties = append(properties, CycloneDXProperty{Name: "goctor:required", Value: item.RequiredVersion})
+	}
+	if item.CommandPath != "" {
+		properties = append(properties, CycloneDXProperty{Name: "goctor:path", Value: item.CommandPath})
+	}
+	if item.ManagedBy != "" {
+		properties = append(properties, CycloneDXProperty{Name: "goctor:managed_by", Value: item.ManagedBy})
+	}
+	if item.Workspace != "" {
+		properties = append(properties, CycloneDXProperty{Name: "goctor:workspace", Value: item.Workspace})
+	}
+	return properties
+}
+
+// genericPURL returns the package URL of a tool that has no package ecosystem of its own
+func genericPURL(name, version string) string {
+	return "pkg:generic/" + purlEscape(strings.ToLower(name)) + "@" + purlEscape(version)
+}
+
+// purlEscape percent-encodes a purl name or version, keeping only unreserved characters
+func purlEscape(s string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(s) {
+		if 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || strings.IndexByte("-._~", b) >= 0 {
+			escaped.WriteByte(b)
+		} else {
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,49 @@
+package output
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+// serialNumberRegex matches the random serial number of an SBOM, which the golden file replaces
+var serialNumberRegex = regexp.MustCompile(`"serialNumber": "urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}"`)
+
+func TestCycloneDXFormatter(t *testing.T) {
+	report := testReport()
+	report.Items[0].CommandPath = "/usr/local/go/bin/go"
+	report.Items[1].ManagedBy = "mise"
+	report.Items = append(report.Items,
+		checker.CheckResult{ToolID: "Protoc-Gen_Go", ToolName: "protoc-gen-go", Status: checker.StatusOK, ActualVersion: "1.34.2+dev/1", Workspace: "api"},
+	)
+
+	got, err := NewCycloneDXFormatter("1.2.3").FormatEnvironmentReport(report)
+	if err != nil {
+		t.Fatalf("FormatEnvironmentReport() error = %v", err)
+	}
+
+	if !serialNumberRegex.MatchString(got) {
+		t.Fatalf("SBOM has no random UUID serial number:\n%s", got)
+	}
+	got = serialNumberRegex.ReplaceAllString(got, `"serialNumber": "urn:uuid:00000000-0000-4000-8000-000000000000"`)
+	assertGolden(t, "report-cyclonedx.json", got)
+}
+
+func TestGenericPURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{"go", "1.23.4", "pkg:generic/go@1.23.4"},
+		{"Terraform", "1.9.0", "pkg:generic/terraform@1.9.0"},
+		{"protoc-gen-go", "1.34.2+dev/1", "pkg:generic/protoc-gen-go@1.34.2%2Bdev%2F1"},
+	}
+
+	for _, tt := range tests {
+		if got := genericPURL(tt.name, tt.version); got != tt.want {
+			t.Errorf("genericPURL(%q, %q) = %q, want %q", tt.name, tt.version, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// GitHubFormatter provides GitHub Actions workflow command output
+type GitHubFormatter struct{}
+
+// NewGitHubFormatter creates a new GitHub Actions formatter
+func NewGitHubFormatter() *GitHubFormatter {
+	return &GitHubFormatter{}
+}
+
+// FormatAnnotations formats ::error / ::warning workflow commands for tools needing attention
+// Annotations point at the manifest file when it is local so they show up in PR diffs; with
+// layered manifests the file defining a tool is unknown, so they point at none
+func (gf *GitHubFormatter) FormatAnnotations(report checker.EnvironmentReport) string {
+	var output strings.Builder
+
+	for _, item := range report.Items {
+		if !item.NeedsAttention() {
+			continue
+		}
+
+		level := "error"
+		if item.Status == checker.StatusOK || item.Severity == manifest.SeverityWarning || item.Deprecated {
+			level = "warning"
+		}
+
+		properties := []string{"title=" + escapeGitHubProperty(fmt.Sprintf("%s (%s)", item.ToolName, item.ToolID))}
+		if !manifest.IsURL(report.ManifestSource) && len(report.ManifestSources) == 0 {
+			properties = append([]string{"file=" + escapeGitHubProperty(report.ManifestSource)}, properties...)
+		}
+
+		output.WriteString(fmt.Sprintf("::%s %s::%s\n", level, strings.Join(properties, ","), escapeGitHubData(gf.annotationMessage(item))))
+	}
+
+	return output.String()
+}
+
+// FormatStepSummary formats the report for $GITHUB_STEP_SUMMARY
+func (gf *GitHubFormatter) FormatStepSummary(report checker.EnvironmentReport) string {
+	return NewMarkdownFormatter().FormatEnvironmentReport(report)
+}
+
+// annotationMessage describes what is wrong with a tool and how to fix it
+func (gf *GitHubFormatter) annotationMessage(item checker.CheckResult) string {
+	message := problemDescription(item)
+	if item.InstallHint != "" {
+		message += "\nInstall: " + item.InstallHint
+	}
+
+	return message
+}
+
+// problemDescription describes in one line what is wrong with a tool that needs attention
+func problemDescription(item checker.CheckResult) string {
+	if item.Deprecated {
+		message := fmt.Sprintf("%s is deprecated and still installed", item.ToolName)
+		if migration := item.Links[manifest.MigrationLink]; migration != "" {
+			message += "; see " + migration
+		}
+		return message
+	}
+
+	var message string
+	switch item.Status {
+	case checker.StatusOK:
+		message = fmt.Sprintf("%s %s is below the recommended %s", item.ToolName, item.ActualVersion, item.RecommendedVersion)
+	case checker.StatusNotFound, checker.StatusMissing:
+		message = fmt.Sprintf("%s is not installed (required %s)", item.ToolName, item.RequiredVersion)
+	case checker.StatusOutdated:
+		message = fmt.Sprintf("%s %s does not satisfy %s", item.ToolName, item.ActualVersion, item.RequiredVersion)
+		if item.Blocked != nil {
+			message = fmt.Sprintf("%s %s is blocked", item.ToolName, item.ActualVersion)
+		}
+	default:
+		message = fmt.Sprintf("%s could not be checked", item.ToolName)
+	}
+
+	if item.ErrorMessage != "" && item.Status != checker.StatusNotFound {
+		message += ": " + item.ErrorMessage
+	}
+
+	return message
+}
+
+// escapeGitHubData escapes a workflow command message
+func escapeGitHubData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeGitHubProperty escapes a workflow command property value
+func escapeGitHubProperty(s string) string {
+	s = escapeGitHubData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
@@ -0,0 +1,95 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+func TestEscapeGitHubData(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{"100% done", "100%25 done"},
+		{"%0A is not a newline", "%250A is not a newline"},
+		{"line1\nline2", "line1%0Aline2"},
+		{"line1\r\nline2", "line1%0D%0Aline2"},
+		{"a: b, c", "a: b, c"},
+	}
+
+	for _, tt := range tests {
+		if got := escapeGitHubData(tt.in); got != tt.want {
+			t.Errorf("escapeGitHubData(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeGitHubProperty(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"tools.yaml", "tools.yaml"},
+		{"C:\\work\\tools.yaml", "C%3A\\work\\tools.yaml"},
+		{"Node.js, LTS (node)", "Node.js%2C LTS (node)"},
+		{"50%: a\r\nb", "50%25%3A a%0D%0Ab"},
+	}
+
+	for _, tt := range tests {
+		if got := escapeGitHubProperty(tt.in); got != tt.want {
+			t.Errorf("escapeGitHubProperty(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGitHubFormatAnnotations(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		items  []checker.CheckResult
+		want   string
+	}{
+		{
+			name:   "escapes properties and message",
+			source: "ci/tools: 100%.yaml",
+			items: []checker.CheckResult{{
+				ToolID:          "node",
+				ToolName:        "Node.js, LTS",
+				Status:          checker.StatusNotFound,
+				RequiredVersion: ">=18",
+				InstallHint:     "mise use node@20 # 100%",
+			}},
+			want: "::error file=ci/tools%3A 100%25.yaml,title=Node.js%2C LTS (node)::Node.js, LTS is not installed (required >=18)%0AInstall: mise use node@20 # 100%25\n",
+		},
+		{
+			name:   "warnings and errors",
+			source: "tools.yaml",
+			items: []checker.CheckResult{
+				{ToolID: "go", ToolName: "Go", Status: checker.StatusOK, ActualVersion: "1.23.4"},
+				{ToolID: "jq", ToolName: "jq", Status: checker.StatusOutdated, RequiredVersion: ">=1.7", ActualVersion: "1.6", ErrorMessage: "line1\r\nline2"},
+				{ToolID: "shellcheck", ToolName: "ShellCheck", Status: checker.StatusNotFound, Severity: manifest.SeverityWarning, RequiredVersion: ">=0.9"},
+				{ToolID: "xcode", ToolName: "Xcode", Status: checker.StatusSkipped},
+			},
+			want: "::error file=tools.yaml,title=jq (jq)::jq 1.6 does not satisfy >=1.7: line1%0D%0Aline2\n" +
+				"::warning file=tools.yaml,title=ShellCheck (shellcheck)::ShellCheck is not installed (required >=0.9)\n",
+		},
+		{
+			name:   "remote manifest has no file",
+			source: "https://example.com/tools.yaml",
+			items:  []checker.CheckResult{{ToolID: "jq", ToolName: "jq", Status: checker.StatusNotFound, RequiredVersion: ">=1.6"}},
+			want:   "::error title=jq (jq)::jq is not installed (required >=1.6)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := checker.EnvironmentReport{ManifestSource: tt.source, Items: tt.items}
+			if got := NewGitHubFormatter().FormatAnnotations(report); got != tt.want {
+				t.Errorf("FormatAnnotations() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package output
+
+import (
+	"html/template"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+// HTMLFormatter provides standalone HTML report formatting with embedded styling
+type HTMLFormatter struct{}
+
+// NewHTMLFormatter creates a new HTML formatter
+func NewHTMLFormatter() *HTMLFormatter {
+	return &HTMLFormatter{}
+}
+
+// htmlLink is a single remediation link rendered in a tool card
+type htmlLink struct {
+	Type string
+	URL  string
+}
+
+// htmlItem is the template view of a single check result
+type htmlItem struct {
+	checker.CheckResult
+	StatusClass string
+	StatusLabel string
+	SortedLinks []htmlLink
+	NeedsAction bool
+}
+
+// htmlReport is the template view of an environment report
+type htmlReport struct {
+	Report     checker.EnvironmentReport
+	Successful bool
+	Generated  string
+	Items      []htmlItem
+}
+
+// FormatEnvironmentReport formats a complete environment report as a self-contained HTML page
+func (hf *HTMLFormatter) FormatEnvironmentReport(report checker.EnvironmentReport) (string, error) {
+	view := htmlReport{
+		Report:     report,
+		Successful: report.IsSuccessful(),
+		Generated:  report.GeneratedAt.Format("2006-01-02 15:04:05"),
+		Items:      make([]htmlItem, len(report.Items)),
+	}
+
+	for i, item := range report.Items {
+		links := make([]htmlLink, 0, len(item.Links))
+		for _, linkType := range sortedKeys(item.Links) {
+			links = append(links, htmlLink{Type: linkType, URL: item.Links[linkType]})
+		}
+
+		statusClass, statusLabel := hf.getStatusClass(item)
+		view.Items[i] = htmlItem{
+			CheckResult: item,
+			StatusClass: statusClass,
+			StatusLabel: statusLabel,
+			SortedLinks: links,
+			NeedsAction: item.NeedsAttention(),
+		}
+	}
+
+	var output strings.Builder
+	if err := htmlReportTemplate.Execute(&output, view); err != nil {
+		return "", err
+	}
+
+	return output.String(), nil
+}
+
+// getStatusClass returns the CSS class and label for a check result
+func (hf *HTMLFormatter) getStatusClass(item checker.CheckResult) (string, string) {
+	if item.Status == checker.StatusSkipped {
+		return "skip", "skipped"
+	}
+	if item.Informational {
+		return "info", "info"
+	}
+	if item.Deprecated {
+		return "warn", "deprecated"
+	}
+
+	switch item.Status {
+	case checker.StatusOK:
+		if item.BelowRecommended {
+			return "warn", "below recommended"
+		}
+		return "ok", "ok"
+	case checker.StatusNotFound, checker.StatusMissing:
+		return "fail", "missing"
+	case checker.StatusOutdated:
+		return "warn", "outdated"
+	case checker.StatusError:
+		return "fail", "error"
+	default:
+		return "info", "unknown"
+	}
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Development Environment Check</title>
+<style>
+  body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; background: #f6f8fa; color: #1f2328; }
+  main { max-width: 960px; margin: 0 auto; padding: 24px; }
+  h1 { font-size: 1.6em; margin: 0 0 8px; }
+  .meta { color: #59636e; font-size: 0.9em; margin-bottom: 16px; }
+  .banner { border-radius: 8px; padding: 16px; margin-bottom: 24px; color: #fff; }
+  .banner.ok { background: #1a7f37; }
+  .banner.fail { background: #cf222e; }
+  .banner .counts span { margin-right: 16px; }
+  .cards { display: grid; grid-template-columns: repeat(auto-fill, minmax(280px, 1fr)); gap: 16px; }
+  .card { background: #fff; border: 1px solid #d1d9e0; border-left: 6px solid #d1d9e0; border-radius: 8px; padding: 16px; }
+  .card.ok { border-left-color: #1a7f37; }
+  .card.warn { border-left-color: #9a6700; }
+  .card.fail { border-left-color: #cf222e; }
+  .card.info { border-left-color: #0969da; }
+  .card.skip { border-left-color: #8c959f; }
+  .card h2 { font-size: 1.1em; margin: 0 0 8px; }
+  .badge { display: inline-block; font-size: 0.75em; padding: 2px 8px; border-radius: 12px; background: #eaeef2; text-transform: uppercase; }
+  .card dl { margin: 8px 0; display: grid; grid-template-columns: auto 1fr; gap: 4px 12px; font-size: 0.9em; }
+  .card dt { color: #59636e; }
+  .card dd { margin: 0; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; word-break: break-all; }
+  .error { color: #cf222e; font-size: 0.9em; }
+  .subchecks { margin: 8px 0; padding-left: 20px; font-size: 0.9em; }
+  .remediation { margin-top: 8px; font-size: 0.9em; }
+  .remediation ul { margin: 4px 0; padding-left: 20px; }
+  code { background: #eaeef2; padding: 1px 4px; border-radius: 4px; }
+</style>
+</head>
+<body>
+<main>
+<h1>Development Environment Check</h1>
+<div class="meta">Manifest: {{.Report.ManifestSource}} &middot; Generated: {{.Generated}}</div>
+
+<div class="banner {{if .Successful}}ok{{else}}fail{{end}}">
+  <strong>{{if .Successful}}All required tools are ready{{else}}Some tools need attention{{end}}</strong>
+  <div class="counts">
+    <span>Total: {{.Report.Summary.Total}}</span>
+    <span>OK: {{.Report.Summary.OK}}</span>
+    {{if .Report.Summary.Missing}}<span>Missing: {{.Report.Summary.Missing}}</span>{{end}}
+    {{if .Report.Summary.Outdated}}<span>Outdated: {{.Report.Summary.Outdated}}</span>{{end}}
+    {{if .Report.Summary.Errors}}<span>Errors: {{.Report.Summary.Errors}}</span>{{end}}
+    {{if .Report.Summary.Skipped}}<span>Skipped: {{.Report.Summary.Skipped}}</span>{{end}}
+    {{if .Report.Summary.Warnings}}<span>Warnings: {{.Report.Summary.Warnings}}</span>{{end}}
+    {{if .Report.Summary.Informational}}<span>Informational: {{.Report.Summary.Informational}}</span>{{end}}
+  </div>
+</div>
+
+<div class="cards">
+{{range .Items}}
+  <section class="card {{.StatusClass}}">
+    <h2>{{.ToolName}} <span class="badge">{{.StatusLabel}}</span></h2>
+    <dl>
+      <dt>ID</dt><dd>{{.ToolID}}</dd>
+      {{if .ActualVersion}}<dt>Installed</dt><dd>{{.ActualVersion}}</dd>{{end}}
+      {{if .RequiredVersion}}<dt>Required</dt><dd>{{.RequiredVersion}}</dd>{{end}}
+      {{if .RecommendedVersion}}<dt>Recommended</dt><dd>{{.RecommendedVersion}}</dd>{{end}}
+      {{if .CommandPath}}<dt>Path</dt><dd>{{.CommandPath}}</dd>{{end}}
+      {{if .ManagedBy}}<dt>Managed by</dt><dd>{{.ManagedBy}}</dd>{{end}}
+      {{if .SkipReason}}<dt>Skipped</dt><dd>{{.SkipReason}}</dd>{{end}}
+    </dl>
+    {{if .ErrorMessage}}<div class="error">{{.ErrorMessage}}</div>{{end}}
+    {{if .SubChecks}}
+    <ul class="subchecks">
+      {{range .SubChecks}}<li>{{.Name}} <span class="badge">{{.Status}}</span>{{if .ActualVersion}} <code>{{.ActualVersion}}</code>{{end}}{{if .ErrorMessage}} <span class="error">{{.ErrorMessage}}</span>{{end}}</li>{{end}}
+    </ul>
+    {{end}}
+    {{if .Variants}}
+    <ul class="subchecks">
+      {{range .Variants}}<li>{{.Name}} <span class="badge">{{.Status}}</span>{{if .ActualVersion}} <code>{{.ActualVersion}}</code>{{end}}{{if .CommandPath}} {{.CommandPath}}{{end}}{{if .ErrorMessage}} <span class="error">{{.ErrorMessage}}</span>{{end}}</li>{{end}}
+    </ul>
+    {{end}}
+    {{if .NeedsAction}}
+    <div class="remediation">
+      {{if .InstallHint}}<div>Install: <code>{{.InstallHint}}</code></div>{{end}}
+      {{if .SortedLinks}}
+      <ul>
+        {{range .SortedLinks}}<li><a href="{{.URL}}">{{.Type}}</a></li>{{end}}
+      </ul>
+      {{end}}
+    </div>
+    {{end}}
+  </section>
+{{end}}
+</div>
+</main>
+</body>
+</html>
+`))
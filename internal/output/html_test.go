@@ -0,0 +1,28 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+func TestHTMLFormatter(t *testing.T) {
+	// The report's names and constraints hold characters HTML must escape, such as < and |
+	got, err := NewHTMLFormatter().FormatEnvironmentReport(testReport())
+	if err != nil {
+		t.Fatalf("FormatEnvironmentReport() error = %v", err)
+	}
+	assertGolden(t, "report.html", got)
+}
+
+func TestHTMLFormatterSuccess(t *testing.T) {
+	report := testReport()
+	report.Items = report.Items[:1]
+	report.Summary = checker.CalculateCheckSummary(report.Items)
+
+	got, err := NewHTMLFormatter().FormatEnvironmentReport(report)
+	if err != nil {
+		t.Fatalf("FormatEnvironmentReport() error = %v", err)
+	}
+	assertGolden(t, "report-success.html", got)
+}
@@ -1,22 +1,45 @@
 package output
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/history"
+	"github.com/ikorihn/goctor/internal/i18n"
+	"github.com/ikorihn/goctor/internal/links"
 	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/paths"
+	"github.com/ikorihn/goctor/internal/selfcheck"
+	"github.com/ikorihn/goctor/internal/upstream"
 )
 
 // HumanFormatter provides human-readable output formatting
 type HumanFormatter struct {
 	colorEnabled bool
+	printer      *i18n.Printer
+	view         string
+	durations    bool
+	verbose      bool
 }
 
+// Views of an environment report
+const (
+	ViewFull     = "full"     // header, summary, every result, and recommendations
+	ViewFailures = "failures" // the full report without results that need no attention
+	ViewSummary  = "summary"  // only the quick one-line summary
+)
+
 // NewHumanFormatter creates a new human-readable formatter
 func NewHumanFormatter() *HumanFormatter {
 	return &HumanFormatter{
 		colorEnabled: true, // Can be disabled for non-terminal output
+		printer:      i18n.NewPrinter(i18n.DefaultLanguage),
+		view:         ViewFull,
 	}
 }
 
@@ -25,8 +48,33 @@ func (hf *HumanFormatter) SetColorEnabled(enabled bool) {
 	hf.colorEnabled = enabled
 }
 
+// SetLanguage selects the language of reports, tool lists, and quick summaries
+// Unsupported languages fall back to English
+func (hf *HumanFormatter) SetLanguage(lang string) {
+	hf.printer = i18n.NewPrinter(lang)
+}
+
+// SetView selects how much of an environment report is printed
+func (hf *HumanFormatter) SetView(view string) {
+	hf.view = view
+}
+
+// SetShowDurations adds how long each check took to report results
+func (hf *HumanFormatter) SetShowDurations(enabled bool) {
+	hf.durations = enabled
+}
+
+// SetVerbose adds the trace of each check and its duration to report results
+func (hf *HumanFormatter) SetVerbose(enabled bool) {
+	hf.verbose = enabled
+}
+
 // FormatEnvironmentReport formats a complete environment report
 func (hf *HumanFormatter) FormatEnvironmentReport(report checker.EnvironmentReport) string {
+	if hf.view == ViewSummary {
+		return hf.FormatQuickSummary(report.Summary) + "\n"
+	}
+
 	var output strings.Builder
 
 	// Header
@@ -38,7 +86,16 @@ func (hf *HumanFormatter) FormatEnvironmentReport(report checker.EnvironmentRepo
 	output.WriteString("\n")
 
 	// Individual tool results
-	output.WriteString(hf.formatToolResults(report.Items))
+	items := report.Items
+	if hf.view == ViewFailures {
+		items = nil
+		for _, item := range report.Items {
+			if item.NeedsAttention() {
+				items = append(items, item)
+			}
+		}
+	}
+	output.WriteString(hf.formatToolResults(items))
 
 	// Footer with recommendations
 	if !report.IsSuccessful() {
@@ -51,17 +108,43 @@ func (hf *HumanFormatter) FormatEnvironmentReport(report checker.EnvironmentRepo
 
 // FormatToolList formats a list of tools from a manifest
 func (hf *HumanFormatter) FormatToolList(tools []manifest.ToolDefinition, manifestSource string) string {
+	return hf.FormatToolListWithStatus(tools, nil, manifestSource)
+}
+
+// FormatToolListWithStatus formats a list of tools with each tool's current status inline
+// Tools without an entry in results are listed without a status
+func (hf *HumanFormatter) FormatToolListWithStatus(tools []manifest.ToolDefinition, results map[string]checker.CheckResult, manifestSource string) string {
 	var output strings.Builder
 
-	output.WriteString(fmt.Sprintf("Tools defined in manifest (%s):\n\n", manifestSource))
+	output.WriteString(hf.printer.Sprintf("list.title", manifestSource) + "\n\n")
 
 	for i, tool := range tools {
 		output.WriteString(fmt.Sprintf("%d. %s (%s)\n", i+1, tool.Name, tool.ID))
-		output.WriteString(fmt.Sprintf("   Required version: %s\n", tool.RequiredVersion))
-		output.WriteString(fmt.Sprintf("   Rationale: %s\n", tool.Rationale))
+		output.WriteString("   " + hf.printer.Sprintf("list.required_version", tool.RequiredVersion) + "\n")
+		output.WriteString("   " + hf.printer.Sprintf("list.rationale", tool.Rationale) + "\n")
+
+		if result, ok := results[tool.ID]; ok {
+			status := fmt.Sprintf("%s %s", hf.getStatusIcon(result.Status), hf.statusWord(result.Status))
+			if result.ActualVersion != "" {
+				status += " (" + result.ActualVersion + ")"
+			}
+			output.WriteString("   " + hf.printer.Sprintf("list.status", status) + "\n")
+		}
+		if tool.Severity != "" {
+			output.WriteString("   " + hf.printer.Sprintf("list.severity", tool.Severity) + "\n")
+		}
+		if len(tool.Tags) > 0 {
+			output.WriteString("   " + hf.printer.Sprintf("list.tags", strings.Join(tool.Tags, ", ")) + "\n")
+		}
+		if tool.Scope != "" {
+			output.WriteString("   " + hf.printer.Sprintf("list.scope", tool.Scope) + "\n")
+		}
+		if len(tool.Platforms) > 0 {
+			output.WriteString("   " + hf.printer.Sprintf("list.platforms", strings.Join(tool.Platforms, ", ")) + "\n")
+		}
 
 		if len(tool.Links) > 0 {
-			output.WriteString("   Links:\n")
+			output.WriteString("   " + hf.printer.Sprintf("links") + "\n")
 			for linkType, url := range tool.Links {
 				output.WriteString(fmt.Sprintf("     %s: %s\n", linkType, url))
 			}
@@ -72,24 +155,455 @@ func (hf *HumanFormatter) FormatToolList(tools []manifest.ToolDefinition, manife
 	return output.String()
 }
 
+// FormatToolExplanation formats full detail for a single tool, optionally with live check details
+func (hf *HumanFormatter) FormatToolExplanation(tool manifest.ToolDefinition, constraintExplanation string, result *checker.CheckResult) string {
+	var output strings.Builder
+
+	output.WriteString(fmt.Sprintf("%s (%s)\n", tool.Name, tool.ID))
+	output.WriteString(strings.Repeat("=", len(tool.Name)+len(tool.ID)+3) + "\n\n")
+
+	output.WriteString(fmt.Sprintf("Rationale:   %s\n", tool.Rationale))
+	if tool.RequiredVersion != "" {
+		output.WriteString(fmt.Sprintf("Constraint:  %s\n", tool.RequiredVersion))
+		output.WriteString(fmt.Sprintf("             Satisfied by %s\n", constraintExplanation))
+	}
+	if tool.RequireFrom != nil {
+		source := tool.RequireFrom.File
+		if tool.RequireFrom.Key != "" {
+			source += " (" + tool.RequireFrom.Key + ")"
+		}
+		output.WriteString(fmt.Sprintf("Require from: %s, match %s\n", source, cmp.Or(tool.RequireFrom.Match, manifest.MatchMinimum)))
+	}
+	if tool.Informational {
+		output.WriteString("Mode:        informational (never affects the exit code)\n")
+	}
+	output.WriteString("Platforms:   all supported platforms (darwin, linux)\n")
+	if len(tool.DependsOn) > 0 {
+		output.WriteString(fmt.Sprintf("Depends on:  %s\n", strings.Join(tool.DependsOn, ", ")))
+	}
+
+	output.WriteString("\nCheck:\n")
+	if tool.IsService() {
+		output.WriteString("  Type:    service (passes while the command succeeds)\n")
+	}
+	if tool.IsOS() {
+		output.WriteString(fmt.Sprintf("  Type:    os (compares the %s version)\n", tool.Check.OS))
+	}
+	if tool.Check.Probe != "" {
+		output.WriteString(fmt.Sprintf("  Probe:   %s\n", tool.Check.Probe))
+	}
+	switch {
+	case tool.IsPlugin():
+		output.WriteString(fmt.Sprintf("  Plugin:  %s\n", tool.Check.Plugin))
+	case tool.IsGitConfig():
+		output.WriteString("  Type:    git-config (git config --get KEY must match REGEX)\n")
+		for _, key := range tool.GitConfigKeys() {
+			output.WriteString(fmt.Sprintf("  Config:  %s =~ %s\n", key, tool.Check.GitConfig[key]))
+		}
+	case tool.IsEnv():
+		output.WriteString(fmt.Sprintf("  Type:    env (%s must be set)\n", tool.Check.Var))
+		if tool.Check.Regex != "" {
+			output.WriteString(fmt.Sprintf("  Match:   %s\n", tool.Check.Regex))
+		}
+		if tool.Check.Exists != "" {
+			output.WriteString(fmt.Sprintf("  Exists:  %s\n", tool.Check.Exists))
+		}
+		if tool.Check.Sensitive {
+			output.WriteString("  Sensitive: yes (the value is masked)\n")
+		}
+	default:
+		output.WriteString(fmt.Sprintf("  Command: %s\n", strings.Join(tool.CheckCommand(), " ")))
+		for _, fallback := range tool.Check.Fallbacks {
+			output.WriteString(fmt.Sprintf("  Fallback: %s\n", strings.Join(fallback, " ")))
+		}
+		if tool.VersionRegex() != "" || (!tool.IsService() && !tool.IsOS()) {
+			output.WriteString(fmt.Sprintf("  Regex:   %s\n", tool.VersionRegex()))
+		}
+		if key := tool.VersionRegexKey(); key != "" {
+			output.WriteString(fmt.Sprintf("  Regex key: %s\n", key))
+		}
+	}
+	if tool.Check.Shell {
+		output.WriteString("  Shell:   yes\n")
+	}
+	if tool.Check.Workdir != "" {
+		output.WriteString(fmt.Sprintf("  Workdir: %s\n", tool.Check.Workdir))
+	}
+	for _, name := range sortedKeys(tool.Check.Env) {
+		output.WriteString(fmt.Sprintf("  Env:     %s=%s\n", name, tool.Check.Env[name]))
+	}
+	if tool.TimeoutSeconds > 0 {
+		output.WriteString(fmt.Sprintf("  Timeout: %ds\n", tool.TimeoutSeconds))
+	}
+	for _, p := range sortedKeys(tool.ExpectSHA256) {
+		output.WriteString(fmt.Sprintf("  SHA-256: %s on %s\n", strings.ToLower(tool.ExpectSHA256[p]), p))
+	}
+	for _, sub := range tool.Checks {
+		output.WriteString(fmt.Sprintf("  Sub-check %s: %s\n", sub.Name, strings.Join(sub.Check.Command, " ")))
+	}
+
+	if len(tool.Links) > 0 {
+		output.WriteString("\nLinks:\n")
+		for _, linkType := range sortedKeys(tool.Links) {
+			output.WriteString(fmt.Sprintf("  %s: %s\n", linkType, tool.Links[linkType]))
+		}
+	}
+
+	if result != nil {
+		output.WriteString("\nLive Check:\n")
+		output.WriteString(fmt.Sprintf("  Status:  %s %s\n", hf.getStatusIcon(result.Status), result.Status.String()))
+		if result.CommandPath != "" {
+			output.WriteString(fmt.Sprintf("  Path:    %s\n", result.CommandPath))
+		}
+		if result.ResolvedCommand != "" {
+			output.WriteString(fmt.Sprintf("  Resolved: %s\n", result.ResolvedCommand))
+		}
+		if result.ManagedBy != "" {
+			output.WriteString(fmt.Sprintf("  Managed by: %s\n", result.ManagedBy))
+		}
+		if result.ActualVersion != "" {
+			if result.VersionGroup != "" {
+				output.WriteString(fmt.Sprintf("  Version: %s (capture group %s)\n", result.ActualVersion, result.VersionGroup))
+			} else {
+				output.WriteString(fmt.Sprintf("  Version: %s\n", result.ActualVersion))
+			}
+		}
+		if result.ErrorMessage != "" {
+			output.WriteString(fmt.Sprintf("  %s %s\n", hf.colorize("Error:", "red"), result.ErrorMessage))
+		}
+		if result.RawOutput != "" {
+			output.WriteString("  Raw output:\n")
+			for _, line := range strings.Split(strings.TrimRight(result.RawOutput, "\n"), "\n") {
+				output.WriteString(fmt.Sprintf("    | %s\n", line))
+			}
+		}
+	}
+
+	return output.String()
+}
+
+// FormatReportDiff formats the differences between two environment reports
+func (hf *HumanFormatter) FormatReportDiff(diff checker.ReportDiff, oldSource, newSource string) string {
+	var output strings.Builder
+
+	output.WriteString(fmt.Sprintf("Comparing %s -> %s\n", oldSource, newSource))
+
+	if diff.IsEmpty() {
+		output.WriteString("\nNo changes\n")
+		return output.String()
+	}
+
+	if len(diff.Added) > 0 {
+		output.WriteString("\nAdded:\n")
+		for _, change := range diff.Added {
+			output.WriteString(fmt.Sprintf("  %s %s (%s) %s\n",
+				hf.colorize("+", "green"), change.ToolName, change.ToolID, hf.formatVersionStatus(change.NewVersion, change.NewStatus)))
+		}
+	}
+
+	if len(diff.Removed) > 0 {
+		output.WriteString("\nRemoved:\n")
+		for _, change := range diff.Removed {
+			output.WriteString(fmt.Sprintf("  %s %s (%s) %s\n",
+				hf.colorize("-", "red"), change.ToolName, change.ToolID, hf.formatVersionStatus(change.OldVersion, change.OldStatus)))
+		}
+	}
+
+	if len(diff.Changed) > 0 {
+		output.WriteString("\nChanged:\n")
+		for _, change := range diff.Changed {
+			output.WriteString(fmt.Sprintf("  %s %s (%s)\n", hf.colorize("~", "yellow"), change.ToolName, change.ToolID))
+			if change.VersionChange != "" {
+				output.WriteString(fmt.Sprintf("      version: %s -> %s (%s)\n",
+					orDash(change.OldVersion), orDash(change.NewVersion), change.VersionChange))
+			}
+			if change.StatusChanged() {
+				output.WriteString(fmt.Sprintf("      status:  %s %s -> %s %s\n",
+					hf.getStatusIcon(change.OldStatus), change.OldStatus, hf.getStatusIcon(change.NewStatus), change.NewStatus))
+			}
+		}
+	}
+
+	return output.String()
+}
+
+// formatVersionStatus renders a version and status pair such as "1.22.1 [ok]"
+func (hf *HumanFormatter) formatVersionStatus(version string, status checker.CheckStatus) string {
+	return fmt.Sprintf("%s [%s]", orDash(version), status)
+}
+
+// orDash returns "-" for empty values
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// FormatSelfCheck formats the findings of `goctor doctor env`
+func (hf *HumanFormatter) FormatSelfCheck(version string, findings []selfcheck.Finding) string {
+	var output strings.Builder
+
+	title := fmt.Sprintf("goctor %s self-check", version)
+	output.WriteString(title + "\n")
+	output.WriteString(strings.Repeat("=", len(title)) + "\n\n")
+
+	for _, finding := range findings {
+		var icon string
+		switch finding.Status {
+		case selfcheck.StatusOK:
+			icon = hf.colorize("✓", "green")
+		case selfcheck.StatusWarning:
+			icon = hf.colorize("⚠", "yellow")
+		case selfcheck.StatusFail:
+			icon = hf.colorize("✗", "red")
+		default:
+			icon = hf.colorize("-", "gray")
+		}
+		output.WriteString(fmt.Sprintf("%s %-11s %s\n", icon, finding.Name, finding.Message))
+	}
+
+	return output.String()
+}
+
+// FormatHistory formats the runs listed by `goctor history`, newest first
+func (hf *HumanFormatter) FormatHistory(entries []history.Entry) string {
+	var output strings.Builder
+
+	for _, entry := range entries {
+		icon := hf.colorize("✓", "green")
+		if entry.ExitCode != 0 {
+			icon = hf.colorize("✗", "red")
+		}
+
+		counts := []string{fmt.Sprintf("%d ok", entry.Summary.OK)}
+		for _, count := range []struct {
+			n     int
+			label string
+		}{
+			{entry.Summary.Missing, "missing"},
+			{entry.Summary.Outdated, "outdated"},
+			{entry.Summary.Errors, "errors"},
+		} {
+			if count.n > 0 {
+				counts = append(counts, fmt.Sprintf("%d %s", count.n, count.label))
+			}
+		}
+
+		output.WriteString(fmt.Sprintf("%s  %s  %s %-32s %s\n",
+			entry.ID, entry.Time.Local().Format("2006-01-02 15:04:05"), icon, strings.Join(counts, ", "), hf.colorize(entry.ManifestSource, "gray")))
+	}
+
+	return output.String()
+}
+
+// FormatLinkReport formats the dead links found by `goctor lint --check-links`
+func (hf *HumanFormatter) FormatLinkReport(report links.Report) string {
+	var output strings.Builder
+
+	if len(report.Dead) == 0 {
+		output.WriteString(fmt.Sprintf("%s All %d links answered\n", hf.colorize("✓", "green"), report.Checked))
+	} else {
+		output.WriteString(fmt.Sprintf("%s %d of %d links are dead:\n", hf.colorize("✗", "red"), len(report.Dead), report.Checked))
+		for _, dead := range report.Dead {
+			output.WriteString(fmt.Sprintf("  %s %s: %s %s\n",
+				dead.ToolID, dead.LinkType, dead.URL, hf.colorize("("+dead.Error+")", "gray")))
+		}
+	}
+
+	if len(report.Skipped) > 0 {
+		output.WriteString(fmt.Sprintf("Skipped %d logical links without a --link-resolver:\n", len(report.Skipped)))
+		for _, ref := range report.Skipped {
+			output.WriteString(fmt.Sprintf("  %s %s: %s\n", ref.ToolID, ref.LinkType, ref.URL))
+		}
+	}
+
+	return output.String()
+}
+
+// FormatLintFindings formats the best-practice findings of `goctor lint`
+func (hf *HumanFormatter) FormatLintFindings(findings []manifest.LintFinding) string {
+	if len(findings) == 0 {
+		return fmt.Sprintf("%s No lint findings\n", hf.colorize("✓", "green"))
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%s %d lint findings:\n", hf.colorize("!", "yellow"), len(findings)))
+	for _, finding := range findings {
+		output.WriteString(fmt.Sprintf("  %s: %s %s\n", cmp.Or(finding.ToolID, "manifest"), finding.Message, hf.colorize("("+finding.Rule+")", "gray")))
+	}
+	return output.String()
+}
+
+// FormatOutdated formats `goctor doctor outdated`, flagging tools whose required or installed
+// version lags the latest release by at least threshold
+func (hf *HumanFormatter) FormatOutdated(findings []upstream.Finding, threshold upstream.Lag) string {
+	var output strings.Builder
+
+	outdated := 0
+	for _, f := range findings {
+		switch {
+		case f.Error != "":
+			output.WriteString(fmt.Sprintf("%s %s (%s): %s\n", hf.colorize("!", "yellow"), f.ToolName, f.ToolID, f.Error))
+		case f.Outdated(threshold):
+			outdated++
+			output.WriteString(fmt.Sprintf("%s %s (%s): latest %s from %s\n", hf.colorize("✗", "red"), f.ToolName, f.ToolID, f.Latest, f.Source))
+			if f.RequiredLag.AtLeast(threshold) {
+				output.WriteString(fmt.Sprintf("    required %s is a %s version behind\n", f.Required, f.RequiredLag))
+			}
+			if f.InstalledLag.AtLeast(threshold) {
+				output.WriteString(fmt.Sprintf("    installed %s is a %s version behind\n", f.Installed, f.InstalledLag))
+			}
+		default:
+			output.WriteString(fmt.Sprintf("%s %s (%s): latest %s from %s\n", hf.colorize("✓", "green"), f.ToolName, f.ToolID, f.Latest, f.Source))
+		}
+	}
+
+	output.WriteString(fmt.Sprintf("\n%d of %d tools are at least a %s version behind upstream\n", outdated, len(findings), threshold))
+	return output.String()
+}
+
+// FormatPaths formats the directories listed by `goctor doctor paths`
+func (hf *HumanFormatter) FormatPaths(entries []paths.Entry) string {
+	var output strings.Builder
+
+	for _, entry := range entries {
+		source := ""
+		if entry.Source != "default" {
+			source = hf.colorize(" (from "+entry.Source+")", "gray")
+		}
+		output.WriteString(fmt.Sprintf("%-13s %s%s\n", entry.Name, entry.Path, source))
+	}
+
+	return output.String()
+}
+
+// FormatCheckPlans formats what `goctor doctor --dry-run` would run for each tool
+func (hf *HumanFormatter) FormatCheckPlans(plans []checker.CheckPlan, manifestSource string) string {
+	var output strings.Builder
+
+	output.WriteString(fmt.Sprintf("Dry run of %s: nothing was executed\n", manifestSource))
+	for _, plan := range plans {
+		output.WriteString(fmt.Sprintf("\n%s (%s)\n", plan.ToolName, plan.ToolID))
+		if plan.SkipReason != "" {
+			output.WriteString("  " + hf.colorize("Skipped: "+plan.SkipReason, "gray") + "\n")
+			continue
+		}
+		hf.formatCheckPlan(&output, plan, "  ")
+	}
+
+	return output.String()
+}
+
+// formatCheckPlan writes the details of one plan, and those of its sub-checks further indented
+func (hf *HumanFormatter) formatCheckPlan(output *strings.Builder, plan checker.CheckPlan, indent string) {
+	line := func(label, value string) {
+		output.WriteString(fmt.Sprintf("%s%-12s%s\n", indent, label+":", value))
+	}
+
+	if plan.Type != manifest.CheckTypeCommand {
+		line("Type", plan.Type)
+	}
+	if plan.Plugin != "" {
+		line("Plugin", plan.Plugin)
+	}
+	for _, key := range sortedKeys(plan.GitConfig) {
+		line("Config", fmt.Sprintf("git config --get %s =~ %s", key, plan.GitConfig[key]))
+	}
+	if plan.Var != "" {
+		variable := plan.Var
+		if plan.Exists != "" {
+			variable += " names an existing " + plan.Exists
+		}
+		if plan.Sensitive {
+			variable += ", value masked"
+		}
+		line("Variable", variable)
+	}
+	if len(plan.Command) > 0 {
+		line("Command", strings.Join(plan.Command, " "))
+	}
+	for _, fallback := range plan.Fallbacks {
+		line("Fallback", strings.Join(fallback, " "))
+	}
+	if plan.ExpectSHA256 != "" {
+		line("SHA-256", plan.ExpectSHA256)
+	}
+	if plan.Shell {
+		line("Shell", "yes")
+	}
+	if plan.Workdir != "" {
+		line("Workdir", plan.Workdir)
+	}
+	for _, name := range sortedKeys(plan.Env) {
+		line("Env", name+"="+plan.Env[name])
+	}
+	if plan.Timeout > 0 {
+		timeout := time.Duration(plan.Timeout).String()
+		if plan.DetectTimeout > 0 {
+			timeout += fmt.Sprintf(" (lookup %s)", time.Duration(plan.DetectTimeout))
+		}
+		line("Timeout", timeout)
+	}
+	if plan.Regex != "" {
+		line("Regex", plan.Regex)
+	}
+	if plan.CaptureGroup != "" {
+		line("Capture", plan.CaptureGroup)
+	}
+	if len(plan.ExpectExitCodes) > 0 {
+		codes := make([]string, len(plan.ExpectExitCodes))
+		for i, code := range plan.ExpectExitCodes {
+			codes[i] = fmt.Sprint(code)
+		}
+		line("Exit codes", strings.Join(codes, ", "))
+	}
+	if plan.Constraint != "" {
+		constraint := plan.Constraint
+		if plan.VersionScheme != "" {
+			constraint += " (" + plan.VersionScheme + ")"
+		}
+		if plan.ConstraintFrom != "" {
+			constraint += " from " + plan.ConstraintFrom
+		}
+		if plan.AllowPrerelease {
+			constraint += ", prereleases allowed"
+		}
+		line("Constraint", constraint)
+	} else if plan.ConstraintFrom != "" {
+		line("Constraint", "from "+plan.ConstraintFrom)
+	}
+	if plan.Recommended != "" {
+		line("Recommended", plan.Recommended)
+	}
+	for _, excluded := range plan.ExcludeVersions {
+		line("Excluded", excluded.String())
+	}
+	if plan.Error != "" {
+		output.WriteString(fmt.Sprintf("%s%s %s\n", indent, hf.colorize("Error:", "red"), plan.Error))
+	}
+	for _, sub := range plan.SubChecks {
+		output.WriteString(fmt.Sprintf("%sSub-check %s\n", indent, sub.ToolName))
+		hf.formatCheckPlan(output, sub, indent+"  ")
+	}
+	for _, variant := range plan.Variants {
+		output.WriteString(fmt.Sprintf("%sVariant %s\n", indent, variant.ToolName))
+		hf.formatCheckPlan(output, variant, indent+"  ")
+	}
+}
+
 // formatHeader creates the report header
 func (hf *HumanFormatter) formatHeader(report checker.EnvironmentReport) string {
 	var header strings.Builder
 
-	header.WriteString("Development Environment Check\n")
-	header.WriteString("============================\n")
+	header.WriteString(hf.heading(hf.printer.Sprintf("report.title"), "="))
 
-	// Try to extract platform info if it's the right type
-	if platformMap, ok := report.Platform.(map[string]interface{}); ok {
-		if os, exists := platformMap["os"]; exists {
-			if arch, exists := platformMap["arch"]; exists {
-				header.WriteString(fmt.Sprintf("Platform: %s/%s\n", os, arch))
-			}
-		}
+	if info, ok := reportPlatform(report); ok {
+		header.WriteString(hf.printer.Sprintf("report.platform", info.OS, info.Architecture) + "\n")
 	}
 
-	header.WriteString(fmt.Sprintf("Manifest: %s\n", report.ManifestSource))
-	header.WriteString(fmt.Sprintf("Generated: %s\n", report.GeneratedAt.Format("2006-01-02 15:04:05")))
+	header.WriteString(hf.printer.Sprintf("report.manifest", report.ManifestSource) + "\n")
+	header.WriteString(hf.printer.Sprintf("report.generated", report.GeneratedAt.Format("2006-01-02 15:04:05")) + "\n")
 
 	return header.String()
 }
@@ -98,81 +612,205 @@ func (hf *HumanFormatter) formatHeader(report checker.EnvironmentReport) string
 func (hf *HumanFormatter) formatSummary(summary checker.CheckSummary) string {
 	var output strings.Builder
 
-	output.WriteString("Summary:\n")
-	output.WriteString("--------\n")
+	output.WriteString(hf.heading(hf.printer.Sprintf("summary.title"), "-"))
 
-	output.WriteString(fmt.Sprintf("Total tools: %d\n", summary.Total))
+	output.WriteString(hf.printer.Sprintf("summary.total", summary.Total) + "\n")
 
 	if summary.OK > 0 {
-		output.WriteString(fmt.Sprintf("%s %d tools OK\n",
-			hf.colorize("✓", "green"), summary.OK))
+		output.WriteString(hf.colorize("✓", "green") + " " + hf.printer.Sprintf("summary.ok", summary.OK) + "\n")
 	}
 
 	if summary.Missing > 0 {
-		output.WriteString(fmt.Sprintf("%s %d tools missing\n",
-			hf.colorize("✗", "red"), summary.Missing))
+		output.WriteString(hf.colorize("✗", "red") + " " + hf.printer.Sprintf("summary.missing", summary.Missing) + "\n")
 	}
 
 	if summary.Outdated > 0 {
-		output.WriteString(fmt.Sprintf("%s %d tools outdated\n",
-			hf.colorize("⚠", "yellow"), summary.Outdated))
+		output.WriteString(hf.colorize("⚠", "yellow") + " " + hf.printer.Sprintf("summary.outdated", summary.Outdated) + "\n")
 	}
 
 	if summary.Errors > 0 {
-		output.WriteString(fmt.Sprintf("%s %d tools with errors\n",
-			hf.colorize("!", "red"), summary.Errors))
+		output.WriteString(hf.colorize("!", "red") + " " + hf.printer.Sprintf("summary.errors", summary.Errors) + "\n")
+	}
+
+	if summary.Skipped > 0 {
+		output.WriteString(hf.colorize("-", "gray") + " " + hf.printer.Sprintf("summary.skipped", summary.Skipped) + "\n")
+	}
+
+	if summary.Warnings > 0 {
+		output.WriteString(hf.colorize("⚠", "yellow") + " " + hf.printer.Sprintf("summary.warnings", summary.Warnings) + "\n")
+	}
+
+	if summary.Informational > 0 {
+		output.WriteString(hf.colorize("i", "blue") + " " + hf.printer.Sprintf("summary.informational", summary.Informational) + "\n")
 	}
 
 	return output.String()
 }
 
 // formatToolResults creates the detailed tool results section
+// When the manifest scopes its tools, machine and project tools are listed apart, since they
+// are fixed in different ways and often by different people
 func (hf *HumanFormatter) formatToolResults(items []checker.CheckResult) string {
 	var output strings.Builder
 
-	output.WriteString("\nDetailed Results:\n")
-	output.WriteString("-----------------\n")
+	output.WriteString("\n")
+	output.WriteString(hf.heading(hf.printer.Sprintf("results.title"), "-"))
 
-	for _, item := range items {
-		output.WriteString(hf.formatSingleResult(item))
-		output.WriteString("\n")
+	if len(items) == 0 && hf.view == ViewFailures {
+		output.WriteString(hf.printer.Sprintf("results.none") + "\n")
+	}
+
+	if !slices.ContainsFunc(items, func(item checker.CheckResult) bool { return item.Scope != "" }) {
+		for _, item := range items {
+			output.WriteString(hf.formatSingleResult(item))
+			output.WriteString("\n")
+		}
+		return output.String()
+	}
+
+	for _, scope := range []string{manifest.ScopeMachine, manifest.ScopeProject, ""} {
+		title := hf.printer.Sprintf("results.scope." + cmp.Or(scope, "none"))
+		written := false
+		for _, item := range items {
+			if item.Scope != scope {
+				continue
+			}
+			if !written {
+				output.WriteString(title + "\n")
+				written = true
+			}
+			output.WriteString(hf.formatSingleResult(item))
+			output.WriteString("\n")
+		}
 	}
 
 	return output.String()
 }
 
+// formatSubResults formats one line per sub-check or variant of a tool
+func (hf *HumanFormatter) formatSubResults(subs []checker.SubCheckResult) string {
+	var output strings.Builder
+	for _, sub := range subs {
+		line := fmt.Sprintf("    %s %s", hf.getStatusIcon(sub.Status), sub.Name)
+		if sub.ActualVersion != "" {
+			line += " " + sub.ActualVersion
+		}
+		if sub.RequiredVersion != "" {
+			line += " " + hf.printer.Sprintf("result.subcheck_required", sub.RequiredVersion)
+		}
+		if sub.Status != checker.StatusOK && sub.ErrorMessage != "" {
+			line += ": " + sub.ErrorMessage
+		}
+		output.WriteString(line + "\n")
+	}
+	return output.String()
+}
+
 // formatSingleResult formats a single tool check result
 func (hf *HumanFormatter) formatSingleResult(result checker.CheckResult) string {
 	var output strings.Builder
 
 	// Status icon and tool name
 	icon := hf.getStatusIcon(result.Status)
-	output.WriteString(fmt.Sprintf("%s %s (%s)\n",
-		icon, result.ToolName, result.ToolID))
+	if result.Status == checker.StatusSkipped {
+		output.WriteString(fmt.Sprintf("%s %s (%s) %s\n",
+			icon, result.ToolName, result.ToolID, hf.colorize(hf.printer.Sprintf("result.skipped", result.SkipReason), "gray")))
+		return output.String()
+	}
+	if result.Informational {
+		icon = hf.colorize("i", "blue")
+		output.WriteString(fmt.Sprintf("%s %s (%s) %s\n",
+			icon, result.ToolName, result.ToolID, hf.printer.Sprintf("result.informational")))
+	} else if result.Severity == manifest.SeverityWarning {
+		output.WriteString(fmt.Sprintf("%s %s (%s) %s\n",
+			icon, result.ToolName, result.ToolID, hf.printer.Sprintf("result.warning")))
+	} else {
+		output.WriteString(fmt.Sprintf("%s %s (%s)\n",
+			icon, result.ToolName, result.ToolID))
+	}
 
 	// Version information
 	if result.ActualVersion != "" {
-		output.WriteString(fmt.Sprintf("  Installed: %s\n", result.ActualVersion))
+		output.WriteString("  " + hf.printer.Sprintf("result.installed", result.ActualVersion) + "\n")
+	}
+	if result.RequiredVersion != "" {
+		output.WriteString("  " + hf.printer.Sprintf("result.required", result.RequiredVersion) + "\n")
+	}
+	if result.RecommendedVersion != "" {
+		output.WriteString("  " + hf.printer.Sprintf("result.recommended", result.RecommendedVersion) + "\n")
 	}
-	output.WriteString(fmt.Sprintf("  Required:  %s\n", result.RequiredVersion))
 
 	// Path information
 	if result.CommandPath != "" {
-		output.WriteString(fmt.Sprintf("  Path:      %s\n", result.CommandPath))
+		output.WriteString("  " + hf.printer.Sprintf("result.path", result.CommandPath) + "\n")
+	}
+	if result.ResolvedCommand != "" {
+		output.WriteString("  " + hf.printer.Sprintf("result.resolved_command", result.ResolvedCommand) + "\n")
+	}
+	if result.ManagedBy != "" {
+		output.WriteString("  " + hf.printer.Sprintf("result.managed_by", result.ManagedBy) + "\n")
+	}
+	if result.Source != "" {
+		output.WriteString("  " + hf.printer.Sprintf("result.source", result.Source) + "\n")
+	}
+	if hf.durations || hf.verbose {
+		took := time.Duration(result.CheckDuration).Round(time.Millisecond)
+		output.WriteString("  " + hf.printer.Sprintf("result.duration", took) + "\n")
+	}
+	if hf.verbose && len(result.Trace) > 0 {
+		output.WriteString("  " + hf.printer.Sprintf("result.trace") + "\n")
+		for _, step := range result.Trace {
+			output.WriteString("    " + step.String() + "\n")
+			if step.Output != "" {
+				for _, line := range strings.Split(strings.TrimRight(step.Output, "\n"), "\n") {
+					output.WriteString(fmt.Sprintf("      | %s\n", line))
+				}
+			}
+		}
 	}
 
 	// Error message if present
 	if result.ErrorMessage != "" {
 		output.WriteString(fmt.Sprintf("  %s %s\n",
-			hf.colorize("Error:", "red"), result.ErrorMessage))
+			hf.colorize(hf.printer.Sprintf("result.error"), "red"), result.ErrorMessage))
+	}
+
+	// Sub-check and variant details
+	if len(result.SubChecks) > 0 {
+		output.WriteString("  " + hf.printer.Sprintf("result.subchecks") + "\n")
+		output.WriteString(hf.formatSubResults(result.SubChecks))
+	}
+	if len(result.Variants) > 0 {
+		output.WriteString("  " + hf.printer.Sprintf("result.variants") + "\n")
+		output.WriteString(hf.formatSubResults(result.Variants))
 	}
 
 	// Status-specific messages
 	switch result.Status {
 	case checker.StatusNotFound:
-		output.WriteString("  Tool not found in PATH\n")
+		output.WriteString("  " + hf.printer.Sprintf("result.not_found") + "\n")
 	case checker.StatusOutdated:
-		output.WriteString("  Installed version does not meet requirements\n")
+		if result.Blocked != nil {
+			output.WriteString("  " + hf.printer.Sprintf("result.blocked") + "\n")
+		} else {
+			output.WriteString("  " + hf.printer.Sprintf("result.outdated") + "\n")
+		}
+	case checker.StatusOK:
+		if result.BelowRecommended {
+			output.WriteString(fmt.Sprintf("  %s %s\n",
+				hf.colorize("⚠", "yellow"), hf.printer.Sprintf("result.below_recommended", result.RecommendedVersion)))
+		}
+	}
+
+	// Deprecation notices
+	if result.Deprecated {
+		output.WriteString(fmt.Sprintf("  %s %s\n", hf.colorize("⚠", "yellow"), hf.printer.Sprintf("result.deprecated")))
+		if migration := result.Links[manifest.MigrationLink]; migration != "" {
+			output.WriteString("  " + hf.printer.Sprintf("result.migration", migration) + "\n")
+		}
+	}
+	if result.Sunset != "" {
+		output.WriteString("  " + hf.printer.Sprintf("result.sunset", result.Sunset) + "\n")
 	}
 
 	return output.String()
@@ -182,28 +820,37 @@ func (hf *HumanFormatter) formatSingleResult(result checker.CheckResult) string
 func (hf *HumanFormatter) formatRecommendations(items []checker.CheckResult) string {
 	var output strings.Builder
 
-	output.WriteString("Recommendations:\n")
-	output.WriteString("----------------\n")
+	output.WriteString(hf.heading(hf.printer.Sprintf("recommendations.title"), "-"))
 
 	for _, item := range items {
-		if item.Status == checker.StatusOK {
+		if !item.NeedsAttention() {
 			continue
 		}
 
 		output.WriteString(fmt.Sprintf("\n%s (%s):\n", item.ToolName, item.ToolID))
 
-		switch item.Status {
-		case checker.StatusNotFound:
-			output.WriteString("  Install this tool to continue development\n")
-		case checker.StatusOutdated:
-			output.WriteString(fmt.Sprintf("  Update to version %s or later\n", item.RequiredVersion))
-		case checker.StatusError:
-			output.WriteString("  Check tool installation and PATH configuration\n")
+		switch {
+		case item.Deprecated:
+			output.WriteString("  " + hf.printer.Sprintf("recommendation.deprecated") + "\n")
+		case item.Blocked != nil:
+			output.WriteString("  " + hf.printer.Sprintf("recommendation.blocked") + "\n")
+		case item.Status == checker.StatusOK:
+			output.WriteString("  " + hf.printer.Sprintf("recommendation.upgrade", item.RecommendedVersion) + "\n")
+		case item.Status == checker.StatusNotFound:
+			output.WriteString("  " + hf.printer.Sprintf("recommendation.install") + "\n")
+		case item.Status == checker.StatusOutdated:
+			output.WriteString("  " + hf.printer.Sprintf("recommendation.update", item.RequiredVersion) + "\n")
+		case item.Status == checker.StatusError:
+			output.WriteString("  " + hf.printer.Sprintf("recommendation.check_install") + "\n")
+		}
+
+		if item.InstallHint != "" {
+			output.WriteString("  " + hf.printer.Sprintf("recommendation.install_hint", item.InstallHint) + "\n")
 		}
 
 		// Add helpful links
 		if len(item.Links) > 0 {
-			output.WriteString("  Links:\n")
+			output.WriteString("  " + hf.printer.Sprintf("links") + "\n")
 			for linkType, url := range item.Links {
 				output.WriteString(fmt.Sprintf("    %s: %s\n", strings.Title(linkType), url))
 			}
@@ -213,6 +860,25 @@ func (hf *HumanFormatter) formatRecommendations(items []checker.CheckResult) str
 	return output.String()
 }
 
+// statusWord returns the status in the formatter's language
+func (hf *HumanFormatter) statusWord(status checker.CheckStatus) string {
+	return hf.printer.Sprintf("status." + status.String())
+}
+
+// heading returns title underlined to its display width
+// Characters encoded in three or more bytes, such as CJK text, are counted as two columns
+func (hf *HumanFormatter) heading(title, underline string) string {
+	width := 0
+	for _, r := range title {
+		if utf8.RuneLen(r) >= 3 {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return title + "\n" + strings.Repeat(underline, width) + "\n"
+}
+
 // getStatusIcon returns an appropriate icon for the status
 func (hf *HumanFormatter) getStatusIcon(status checker.CheckStatus) string {
 	switch status {
@@ -224,6 +890,8 @@ func (hf *HumanFormatter) getStatusIcon(status checker.CheckStatus) string {
 		return hf.colorize("⚠", "yellow")
 	case checker.StatusError:
 		return hf.colorize("!", "red")
+	case checker.StatusSkipped:
+		return hf.colorize("-", "gray")
 	default:
 		return hf.colorize("?", "gray")
 	}
@@ -253,10 +921,34 @@ func (hf *HumanFormatter) colorize(text, color string) string {
 
 // FormatQuickSummary provides a brief one-line summary
 func (hf *HumanFormatter) FormatQuickSummary(summary checker.CheckSummary) string {
+	// Skipped tools were not checked, so they are neither ready nor in need of attention
+	checked := summary.Total - summary.Skipped
 	if summary.Missing == 0 && summary.Outdated == 0 && summary.Errors == 0 {
-		return hf.colorize(fmt.Sprintf("✓ All %d tools are ready", summary.Total), "green")
+		return hf.colorize("✓ "+hf.printer.Sprintf("quick.ready", checked), "green")
 	}
 
 	issues := summary.Missing + summary.Outdated + summary.Errors
-	return hf.colorize(fmt.Sprintf("✗ %d of %d tools need attention", issues, summary.Total), "red")
-}
\ No newline at end of file
+	return hf.colorize("✗ "+hf.printer.Sprintf("quick.attention", issues, checked), "red")
+}
+
+// FormatTransition formats a tool that was fixed or broke between two watch checks as one
+// timestamped line, with the hook failure on a second line if its hook failed
+func (hf *HumanFormatter) FormatTransition(transition checker.Transition, result checker.CheckResult, at time.Time) string {
+	icon, key := hf.getStatusIcon(transition.To), "watch.broke"
+	if transition.Kind == checker.TransitionRecover {
+		key = "watch.fixed"
+	}
+
+	line := fmt.Sprintf("%s %s %s", hf.colorize(at.Local().Format("15:04:05"), "gray"), icon,
+		hf.printer.Sprintf(key, result.ToolName, result.ToolID, hf.statusWord(transition.From), hf.statusWord(transition.To)))
+	if result.ActualVersion != "" {
+		line += " (" + result.ActualVersion + ")"
+	}
+	line += "\n"
+
+	if transition.HookError != "" {
+		line += "         " + hf.colorize(transition.HookError, "red") + "\n"
+	}
+
+	return line
+}
@@ -2,6 +2,7 @@ package output
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/ikorihn/goctor/internal/checker"
@@ -29,12 +30,13 @@ func (jf *JSONFormatter) SetPrettyPrint(enabled bool) {
 func (jf *JSONFormatter) FormatEnvironmentReport(report checker.EnvironmentReport) (string, error) {
 	// Convert to JSON-friendly format
 	jsonReport := JSONEnvironmentReport{
-		SchemaVersion:  report.SchemaVersion,
-		Platform:       report.Platform,
-		Summary:        report.Summary,
-		ManifestSource: report.ManifestSource,
-		Items:          make([]JSONCheckResult, len(report.Items)),
-		GeneratedAt:    report.GeneratedAt,
+		SchemaVersion:   report.SchemaVersion,
+		Platform:        report.Platform,
+		Summary:         report.Summary,
+		ManifestSource:  report.ManifestSource,
+		ManifestSources: report.ManifestSources,
+		Items:           make([]JSONCheckResult, len(report.Items)),
+		GeneratedAt:     report.GeneratedAt,
 	}
 
 	// Convert check results
@@ -55,14 +57,22 @@ func (jf *JSONFormatter) FormatToolList(tools []manifest.ToolDefinition, manifes
 
 	for i, tool := range tools {
 		response.Tools[i] = JSONTool{
-			ID:              tool.ID,
-			Name:            tool.Name,
-			RequiredVersion: tool.RequiredVersion,
-			Rationale:       tool.Rationale,
-			CheckCommand:    tool.CheckCommand(),
-			VersionRegex:    tool.VersionRegex(),
-			Links:           tool.Links,
-			TimeoutSeconds:  tool.TimeoutSeconds,
+			ID:                 tool.ID,
+			Name:               tool.Name,
+			RequiredVersion:    tool.RequiredVersion,
+			RecommendedVersion: tool.RecommendedVersion,
+			Rationale:          tool.Rationale,
+			CheckCommand:       tool.CheckCommand(),
+			VersionRegex:       tool.VersionRegex(),
+			Plugin:             tool.Check.Plugin,
+			Links:              tool.Links,
+			TimeoutSeconds:     tool.TimeoutSeconds,
+			Informational:      tool.Informational,
+			Severity:           tool.Severity,
+			Tags:               tool.Tags,
+			Scope:              tool.Scope,
+			Platforms:          tool.Platforms,
+			Install:            tool.Install,
 		}
 	}
 
@@ -77,18 +87,52 @@ func (jf *JSONFormatter) FormatCheckSummary(summary checker.CheckSummary) (strin
 // convertCheckResult converts internal CheckResult to JSON-friendly format
 func (jf *JSONFormatter) convertCheckResult(result checker.CheckResult) JSONCheckResult {
 	return JSONCheckResult{
-		ToolID:          result.ToolID,
-		ToolName:        result.ToolName,
-		Status:          result.Status.String(),
-		RequiredVersion: result.RequiredVersion,
-		ActualVersion:   result.ActualVersion,
-		ErrorMessage:    result.ErrorMessage,
-		Platform:        result.Platform,
-		Links:           result.Links,
-		CheckDuration:   result.CheckDuration,
+		ToolID:             result.ToolID,
+		ToolName:           result.ToolName,
+		Status:             result.Status.String(),
+		RequiredVersion:    result.RequiredVersion,
+		ActualVersion:      result.ActualVersion,
+		RecommendedVersion: result.RecommendedVersion,
+		BelowRecommended:   result.BelowRecommended,
+		Deprecated:         result.Deprecated,
+		Sunset:             result.Sunset,
+		ManagedBy:          result.ManagedBy,
+		ErrorMessage:       result.ErrorMessage,
+		ErrorType:          result.ErrorType,
+		Platform:           result.Platform,
+		Links:              result.Links,
+		CheckDuration:      result.CheckDuration,
+		Informational:      result.Informational,
+		Severity:           result.Severity,
+		Scope:              result.Scope,
+		InstallHint:        result.InstallHint,
+		SubChecks:          jf.convertSubChecks(result.SubChecks),
+		Variants:           jf.convertSubChecks(result.Variants),
+		Source:             result.Source,
 	}
 }
 
+// convertSubChecks converts sub-check or variant results to JSON-friendly format
+func (jf *JSONFormatter) convertSubChecks(subChecks []checker.SubCheckResult) []JSONSubCheckResult {
+	if len(subChecks) == 0 {
+		return nil
+	}
+
+	converted := make([]JSONSubCheckResult, len(subChecks))
+	for i, sub := range subChecks {
+		converted[i] = JSONSubCheckResult{
+			Name:            sub.Name,
+			Status:          sub.Status.String(),
+			RequiredVersion: sub.RequiredVersion,
+			ActualVersion:   sub.ActualVersion,
+			CommandPath:     sub.CommandPath,
+			ErrorMessage:    sub.ErrorMessage,
+		}
+	}
+
+	return converted
+}
+
 // marshalJSON marshals data to JSON with appropriate formatting
 func (jf *JSONFormatter) marshalJSON(data interface{}) (string, error) {
 	var jsonData []byte
@@ -109,25 +153,49 @@ func (jf *JSONFormatter) marshalJSON(data interface{}) (string, error) {
 
 // JSONEnvironmentReport represents the JSON structure for environment reports
 type JSONEnvironmentReport struct {
-	SchemaVersion  int                `json:"schema_version"`
-	Platform       interface{}        `json:"platform"`
-	Summary        checker.CheckSummary `json:"summary"`
-	ManifestSource string             `json:"manifest_source"`
-	Items          []JSONCheckResult  `json:"items"`
-	GeneratedAt    time.Time          `json:"generated_at"`
+	SchemaVersion   int                  `json:"schema_version"`
+	Platform        interface{}          `json:"platform"`
+	Summary         checker.CheckSummary `json:"summary"`
+	ManifestSource  string               `json:"manifest_source"`
+	ManifestSources []string             `json:"manifest_sources,omitempty"`
+	Items           []JSONCheckResult    `json:"items"`
+	GeneratedAt     time.Time            `json:"generated_at"`
 }
 
 // JSONCheckResult represents the JSON structure for individual tool check results
 type JSONCheckResult struct {
-	ToolID          string            `json:"id"`
-	ToolName        string            `json:"name"`
-	Status          string            `json:"status"`
-	RequiredVersion string            `json:"required_version"`
-	ActualVersion   string            `json:"actual_version,omitempty"`
-	ErrorMessage    string            `json:"error_message,omitempty"`
-	Platform        string            `json:"platform"`
-	Links           map[string]string `json:"links"`
-	CheckDuration   time.Duration     `json:"check_duration_ms,omitempty"`
+	ToolID             string               `json:"id"`
+	ToolName           string               `json:"name"`
+	Status             string               `json:"status"`
+	RequiredVersion    string               `json:"required_version"`
+	ActualVersion      string               `json:"actual_version,omitempty"`
+	RecommendedVersion string               `json:"recommended_version,omitempty"`
+	BelowRecommended   bool                 `json:"below_recommended,omitempty"`
+	Deprecated         bool                 `json:"deprecated,omitempty"`
+	Sunset             string               `json:"sunset,omitempty"`
+	ManagedBy          string               `json:"managed_by,omitempty"`
+	ErrorMessage       string               `json:"error_message,omitempty"`
+	ErrorType          string               `json:"error_type,omitempty"`
+	Platform           string               `json:"platform"`
+	Links              map[string]string    `json:"links"`
+	CheckDuration      checker.Milliseconds `json:"check_duration_ms,omitempty"`
+	Informational      bool                 `json:"informational,omitempty"`
+	Severity           string               `json:"severity,omitempty"`
+	Scope              string               `json:"scope,omitempty"`
+	InstallHint        string               `json:"install_hint,omitempty"`
+	SubChecks          []JSONSubCheckResult `json:"sub_checks,omitempty"`
+	Variants           []JSONSubCheckResult `json:"variants,omitempty"`
+	Source             string               `json:"source,omitempty"`
+}
+
+// JSONSubCheckResult represents the JSON structure for a single sub-check or variant result
+type JSONSubCheckResult struct {
+	Name            string `json:"name"`
+	Status          string `json:"status"`
+	RequiredVersion string `json:"required_version,omitempty"`
+	ActualVersion   string `json:"actual_version,omitempty"`
+	CommandPath     string `json:"command_path,omitempty"`
+	ErrorMessage    string `json:"error_message,omitempty"`
 }
 
 // JSONToolListResponse represents the JSON structure for tool list responses
@@ -137,21 +205,53 @@ type JSONToolListResponse struct {
 	GeneratedAt    time.Time  `json:"generated_at"`
 }
 
+// ListSchemaVersion is the schema version of `list --json` output
+const ListSchemaVersion = 1
+
+// ListResponse is the JSON output of `list`
+type ListResponse struct {
+	SchemaVersion  int          `json:"schema_version"`
+	ManifestSource string       `json:"manifest_source"`
+	Tools          []ListedTool `json:"tools"`
+}
+
+// ListedTool is one tool in ListResponse; status and actual version are set with --with-status
+type ListedTool struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	RequiredVersion string   `json:"required_version"`
+	Rationale       string   `json:"rationale"`
+	Severity        string   `json:"severity,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	Scope           string   `json:"scope,omitempty"`
+	Platforms       []string `json:"platforms,omitempty"`
+	Status          string   `json:"status,omitempty"`
+	ActualVersion   string   `json:"actual_version,omitempty"`
+}
+
 // JSONTool represents the JSON structure for tool definitions
 type JSONTool struct {
-	ID              string            `json:"id"`
-	Name            string            `json:"name"`
-	RequiredVersion string            `json:"required_version"`
-	Rationale       string            `json:"rationale"`
-	CheckCommand    []string          `json:"check_command"`
-	VersionRegex    string            `json:"version_regex"`
-	Links           map[string]string `json:"links"`
-	TimeoutSeconds  int               `json:"timeout_seconds,omitempty"`
+	ID                 string            `json:"id"`
+	Name               string            `json:"name"`
+	RequiredVersion    string            `json:"required_version"`
+	RecommendedVersion string            `json:"recommended_version,omitempty"`
+	Rationale          string            `json:"rationale"`
+	CheckCommand       []string          `json:"check_command"`
+	VersionRegex       string            `json:"version_regex"`
+	Plugin             string            `json:"plugin,omitempty"`
+	Links              map[string]string `json:"links"`
+	TimeoutSeconds     int               `json:"timeout_seconds,omitempty"`
+	Informational      bool              `json:"informational,omitempty"`
+	Severity           string            `json:"severity,omitempty"`
+	Tags               []string          `json:"tags,omitempty"`
+	Scope              string            `json:"scope,omitempty"`
+	Platforms          []string          `json:"platforms,omitempty"`
+	Install            map[string]string `json:"install,omitempty"`
 }
 
 // Validate validates the JSON environment report structure
 func (jer *JSONEnvironmentReport) Validate() error {
-	if jer.SchemaVersion != 1 {
+	if jer.SchemaVersion != checker.ReportSchemaVersion {
 		return &ValidationError{
 			Field:   "schema_version",
 			Message: "unsupported schema version",
@@ -194,17 +294,29 @@ func (ve *ValidationError) Error() string {
 }
 
 // FormatError formats an error response as JSON
+// Manifest load errors also report their type, source, and the line and column when known
 func (jf *JSONFormatter) FormatError(err error, context string) (string, error) {
-	errorResponse := map[string]interface{}{
-		"error": map[string]interface{}{
-			"message":     err.Error(),
-			"context":     context,
-			"timestamp":   time.Now(),
-			"schema_version": 1,
-		},
+	details := map[string]interface{}{
+		"type":           "error",
+		"message":        err.Error(),
+		"context":        context,
+		"timestamp":      time.Now(),
+		"schema_version": 1,
 	}
 
-	return jf.marshalJSON(errorResponse)
+	var loadErr *manifest.LoadError
+	if errors.As(err, &loadErr) {
+		details["type"] = loadErr.Type
+		details["source"] = loadErr.Source
+		if loadErr.Line > 0 {
+			details["line"] = loadErr.Line
+		}
+		if loadErr.Column > 0 {
+			details["column"] = loadErr.Column
+		}
+	}
+
+	return jf.marshalJSON(map[string]interface{}{"error": details})
 }
 
 // FormatValidationErrors formats multiple validation errors as JSON
@@ -216,13 +328,13 @@ func (jf *JSONFormatter) FormatValidationErrors(errors []error) (string, error)
 
 	errorResponse := map[string]interface{}{
 		"error": map[string]interface{}{
-			"type":        "validation_error",
-			"message":     "Multiple validation errors occurred",
-			"details":     errorMessages,
-			"timestamp":   time.Now(),
+			"type":           "validation_error",
+			"message":        "Multiple validation errors occurred",
+			"details":        errorMessages,
+			"timestamp":      time.Now(),
 			"schema_version": 1,
 		},
 	}
 
 	return jf.marshalJSON(errorResponse)
-}
\ No newline at end of file
+}
@@ -0,0 +1,103 @@
+package output
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+// JSON Lines record types
+const (
+	JSONLinesResult     = "result"
+	JSONLinesSummary    = "summary"
+	JSONLinesTransition = "transition" // A tool fixed or broke in watch mode
+)
+
+// JSONLinesFormatter renders check results as JSON Lines, one record per line
+// Result records carry the same fields as report items plus "type": "result"; the run ends
+// with a single "type": "summary" record. A later result for the same tool ID supersedes
+// an earlier one (for example after --merge-results)
+type JSONLinesFormatter struct {
+	exitPolicy checker.ExitPolicy
+}
+
+// NewJSONLinesFormatter creates a new JSON Lines formatter
+func NewJSONLinesFormatter() *JSONLinesFormatter {
+	return &JSONLinesFormatter{exitPolicy: checker.DefaultExitPolicy}
+}
+
+// SetExitPolicy sets the policy used for the exit code in the summary record
+func (jlf *JSONLinesFormatter) SetExitPolicy(policy checker.ExitPolicy) {
+	jlf.exitPolicy = policy
+}
+
+// jsonLinesResult is a result record
+type jsonLinesResult struct {
+	Type string `json:"type"`
+	checker.CheckResult
+}
+
+// jsonLinesSummary is the final record of a run
+type jsonLinesSummary struct {
+	Type           string               `json:"type"`
+	SchemaVersion  int                  `json:"schema_version"`
+	Platform       interface{}          `json:"platform"`
+	ManifestSource string               `json:"manifest_source"`
+	Summary        checker.CheckSummary `json:"summary"`
+	ExitCode       int                  `json:"exit_code"`
+	GeneratedAt    time.Time            `json:"generated_at"`
+}
+
+// jsonLinesTransition is a watch mode record for a tool whose status changed
+type jsonLinesTransition struct {
+	Type       string    `json:"type"`
+	ToolID     string    `json:"id"`
+	Transition string    `json:"transition"`
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	Version    string    `json:"actual_version,omitempty"`
+	HookError  string    `json:"hook_error,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// FormatTransition formats a watch mode status change as a line
+func (jlf *JSONLinesFormatter) FormatTransition(transition checker.Transition, result checker.CheckResult, at time.Time) (string, error) {
+	return jlf.line(jsonLinesTransition{
+		Type:       JSONLinesTransition,
+		ToolID:     transition.ToolID,
+		Transition: transition.Kind.String(),
+		From:       transition.From.String(),
+		To:         transition.To.String(),
+		Version:    result.ActualVersion,
+		HookError:  transition.HookError,
+		Time:       at,
+	})
+}
+
+// FormatResult formats one check result as a line
+func (jlf *JSONLinesFormatter) FormatResult(result checker.CheckResult) (string, error) {
+	return jlf.line(jsonLinesResult{Type: JSONLinesResult, CheckResult: result})
+}
+
+// FormatSummary formats the closing summary line of a report
+func (jlf *JSONLinesFormatter) FormatSummary(report checker.EnvironmentReport) (string, error) {
+	return jlf.line(jsonLinesSummary{
+		Type:           JSONLinesSummary,
+		SchemaVersion:  report.SchemaVersion,
+		Platform:       report.Platform,
+		ManifestSource: report.ManifestSource,
+		Summary:        report.Summary,
+		ExitCode:       report.GetExitCode(jlf.exitPolicy),
+		GeneratedAt:    report.GeneratedAt,
+	})
+}
+
+// line marshals a record as compact JSON terminated by a newline
+func (jlf *JSONLinesFormatter) line(record interface{}) (string, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
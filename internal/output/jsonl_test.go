@@ -0,0 +1,102 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+// formatJSONLines renders a report the way doctor streams it: one result line per item, then
+// the summary line
+func formatJSONLines(t *testing.T, formatter *JSONLinesFormatter, report checker.EnvironmentReport) string {
+	t.Helper()
+
+	var output strings.Builder
+	for _, item := range report.Items {
+		line, err := formatter.FormatResult(item)
+		if err != nil {
+			t.Fatalf("FormatResult() error = %v", err)
+		}
+		output.WriteString(line)
+	}
+	line, err := formatter.FormatSummary(report)
+	if err != nil {
+		t.Fatalf("FormatSummary() error = %v", err)
+	}
+	output.WriteString(line)
+	return output.String()
+}
+
+func TestJSONLinesFormatter(t *testing.T) {
+	report := testReport()
+	got := formatJSONLines(t, NewJSONLinesFormatter(), report)
+	assertGolden(t, "report.jsonl", got)
+
+	// Every line is a single JSON object; results come in report order, the summary last
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != len(report.Items)+1 {
+		t.Fatalf("got %d lines, want %d", len(lines), len(report.Items)+1)
+	}
+	for i, line := range lines {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line %d is not a JSON object: %v\n%s", i+1, err, line)
+		}
+
+		if i < len(report.Items) {
+			if record["type"] != JSONLinesResult || record["id"] != report.Items[i].ToolID {
+				t.Errorf("line %d = %s, want the result of %s", i+1, line, report.Items[i].ToolID)
+			}
+		} else if record["type"] != JSONLinesSummary {
+			t.Errorf("last line = %s, want the summary", line)
+		}
+	}
+}
+
+func TestJSONLinesFormatterSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy checker.ExitPolicy
+		items  int
+		want   string
+	}{
+		{
+			name:   "failures with default policy",
+			policy: checker.DefaultExitPolicy,
+			items:  5,
+			want:   `{"type":"summary","schema_version":1,"platform":{"os":"linux","arch":"amd64"},"manifest_source":"tools.yaml","summary":{"total":5,"ok":2,"missing":1,"outdated":1,"errors":0,"skipped":1,"informational":0,"warnings":0},"exit_code":1,"generated_at":"2026-10-15T09:00:00Z"}` + "\n",
+		},
+		{
+			name:   "failures with granular policy",
+			policy: checker.GranularExitPolicy,
+			items:  5,
+			want:   `{"type":"summary","schema_version":1,"platform":{"os":"linux","arch":"amd64"},"manifest_source":"tools.yaml","summary":{"total":5,"ok":2,"missing":1,"outdated":1,"errors":0,"skipped":1,"informational":0,"warnings":0},"exit_code":2,"generated_at":"2026-10-15T09:00:00Z"}` + "\n",
+		},
+		{
+			name:   "all pass",
+			policy: checker.DefaultExitPolicy,
+			items:  1,
+			want:   `{"type":"summary","schema_version":1,"platform":{"os":"linux","arch":"amd64"},"manifest_source":"tools.yaml","summary":{"total":1,"ok":1,"missing":0,"outdated":0,"errors":0,"skipped":0,"informational":0,"warnings":0},"exit_code":0,"generated_at":"2026-10-15T09:00:00Z"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := testReport()
+			report.Items = report.Items[:tt.items]
+			report.Summary = checker.CalculateCheckSummary(report.Items)
+
+			formatter := NewJSONLinesFormatter()
+			formatter.SetExitPolicy(tt.policy)
+			got, err := formatter.FormatSummary(report)
+			if err != nil {
+				t.Fatalf("FormatSummary() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatSummary() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// JUnitFormatter provides JUnit XML output, which most CI systems render as test results
+type JUnitFormatter struct{}
+
+// NewJUnitFormatter creates a new JUnit formatter
+func NewJUnitFormatter() *JUnitFormatter {
+	return &JUnitFormatter{}
+}
+
+// JUnitTestSuites is the root element of a JUnit report
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite holds the test cases of one manifest
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is the result of one tool
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitProblem `xml:"failure,omitempty"`
+	Error     *JUnitProblem `xml:"error,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitProblem describes why a test case failed
+type JUnitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// JUnitSkipped marks a test case that did not run
+type JUnitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// FormatEnvironmentReport formats the report as a JUnit XML document with a test case per tool
+// Blocking problems fail their test case and tools that could not be checked are errors;
+// warnings, deprecated tools, and versions below the recommended one pass with the problem in
+// system-out, since they do not fail a doctor run either
+func (jf *JUnitFormatter) FormatEnvironmentReport(report checker.EnvironmentReport) (string, error) {
+	suite := JUnitTestSuite{
+		Name:  report.ManifestSource,
+		Cases: []JUnitTestCase{},
+	}
+	if !report.GeneratedAt.IsZero() {
+		suite.Timestamp = report.GeneratedAt.UTC().Format("2006-01-02T15:04:05")
+	}
+
+	var total time.Duration
+	for _, item := range report.Items {
+		testCase := JUnitTestCase{
+			Name:      fmt.Sprintf("%s (%s)", item.ToolName, item.ToolID),
+			ClassName: "goctor." + item.ToolID,
+			Time:      junitSeconds(time.Duration(item.CheckDuration)),
+		}
+		total += time.Duration(item.CheckDuration)
+
+		switch {
+		case item.Status == checker.StatusSkipped:
+			testCase.Skipped = &JUnitSkipped{Message: item.SkipReason}
+			suite.Skipped++
+		case !item.NeedsAttention():
+		case item.Status == checker.StatusOK || item.Severity == manifest.SeverityWarning || item.Deprecated:
+			testCase.SystemOut = problemDescription(item)
+		case item.Status == checker.StatusError:
+			testCase.Error = jf.problem(item)
+			suite.Errors++
+		default:
+			testCase.Failure = jf.problem(item)
+			suite.Failures++
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Tests = len(suite.Cases)
+	suite.Time = junitSeconds(total)
+
+	suites := JUnitTestSuites{
+		Name:     "goctor",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []JUnitTestSuite{suite},
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(data) + "\n", nil
+}
+
+// problem describes a failing tool, with the install hint in the body
+func (jf *JUnitFormatter) problem(item checker.CheckResult) *JUnitProblem {
+	problem := &JUnitProblem{Message: problemDescription(item), Type: item.Status.String()}
+	if item.InstallHint != "" {
+		problem.Body = "Install: " + item.InstallHint
+	}
+	return problem
+}
+
+// junitSeconds formats a duration as the seconds JUnit time attributes hold
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
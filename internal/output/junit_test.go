@@ -0,0 +1,52 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+func TestJUnitFormatter(t *testing.T) {
+	tests := []struct {
+		name   string
+		golden string
+		report func() checker.EnvironmentReport
+	}{
+		{
+			name:   "failures, errors, and skips",
+			golden: "report-junit.xml",
+			report: func() checker.EnvironmentReport {
+				report := testReport()
+				report.Items[0].CheckDuration = checker.Milliseconds(1500 * time.Millisecond)
+				report.Items = append(report.Items,
+					checker.CheckResult{ToolID: "terraform", ToolName: "Terraform", Status: checker.StatusError, ErrorMessage: "terraform version timed out after 5s"},
+					checker.CheckResult{ToolID: "shellcheck", ToolName: "ShellCheck", Status: checker.StatusNotFound, Severity: manifest.SeverityWarning, RequiredVersion: ">=0.9"},
+				)
+				report.Summary = checker.CalculateCheckSummary(report.Items)
+				return report
+			},
+		},
+		{
+			name:   "all pass",
+			golden: "report-success-junit.xml",
+			report: func() checker.EnvironmentReport {
+				report := testReport()
+				report.Items = report.Items[:1]
+				report.Summary = checker.CalculateCheckSummary(report.Items)
+				return report
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewJUnitFormatter().FormatEnvironmentReport(tt.report())
+			if err != nil {
+				t.Fatalf("FormatEnvironmentReport() error = %v", err)
+			}
+			assertGolden(t, tt.golden, got)
+		})
+	}
+}
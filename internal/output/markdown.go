@@ -0,0 +1,223 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+// MarkdownFormatter provides Markdown output formatting for sharing reports in PRs and wikis
+type MarkdownFormatter struct{}
+
+// NewMarkdownFormatter creates a new Markdown formatter
+func NewMarkdownFormatter() *MarkdownFormatter {
+	return &MarkdownFormatter{}
+}
+
+// FormatEnvironmentReport formats a complete environment report as Markdown
+func (mf *MarkdownFormatter) FormatEnvironmentReport(report checker.EnvironmentReport) string {
+	var output strings.Builder
+
+	// Header
+	output.WriteString("## Development Environment Check\n\n")
+	if info, ok := reportPlatform(report); ok {
+		output.WriteString(fmt.Sprintf("- **Platform:** %s/%s\n", info.OS, info.Architecture))
+	}
+	output.WriteString(fmt.Sprintf("- **Manifest:** `%s`\n", report.ManifestSource))
+	output.WriteString(fmt.Sprintf("- **Generated:** %s\n", report.GeneratedAt.Format("2006-01-02 15:04:05")))
+	output.WriteString(fmt.Sprintf("- **Summary:** %s\n\n", mf.formatSummary(report.Summary)))
+
+	// Results table
+	output.WriteString("| Status | Tool | Installed | Required |\n")
+	output.WriteString("| --- | --- | --- | --- |\n")
+	for _, item := range report.Items {
+		installed := item.ActualVersion
+		if installed == "" {
+			installed = "-"
+		}
+		required := "-"
+		if item.RequiredVersion != "" {
+			required = "`" + escapeMarkdownCell(item.RequiredVersion) + "`"
+		}
+		status := mf.getStatusLabel(item.Status)
+		if item.Status == checker.StatusSkipped {
+			status += " (" + escapeMarkdownCell(item.SkipReason) + ")"
+		} else if item.Informational {
+			status = "ℹ️ info"
+		} else if item.Deprecated {
+			status = "⚠️ deprecated"
+			if migration := item.Links[manifest.MigrationLink]; migration != "" {
+				status += fmt.Sprintf(" ([migration guide](%s))", migration)
+			}
+		} else if item.BelowRecommended {
+			status = "⚠️ below recommended"
+		}
+		output.WriteString(fmt.Sprintf("| %s | %s (`%s`) | %s | %s |\n",
+			status,
+			escapeMarkdownCell(item.ToolName),
+			item.ToolID,
+			escapeMarkdownCell(installed),
+			required))
+
+		for _, sub := range slices.Concat(item.SubChecks, item.Variants) {
+			subInstalled := sub.ActualVersion
+			if subInstalled == "" {
+				subInstalled = "-"
+			}
+			subRequired := "-"
+			if sub.RequiredVersion != "" {
+				subRequired = "`" + escapeMarkdownCell(sub.RequiredVersion) + "`"
+			}
+			output.WriteString(fmt.Sprintf("| %s | ↳ %s | %s | %s |\n",
+				mf.getStatusLabel(sub.Status),
+				escapeMarkdownCell(sub.Name),
+				escapeMarkdownCell(subInstalled),
+				subRequired))
+		}
+	}
+
+	// Remediation section for failed checks
+	if !report.IsSuccessful() {
+		output.WriteString("\n")
+		output.WriteString(mf.formatRemediation(report.Items))
+	}
+
+	return output.String()
+}
+
+// formatSummary creates a one-line summary of the check counts
+func (mf *MarkdownFormatter) formatSummary(summary checker.CheckSummary) string {
+	parts := []string{fmt.Sprintf("%d total", summary.Total)}
+
+	if summary.OK > 0 {
+		parts = append(parts, fmt.Sprintf("%d ok", summary.OK))
+	}
+	if summary.Missing > 0 {
+		parts = append(parts, fmt.Sprintf("%d missing", summary.Missing))
+	}
+	if summary.Outdated > 0 {
+		parts = append(parts, fmt.Sprintf("%d outdated", summary.Outdated))
+	}
+	if summary.Errors > 0 {
+		parts = append(parts, fmt.Sprintf("%d errors", summary.Errors))
+	}
+	if summary.Skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", summary.Skipped))
+	}
+	if summary.Warnings > 0 {
+		parts = append(parts, fmt.Sprintf("%d warnings", summary.Warnings))
+	}
+	if summary.Informational > 0 {
+		parts = append(parts, fmt.Sprintf("%d informational", summary.Informational))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// formatRemediation creates a collapsible remediation section with links
+func (mf *MarkdownFormatter) formatRemediation(items []checker.CheckResult) string {
+	var output strings.Builder
+
+	output.WriteString("<details>\n")
+	output.WriteString("<summary>Remediation</summary>\n\n")
+
+	for _, item := range items {
+		if !item.NeedsAttention() {
+			continue
+		}
+
+		output.WriteString(fmt.Sprintf("### %s (`%s`)\n\n", item.ToolName, item.ToolID))
+
+		switch {
+		case item.Deprecated:
+			output.WriteString("This tool is deprecated. Migrate away from it and uninstall it.\n")
+		case item.Blocked != nil:
+			output.WriteString("This version is blocked. Switch to a version that is not blocked.\n")
+		case item.Status == checker.StatusOK:
+			output.WriteString(fmt.Sprintf("Consider upgrading to a version matching `%s`.\n", item.RecommendedVersion))
+		case item.Status == checker.StatusNotFound, item.Status == checker.StatusMissing:
+			output.WriteString("Install this tool to continue development.\n")
+		case item.Status == checker.StatusOutdated:
+			output.WriteString(fmt.Sprintf("Update to a version matching `%s`.\n", item.RequiredVersion))
+		case item.Status == checker.StatusError:
+			output.WriteString("Check tool installation and PATH configuration.\n")
+		}
+
+		if item.InstallHint != "" {
+			output.WriteString(fmt.Sprintf("\n```sh\n%s\n```\n", item.InstallHint))
+		}
+
+		if item.ErrorMessage != "" {
+			output.WriteString(fmt.Sprintf("\n> %s\n", item.ErrorMessage))
+		}
+
+		if len(item.Links) > 0 {
+			output.WriteString("\n")
+			for _, linkType := range sortedKeys(item.Links) {
+				output.WriteString(fmt.Sprintf("- [%s](%s)\n", linkType, item.Links[linkType]))
+			}
+		}
+		output.WriteString("\n")
+	}
+
+	output.WriteString("</details>\n")
+
+	return output.String()
+}
+
+// getStatusLabel returns a Markdown-friendly label for the status
+func (mf *MarkdownFormatter) getStatusLabel(status checker.CheckStatus) string {
+	switch status {
+	case checker.StatusOK:
+		return "✅ ok"
+	case checker.StatusNotFound, checker.StatusMissing:
+		return "❌ missing"
+	case checker.StatusOutdated:
+		return "⚠️ outdated"
+	case checker.StatusError:
+		return "❗ error"
+	case checker.StatusSkipped:
+		return "⏭️ skipped"
+	default:
+		return "❔ unknown"
+	}
+}
+
+// escapeMarkdownCell escapes characters that would break a Markdown table cell
+func escapeMarkdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", "\\|")
+}
+
+// reportPlatform returns the platform a report was generated on; reports built in this run hold
+// a PlatformInfo, loaded ones a JSON object
+func reportPlatform(report checker.EnvironmentReport) (platform.PlatformInfo, bool) {
+	switch info := report.Platform.(type) {
+	case platform.PlatformInfo:
+		return info, info.OS != ""
+	case *platform.PlatformInfo:
+		return *info, info != nil && info.OS != ""
+	}
+
+	var info platform.PlatformInfo
+	data, err := json.Marshal(report.Platform)
+	if err != nil || json.Unmarshal(data, &info) != nil {
+		return platform.PlatformInfo{}, false
+	}
+	return info, info.OS != ""
+}
+
+// sortedKeys returns the keys of a string map in a stable order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,96 @@
+package output
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// testReport returns a report with a result of every kind the formatters render
+func testReport() checker.EnvironmentReport {
+	items := []checker.CheckResult{
+		{ToolID: "go", ToolName: "Go", Status: checker.StatusOK, RequiredVersion: ">=1.22", ActualVersion: "1.23.4"},
+		{
+			ToolID:          "node",
+			ToolName:        "Node.js",
+			Status:          checker.StatusOutdated,
+			RequiredVersion: ">=18 | <3",
+			ActualVersion:   "16.20.0",
+			Links:           map[string]string{"homepage": "https://nodejs.org/"},
+		},
+		{
+			ToolID:          "jq",
+			ToolName:        "jq | JSON processor",
+			Status:          checker.StatusNotFound,
+			RequiredVersion: ">=1.6",
+			InstallHint:     "brew install jq",
+			ErrorMessage:    "jq not found in PATH",
+		},
+		{
+			ToolID:   "docker",
+			ToolName: "Docker",
+			Status:   checker.StatusOK,
+			SubChecks: []checker.SubCheckResult{
+				{Name: "compose", Status: checker.StatusOK, RequiredVersion: ">=2.20", ActualVersion: "2.24.1"},
+				{Name: "buildx", Status: checker.StatusMissing, RequiredVersion: ">=0.11"},
+			},
+		},
+		{ToolID: "xcode", ToolName: "Xcode", Status: checker.StatusSkipped, SkipReason: "only for darwin"},
+	}
+
+	report := checker.NewEnvironmentReport(platform.PlatformInfo{OS: "linux", Architecture: "amd64"}, "tools.yaml", items)
+	report.GeneratedAt = time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	return *report
+}
+
+// assertGolden compares got with testdata/name, rewriting the file with -update
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file (run go test -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("Output differs from %s (run go test -update to accept it):\n%s", path, got)
+	}
+}
+
+func TestMarkdownFormatter(t *testing.T) {
+	report := testReport()
+	assertGolden(t, "report.md", NewMarkdownFormatter().FormatEnvironmentReport(report))
+
+	// Reports loaded from JSON hold the platform as a JSON object and render the same
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded checker.EnvironmentReport
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "report.md", NewMarkdownFormatter().FormatEnvironmentReport(loaded))
+}
+
+func TestMarkdownFormatterSuccess(t *testing.T) {
+	report := testReport()
+	report.Items = report.Items[:1]
+	report.Summary = checker.CalculateCheckSummary(report.Items)
+	report.Platform = nil
+	assertGolden(t, "report-success.md", NewMarkdownFormatter().FormatEnvironmentReport(report))
+}
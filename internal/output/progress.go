@@ -0,0 +1,115 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// Progress formats accepted by --progress-format
+const (
+	ProgressNone = "none"
+	ProgressJSON = "json"
+)
+
+// ProgressFormats lists the supported progress formats
+var ProgressFormats = []string{ProgressNone, ProgressJSON}
+
+// Progress event names
+const (
+	ProgressRunStarted  = "run_started"
+	ProgressStarted     = "started"
+	ProgressFinished    = "finished"
+	ProgressRunFinished = "run_finished"
+)
+
+// ProgressEvent is one line of the JSON progress protocol
+// Percent is the share of checks finished so far, so it only grows
+type ProgressEvent struct {
+	Event     string                `json:"event"`
+	Time      time.Time             `json:"time"`
+	ToolID    string                `json:"id,omitempty"`
+	ToolName  string                `json:"name,omitempty"`
+	Status    string                `json:"status,omitempty"`
+	Message   string                `json:"message,omitempty"`
+	Install   string                `json:"install_hint,omitempty"`
+	Links     map[string]string     `json:"links,omitempty"`
+	Completed int                   `json:"completed"`
+	Total     int                   `json:"total"`
+	Percent   float64               `json:"percent"`
+	Summary   *checker.CheckSummary `json:"summary,omitempty"`
+	ExitCode  *int                  `json:"exit_code,omitempty"`
+}
+
+// ProgressReporter writes progress events as JSON Lines, typically to stderr
+// It is not safe for concurrent use; checker.CheckAll serializes its event callbacks
+type ProgressReporter struct {
+	w          io.Writer
+	total      int
+	completed  int
+	now        func() time.Time
+	exitPolicy checker.ExitPolicy
+}
+
+// NewProgressReporter creates a reporter writing to w
+func NewProgressReporter(w io.Writer) *ProgressReporter {
+	return &ProgressReporter{w: w, now: time.Now, exitPolicy: checker.DefaultExitPolicy}
+}
+
+// SetExitPolicy sets the policy used for the exit code reported when the run finishes
+func (pr *ProgressReporter) SetExitPolicy(policy checker.ExitPolicy) {
+	pr.exitPolicy = policy
+}
+
+// RunStarted announces the run and the number of checks it will perform
+func (pr *ProgressReporter) RunStarted(total int) {
+	pr.total = total
+	pr.completed = 0
+	pr.emit(ProgressEvent{Event: ProgressRunStarted})
+}
+
+// Started announces that a tool's check began
+func (pr *ProgressReporter) Started(tool manifest.ToolDefinition) {
+	pr.emit(ProgressEvent{Event: ProgressStarted, ToolID: tool.ID, ToolName: tool.Name})
+}
+
+// Finished reports a tool's result with the remediation an editor needs for a diagnostic
+func (pr *ProgressReporter) Finished(result checker.CheckResult) {
+	pr.completed++
+	pr.emit(ProgressEvent{
+		Event:    ProgressFinished,
+		ToolID:   result.ToolID,
+		ToolName: result.ToolName,
+		Status:   result.Status.String(),
+		Message:  result.ErrorMessage,
+		Install:  result.InstallHint,
+		Links:    result.Links,
+	})
+}
+
+// RunFinished reports the summary and exit code of the run
+func (pr *ProgressReporter) RunFinished(report checker.EnvironmentReport) {
+	exitCode := report.GetExitCode(pr.exitPolicy)
+	pr.emit(ProgressEvent{Event: ProgressRunFinished, Summary: &report.Summary, ExitCode: &exitCode})
+}
+
+// emit fills in the counters and writes one event line; write errors are ignored since
+// progress is advisory
+func (pr *ProgressReporter) emit(event ProgressEvent) {
+	event.Time = pr.now()
+	event.Completed = pr.completed
+	event.Total = pr.total
+	event.Percent = 100
+	if pr.total > 0 {
+		event.Percent = float64(pr.completed*1000/pr.total) / 10
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	pr.w.Write(append(data, '\n'))
+}
@@ -0,0 +1,210 @@
+// Package paths locates the directories goctor keeps files in
+//
+// Linux and other Unix systems follow the XDG Base Directory specification; macOS and
+// Windows use their native locations unless the corresponding XDG variable is set
+// explicitly. Each directory can be overridden with a GOCTOR_*_DIR variable.
+package paths
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// appName is the directory name used under each base directory
+const appName = "goctor"
+
+// Override environment variables, checked before any XDG or platform default
+const (
+	EnvConfigDir = "GOCTOR_CONFIG_DIR"
+	EnvCacheDir  = "GOCTOR_CACHE_DIR"
+	EnvStateDir  = "GOCTOR_STATE_DIR"
+	EnvDataDir   = "GOCTOR_DATA_DIR"
+)
+
+// Dirs are the base directories goctor uses
+type Dirs struct {
+	// Config holds user configuration
+	Config string
+	// Cache holds data that can be deleted at any time (bundles, recent results)
+	Cache string
+	// State holds data that should persist but is not worth backing up (history, onboarding)
+	State string
+	// Data holds user-installed files such as plugins, and the reports goctor server collects
+	Data string
+}
+
+// Entry is one directory or file goctor uses, with where its location came from
+type Entry struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Source string `json:"source"`
+}
+
+// sources records which variable, if any, determined each base directory
+type sources struct {
+	config, cache, state, data string
+}
+
+// getenv looks up an environment variable; os.Getenv outside tests
+type getenv func(key string) string
+
+// Default resolves the directories for the running platform and environment
+func Default() (Dirs, error) {
+	dirs, _, err := resolve(runtime.GOOS, os.Getenv)
+	return dirs, err
+}
+
+// resolve computes the base directories and the source of each
+func resolve(goos string, env getenv) (Dirs, sources, error) {
+	var dirs Dirs
+	var src sources
+
+	home := env("HOME")
+	if goos == "windows" && home == "" {
+		home = env("USERPROFILE")
+	}
+
+	var err error
+	dirs.Config, src.config, err = pick(env, EnvConfigDir, "XDG_CONFIG_HOME", platformBase(goos, env, home, "config"))
+	if err != nil {
+		return Dirs{}, sources{}, err
+	}
+	dirs.Cache, src.cache, err = pick(env, EnvCacheDir, "XDG_CACHE_HOME", platformBase(goos, env, home, "cache"))
+	if err != nil {
+		return Dirs{}, sources{}, err
+	}
+	dirs.State, src.state, err = pick(env, EnvStateDir, "XDG_STATE_HOME", platformBase(goos, env, home, "state"))
+	if err != nil {
+		return Dirs{}, sources{}, err
+	}
+	dirs.Data, src.data, err = pick(env, EnvDataDir, "XDG_DATA_HOME", platformBase(goos, env, home, "data"))
+	if err != nil {
+		return Dirs{}, sources{}, err
+	}
+
+	return dirs, src, nil
+}
+
+// pick returns the override if set, else <XDG variable>/goctor, else <fallback>/goctor
+// Relative XDG values are ignored, as the specification requires
+func pick(env getenv, override, xdg, fallback string) (string, string, error) {
+	if dir := env(override); dir != "" {
+		return filepath.Clean(dir), override, nil
+	}
+	if dir := env(xdg); dir != "" && filepath.IsAbs(dir) {
+		return filepath.Join(dir, appName), xdg, nil
+	}
+	if fallback == "" {
+		return "", "", errors.New("cannot determine home directory; set " + override)
+	}
+	return filepath.Join(fallback, appName), "default", nil
+}
+
+// platformBase returns the native base directory of the given kind, or "" without a home
+func platformBase(goos string, env getenv, home, kind string) string {
+	switch goos {
+	case "darwin":
+		if home == "" {
+			return ""
+		}
+		if kind == "cache" {
+			return filepath.Join(home, "Library", "Caches")
+		}
+		return filepath.Join(home, "Library", "Application Support")
+	case "windows":
+		if kind == "config" {
+			return env("APPDATA")
+		}
+		return env("LOCALAPPDATA")
+	}
+
+	if home == "" {
+		return ""
+	}
+	switch kind {
+	case "config":
+		return filepath.Join(home, ".config")
+	case "cache":
+		return filepath.Join(home, ".cache")
+	case "state":
+		return filepath.Join(home, ".local", "state")
+	default:
+		return filepath.Join(home, ".local", "share")
+	}
+}
+
+// ConfigFile is the user configuration file
+func (d Dirs) ConfigFile() string {
+	return filepath.Join(d.Config, "config.yaml")
+}
+
+// TrustedSources is the allowlist of remote manifest sources the user has trusted
+func (d Dirs) TrustedSources() string {
+	return filepath.Join(d.Config, "trusted_sources")
+}
+
+// Bundles is where manifest bundles are extracted
+func (d Dirs) Bundles() string {
+	return filepath.Join(d.Cache, "bundles")
+}
+
+// ResultCache is the file recent check results are cached in
+func (d Dirs) ResultCache() string {
+	return filepath.Join(d.Cache, "results.json")
+}
+
+// LinkCache is the file links that answered a link check are cached in
+func (d Dirs) LinkCache() string {
+	return filepath.Join(d.Cache, "links.json")
+}
+
+// History is where past run reports are kept
+func (d Dirs) History() string {
+	return filepath.Join(d.State, "history")
+}
+
+// Onboarding is where first-run markers are kept
+func (d Dirs) Onboarding() string {
+	return filepath.Join(d.State, "onboarding")
+}
+
+// Plugins is where user-installed check plugins live
+func (d Dirs) Plugins() string {
+	return filepath.Join(d.Data, "plugins")
+}
+
+// Fleet is where goctor server keeps the latest report of each host
+func (d Dirs) Fleet() string {
+	return filepath.Join(d.Data, "fleet")
+}
+
+// List resolves the directories for the running platform and returns every location in use
+func List() ([]Entry, error) {
+	return list(runtime.GOOS, os.Getenv)
+}
+
+// list returns every location goctor uses, in a stable order
+func list(goos string, env getenv) ([]Entry, error) {
+	d, src, err := resolve(goos, env)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Entry{
+		{"config", d.Config, src.config},
+		{"config-file", d.ConfigFile(), src.config},
+		{"trusted-sources", d.TrustedSources(), src.config},
+		{"cache", d.Cache, src.cache},
+		{"state", d.State, src.state},
+		{"data", d.Data, src.data},
+		{"bundles", d.Bundles(), src.cache},
+		{"result-cache", d.ResultCache(), src.cache},
+		{"link-cache", d.LinkCache(), src.cache},
+		{"history", d.History(), src.state},
+		{"onboarding", d.Onboarding(), src.state},
+		{"plugins", d.Plugins(), src.data},
+		{"fleet", d.Fleet(), src.data},
+	}, nil
+}
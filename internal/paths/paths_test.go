@@ -0,0 +1,165 @@
+package paths
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func fakeEnv(vars map[string]string) getenv {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		env  map[string]string
+		want Dirs
+	}{
+		{
+			name: "linux defaults",
+			goos: "linux",
+			env:  map[string]string{"HOME": "/home/dev"},
+			want: Dirs{
+				Config: "/home/dev/.config/goctor",
+				Cache:  "/home/dev/.cache/goctor",
+				State:  "/home/dev/.local/state/goctor",
+				Data:   "/home/dev/.local/share/goctor",
+			},
+		},
+		{
+			name: "xdg variables",
+			goos: "linux",
+			env: map[string]string{
+				"HOME":            "/home/dev",
+				"XDG_CONFIG_HOME": "/xdg/config",
+				"XDG_CACHE_HOME":  "/xdg/cache",
+				"XDG_STATE_HOME":  "relative/state",
+			},
+			want: Dirs{
+				Config: "/xdg/config/goctor",
+				Cache:  "/xdg/cache/goctor",
+				State:  "/home/dev/.local/state/goctor",
+				Data:   "/home/dev/.local/share/goctor",
+			},
+		},
+		{
+			name: "goctor overrides win",
+			goos: "linux",
+			env: map[string]string{
+				"HOME":              "/home/dev",
+				"XDG_CACHE_HOME":    "/xdg/cache",
+				"GOCTOR_CACHE_DIR":  "/ci/cache/",
+				"GOCTOR_CONFIG_DIR": "/etc/goctor",
+			},
+			want: Dirs{
+				Config: "/etc/goctor",
+				Cache:  "/ci/cache",
+				State:  "/home/dev/.local/state/goctor",
+				Data:   "/home/dev/.local/share/goctor",
+			},
+		},
+		{
+			name: "macos defaults",
+			goos: "darwin",
+			env:  map[string]string{"HOME": "/Users/dev"},
+			want: Dirs{
+				Config: "/Users/dev/Library/Application Support/goctor",
+				Cache:  "/Users/dev/Library/Caches/goctor",
+				State:  "/Users/dev/Library/Application Support/goctor",
+				Data:   "/Users/dev/Library/Application Support/goctor",
+			},
+		},
+		{
+			name: "macos honors explicit xdg",
+			goos: "darwin",
+			env:  map[string]string{"HOME": "/Users/dev", "XDG_CONFIG_HOME": "/Users/dev/.config"},
+			want: Dirs{
+				Config: "/Users/dev/.config/goctor",
+				Cache:  "/Users/dev/Library/Caches/goctor",
+				State:  "/Users/dev/Library/Application Support/goctor",
+				Data:   "/Users/dev/Library/Application Support/goctor",
+			},
+		},
+		{
+			name: "windows defaults",
+			goos: "windows",
+			env:  map[string]string{"APPDATA": "/appdata/roaming", "LOCALAPPDATA": "/appdata/local"},
+			want: Dirs{
+				Config: filepath.Join("/appdata/roaming", "goctor"),
+				Cache:  filepath.Join("/appdata/local", "goctor"),
+				State:  filepath.Join("/appdata/local", "goctor"),
+				Data:   filepath.Join("/appdata/local", "goctor"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := resolve(tt.goos, fakeEnv(tt.env))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolve() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveWithoutHome(t *testing.T) {
+	_, _, err := resolve("linux", fakeEnv(nil))
+	if err == nil || !strings.Contains(err.Error(), EnvConfigDir) {
+		t.Fatalf("Expected error mentioning %s, got: %v", EnvConfigDir, err)
+	}
+
+	// Overrides alone are enough
+	dirs, _, err := resolve("linux", fakeEnv(map[string]string{
+		EnvConfigDir: "/c", EnvCacheDir: "/k", EnvStateDir: "/s", EnvDataDir: "/d",
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dirs.Plugins() != "/d/plugins" || dirs.History() != "/s/history" {
+		t.Errorf("Unexpected derived paths: %s, %s", dirs.Plugins(), dirs.History())
+	}
+}
+
+func TestList(t *testing.T) {
+	entries, err := list("linux", fakeEnv(map[string]string{
+		"HOME":             "/home/dev",
+		"XDG_CACHE_HOME":   "/xdg/cache",
+		"GOCTOR_STATE_DIR": "/state",
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := map[string]Entry{
+		"config":          {"config", "/home/dev/.config/goctor", "default"},
+		"config-file":     {"config-file", "/home/dev/.config/goctor/config.yaml", "default"},
+		"trusted-sources": {"trusted-sources", "/home/dev/.config/goctor/trusted_sources", "default"},
+		"bundles":         {"bundles", "/xdg/cache/goctor/bundles", "XDG_CACHE_HOME"},
+		"result-cache":    {"result-cache", "/xdg/cache/goctor/results.json", "XDG_CACHE_HOME"},
+		"link-cache":      {"link-cache", "/xdg/cache/goctor/links.json", "XDG_CACHE_HOME"},
+		"history":         {"history", "/state/history", EnvStateDir},
+		"plugins":         {"plugins", "/home/dev/.local/share/goctor/plugins", "default"},
+		"fleet":           {"fleet", "/home/dev/.local/share/goctor/fleet", "default"},
+	}
+
+	found := 0
+	for _, entry := range entries {
+		if expected, ok := want[entry.Name]; ok {
+			found++
+			if entry != expected {
+				t.Errorf("Entry %s = %+v, want %+v", entry.Name, entry, expected)
+			}
+		}
+	}
+	if found != len(want) {
+		t.Errorf("Expected %d of the checked entries, found %d", len(want), found)
+	}
+}
@@ -0,0 +1,36 @@
+package platform
+
+import (
+	"os"
+)
+
+// CI providers reported by DetectCI
+const (
+	CIGitHubActions = "github-actions"
+	CIGitLab        = "gitlab"
+	CIGeneric       = "ci"
+)
+
+// DetectCI returns the continuous integration provider goctor is running under, or "" locally
+func DetectCI() string {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return CIGitHubActions
+	case os.Getenv("GITLAB_CI") == "true":
+		return CIGitLab
+	case os.Getenv("CI") == "true" || os.Getenv("CI") == "1":
+		return CIGeneric
+	default:
+		return ""
+	}
+}
+
+// IsGitHubActions returns true when running inside a GitHub Actions workflow
+func (pi *PlatformInfo) IsGitHubActions() bool {
+	return pi.CI == CIGitHubActions
+}
+
+// GitHubStepSummaryPath returns the file GitHub Actions renders as the job's step summary
+func GitHubStepSummaryPath() string {
+	return os.Getenv("GITHUB_STEP_SUMMARY")
+}
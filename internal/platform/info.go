@@ -1,8 +1,12 @@
 package platform
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"os"
 	"runtime"
+	"slices"
 	"strings"
 )
 
@@ -11,6 +15,11 @@ type PlatformInfo struct {
 	OS           string `json:"os"`
 	Architecture string `json:"arch"`
 	Hostname     string `json:"hostname,omitempty"`
+	CI           string `json:"ci,omitempty"`
+	// PackageManagers is the inventory of package managers found on PATH
+	PackageManagers []string `json:"package_managers,omitempty"`
+	// Home is the home directory of a remote target; local checks use the current user's
+	Home string `json:"-"`
 }
 
 // CheckSummary provides statistical summary (duplicate here for package independence)
@@ -25,8 +34,10 @@ type CheckSummary struct {
 // DetectPlatform detects the current platform information
 func DetectPlatform() PlatformInfo {
 	platform := PlatformInfo{
-		OS:           runtime.GOOS,
-		Architecture: runtime.GOARCH,
+		OS:              runtime.GOOS,
+		Architecture:    runtime.GOARCH,
+		CI:              DetectCI(),
+		PackageManagers: DetectPackageManagers(),
 	}
 
 	// Try to get hostname, but don't fail if we can't
@@ -37,20 +48,44 @@ func DetectPlatform() PlatformInfo {
 	return platform
 }
 
-// IsSupported returns true if the platform is supported
-func (pi *PlatformInfo) IsSupported() bool {
-	supportedOS := map[string]bool{
-		"darwin": true,
-		"linux":  true,
+// unameArchitectures maps machine names printed by `uname -m` to Go architecture names
+var unameArchitectures = map[string]string{
+	"x86_64":  "amd64",
+	"amd64":   "amd64",
+	"aarch64": "arm64",
+	"arm64":   "arm64",
+	"i386":    "386",
+	"i486":    "386",
+	"i586":    "386",
+	"i686":    "386",
+}
+
+// ParseUname converts the output of `uname -sm`, e.g. "Linux x86_64", into Go's OS and
+// architecture names; unknown names are returned lowercased so IsSupported can reject them
+func ParseUname(output string) (string, string, error) {
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return "", "", fmt.Errorf("unexpected uname output: %q", strings.TrimSpace(output))
 	}
 
-	supportedArch := map[string]bool{
-		"amd64": true,
-		"arm64": true,
-		"386":   true,
+	goos := strings.ToLower(fields[0])
+	arch, ok := unameArchitectures[strings.ToLower(fields[1])]
+	if !ok {
+		arch = strings.ToLower(fields[1])
 	}
 
-	return supportedOS[pi.OS] && supportedArch[pi.Architecture]
+	return goos, arch, nil
+}
+
+// SupportedOS lists the operating systems goctor runs on
+var SupportedOS = []string{"darwin", "linux"}
+
+// SupportedArchitectures lists the CPU architectures goctor runs on
+var SupportedArchitectures = []string{"amd64", "arm64", "386"}
+
+// IsSupported returns true if the platform is supported
+func (pi *PlatformInfo) IsSupported() bool {
+	return slices.Contains(SupportedOS, pi.OS) && slices.Contains(SupportedArchitectures, pi.Architecture)
 }
 
 // String returns a human-readable representation of the platform
@@ -61,6 +96,21 @@ func (pi *PlatformInfo) String() string {
 	return pi.OS + "/" + pi.Architecture
 }
 
+// Anonymize replaces the hostname with AnonymizeHostname's pseudonym of it
+func (pi *PlatformInfo) Anonymize() {
+	pi.Hostname = AnonymizeHostname(pi.Hostname)
+}
+
+// AnonymizeHostname returns a stable pseudonym of hostname, so reports of one machine can still
+// be grouped without naming it; an empty hostname stays empty
+func AnonymizeHostname(hostname string) string {
+	if hostname == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(hostname))
+	return "host-" + hex.EncodeToString(sum[:6])
+}
+
 // GetPlatformSpecificCommands returns platform-specific variations of commands
 func (pi *PlatformInfo) GetPlatformSpecificCommands(baseCommand []string) []string {
 	// For most tools, commands are the same across platforms
@@ -75,6 +125,15 @@ func (pi *PlatformInfo) ShouldUseShell() bool {
 	return false
 }
 
+// ShellCommand returns the command that runs a script through this platform's shell
+// Used only by checks that opt in with check.shell
+func (pi *PlatformInfo) ShellCommand(script string) []string {
+	if pi.OS == "windows" {
+		return []string{"cmd", "/C", script}
+	}
+	return []string{"/bin/sh", "-c", script}
+}
+
 // GetPathSeparator returns the path separator for this platform
 func (pi *PlatformInfo) GetPathSeparator() string {
 	if pi.OS == "windows" {
@@ -117,8 +176,7 @@ func (pi *PlatformInfo) GetPackageManager() string {
 	case "darwin":
 		return "homebrew"
 	case "linux":
-		// This is a simplified detection - in reality we'd check for specific distros
-		return "apt" // Default to apt for Linux
+		return pi.GetPreferredPackageManager()
 	default:
 		return "unknown"
 	}
@@ -193,51 +251,46 @@ func (pi *PlatformInfo) DetectDistribution() string {
 		return ""
 	}
 
-	// Try to read /etc/os-release
-	if data, err := os.ReadFile("/etc/os-release"); err == nil {
-		content := string(data)
+	ids := distributionIDs()
+	if len(ids) == 0 {
+		return "unknown"
+	}
+	return ids[0]
+}
 
-		// Look for ID= line
-		for _, line := range strings.Split(content, "\n") {
-			if strings.HasPrefix(line, "ID=") {
-				id := strings.TrimPrefix(line, "ID=")
-				id = strings.Trim(id, "\"")
-				return id
-			}
+// distributionIDs returns the os-release ID of the running Linux distribution followed by the
+// IDs of its ID_LIKE parents, e.g. rocky, rhel, centos, fedora
+func distributionIDs() []string {
+	if data, err := os.ReadFile(osReleasePath); err == nil {
+		release := ParseOSRelease(string(data))
+		if release["ID"] != "" {
+			return append([]string{release["ID"]}, strings.Fields(release["ID_LIKE"])...)
 		}
 	}
 
-	// Fallback checks for common distributions
+	// Fallback checks for distributions without os-release
 	if _, err := os.Stat("/etc/debian_version"); err == nil {
-		return "debian"
+		return []string{"debian"}
 	}
-
 	if _, err := os.Stat("/etc/redhat-release"); err == nil {
-		return "redhat"
+		return []string{"redhat"}
+	}
+	if _, err := os.Stat("/etc/alpine-release"); err == nil {
+		return []string{"alpine"}
 	}
 
-	return "unknown"
+	return nil
 }
 
-// GetPreferredPackageManager returns the preferred package manager for the detected platform
+// GetPreferredPackageManager returns the native package manager of the detected platform, or
+// "" on a Linux distribution goctor does not know that has no known package manager installed
 func (pi *PlatformInfo) GetPreferredPackageManager() string {
-	if pi.IsMacOS() {
-		return "brew"
-	}
-
-	if pi.IsLinux() {
-		distro := pi.DetectDistribution()
-		switch distro {
-		case "ubuntu", "debian":
-			return "apt"
-		case "fedora", "centos", "rhel":
-			return "yum"
-		case "arch":
-			return "pacman"
-		default:
-			return "apt" // Default fallback
-		}
+	switch {
+	case pi.IsMacOS():
+		return preferredPackageManager("darwin", nil, pi.PackageManagers)
+	case pi.IsLinux():
+		return preferredPackageManager("linux", distributionIDs(), pi.PackageManagers)
+	default:
+		return "unknown"
 	}
-
-	return "unknown"
-}
\ No newline at end of file
+}
@@ -0,0 +1,111 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// OS version sources of `check.type: os`; any other source is a Linux distribution's
+// os-release ID, such as "ubuntu" or "debian"
+const (
+	OSVersionMacOS  = "macos"
+	OSVersionKernel = "kernel"
+)
+
+// osReleasePath is where Linux distributions describe themselves
+const osReleasePath = "/etc/os-release"
+
+// kernelVersionRegex matches the numeric part of a kernel release such as "6.5.0-14-generic"
+var kernelVersionRegex = regexp.MustCompile(`^\d+(\.\d+)*`)
+
+// OSVersionCommand returns the command that prints the version named by source: sw_vers for
+// macOS, uname for the kernel, and the os-release file for a distribution
+func OSVersionCommand(source string) []string {
+	switch source {
+	case OSVersionMacOS:
+		return []string{"sw_vers", "-productVersion"}
+	case OSVersionKernel:
+		return []string{"uname", "-r"}
+	default:
+		return []string{"cat", osReleasePath}
+	}
+}
+
+// OSVersionOS returns the OS a version source exists on, or "" if it exists on every OS
+func OSVersionOS(source string) string {
+	switch source {
+	case OSVersionMacOS:
+		return "darwin"
+	case OSVersionKernel:
+		return ""
+	default:
+		return "linux"
+	}
+}
+
+// OtherOSError reports that the machine runs another OS or distribution than a check asks for
+type OtherOSError struct {
+	Want    string
+	Running string
+}
+
+func (e *OtherOSError) Error() string {
+	return fmt.Sprintf("running %s, not %s", e.Running, e.Want)
+}
+
+// ParseOSVersion extracts the version of source from the output of its OSVersionCommand
+// A distribution other than the one asked for is an *OtherOSError
+func ParseOSVersion(source, output string) (string, error) {
+	switch source {
+	case OSVersionMacOS:
+		version := strings.TrimSpace(output)
+		if version == "" {
+			return "", errors.New("sw_vers printed no version")
+		}
+		return version, nil
+	case OSVersionKernel:
+		version := kernelVersionRegex.FindString(strings.TrimSpace(output))
+		if version == "" {
+			return "", fmt.Errorf("unexpected kernel release: %q", strings.TrimSpace(output))
+		}
+		return version, nil
+	}
+
+	release := ParseOSRelease(output)
+	if id := release["ID"]; id != source {
+		if id == "" {
+			return "", fmt.Errorf("%s has no ID", osReleasePath)
+		}
+		return "", &OtherOSError{Want: source, Running: id}
+	}
+	if release["VERSION_ID"] == "" {
+		return "", fmt.Errorf("%s has no VERSION_ID", osReleasePath)
+	}
+	return release["VERSION_ID"], nil
+}
+
+// ParseOSRelease parses the KEY=value lines of an os-release file, unquoting the values
+func ParseOSRelease(content string) map[string]string {
+	release := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `'"`)
+		}
+		release[key] = value
+	}
+	return release
+}
@@ -0,0 +1,23 @@
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PathFingerprint summarizes the directories of a PATH-style list so installs can be noticed
+// without a file watcher: it changes when a directory appears or disappears, or when its
+// modification time changes, which happens whenever an executable is added to or removed from it
+func PathFingerprint(pathList string) string {
+	var fingerprint strings.Builder
+	for _, dir := range filepath.SplitList(pathList) {
+		fingerprint.WriteString(dir)
+		if info, err := os.Stat(dir); err == nil {
+			fingerprint.WriteString("@" + info.ModTime().Format(time.RFC3339Nano))
+		}
+		fingerprint.WriteString("\n")
+	}
+	return fingerprint.String()
+}
@@ -0,0 +1,163 @@
+package platform
+
+import (
+	"os/exec"
+	"slices"
+)
+
+// packageManagerBinaries maps each known package manager to the executable that reveals it
+var packageManagerBinaries = []struct {
+	name   string
+	binary string
+}{
+	{"brew", "brew"},
+	{"apt", "apt-get"},
+	{"dnf", "dnf"},
+	{"yum", "yum"},
+	{"zypper", "zypper"},
+	{"apk", "apk"},
+	{"pacman", "pacman"},
+	{"emerge", "emerge"},
+	{"nix-darwin", "darwin-rebuild"},
+	{"home-manager", "home-manager"},
+	{"nix", "nix"},
+	{"asdf", "asdf"},
+	{"mise", "mise"},
+	{"scoop", "scoop"},
+}
+
+// distributionPackageManagers maps os-release IDs to the native package managers of those
+// distributions, preferred first; distributions not listed are matched by their ID_LIKE parents
+var distributionPackageManagers = map[string][]string{
+	"debian":   {"apt"},
+	"ubuntu":   {"apt"},
+	"fedora":   {"dnf", "yum"},
+	"rhel":     {"dnf", "yum"},
+	"centos":   {"dnf", "yum"},
+	"redhat":   {"dnf", "yum"},
+	"amzn":     {"dnf", "yum"},
+	"suse":     {"zypper"},
+	"opensuse": {"zypper"},
+	"sles":     {"zypper"},
+	"alpine":   {"apk"},
+	"arch":     {"pacman"},
+	"gentoo":   {"emerge"},
+	"nixos":    {"nix"},
+}
+
+// linuxPackageManagers are the system package managers of Linux distributions, tried in this
+// order on a distribution goctor does not know
+var linuxPackageManagers = []string{"apt", "dnf", "yum", "zypper", "apk", "pacman", "emerge", "nix"}
+
+// darwinPackageManagers are the package managers of macOS, preferred first: Homebrew, then a
+// nix-darwin or Home Manager configuration, then plain nix
+var darwinPackageManagers = []string{"brew", "nix-darwin", "home-manager", "nix"}
+
+// nixFrontends install packages through nix, so tools' nix hints apply where they are found
+var nixFrontends = []string{"nix-darwin", "home-manager"}
+
+// lookPath is replaced in tests
+var lookPath = exec.LookPath
+
+// KnownPackageManagers returns the package managers DetectPackageManagers looks for
+func KnownPackageManagers() []string {
+	names := make([]string, 0, len(packageManagerBinaries))
+	for _, pm := range packageManagerBinaries {
+		names = append(names, pm.name)
+	}
+	return names
+}
+
+// DetectPackageManagers returns the package managers installed on PATH, in KnownPackageManagers order
+func DetectPackageManagers() []string {
+	return DetectPackageManagersWith(func(binary string) bool {
+		_, err := lookPath(binary)
+		return err == nil
+	})
+}
+
+// DetectPackageManagersWith is DetectPackageManagers for another machine, where installed
+// reports whether an executable is on its PATH
+func DetectPackageManagersWith(installed func(binary string) bool) []string {
+	var found []string
+	for _, pm := range packageManagerBinaries {
+		if installed(pm.binary) {
+			found = append(found, pm.name)
+		}
+	}
+	return found
+}
+
+// InstallPreference returns package managers in the order their install hints should be tried
+// The platform's native manager comes first when installed, followed by the rest of the
+// inventory; without an inventory the native manager is assumed
+func (pi *PlatformInfo) InstallPreference() []string {
+	return installPreference(pi.GetPreferredPackageManager(), pi.PackageManagers)
+}
+
+// installPreference orders the inventory with preferred first; nix follows nix-darwin and
+// Home Manager even when the nix command itself is not on PATH
+func installPreference(preferred string, inventory []string) []string {
+	if len(inventory) == 0 {
+		if preferred == "" {
+			return nil
+		}
+		return []string{preferred}
+	}
+
+	order := make([]string, 0, len(inventory)+1)
+	if slices.Contains(inventory, preferred) {
+		order = append(order, preferred)
+	}
+	for _, pm := range inventory {
+		if pm != preferred {
+			order = append(order, pm)
+		}
+	}
+	for _, pm := range order {
+		if slices.Contains(nixFrontends, pm) && !slices.Contains(order, "nix") {
+			order = append(order, "nix")
+			break
+		}
+	}
+
+	return order
+}
+
+// preferredPackageManager picks the native package manager of goos and, on Linux, the
+// distribution with the given os-release IDs: the first of its managers in the inventory, or the
+// distribution's usual one when none is installed
+// An unknown distribution gets the first Linux package manager installed, or "" rather than a
+// guess
+func preferredPackageManager(goos string, distributionIDs, inventory []string) string {
+	var candidates []string
+	switch goos {
+	case "darwin":
+		candidates = darwinPackageManagers
+	case "linux":
+		for _, id := range distributionIDs {
+			if managers, ok := distributionPackageManagers[id]; ok {
+				candidates = managers
+				break
+			}
+		}
+	}
+
+	for _, pm := range candidates {
+		if slices.Contains(inventory, pm) {
+			return pm
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0]
+	}
+
+	if goos == "linux" {
+		for _, pm := range linuxPackageManagers {
+			if slices.Contains(inventory, pm) {
+				return pm
+			}
+		}
+	}
+	return ""
+}
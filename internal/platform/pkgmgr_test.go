@@ -0,0 +1,64 @@
+package platform
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPreferredPackageManager(t *testing.T) {
+	tests := []struct {
+		name            string
+		goos            string
+		distributionIDs []string
+		inventory       []string
+		want            string
+	}{
+		{name: "ubuntu", goos: "linux", distributionIDs: []string{"ubuntu", "debian"}, inventory: []string{"apt"}, want: "apt"},
+		{name: "linux mint by ID_LIKE", goos: "linux", distributionIDs: []string{"linuxmint", "ubuntu", "debian"}, want: "apt"},
+		{name: "fedora", goos: "linux", distributionIDs: []string{"fedora"}, inventory: []string{"dnf", "yum"}, want: "dnf"},
+		{name: "rocky by ID_LIKE", goos: "linux", distributionIDs: []string{"rocky", "rhel", "centos", "fedora"}, inventory: []string{"dnf"}, want: "dnf"},
+		{name: "centos 7 without dnf", goos: "linux", distributionIDs: []string{"centos", "rhel", "fedora"}, inventory: []string{"yum"}, want: "yum"},
+		{name: "opensuse", goos: "linux", distributionIDs: []string{"opensuse-tumbleweed", "opensuse", "suse"}, inventory: []string{"zypper"}, want: "zypper"},
+		{name: "alpine", goos: "linux", distributionIDs: []string{"alpine"}, inventory: []string{"apk"}, want: "apk"},
+		{name: "manjaro", goos: "linux", distributionIDs: []string{"manjaro", "arch"}, inventory: []string{"pacman"}, want: "pacman"},
+		{name: "gentoo", goos: "linux", distributionIDs: []string{"gentoo"}, inventory: []string{"emerge"}, want: "emerge"},
+		{name: "nixos", goos: "linux", distributionIDs: []string{"nixos"}, inventory: []string{"home-manager", "nix"}, want: "nix"},
+		{name: "native manager over nix", goos: "linux", distributionIDs: []string{"debian"}, inventory: []string{"apt", "nix"}, want: "apt"},
+		{name: "unknown distribution", goos: "linux", distributionIDs: []string{"mystery"}, inventory: []string{"brew", "apk"}, want: "apk"},
+		{name: "unknown distribution without managers", goos: "linux", distributionIDs: []string{"mystery"}, inventory: []string{"brew"}, want: ""},
+		{name: "no os-release", goos: "linux", want: ""},
+		{name: "macos", goos: "darwin", inventory: []string{"brew", "nix"}, want: "brew"},
+		{name: "nix-darwin", goos: "darwin", inventory: []string{"nix-darwin", "home-manager", "nix"}, want: "nix-darwin"},
+		{name: "macos without inventory", goos: "darwin", want: "brew"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := preferredPackageManager(tt.goos, tt.distributionIDs, tt.inventory); got != tt.want {
+				t.Errorf("preferredPackageManager() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallPreference(t *testing.T) {
+	tests := []struct {
+		name      string
+		preferred string
+		inventory []string
+		want      []string
+	}{
+		{name: "native first", preferred: "dnf", inventory: []string{"brew", "dnf", "mise"}, want: []string{"dnf", "brew", "mise"}},
+		{name: "assumed without inventory", preferred: "apk", want: []string{"apk"}},
+		{name: "nothing to assume", preferred: "", want: nil},
+		{name: "nix behind home-manager", preferred: "brew", inventory: []string{"brew", "home-manager"}, want: []string{"brew", "home-manager", "nix"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := installPreference(tt.preferred, tt.inventory); !slices.Equal(got, tt.want) {
+				t.Errorf("installPreference() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package platform
+
+import (
+	"os"
+)
+
+// TemplateVars returns the platform-derived variables available to check command templates
+func (pi *PlatformInfo) TemplateVars() map[string]string {
+	vars := map[string]string{
+		"os":          pi.OS,
+		"arch":        pi.Architecture,
+		"brew_prefix": pi.BrewPrefix(),
+		"home":        "",
+	}
+
+	if pi.Home != "" {
+		vars["home"] = pi.Home
+	} else if home, err := os.UserHomeDir(); err == nil {
+		vars["home"] = home
+	}
+
+	return vars
+}
+
+// BrewPrefix returns the Homebrew prefix for this platform, honoring HOMEBREW_PREFIX
+func (pi *PlatformInfo) BrewPrefix() string {
+	if prefix := os.Getenv("HOMEBREW_PREFIX"); prefix != "" {
+		return prefix
+	}
+
+	switch {
+	case pi.IsMacOS() && pi.IsARM():
+		return "/opt/homebrew"
+	case pi.IsMacOS():
+		return "/usr/local"
+	case pi.IsLinux():
+		return "/home/linuxbrew/.linuxbrew"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,149 @@
+// Package reporting uploads the report of each run to a collection endpoint, so platform
+// teams can aggregate compliance across a fleet of machines
+//
+// The report is POSTed as the same JSON document `doctor --json` prints. Connection failures,
+// 429, and 5xx responses are retried with exponential backoff; other responses are final.
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+// Defaults for uploads
+const (
+	DefaultTimeout  = 10 * time.Second
+	DefaultAttempts = 3
+	DefaultBackoff  = time.Second
+)
+
+// Uploader posts reports to one endpoint
+type Uploader struct {
+	url         string
+	headers     http.Header
+	bearerToken string
+	client      *http.Client
+	attempts    int
+	backoff     time.Duration
+}
+
+// NewUploader creates an uploader for an http or https URL
+func NewUploader(endpoint string) (*Uploader, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid report URL %q (expected http:// or https://)", endpoint)
+	}
+
+	return &Uploader{
+		url:      endpoint,
+		headers:  http.Header{},
+		client:   &http.Client{Timeout: DefaultTimeout},
+		attempts: DefaultAttempts,
+		backoff:  DefaultBackoff,
+	}, nil
+}
+
+// AddHeader sends a header, such as Authorization, with every upload
+func (u *Uploader) AddHeader(name, value string) {
+	u.headers.Add(name, value)
+}
+
+// SetBearerToken authenticates uploads with an Authorization: Bearer header unless an
+// Authorization header was added; the token is only sent over https, never in cleartext
+func (u *Uploader) SetBearerToken(token string) {
+	u.bearerToken = token
+}
+
+// SetRetry sets how many times an upload is attempted and the wait before the first retry,
+// which doubles after each one
+func (u *Uploader) SetRetry(attempts int, backoff time.Duration) {
+	u.attempts = max(attempts, 1)
+	u.backoff = backoff
+}
+
+// URL returns the endpoint reports are posted to
+func (u *Uploader) URL() string {
+	return u.url
+}
+
+// Upload posts the report, retrying transient failures
+func (u *Uploader) Upload(ctx context.Context, report checker.EnvironmentReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	wait := u.backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := u.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= u.attempts {
+			if attempt > 1 {
+				return fmt.Errorf("%v (after %d attempts)", err, attempt)
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// post sends the report once and reports whether a failure is worth retrying
+func (u *Uploader) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header = u.headers.Clone()
+	if u.bearerToken != "" && req.URL.Scheme == "https" && req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", "Bearer "+u.bearerToken)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "goctor-report")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("HTTP %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	if message := responseMessage(resp.Body); message != "" {
+		err = fmt.Errorf("%v: %s", err, message)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// responseMessage returns the first line of an error response, which usually says what was
+// wrong, or the error of a JSON error body such as goctor server sends
+func responseMessage(body io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(body, 512))
+	var jsonError struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(data, &jsonError) == nil && jsonError.Error != "" {
+		return jsonError.Error
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(data)), "\n")
+	return strings.TrimSpace(line)
+}
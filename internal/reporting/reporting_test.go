@@ -0,0 +1,155 @@
+package reporting
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+func TestNewUploader(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "https://fleet.example.com/api/reports"},
+		{url: "http://localhost:8080/reports"},
+		{url: "ftp://fleet.example.com/reports", wantErr: true},
+		{url: "fleet.example.com/reports", wantErr: true},
+		{url: "https://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		_, err := NewUploader(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewUploader(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
+
+func TestUpload(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		body         string
+		wantAttempts int
+		wantErr      string
+	}{
+		{name: "accepted", statuses: []int{http.StatusAccepted}, wantAttempts: 1},
+		{name: "retried after server error", statuses: []int{http.StatusBadGateway, http.StatusOK}, wantAttempts: 2},
+		{name: "retried after rate limit", statuses: []int{http.StatusTooManyRequests, http.StatusOK}, wantAttempts: 2},
+		{
+			name:         "gives up after the last attempt",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			wantAttempts: 3,
+			wantErr:      "HTTP 503 Service Unavailable: try later (after 3 attempts)",
+		},
+		{name: "client errors are final", statuses: []int{http.StatusUnauthorized}, wantAttempts: 1, wantErr: "HTTP 401 Unauthorized: try later"},
+		{
+			name:         "JSON error body",
+			statuses:     []int{http.StatusBadRequest},
+			body:         "{\n  \"error\": \"the report names no host\"\n}\n",
+			wantAttempts: 1,
+			wantErr:      "HTTP 400 Bad Request: the report names no host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int
+			var received checker.EnvironmentReport
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[attempts]
+				attempts++
+
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+					t.Errorf("Authorization = %q", got)
+				}
+				if got := r.Header.Get("X-Fleet"); got != "laptops" {
+					t.Errorf("X-Fleet = %q", got)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+					t.Errorf("decoding body: %v", err)
+				}
+
+				w.WriteHeader(status)
+				if status >= 300 {
+					w.Write([]byte(cmp.Or(tt.body, "try later\n")))
+				}
+			}))
+			defer srv.Close()
+
+			uploader, err := NewUploader(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			uploader.client = srv.Client()
+			uploader.SetBearerToken("secret")
+			uploader.AddHeader("X-Fleet", "laptops")
+			uploader.SetRetry(3, 0)
+
+			report := checker.NewEnvironmentReport(nil, "tools.yaml", []checker.CheckResult{{ToolID: "go", Status: checker.StatusOK}})
+			err = uploader.Upload(context.Background(), *report)
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Upload() error = %v", err)
+				}
+				if received.ManifestSource != "tools.yaml" || len(received.Items) != 1 {
+					t.Errorf("unexpected report received: %+v", received)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Upload() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUploadBearerTokenOnlyOverHTTPS(t *testing.T) {
+	tests := []struct {
+		name      string
+		newServer func(http.Handler) *httptest.Server
+		want      string
+	}{
+		{name: "https", newServer: httptest.NewTLSServer, want: "Bearer secret"},
+		{name: "plain http", newServer: httptest.NewServer, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authorization string
+			srv := tt.newServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorization = r.Header.Get("Authorization")
+			}))
+			defer srv.Close()
+
+			uploader, err := NewUploader(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			uploader.client = srv.Client()
+			uploader.SetBearerToken("secret")
+
+			report := checker.NewEnvironmentReport(nil, "tools.yaml", nil)
+			if err := uploader.Upload(context.Background(), *report); err != nil {
+				t.Fatalf("Upload() error = %v", err)
+			}
+			if authorization != tt.want {
+				t.Errorf("Authorization = %q, want %q", authorization, tt.want)
+			}
+		})
+	}
+}
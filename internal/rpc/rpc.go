@@ -0,0 +1,294 @@
+// Package rpc implements the JSON-RPC 2.0 backend behind `goctor doctor lsp`
+//
+// Messages use the Language Server Protocol base framing (a Content-Length header, a blank
+// line, then the JSON body) so editor extensions can reuse their existing LSP client
+// libraries. Requests are handled one at a time; results of running checks are streamed as
+// "goctor/result" notifications before the request's response.
+//
+// Methods:
+//
+//	initialize       server name, version, and supported methods
+//	goctor/tools     the manifest's tools
+//	goctor/check     check the tools with the given IDs (all tools if none), streaming results
+//	shutdown         acknowledge; the client then sends exit
+//	exit             stop serving (notification)
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// Method names
+const (
+	MethodInitialize = "initialize"
+	MethodTools      = "goctor/tools"
+	MethodCheck      = "goctor/check"
+	MethodShutdown   = "shutdown"
+	MethodExit       = "exit"
+
+	// NotificationResult carries one check result while goctor/check runs
+	NotificationResult = "goctor/result"
+)
+
+// Methods lists the requests the server answers
+var Methods = []string{MethodInitialize, MethodTools, MethodCheck, MethodShutdown, MethodExit}
+
+// JSON-RPC error codes
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Backend provides the manifest and runs checks for the server
+type Backend interface {
+	// Tools returns the tools of the current manifest
+	Tools(ctx context.Context) ([]manifest.ToolDefinition, error)
+	// Check checks the tools with the given IDs, or all tools if ids is empty, calling
+	// onResult as each check finishes
+	Check(ctx context.Context, ids []string, onResult func(checker.CheckResult)) (*checker.EnvironmentReport, error)
+}
+
+// Server answers JSON-RPC requests from one client
+type Server struct {
+	backend Backend
+	name    string
+	version string
+
+	out     io.Writer
+	writeMu sync.Mutex
+}
+
+// NewServer creates a server reporting the given goctor version
+func NewServer(backend Backend, version string) *Server {
+	return &Server{backend: backend, name: "goctor", version: version}
+}
+
+// request is an incoming request or notification; notifications have no ID
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is sent for every request with an ID
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// notification is a message from the server that expects no answer
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// Error is a JSON-RPC error object
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Serve reads requests from in and writes responses to out until exit or end of input
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+	reader := bufio.NewReader(in)
+
+	for {
+		body, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			s.reply(nil, nil, &Error{Code: CodeParseError, Message: err.Error()})
+			continue
+		}
+		if req.Method == MethodExit {
+			return nil
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			s.reply(req.ID, nil, &Error{Code: CodeInvalidRequest, Message: "invalid request"})
+			continue
+		}
+
+		result, rpcErr := s.handle(ctx, req)
+		if len(req.ID) > 0 {
+			s.reply(req.ID, result, rpcErr)
+		}
+	}
+}
+
+// initializeResult describes the server
+type initializeResult struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Methods []string `json:"methods"`
+}
+
+// checkParams are the parameters of goctor/check
+type checkParams struct {
+	IDs []string `json:"ids,omitempty"`
+}
+
+// checkResponse is the response of goctor/check
+type checkResponse struct {
+	Summary  checker.CheckSummary `json:"summary"`
+	ExitCode int                  `json:"exit_code"`
+	Results  []ResultParams       `json:"results"`
+}
+
+// ResultParams is one check result with the quick fix an editor can offer for it
+type ResultParams struct {
+	Result   checker.CheckResult `json:"result"`
+	QuickFix *QuickFix           `json:"quick_fix,omitempty"`
+}
+
+// QuickFix is remediation metadata for a result that needs attention
+type QuickFix struct {
+	Title          string            `json:"title"`
+	InstallCommand string            `json:"install_command,omitempty"`
+	Links          map[string]string `json:"links,omitempty"`
+}
+
+// handle dispatches a request to its method
+func (s *Server) handle(ctx context.Context, req request) (interface{}, *Error) {
+	switch req.Method {
+	case MethodInitialize:
+		return initializeResult{Name: s.name, Version: s.version, Methods: Methods}, nil
+	case MethodShutdown:
+		return struct{}{}, nil
+	case MethodTools:
+		tools, err := s.backend.Tools(ctx)
+		if err != nil {
+			return nil, &Error{Code: CodeInternalError, Message: err.Error()}
+		}
+		return map[string]interface{}{"tools": tools}, nil
+	case MethodCheck:
+		var params checkParams
+		if len(req.Params) > 0 {
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+			}
+		}
+		return s.check(ctx, params)
+	default:
+		return nil, &Error{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+// check runs the requested checks, streaming each result as a notification
+func (s *Server) check(ctx context.Context, params checkParams) (interface{}, *Error) {
+	report, err := s.backend.Check(ctx, params.IDs, func(result checker.CheckResult) {
+		s.notify(NotificationResult, withQuickFix(result))
+	})
+	if err != nil {
+		return nil, &Error{Code: CodeInternalError, Message: err.Error()}
+	}
+
+	response := checkResponse{
+		Summary:  report.Summary,
+		ExitCode: report.GetExitCode(),
+		Results:  make([]ResultParams, len(report.Items)),
+	}
+	for i, item := range report.Items {
+		response.Results[i] = withQuickFix(item)
+	}
+
+	return response, nil
+}
+
+// withQuickFix attaches remediation metadata to results that need attention
+func withQuickFix(result checker.CheckResult) ResultParams {
+	params := ResultParams{Result: result}
+	if !result.NeedsAttention() {
+		return params
+	}
+	if result.InstallHint == "" && len(result.Links) == 0 {
+		return params
+	}
+
+	title := "Install " + result.ToolName
+	if result.Status == checker.StatusOutdated || result.BelowRecommended {
+		title = "Upgrade " + result.ToolName
+	}
+	if result.Deprecated {
+		title = "Migrate away from " + result.ToolName
+	}
+	params.QuickFix = &QuickFix{Title: title, InstallCommand: result.InstallHint, Links: result.Links}
+	return params
+}
+
+// reply sends the response to a request
+func (s *Server) reply(id json.RawMessage, result interface{}, rpcErr *Error) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	s.write(response{JSONRPC: "2.0", ID: id, Result: result, Error: rpcErr})
+}
+
+// notify sends a notification
+func (s *Server) notify(method string, params interface{}) {
+	s.write(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// write frames and sends one message; failures are dropped since the client is gone
+func (s *Server) write(message interface{}) {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+// readMessage reads one framed message body
+func readMessage(reader *bufio.Reader) ([]byte, error) {
+	headers, err := textproto.NewReader(reader).ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(headers) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("invalid message header: %v", err)
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(headers.Get("Content-Length")))
+	if err != nil || length < 0 {
+		return nil, errors.New("invalid or missing Content-Length header")
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, fmt.Errorf("failed to read message body: %v", err)
+	}
+
+	return body, nil
+}
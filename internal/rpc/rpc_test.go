@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+type fakeBackend struct {
+	checkedIDs []string
+}
+
+func (fb *fakeBackend) Tools(ctx context.Context) ([]manifest.ToolDefinition, error) {
+	return []manifest.ToolDefinition{{ID: "go", Name: "Go"}, {ID: "docker", Name: "Docker"}}, nil
+}
+
+func (fb *fakeBackend) Check(ctx context.Context, ids []string, onResult func(checker.CheckResult)) (*checker.EnvironmentReport, error) {
+	fb.checkedIDs = ids
+	if len(ids) == 1 && ids[0] == "missing" {
+		return nil, errors.New(`tool "missing" not found in manifest`)
+	}
+
+	results := []checker.CheckResult{
+		{ToolID: "go", ToolName: "Go", Status: checker.StatusOK},
+		{ToolID: "docker", ToolName: "Docker", Status: checker.StatusNotFound, InstallHint: "brew install --cask docker"},
+	}
+	for _, result := range results {
+		onResult(result)
+	}
+	return checker.NewEnvironmentReport(nil, "tools.yaml", results), nil
+}
+
+// frame encodes messages with LSP base framing
+func frame(messages ...string) *bytes.Buffer {
+	var buf bytes.Buffer
+	for _, message := range messages {
+		fmt.Fprintf(&buf, "Content-Length: %d\r\n\r\n%s", len(message), message)
+	}
+	return &buf
+}
+
+// decodeAll reads every framed message written by the server
+func decodeAll(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	reader := bufio.NewReader(out)
+	var messages []map[string]interface{}
+	for {
+		body, err := readMessage(reader)
+		if err != nil {
+			break
+		}
+		var message map[string]interface{}
+		if err := json.Unmarshal(body, &message); err != nil {
+			t.Fatalf("Invalid message %s: %v", body, err)
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+func TestServe(t *testing.T) {
+	backend := &fakeBackend{}
+	in := frame(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"goctor/tools"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"goctor/check","params":{"ids":["docker"]}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"goctor/unknown"}`,
+		`{"jsonrpc":"2.0","id":5,"method":"goctor/check","params":{"ids":["missing"]}}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":6,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+		`{"jsonrpc":"2.0","id":7,"method":"initialize"}`,
+	)
+	var out bytes.Buffer
+
+	if err := NewServer(backend, "v1.2.3").Serve(context.Background(), in, &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	messages := decodeAll(t, &out)
+
+	summaries := make([]string, len(messages))
+	for i, message := range messages {
+		switch {
+		case message["method"] != nil:
+			summaries[i] = fmt.Sprint(message["method"])
+		case message["error"] != nil:
+			summaries[i] = fmt.Sprintf("%v:error %v", message["id"], message["error"].(map[string]interface{})["code"])
+		default:
+			summaries[i] = fmt.Sprintf("%v:ok", message["id"])
+		}
+	}
+	want := []string{
+		"1:ok",
+		"2:ok",
+		NotificationResult,
+		NotificationResult,
+		"3:ok",
+		"4:error -32601",
+		"5:error -32603",
+		"<nil>:error -32700",
+		"6:ok",
+	}
+	if strings.Join(summaries, ",") != strings.Join(want, ",") {
+		t.Fatalf("Unexpected message sequence\ngot:  %v\nwant: %v", summaries, want)
+	}
+
+	if len(backend.checkedIDs) != 1 || backend.checkedIDs[0] != "missing" {
+		t.Errorf("Expected the IDs to reach the backend, got %v", backend.checkedIDs)
+	}
+
+	// The missing tool's notification carries a quick fix; the passing one doesn't
+	notification := messages[3]["params"].(map[string]interface{})
+	quickFix, ok := notification["quick_fix"].(map[string]interface{})
+	if !ok || quickFix["install_command"] != "brew install --cask docker" || quickFix["title"] != "Install Docker" {
+		t.Errorf("Expected install quick fix, got %v", notification)
+	}
+	if _, ok := messages[2]["params"].(map[string]interface{})["quick_fix"]; ok {
+		t.Errorf("Expected no quick fix for a passing tool")
+	}
+}
+
+func TestReadMessageErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing length", "X-Other: 1\r\n\r\n{}"},
+		{"short body", "Content-Length: 10\r\n\r\n{}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readMessage(bufio.NewReader(strings.NewReader(tt.input))); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+// Package scheduler runs interdependent tasks with bounded parallelism
+//
+// It is the single place that decides when checks run, so one-shot, watch, serve, and
+// daemon modes share the same semantics:
+//
+//   - Parallelism bounds how many tasks run at once
+//   - Among tasks that are ready, higher Priority starts first, then input order
+//   - A task starts only after all of its DependsOn tasks succeeded; if one failed or
+//     was skipped, the task is skipped instead
+//   - FailFast stops starting new tasks after the first failure, cancels the context of
+//     running tasks, and skips everything else; errors wrapping ErrNonFatal only fail
+//     the task's dependents
+//   - MinInterval spaces out task starts to rate-limit expensive commands
+//
+// Results are always returned in input order
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Task is a unit of work; Run returns an error to mark the task as failed
+type Task struct {
+	ID        string
+	Priority  int
+	DependsOn []string
+	Run       func(ctx context.Context) error
+}
+
+// Options control how tasks are scheduled
+type Options struct {
+	// Parallelism is the maximum number of tasks running at once; values below 1 mean 1
+	Parallelism int
+	// FailFast stops scheduling after the first failed task
+	FailFast bool
+	// MinInterval is the minimum delay between two task starts
+	MinInterval time.Duration
+}
+
+// ErrNonFatal can be wrapped by a task's error to skip its dependents without stopping
+// the run under FailFast
+var ErrNonFatal = errors.New("non-fatal failure")
+
+// Skip reasons
+const (
+	SkipDependencyFailed = "dependency failed"
+	SkipFailFast         = "fail-fast"
+	SkipCanceled         = "canceled"
+)
+
+// Result is the outcome of one task
+type Result struct {
+	ID string
+	// Err is the error returned by the task, nil on success or when skipped
+	Err error
+	// Skipped is true if the task never ran; SkipReason says why
+	Skipped    bool
+	SkipReason string
+}
+
+// Failed returns true if the task ran and returned an error
+func (r Result) Failed() bool {
+	return !r.Skipped && r.Err != nil
+}
+
+// Validate checks that task IDs are unique, dependencies exist, and there are no cycles
+func Validate(tasks []Task) error {
+	index := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		if task.ID == "" {
+			return fmt.Errorf("task %d has no ID", i)
+		}
+		if _, exists := index[task.ID]; exists {
+			return fmt.Errorf("duplicate task ID: %s", task.ID)
+		}
+		index[task.ID] = i
+	}
+
+	for _, task := range tasks {
+		for _, dep := range task.DependsOn {
+			if _, exists := index[dep]; !exists {
+				return fmt.Errorf("task %s depends on unknown task %s", task.ID, dep)
+			}
+		}
+	}
+
+	// Depth-first search; a task reached again while still on the stack closes a cycle
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(tasks))
+	var stack []string
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			start := 0
+			for j, id := range stack {
+				if id == tasks[i].ID {
+					start = j
+				}
+			}
+			cycle := append(stack[start:], tasks[i].ID)
+			return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[i] = visiting
+		stack = append(stack, tasks[i].ID)
+		for _, dep := range tasks[i].DependsOn {
+			if err := visit(index[dep]); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[i] = done
+		return nil
+	}
+
+	for i := range tasks {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// completion is sent by a worker when a task finishes
+type completion struct {
+	index int
+	err   error
+}
+
+// Run executes the tasks according to opts and returns one result per task in input order
+// An error is returned only if the task graph is invalid, in which case nothing runs
+func Run(ctx context.Context, tasks []Task, opts Options) ([]Result, error) {
+	if err := Validate(tasks); err != nil {
+		return nil, err
+	}
+
+	parallelism := opts.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	index := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		index[task.ID] = i
+	}
+
+	results := make([]Result, len(tasks))
+	finished := make([]bool, len(tasks))
+	started := make([]bool, len(tasks))
+	for i, task := range tasks {
+		results[i].ID = task.ID
+	}
+
+	completions := make(chan completion)
+	running := 0
+	remaining := len(tasks)
+	stopped := ""
+	var lastStart time.Time
+
+	skip := func(i int, reason string) {
+		results[i].Skipped = true
+		results[i].SkipReason = reason
+		finished[i] = true
+		remaining--
+	}
+
+	for remaining > 0 {
+		if stopped == "" && ctx.Err() != nil {
+			stopped = SkipCanceled
+		}
+
+		// Resolve tasks whose fate is decided without running them
+		for changed := true; changed; {
+			changed = false
+			for i, task := range tasks {
+				if finished[i] || started[i] {
+					continue
+				}
+				if stopped != "" {
+					skip(i, stopped)
+					changed = true
+					continue
+				}
+				for _, dep := range task.DependsOn {
+					d := index[dep]
+					if finished[d] && (results[d].Skipped || results[d].Err != nil) {
+						skip(i, SkipDependencyFailed+": "+dep)
+						changed = true
+						break
+					}
+				}
+			}
+		}
+
+		// Start ready tasks, highest priority first, until the parallelism limit is reached
+		for _, i := range readyTasks(tasks, index, started, finished) {
+			if running >= parallelism || stopped != "" {
+				break
+			}
+			if opts.MinInterval > 0 && !lastStart.IsZero() {
+				if wait := opts.MinInterval - time.Since(lastStart); wait > 0 {
+					select {
+					case <-time.After(wait):
+					case <-ctx.Done():
+					}
+					if ctx.Err() != nil {
+						break
+					}
+				}
+			}
+
+			started[i] = true
+			running++
+			lastStart = time.Now()
+			go func(i int) {
+				completions <- completion{index: i, err: tasks[i].Run(ctx)}
+			}(i)
+		}
+
+		if running == 0 {
+			// Nothing can start and nothing is running: only skipped tasks remain
+			continue
+		}
+
+		done := <-completions
+		running--
+		results[done.index].Err = done.err
+		finished[done.index] = true
+		remaining--
+
+		if done.err != nil && opts.FailFast && stopped == "" && !errors.Is(done.err, ErrNonFatal) {
+			stopped = SkipFailFast
+			cancel()
+		}
+	}
+
+	return results, nil
+}
+
+// readyTasks returns the unstarted tasks whose dependencies all succeeded, by descending priority
+func readyTasks(tasks []Task, index map[string]int, started, finished []bool) []int {
+	var ready []int
+
+	for i, task := range tasks {
+		if started[i] || finished[i] {
+			continue
+		}
+		isReady := true
+		for _, dep := range task.DependsOn {
+			if !finished[index[dep]] {
+				isReady = false
+				break
+			}
+		}
+		if isReady {
+			ready = append(ready, i)
+		}
+	}
+
+	sort.SliceStable(ready, func(a, b int) bool {
+		return tasks[ready[a]].Priority > tasks[ready[b]].Priority
+	})
+
+	return ready
+}
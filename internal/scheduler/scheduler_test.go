@@ -0,0 +1,259 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recorder collects the order in which tasks start
+type recorder struct {
+	mu    sync.Mutex
+	order []string
+}
+
+func (r *recorder) task(id string, priority int, deps []string, err error) Task {
+	return Task{
+		ID:        id,
+		Priority:  priority,
+		DependsOn: deps,
+		Run: func(ctx context.Context) error {
+			r.mu.Lock()
+			r.order = append(r.order, id)
+			r.mu.Unlock()
+			return err
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	noop := func(context.Context) error { return nil }
+
+	tests := []struct {
+		name    string
+		tasks   []Task
+		wantErr string
+	}{
+		{"valid graph", []Task{{ID: "a", Run: noop}, {ID: "b", DependsOn: []string{"a"}, Run: noop}}, ""},
+		{"empty ID", []Task{{Run: noop}}, "task 0 has no ID"},
+		{"duplicate ID", []Task{{ID: "a", Run: noop}, {ID: "a", Run: noop}}, "duplicate task ID: a"},
+		{"unknown dependency", []Task{{ID: "a", DependsOn: []string{"b"}, Run: noop}}, "task a depends on unknown task b"},
+		{"self cycle", []Task{{ID: "a", DependsOn: []string{"a"}, Run: noop}}, "dependency cycle detected: a -> a"},
+		{"cycle", []Task{
+			{ID: "a", DependsOn: []string{"b"}, Run: noop},
+			{ID: "b", DependsOn: []string{"c"}, Run: noop},
+			{ID: "c", DependsOn: []string{"a"}, Run: noop},
+		}, "dependency cycle detected: a -> b -> c -> a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.tasks)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRunOrdering(t *testing.T) {
+	failure := errors.New("boom")
+
+	tests := []struct {
+		name      string
+		tasks     func(r *recorder) []Task
+		opts      Options
+		wantOrder []string
+		wantSkip  map[string]string
+	}{
+		{
+			name: "input order",
+			tasks: func(r *recorder) []Task {
+				return []Task{r.task("a", 0, nil, nil), r.task("b", 0, nil, nil), r.task("c", 0, nil, nil)}
+			},
+			wantOrder: []string{"a", "b", "c"},
+		},
+		{
+			name: "priority first",
+			tasks: func(r *recorder) []Task {
+				return []Task{r.task("a", 0, nil, nil), r.task("b", 5, nil, nil), r.task("c", 1, nil, nil)}
+			},
+			wantOrder: []string{"b", "c", "a"},
+		},
+		{
+			name: "dependencies before dependents",
+			tasks: func(r *recorder) []Task {
+				return []Task{r.task("a", 0, []string{"b"}, nil), r.task("b", 0, []string{"c"}, nil), r.task("c", 0, nil, nil)}
+			},
+			wantOrder: []string{"c", "b", "a"},
+		},
+		{
+			name: "failed dependency skips dependents transitively",
+			tasks: func(r *recorder) []Task {
+				return []Task{r.task("a", 0, nil, failure), r.task("b", 0, []string{"a"}, nil), r.task("c", 0, []string{"b"}, nil), r.task("d", 0, nil, nil)}
+			},
+			wantOrder: []string{"a", "d"},
+			wantSkip:  map[string]string{"b": "dependency failed: a", "c": "dependency failed: b"},
+		},
+		{
+			name: "fail-fast skips the rest",
+			tasks: func(r *recorder) []Task {
+				return []Task{r.task("a", 0, nil, nil), r.task("b", 0, nil, failure), r.task("c", 0, nil, nil)}
+			},
+			opts:      Options{FailFast: true},
+			wantOrder: []string{"a", "b"},
+			wantSkip:  map[string]string{"c": SkipFailFast},
+		},
+		{
+			name: "non-fatal failure skips dependents but not the rest under fail-fast",
+			tasks: func(r *recorder) []Task {
+				soft := fmt.Errorf("not passed: %w", ErrNonFatal)
+				return []Task{r.task("a", 0, nil, soft), r.task("b", 0, []string{"a"}, nil), r.task("c", 0, nil, nil)}
+			},
+			opts:      Options{FailFast: true},
+			wantOrder: []string{"a", "c"},
+			wantSkip:  map[string]string{"b": "dependency failed: a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			tasks := tt.tasks(r)
+
+			results, err := Run(context.Background(), tasks, tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if strings.Join(r.order, ",") != strings.Join(tt.wantOrder, ",") {
+				t.Errorf("Expected start order %v, got %v", tt.wantOrder, r.order)
+			}
+
+			for i, result := range results {
+				if result.ID != tasks[i].ID {
+					t.Errorf("Expected result %d to be %s, got %s", i, tasks[i].ID, result.ID)
+				}
+				reason, wantSkipped := tt.wantSkip[result.ID]
+				if result.Skipped != wantSkipped || result.SkipReason != reason {
+					t.Errorf("Task %s: expected skipped=%v (%q), got skipped=%v (%q)",
+						result.ID, wantSkipped, reason, result.Skipped, result.SkipReason)
+				}
+			}
+		})
+	}
+}
+
+func TestRunParallelism(t *testing.T) {
+	var running, peak int32
+	tasks := make([]Task, 8)
+	for i := range tasks {
+		tasks[i] = Task{
+			ID: string(rune('a' + i)),
+			Run: func(ctx context.Context) error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			},
+		}
+	}
+
+	if _, err := Run(context.Background(), tasks, Options{Parallelism: 3}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if peak < 2 || peak > 3 {
+		t.Errorf("Expected between 2 and 3 concurrent tasks, got %d", peak)
+	}
+}
+
+func TestRunFailFastCancelsRunningTasks(t *testing.T) {
+	canceled := make(chan struct{})
+	tasks := []Task{
+		{ID: "slow", Run: func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				close(canceled)
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+				return nil
+			}
+		}},
+		{ID: "fail", Run: func(ctx context.Context) error {
+			return errors.New("boom")
+		}},
+	}
+
+	results, err := Run(context.Background(), tasks, Options{Parallelism: 2, FailFast: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	select {
+	case <-canceled:
+	default:
+		t.Fatal("Expected the running task to be canceled")
+	}
+	if !results[1].Failed() {
+		t.Errorf("Expected fail to be reported as failed, got %+v", results[1])
+	}
+}
+
+func TestRunMinInterval(t *testing.T) {
+	var mu sync.Mutex
+	var starts []time.Time
+	tasks := make([]Task, 3)
+	for i := range tasks {
+		tasks[i] = Task{
+			ID: string(rune('a' + i)),
+			Run: func(ctx context.Context) error {
+				mu.Lock()
+				starts = append(starts, time.Now())
+				mu.Unlock()
+				return nil
+			},
+		}
+	}
+
+	interval := 20 * time.Millisecond
+	if _, err := Run(context.Background(), tasks, Options{Parallelism: 3, MinInterval: interval}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := 1; i < len(starts); i++ {
+		if gap := starts[i].Sub(starts[i-1]); gap < interval-2*time.Millisecond {
+			t.Errorf("Expected at least %v between starts, got %v", interval, gap)
+		}
+	}
+}
+
+func TestRunCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := &recorder{}
+	results, err := Run(ctx, []Task{r.task("a", 0, nil, nil)}, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(r.order) != 0 || !results[0].Skipped || results[0].SkipReason != SkipCanceled {
+		t.Errorf("Expected task to be skipped as canceled, got %+v (ran %v)", results[0], r.order)
+	}
+}
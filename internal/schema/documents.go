@@ -0,0 +1,198 @@
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/i18n"
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/output"
+	"github.com/ikorihn/goctor/internal/platform"
+	"github.com/ikorihn/goctor/internal/semver"
+)
+
+// BaseURL is where the schemas are published; each $id is BaseURL + FileName
+const BaseURL = "https://raw.githubusercontent.com/ikorihn/goctor/main/schema/"
+
+// Schema names
+const (
+	NameReport   = "report"
+	NameList     = "list"
+	NameManifest = "manifest"
+)
+
+// Names lists the published schemas
+var Names = []string{NameReport, NameList, NameManifest}
+
+// Version returns the schema version of a named schema, which is part of its file name and $id
+// Report and list versions are bumped when a field is removed or changes meaning; added
+// fields keep the version
+func Version(name string) (int, error) {
+	switch name {
+	case NameReport:
+		return checker.ReportSchemaVersion, nil
+	case NameList:
+		return output.ListSchemaVersion, nil
+	case NameManifest:
+		return manifest.CurrentVersion, nil
+	default:
+		return 0, fmt.Errorf("unknown schema %q (must be one of: %s)", name, strings.Join(Names, ", "))
+	}
+}
+
+// FileName returns the published file name of a named schema, e.g. report.v1.schema.json
+func FileName(name string) (string, error) {
+	version, err := Version(name)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.v%d.schema.json", name, version), nil
+}
+
+// Lookup generates a named schema
+func Lookup(name string) (*Schema, error) {
+	fileName, err := FileName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var s *Schema
+	switch name {
+	case NameReport:
+		s = report()
+	case NameList:
+		s = list()
+	case NameManifest:
+		s = manifestSchema()
+	}
+
+	s.Schema = Draft
+	s.ID = BaseURL + fileName
+	return s, nil
+}
+
+// outputGenerator describes JSON output, where every field without omitempty is present
+var outputGenerator = Generator{
+	Tag:           "json",
+	RequireFields: true,
+	Overrides: map[reflect.Type]*Schema{
+		reflect.TypeFor[checker.Milliseconds](): {Type: "integer", Description: "Duration in milliseconds"},
+	},
+}
+
+// report describes `doctor --json`
+func report() *Schema {
+	s := outputGenerator.Generate(checker.EnvironmentReport{})
+	s.Title = "goctor environment report"
+	s.Description = "Output of goctor doctor --json"
+	s.Properties.Get("schema_version").Const = checker.ReportSchemaVersion
+	// The platform is declared as interface{} to keep checker independent of platform
+	s.Properties.Set("platform", outputGenerator.Generate(platform.PlatformInfo{}))
+
+	item := s.Properties.Get("items").Items
+	item.Properties.Set("status", &Schema{Type: "string", Enum: statuses()})
+	item.Properties.Get("severity").Enum = []any{manifest.SeverityError, manifest.SeverityWarning}
+	item.Properties.Get("scope").Enum = values(manifest.Scopes)
+	item.Properties.Get("sub_checks").Items.Properties.Set("status", &Schema{Type: "string", Enum: statuses()})
+	return s
+}
+
+// list describes `list --json`
+func list() *Schema {
+	s := outputGenerator.Generate(output.ListResponse{})
+	s.Title = "goctor tool list"
+	s.Description = "Output of goctor list --json"
+	s.Properties.Get("schema_version").Const = output.ListSchemaVersion
+
+	tool := s.Properties.Get("tools").Items
+	tool.Properties.Get("status").Enum = statuses()
+	tool.Properties.Get("severity").Enum = []any{manifest.SeverityError, manifest.SeverityWarning}
+	tool.Properties.Get("scope").Enum = values(manifest.Scopes)
+	return s
+}
+
+// manifestSchema describes the manifest in its YAML form; TOML and JSON manifests have the
+// same structure
+func manifestSchema() *Schema {
+	s := Generator{Tag: "yaml"}.Generate(manifest.Manifest{})
+	s.Title = "goctor manifest"
+	s.Description = "Tool requirements checked by goctor"
+	s.Required = []string{"meta"}
+
+	meta := s.Properties.Get("meta")
+	meta.Required = []string{"version"}
+	meta.Properties.Get("version").Enum = values(manifest.SupportedVersions)
+	meta.Properties.Get("language").Enum = values(i18n.Languages)
+
+	include := s.Properties.Get("include").Items
+	include.Required = []string{"source"}
+	include.Properties.Get("merge").Enum = values(manifest.MergeModes)
+	s.Properties.Get("include").Items = &Schema{
+		Description: "Manifest to include, or a map with source and merge",
+		OneOf:       []*Schema{{Type: "string"}, include},
+	}
+
+	tool := s.Properties.Get("tools").Items
+	tool.Required = []string{"id"}
+	tool.Properties.Set("require", &Schema{
+		Description: "Version constraint, or minimum and recommended tiers",
+		OneOf: []*Schema{
+			{Type: "string"},
+			Generator{Tag: "yaml"}.Generate(struct {
+				Minimum     string `yaml:"minimum"`
+				Recommended string `yaml:"recommended"`
+			}{}),
+		},
+	})
+	requireFrom := tool.Properties.Get("require_from")
+	requireFrom.Properties.Get("match").Enum = values(manifest.RequireMatches)
+	requireFrom.Required = []string{"file"}
+	tool.Properties.Set("require_from", &Schema{
+		Description: "Project file to read the version constraint from, or a map with file, key, and match",
+		OneOf:       []*Schema{{Type: "string"}, requireFrom},
+	})
+	excluded := tool.Properties.Get("exclude_versions").Items
+	excluded.Required = []string{"versions"}
+	tool.Properties.Get("exclude_versions").Items = &Schema{
+		Description: "Version constraint to block, or a map with versions and reason",
+		OneOf:       []*Schema{{Type: "string"}, excluded},
+	}
+	tool.Properties.Get("severity").Enum = []any{manifest.SeverityError, manifest.SeverityWarning}
+	tool.Properties.Get("scope").Enum = values(manifest.Scopes)
+	checkConfig(tool.Properties.Get("check"))
+	checkConfig(tool.Properties.Get("checks").Items.Properties.Get("check"))
+	return s
+}
+
+// checkConfig adds the fixed values of a check's fields
+func checkConfig(check *Schema) {
+	check.Properties.Get("type").Enum = values(manifest.CheckTypes)
+	check.Properties.Get("version_scheme").Enum = values(semver.SchemeNames())
+	check.Properties.Get("probe").Enum = values(slices.Sorted(maps.Keys(manifest.ServiceProbes)))
+}
+
+// statuses returns the check statuses as written in reports
+func statuses() []any {
+	return []any{
+		checker.StatusOK.String(),
+		checker.StatusMissing.String(),
+		checker.StatusNotFound.String(),
+		checker.StatusOutdated.String(),
+		checker.StatusError.String(),
+		checker.StatusSkipped.String(),
+		checker.StatusUnknown.String(),
+	}
+}
+
+// values converts a slice for Enum
+func values[T any](s []T) []any {
+	converted := make([]any, len(s))
+	for i, v := range s {
+		converted[i] = v
+	}
+	return converted
+}
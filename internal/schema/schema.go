@@ -0,0 +1,202 @@
+// Package schema generates JSON Schemas of goctor's JSON output and of the manifest from the
+// Go types that encode them, so consumers can validate and generate code against them
+package schema
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of generated schemas
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema, limited to the keywords the generator uses
+type Schema struct {
+	Schema               string      `json:"$schema,omitempty"`
+	ID                   string      `json:"$id,omitempty"`
+	Title                string      `json:"title,omitempty"`
+	Description          string      `json:"description,omitempty"`
+	Type                 any         `json:"type,omitempty"` // a type name, or several
+	Format               string      `json:"format,omitempty"`
+	Enum                 []any       `json:"enum,omitempty"`
+	Const                any         `json:"const,omitempty"`
+	Properties           *Properties `json:"properties,omitempty"`
+	Required             []string    `json:"required,omitempty"`
+	AdditionalProperties *Schema     `json:"additionalProperties,omitempty"`
+	Items                *Schema     `json:"items,omitempty"`
+	OneOf                []*Schema   `json:"oneOf,omitempty"`
+}
+
+// Properties are the properties of an object schema, kept in the order of the struct fields
+type Properties struct {
+	names   []string
+	schemas map[string]*Schema
+}
+
+// Set adds or replaces a property
+func (p *Properties) Set(name string, schema *Schema) {
+	if p.schemas == nil {
+		p.schemas = make(map[string]*Schema)
+	}
+	if _, ok := p.schemas[name]; !ok {
+		p.names = append(p.names, name)
+	}
+	p.schemas[name] = schema
+}
+
+// Get returns a property, or nil if there is none
+func (p *Properties) Get(name string) *Schema {
+	if p == nil {
+		return nil
+	}
+	return p.schemas[name]
+}
+
+// MarshalJSON encodes the properties as an object in field order
+func (p *Properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range p.names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.schemas[name])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Generator derives schemas from Go types
+type Generator struct {
+	// Tag is the struct tag naming fields: json for output, yaml for the manifest
+	Tag string
+	// RequireFields marks fields without omitempty as required, which holds for output the
+	// program writes but not for input it reads
+	RequireFields bool
+	// Overrides are the schemas of types that encode themselves, such as time.Time
+	Overrides map[reflect.Type]*Schema
+}
+
+var (
+	timeType        = reflect.TypeFor[time.Time]()
+	marshalerType   = reflect.TypeFor[json.Marshaler]()
+	textMarshalType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Generate returns the schema of the type of v
+func (g Generator) Generate(v any) *Schema {
+	return g.schema(reflect.TypeOf(v))
+}
+
+// schema returns the schema of t
+func (g Generator) schema(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	if override, ok := g.Overrides[t]; ok {
+		copied := *override
+		return &copied
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if t.Kind() == reflect.Pointer {
+		return g.schema(t.Elem())
+	}
+	if t.Implements(marshalerType) || t.Implements(textMarshalType) {
+		// The encoding is the type's own; without an override nothing is known about it
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: &Properties{}}
+		g.addFields(s, t)
+		return s
+	default:
+		// interface{} holds any value
+		return &Schema{}
+	}
+}
+
+// addFields adds the encoded fields of struct type t to s, flattening embedded structs the
+// way the encoders do
+func (g Generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get(g.Tag), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+
+		inline := strings.Contains(","+options+",", ",inline,")
+		if field.Anonymous && (name == "" || inline) {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(s, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+			if g.Tag == "yaml" {
+				name = strings.ToLower(name)
+			}
+		}
+		property := g.schema(field.Type)
+		if g.RequireFields && !strings.Contains(","+options+",", ",omitempty,") {
+			s.Required = append(s.Required, name)
+			// encoding/json writes a nil map or slice as null
+			if kind := field.Type.Kind(); kind == reflect.Map || kind == reflect.Slice {
+				if typeName, ok := property.Type.(string); ok {
+					property.Type = []string{typeName, "null"}
+				}
+			}
+		}
+		s.Properties.Set(name, property)
+	}
+}
+
+// Document returns the published form of a schema: pretty-printed with a trailing newline
+func Document(s *Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding schema: %v", err)
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,133 @@
+package schema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+type embedded struct {
+	Shared string `json:"shared"`
+}
+
+type sample struct {
+	embedded
+	Name     string            `json:"name" yaml:"name"`
+	Count    int               `json:"count,omitempty" yaml:"count,omitempty"`
+	Tags     []string          `json:"tags" yaml:"tags"`
+	Links    map[string]string `json:"links,omitempty" yaml:"links,omitempty"`
+	When     time.Time         `json:"when"`
+	Skipped  string            `json:"-" yaml:"-"`
+	Nested   *nested           `json:"nested,omitempty" yaml:"nested"`
+	Anything interface{}       `json:"anything,omitempty"`
+}
+
+type nested struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generator{Tag: "json", RequireFields: true}.Generate(sample{})
+
+	tests := []struct {
+		property string
+		want     *Schema
+	}{
+		{"shared", &Schema{Type: "string"}},
+		{"name", &Schema{Type: "string"}},
+		{"count", &Schema{Type: "integer"}},
+		{"tags", &Schema{Type: []string{"array", "null"}, Items: &Schema{Type: "string"}}},
+		{"links", &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}},
+		{"when", &Schema{Type: "string", Format: "date-time"}},
+		{"anything", &Schema{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.property, func(t *testing.T) {
+			got := s.Properties.Get(tt.property)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("property %s = %+v, want %+v", tt.property, got, tt.want)
+			}
+		})
+	}
+
+	if s.Properties.Get("Skipped") != nil || s.Properties.Get("-") != nil {
+		t.Error("fields tagged \"-\" should be left out")
+	}
+	if nested := s.Properties.Get("nested"); nested == nil || nested.Properties.Get("enabled") == nil {
+		t.Errorf("nested = %+v, want an object with enabled", nested)
+	}
+
+	wantRequired := []string{"shared", "name", "tags", "when"}
+	if !slices.Equal(s.Required, wantRequired) {
+		t.Errorf("Required = %v, want %v", s.Required, wantRequired)
+	}
+}
+
+func TestGenerateYAMLInput(t *testing.T) {
+	s := Generator{Tag: "yaml"}.Generate(sample{})
+
+	if len(s.Required) != 0 {
+		t.Errorf("Required = %v, want none for input", s.Required)
+	}
+	if got := s.Properties.Get("tags").Type; got != "array" {
+		t.Errorf("tags type = %v, want array", got)
+	}
+	if s.Properties.Get("when") == nil {
+		t.Error("a field without a yaml tag should use its lowercased name")
+	}
+}
+
+func TestPropertiesKeepFieldOrder(t *testing.T) {
+	s := Generator{Tag: "json"}.Generate(nested{})
+	s.Properties.Set("zeta", &Schema{Type: "string"})
+	s.Properties.Set("alpha", &Schema{Type: "string"})
+
+	data, err := json.Marshal(s.Properties)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `{"enabled":{"type":"boolean"},"zeta":{"type":"string"},"alpha":{"type":"string"}}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestLookupUnknown(t *testing.T) {
+	if _, err := Lookup("bogus"); err == nil {
+		t.Error("expected an error for an unknown schema")
+	}
+}
+
+// TestPublishedSchemas fails when a struct changed without regenerating schema/ with
+// `goctor schema -o schema`; a removed or changed field also needs a schema version bump
+func TestPublishedSchemas(t *testing.T) {
+	for _, name := range Names {
+		t.Run(name, func(t *testing.T) {
+			s, err := Lookup(name)
+			if err != nil {
+				t.Fatalf("Lookup() error = %v", err)
+			}
+			generated, err := Document(s)
+			if err != nil {
+				t.Fatalf("Document() error = %v", err)
+			}
+
+			fileName, err := FileName(name)
+			if err != nil {
+				t.Fatalf("FileName() error = %v", err)
+			}
+			published, err := os.ReadFile(filepath.Join("..", "..", "schema", fileName))
+			if err != nil {
+				t.Fatalf("reading published schema: %v", err)
+			}
+			if string(published) != string(generated) {
+				t.Errorf("schema/%s is out of date; run goctor schema -o schema", fileName)
+			}
+		})
+	}
+}
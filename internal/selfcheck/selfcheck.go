@@ -0,0 +1,247 @@
+package selfcheck
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// Status is the outcome of a single self-check
+type Status string
+
+const (
+	StatusOK      Status = "ok"
+	StatusWarning Status = "warning"
+	StatusFail    Status = "fail"
+	StatusSkipped Status = "skipped"
+)
+
+// MaxClockSkew is the largest clock difference to a manifest server that is considered sane
+const MaxClockSkew = 5 * time.Minute
+
+// Finding is the result of one self-check
+type Finding struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+}
+
+// Options describes the goctor setup to diagnose
+type Options struct {
+	// Loader is the configured manifest loader; nil if configuration failed
+	Loader *manifest.Loader
+	// ConfigErrors are errors from parsing flags, environment variables, and credential files
+	ConfigErrors []error
+	// ManifestSource is the manifest file path or URL
+	ManifestSource string
+	// CacheDir is the directory goctor caches data in
+	CacheDir string
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+}
+
+// Run performs all self-checks in a fixed order
+func Run(opts Options) []Finding {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	findings := []Finding{
+		checkConfig(opts.ConfigErrors),
+		checkCacheDir(opts.CacheDir),
+	}
+
+	m, manifestFinding := checkManifest(opts.Loader, opts.ManifestSource)
+	findings = append(findings,
+		manifestFinding,
+		checkClockSkew(opts.Loader, opts.ManifestSource, opts.Now),
+		checkPlugins(m),
+	)
+
+	return findings
+}
+
+// HasFailures returns true if any finding failed
+func HasFailures(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConfig reports configuration errors collected while starting up
+func checkConfig(configErrors []error) Finding {
+	finding := Finding{Name: "config", Status: StatusOK, Message: "flags, environment, and credential files parse"}
+
+	if len(configErrors) > 0 {
+		finding.Status = StatusFail
+		finding.Message = joinErrors(configErrors)
+	}
+
+	return finding
+}
+
+// checkCacheDir verifies the cache directory, or the directory it would be created in, is writable
+func checkCacheDir(cacheDir string) Finding {
+	finding := Finding{Name: "cache_dir", Status: StatusOK}
+
+	if cacheDir == "" {
+		finding.Status = StatusFail
+		finding.Message = "cache directory could not be determined"
+		return finding
+	}
+
+	dir := cacheDir
+	if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
+		// The cache is created on demand, so its closest existing parent must be writable
+		for {
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+			if _, err := os.Stat(dir); err == nil {
+				break
+			}
+		}
+	}
+
+	probe, err := os.CreateTemp(dir, ".goctor-selfcheck-*")
+	if err != nil {
+		finding.Status = StatusFail
+		finding.Message = fmt.Sprintf("%s is not writable: %v", dir, err)
+		return finding
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	finding.Message = cacheDir + " is writable"
+	if dir != cacheDir {
+		finding.Message = fmt.Sprintf("%s does not exist yet; %s is writable", cacheDir, dir)
+	}
+
+	return finding
+}
+
+// checkManifest verifies the manifest source is reachable and valid
+func checkManifest(loader *manifest.Loader, source string) (*manifest.Manifest, Finding) {
+	finding := Finding{Name: "manifest", Status: StatusOK}
+
+	if loader == nil {
+		finding.Status = StatusSkipped
+		finding.Message = "skipped because the configuration is invalid"
+		return nil, finding
+	}
+
+	m, err := loader.LoadFromSource(source)
+	if err != nil {
+		finding.Status = StatusFail
+		finding.Message = err.Error()
+		return nil, finding
+	}
+
+	finding.Message = fmt.Sprintf("%s loaded (%d tools)", source, len(m.Tools))
+	return m, finding
+}
+
+// checkClockSkew compares the local clock with a remote manifest server's clock
+func checkClockSkew(loader *manifest.Loader, source string, now func() time.Time) Finding {
+	finding := Finding{Name: "clock_skew", Status: StatusOK}
+
+	if loader == nil || !manifest.IsURL(source) {
+		finding.Status = StatusSkipped
+		finding.Message = "only checked for remote manifests"
+		return finding
+	}
+
+	serverTime, err := loader.ServerTime(source)
+	if err != nil {
+		finding.Status = StatusWarning
+		finding.Message = err.Error()
+		return finding
+	}
+
+	skew := now().Sub(serverTime).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+
+	finding.Message = fmt.Sprintf("local clock is within %s of the manifest server", skew)
+	if skew > MaxClockSkew {
+		finding.Status = StatusWarning
+		finding.Message = fmt.Sprintf("local clock differs from the manifest server by %s; cached manifests may expire incorrectly", skew)
+	}
+
+	return finding
+}
+
+// checkPlugins verifies every check plugin referenced by the manifest exists and is executable
+func checkPlugins(m *manifest.Manifest) Finding {
+	finding := Finding{Name: "plugins", Status: StatusOK}
+
+	if m == nil {
+		finding.Status = StatusSkipped
+		finding.Message = "skipped because the manifest could not be loaded"
+		return finding
+	}
+
+	var plugins []string
+	for _, tool := range m.Tools {
+		if tool.IsPlugin() {
+			plugins = append(plugins, tool.Check.Plugin)
+		}
+		for _, sub := range tool.Checks {
+			if sub.Check.Plugin != "" {
+				plugins = append(plugins, sub.Check.Plugin)
+			}
+		}
+	}
+
+	if len(plugins) == 0 {
+		finding.Message = "no plugins referenced"
+		return finding
+	}
+
+	var problems []error
+	for _, plugin := range plugins {
+		path, err := checker.ResolvePluginPath(plugin)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if info.Mode().Perm()&0111 == 0 {
+			problems = append(problems, fmt.Errorf("plugin is not executable: %s", plugin))
+		}
+	}
+
+	if len(problems) > 0 {
+		finding.Status = StatusFail
+		finding.Message = joinErrors(problems)
+		return finding
+	}
+
+	finding.Message = fmt.Sprintf("%d plugins found and executable", len(plugins))
+	return finding
+}
+
+// joinErrors joins error messages into a single line
+func joinErrors(errs []error) string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
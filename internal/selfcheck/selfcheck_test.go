@@ -0,0 +1,128 @@
+package selfcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+func TestCheckConfig(t *testing.T) {
+	if finding := checkConfig(nil); finding.Status != StatusOK {
+		t.Errorf("Expected ok without errors, got %+v", finding)
+	}
+
+	finding := checkConfig([]error{errors.New("bad header"), errors.New("bad resolver")})
+	if finding.Status != StatusFail || finding.Message != "bad header; bad resolver" {
+		t.Errorf("Expected failure listing both errors, got %+v", finding)
+	}
+}
+
+func TestCheckCacheDir(t *testing.T) {
+	dir := t.TempDir()
+
+	if finding := checkCacheDir(dir); finding.Status != StatusOK {
+		t.Errorf("Expected existing writable directory to pass, got %+v", finding)
+	}
+
+	if finding := checkCacheDir(filepath.Join(dir, "goctor", "nested")); finding.Status != StatusOK {
+		t.Errorf("Expected missing directory with writable parent to pass, got %+v", finding)
+	}
+
+	if os.Geteuid() != 0 {
+		readOnly := filepath.Join(dir, "readonly")
+		if err := os.Mkdir(readOnly, 0555); err != nil {
+			t.Fatal(err)
+		}
+		if finding := checkCacheDir(readOnly); finding.Status != StatusFail {
+			t.Errorf("Expected read-only directory to fail, got %+v", finding)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != "readonly" {
+			t.Errorf("Expected probe files to be cleaned up, found %s", entry.Name())
+		}
+	}
+}
+
+func TestCheckClockSkew(t *testing.T) {
+	serverTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+	}))
+	defer server.Close()
+
+	loader := manifest.NewLoader()
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected Status
+	}{
+		{"in sync", serverTime.Add(30 * time.Second), StatusOK},
+		{"ahead", serverTime.Add(10 * time.Minute), StatusWarning},
+		{"behind", serverTime.Add(-10 * time.Minute), StatusWarning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding := checkClockSkew(loader, server.URL+"/tools.yaml", func() time.Time { return tt.now })
+			if finding.Status != tt.expected {
+				t.Errorf("Expected %s, got %+v", tt.expected, finding)
+			}
+		})
+	}
+
+	if finding := checkClockSkew(loader, "./tools.yaml", time.Now); finding.Status != StatusSkipped {
+		t.Errorf("Expected local manifests to be skipped, got %+v", finding)
+	}
+}
+
+func TestCheckPlugins(t *testing.T) {
+	dir := t.TempDir()
+	executable := filepath.Join(dir, "ok.sh")
+	if err := os.WriteFile(executable, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	notExecutable := filepath.Join(dir, "noexec.sh")
+	if err := os.WriteFile(notExecutable, []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		plugins  []string
+		expected Status
+	}{
+		{"executable", []string{executable}, StatusOK},
+		{"not executable", []string{executable, notExecutable}, StatusFail},
+		{"missing", []string{filepath.Join(dir, "missing.sh")}, StatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &manifest.Manifest{}
+			for _, plugin := range tt.plugins {
+				m.Tools = append(m.Tools, manifest.ToolDefinition{Check: manifest.CheckConfig{Plugin: plugin}})
+			}
+
+			if finding := checkPlugins(m); finding.Status != tt.expected {
+				t.Errorf("Expected %s, got %+v", tt.expected, finding)
+			}
+		})
+	}
+
+	if finding := checkPlugins(nil); finding.Status != StatusSkipped {
+		t.Errorf("Expected plugins to be skipped without a manifest, got %+v", finding)
+	}
+}
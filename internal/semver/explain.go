@@ -0,0 +1,154 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explain returns a human-readable description of the versions satisfying the constraint
+func (c Constraint) Explain() string {
+	v := c.Version
+
+	switch c.Operator {
+	case OpEqual:
+		return fmt.Sprintf("exactly %s", v)
+	case OpGreater:
+		return fmt.Sprintf("any version newer than %s", v)
+	case OpGreaterEqual:
+		return fmt.Sprintf("%s or newer", v)
+	case OpLess:
+		return fmt.Sprintf("any version older than %s", v)
+	case OpLessEqual:
+		return fmt.Sprintf("%s or older", v)
+	case OpNotEqual:
+		return fmt.Sprintf("any version except %s", v)
+	case OpTilde, OpCaret, OpPessimistic:
+		_, upper := c.Range()
+		switch {
+		case upper.Major > v.Major:
+			return fmt.Sprintf("%s or newer, but older than %s (minor and patch updates)", v, upper)
+		case upper.Minor > v.Minor && c.Operator == OpCaret && v.Major == 0:
+			return fmt.Sprintf("%s or newer, but older than %s (patch updates only for 0.x)", v, upper)
+		case upper.Minor > v.Minor:
+			return fmt.Sprintf("%s or newer, but older than %s (patch updates only)", v, upper)
+		case upper.Patch > v.Patch && len(v.Extra) > 0:
+			return fmt.Sprintf("%s or newer, but older than %s (updates after the patch version only)", v, upper)
+		default:
+			return fmt.Sprintf("exactly %s (0.0.x releases are treated as incompatible)", v)
+		}
+	default:
+		return c.String()
+	}
+}
+
+// ExplainConstraints parses a space-separated constraint string and describes it
+// Versions of the lenient scheme, such as 1.8.0_392, are accepted too
+func ExplainConstraints(constraintStr string) (string, error) {
+	constraints, err := ParseLenientConstraints(constraintStr)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, len(constraints))
+	for i, constraint := range constraints {
+		parts[i] = constraint.Explain()
+	}
+
+	return strings.Join(parts, ", and "), nil
+}
+
+// MinimumVersion returns the lowest version allowed by a constraint string, as written in it
+// Only inclusive lower bounds (=, >=, ~, ^, ~>) count; false is returned when there is none
+func MinimumVersion(constraintStr string) (string, bool) {
+	constraints, err := ParseConstraints(constraintStr)
+	if err != nil {
+		return "", false
+	}
+
+	var minimum string
+	var lowest Version
+	found := false
+	for i, constraint := range constraints {
+		switch constraint.Operator {
+		case OpEqual, OpGreaterEqual, OpTilde, OpCaret, OpPessimistic:
+		default:
+			continue
+		}
+		// Several lower bounds narrow the range, so the highest one is the real minimum
+		if !found || constraint.Version.Compare(lowest) > 0 {
+			lowest = constraint.Version
+			minimum = strings.TrimPrefix(strings.TrimLeft(constraintFields(constraintStr)[i], "<>=~^!"), "v")
+			found = true
+		}
+	}
+
+	return minimum, found
+}
+
+// Mismatch explains why a version fails a constraint
+type Mismatch struct {
+	// Version is the installed version as the scheme parsed it
+	Version string `json:"version"`
+	// Constraints are the normalized clauses of the constraint, all of which must hold
+	Constraints []string `json:"constraints"`
+	// Failed is the first clause the version does not satisfy
+	Failed string `json:"failed"`
+}
+
+// String describes the mismatch, e.g. "1.21.3 fails '>=1.22.0'"; the full constraint set
+// is added when it has more than one clause
+func (m Mismatch) String() string {
+	message := fmt.Sprintf("%s fails '%s'", m.Version, m.Failed)
+	if len(m.Constraints) > 1 {
+		message += fmt.Sprintf(" of '%s'", strings.Join(m.Constraints, " "))
+	}
+	return message
+}
+
+// ExplainMismatch returns why version fails the constraint string in the scheme, or nil
+// if the version satisfies it
+func ExplainMismatch(scheme Scheme, version, constraintStr string) (*Mismatch, error) {
+	parts, err := splitConstraints(constraintStr)
+	if err != nil {
+		return nil, err
+	}
+
+	parser, normalize := scheme.(versionParser)
+
+	mismatch := &Mismatch{Version: version, Constraints: make([]string, len(parts))}
+	if normalize {
+		parsed, err := parser.parseVersion(version)
+		if err != nil {
+			return nil, err
+		}
+		mismatch.Version = parsed.String()
+	}
+
+	for i, part := range parts {
+		clause := part.operator + part.version
+		if normalize {
+			constraints, err := parser.parseConstraints(clause)
+			if err != nil {
+				return nil, err
+			}
+			clause = constraints[0].String()
+		}
+		mismatch.Constraints[i] = clause
+
+		if mismatch.Failed != "" {
+			continue
+		}
+		satisfied, err := scheme.Satisfies(version, clause)
+		if err != nil {
+			return nil, err
+		}
+		if !satisfied {
+			mismatch.Failed = clause
+		}
+	}
+
+	if mismatch.Failed == "" {
+		return nil, nil
+	}
+	return mismatch, nil
+}
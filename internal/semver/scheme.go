@@ -0,0 +1,367 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Scheme is a versioning scheme that can compare versions and evaluate constraints
+type Scheme interface {
+	// Name returns the scheme identifier used in manifests (check.version_scheme)
+	Name() string
+	// Validate returns an error if the version string is not valid in this scheme
+	Validate(version string) error
+	// Satisfies reports whether the version satisfies a space-separated constraint string
+	Satisfies(version, constraint string) (bool, error)
+}
+
+// DefaultScheme is the scheme used when a tool does not specify one
+const DefaultScheme = "semver"
+
+var schemes = map[string]Scheme{}
+
+func init() {
+	RegisterScheme(semverScheme{})
+	RegisterScheme(calverScheme{})
+	RegisterScheme(looseScheme{})
+	RegisterScheme(lenientScheme{})
+}
+
+// RegisterScheme makes a version scheme available by name
+func RegisterScheme(scheme Scheme) {
+	schemes[scheme.Name()] = scheme
+}
+
+// GetScheme returns the scheme with the given name; an empty name selects the default scheme
+func GetScheme(name string) (Scheme, error) {
+	if name == "" {
+		name = DefaultScheme
+	}
+
+	scheme, exists := schemes[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown version scheme: %s (available: %s)", name, strings.Join(SchemeNames(), ", "))
+	}
+
+	return scheme, nil
+}
+
+// SchemeNames returns the names of all registered schemes in sorted order
+func SchemeNames() []string {
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateConstraintString checks that every part of a constraint string is valid in the scheme
+func ValidateConstraintString(scheme Scheme, constraintStr string) error {
+	parts, err := splitConstraints(constraintStr)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range parts {
+		if err := scheme.Validate(part.version); err != nil {
+			return err
+		}
+		if _, ok := scheme.(versionParser); !ok && (part.operator == "~" || part.operator == "^" || part.operator == "~>") {
+			return fmt.Errorf("operator %s is only supported by the semver and lenient schemes", part.operator)
+		}
+	}
+
+	return nil
+}
+
+// versionParser is implemented by the schemes built on Version and Constraint, which
+// normalize versions and support the range operators ~, ^, and ~>
+type versionParser interface {
+	parseVersion(version string) (Version, error)
+	parseConstraints(constraint string) ([]Constraint, error)
+}
+
+// satisfiesParsed evaluates a constraint string with a scheme built on Version
+func satisfiesParsed(parser versionParser, version, constraint string) (bool, error) {
+	actual, err := parser.parseVersion(version)
+	if err != nil {
+		return false, err
+	}
+
+	constraints, err := parser.parseConstraints(constraint)
+	if err != nil {
+		return false, err
+	}
+
+	return SatisfiesAll(actual, constraints), nil
+}
+
+// SatisfiesAllowingPrerelease is scheme.Satisfies, except that a prerelease also meets every
+// constraint its release meets: 1.0.0-rc.1 satisfies >=1.0.0, while ^1.2.3 still rejects
+// 2.0.0-rc.1. Schemes not built on Version have no prereleases and evaluate as usual
+func SatisfiesAllowingPrerelease(scheme Scheme, version, constraint string) (bool, error) {
+	parser, ok := scheme.(versionParser)
+	if !ok {
+		return scheme.Satisfies(version, constraint)
+	}
+
+	actual, err := parser.parseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	constraints, err := parser.parseConstraints(constraint)
+	if err != nil {
+		return false, err
+	}
+
+	release := actual
+	release.Prerelease = ""
+	for _, c := range constraints {
+		if !c.IsSatisfiedBy(actual) && !c.IsSatisfiedBy(release) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// semverScheme implements semantic versioning using Version and Constraint
+type semverScheme struct{}
+
+func (semverScheme) Name() string { return "semver" }
+
+func (semverScheme) Validate(version string) error {
+	_, err := ParseVersion(version)
+	return err
+}
+
+func (s semverScheme) Satisfies(version, constraint string) (bool, error) {
+	return satisfiesParsed(s, version, constraint)
+}
+
+func (semverScheme) parseVersion(version string) (Version, error) { return ParseVersion(version) }
+
+func (semverScheme) parseConstraints(constraint string) ([]Constraint, error) {
+	return ParseConstraints(constraint)
+}
+
+// lenientScheme is semver that also accepts vendor-style versions such as Java's 1.8.0_392
+// and Windows' 10.0.19041.1, comparing every numeric segment
+type lenientScheme struct{}
+
+func (lenientScheme) Name() string { return "lenient" }
+
+func (lenientScheme) Validate(version string) error {
+	_, err := ParseLenientVersion(version)
+	return err
+}
+
+func (s lenientScheme) Satisfies(version, constraint string) (bool, error) {
+	return satisfiesParsed(s, version, constraint)
+}
+
+func (lenientScheme) parseVersion(version string) (Version, error) {
+	return ParseLenientVersion(version)
+}
+
+func (lenientScheme) parseConstraints(constraint string) ([]Constraint, error) {
+	return ParseLenientConstraints(constraint)
+}
+
+// calverScheme implements calendar versioning such as 2024.10.1 or 24.04
+// Any number of dot-separated numeric segments is compared numerically
+type calverScheme struct{}
+
+var calverRegex = regexp.MustCompile(`^v?\d+(\.\d+)*$`)
+
+func (calverScheme) Name() string { return "calver" }
+
+func (calverScheme) Validate(version string) error {
+	if !calverRegex.MatchString(version) {
+		return fmt.Errorf("invalid calver version: %s", version)
+	}
+	return nil
+}
+
+func (s calverScheme) Satisfies(version, constraint string) (bool, error) {
+	if err := s.Validate(version); err != nil {
+		return false, err
+	}
+
+	return satisfiesWith(version, constraint, s.Validate, func(a, b string) int {
+		return compareSegments(splitLoose(a), splitLoose(b))
+	})
+}
+
+// looseScheme compares versions segment by segment, numerically where possible and
+// lexicographically otherwise, so versions like 1.1.1k (OpenSSL) order correctly
+type looseScheme struct{}
+
+func (looseScheme) Name() string { return "loose" }
+
+func (looseScheme) Validate(version string) error {
+	if strings.TrimSpace(version) == "" {
+		return errors.New("version string cannot be empty")
+	}
+	return nil
+}
+
+func (s looseScheme) Satisfies(version, constraint string) (bool, error) {
+	return satisfiesWith(version, constraint, s.Validate, func(a, b string) int {
+		return compareSegments(splitLoose(a), splitLoose(b))
+	})
+}
+
+// CompareVersions compares two version strings, using semver ordering when both parse
+// as semver and loose segment ordering otherwise
+func CompareVersions(a, b string) int {
+	va, errA := ParseVersion(a)
+	vb, errB := ParseVersion(b)
+	if errA == nil && errB == nil {
+		return va.Compare(vb)
+	}
+
+	return compareSegments(splitLoose(a), splitLoose(b))
+}
+
+// constraintPart is a single operator/version pair from a constraint string
+type constraintPart struct {
+	operator string
+	version  string
+}
+
+// splitConstraints splits a space-separated constraint string into operator/version pairs
+func splitConstraints(constraintStr string) ([]constraintPart, error) {
+	fields := constraintFields(constraintStr)
+	if len(fields) == 0 {
+		return nil, errors.New("constraint string cannot be empty")
+	}
+
+	parts := make([]constraintPart, len(fields))
+	for i, field := range fields {
+		matches := constraintRegex.FindStringSubmatch(field)
+		if matches == nil {
+			return nil, fmt.Errorf("invalid constraint format: %s", field)
+		}
+		parts[i] = constraintPart{operator: matches[1], version: matches[2]}
+	}
+
+	return parts, nil
+}
+
+// satisfiesWith evaluates comparison-only constraints using the given compare function
+func satisfiesWith(version, constraintStr string, validate func(string) error, compare func(a, b string) int) (bool, error) {
+	parts, err := splitConstraints(constraintStr)
+	if err != nil {
+		return false, err
+	}
+
+	for _, part := range parts {
+		if err := validate(part.version); err != nil {
+			return false, err
+		}
+
+		comparison := compare(version, part.version)
+
+		var ok bool
+		switch part.operator {
+		case "", "=":
+			ok = comparison == 0
+		case ">":
+			ok = comparison > 0
+		case ">=":
+			ok = comparison >= 0
+		case "<":
+			ok = comparison < 0
+		case "<=":
+			ok = comparison <= 0
+		case "!=":
+			ok = comparison != 0
+		default:
+			return false, fmt.Errorf("operator %s is only supported by the semver and lenient schemes", part.operator)
+		}
+
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// splitLoose splits a version into alternating numeric and non-numeric segments
+// e.g. "v1.1.1k" -> ["1", "1", "1", "k"]
+func splitLoose(version string) []string {
+	version = strings.TrimPrefix(version, "v")
+
+	var segments []string
+	var current strings.Builder
+	currentIsDigit := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			segments = append(segments, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range version {
+		isDigit := r >= '0' && r <= '9'
+		isAlpha := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+
+		if !isDigit && !isAlpha {
+			flush()
+			continue
+		}
+
+		if current.Len() > 0 && isDigit != currentIsDigit {
+			flush()
+		}
+
+		current.WriteRune(r)
+		currentIsDigit = isDigit
+	}
+	flush()
+
+	return segments
+}
+
+// compareSegments compares two segment lists; a missing segment orders before a present one
+func compareSegments(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		aNum, aErr := strconv.Atoi(a[i])
+		bNum, bErr := strconv.Atoi(b[i])
+
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNum != bNum {
+				if aNum < bNum {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			// Numeric segments order after alphabetic ones (1.0.1 > 1.0.beta)
+			return 1
+		case bErr == nil:
+			return -1
+		default:
+			if c := strings.Compare(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	default:
+		return 0
+	}
+}
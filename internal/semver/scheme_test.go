@@ -0,0 +1,150 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestSchemeSatisfies(t *testing.T) {
+	tests := []struct {
+		name       string
+		scheme     string
+		version    string
+		constraint string
+		satisfied  bool
+		expectErr  bool
+	}{
+		{"semver range", "semver", "1.23.0", ">=1.22 <1.25", true, false},
+		{"semver range upper bound", "semver", "1.25.0", ">=1.22 <1.25", false, false},
+		{"calver newer", "calver", "2024.10.1", ">=2024.6", true, false},
+		{"calver older", "calver", "2023.12.31", ">=2024.1", false, false},
+		{"calver rejects caret", "calver", "2024.10.1", "^2024.1", false, true},
+		{"calver rejects letters", "calver", "2024.10a", ">=2024.1", false, true},
+		{"loose letter suffix newer", "loose", "1.1.1k", ">=1.1.1g", true, false},
+		{"loose letter suffix after release", "loose", "1.1.1k", ">1.1.1", true, false},
+		{"loose older", "loose", "1.0.2u", ">=1.1.1", false, false},
+		{"default scheme", "", "1.2.3", "^1.2.0", true, false},
+		{"semver rejects underscores", "semver", "1.8.0_392", ">=1.8", false, true},
+		{"lenient java update", "lenient", "1.8.0_392", ">=1.8.0_381", true, false},
+		{"lenient java update older", "lenient", "1.8.0_372", ">=1.8.0_381", false, false},
+		{"lenient four segments", "lenient", "10.0.19041.1", ">=10.0.19041 <10.1", true, false},
+		{"lenient caret", "lenient", "1.8.0_392", "^1.8", true, false},
+		{"lenient pessimistic on the fourth segment", "lenient", "1.8.1.0", "~>1.8.0.392", false, false},
+		{"lenient rejects letters", "lenient", "1.1.1k", ">=1.1.1", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme, err := GetScheme(tt.scheme)
+			if err != nil {
+				t.Fatalf("Failed to get scheme '%s': %v", tt.scheme, err)
+			}
+
+			satisfied, err := scheme.Satisfies(tt.version, tt.constraint)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected error for %s %s, got nil", tt.version, tt.constraint)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if satisfied != tt.satisfied {
+				t.Errorf("Expected '%s' satisfied by '%s' to be %t, got %t",
+					tt.constraint, tt.version, tt.satisfied, satisfied)
+			}
+		})
+	}
+}
+
+func TestSatisfiesAllowingPrerelease(t *testing.T) {
+	tests := []struct {
+		name       string
+		scheme     string
+		version    string
+		constraint string
+		satisfied  bool
+	}{
+		{"release candidate of the minimum", "semver", "1.0.0-rc.1", ">=1.0.0", true},
+		{"release candidate of a newer version", "semver", "1.23.0-rc.2", ">=1.22 <1.24", true},
+		{"release candidate of an older version", "semver", "0.9.0-rc.1", ">=1.0.0", false},
+		{"below an upper bound", "semver", "2.0.0-rc.1", "<2.0.0", true},
+		{"caret upper bound", "semver", "2.0.0-rc.1", "^1.2.3", false},
+		{"caret lower bound", "semver", "1.2.3-beta.1", "^1.2.3", true},
+		{"exact version", "semver", "1.2.3-rc.1", "1.2.3", true},
+		{"release", "semver", "1.2.3", ">=1.2", true},
+		{"lenient", "lenient", "1.8.0_392-ea", ">=1.8.0_392", true},
+		{"loose compares as usual", "loose", "1.1.1k", ">=1.1.1g", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme, err := GetScheme(tt.scheme)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			satisfied, err := SatisfiesAllowingPrerelease(scheme, tt.version, tt.constraint)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if satisfied != tt.satisfied {
+				t.Errorf("Expected '%s' satisfied by '%s' to be %t, got %t",
+					tt.constraint, tt.version, tt.satisfied, satisfied)
+			}
+		})
+	}
+}
+
+func TestGetSchemeUnknown(t *testing.T) {
+	if _, err := GetScheme("romver"); err == nil {
+		t.Error("Expected error for unknown scheme")
+	}
+}
+
+func TestExplainMismatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		scheme     string
+		version    string
+		constraint string
+		expected   string
+	}{
+		{"lower bound", "semver", "1.21.3", ">=1.22", "1.21.3 fails '>=1.22.0'"},
+		{"upper bound of a range", "semver", "1.25.0", ">=1.22 <1.25", "1.25.0 fails '<1.25.0' of '>=1.22.0 <1.25.0'"},
+		{"normalized installed version", "semver", "1.21", "^1.22", "1.21.0 fails '^1.22.0'"},
+		{"exact version", "semver", "1.2.4", "1.2.3", "1.2.4 fails '1.2.3'"},
+		{"calver", "calver", "2023.12.31", ">=2024.1", "2023.12.31 fails '>=2024.1'"},
+		{"loose", "loose", "1.0.2u", ">=1.1.1 <3", "1.0.2u fails '>=1.1.1' of '>=1.1.1 <3'"},
+		{"satisfied", "semver", "1.23.0", ">=1.22", ""},
+		{"tilde keeps a written major-only range", "semver", "2.0.0", "~1", "2.0.0 fails '~1'"},
+		{"spaced pessimistic operator", "semver", "3.0.0", "~> 2.2", "3.0.0 fails '~>2.2'"},
+		{"lenient normalizes underscores", "lenient", "1.8.0_372", ">=1.8.0_381", "1.8.0.372 fails '>=1.8.0.381'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme, err := GetScheme(tt.scheme)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			mismatch, err := ExplainMismatch(scheme, tt.version, tt.constraint)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.expected == "" {
+				if mismatch != nil {
+					t.Errorf("Expected no mismatch, got %q", mismatch)
+				}
+				return
+			}
+			if mismatch == nil {
+				t.Fatalf("Expected mismatch %q, got none", tt.expected)
+			}
+			if mismatch.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, mismatch.String())
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ type Version struct {
 	Major      int
 	Minor      int
 	Patch      int
+	Extra      []int // Segments after Patch in lenient versions, e.g. 392 of 1.8.0_392
 	Prerelease string
 	Build      string
 }
@@ -29,20 +30,28 @@ const (
 	OpTilde
 	OpCaret
 	OpNotEqual
+	OpPessimistic // Ruby's ~>
 )
 
 // Constraint represents a version constraint
 type Constraint struct {
 	Operator Operator
 	Version  Version
+	// Precision is the number of version components written in the constraint (1-3, or more
+	// in lenient versions), which decides the range of ~, ^, and ~>; zero means three
+	Precision int
 }
 
 var (
 	// versionRegex matches semantic versions with optional v prefix
 	versionRegex = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z\-\.]+))?(?:\+([0-9A-Za-z\-\.]+))?$`)
 
+	// lenientVersionRegex matches versions with any number of numeric segments, separated by
+	// dots or underscores
+	lenientVersionRegex = regexp.MustCompile(`^v?(\d+(?:[._]\d+)*)(?:-([0-9A-Za-z\-\.]+))?(?:\+([0-9A-Za-z\-\.]+))?$`)
+
 	// constraintRegex matches version constraints
-	constraintRegex = regexp.MustCompile(`^(>=|<=|>|<|~|\^|!=)?(.+)$`)
+	constraintRegex = regexp.MustCompile(`^(>=|<=|~>|!=|=|>|<|~|\^)?(.+)$`)
 )
 
 // ParseVersion parses a version string into a Version struct
@@ -94,8 +103,72 @@ func ParseVersion(versionStr string) (Version, error) {
 	return version, nil
 }
 
+// ParseStrictVersion parses a version like ParseVersion but rejects 1-part and 2-part versions
+// instead of zero-padding them, so a truncated 1.2 is not mistaken for 1.2.0
+func ParseStrictVersion(versionStr string) (Version, error) {
+	matches := versionRegex.FindStringSubmatch(versionStr)
+	if matches != nil && (matches[2] == "" || matches[3] == "") {
+		return Version{}, fmt.Errorf("truncated version %s (strict semver requires MAJOR.MINOR.PATCH)", versionStr)
+	}
+
+	return ParseVersion(versionStr)
+}
+
+// ParseLenientVersion parses a version like ParseVersion but also accepts vendor-style
+// versions with four or more segments or underscore separators, such as Java's 1.8.0_392 or
+// Windows' 10.0.19041.1; segments after the patch version are kept in Extra
+func ParseLenientVersion(versionStr string) (Version, error) {
+	if versionStr == "" {
+		return Version{}, errors.New("version string cannot be empty")
+	}
+
+	matches := lenientVersionRegex.FindStringSubmatch(versionStr)
+	if matches == nil {
+		return Version{}, fmt.Errorf("invalid version format: %s", versionStr)
+	}
+
+	var segments []int
+	for _, field := range strings.FieldsFunc(matches[1], isSegmentSeparator) {
+		segment, err := strconv.Atoi(field)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version segment: %s", field)
+		}
+		segments = append(segments, segment)
+	}
+
+	version := versionOf(segments)
+	version.Prerelease = matches[2]
+	version.Build = matches[3]
+	return version, nil
+}
+
+// isSegmentSeparator returns true for the characters separating numeric version segments
+func isSegmentSeparator(r rune) bool {
+	return r == '.' || r == '_'
+}
+
+// versionOf builds a version from its numeric segments; missing ones are zero
+func versionOf(segments []int) Version {
+	padded := append(segments, make([]int, max(0, 3-len(segments)))...)
+	version := Version{Major: padded[0], Minor: padded[1], Patch: padded[2]}
+	if len(padded) > 3 {
+		version.Extra = padded[3:]
+	}
+	return version
+}
+
+// segments returns the numeric segments of the version, Extra included
+func (v Version) segments() []int {
+	return append([]int{v.Major, v.Minor, v.Patch}, v.Extra...)
+}
+
 // ParseConstraint parses a constraint string into a Constraint struct
 func ParseConstraint(constraintStr string) (Constraint, error) {
+	return parseConstraint(constraintStr, ParseVersion)
+}
+
+// parseConstraint parses a constraint string, parsing its version with parseVersion
+func parseConstraint(constraintStr string, parseVersion func(string) (Version, error)) (Constraint, error) {
 	if constraintStr == "" {
 		return Constraint{}, errors.New("constraint string cannot be empty")
 	}
@@ -123,6 +196,8 @@ func ParseConstraint(constraintStr string) (Constraint, error) {
 		operator = OpTilde
 	case "^":
 		operator = OpCaret
+	case "~>":
+		operator = OpPessimistic
 	case "!=":
 		operator = OpNotEqual
 	case "", "=":
@@ -132,14 +207,19 @@ func ParseConstraint(constraintStr string) (Constraint, error) {
 	}
 
 	// Parse version
-	version, err := ParseVersion(versionStr)
+	version, err := parseVersion(versionStr)
 	if err != nil {
 		return Constraint{}, fmt.Errorf("invalid version in constraint: %v", err)
 	}
 
+	// The segments written before any prerelease or build suffix
+	written, _, _ := strings.Cut(strings.TrimPrefix(versionStr, "v"), "-")
+	written, _, _ = strings.Cut(written, "+")
+
 	return Constraint{
-		Operator: operator,
-		Version:  version,
+		Operator:  operator,
+		Version:   version,
+		Precision: len(strings.FieldsFunc(written, isSegmentSeparator)),
 	}, nil
 }
 
@@ -170,11 +250,30 @@ func (v Version) Compare(other Version) int {
 		return 1
 	}
 
+	// Compare the segments of lenient versions; a missing segment is zero
+	for i := 0; i < len(v.Extra) || i < len(other.Extra); i++ {
+		a, b := segmentAt(v.Extra, i), segmentAt(other.Extra, i)
+		if a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	}
+
 	// Compare prerelease
 	return comparePrerelease(v.Prerelease, other.Prerelease)
 }
 
-// comparePrerelease compares prerelease versions
+// segmentAt returns the segment at index i, or zero past the end
+func segmentAt(segments []int, i int) int {
+	if i < len(segments) {
+		return segments[i]
+	}
+	return 0
+}
+
+// comparePrerelease compares prerelease versions following semver 2.0.0 §11
 func comparePrerelease(pre1, pre2 string) int {
 	// No prerelease is greater than any prerelease
 	if pre1 == "" && pre2 == "" {
@@ -187,14 +286,65 @@ func comparePrerelease(pre1, pre2 string) int {
 		return -1
 	}
 
-	// Compare prerelease strings lexicographically
-	if pre1 < pre2 {
+	// Compare dot-separated identifiers from left to right
+	ids1 := strings.Split(pre1, ".")
+	ids2 := strings.Split(pre2, ".")
+	for i := 0; i < len(ids1) && i < len(ids2); i++ {
+		if c := comparePrereleaseIdentifier(ids1[i], ids2[i]); c != 0 {
+			return c
+		}
+	}
+
+	// A larger set of identifiers has higher precedence when all preceding ones are equal
+	switch {
+	case len(ids1) < len(ids2):
 		return -1
+	case len(ids1) > len(ids2):
+		return 1
+	default:
+		return 0
 	}
-	if pre1 > pre2 {
+}
+
+// comparePrereleaseIdentifier compares a single prerelease identifier
+// Numeric identifiers compare numerically and have lower precedence than alphanumeric
+// ones, which compare lexically in ASCII order
+func comparePrereleaseIdentifier(id1, id2 string) int {
+	numeric1 := isNumericIdentifier(id1)
+	numeric2 := isNumericIdentifier(id2)
+
+	switch {
+	case numeric1 && numeric2:
+		// Compare by length first so identifiers of any size compare without overflow
+		n1 := strings.TrimLeft(id1, "0")
+		n2 := strings.TrimLeft(id2, "0")
+		if len(n1) != len(n2) {
+			if len(n1) < len(n2) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(n1, n2)
+	case numeric1:
+		return -1
+	case numeric2:
 		return 1
+	default:
+		return strings.Compare(id1, id2)
 	}
-	return 0
+}
+
+// isNumericIdentifier returns true if the identifier consists only of digits
+func isNumericIdentifier(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
 }
 
 // IsSatisfiedBy checks if a version satisfies this constraint
@@ -214,70 +364,78 @@ func (c Constraint) IsSatisfiedBy(version Version) bool {
 		return comparison <= 0
 	case OpNotEqual:
 		return comparison != 0
-	case OpTilde:
-		return c.tildeConstraint(version)
-	case OpCaret:
-		return c.caretConstraint(version)
+	case OpTilde, OpCaret, OpPessimistic:
+		// The upper bound is exclusive for its prereleases too: ^1.2.3 rejects 2.0.0-rc.1
+		lower, upper := c.Range()
+		upper.Prerelease = "0"
+		return version.Compare(lower) >= 0 && version.Compare(upper) < 0
 	default:
 		return false
 	}
 }
 
-// tildeConstraint implements tilde (~) constraint logic
-// ~1.2.3 := >=1.2.3 <1.3.0 (reasonably close to 1.2.3)
-// ~1.2 := >=1.2.0 <1.3.0
-// ~1 := >=1.0.0 <2.0.0
-func (c Constraint) tildeConstraint(version Version) bool {
-	if version.Major != c.Version.Major {
-		return false
-	}
-
-	if version.Minor != c.Version.Minor {
-		return false
-	}
-
-	// Allow any patch version >= constraint patch
-	return version.Patch >= c.Version.Patch
-}
-
-// caretConstraint implements caret (^) constraint logic
-// ^1.2.3 := >=1.2.3 <2.0.0 (compatible within same major version)
-// ^0.2.3 := >=0.2.3 <0.3.0 (for 0.x, compatible within same minor)
-// ^0.0.3 := >=0.0.3 <0.0.4 (for 0.0.x, compatible within same patch)
-func (c Constraint) caretConstraint(version Version) bool {
-	if version.Major != c.Version.Major {
-		return false
-	}
+// Range returns the inclusive lower and exclusive upper bound of a ~, ^, or ~> constraint,
+// following npm's node-semver for ~ and ^ and RubyGems for ~>:
+//
+//	~1.2.3 := >=1.2.3 <1.3.0    ^1.2.3 := >=1.2.3 <2.0.0    ~>1.2.3 := >=1.2.3 <1.3.0
+//	~1.2   := >=1.2.0 <1.3.0    ^0.2.3 := >=0.2.3 <0.3.0    ~>1.2   := >=1.2.0 <2.0.0
+//	~1     := >=1.0.0 <2.0.0    ^0.0.3 := >=0.0.3 <0.0.4    ~>1     := >=1.0.0 <2.0.0
+//	                            ^0.0   := >=0.0.0 <0.1.0
+//	                            ^0     := >=0.0.0 <1.0.0
+//
+// Other operators have no range; their version is returned as both bounds
+func (c Constraint) Range() (Version, Version) {
+	lower := c.Version
+	precision := c.Precision
+	if precision == 0 {
+		precision = 3
+	}
+
+	nextMajor := Version{Major: lower.Major + 1}
+	nextMinor := Version{Major: lower.Major, Minor: lower.Minor + 1}
+	nextPatch := Version{Major: lower.Major, Minor: lower.Minor, Patch: lower.Patch + 1}
 
-	// For major version 0, be more restrictive
-	if c.Version.Major == 0 {
-		if version.Minor != c.Version.Minor {
-			return false
+	switch c.Operator {
+	case OpTilde:
+		if precision == 1 {
+			return lower, nextMajor
 		}
-
-		// For 0.0.x, only allow same patch
-		if c.Version.Minor == 0 {
-			return version.Patch == c.Version.Patch
+		return lower, nextMinor
+	case OpCaret:
+		// Everything up to the first non-zero component that was written is fixed
+		switch {
+		case lower.Major != 0 || precision == 1:
+			return lower, nextMajor
+		case lower.Minor != 0 || precision == 2:
+			return lower, nextMinor
+		default:
+			return lower, nextPatch
 		}
-
-		// For 0.x.y, allow patch changes
-		return version.Patch >= c.Version.Patch
-	}
-
-	// For major version > 0, allow minor and patch changes
-	if version.Minor > c.Version.Minor {
-		return true
-	}
-	if version.Minor == c.Version.Minor {
-		return version.Patch >= c.Version.Patch
+	case OpPessimistic:
+		// The last component written may grow
+		if precision < 3 {
+			return lower, nextMajor
+		}
+		return lower, lower.bumped(precision - 2)
+	default:
+		return lower, lower
 	}
+}
 
-	return false
+// bumped returns the lowest version after every version sharing the first i+1 segments of
+// v, e.g. segment 1 of 1.2.3 gives 1.3.0
+func (v Version) bumped(i int) Version {
+	segments := v.segments()[:i+1]
+	segments[i]++
+	return versionOf(segments)
 }
 
 // String returns the string representation of the version
 func (v Version) String() string {
 	result := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	for _, segment := range v.Extra {
+		result += "." + strconv.Itoa(segment)
+	}
 
 	if v.Prerelease != "" {
 		result += "-" + v.Prerelease
@@ -309,19 +467,48 @@ func (op Operator) String() string {
 		return "^"
 	case OpNotEqual:
 		return "!="
+	case OpPessimistic:
+		return "~>"
 	default:
 		return "unknown"
 	}
 }
 
 // String returns the string representation of the constraint
+// Versions are padded to three components unless that changes a range: ^1.2 is ^1.2.0,
+// but ~1 stays ~1 since ~1.0.0 allows only patch updates
 func (c Constraint) String() string {
-	if c.Operator == OpEqual {
+	switch c.Operator {
+	case OpEqual:
 		return c.Version.String()
+	case OpTilde, OpCaret, OpPessimistic:
+		padded := Constraint{Operator: c.Operator, Version: c.Version, Precision: 3}
+		_, upper := c.Range()
+		if _, paddedUpper := padded.Range(); upper.Compare(paddedUpper) != 0 {
+			return c.Operator.String() + c.Version.written(c.Precision)
+		}
 	}
 	return c.Operator.String() + c.Version.String()
 }
 
+// written returns the version with only its first precision components
+func (v Version) written(precision int) string {
+	components := v.segments()[:precision]
+	parts := make([]string, len(components))
+	for i, component := range components {
+		parts[i] = strconv.Itoa(component)
+	}
+
+	result := strings.Join(parts, ".")
+	if v.Prerelease != "" {
+		result += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		result += "+" + v.Build
+	}
+	return result
+}
+
 // SatisfiesAll checks if a version satisfies all constraints in a list
 func SatisfiesAll(version Version, constraints []Constraint) bool {
 	for _, constraint := range constraints {
@@ -332,17 +519,81 @@ func SatisfiesAll(version Version, constraints []Constraint) bool {
 	return true
 }
 
+// Satisfiable returns false if no release can meet every constraint, as with <1.0.0 >=2.0.0
+// Only releases at or just past a bound of some constraint are tried, which is enough to find
+// one meeting them all when there is one. Releases have three segments unless lenient is set,
+// in which case they may have more, as versions of the lenient scheme do
+func Satisfiable(constraints []Constraint, lenient bool) bool {
+	candidates := []Version{{}}
+	for _, c := range constraints {
+		lower, upper := c.Range()
+		for _, bound := range []Version{lower, upper} {
+			release := Version{Major: bound.Major, Minor: bound.Minor, Patch: bound.Patch}
+			if lenient {
+				release.Extra = bound.Extra
+			}
+			segments := release.segments()
+			candidates = append(candidates, release, release.bumped(len(segments)-1))
+			if lenient {
+				candidates = append(candidates, versionOf(append(segments, 1)))
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		if SatisfiesAll(candidate, constraints) {
+			return true
+		}
+	}
+	return false
+}
+
+// Conflicting returns the fewest constraints that no release meets together: a constraint
+// nothing meets on its own, a pair such as >=2.0.0 <1.5.0, or else all of them. It returns nil
+// if some release meets every constraint; lenient is as for Satisfiable
+func Conflicting(constraints []Constraint, lenient bool) []Constraint {
+	if Satisfiable(constraints, lenient) {
+		return nil
+	}
+
+	for _, c := range constraints {
+		if !Satisfiable([]Constraint{c}, lenient) {
+			return []Constraint{c}
+		}
+	}
+	for i, a := range constraints {
+		for _, b := range constraints[i+1:] {
+			if !Satisfiable([]Constraint{a, b}, lenient) {
+				return []Constraint{a, b}
+			}
+		}
+	}
+	return constraints
+}
+
 // ParseConstraints parses multiple constraints from a space-separated string
 func ParseConstraints(constraintStr string) ([]Constraint, error) {
+	return parseConstraints(constraintStr, ParseVersion)
+}
+
+// ParseLenientConstraints parses constraints like ParseConstraints, accepting the versions
+// ParseLenientVersion accepts
+func ParseLenientConstraints(constraintStr string) ([]Constraint, error) {
+	return parseConstraints(constraintStr, ParseLenientVersion)
+}
+
+// parseConstraints parses a space-separated constraint string, parsing versions with
+// parseVersion
+func parseConstraints(constraintStr string, parseVersion func(string) (Version, error)) ([]Constraint, error) {
 	if constraintStr == "" {
 		return nil, errors.New("constraint string cannot be empty")
 	}
 
-	parts := strings.Fields(constraintStr)
+	parts := constraintFields(constraintStr)
 	constraints := make([]Constraint, len(parts))
 
 	for i, part := range parts {
-		constraint, err := ParseConstraint(part)
+		constraint, err := parseConstraint(part, parseVersion)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse constraint '%s': %v", part, err)
 		}
@@ -350,4 +601,28 @@ func ParseConstraints(constraintStr string) ([]Constraint, error) {
 	}
 
 	return constraints, nil
-}
\ No newline at end of file
+}
+
+// constraintFields splits a constraint string at whitespace, joining an operator written on
+// its own to the version after it, so Ruby-style "~> 2.2" and ">= 1.20" read as one clause
+func constraintFields(constraintStr string) []string {
+	var fields []string
+	for _, field := range strings.Fields(constraintStr) {
+		if n := len(fields); n > 0 && isOperator(fields[n-1]) {
+			fields[n-1] += field
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// isOperator returns true if s is a constraint operator without a version
+func isOperator(s string) bool {
+	switch s {
+	case ">=", "<=", ">", "<", "~", "^", "~>", "!=", "=":
+		return true
+	default:
+		return false
+	}
+}
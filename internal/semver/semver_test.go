@@ -1,6 +1,7 @@
 package semver
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -100,6 +101,86 @@ func TestVersionParsing(t *testing.T) {
 	}
 }
 
+func TestParseStrictVersion(t *testing.T) {
+	tests := []struct {
+		version     string
+		expectError bool
+	}{
+		{"1.2.3", false},
+		{"v1.2.3-rc.1+build.5", false},
+		{"1.2", true},
+		{"1", true},
+		{"v1.2-beta", true},
+		{"not-a-version", true},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseStrictVersion(tt.version)
+		if tt.expectError && err == nil {
+			t.Errorf("ParseStrictVersion(%q): expected error but got none", tt.version)
+		}
+		if !tt.expectError && err != nil {
+			t.Errorf("ParseStrictVersion(%q): unexpected error: %v", tt.version, err)
+		}
+	}
+}
+
+func TestParseLenientVersion(t *testing.T) {
+	tests := []struct {
+		version     string
+		want        string
+		expectError bool
+	}{
+		{version: "1.2.3", want: "1.2.3"},
+		{version: "1.8.0_392", want: "1.8.0.392"},
+		{version: "10.0.19041.1", want: "10.0.19041.1"},
+		{version: "v2.1.0.7-beta+build.5", want: "2.1.0.7-beta+build.5"},
+		{version: "17", want: "17.0.0"},
+		{version: "1.8.0__392", expectError: true},
+		{version: "1.1.1k", expectError: true},
+		{version: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		version, err := ParseLenientVersion(tt.version)
+		if tt.expectError {
+			if err == nil {
+				t.Errorf("ParseLenientVersion(%q): expected error but got none", tt.version)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseLenientVersion(%q): unexpected error: %v", tt.version, err)
+			continue
+		}
+		if got := version.String(); got != tt.want {
+			t.Errorf("ParseLenientVersion(%q) = %s, want %s", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestLenientVersionComparison(t *testing.T) {
+	tests := []struct {
+		version1 string
+		version2 string
+		expected int
+	}{
+		{"1.8.0_392", "1.8.0_381", 1},
+		{"1.8.0_392", "1.8.0", 1},
+		{"1.8.0.0", "1.8.0", 0},
+		{"10.0.19041.1", "10.0.22621.1", -1},
+		{"1.8.0_392", "1.8.1", -1},
+	}
+
+	for _, tt := range tests {
+		v1, _ := ParseLenientVersion(tt.version1)
+		v2, _ := ParseLenientVersion(tt.version2)
+		if got := v1.Compare(v2); got != tt.expected {
+			t.Errorf("Compare(%s, %s) = %d, want %d", tt.version1, tt.version2, got, tt.expected)
+		}
+	}
+}
+
 func TestVersionComparison(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -128,6 +209,7 @@ func TestVersionComparison(t *testing.T) {
 		{"prerelease vs release", "1.2.3-alpha", "1.2.3", -1},
 		{"prerelease comparison", "1.2.3-alpha.1", "1.2.3-alpha.2", -1},
 		{"prerelease comparison 2", "1.2.3-beta", "1.2.3-alpha", 1},
+		{"numeric prerelease identifiers", "1.2.3-alpha.10", "1.2.3-alpha.9", 1},
 
 		// Different formats
 		{"two vs three parts", "1.22", "1.22.0", 0},
@@ -152,6 +234,68 @@ func TestVersionComparison(t *testing.T) {
 	}
 }
 
+func TestComparePrerelease(t *testing.T) {
+	tests := []struct {
+		pre1     string
+		pre2     string
+		expected int
+	}{
+		// Release versions
+		{"", "", 0},
+		{"", "alpha", 1},
+		{"alpha", "", -1},
+
+		// Numeric identifiers compare numerically
+		{"alpha.9", "alpha.10", -1},
+		{"alpha.10", "alpha.9", 1},
+		{"rc.2", "rc.2", 0},
+		{"1", "2", -1},
+		{"10", "9", 1},
+		{"99999999999999999999", "100000000000000000000", -1},
+
+		// Numeric identifiers have lower precedence than alphanumeric ones
+		{"1", "alpha", -1},
+		{"alpha.1", "alpha.beta", -1},
+		{"beta", "11", 1},
+
+		// Alphanumeric identifiers compare lexically in ASCII order
+		{"alpha", "beta", -1},
+		{"beta", "alpha", 1},
+		{"RC", "rc", -1},
+		{"alpha-2", "alpha-10", 1},
+		{"beta2", "beta10", 1},
+
+		// A larger set of identifiers has higher precedence
+		{"alpha.1", "alpha", 1},
+		{"alpha.1", "alpha.1.1", -1},
+
+		// Precedence example from semver 2.0.0 §11
+		{"alpha", "alpha.1", -1},
+		{"alpha.1", "alpha.beta", -1},
+		{"alpha.beta", "beta", -1},
+		{"beta", "beta.2", -1},
+		{"beta.2", "beta.11", -1},
+		{"beta.11", "rc.1", -1},
+	}
+
+	for _, tt := range tests {
+		if got := comparePrerelease(tt.pre1, tt.pre2); got != tt.expected {
+			t.Errorf("comparePrerelease(%q, %q) = %d, want %d", tt.pre1, tt.pre2, got, tt.expected)
+		}
+	}
+
+	// The full precedence chain, including the release that follows its prereleases
+	chain := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0",
+	}
+	for i := 1; i < len(chain); i++ {
+		if mustParseVersion(chain[i-1]).Compare(mustParseVersion(chain[i])) != -1 {
+			t.Errorf("Expected %s < %s", chain[i-1], chain[i])
+		}
+	}
+}
+
 func TestConstraintParsing(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -165,6 +309,12 @@ func TestConstraintParsing(t *testing.T) {
 			expectError: false,
 			expected:    Constraint{Operator: OpEqual, Version: mustParseVersion("1.2.3")},
 		},
+		{
+			name:        "explicit exact constraint",
+			constraint:  "=1.2.3",
+			expectError: false,
+			expected:    Constraint{Operator: OpEqual, Version: mustParseVersion("1.2.3")},
+		},
 		{
 			name:        "greater than or equal",
 			constraint:  ">=1.22",
@@ -381,11 +531,12 @@ func TestVersionString(t *testing.T) {
 		version  Version
 		expected string
 	}{
-		{Version{1, 2, 3, "", ""}, "1.2.3"},
-		{Version{1, 2, 3, "alpha", ""}, "1.2.3-alpha"},
-		{Version{1, 2, 3, "", "build.1"}, "1.2.3+build.1"},
-		{Version{1, 2, 3, "beta.2", "build.456"}, "1.2.3-beta.2+build.456"},
-		{Version{24, 0, 0, "", ""}, "24.0.0"},
+		{Version{Major: 1, Minor: 2, Patch: 3}, "1.2.3"},
+		{Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "alpha"}, "1.2.3-alpha"},
+		{Version{Major: 1, Minor: 2, Patch: 3, Build: "build.1"}, "1.2.3+build.1"},
+		{Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "beta.2", Build: "build.456"}, "1.2.3-beta.2+build.456"},
+		{Version{Major: 24}, "24.0.0"},
+		{Version{Major: 1, Minor: 8, Patch: 0, Extra: []int{392}}, "1.8.0.392"},
 	}
 
 	for _, tt := range tests {
@@ -438,4 +589,303 @@ func mustParseVersion(s string) Version {
 		panic(err)
 	}
 	return v
-}
\ No newline at end of file
+}
+func TestExplainConstraints(t *testing.T) {
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{">=1.22", "1.22.0 or newer"},
+		{"1.2.3", "exactly 1.2.3"},
+		{"~1.2.3", "1.2.3 or newer, but older than 1.3.0 (patch updates only)"},
+		{"^1.2.3", "1.2.3 or newer, but older than 2.0.0 (minor and patch updates)"},
+		{"^0.2.3", "0.2.3 or newer, but older than 0.3.0 (patch updates only for 0.x)"},
+		{">=1.22 <1.25", "1.22.0 or newer, and any version older than 1.25.0"},
+		{"~1", "1.0.0 or newer, but older than 2.0.0 (minor and patch updates)"},
+		{"^0.0.3", "exactly 0.0.3 (0.0.x releases are treated as incompatible)"},
+		{"^0.0", "0.0.0 or newer, but older than 0.1.0 (patch updates only for 0.x)"},
+		{"~> 2.2", "2.2.0 or newer, but older than 3.0.0 (minor and patch updates)"},
+		{"~>2.2.0", "2.2.0 or newer, but older than 2.3.0 (patch updates only)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			explanation, err := ExplainConstraints(tt.constraint)
+			if err != nil {
+				t.Fatalf("Failed to explain constraint '%s': %v", tt.constraint, err)
+			}
+			if explanation != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, explanation)
+			}
+		})
+	}
+}
+
+func TestMinimumVersion(t *testing.T) {
+	tests := []struct {
+		constraint string
+		expected   string
+		ok         bool
+	}{
+		{">=1.20", "1.20", true},
+		{"v1.2.3", "1.2.3", true},
+		{"^18.0", "18.0", true},
+		{"~> 2.2", "2.2", true},
+		{">=1.20 >=1.22.1 <2.0", "1.22.1", true},
+		{"<2.0", "", false},
+		{">1.0", "", false},
+		{"not a constraint", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			minimum, ok := MinimumVersion(tt.constraint)
+			if minimum != tt.expected || ok != tt.ok {
+				t.Errorf("MinimumVersion(%q) = (%q, %v), want (%q, %v)", tt.constraint, minimum, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRangeConstraints(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		satisfied  bool
+	}{
+		// Tilde: patch updates when a minor version is given, minor updates otherwise
+		{"~1.2.3", "1.2.3", true},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.2.2", false},
+		{"~1.2.3", "1.3.0", false},
+		{"~1.2.3", "1.3.0-alpha", false},
+		{"~1.2", "1.2.0", true},
+		{"~1.2", "1.2.99", true},
+		{"~1.2", "1.3.0", false},
+		{"~1", "1.0.0", true},
+		{"~1", "1.9.9", true},
+		{"~1", "2.0.0", false},
+		{"~1", "0.9.9", false},
+		{"~0.2.3", "0.2.5", true},
+		{"~0.2.3", "0.3.0", false},
+		{"~0.2", "0.2.0", true},
+		{"~0.2", "0.3.0", false},
+		{"~0", "0.9.0", true},
+		{"~0", "1.0.0", false},
+		{"~1.2.3-beta.2", "1.2.3-beta.4", true},
+		{"~1.2.3-beta.2", "1.2.3-beta.1", false},
+		{"~1.2.3-beta.2", "1.2.3", true},
+		{"~1.2.3-beta.2", "1.2.4", true},
+
+		// Caret: updates that do not change the first non-zero component written
+		{"^1.2.3", "1.2.3", true},
+		{"^1.2.3", "1.9.0", true},
+		{"^1.2.3", "1.2.2", false},
+		{"^1.2.3", "1.2.3-beta", false},
+		{"^1.2.3", "2.0.0", false},
+		{"^1.2.3", "2.0.0-rc.1", false},
+		{"^1.2", "1.2.0", true},
+		{"^1.2", "1.99.0", true},
+		{"^1.2", "2.0.0", false},
+		{"^1", "1.0.0", true},
+		{"^1", "2.0.0", false},
+		{"^0.2.3", "0.2.3", true},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.2", "0.2.9", true},
+		{"^0.2", "0.3.0", false},
+		{"^0.0.3", "0.0.3", true},
+		{"^0.0.3", "0.0.4", false},
+		{"^0.0.3", "0.0.2", false},
+		{"^0.0", "0.0.9", true},
+		{"^0.0", "0.1.0", false},
+		{"^0", "0.9.9", true},
+		{"^0", "1.0.0", false},
+		{"^0.0.0", "0.0.0", true},
+		{"^0.0.0", "0.0.1", false},
+		{"^1.2.3-beta.2", "1.2.3-beta.3", true},
+		{"^1.2.3-beta.2", "1.9.0", true},
+		{"^1.2.3-beta.2", "1.2.3-alpha", false},
+
+		// Pessimistic: the last component written may grow
+		{"~>2.2", "2.2.0", true},
+		{"~>2.2", "2.9.1", true},
+		{"~>2.2", "3.0.0", false},
+		{"~>2.2", "2.1.9", false},
+		{"~>2.2.0", "2.2.9", true},
+		{"~>2.2.0", "2.3.0", false},
+		{"~>2", "2.9.9", true},
+		{"~>2", "3.0.0", false},
+		{"~>0.4", "0.9.0", true},
+		{"~>0.4", "1.0.0", false},
+		{"~> 2.2", "2.5.0", true},
+		{"~> 2.2", "3.0.0", false},
+
+		// Exact: = is optional and may stand apart from its version
+		{"=1.2.3", "1.2.3", true},
+		{"=1.2.3", "1.2.4", false},
+		{"= 1.2.3", "1.2.3", true},
+		{"= 1.2.3", "1.2.2", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+" "+tt.version, func(t *testing.T) {
+			constraints, err := ParseConstraints(tt.constraint)
+			if err != nil {
+				t.Fatalf("Failed to parse constraint '%s': %v", tt.constraint, err)
+			}
+			version, err := ParseVersion(tt.version)
+			if err != nil {
+				t.Fatalf("Failed to parse version '%s': %v", tt.version, err)
+			}
+
+			if satisfied := SatisfiesAll(version, constraints); satisfied != tt.satisfied {
+				t.Errorf("Expected '%s' satisfied by '%s' to be %t, got %t", tt.constraint, tt.version, tt.satisfied, satisfied)
+			}
+		})
+	}
+}
+
+func TestConstraintRange(t *testing.T) {
+	tests := []struct {
+		constraint string
+		lower      string
+		upper      string
+	}{
+		{"~1.2.3", "1.2.3", "1.3.0"},
+		{"~1.2", "1.2.0", "1.3.0"},
+		{"~1", "1.0.0", "2.0.0"},
+		{"^1.2.3", "1.2.3", "2.0.0"},
+		{"^0.2.3", "0.2.3", "0.3.0"},
+		{"^0.0.3", "0.0.3", "0.0.4"},
+		{"^0.0", "0.0.0", "0.1.0"},
+		{"^0", "0.0.0", "1.0.0"},
+		{"~>1.2.3", "1.2.3", "1.3.0"},
+		{"~>1.2", "1.2.0", "2.0.0"},
+		{"~>1", "1.0.0", "2.0.0"},
+		{">=1.2.3", "1.2.3", "1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			constraint, err := ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("Failed to parse constraint '%s': %v", tt.constraint, err)
+			}
+
+			lower, upper := constraint.Range()
+			if lower.String() != tt.lower || upper.String() != tt.upper {
+				t.Errorf("Range() = (%s, %s), want (%s, %s)", lower, upper, tt.lower, tt.upper)
+			}
+		})
+	}
+}
+
+func TestSatisfiable(t *testing.T) {
+	tests := []struct {
+		constraints string
+		lenient     bool
+		expected    bool
+	}{
+		{">=1.20", false, true},
+		{">=1.20 <2.0", false, true},
+		{"<1.0 >=2.0", false, false},
+		{"^1.2.3 ~1.4", false, true},
+		{"^1.2.3 ^2.0.0", false, false},
+		{">1.2.3 <=1.2.3", false, false},
+		{"!=1.2.3 1.2.3", false, false},
+		{"1.2.3 >=1.0", false, true},
+		{">1.0.0 <1.0.1", false, false},
+		{">=2.0.0-rc.1 <2.0.0", false, false},
+		{">1.2.3 <1.2.4", true, true},
+		{">=1.8.0_392 <1.8.0_400", true, true},
+		{">=17 <11", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraints, func(t *testing.T) {
+			parse := ParseConstraints
+			if tt.lenient {
+				parse = ParseLenientConstraints
+			}
+			constraints, err := parse(tt.constraints)
+			if err != nil {
+				t.Fatalf("Failed to parse constraints '%s': %v", tt.constraints, err)
+			}
+
+			if got := Satisfiable(constraints, tt.lenient); got != tt.expected {
+				t.Errorf("Satisfiable(%s) = %v, want %v", tt.constraints, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConflicting(t *testing.T) {
+	tests := []struct {
+		constraints string
+		expected    string
+	}{
+		{">=1.20 <2.0", ""},
+		{">=2.0 <1.5", ">=2.0.0 <1.5.0"},
+		{"^1.2.3 >=1.0 <1.2", "^1.2.3 <1.2.0"},
+		{">=1.0 <0", "<0.0.0"},
+		{">=1.0 <=1.0 !=1.0", ">=1.0.0 <=1.0.0 !=1.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraints, func(t *testing.T) {
+			constraints, err := ParseConstraints(tt.constraints)
+			if err != nil {
+				t.Fatalf("Failed to parse constraints '%s': %v", tt.constraints, err)
+			}
+
+			var got []string
+			for _, c := range Conflicting(constraints, false) {
+				got = append(got, c.String())
+			}
+			if strings.Join(got, " ") != tt.expected {
+				t.Errorf("Conflicting(%s) = %v, want %q", tt.constraints, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConstraintStringKeepsRanges(t *testing.T) {
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"^1.22", "^1.22.0"},
+		{"~1.2", "~1.2.0"},
+		{"~1", "~1"},
+		{"^0.0", "^0.0"},
+		{"^0", "^0"},
+		{"^1", "^1.0.0"},
+		{"~>2.2", "~>2.2"},
+		{"~>2.2.0", "~>2.2.0"},
+		{"~>2", "~>2"},
+		{">=1.22", ">=1.22.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			constraint, err := ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("Failed to parse constraint '%s': %v", tt.constraint, err)
+			}
+			if got := constraint.String(); got != tt.expected {
+				t.Errorf("String() = %q, want %q", got, tt.expected)
+			}
+
+			// The printed form must mean the same as the original
+			reparsed, err := ParseConstraint(constraint.String())
+			if err != nil {
+				t.Fatalf("Failed to reparse %q: %v", constraint.String(), err)
+			}
+			_, upper := constraint.Range()
+			if _, reparsedUpper := reparsed.Range(); upper.Compare(reparsedUpper) != 0 {
+				t.Errorf("%q and %q have different ranges", tt.constraint, constraint.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package server
+
+import (
+	"cmp"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+// Headers an uploader sends to place its report in the fleet
+const (
+	// TeamHeader names the team of the uploading machine
+	TeamHeader = "X-Goctor-Team"
+	// HostHeader overrides the hostname of the report's platform
+	HostHeader = "X-Goctor-Host"
+)
+
+// maxReportSize is the largest report body the fleet server accepts
+const maxReportSize = 10 << 20
+
+// HostReport is the latest report a host uploaded
+type HostReport struct {
+	Host       string                    `json:"host"`
+	Team       string                    `json:"team,omitempty"`
+	ReceivedAt time.Time                 `json:"received_at"`
+	Report     checker.EnvironmentReport `json:"report"`
+}
+
+// HostStatus is the compliance of one host
+type HostStatus struct {
+	Host           string               `json:"host"`
+	Team           string               `json:"team,omitempty"`
+	ReceivedAt     time.Time            `json:"received_at"`
+	GeneratedAt    time.Time            `json:"generated_at"`
+	ManifestSource string               `json:"manifest_source"`
+	Compliant      bool                 `json:"compliant"`
+	Summary        checker.CheckSummary `json:"summary"`
+}
+
+// TeamStatus is the compliance of the hosts of one team; hosts without a team share the
+// empty team
+type TeamStatus struct {
+	Team      string  `json:"team"`
+	Hosts     int     `json:"hosts"`
+	Compliant int     `json:"compliant"`
+	Percent   float64 `json:"percent"`
+}
+
+// Fleet collects the reports of many machines and serves their compliance per team and host
+// The latest report of each host is kept in memory and, with a data directory, in one JSON
+// file per host so the fleet survives restarts
+type Fleet struct {
+	dir   string
+	token string
+	now   func() time.Time
+
+	mu    sync.RWMutex
+	hosts map[string]HostReport
+}
+
+// NewFleet creates a fleet server storing reports in dir, loading those stored earlier; an
+// empty dir keeps reports in memory only
+func NewFleet(dir string) (*Fleet, error) {
+	f := &Fleet{dir: dir, now: time.Now, hosts: map[string]HostReport{}}
+	if dir == "" {
+		return f, nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var entry HostReport
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("reading %s: %v", file, err)
+		}
+		f.hosts[entry.Host] = entry
+	}
+	return f, nil
+}
+
+// SetToken requires uploads to authenticate with an Authorization: Bearer header
+func (f *Fleet) SetToken(token string) {
+	f.token = token
+}
+
+// Store records the latest report of a host
+func (f *Fleet) Store(host, team string, report checker.EnvironmentReport) (HostReport, error) {
+	entry := HostReport{Host: host, Team: team, ReceivedAt: f.now().UTC(), Report: report}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.dir != "" {
+		data, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return HostReport{}, err
+		}
+		// Write and rename so a crash never leaves half a report behind
+		path := filepath.Join(f.dir, url.PathEscape(host)+".json")
+		if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+			return HostReport{}, err
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return HostReport{}, err
+		}
+	}
+
+	f.hosts[host] = entry
+	return entry, nil
+}
+
+// Hosts returns the compliance of every host, or of one team's hosts if team is not nil,
+// sorted by team and host
+func (f *Fleet) Hosts(team *string) []HostStatus {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	statuses := []HostStatus{}
+	for _, entry := range f.hosts {
+		if team != nil && entry.Team != *team {
+			continue
+		}
+		statuses = append(statuses, HostStatus{
+			Host:           entry.Host,
+			Team:           entry.Team,
+			ReceivedAt:     entry.ReceivedAt,
+			GeneratedAt:    entry.Report.GeneratedAt,
+			ManifestSource: entry.Report.ManifestSource,
+			Compliant:      entry.Report.IsSuccessful(),
+			Summary:        entry.Report.Summary,
+		})
+	}
+	slices.SortFunc(statuses, func(a, b HostStatus) int {
+		return cmp.Or(cmp.Compare(a.Team, b.Team), cmp.Compare(a.Host, b.Host))
+	})
+	return statuses
+}
+
+// Teams returns the compliance of every team, sorted by name
+func (f *Fleet) Teams() []TeamStatus {
+	teams := []TeamStatus{}
+	for _, host := range f.Hosts(nil) {
+		if len(teams) == 0 || teams[len(teams)-1].Team != host.Team {
+			teams = append(teams, TeamStatus{Team: host.Team})
+		}
+		team := &teams[len(teams)-1]
+		team.Hosts++
+		if host.Compliant {
+			team.Compliant++
+		}
+		team.Percent = float64(team.Compliant) * 100 / float64(team.Hosts)
+	}
+	return teams
+}
+
+// Report returns the latest report of a host
+func (f *Fleet) Report(host string) (HostReport, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	entry, ok := f.hosts[host]
+	return entry, ok
+}
+
+// Handler returns the HTTP handler for the fleet's endpoints
+func (f *Fleet) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/{$}", f.handleDashboard)
+	mux.HandleFunc("/healthz", f.handleHealthz)
+	mux.HandleFunc("/reports", f.handleUpload)
+	mux.HandleFunc("/api/teams", f.handleTeams)
+	mux.HandleFunc("/api/hosts", f.handleHosts)
+	mux.HandleFunc("/api/hosts/{host}", f.handleHost)
+	return mux
+}
+
+// handleHealthz reports that the fleet server is up and how many hosts it knows
+func (f *Fleet) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	f.mu.RLock()
+	hosts := len(f.hosts)
+	f.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ok", "hosts": hosts})
+}
+
+// handleUpload stores a report POSTed by doctor --report-url or goctor agent
+func (f *Fleet) handleUpload(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	if f.token != "" {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(f.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+	}
+
+	host, report, err := decodeUpload(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entry, err := f.Store(host, strings.TrimSpace(r.Header.Get(TeamHeader)), report)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]any{"host": entry.Host, "team": entry.Team, "received_at": entry.ReceivedAt})
+}
+
+// decodeUpload reads an uploaded report and the host it is for: the host header, or else the
+// hostname of the report's platform
+func decodeUpload(w http.ResponseWriter, r *http.Request) (string, checker.EnvironmentReport, error) {
+	var report checker.EnvironmentReport
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReportSize)).Decode(&report); err != nil {
+		return "", checker.EnvironmentReport{}, fmt.Errorf("invalid report: %v", err)
+	}
+
+	if report.SchemaVersion != checker.ReportSchemaVersion {
+		return "", checker.EnvironmentReport{}, fmt.Errorf("unsupported report schema version: %d", report.SchemaVersion)
+	}
+	if report.Items == nil {
+		return "", checker.EnvironmentReport{}, errors.New("invalid report: items cannot be nil")
+	}
+
+	var hostname string
+	if platform, ok := report.Platform.(map[string]any); ok {
+		hostname, _ = platform["hostname"].(string)
+	}
+	host := cmp.Or(strings.TrimSpace(r.Header.Get(HostHeader)), hostname)
+	if host == "" {
+		return "", checker.EnvironmentReport{}, fmt.Errorf("the report names no host; send the %s header", HostHeader)
+	}
+	return host, report, nil
+}
+
+// handleTeams returns the compliance of every team
+func (f *Fleet) handleTeams(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, f.Teams())
+}
+
+// handleHosts returns the compliance of every host, or of one team's with ?team=NAME
+func (f *Fleet) handleHosts(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	var team *string
+	if r.URL.Query().Has("team") {
+		name := r.URL.Query().Get("team")
+		team = &name
+	}
+	writeJSON(w, http.StatusOK, f.Hosts(team))
+}
+
+// handleHost returns the latest report of one host
+func (f *Fleet) handleHost(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	entry, ok := f.Report(r.PathValue("host"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "no report from host "+r.PathValue("host"))
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// handleDashboard shows the compliance of teams and hosts as an HTML page
+func (f *Fleet) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	view := struct {
+		Teams       []TeamStatus
+		Hosts       []HostStatus
+		GeneratedAt time.Time
+	}{Teams: f.Teams(), Hosts: f.Hosts(nil), GeneratedAt: f.now().UTC()}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := fleetDashboardTemplate.Execute(w, view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var fleetDashboardTemplate = template.Must(template.New("fleet").Funcs(template.FuncMap{
+	"team": func(name string) string { return cmp.Or(name, "(no team)") },
+	"time": func(t time.Time) string { return t.Format("2006-01-02 15:04 UTC") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>goctor fleet</title>
+<style>
+  body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #1f2328; }
+  table { border-collapse: collapse; margin-bottom: 2rem; }
+  th, td { text-align: left; padding: 0.3rem 0.8rem; border-bottom: 1px solid #d0d7de; }
+  .ok { color: #1a7f37; }
+  .fail { color: #cf222e; }
+  .meta { color: #656d76; }
+</style>
+</head>
+<body>
+<h1>goctor fleet</h1>
+<p class="meta">{{len .Hosts}} hosts, as of {{time .GeneratedAt}}</p>
+
+<h2>Teams</h2>
+<table>
+  <tr><th>Team</th><th>Hosts</th><th>Compliant</th><th>%</th></tr>
+  {{range .Teams}}<tr><td>{{team .Team}}</td><td>{{.Hosts}}</td><td>{{.Compliant}}</td><td>{{printf "%.0f" .Percent}}%</td></tr>
+  {{end}}
+</table>
+
+<h2>Hosts</h2>
+<table>
+  <tr><th>Host</th><th>Team</th><th>Status</th><th>OK</th><th>Missing</th><th>Outdated</th><th>Errors</th><th>Reported</th></tr>
+  {{range .Hosts}}<tr>
+    <td><a href="/api/hosts/{{.Host}}">{{.Host}}</a></td>
+    <td>{{team .Team}}</td>
+    <td>{{if .Compliant}}<span class="ok">compliant</span>{{else}}<span class="fail">needs attention</span>{{end}}</td>
+    <td>{{.Summary.OK}}</td><td>{{.Summary.Missing}}</td><td>{{.Summary.Outdated}}</td><td>{{.Summary.Errors}}</td>
+    <td>{{time .ReceivedAt}}</td>
+  </tr>
+  {{end}}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/platform"
+)
+
+func uploadBody(t *testing.T, hostname string, status checker.CheckStatus) string {
+	t.Helper()
+	report := checker.NewEnvironmentReport(platform.PlatformInfo{OS: "linux", Architecture: "amd64", Hostname: hostname}, "tools.yaml", []checker.CheckResult{
+		{ToolID: "go", ToolName: "Go", Status: status},
+	})
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestFleetUploads(t *testing.T) {
+	dir := t.TempDir()
+	fleet, err := NewFleet(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fleet.SetToken("s3cret")
+	handler := fleet.Handler()
+
+	upload := func(body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		headers  map[string]string
+		wantCode int
+	}{
+		{name: "host from the report", body: uploadBody(t, "dev-01", checker.StatusOK), headers: map[string]string{TeamHeader: "backend"}, wantCode: http.StatusCreated},
+		{name: "failing host", body: uploadBody(t, "dev-02", checker.StatusOutdated), headers: map[string]string{TeamHeader: "backend"}, wantCode: http.StatusCreated},
+		{name: "host header", body: uploadBody(t, "", checker.StatusOK), headers: map[string]string{HostHeader: "ci-runner"}, wantCode: http.StatusCreated},
+		{name: "no host", body: uploadBody(t, "", checker.StatusOK), wantCode: http.StatusBadRequest},
+		{name: "not a report", body: `{"schema_version": 99, "items": []}`, wantCode: http.StatusBadRequest},
+		{name: "wrong token", body: uploadBody(t, "dev-03", checker.StatusOK), headers: map[string]string{"Authorization": "Bearer guess"}, wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := upload(tt.body, tt.headers); rec.Code != tt.wantCode {
+				t.Errorf("Expected %d, got %d: %s", tt.wantCode, rec.Code, rec.Body)
+			}
+		})
+	}
+
+	// Reports survive a restart
+	reloaded, err := NewFleet(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	teams := reloaded.Teams()
+	want := []TeamStatus{
+		{Team: "", Hosts: 1, Compliant: 1, Percent: 100},
+		{Team: "backend", Hosts: 2, Compliant: 1, Percent: 50},
+	}
+	if len(teams) != len(want) {
+		t.Fatalf("Expected teams %+v, got %+v", want, teams)
+	}
+	for i := range want {
+		if teams[i] != want[i] {
+			t.Errorf("Team %d = %+v, want %+v", i, teams[i], want[i])
+		}
+	}
+
+	backend := "backend"
+	hosts := reloaded.Hosts(&backend)
+	if len(hosts) != 2 || hosts[0].Host != "dev-01" || !hosts[0].Compliant || hosts[1].Host != "dev-02" || hosts[1].Compliant {
+		t.Errorf("Expected dev-01 compliant and dev-02 not, got %+v", hosts)
+	}
+}
+
+func TestFleetEndpoints(t *testing.T) {
+	fleet, err := NewFleet("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fleet.Store("dev-01", "web", checker.EnvironmentReport{Items: []checker.CheckResult{}}); err != nil {
+		t.Fatal(err)
+	}
+	handler := fleet.Handler()
+
+	tests := []struct {
+		method   string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{method: http.MethodGet, path: "/", wantCode: http.StatusOK, wantBody: "dev-01"},
+		{method: http.MethodGet, path: "/healthz", wantCode: http.StatusOK, wantBody: `"hosts": 1`},
+		{method: http.MethodGet, path: "/api/teams", wantCode: http.StatusOK, wantBody: `"team": "web"`},
+		{method: http.MethodGet, path: "/api/hosts?team=web", wantCode: http.StatusOK, wantBody: `"host": "dev-01"`},
+		{method: http.MethodGet, path: "/api/hosts?team=ops", wantCode: http.StatusOK, wantBody: "[]"},
+		{method: http.MethodGet, path: "/api/hosts/dev-01", wantCode: http.StatusOK, wantBody: `"report"`},
+		{method: http.MethodGet, path: "/api/hosts/dev-99", wantCode: http.StatusNotFound},
+		{method: http.MethodGet, path: "/reports", wantCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("Expected %d, got %d: %s", tt.wantCode, rec.Code, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Expected the body to contain %q, got %s", tt.wantBody, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Package server exposes check results over HTTP for `goctor doctor serve`, and collects
+// the results of a fleet of machines for `goctor server`
+//
+// Endpoints of Server:
+//
+//	GET  /healthz  liveness of the server itself, 200 while it is running
+//	GET  /report   the latest EnvironmentReport as JSON, 503 until the first check finishes
+//	POST /check    run the checks again and return the new report
+//
+// Endpoints of Fleet:
+//
+//	GET  /                  HTML dashboard of compliance per team and host
+//	GET  /healthz           liveness of the server and the number of hosts
+//	POST /reports           store the report of a host, as doctor --report-url uploads it
+//	GET  /api/teams         compliance per team
+//	GET  /api/hosts         latest status of every host, of one team with ?team=NAME
+//	GET  /api/hosts/{host}  latest report of one host
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+// CheckFunc runs the checks and returns a fresh report
+type CheckFunc func(ctx context.Context) (*checker.EnvironmentReport, error)
+
+// Server serves the latest report and re-runs checks on request
+type Server struct {
+	check CheckFunc
+
+	// checking serializes check runs so concurrent POST /check requests don't overlap
+	checking sync.Mutex
+
+	mu        sync.RWMutex
+	report    *checker.EnvironmentReport
+	lastError string
+	checkedAt time.Time
+	now       func() time.Time
+}
+
+// New creates a server that produces reports with check
+func New(check CheckFunc) *Server {
+	return &Server{check: check, now: time.Now}
+}
+
+// Check runs the checks and stores the report; failures are kept for /healthz
+func (s *Server) Check(ctx context.Context) (*checker.EnvironmentReport, error) {
+	s.checking.Lock()
+	defer s.checking.Unlock()
+
+	report, err := s.check(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkedAt = s.now()
+	if err != nil {
+		s.lastError = err.Error()
+		return nil, err
+	}
+	s.report = report
+	s.lastError = ""
+	return report, nil
+}
+
+// Handler returns the HTTP handler for the server's endpoints
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/report", s.handleReport)
+	mux.HandleFunc("/check", s.handleCheck)
+	return mux
+}
+
+// healthResponse is the body of GET /healthz
+type healthResponse struct {
+	Status    string     `json:"status"`
+	Ready     bool       `json:"ready"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// handleHealthz reports that the server is up and whether the machine passed its last check
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	s.mu.RLock()
+	response := healthResponse{Status: "ok", LastError: s.lastError}
+	if s.report != nil {
+		response.Ready = s.report.IsSuccessful()
+	}
+	if !s.checkedAt.IsZero() {
+		checkedAt := s.checkedAt
+		response.CheckedAt = &checkedAt
+	}
+	s.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleReport returns the latest report
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	s.mu.RLock()
+	report := s.report
+	s.mu.RUnlock()
+
+	if report == nil {
+		writeError(w, http.StatusServiceUnavailable, "no check has completed yet")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleCheck re-runs the checks and returns the new report
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	report, err := s.Check(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// allowMethod rejects requests with any other method, returning false if it did
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	return false
+}
+
+// writeError writes a JSON error body
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// writeJSON writes data as an indented JSON response
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	body, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
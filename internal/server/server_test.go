@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+func newReport(status checker.CheckStatus) *checker.EnvironmentReport {
+	return checker.NewEnvironmentReport(nil, "tools.yaml", []checker.CheckResult{
+		{ToolID: "go", ToolName: "Go", Status: status},
+	})
+}
+
+func TestServerEndpoints(t *testing.T) {
+	runs := 0
+	srv := New(func(ctx context.Context) (*checker.EnvironmentReport, error) {
+		runs++
+		if runs == 1 {
+			return newReport(checker.StatusOutdated), nil
+		}
+		return newReport(checker.StatusOK), nil
+	})
+	handler := srv.Handler()
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	// Before the first check, the server is alive but has nothing to report
+	if rec := request(http.MethodGet, "/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("Expected /healthz 200, got %d", rec.Code)
+	}
+	if rec := request(http.MethodGet, "/report"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /report 503 before the first check, got %d", rec.Code)
+	}
+
+	if rec := request(http.MethodPost, "/check"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected /check 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var health healthResponse
+	rec := request(http.MethodGet, "/healthz")
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+		t.Fatal(err)
+	}
+	if health.Ready || health.CheckedAt == nil {
+		t.Errorf("Expected not ready with a check time after an outdated result, got %+v", health)
+	}
+
+	request(http.MethodPost, "/check")
+	rec = request(http.MethodGet, "/report")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected /report 200, got %d", rec.Code)
+	}
+	var report checker.EnvironmentReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Summary.OK != 1 {
+		t.Errorf("Expected the latest report after re-check, got summary %+v", report.Summary)
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		allow  string
+	}{
+		{http.MethodPost, "/healthz", http.MethodGet},
+		{http.MethodDelete, "/report", http.MethodGet},
+		{http.MethodGet, "/check", http.MethodPost},
+	}
+	for _, tt := range tests {
+		rec := request(tt.method, tt.path)
+		if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != tt.allow {
+			t.Errorf("%s %s: expected 405 with Allow %s, got %d %q", tt.method, tt.path, tt.allow, rec.Code, rec.Header().Get("Allow"))
+		}
+	}
+}
+
+func TestServerCheckFailure(t *testing.T) {
+	fail := false
+	srv := New(func(ctx context.Context) (*checker.EnvironmentReport, error) {
+		if fail {
+			return nil, errors.New("loading manifest: not found")
+		}
+		return newReport(checker.StatusOK), nil
+	})
+	handler := srv.Handler()
+
+	if _, err := srv.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	fail = true
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/check", nil))
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "not found") {
+		t.Errorf("Expected 500 with the error, got %d: %s", rec.Code, rec.Body)
+	}
+
+	// The previous report stays available and the failure shows up in /healthz
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/report", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected previous report to stay available, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if !strings.Contains(rec.Body.String(), "loading manifest: not found") {
+		t.Errorf("Expected last error in /healthz, got %s", rec.Body)
+	}
+}
@@ -0,0 +1,438 @@
+// Package tui implements the interactive terminal dashboard behind `goctor tui`
+//
+// The dashboard lists the manifest's tools with their live statuses next to a detail pane for
+// the selected tool. It is redrawn as a whole after every key press and check result; the
+// state lives in Dashboard, which knows nothing about the terminal so it can be tested alone.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+// Backend provides the manifest and runs checks for the dashboard; it is the same backend
+// `goctor doctor lsp` serves editors from
+type Backend interface {
+	// Tools returns the tools of the current manifest
+	Tools(ctx context.Context) ([]manifest.ToolDefinition, error)
+	// Check checks the tools with the given IDs, or all tools if ids is empty, calling
+	// onResult as each one finishes
+	Check(ctx context.Context, ids []string, onResult func(checker.CheckResult)) (*checker.EnvironmentReport, error)
+}
+
+// Key is a key press the dashboard responds to
+type Key int
+
+const (
+	KeyNone Key = iota
+	KeyUp
+	KeyDown
+	KeyTop
+	KeyBottom
+	KeyCheck    // re-check the selected tool
+	KeyCheckAll // re-check every tool
+	KeyCopy     // copy the selected tool's install command
+	KeyQuit
+)
+
+// ActionKind says what the terminal loop has to do after a key press
+type ActionKind int
+
+const (
+	ActionNone ActionKind = iota
+	ActionQuit
+	ActionCheck
+	ActionCopy
+)
+
+// Action is the work a key press asks for
+type Action struct {
+	Kind ActionKind
+	IDs  []string // tools to check; empty checks all of them
+	Text string   // text to copy
+}
+
+// keyHelp is shown on the last line of the screen
+const keyHelp = "↑/↓ select  r re-check  R re-check all  c copy install command  q quit"
+
+// Dashboard holds the tools, their latest results, and the selection
+type Dashboard struct {
+	source   string
+	tools    []manifest.ToolDefinition
+	results  map[string]checker.CheckResult
+	checking map[string]bool
+	selected int
+	message  string
+	color    bool
+}
+
+// NewDashboard creates a dashboard for the manifest at source
+func NewDashboard(source string, color bool) *Dashboard {
+	return &Dashboard{
+		source:   source,
+		results:  map[string]checker.CheckResult{},
+		checking: map[string]bool{},
+		color:    color,
+	}
+}
+
+// SetTools replaces the tool list, keeping the selection on the same tool when it still exists
+func (d *Dashboard) SetTools(tools []manifest.ToolDefinition) {
+	var selectedID string
+	if tool, ok := d.Selected(); ok {
+		selectedID = tool.ID
+	}
+
+	d.tools = tools
+	d.selected = 0
+	for i, tool := range tools {
+		if tool.ID == selectedID {
+			d.selected = i
+		}
+	}
+}
+
+// Selected returns the tool under the cursor
+func (d *Dashboard) Selected() (manifest.ToolDefinition, bool) {
+	if d.selected < 0 || d.selected >= len(d.tools) {
+		return manifest.ToolDefinition{}, false
+	}
+	return d.tools[d.selected], true
+}
+
+// Checking reports whether a check is running
+func (d *Dashboard) Checking() bool {
+	return len(d.checking) > 0
+}
+
+// StartCheck marks the tools with the given IDs, or all tools, as being checked
+func (d *Dashboard) StartCheck(ids []string) {
+	if len(ids) == 0 {
+		for _, tool := range d.tools {
+			ids = append(ids, tool.ID)
+		}
+	}
+	for _, id := range ids {
+		d.checking[id] = true
+	}
+	d.message = ""
+}
+
+// AddResult records a finished check
+func (d *Dashboard) AddResult(result checker.CheckResult) {
+	d.results[result.ToolID] = result
+	delete(d.checking, result.ToolID)
+}
+
+// FinishCheck clears what is left of a check, such as tools skipped on this platform
+func (d *Dashboard) FinishCheck(err error) {
+	d.checking = map[string]bool{}
+	if err != nil {
+		d.message = "Check failed: " + err.Error()
+	}
+}
+
+// SetMessage shows a message above the key help
+func (d *Dashboard) SetMessage(message string) {
+	d.message = message
+}
+
+// Handle applies a key press and returns the work it asks for
+func (d *Dashboard) Handle(key Key) Action {
+	switch key {
+	case KeyUp:
+		if d.selected > 0 {
+			d.selected--
+		}
+	case KeyDown:
+		if d.selected < len(d.tools)-1 {
+			d.selected++
+		}
+	case KeyTop:
+		d.selected = 0
+	case KeyBottom:
+		d.selected = max(len(d.tools)-1, 0)
+	case KeyQuit:
+		return Action{Kind: ActionQuit}
+	case KeyCheck, KeyCheckAll:
+		if d.Checking() {
+			d.message = "A check is already running"
+			return Action{}
+		}
+		if key == KeyCheckAll {
+			return Action{Kind: ActionCheck}
+		}
+		if tool, ok := d.Selected(); ok {
+			return Action{Kind: ActionCheck, IDs: []string{tool.ID}}
+		}
+	case KeyCopy:
+		tool, ok := d.Selected()
+		if !ok {
+			return Action{}
+		}
+		hint := d.results[tool.ID].InstallHint
+		if hint == "" {
+			d.message = fmt.Sprintf("No install command for %s on this platform", toolName(tool))
+			return Action{}
+		}
+		return Action{Kind: ActionCopy, Text: hint}
+	}
+	return Action{}
+}
+
+// Render draws the whole screen as lines separated by "\r\n", fitted to width and height
+func (d *Dashboard) Render(width, height int) string {
+	if width < 20 || height < 5 {
+		return truncate("Terminal too small", width)
+	}
+
+	listWidth := min(max(width/3, 16), 40)
+	detailWidth := width - listWidth - 3
+	bodyHeight := height - 3
+
+	list := d.listLines(bodyHeight)
+	detail := d.detailLines(detailWidth)
+
+	lines := []string{d.header(width)}
+	for i := 0; i < bodyHeight; i++ {
+		var left, right string
+		if i < len(list) {
+			left = list[i]
+		}
+		if i < len(detail) {
+			right = detail[i]
+		}
+		lines = append(lines, pad(left, listWidth)+" │ "+truncate(right, detailWidth))
+	}
+	lines = append(lines, truncate(d.message, width), d.colorize(truncate(keyHelp, width), "gray"))
+
+	return strings.Join(lines, "\r\n")
+}
+
+// header shows the manifest and the counts of the latest results
+func (d *Dashboard) header(width int) string {
+	var ok, failing int
+	for _, result := range d.results {
+		if result.Status == checker.StatusSkipped {
+			continue
+		}
+		if result.Status == checker.StatusOK || result.Informational {
+			ok++
+		} else {
+			failing++
+		}
+	}
+
+	header := fmt.Sprintf("goctor — %s   %d ok, %d need attention", d.source, ok, failing)
+	if d.Checking() {
+		header += fmt.Sprintf(", checking %d…", len(d.checking))
+	}
+	return truncate(header, width)
+}
+
+// listLines shows the tools with their status icons, scrolled to keep the selection visible
+func (d *Dashboard) listLines(height int) []string {
+	offset := 0
+	if d.selected >= height {
+		offset = d.selected - height + 1
+	}
+
+	var lines []string
+	for i := offset; i < len(d.tools) && i < offset+height; i++ {
+		tool := d.tools[i]
+		cursor := "  "
+		if i == d.selected {
+			cursor = "> "
+		}
+		lines = append(lines, cursor+d.icon(tool.ID)+" "+toolName(tool))
+	}
+	return lines
+}
+
+// detailLines describes the selected tool and its latest result
+func (d *Dashboard) detailLines(width int) []string {
+	tool, ok := d.Selected()
+	if !ok {
+		return []string{"No tools in the manifest"}
+	}
+
+	lines := []string{toolName(tool) + " (" + tool.ID + ")", ""}
+
+	result, checked := d.results[tool.ID]
+	switch {
+	case d.checking[tool.ID]:
+		lines = append(lines, "Status:    checking…")
+	case checked:
+		lines = append(lines, "Status:    "+d.icon(tool.ID)+" "+result.Status.String())
+	default:
+		lines = append(lines, "Status:    not checked")
+	}
+	if tool.RequiredVersion != "" {
+		lines = append(lines, "Required:  "+tool.RequiredVersion)
+	}
+	if result.ActualVersion != "" {
+		lines = append(lines, "Installed: "+result.ActualVersion)
+	}
+	if result.CommandPath != "" {
+		lines = append(lines, "Path:      "+result.CommandPath)
+	}
+	if result.ErrorMessage != "" {
+		lines = append(lines, "Error:     "+result.ErrorMessage)
+	}
+
+	if tool.Rationale != "" {
+		lines = append(lines, "", "Why:")
+		lines = append(lines, wrap(tool.Rationale, width-2, "  ")...)
+	}
+
+	if result.InstallHint != "" {
+		lines = append(lines, "", "Install:", "  "+result.InstallHint)
+	}
+
+	if len(tool.Links) > 0 {
+		names := make([]string, 0, len(tool.Links))
+		for name := range tool.Links {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		lines = append(lines, "", "Links:")
+		for _, name := range names {
+			lines = append(lines, "  "+name+": "+tool.Links[name])
+		}
+	}
+
+	return lines
+}
+
+// icon returns the status icon of a tool's latest result
+func (d *Dashboard) icon(id string) string {
+	if d.checking[id] {
+		return d.colorize("…", "blue")
+	}
+	result, ok := d.results[id]
+	if !ok {
+		return d.colorize("·", "gray")
+	}
+
+	switch result.Status {
+	case checker.StatusOK:
+		return d.colorize("✓", "green")
+	case checker.StatusNotFound, checker.StatusMissing:
+		return d.colorize("✗", "red")
+	case checker.StatusOutdated:
+		return d.colorize("⚠", "yellow")
+	case checker.StatusError:
+		return d.colorize("!", "red")
+	case checker.StatusSkipped:
+		return d.colorize("-", "gray")
+	default:
+		return d.colorize("?", "gray")
+	}
+}
+
+// colorize applies color codes to text if colors are enabled
+func (d *Dashboard) colorize(text, color string) string {
+	if !d.color || text == "" {
+		return text
+	}
+
+	colorCodes := map[string]string{
+		"red":    "\033[31m",
+		"green":  "\033[32m",
+		"yellow": "\033[33m",
+		"blue":   "\033[34m",
+		"gray":   "\033[90m",
+	}
+	return colorCodes[color] + text + "\033[0m"
+}
+
+// toolName returns the display name of a tool
+func toolName(tool manifest.ToolDefinition) string {
+	if tool.Name != "" {
+		return tool.Name
+	}
+	return tool.ID
+}
+
+// visibleWidth counts the runes of s that take up a column, skipping color codes
+func visibleWidth(s string) int {
+	width := 0
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case r == '\033':
+			inEscape = true
+		case inEscape:
+			if r == 'm' {
+				inEscape = false
+			}
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+// truncate cuts s to width columns, keeping color codes intact
+func truncate(s string, width int) string {
+	if visibleWidth(s) <= width {
+		return s
+	}
+
+	var out strings.Builder
+	columns := 0
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case r == '\033':
+			inEscape = true
+		case inEscape:
+			if r == 'm' {
+				inEscape = false
+			}
+		default:
+			if columns == width-1 {
+				out.WriteString("…")
+				if strings.Contains(s, "\033[") {
+					out.WriteString("\033[0m")
+				}
+				return out.String()
+			}
+			columns++
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
+// pad fits s to exactly width columns
+func pad(s string, width int) string {
+	s = truncate(s, width)
+	return s + strings.Repeat(" ", width-visibleWidth(s))
+}
+
+// wrap breaks text into lines of at most width columns, each starting with indent
+func wrap(text string, width int, indent string) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && visibleWidth(line)+1+visibleWidth(word) > width {
+			lines = append(lines, indent+line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, indent+line)
+	}
+	return lines
+}
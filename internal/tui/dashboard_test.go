@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/internal/checker"
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+func newTestDashboard() *Dashboard {
+	d := NewDashboard("tools.yaml", false)
+	d.SetTools([]manifest.ToolDefinition{
+		{ID: "go", Name: "Go", RequiredVersion: ">=1.22", Rationale: "Builds the backend services"},
+		{ID: "docker", Name: "Docker", Links: map[string]string{"docs": "https://docs.docker.com"}},
+		{ID: "jq"},
+	})
+	d.AddResult(checker.CheckResult{ToolID: "go", Status: checker.StatusOK, ActualVersion: "1.22.3"})
+	d.AddResult(checker.CheckResult{ToolID: "docker", Status: checker.StatusNotFound, InstallHint: "brew install --cask docker"})
+	return d
+}
+
+func TestHandle(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []Key
+		want     Action
+		selected string
+		message  string
+	}{
+		{name: "down then check", keys: []Key{KeyDown, KeyCheck}, want: Action{Kind: ActionCheck, IDs: []string{"docker"}}, selected: "docker"},
+		{name: "up stops at the top", keys: []Key{KeyUp, KeyUp}, selected: "go"},
+		{name: "down stops at the bottom", keys: []Key{KeyDown, KeyDown, KeyDown, KeyDown}, selected: "jq"},
+		{name: "bottom then top", keys: []Key{KeyBottom, KeyTop}, selected: "go"},
+		{name: "check all", keys: []Key{KeyCheckAll}, want: Action{Kind: ActionCheck}, selected: "go"},
+		{name: "copy install hint", keys: []Key{KeyDown, KeyCopy}, want: Action{Kind: ActionCopy, Text: "brew install --cask docker"}, selected: "docker"},
+		{name: "copy without hint", keys: []Key{KeyCopy}, selected: "go", message: "No install command for Go on this platform"},
+		{name: "quit", keys: []Key{KeyQuit}, want: Action{Kind: ActionQuit}, selected: "go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDashboard()
+			var got Action
+			for _, key := range tt.keys {
+				got = d.Handle(key)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Handle() = %+v, want %+v", got, tt.want)
+			}
+			if tool, _ := d.Selected(); tool.ID != tt.selected {
+				t.Errorf("selected %q, want %q", tool.ID, tt.selected)
+			}
+			if d.message != tt.message {
+				t.Errorf("message %q, want %q", d.message, tt.message)
+			}
+		})
+	}
+}
+
+func TestCheckLifecycle(t *testing.T) {
+	d := newTestDashboard()
+
+	d.StartCheck(nil)
+	if !d.Checking() {
+		t.Fatal("expected a running check")
+	}
+	if got := d.Handle(KeyCheck); got.Kind != ActionNone || d.message != "A check is already running" {
+		t.Errorf("Handle() during a check = %+v (%q)", got, d.message)
+	}
+
+	d.AddResult(checker.CheckResult{ToolID: "jq", Status: checker.StatusOK})
+	d.FinishCheck(errors.New("manifest not found"))
+	if d.Checking() {
+		t.Error("check still running after FinishCheck")
+	}
+	if d.message != "Check failed: manifest not found" {
+		t.Errorf("message %q", d.message)
+	}
+}
+
+func TestSetToolsKeepsSelection(t *testing.T) {
+	d := newTestDashboard()
+	d.Handle(KeyDown)
+
+	d.SetTools([]manifest.ToolDefinition{{ID: "node"}, {ID: "docker"}})
+	if tool, _ := d.Selected(); tool.ID != "docker" {
+		t.Errorf("selected %q, want docker", tool.ID)
+	}
+
+	d.SetTools([]manifest.ToolDefinition{{ID: "node"}})
+	if tool, _ := d.Selected(); tool.ID != "node" {
+		t.Errorf("selected %q, want node", tool.ID)
+	}
+}
+
+func TestRender(t *testing.T) {
+	d := newTestDashboard()
+	d.Handle(KeyDown)
+
+	screen := d.Render(80, 12)
+	lines := strings.Split(screen, "\r\n")
+	if len(lines) != 12 {
+		t.Fatalf("rendered %d lines, want 12", len(lines))
+	}
+	for i, line := range lines {
+		if visibleWidth(line) > 80 {
+			t.Errorf("line %d is %d columns wide: %q", i, visibleWidth(line), line)
+		}
+	}
+
+	for _, want := range []string{
+		"goctor — tools.yaml   1 ok, 1 need attention",
+		"  ✓ Go",
+		"> ✗ Docker",
+		"  · jq",
+		"Docker (docker)",
+		"Status:    ✗ not_found",
+		"brew install --cask docker",
+		"docs: https://docs.docker.com",
+		keyHelp,
+	} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("screen does not contain %q:\n%s", want, screen)
+		}
+	}
+}
+
+func TestRenderScrollsToSelection(t *testing.T) {
+	d := NewDashboard("tools.yaml", false)
+	var tools []manifest.ToolDefinition
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		tools = append(tools, manifest.ToolDefinition{ID: id})
+	}
+	d.SetTools(tools)
+	d.Handle(KeyBottom)
+
+	screen := d.Render(60, 6)
+	if !strings.Contains(screen, "> · f") || strings.Contains(screen, "· a") {
+		t.Errorf("selection not scrolled into view:\n%s", screen)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"a bit too long", 8, "a bit t…"},
+		{"\033[32m✓\033[0m Go toolchain", 6, "\033[32m✓\033[0m Go …\033[0m"},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.width); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	got := wrap("Builds the backend services locally", 16, "  ")
+	want := []string{"  Builds the", "  backend services", "  locally"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrap() = %q, want %q", got, want)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []Key
+	}{
+		{"j", []Key{KeyDown}},
+		{"k", []Key{KeyUp}},
+		{"\033[A\033[B", []Key{KeyUp, KeyDown}},
+		{"\033OA", []Key{KeyUp}},
+		{"\033[H\033[F", []Key{KeyTop, KeyBottom}},
+		{"rRc", []Key{KeyCheck, KeyCheckAll, KeyCopy}},
+		{"\r", []Key{KeyCheck}},
+		{"q", []Key{KeyQuit}},
+		{"\x03", []Key{KeyQuit}},
+		{"\033", []Key{KeyQuit}},
+		{"x\033[C", nil},
+	}
+
+	for _, tt := range tests {
+		if got := parseKeys([]byte(tt.input)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseKeys(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/checker"
+)
+
+// sizePollInterval is how often the terminal size is read to notice resizes
+const sizePollInterval = 500 * time.Millisecond
+
+// Escape sequences for the screen
+const (
+	enterScreen = "\033[?1049h\033[?25l" // alternate screen, hidden cursor
+	leaveScreen = "\033[?25h\033[?1049l"
+	clearScreen = "\033[H\033[2J"
+)
+
+// Run shows the dashboard on the terminal until the user quits or ctx is cancelled
+// All tools are checked when the dashboard opens; in must be the terminal so it can be put
+// into raw mode with stty
+func Run(ctx context.Context, backend Backend, dashboard *Dashboard, in *os.File, out io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tools, err := backend.Tools(ctx)
+	if err != nil {
+		return err
+	}
+	dashboard.SetTools(tools)
+
+	restore, err := rawMode(in)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	fmt.Fprint(out, enterScreen)
+	defer fmt.Fprint(out, leaveScreen)
+
+	keys := make(chan Key)
+	go readKeys(in, keys)
+
+	results := make(chan checker.CheckResult)
+	done := make(chan error, 1)
+	startCheck := func(ids []string) {
+		if len(ids) == 0 {
+			// Checking everything picks up tools added to the manifest since the last check
+			if tools, err := backend.Tools(ctx); err == nil {
+				dashboard.SetTools(tools)
+			}
+		}
+		dashboard.StartCheck(ids)
+		go func() {
+			_, err := backend.Check(ctx, ids, func(result checker.CheckResult) {
+				select {
+				case results <- result:
+				case <-ctx.Done():
+				}
+			})
+			done <- err
+		}()
+	}
+
+	width, height := terminalSize(in)
+	redraw := func() {
+		fmt.Fprint(out, clearScreen+dashboard.Render(width, height))
+	}
+
+	ticker := time.NewTicker(sizePollInterval)
+	defer ticker.Stop()
+
+	startCheck(nil)
+	for {
+		redraw()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case key := <-keys:
+			action := dashboard.Handle(key)
+			switch action.Kind {
+			case ActionQuit:
+				return nil
+			case ActionCheck:
+				startCheck(action.IDs)
+			case ActionCopy:
+				if err := copyToClipboard(action.Text, out); err != nil {
+					dashboard.SetMessage("Copy failed: " + err.Error())
+				} else {
+					dashboard.SetMessage("Copied: " + action.Text)
+				}
+			}
+		case result := <-results:
+			dashboard.AddResult(result)
+		case err := <-done:
+			dashboard.FinishCheck(err)
+		case <-ticker.C:
+			newWidth, newHeight := terminalSize(in)
+			if newWidth == width && newHeight == height {
+				continue
+			}
+			width, height = newWidth, newHeight
+		}
+	}
+}
+
+// rawMode switches the terminal to raw input without echo and returns a function that
+// restores the previous settings
+func rawMode(in *os.File) (func(), error) {
+	saved, err := stty(in, "-g")
+	if err != nil {
+		return nil, fmt.Errorf("reading terminal settings: %v", err)
+	}
+	if _, err := stty(in, "raw", "-echo"); err != nil {
+		return nil, fmt.Errorf("switching terminal to raw mode: %v", err)
+	}
+	return func() { stty(in, strings.TrimSpace(saved)) }, nil
+}
+
+// terminalSize returns the columns and rows of the terminal, or 80x24 if stty cannot tell
+func terminalSize(in *os.File) (int, int) {
+	output, err := stty(in, "size")
+	if err != nil {
+		return 80, 24
+	}
+
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 80, 24
+	}
+	rows, rowsErr := strconv.Atoi(fields[0])
+	columns, columnsErr := strconv.Atoi(fields[1])
+	if rowsErr != nil || columnsErr != nil || rows == 0 || columns == 0 {
+		return 80, 24
+	}
+	return columns, rows
+}
+
+// stty runs stty on the terminal
+func stty(in *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = in
+	output, err := cmd.Output()
+	return string(output), err
+}
+
+// readKeys sends the keys read from the terminal until it is closed
+func readKeys(in io.Reader, keys chan<- Key) {
+	buf := make([]byte, 64)
+	for {
+		n, err := in.Read(buf)
+		for _, key := range parseKeys(buf[:n]) {
+			keys <- key
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// parseKeys turns the bytes of one terminal read into keys, ignoring unbound ones
+func parseKeys(input []byte) []Key {
+	var keys []Key
+	for len(input) > 0 {
+		// Arrow keys and Home/End arrive as ESC [ x or, in application mode, ESC O x
+		if input[0] == 0x1b {
+			if len(input) == 1 {
+				keys = append(keys, KeyQuit)
+				break
+			}
+			if len(input) >= 3 && (input[1] == '[' || input[1] == 'O') {
+				switch input[2] {
+				case 'A':
+					keys = append(keys, KeyUp)
+				case 'B':
+					keys = append(keys, KeyDown)
+				case 'H':
+					keys = append(keys, KeyTop)
+				case 'F':
+					keys = append(keys, KeyBottom)
+				}
+				input = input[3:]
+				continue
+			}
+			input = input[1:]
+			continue
+		}
+
+		switch input[0] {
+		case 'k':
+			keys = append(keys, KeyUp)
+		case 'j':
+			keys = append(keys, KeyDown)
+		case 'g':
+			keys = append(keys, KeyTop)
+		case 'G':
+			keys = append(keys, KeyBottom)
+		case 'r', '\r':
+			keys = append(keys, KeyCheck)
+		case 'R':
+			keys = append(keys, KeyCheckAll)
+		case 'c':
+			keys = append(keys, KeyCopy)
+		case 'q', 0x03: // Ctrl-C arrives as a byte in raw mode
+			keys = append(keys, KeyQuit)
+		}
+		input = input[1:]
+	}
+	return keys
+}
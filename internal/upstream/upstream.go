@@ -0,0 +1,291 @@
+// Package upstream looks up the latest release of tools from the sources their manifest
+// declares, so maintainers can tell when required versions have fallen behind
+//
+// Supported sources are GitHub releases, the Homebrew formula API, and endoflife.date.
+package upstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+	"github.com/ikorihn/goctor/internal/semver"
+)
+
+// Source names
+const (
+	SourceGitHub    = "github"
+	SourceHomebrew  = "homebrew"
+	SourceEndOfLife = "endoflife"
+)
+
+// DefaultTimeout bounds each request to an upstream source
+const DefaultTimeout = 10 * time.Second
+
+// Default API endpoints
+const (
+	DefaultGitHubAPI    = "https://api.github.com"
+	DefaultHomebrewAPI  = "https://formulae.brew.sh/api"
+	DefaultEndOfLifeAPI = "https://endoflife.date/api"
+)
+
+// versionInTagRegex finds the version in release tags such as v2.45.0, jq-1.7.1, or go1.22.1
+var versionInTagRegex = regexp.MustCompile(`\d+(?:\.\d+)*`)
+
+// Release is the latest version published by a source
+type Release struct {
+	Version string `json:"version"`
+	Source  string `json:"source"`
+}
+
+// Client queries upstream sources
+type Client struct {
+	client       *http.Client
+	githubAPI    string
+	homebrewAPI  string
+	endOfLifeAPI string
+	githubToken  string
+}
+
+// NewClient creates a client for the public APIs with the given request timeout
+func NewClient(timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Client{
+		client:       &http.Client{Timeout: timeout},
+		githubAPI:    DefaultGitHubAPI,
+		homebrewAPI:  DefaultHomebrewAPI,
+		endOfLifeAPI: DefaultEndOfLifeAPI,
+	}
+}
+
+// SetGitHubToken authenticates GitHub requests, which raises the API rate limit
+func (c *Client) SetGitHubToken(token string) {
+	c.githubToken = token
+}
+
+// Latest returns the latest release from the first declared source that answers
+// If every source fails, the error of the first one is returned
+func (c *Client) Latest(ctx context.Context, u manifest.Upstream) (Release, error) {
+	type lookup struct {
+		source string
+		fetch  func(context.Context) (string, error)
+	}
+
+	var lookups []lookup
+	if repo := u.GitHubRepository(); repo != "" {
+		lookups = append(lookups, lookup{SourceGitHub, func(ctx context.Context) (string, error) { return c.github(ctx, repo) }})
+	}
+	if u.Homebrew != "" {
+		lookups = append(lookups, lookup{SourceHomebrew, func(ctx context.Context) (string, error) { return c.homebrew(ctx, u.Homebrew) }})
+	}
+	if u.EndOfLife != "" {
+		lookups = append(lookups, lookup{SourceEndOfLife, func(ctx context.Context) (string, error) { return c.endOfLife(ctx, u.EndOfLife) }})
+	}
+	if len(lookups) == 0 {
+		return Release{}, errors.New("no upstream source declared")
+	}
+
+	var firstErr error
+	for _, l := range lookups {
+		raw, err := l.fetch(ctx)
+		if err == nil {
+			version := versionInTagRegex.FindString(raw)
+			if version != "" {
+				return Release{Version: version, Source: l.source}, nil
+			}
+			err = fmt.Errorf("no version in %q", raw)
+		}
+		if firstErr == nil {
+			firstErr = fmt.Errorf("%s: %v", l.source, err)
+		}
+	}
+
+	return Release{}, firstErr
+}
+
+// github returns the tag of the repository's latest release
+func (c *Client) github(ctx context.Context, repo string) (string, error) {
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	headers := map[string]string{"Accept": "application/vnd.github+json"}
+	if c.githubToken != "" {
+		headers["Authorization"] = "Bearer " + c.githubToken
+	}
+	if err := c.getJSON(ctx, c.githubAPI+"/repos/"+repo+"/releases/latest", headers, &release); err != nil {
+		return "", err
+	}
+	return release.TagName, nil
+}
+
+// homebrew returns the stable version of a formula
+func (c *Client) homebrew(ctx context.Context, formula string) (string, error) {
+	var info struct {
+		Versions struct {
+			Stable string `json:"stable"`
+		} `json:"versions"`
+	}
+	if err := c.getJSON(ctx, c.homebrewAPI+"/formula/"+url.PathEscape(formula)+".json", nil, &info); err != nil {
+		return "", err
+	}
+	return info.Versions.Stable, nil
+}
+
+// endOfLife returns the latest release of the newest release cycle of a product
+func (c *Client) endOfLife(ctx context.Context, product string) (string, error) {
+	var cycles []struct {
+		Latest string `json:"latest"`
+	}
+	if err := c.getJSON(ctx, c.endOfLifeAPI+"/"+url.PathEscape(product)+".json", nil, &cycles); err != nil {
+		return "", err
+	}
+	if len(cycles) == 0 {
+		return "", errors.New("no release cycles")
+	}
+	return cycles[0].Latest, nil
+}
+
+// getJSON fetches a URL and decodes its JSON body into v
+func (c *Client) getJSON(ctx context.Context, link string, headers map[string]string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "goctor-outdated")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// Lag is how far a version is behind the latest release
+type Lag string
+
+// Lags from least to most significant
+const (
+	LagNone  Lag = ""
+	LagPatch Lag = "patch"
+	LagMinor Lag = "minor"
+	LagMajor Lag = "major"
+)
+
+// Lags lists the lags a threshold can be set to, least significant first
+var Lags = []Lag{LagPatch, LagMinor, LagMajor}
+
+// rank orders lags by significance
+func (l Lag) rank() int {
+	switch l {
+	case LagPatch:
+		return 1
+	case LagMinor:
+		return 2
+	case LagMajor:
+		return 3
+	}
+	return 0
+}
+
+// AtLeast returns true if the lag is as significant as threshold; no lag never is
+func (l Lag) AtLeast(threshold Lag) bool {
+	return l != LagNone && l.rank() >= threshold.rank()
+}
+
+// Behind returns the most significant version component in which version trails latest
+// Versions that cannot be compared, or are not older, have no lag
+func Behind(version, latest string) Lag {
+	current, err := parseVersion(version)
+	if err != nil {
+		return LagNone
+	}
+	newest, err := parseVersion(latest)
+	if err != nil {
+		return LagNone
+	}
+
+	switch {
+	case current.Compare(newest) >= 0:
+		return LagNone
+	case current.Major < newest.Major:
+		return LagMajor
+	case current.Major == newest.Major && current.Minor < newest.Minor:
+		return LagMinor
+	default:
+		return LagPatch
+	}
+}
+
+// parseVersion parses the first version in s, ignoring components beyond the patch level
+func parseVersion(s string) (semver.Version, error) {
+	parts := strings.SplitN(versionInTagRegex.FindString(s), ".", 4)
+	if len(parts) > 3 {
+		parts = parts[:3]
+	}
+	return semver.ParseVersion(strings.Join(parts, "."))
+}
+
+// Finding compares a tool's required and installed versions with its latest release
+type Finding struct {
+	ToolID       string `json:"id"`
+	ToolName     string `json:"name"`
+	Latest       string `json:"latest,omitempty"`
+	Source       string `json:"source,omitempty"`
+	Required     string `json:"required,omitempty"`
+	RequiredLag  Lag    `json:"required_lag,omitempty"`
+	Installed    string `json:"installed,omitempty"`
+	InstalledLag Lag    `json:"installed_lag,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// Outdated returns true if the required or installed version lags by at least threshold
+func (f Finding) Outdated(threshold Lag) bool {
+	return f.RequiredLag.AtLeast(threshold) || f.InstalledLag.AtLeast(threshold)
+}
+
+// Compare looks up the tool's latest release and measures how far the minimum of its
+// requirement and the installed version, if any, are behind it
+func (c *Client) Compare(ctx context.Context, tool manifest.ToolDefinition, installed string) Finding {
+	finding := Finding{ToolID: tool.ID, ToolName: tool.Name, Installed: installed}
+	finding.Required, _ = semver.MinimumVersion(tool.RequiredVersion)
+
+	if tool.Upstream == nil {
+		finding.Error = "no upstream source declared"
+		return finding
+	}
+
+	release, err := c.Latest(ctx, *tool.Upstream)
+	if err != nil {
+		finding.Error = err.Error()
+		return finding
+	}
+
+	finding.Latest = release.Version
+	finding.Source = release.Source
+	if finding.Required != "" {
+		finding.RequiredLag = Behind(finding.Required, release.Version)
+	}
+	if installed != "" {
+		finding.InstalledLag = Behind(installed, release.Version)
+	}
+
+	return finding
+}
@@ -0,0 +1,128 @@
+package upstream
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ikorihn/goctor/internal/manifest"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/github/repos/cli/cli/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"tag_name": "v2.45.0"}`))
+	})
+	mux.HandleFunc("/brew/formula/jq.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"versions": {"stable": "1.7.1"}}`))
+	})
+	mux.HandleFunc("/eol/go.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"cycle": "1.23", "latest": "1.23.2"}, {"cycle": "1.22", "latest": "1.22.8"}]`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := NewClient(time.Second)
+	client.githubAPI = server.URL + "/github"
+	client.homebrewAPI = server.URL + "/brew"
+	client.endOfLifeAPI = server.URL + "/eol"
+	client.SetGitHubToken("secret")
+	return client
+}
+
+func TestLatest(t *testing.T) {
+	client := newTestClient(t)
+
+	tests := []struct {
+		name        string
+		upstream    manifest.Upstream
+		wantVersion string
+		wantSource  string
+		wantErr     string
+	}{
+		{"github repository", manifest.Upstream{GitHub: "cli/cli"}, "2.45.0", SourceGitHub, ""},
+		{"github URL", manifest.Upstream{GitHub: "https://github.com/cli/cli/releases"}, "2.45.0", SourceGitHub, ""},
+		{"homebrew", manifest.Upstream{Homebrew: "jq"}, "1.7.1", SourceHomebrew, ""},
+		{"endoflife", manifest.Upstream{EndOfLife: "go"}, "1.23.2", SourceEndOfLife, ""},
+		{"falls back to the next source", manifest.Upstream{GitHub: "golang/go", EndOfLife: "go"}, "1.23.2", SourceEndOfLife, ""},
+		{"every source fails", manifest.Upstream{GitHub: "golang/go", Homebrew: "missing"}, "", "", "github: HTTP 404"},
+		{"no source", manifest.Upstream{}, "", "", "no upstream source declared"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release, err := client.Latest(context.Background(), tt.upstream)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if release.Version != tt.wantVersion || release.Source != tt.wantSource {
+				t.Errorf("Expected %s from %s, got %+v", tt.wantVersion, tt.wantSource, release)
+			}
+		})
+	}
+}
+
+func TestBehind(t *testing.T) {
+	tests := []struct {
+		version string
+		latest  string
+		want    Lag
+	}{
+		{"1.22", "1.23.2", LagMinor},
+		{"1.23.0", "1.23.2", LagPatch},
+		{"1.23.2", "1.23.2", LagNone},
+		{"2.0.0", "1.23.2", LagNone},
+		{"1.9", "2.45.0", LagMajor},
+		{"v2.44.1", "2.45.0", LagMinor},
+		{"1.1.1.4", "1.1.1", LagNone},
+		{"unknown", "1.0.0", LagNone},
+	}
+
+	for _, tt := range tests {
+		if got := Behind(tt.version, tt.latest); got != tt.want {
+			t.Errorf("Behind(%q, %q) = %q, want %q", tt.version, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	client := newTestClient(t)
+
+	tool := manifest.ToolDefinition{
+		ID:              "go",
+		Name:            "Go",
+		RequiredVersion: ">=1.20 <2",
+		Upstream:        &manifest.Upstream{EndOfLife: "go"},
+	}
+
+	finding := client.Compare(context.Background(), tool, "1.23.1")
+	if finding.Latest != "1.23.2" || finding.Required != "1.20" {
+		t.Fatalf("Unexpected finding: %+v", finding)
+	}
+	if finding.RequiredLag != LagMinor || finding.InstalledLag != LagPatch {
+		t.Errorf("Expected minor and patch lag, got %q and %q", finding.RequiredLag, finding.InstalledLag)
+	}
+	if !finding.Outdated(LagMinor) || finding.Outdated(LagMajor) {
+		t.Errorf("Expected the finding to be outdated at minor but not major")
+	}
+
+	noUpstream := client.Compare(context.Background(), manifest.ToolDefinition{ID: "git"}, "")
+	if noUpstream.Error == "" || noUpstream.Outdated(LagPatch) {
+		t.Errorf("Expected an error and no lag without an upstream, got %+v", noUpstream)
+	}
+}
@@ -0,0 +1,123 @@
+// Package versionfile reads the tool versions a project pins in its own files, such as .nvmrc,
+// .tool-versions, and go.mod, so a manifest can take its requirements from the repository
+package versionfile
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// File formats
+const (
+	FormatPlain        = "plain"         // A version on the first line: .nvmrc, .python-version, ...
+	FormatToolVersions = "tool-versions" // asdf and mise: one "tool version..." line per tool
+	FormatGoMod        = "go.mod"        // The go or toolchain directive
+)
+
+// FormatOf returns the format of a file, decided by its name; any file not recognized holds
+// a plain version
+func FormatOf(path string) string {
+	switch filepath.Base(path) {
+	case ".tool-versions":
+		return FormatToolVersions
+	case "go.mod":
+		return FormatGoMod
+	default:
+		return FormatPlain
+	}
+}
+
+// Read returns the version pinned in the file at path, without a leading v
+// key selects the entry: the tool in .tool-versions, where toolID is the default, and the
+// directive in go.mod ("go" by default, or "toolchain"); plain files have no keys
+func Read(path, key, toolID string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	var version string
+	switch FormatOf(path) {
+	case FormatToolVersions:
+		if key == "" {
+			key = toolID
+		}
+		version, err = parseToolVersions(data, key)
+	case FormatGoMod:
+		version, err = parseGoMod(data, key)
+	default:
+		if key != "" {
+			return "", fmt.Errorf("%s: key %q given but the file holds a single version", path, key)
+		}
+		version, err = parsePlain(data)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", path, err)
+	}
+
+	if !versionRegex.MatchString(version) {
+		return "", fmt.Errorf("%s: %q is not a version", path, version)
+	}
+	return strings.TrimPrefix(version, "v"), nil
+}
+
+// versionRegex matches the versions files pin; aliases such as lts/iron or system are rejected
+var versionRegex = regexp.MustCompile(`^v?\d+(\.\d+)*([-+][0-9A-Za-z.-]+)?$`)
+
+// lines returns the lines of data without comments and surrounding whitespace, skipping empty ones
+func lines(data []byte, comment string) []string {
+	var result []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), comment)
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// parsePlain returns the first line of a single-version file
+func parsePlain(data []byte) (string, error) {
+	lines := lines(data, "#")
+	if len(lines) == 0 {
+		return "", errors.New("no version found")
+	}
+	return strings.Fields(lines[0])[0], nil
+}
+
+// parseToolVersions returns the first, preferred version of a tool in a .tool-versions file
+func parseToolVersions(data []byte, tool string) (string, error) {
+	for _, line := range lines(data, "#") {
+		fields := strings.Fields(line)
+		if fields[0] == tool && len(fields) > 1 {
+			return fields[1], nil
+		}
+	}
+	return "", fmt.Errorf("no version for %s", tool)
+}
+
+// parseGoMod returns the version of the go directive, or of the toolchain directive
+func parseGoMod(data []byte, directive string) (string, error) {
+	if directive == "" {
+		directive = "go"
+	}
+	if directive != "go" && directive != "toolchain" {
+		return "", fmt.Errorf("unknown key %q (must be go or toolchain)", directive)
+	}
+
+	for _, line := range lines(data, "//") {
+		fields := strings.Fields(line)
+		if fields[0] == directive && len(fields) > 1 {
+			// The toolchain directive names a toolchain, e.g. go1.22.3
+			return strings.TrimPrefix(fields[1], "go"), nil
+		}
+	}
+	return "", fmt.Errorf("no %s directive", directive)
+}
@@ -0,0 +1,64 @@
+package versionfile
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		key       string
+		want      string
+		wantError string
+	}{
+		{name: "nvmrc", file: ".nvmrc", content: "v20.11.0\n", want: "20.11.0"},
+		{name: "python version with comment", file: ".python-version", content: "# pinned\n3.12.1\n3.11.7\n", want: "3.12.1"},
+		{name: "nvmrc alias", file: ".nvmrc", content: "lts/iron\n", wantError: `"lts/iron" is not a version`},
+		{name: "empty file", file: ".ruby-version", content: "\n", wantError: "no version found"},
+		{name: "key for plain file", file: ".nvmrc", content: "20\n", key: "node", wantError: "single version"},
+		{name: "tool-versions default key", file: ".tool-versions", content: "golang 1.22.3\nnodejs 20.11.0 18.19.0\n", want: "1.22.3"},
+		{name: "tool-versions key", file: ".tool-versions", content: "golang 1.22.3\nnodejs 20.11.0 18.19.0\n", key: "nodejs", want: "20.11.0"},
+		{name: "tool-versions missing tool", file: ".tool-versions", content: "nodejs 20.11.0\n", key: "python", wantError: "no version for python"},
+		{name: "go.mod go directive", file: "go.mod", content: "module example.com/m // comment\n\ngo 1.22.1\n\ntoolchain go1.22.3\n", want: "1.22.1"},
+		{name: "go.mod toolchain", file: "go.mod", content: "module example.com/m\n\ngo 1.22.1\n\ntoolchain go1.22.3\n", key: "toolchain", want: "1.22.3"},
+		{name: "go.mod without toolchain", file: "go.mod", content: "module example.com/m\n\ngo 1.22\n", key: "toolchain", wantError: "no toolchain directive"},
+		{name: "go.mod unknown key", file: "go.mod", content: "go 1.22\n", key: "require", wantError: "unknown key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Read(path, tt.key, "golang")
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("Read() error = %v, want one containing %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Read() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	_, err := Read(filepath.Join(t.TempDir(), ".nvmrc"), "", "node")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Read() error = %v, want fs.ErrNotExist", err)
+	}
+}
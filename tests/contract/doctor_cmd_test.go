@@ -0,0 +1,127 @@
+package contract
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/tests/internal/goctortest"
+)
+
+func TestMain(m *testing.M) {
+	goctortest.Main(m)
+}
+
+func TestGoctorCommandInterface(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		expectedExit   int
+		outputFormat   string
+	}{
+		{
+			name:           "doctor command with no flags",
+			args:           []string{"doctor"},
+			expectedOutput: "✓ Go (go)",
+			expectedExit:   0,
+			outputFormat:   "human",
+		},
+		{
+			name:           "doctor command with JSON flag",
+			args:           []string{"doctor", "--json"},
+			expectedOutput: `"schema_version": 1`,
+			expectedExit:   0,
+			outputFormat:   "json",
+		},
+		{
+			name:           "doctor command with custom manifest",
+			args:           []string{"doctor", "-f", goctortest.Manifest("sample.yaml")},
+			expectedOutput: "✓ Docker (docker)",
+			expectedExit:   0,
+			outputFormat:   "human",
+		},
+		{
+			name:           "help flag",
+			args:           []string{"-h"},
+			expectedOutput: "USAGE:",
+			expectedExit:   0,
+			outputFormat:   "help",
+		},
+		{
+			name:           "version flag",
+			args:           []string{"-v"},
+			expectedOutput: "goctor version",
+			expectedExit:   0,
+			outputFormat:   "version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := goctortest.NewEnv(t).Run(tt.args...)
+
+			if result.ExitCode != tt.expectedExit {
+				t.Errorf("Expected exit code %d, got %d\nOutput: %s", tt.expectedExit, result.ExitCode, result.Output())
+			}
+
+			// Check output format
+			if !strings.Contains(result.Output(), tt.expectedOutput) {
+				t.Errorf("Expected output to contain '%s', got: %s", tt.expectedOutput, result.Output())
+			}
+
+			// Validate JSON output if specified
+			if tt.outputFormat == "json" {
+				var report map[string]interface{}
+				if err := json.Unmarshal([]byte(result.Stdout), &report); err != nil {
+					t.Fatalf("Expected valid JSON output, got error: %v", err)
+				}
+
+				// Validate required fields according to contract
+				requiredFields := []string{"schema_version", "platform", "summary", "manifest_source", "items", "generated_at"}
+				for _, field := range requiredFields {
+					if _, exists := report[field]; !exists {
+						t.Errorf("JSON output missing required field: %s", field)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDoctorCommandExitCodes(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectedExit int
+		description  string
+	}{
+		{
+			name:         "all tools satisfied",
+			args:         []string{"doctor"},
+			expectedExit: 0,
+			description:  "Should return 0 when all tools meet requirements",
+		},
+		{
+			name:         "missing or outdated tools",
+			args:         []string{"doctor", "-f", goctortest.Manifest("missing-tools.yaml")},
+			expectedExit: 1,
+			description:  "Should return 1 when tools are missing or outdated",
+		},
+		{
+			name:         "missing manifest",
+			args:         []string{"doctor", "-f", "nonexistent-manifest.yaml"},
+			expectedExit: 1,
+			description:  "Should return 1 when the manifest cannot be loaded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := goctortest.NewEnv(t).Run(tt.args...)
+			if result.ExitCode != tt.expectedExit {
+				t.Errorf("%s: Expected exit code %d, got %d\nOutput: %s", tt.description, tt.expectedExit, result.ExitCode, result.Output())
+			}
+		})
+	}
+}
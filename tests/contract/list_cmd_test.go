@@ -0,0 +1,154 @@
+package contract
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/tests/internal/goctortest"
+)
+
+func TestListCommandInterface(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		expectedExit   int
+		outputFormat   string
+	}{
+		{
+			name:           "list command with no flags",
+			args:           []string{"list"},
+			expectedOutput: "Tools defined in manifest (./tools.yaml):",
+			expectedExit:   0,
+			outputFormat:   "human",
+		},
+		{
+			name:           "list command with JSON flag",
+			args:           []string{"list", "--json"},
+			expectedOutput: `"manifest_source": "./tools.yaml"`,
+			expectedExit:   0,
+			outputFormat:   "json",
+		},
+		{
+			name:           "list command with custom manifest",
+			args:           []string{"list", "-f", goctortest.Manifest("sample.yaml")},
+			expectedOutput: "Tools defined in manifest (" + goctortest.Manifest("sample.yaml") + "):",
+			expectedExit:   0,
+			outputFormat:   "human",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := goctortest.NewEnv(t).Run(tt.args...)
+
+			if result.ExitCode != tt.expectedExit {
+				t.Errorf("Expected exit code %d, got %d\nOutput: %s", tt.expectedExit, result.ExitCode, result.Output())
+			}
+
+			// Check output format
+			if !strings.Contains(result.Output(), tt.expectedOutput) {
+				t.Errorf("Expected output to contain '%s', got: %s", tt.expectedOutput, result.Output())
+			}
+
+			// Validate JSON output if specified
+			if tt.outputFormat == "json" {
+				var list map[string]interface{}
+				if err := json.Unmarshal([]byte(result.Stdout), &list); err != nil {
+					t.Fatalf("Expected valid JSON output, got error: %v", err)
+				}
+
+				// Validate required fields for list command JSON output
+				requiredFields := []string{"manifest_source", "tools"}
+				for _, field := range requiredFields {
+					if _, exists := list[field]; !exists {
+						t.Errorf("JSON output missing required field: %s", field)
+					}
+				}
+
+				// Validate tools array structure
+				tools, ok := list["tools"].([]interface{})
+				if !ok || len(tools) == 0 {
+					t.Fatalf("tools field should be a non-empty array, got %v", list["tools"])
+				}
+				tool, ok := tools[0].(map[string]interface{})
+				if !ok {
+					t.Fatalf("Tool should be an object, got %v", tools[0])
+				}
+				toolFields := []string{"id", "name", "required_version", "rationale"}
+				for _, field := range toolFields {
+					if _, exists := tool[field]; !exists {
+						t.Errorf("Tool object missing required field: %s", field)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestListCommandDisplaysAllTools(t *testing.T) {
+	// The list command shows every tool of the manifest without executing checks
+	result := goctortest.NewEnv(t).Run("list", "-f", goctortest.Manifest("sample.yaml"))
+	if result.ExitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d\nOutput: %s", result.ExitCode, result.Output())
+	}
+
+	// Check that it includes expected tools from sample manifest
+	expectedTools := []string{"Go (go)", "Git (git)", "Docker (docker)"}
+	for _, tool := range expectedTools {
+		if !strings.Contains(result.Stdout, tool) {
+			t.Errorf("Expected output to contain tool '%s', got: %s", tool, result.Stdout)
+		}
+	}
+
+	// Check that it includes version requirements
+	expectedPatterns := []string{">=1.22", ">=2.30", ">=24"}
+	for _, pattern := range expectedPatterns {
+		if !strings.Contains(result.Stdout, pattern) {
+			t.Errorf("Expected output to contain version pattern '%s', got: %s", pattern, result.Stdout)
+		}
+	}
+
+	// Check that it includes rationales
+	expectedRationales := []string{"Go development", "Version control", "Container platform"}
+	for _, rationale := range expectedRationales {
+		if !strings.Contains(result.Stdout, rationale) {
+			t.Errorf("Expected output to contain rationale '%s', got: %s", rationale, result.Stdout)
+		}
+	}
+
+	// Nothing was checked, so nothing has a status
+	if strings.Contains(result.Stdout, "Installed:") {
+		t.Errorf("Expected no installed versions, got: %s", result.Stdout)
+	}
+}
+
+func TestListRejectsUnsupportedFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		flag string
+	}{
+		{name: "doctor flag after the command", args: []string{"list", "--report-url", "https://example.com/reports"}, flag: "-report-url"},
+		{name: "check flag after the command", args: []string{"list", "--target", "ssh://host"}, flag: "-target"},
+		{name: "doctor flag before the command", args: []string{"--dry-run", "list"}, flag: "-dry-run"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := goctortest.NewEnv(t).Run(tt.args...)
+
+			// A flag list does not use fails with usage instead of being ignored
+			if result.ExitCode != 1 {
+				t.Errorf("Expected exit code 1, got %d\nOutput: %s", result.ExitCode, result.Output())
+			}
+			if !strings.Contains(result.Stderr, "flag provided but not defined: "+tt.flag) || !strings.Contains(result.Stderr, "Usage of list:") {
+				t.Errorf("Expected a usage error for %s, got: %s", tt.flag, result.Output())
+			}
+			if result.Stdout != "" {
+				t.Errorf("Expected nothing to be listed, got: %s", result.Stdout)
+			}
+		})
+	}
+}
@@ -3,7 +3,10 @@ package contract
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/ikorihn/goctor/tests/internal/goctortest"
 	"gopkg.in/yaml.v3"
 )
 
@@ -41,7 +44,8 @@ type CheckCommand struct {
 
 func TestManifestSchemaCompliance(t *testing.T) {
 	manifestFiles := []string{
-		"testdata/manifests/sample.yaml",
+		goctortest.Manifest("sample.yaml"),
+		goctortest.Manifest("missing-tools.yaml"),
 	}
 
 	for _, manifestFile := range manifestFiles {
@@ -163,28 +167,16 @@ func validateToolDefinition(t *testing.T, tool *ToolDefinition, filename string,
 }
 
 func containsNamedCaptureGroup(regex string) bool {
-	// Simple check for named capture group pattern (?P<name>...)
-	// This is a basic implementation for testing
-	return len(regex) > 7 &&
-		   (containsSubstring(regex, "(?P<") || containsSubstring(regex, "(?<"))
-}
-
-func containsSubstring(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
+	return strings.Contains(regex, "(?P<") || strings.Contains(regex, "(?<")
 }
 
 func isValidURL(url string) bool {
 	// Basic URL validation - check for http/https prefix
-	return len(url) > 7 && (url[:7] == "http://" || url[:8] == "https://")
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
 }
 
 func TestManifestSchemaInvalidCases(t *testing.T) {
-	// Test cases for invalid manifest structures
+	// Manifests goctor must refuse to load, with the reason
 	invalidCases := []struct {
 		name     string
 		yaml     string
@@ -204,13 +196,13 @@ tools:
     links:
       homepage: "https://example.com"
 `,
-			expected: "meta section required",
+			expected: "unsupported manifest version: 0",
 		},
 		{
 			name: "invalid version",
 			yaml: `
 meta:
-  version: 2
+  version: 99
   name: "Test"
 tools:
   - id: test
@@ -223,7 +215,7 @@ tools:
     links:
       homepage: "https://example.com"
 `,
-			expected: "version must be 1",
+			expected: "unsupported manifest version: 99",
 		},
 		{
 			name: "empty tools array",
@@ -233,21 +225,25 @@ meta:
   name: "Test"
 tools: []
 `,
-			expected: "tools array must not be empty",
+			expected: "tools list cannot be empty",
 		},
 	}
 
 	for _, tc := range invalidCases {
 		t.Run(tc.name, func(t *testing.T) {
+			env := goctortest.NewEnv(t)
+			path := env.WriteFile("invalid.yaml", tc.yaml)
+
+			// The YAML parses, but loading the manifest validates it
 			var manifest ManifestSchema
-			err := yaml.Unmarshal([]byte(tc.yaml), &manifest)
+			if err := yaml.Unmarshal([]byte(tc.yaml), &manifest); err != nil {
+				t.Fatalf("Expected valid YAML, got: %v", err)
+			}
 
-			// The YAML parsing might succeed, but validation should catch issues
-			if err == nil {
-				// Run validation and expect it to fail
-				// This would be implemented in the actual manifest loading code
-				t.Log("YAML parsed successfully but validation should catch the issue")
+			result := env.Run("list", "-f", path)
+			if result.ExitCode != 1 || !strings.Contains(result.Stderr, tc.expected) {
+				t.Errorf("Expected exit code 1 and an error containing %q, got %d: %s", tc.expected, result.ExitCode, result.Output())
 			}
 		})
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,183 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ikorihn/goctor/tests/internal/goctortest"
+)
+
+func TestMain(m *testing.M) {
+	goctortest.Main(m)
+}
+
+// goManifest is a manifest of the Go toolchain alone
+const goManifest = `
+meta:
+  version: 1
+  name: "Test Manifest"
+
+tools:
+  - id: go
+    name: "Go"
+    rationale: "Go development"
+    require: ">=1.0"
+    check:
+      cmd: ["go", "version"]
+      regex: "go(?P<ver>\\d+\\.\\d+(\\.\\d+)?)"
+    links:
+      homepage: "https://go.dev/"
+`
+
+func TestBasicEnvironmentCheck(t *testing.T) {
+	// This test implements the scenario from quickstart.md
+	// It should run the doctor command and verify basic functionality
+
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectOutput   string
+		expectExitCode int
+		manifest       string
+		description    string
+	}{
+		{
+			name:           "basic check with default manifest",
+			args:           []string{"doctor"},
+			expectOutput:   "✓ Docker (docker)",
+			expectExitCode: 0,
+			description:    "Should run basic environment check",
+		},
+		{
+			name:           "check with custom manifest",
+			args:           []string{"doctor", "-f", "custom.yaml"},
+			expectOutput:   "✓ Go (go)",
+			expectExitCode: 0,
+			manifest:       goManifest,
+			description:    "Should use custom manifest file",
+		},
+		{
+			name:           "check with missing tools manifest",
+			args:           []string{"doctor", "-f", goctortest.Manifest("missing-tools.yaml")},
+			expectOutput:   "✗ Nonexistent Tool (nonexistent-tool)",
+			expectExitCode: 1,
+			description:    "Should detect missing tools and return exit code 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := goctortest.NewEnv(t)
+			if tt.manifest != "" {
+				env.WriteFile("custom.yaml", tt.manifest)
+			}
+
+			result := env.Run(tt.args...)
+			if result.ExitCode != tt.expectExitCode {
+				t.Errorf("%s: Expected exit code %d, got %d", tt.description, tt.expectExitCode, result.ExitCode)
+			}
+			if !strings.Contains(result.Output(), tt.expectOutput) {
+				t.Errorf("%s: Expected output to contain '%s', got: %s", tt.description, tt.expectOutput, result.Output())
+			}
+		})
+	}
+}
+
+func TestEnvironmentCheckStatusReporting(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// Create manifest with mix of tools (some present, some missing)
+	env := goctortest.NewEnv(t)
+	manifestFile := env.WriteFile("test-mixed-manifest.yaml", `
+meta:
+  version: 1
+  name: "Mixed Tools Test"
+
+tools:
+  - id: go
+    name: "Go"
+    rationale: "Present tool"
+    require: ">=1.0"
+    check:
+      cmd: ["go", "version"]
+      regex: "go(?P<ver>\\d+\\.\\d+(\\.\\d+)?)"
+    links:
+      homepage: "https://go.dev/"
+
+  - id: nonexistent-tool
+    name: "Nonexistent Tool"
+    rationale: "Missing tool"
+    require: ">=1.0"
+    check:
+      cmd: ["nonexistent-tool", "--version"]
+      regex: "(?P<ver>\\d+\\.\\d+\\.\\d+)"
+    links:
+      homepage: "https://example.com/"
+`)
+
+	// Should return exit code 1 (issues found)
+	result := env.Run("doctor", "-f", manifestFile)
+	if result.ExitCode != 1 {
+		t.Errorf("Expected exit code 1 (issues found), got %d", result.ExitCode)
+	}
+
+	// Should contain both OK and Missing statuses
+	if !strings.Contains(result.Stdout, "✓ Go (go)") {
+		t.Errorf("Expected output to report go as OK, got: %s", result.Stdout)
+	}
+	if !strings.Contains(result.Stdout, "✗ Nonexistent Tool (nonexistent-tool)") {
+		t.Errorf("Expected output to report nonexistent-tool as missing, got: %s", result.Stdout)
+	}
+
+	// Should show how to fix problematic tools, with their links
+	if !strings.Contains(result.Stdout, "Install this tool to continue development") || !strings.Contains(result.Stdout, "Homepage: https://example.com/") {
+		t.Errorf("Expected recommendations with links for the missing tool, got: %s", result.Stdout)
+	}
+}
+
+func TestEnvironmentCheckTimeout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// Create manifest with command that hangs (sleep)
+	env := goctortest.NewEnv(t)
+	manifestFile := env.WriteFile("test-timeout-manifest.yaml", `
+meta:
+  version: 1
+  name: "Timeout Test"
+
+defaults:
+  timeout_sec: 1
+
+tools:
+  - id: hanging-tool
+    name: "Hanging Tool"
+    rationale: "Tool that hangs for testing timeout"
+    require: ">=1.0"
+    check:
+      cmd: ["sleep", "10"]
+      regex: "(?P<ver>\\d+\\.\\d+\\.\\d+)"
+    links:
+      homepage: "https://example.com/"
+`)
+
+	// Run doctor command - should complete quickly due to timeout
+	start := time.Now()
+	result := env.Run("doctor", "-f", manifestFile)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the check to stop after its 1s timeout, took %s", elapsed)
+	}
+
+	// Should handle timeout gracefully
+	if result.ExitCode != 1 || !strings.Contains(result.Stdout, "command timed out after 1s") {
+		t.Errorf("Expected the timeout to be reported as an error, got exit code %d: %s", result.ExitCode, result.Output())
+	}
+}
@@ -0,0 +1,394 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/tests/internal/goctortest"
+)
+
+func TestCustomManifestLoading(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	t.Run("load local manifest file", func(t *testing.T) {
+		// Create a custom manifest
+		env := goctortest.NewEnv(t)
+		manifestFile := env.WriteFile("custom-manifest.yaml", `
+meta:
+  version: 1
+  name: "Custom Test Manifest"
+  language: "en"
+
+defaults:
+  timeout_sec: 10
+  regex_key: "ver"
+
+tools:
+  - id: git
+    name: "Git"
+    rationale: "Version control system"
+    require: ">=2.30"
+    check:
+      cmd: ["git", "--version"]
+      regex: "git version (?P<ver>\\d+\\.\\d+\\.\\d+)"
+    links:
+      homepage: "https://git-scm.com/"
+      download: "https://git-scm.com/downloads"
+      docs: "https://git-scm.com/doc"
+
+  - id: custom-tool
+    name: "Custom Tool"
+    rationale: "Custom tool for testing"
+    require: ">=1.0"
+    check:
+      cmd: ["echo", "custom-tool v1.5.0"]
+      regex: "custom-tool v(?P<ver>\\d+\\.\\d+\\.\\d+)"
+    links:
+      homepage: "https://example.com/"
+`)
+
+		// Run doctor with custom manifest
+		result := env.Run("doctor", "-f", manifestFile)
+		if result.ExitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", result.ExitCode, result.Output())
+		}
+
+		// Should check tools from custom manifest, and only them
+		for _, want := range []string{"✓ Git (git)", "✓ Custom Tool (custom-tool)", "Installed: 1.5.0"} {
+			if !strings.Contains(result.Stdout, want) {
+				t.Errorf("Expected output to contain %q, got: %s", want, result.Stdout)
+			}
+		}
+		if strings.Contains(result.Stdout, "Docker") {
+			t.Errorf("Expected the default manifest not to be checked, got: %s", result.Stdout)
+		}
+	})
+
+	t.Run("load manifest with short flag", func(t *testing.T) {
+		result := goctortest.NewEnv(t).Run("doctor", "-f", goctortest.Manifest("sample.yaml"))
+		if result.ExitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", result.ExitCode, result.Output())
+		}
+
+		// Should load the sample manifest
+		if !strings.Contains(result.Stdout, "Manifest: "+goctortest.Manifest("sample.yaml")) || !strings.Contains(result.Stdout, "✓ Docker (docker)") {
+			t.Errorf("Expected output to include tools from sample manifest, got: %s", result.Stdout)
+		}
+	})
+
+	invalid := []struct {
+		name     string
+		manifest string
+		expected string
+	}{
+		{
+			name:     "invalid manifest file",
+			expected: "manifest file not found",
+		},
+		{
+			name: "malformed manifest file",
+			manifest: `
+meta:
+  version: 1
+  name: "Malformed Manifest"
+tools:
+  - id: test
+    name: "Test"
+    invalid_yaml: [unclosed list
+`,
+			expected: "YAML parsing error",
+		},
+		{
+			name: "manifest with missing required fields",
+			manifest: `
+meta:
+  version: 1
+  name: "Incomplete Manifest"
+
+tools:
+  - id: incomplete-tool
+    name: "Incomplete Tool"
+    # Missing rationale, require, check, links
+`,
+			expected: "tool 0 (incomplete-tool) validation failed",
+		},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			env := goctortest.NewEnv(t)
+			if tt.manifest != "" {
+				env.WriteFile("manifest.yaml", tt.manifest)
+			}
+
+			// Should fail with an error saying what is wrong with the manifest
+			result := env.Run("doctor", "-f", "manifest.yaml")
+			if result.ExitCode != 1 {
+				t.Errorf("Expected exit code 1, got %d", result.ExitCode)
+			}
+			if !strings.Contains(result.Stderr, "Error loading manifest") || !strings.Contains(result.Stderr, tt.expected) {
+				t.Errorf("Expected an error containing %q, got: %s", tt.expected, result.Output())
+			}
+		})
+	}
+}
+
+func TestManifestMerging(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	overrideManifest := `
+meta:
+  version: 1
+  name: "Override Test"
+
+tools:
+  - id: test-only-tool
+    name: "Test Only Tool"
+    rationale: "Only in custom manifest"
+    require: ">=1.0"
+    check:
+      cmd: ["echo", "test-only-tool v1.0.0"]
+      regex: "test-only-tool v(?P<ver>\\d+\\.\\d+\\.\\d+)"
+    links:
+      homepage: "https://example.com/"
+
+  - id: go
+    name: "Go"
+    rationale: "A Go release that does not exist yet"
+    require: ">=99.0"
+    check:
+      cmd: ["go", "version"]
+      regex: "go(?P<ver>\\d+\\.\\d+(\\.\\d+)?)"
+    links:
+      homepage: "https://go.dev/"
+`
+
+	t.Run("custom manifest replaces the default one", func(t *testing.T) {
+		env := goctortest.NewEnv(t)
+		manifestFile := env.WriteFile("override-manifest.yaml", overrideManifest)
+
+		result := env.Run("list", "-f", manifestFile)
+		if result.ExitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", result.ExitCode, result.Output())
+		}
+
+		// Should only include tools from custom manifest
+		if !strings.Contains(result.Stdout, "test-only-tool") {
+			t.Errorf("Expected output to include test-only-tool from custom manifest, got: %s", result.Stdout)
+		}
+		if strings.Contains(result.Stdout, "docker") {
+			t.Errorf("Expected output not to include tools of the default manifest, got: %s", result.Stdout)
+		}
+	})
+
+	t.Run("later manifests take precedence", func(t *testing.T) {
+		env := goctortest.NewEnv(t)
+		manifestFile := env.WriteFile("override-manifest.yaml", overrideManifest)
+
+		result := env.Run("doctor", "-f", "tools.yaml", "-f", manifestFile)
+		if result.ExitCode != 1 {
+			t.Errorf("Expected exit code 1 for the overridden go, got %d", result.ExitCode)
+		}
+		for _, want := range []string{"✓ Test Only Tool (test-only-tool)", "⚠ Go (go)", "✓ Docker (docker)"} {
+			if !strings.Contains(result.Stdout, want) {
+				t.Errorf("Expected output to contain %q, got: %s", want, result.Stdout)
+			}
+		}
+	})
+}
+
+func TestManifestDefaultsApplication(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// Create manifest with defaults and tools that should use them
+	env := goctortest.NewEnv(t)
+	manifestFile := env.WriteFile("defaults-manifest.yaml", `
+meta:
+  version: 1
+  name: "Defaults Test"
+
+defaults:
+  timeout_sec: 1
+  regex_key: "version"
+
+tools:
+  - id: quick-tool
+    name: "Quick Tool"
+    rationale: "Tool whose version is read with the default regex key"
+    require: ">=1.0"
+    check:
+      cmd: ["echo", "quick-tool version 1.0.0"]
+      regex: "quick-tool version (?P<version>\\d+\\.\\d+\\.\\d+)"
+    links:
+      homepage: "https://example.com/"
+
+  - id: slow-tool
+    name: "Slow Tool"
+    rationale: "Tool that outlasts the default timeout"
+    require: ">=1.0"
+    check:
+      cmd: ["sleep", "10"]
+      regex: "slow-tool version (?P<version>\\d+\\.\\d+\\.\\d+)"
+    links:
+      homepage: "https://example.com/"
+`)
+
+	result := env.Run("doctor", "-f", manifestFile)
+	for _, want := range []string{"✓ Quick Tool (quick-tool)", "Installed: 1.0.0", "command timed out after 1s"} {
+		if !strings.Contains(result.Stdout, want) {
+			t.Errorf("Expected output to contain %q, got: %s", want, result.Output())
+		}
+	}
+}
+
+func TestRemoteManifestLoading(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	manifest := goctortest.NewEnv(t).WriteFile("remote.yaml", goManifest)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tools.yaml":
+			http.ServeFile(w, r, manifest)
+		case "/page.html":
+			w.Write([]byte("<html><body>Not a manifest</body></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		path         string
+		trust        bool
+		expectedExit int
+		expected     string
+	}{
+		{"untrusted source", "/tools.yaml", false, 1, "is not a trusted manifest source"},
+		{"trusted source", "/tools.yaml", true, 0, "✓ Go (go)"},
+		{"missing manifest", "/missing.yaml", true, 1, "HTTP 404"},
+		{"non-YAML URL", "/page.html", true, 1, "YAML parsing error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []string{"doctor", "-f", server.URL + tt.path}
+			if tt.trust {
+				args = append(args, "--trust")
+			}
+
+			result := goctortest.NewEnv(t).Run(args...)
+			if result.ExitCode != tt.expectedExit {
+				t.Errorf("Expected exit code %d, got %d", tt.expectedExit, result.ExitCode)
+			}
+			if !strings.Contains(result.Output(), tt.expected) {
+				t.Errorf("Expected output to contain %q, got: %s", tt.expected, result.Output())
+			}
+		})
+	}
+}
+
+func TestDiscoveredManifestFromSubdirectory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// A repository whose tools.yaml sits at the root, run from a subdirectory of it
+	env := goctortest.NewEnv(t)
+	if err := os.Mkdir(filepath.Join(env.Dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(env.Dir, "service"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	env.WriteFile("tools.yaml", `
+meta:
+  version: 2
+  name: "Repository Manifest"
+
+tools:
+  - id: vpn
+    name: "VPN"
+    rationale: "Internal registries are only reachable over the VPN"
+    check:
+      plugin: ./scripts/check-vpn.sh
+    links:
+      docs: "https://example.com/vpn"
+
+  - id: go
+    name: "Go"
+    rationale: "The toolchain go.mod asks for"
+    require_from: go.mod
+    check:
+      cmd: ["go", "version"]
+      regex: "go(?P<ver>\\d+\\.\\d+(\\.\\d+)?)"
+    links:
+      homepage: "https://go.dev/"
+`)
+	env.WriteFile("go.mod", "module example.com/repo\n\ngo 1.22.1\n")
+	plugin := env.WriteFile("scripts/check-vpn.sh", `
+#!/bin/sh
+echo '{"status": "ok", "version": "4.2.1"}'
+`)
+	if err := os.Chmod(plugin, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("relative plugin resolves against the manifest", func(t *testing.T) {
+		result := env.RunIn("service", "doctor")
+		if result.ExitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", result.ExitCode, result.Output())
+		}
+		if !strings.Contains(result.Stdout, "✓ VPN (vpn)") {
+			t.Errorf("Expected the plugin to run, got: %s", result.Stdout)
+		}
+	})
+
+	t.Run("relative require_from resolves against the manifest", func(t *testing.T) {
+		result := env.RunIn("service", "doctor", "--json")
+		if result.ExitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", result.ExitCode, result.Output())
+		}
+		var report EnvironmentReport
+		if err := json.Unmarshal([]byte(result.Stdout), &report); err != nil {
+			t.Fatalf("Failed to parse JSON output: %v\nOutput: %s", err, result.Stdout)
+		}
+		if len(report.Items) != 2 || report.Items[1].Required != ">=1.22.1" {
+			t.Errorf("Expected the requirement read from the repository's go.mod, got %+v", report.Items)
+		}
+	})
+
+	// Every command that loads the manifest discovers it the same way
+	commands := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"doctor", "--dry-run"}, want: "Dry run of ../tools.yaml"},
+		{args: []string{"list"}, want: "Tools defined in manifest (../tools.yaml)"},
+		{args: []string{"lint"}, want: "Manifest ../tools.yaml"},
+		{args: []string{"explain", "vpn"}, want: "VPN (vpn)"},
+		{args: []string{"export", "--format", "tool-versions"}, want: `from "Repository Manifest"`},
+		{args: []string{"doctor", "completion", "--tool-ids"}, want: "vpn\ngo\n"},
+	}
+	for _, tt := range commands {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			result := env.RunIn("service", tt.args...)
+			if !strings.Contains(result.Stdout, tt.want) {
+				t.Errorf("Expected output containing %q, got (exit code %d): %s", tt.want, result.ExitCode, result.Output())
+			}
+		})
+	}
+}
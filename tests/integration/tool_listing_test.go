@@ -0,0 +1,269 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ikorihn/goctor/tests/internal/goctortest"
+)
+
+func TestToolListingFunctionality(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	t.Run("list tools human readable", func(t *testing.T) {
+		// Create test manifest with multiple tools
+		env := goctortest.NewEnv(t)
+		manifestFile := env.WriteFile("list-test-manifest.yaml", `
+meta:
+  version: 1
+  name: "List Test Manifest"
+
+tools:
+  - id: go
+    name: "Go"
+    rationale: "Go development toolchain"
+    require: ">=1.22 <1.25"
+    check:
+      cmd: ["go", "version"]
+      regex: "go(?P<ver>\\d+\\.\\d+(\\.\\d+)?)"
+    links:
+      homepage: "https://go.dev/"
+      download: "https://go.dev/dl/"
+
+  - id: git
+    name: "Git"
+    rationale: "Version control system"
+    require: ">=2.30"
+    check:
+      cmd: ["git", "--version"]
+      regex: "git version (?P<ver>\\d+\\.\\d+\\.\\d+)"
+    links:
+      homepage: "https://git-scm.com/"
+      download: "https://git-scm.com/downloads"
+
+  - id: docker
+    name: "Docker"
+    rationale: "Container platform for development"
+    require: ">=24"
+    check:
+      cmd: ["docker", "--version"]
+      regex: "version (?P<ver>\\d+\\.\\d+\\.\\d+)"
+    links:
+      homepage: "https://www.docker.com/"
+      docs: "https://docs.docker.com/"
+`)
+
+		// Run list command
+		result := env.Run("list", "-f", manifestFile)
+		if result.ExitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", result.ExitCode, result.Output())
+		}
+		output := result.Stdout
+
+		// Should display header
+		if !strings.Contains(output, "Tools defined in manifest ("+manifestFile+"):") {
+			t.Errorf("Expected output to contain list header, got: %s", output)
+		}
+
+		// Should display all tools with their information
+		expectedTools := []string{"1. Go (go)", "2. Git (git)", "3. Docker (docker)"}
+		for _, tool := range expectedTools {
+			if !strings.Contains(output, tool) {
+				t.Errorf("Expected output to contain tool '%s'", tool)
+			}
+		}
+
+		// Should display version requirements
+		expectedVersions := []string{">=1.22 <1.25", ">=2.30", ">=24"}
+		for _, version := range expectedVersions {
+			if !strings.Contains(output, "Required version: "+version) {
+				t.Errorf("Expected output to contain version requirement '%s'", version)
+			}
+		}
+
+		// Should display rationales
+		expectedRationales := []string{
+			"Go development toolchain",
+			"Version control system",
+			"Container platform for development",
+		}
+		for _, rationale := range expectedRationales {
+			if !strings.Contains(output, "Rationale: "+rationale) {
+				t.Errorf("Expected output to contain rationale '%s'", rationale)
+			}
+		}
+
+		// Should NOT execute any tool commands (listing doesn't check versions)
+		if strings.Contains(output, "Installed:") || strings.Contains(output, "✓") {
+			t.Errorf("List command should not show installation status, got: %s", output)
+		}
+	})
+
+	t.Run("list tools JSON format", func(t *testing.T) {
+		result := goctortest.NewEnv(t).Run("list", "--json", "-f", goctortest.Manifest("sample.yaml"))
+		if result.ExitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", result.ExitCode, result.Output())
+		}
+
+		// Validate JSON structure
+		var listResp struct {
+			ManifestSource string `json:"manifest_source"`
+			Tools          []struct {
+				ID              string `json:"id"`
+				Name            string `json:"name"`
+				RequiredVersion string `json:"required_version"`
+				Rationale       string `json:"rationale"`
+			} `json:"tools"`
+		}
+
+		if err := json.Unmarshal([]byte(result.Stdout), &listResp); err != nil {
+			t.Fatalf("Failed to parse JSON output: %v\nOutput: %s", err, result.Stdout)
+		}
+
+		// Validate response structure
+		if listResp.ManifestSource != goctortest.Manifest("sample.yaml") {
+			t.Errorf("JSON output should include manifest_source, got %q", listResp.ManifestSource)
+		}
+
+		if len(listResp.Tools) != 3 {
+			t.Errorf("JSON output should include the 3 tools of the manifest, got %d", len(listResp.Tools))
+		}
+
+		// Validate each tool has required fields
+		for i, tool := range listResp.Tools {
+			if tool.ID == "" {
+				t.Errorf("Tool %d should have ID", i)
+			}
+			if tool.Name == "" {
+				t.Errorf("Tool %d should have Name", i)
+			}
+			if tool.RequiredVersion == "" {
+				t.Errorf("Tool %d should have RequiredVersion", i)
+			}
+			if tool.Rationale == "" {
+				t.Errorf("Tool %d should have Rationale", i)
+			}
+		}
+	})
+
+	t.Run("list with default manifest", func(t *testing.T) {
+		// Test listing without specifying manifest (should use ./tools.yaml)
+		result := goctortest.NewEnv(t).Run("list")
+		if result.ExitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", result.ExitCode, result.Output())
+		}
+
+		// Should show tools from the default manifest, without errors
+		if !strings.Contains(result.Stdout, "Tools defined in manifest (./tools.yaml):") {
+			t.Errorf("Expected output to show tools from the default manifest, got: %s", result.Stdout)
+		}
+		if result.Stderr != "" {
+			t.Errorf("List command should not show errors with the default manifest, got: %s", result.Stderr)
+		}
+	})
+
+	t.Run("list with no tools in manifest", func(t *testing.T) {
+		// Create manifest with no tools
+		env := goctortest.NewEnv(t)
+		manifestFile := env.WriteFile("empty-manifest.yaml", `
+meta:
+  version: 1
+  name: "Empty Manifest"
+
+tools: []
+`)
+
+		// A manifest must define tools, so this is a validation error
+		result := env.Run("list", "-f", manifestFile)
+		if result.ExitCode != 1 || !strings.Contains(result.Stderr, "tools list cannot be empty") {
+			t.Errorf("Expected a validation error for empty tools list, got %d: %s", result.ExitCode, result.Output())
+		}
+	})
+
+	t.Run("list with invalid manifest", func(t *testing.T) {
+		// Test list command with invalid manifest
+		result := goctortest.NewEnv(t).Run("list", "-f", "nonexistent-manifest.yaml")
+
+		// Should handle error gracefully
+		if result.ExitCode == 0 {
+			t.Error("Expected command to fail with nonexistent manifest")
+		}
+		if !strings.Contains(result.Stderr, "manifest file not found") {
+			t.Errorf("Expected error message about missing manifest, got: %s", result.Output())
+		}
+	})
+}
+
+func TestListCommandExitCodes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	tests := []struct {
+		name         string
+		manifest     string
+		expectedExit int
+	}{
+		{"successful list returns exit code 0", goctortest.Manifest("sample.yaml"), 0},
+		{"failing checks do not matter to list", goctortest.Manifest("missing-tools.yaml"), 0},
+		{"list with invalid manifest returns non-zero exit code", "nonexistent-manifest.yaml", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := goctortest.NewEnv(t).Run("list", "-f", tt.manifest)
+			if result.ExitCode != tt.expectedExit {
+				t.Errorf("Expected exit code %d, got %d\nOutput: %s", tt.expectedExit, result.ExitCode, result.Output())
+			}
+		})
+	}
+}
+
+func TestListCommandPerformance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	t.Run("list command does not run checks", func(t *testing.T) {
+		// Create manifest with many tools, each of which would leave a file behind if checked
+		env := goctortest.NewEnv(t)
+		marker := filepath.Join(env.Dir, "checked")
+
+		var manifest strings.Builder
+		manifest.WriteString("meta:\n  version: 1\n  name: \"Many Tools Manifest\"\n\ntools:\n")
+		for i := 0; i < 50; i++ {
+			fmt.Fprintf(&manifest, `  - id: tool%02d
+    name: "Tool %02d"
+    rationale: "Testing tool %02d"
+    require: ">=1.0"
+    check:
+      cmd: ["touch", %q]
+      regex: "(?P<ver>\\d+\\.\\d+\\.\\d+)"
+    links:
+      homepage: "https://example.com/"
+`, i, i, i, marker)
+		}
+		manifestFile := env.WriteFile("many-tools-manifest.yaml", manifest.String())
+
+		// Run list command - should list every tool without executing them
+		result := env.Run("list", "-f", manifestFile)
+		if result.ExitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", result.ExitCode, result.Output())
+		}
+
+		for _, tool := range []string{"1. Tool 00 (tool00)", "50. Tool 49 (tool49)"} {
+			if !strings.Contains(result.Stdout, tool) {
+				t.Errorf("Expected output to list %q, got: %s", tool, result.Stdout)
+			}
+		}
+		if _, err := os.Stat(marker); err == nil {
+			t.Error("Expected list not to run any check command")
+		}
+	})
+}
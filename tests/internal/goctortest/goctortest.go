@@ -0,0 +1,165 @@
+// Package goctortest runs the goctor binary for the contract and integration tests, in a
+// sandbox of fake tools so the results do not depend on what the machine has installed
+package goctortest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// binary is the goctor binary Main builds
+var binary string
+
+// fakeTools are the tools of testdata/manifests/sample.yaml, by the output of their version
+// commands, all at versions that meet the manifest
+var fakeTools = map[string]string{
+	"go":     "go version go1.22.5 linux/amd64",
+	"git":    "git version 2.43.0",
+	"docker": "Docker version 24.0.7, build afdd53b",
+}
+
+// Main builds goctor, runs the tests, and removes the binary; call it from TestMain
+func Main(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "goctortest")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating build directory: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	binary = filepath.Join(dir, "goctor")
+	build := exec.Command("go", "build", "-o", binary, "./cmd/goctor")
+	build.Dir = RepoRoot()
+	if output, err := build.CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error building goctor: %v\n%s", err, output)
+		return 1
+	}
+
+	return m.Run()
+}
+
+// RepoRoot returns the root of the goctor repository
+func RepoRoot() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "..")
+}
+
+// Manifest returns the path of a manifest in testdata/manifests
+func Manifest(name string) string {
+	return filepath.Join(RepoRoot(), "testdata", "manifests", name)
+}
+
+// Result is the outcome of one goctor run
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Output returns what the run printed, standard output first
+func (r Result) Output() string {
+	return r.Stdout + r.Stderr
+}
+
+// Env is a sandbox to run goctor in: a working directory whose tools.yaml is
+// testdata/manifests/sample.yaml, a PATH whose go, git, and docker are fakes that meet it, and
+// a home directory of its own
+type Env struct {
+	Dir  string
+	t    *testing.T
+	path string
+	home string
+}
+
+// NewEnv creates a sandbox removed when the test ends
+func NewEnv(t *testing.T) *Env {
+	t.Helper()
+
+	root := t.TempDir()
+	env := &Env{
+		Dir:  filepath.Join(root, "work"),
+		t:    t,
+		home: filepath.Join(root, "home"),
+	}
+	bin := filepath.Join(root, "bin")
+	for _, dir := range []string{env.Dir, env.home, bin} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, version := range fakeTools {
+		script := fmt.Sprintf("#!/bin/sh\necho '%s'\n", version)
+		if err := os.WriteFile(filepath.Join(bin, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	env.path = bin + string(os.PathListSeparator) + "/usr/bin" + string(os.PathListSeparator) + "/bin"
+
+	sample, err := os.ReadFile(Manifest("sample.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.WriteFile("tools.yaml", string(sample))
+	return env
+}
+
+// WriteFile writes a file in the working directory, creating its directories, and returns its path
+func (e *Env) WriteFile(name, content string) string {
+	e.t.Helper()
+
+	path := filepath.Join(e.Dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		e.t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.TrimLeft(content, "\n")), 0644); err != nil {
+		e.t.Fatal(err)
+	}
+	return path
+}
+
+// Run runs goctor with args in the sandbox
+func (e *Env) Run(args ...string) Result {
+	e.t.Helper()
+	return e.RunIn(".", args...)
+}
+
+// RunIn runs goctor with args in dir, relative to the working directory of the sandbox
+func (e *Env) RunIn(dir string, args ...string) Result {
+	e.t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = filepath.Join(e.Dir, dir)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = []string{
+		"PATH=" + e.path,
+		"HOME=" + e.home,
+		"LANG=en_US.UTF-8",
+		"GOCTOR_NO_ONBOARDING=1",
+	}
+
+	result := Result{}
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		e.t.Fatalf("Failed to run goctor: %v", err)
+	}
+	result.Stdout, result.Stderr = stdout.String(), stderr.String()
+	return result
+}